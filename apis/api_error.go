@@ -10,9 +10,14 @@ import (
 
 // ApiError defines the struct for a basic api error response.
 type ApiError struct {
-	Code    int            `json:"code"`
-	Message string         `json:"message"`
-	Data    map[string]any `json:"data"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+
+	// RequestId is the correlation id of the failed request
+	// (it is populated by the default api error handler).
+	RequestId string `json:"requestId,omitempty"`
+
+	Data map[string]any `json:"data"`
 
 	// stores unformatted error data (could be an internal error, text, etc.)
 	rawData any
@@ -52,16 +52,21 @@ func (api *backupApi) list(c echo.Context) error {
 		return NewBadRequestError("Failed to retrieve backup items. Raw error: \n"+err.Error(), nil)
 	}
 
-	result := make([]models.BackupFileInfo, len(backups))
+	result := make([]models.BackupFileInfo, 0, len(backups))
+
+	for _, obj := range backups {
+		// the logs archives are listed separately
+		if core.IsLogsArchiveKey(obj.Key) {
+			continue
+		}
 
-	for i, obj := range backups {
 		modified, _ := types.ParseDateTime(obj.ModTime)
 
-		result[i] = models.BackupFileInfo{
+		result = append(result, models.BackupFileInfo{
 			Key:      obj.Key,
 			Size:     obj.Size,
 			Modified: modified,
-		}
+		})
 	}
 
 	return c.JSON(http.StatusOK, result)
@@ -31,6 +31,9 @@ func InitApi(app core.App) (*echo.Echo, error) {
 	e.Binder = &rest.MultiBinder{}
 	e.JSONSerializer = &rest.Serializer{
 		FieldsParam: fieldsQueryParam,
+		Modifiers: map[string]rest.FieldModifierFactory{
+			fileMetaModifier: newFileMetaModifier,
+		},
 	}
 
 	// configure a custom router
@@ -48,16 +51,22 @@ func InitApi(app core.App) (*echo.Echo, error) {
 			return !strings.HasPrefix(c.Request().URL.Path, "/api/")
 		},
 	}))
+	e.Pre(LoadRequestId())
 	e.Pre(LoadAuthContext(app))
+	e.Pre(LoadTenantContext(app))
 	e.Use(middleware.Recover())
 	e.Use(middleware.Secure())
 	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			c.Set(ContextExecStartKey, time.Now())
 
+			// expose the matched route to the request context consumers (eg. the slow queries log)
+			c.SetRequest(c.Request().WithContext(core.ContextWithRoute(c.Request().Context(), c.Path())))
+
 			return next(c)
 		}
 	})
+	e.Use(TraceRequest(app))
 
 	// custom error handler
 	e.HTTPErrorHandler = func(c echo.Context, err error) {
@@ -80,6 +89,10 @@ func InitApi(app core.App) (*echo.Echo, error) {
 			}
 		}
 
+		if apiErr.RequestId == "" {
+			apiErr.RequestId = RequestId(c)
+		}
+
 		logRequest(app, c, apiErr)
 
 		if c.Response().Committed {
@@ -126,8 +139,13 @@ func InitApi(app core.App) (*echo.Echo, error) {
 	bindFileApi(app, api)
 	bindRealtimeApi(app, api)
 	bindLogsApi(app, api)
+	bindLogsArchiveApi(app, api)
 	bindHealthApi(app, api)
+	bindMetricsApi(app, api)
 	bindBackupApi(app, api)
+	bindCronApi(app, api)
+	bindOpenApiApi(app, api)
+	bindGraphqlApi(app, api)
 
 	// catch all any route
 	api.Any("/*", func(c echo.Context) error {
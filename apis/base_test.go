@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/AlperRehaYAZGAN/postgresbase/apis"
+	"github.com/AlperRehaYAZGAN/postgresbase/core"
 	"github.com/AlperRehaYAZGAN/postgresbase/tests"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/rest"
 	"github.com/labstack/echo/v5"
@@ -91,10 +92,29 @@ func TestCustomRoutesAndErrorsHandling(t *testing.T) {
 			ExpectedStatus:  200,
 			ExpectedContent: []string{"a+b+c"},
 		},
+		{
+			Name:   "custom route with request context route",
+			Method: http.MethodGet,
+			Url:    "/custom/123",
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				e.AddRoute(echo.Route{
+					Method: http.MethodGet,
+					Path:   "/custom/:id",
+					Handler: func(c echo.Context) error {
+						return c.String(200, "route:"+core.RouteFromContext(c.Request().Context()))
+					},
+				})
+			},
+			ExpectedStatus:  200,
+			ExpectedContent: []string{"route:/custom/:id"},
+		},
 		{
 			Name:   "route with HTTPError",
 			Method: http.MethodGet,
 			Url:    "/http-error",
+			RequestHeaders: map[string]string{
+				"X-Request-Id": "test_request_id",
+			},
 			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
 				e.AddRoute(echo.Route{
 					Method: http.MethodGet,
@@ -105,12 +125,15 @@ func TestCustomRoutesAndErrorsHandling(t *testing.T) {
 				})
 			},
 			ExpectedStatus:  400,
-			ExpectedContent: []string{`{"code":400,"message":"Bad Request.","data":{}}`},
+			ExpectedContent: []string{`{"code":400,"message":"Bad Request.","requestId":"test_request_id","data":{}}`},
 		},
 		{
 			Name:   "route with api error",
 			Method: http.MethodGet,
 			Url:    "/api-error",
+			RequestHeaders: map[string]string{
+				"X-Request-Id": "test_request_id",
+			},
 			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
 				e.AddRoute(echo.Route{
 					Method: http.MethodGet,
@@ -121,12 +144,15 @@ func TestCustomRoutesAndErrorsHandling(t *testing.T) {
 				})
 			},
 			ExpectedStatus:  500,
-			ExpectedContent: []string{`{"code":500,"message":"Test message.","data":{}}`},
+			ExpectedContent: []string{`{"code":500,"message":"Test message.","requestId":"test_request_id","data":{}}`},
 		},
 		{
 			Name:   "route with plain error",
 			Method: http.MethodGet,
 			Url:    "/plain-error",
+			RequestHeaders: map[string]string{
+				"X-Request-Id": "test_request_id",
+			},
 			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
 				e.AddRoute(echo.Route{
 					Method: http.MethodGet,
@@ -137,7 +163,7 @@ func TestCustomRoutesAndErrorsHandling(t *testing.T) {
 				})
 			},
 			ExpectedStatus:  400,
-			ExpectedContent: []string{`{"code":400,"message":"Something went wrong while processing your request.","data":{}}`},
+			ExpectedContent: []string{`{"code":400,"message":"Something went wrong while processing your request.","requestId":"test_request_id","data":{}}`},
 		},
 	}
 
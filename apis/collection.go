@@ -20,6 +20,7 @@ func bindCollectionApi(app core.App, rg *echo.Group) {
 	subGroup.GET("/:collection", api.view)
 	subGroup.PATCH("/:collection", api.update)
 	subGroup.DELETE("/:collection", api.delete)
+	subGroup.POST("/:collection/refresh", api.refresh)
 	subGroup.PUT("/import", api.bulkImport)
 }
 
@@ -175,6 +176,23 @@ func (api *collectionApi) delete(c echo.Context) error {
 	})
 }
 
+func (api *collectionApi) refresh(c echo.Context) error {
+	collection, err := api.app.Dao().FindCollectionByNameOrId(c.PathParam("collection"))
+	if err != nil || collection == nil {
+		return NewNotFoundError("", err)
+	}
+
+	if !collection.IsMaterializedView() {
+		return NewBadRequestError("Only materialized view collections can be refreshed.", nil)
+	}
+
+	if err := api.app.Dao().RefreshMaterializedView(collection); err != nil {
+		return NewBadRequestError("Failed to refresh the materialized view.", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
 func (api *collectionApi) bulkImport(c echo.Context) error {
 	form := forms.NewCollectionsImport(api.app)
 
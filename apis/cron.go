@@ -0,0 +1,88 @@
+package apis
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/core"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/routine"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/types"
+	"github.com/labstack/echo/v5"
+)
+
+// bindCronApi registers the app cron jobs api endpoints.
+func bindCronApi(app core.App, rg *echo.Group) {
+	api := cronApi{app: app}
+
+	subGroup := rg.Group("/crons", ActivityLogger(app), RequireAdminAuth())
+	subGroup.GET("", api.list)
+	subGroup.POST("/:id/run", api.run)
+	subGroup.POST("/:id/pause", api.pause)
+	subGroup.POST("/:id/resume", api.resume)
+}
+
+type cronApi struct {
+	app core.App
+}
+
+func (api *cronApi) list(c echo.Context) error {
+	now := time.Now().In(api.app.Cron().Timezone())
+
+	jobs := api.app.Cron().Jobs()
+
+	result := make([]models.CronJobInfo, len(jobs))
+
+	for i, j := range jobs {
+		nextRun, _ := types.ParseDateTime(j.NextRun(now))
+
+		result[i] = models.CronJobInfo{
+			Id:         j.Id(),
+			Expression: j.Expression(),
+			Paused:     j.IsPaused(),
+			NextRun:    nextRun,
+		}
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func (api *cronApi) run(c echo.Context) error {
+	job := api.app.Cron().Job(c.PathParam("id"))
+	if job == nil {
+		return NewNotFoundError("Missing or invalid cron job.", nil)
+	}
+
+	// run in the background to avoid blocking the request with long-running jobs
+	// (through the cron to notify its observer, aka. for the job metrics)
+	routine.FireAndForget(func() {
+		api.app.Logger().Debug("Manually triggered cron job", slog.String("jobId", job.Id()))
+
+		if err := api.app.Cron().Run(job.Id()); err != nil {
+			api.app.Logger().Debug(
+				"Failed to run the manually triggered cron job",
+				slog.String("jobId", job.Id()),
+				slog.String("error", err.Error()),
+			)
+		}
+	})
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (api *cronApi) pause(c echo.Context) error {
+	if err := api.app.Cron().Pause(c.PathParam("id")); err != nil {
+		return NewNotFoundError("Missing or invalid cron job.", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (api *cronApi) resume(c echo.Context) error {
+	if err := api.app.Cron().Resume(c.PathParam("id")); err != nil {
+		return NewNotFoundError("Missing or invalid cron job.", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
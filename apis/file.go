@@ -4,26 +4,36 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
+	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/AlperRehaYAZGAN/postgresbase/core"
+	"github.com/AlperRehaYAZGAN/postgresbase/daos"
+	"github.com/AlperRehaYAZGAN/postgresbase/forms"
 	"github.com/AlperRehaYAZGAN/postgresbase/models"
 	"github.com/AlperRehaYAZGAN/postgresbase/models/schema"
+	"github.com/AlperRehaYAZGAN/postgresbase/resolvers"
 	"github.com/AlperRehaYAZGAN/postgresbase/tokens"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/filesystem"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/list"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/search"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/security"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/tus"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/dbx"
 	"github.com/spf13/cast"
 	"golang.org/x/sync/semaphore"
 	"golang.org/x/sync/singleflight"
 )
 
-var imageContentTypes = []string{"image/png", "image/jpg", "image/jpeg", "image/gif"}
+var imageContentTypes = []string{"image/png", "image/jpg", "image/jpeg", "image/gif", "image/webp"}
 var defaultThumbSizes = []string{"100x100"}
 
 // bindFileApi registers the file api endpoints and the corresponding handlers.
@@ -33,10 +43,18 @@ func bindFileApi(app core.App, rg *echo.Group) {
 		thumbGenSem:     semaphore.NewWeighted(int64(runtime.NumCPU() + 2)), // the value is arbitrary chosen and may change in the future
 		thumbGenPending: new(singleflight.Group),
 		thumbGenMaxWait: 60 * time.Second,
+		tusStore:        tus.NewStore(filepath.Join(app.DataDir(), core.LocalUploadsDirName)),
 	}
 
 	subGroup := rg.Group("/files", ActivityLogger(app))
 	subGroup.POST("/token", api.fileToken)
+	subGroup.POST("/:collection/uploads", api.presignUpload, LoadCollectionContext(api.app, models.CollectionTypeBase, models.CollectionTypeAuth))
+	subGroup.PUT("/:collection/uploads/:token", api.upload, LoadCollectionContext(api.app, models.CollectionTypeBase, models.CollectionTypeAuth))
+	subGroup.OPTIONS("/:collection/tus", api.tusOptions)
+	subGroup.POST("/:collection/tus", api.tusCreate, LoadCollectionContext(api.app, models.CollectionTypeBase, models.CollectionTypeAuth))
+	subGroup.HEAD("/:collection/tus/:id", api.tusHead, LoadCollectionContext(api.app, models.CollectionTypeBase, models.CollectionTypeAuth))
+	subGroup.PATCH("/:collection/tus/:id", api.tusPatch, LoadCollectionContext(api.app, models.CollectionTypeBase, models.CollectionTypeAuth))
+	subGroup.DELETE("/:collection/tus/:id", api.tusDelete, LoadCollectionContext(api.app, models.CollectionTypeBase, models.CollectionTypeAuth))
 	subGroup.HEAD("/:collection/:recordId/:filename", api.download, LoadCollectionContext(api.app))
 	subGroup.GET("/:collection/:recordId/:filename", api.download, LoadCollectionContext(api.app))
 }
@@ -55,6 +73,9 @@ type fileApi struct {
 	// thumbGenMaxWait is the maximum waiting time for starting a new
 	// thumb generation process.
 	thumbGenMaxWait time.Duration
+
+	// tusStore manages the pending resumable (tus) uploads.
+	tusStore *tus.Store
 }
 
 func (api *fileApi) fileToken(c echo.Context) error {
@@ -86,6 +107,217 @@ func (api *fileApi) fileToken(c echo.Context) error {
 	})
 }
 
+func (api *fileApi) presignUpload(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("", nil)
+	}
+
+	if err := checkDirectUploadAccess(c, api.app, collection); err != nil {
+		return err
+	}
+
+	form := forms.NewRecordFilePresign(api.app, collection)
+
+	// load request
+	if err := c.Bind(form); err != nil {
+		return NewBadRequestError("An error occurred while loading the submitted data.", err)
+	}
+
+	upload, err := form.Submit()
+	if err != nil {
+		return NewBadRequestError("Failed to prepare the file upload.", err)
+	}
+
+	return c.JSON(http.StatusOK, upload)
+}
+
+// checkDirectUploadAccess checks whether the current request is allowed
+// to upload files directly to the collection storage dir.
+//
+// Direct uploads are allowed only if the request satisfies the collection
+// create rule (evaluated against a new blank record) or the update rule
+// of at least one of the existing collection records.
+func checkDirectUploadAccess(c echo.Context, app core.App, collection *models.Collection) error {
+	requestInfo := RequestInfo(c)
+
+	if requestInfo.Admin != nil {
+		return nil
+	}
+
+	if collection.CreateRule == nil && collection.UpdateRule == nil {
+		return NewForbiddenError("Only admins can perform this action.", nil)
+	}
+
+	dao, err := RequestTenantDao(c, app, collection)
+	if err != nil {
+		return err
+	}
+
+	if canCreateBlankRecord(dao, collection, requestInfo) || canUpdateAnyRecord(dao, collection, requestInfo) {
+		return nil
+	}
+
+	return NewForbiddenError("You are not allowed to upload files to this collection.", nil)
+}
+
+// canCreateBlankRecord temporary saves a new blank collection record
+// and checks whether it satisfies the collection create rule.
+func canCreateBlankRecord(dao *daos.Dao, collection *models.Collection, requestInfo *models.RequestInfo) bool {
+	if collection.CreateRule == nil {
+		return false
+	}
+
+	if *collection.CreateRule == "" {
+		return true
+	}
+
+	record := models.NewRecord(collection)
+	record.RefreshId()
+	if collection.IsAuth() {
+		record.RefreshTokenKey()
+		record.SetUsername("u_" + security.RandomStringWithAlphabet(12, "123456789"))
+	}
+
+	err := dao.WithoutHooks().RunInTransaction(func(txDao *daos.Dao) error {
+		tx, ok := txDao.DB().(*dbx.Tx)
+		if !ok {
+			return errors.New("failed to get transaction db")
+		}
+		defer tx.Rollback()
+
+		if err := txDao.SaveRecord(record); err != nil {
+			return err
+		}
+
+		_, err := txDao.FindRecordById(collection.Id, record.Id, func(q *dbx.SelectQuery) error {
+			resolver := resolvers.NewRecordFieldResolver(txDao, collection, requestInfo, true)
+			expr, err := search.FilterData(*collection.CreateRule).BuildExpr(resolver)
+			if err != nil {
+				return err
+			}
+			resolver.UpdateQuery(q)
+			q.AndWhere(expr)
+			return nil
+		})
+
+		return err
+	})
+
+	return err == nil
+}
+
+// canUpdateAnyRecord checks whether at least one of the existing
+// collection records satisfies the collection update rule.
+func canUpdateAnyRecord(dao *daos.Dao, collection *models.Collection, requestInfo *models.RequestInfo) bool {
+	if collection.UpdateRule == nil {
+		return false
+	}
+
+	err := dao.RunInTenantScope(func(scopedDao *daos.Dao) error {
+		query := scopedDao.RecordQuery(collection).Limit(1)
+
+		if *collection.UpdateRule != "" {
+			resolver := resolvers.NewRecordFieldResolver(scopedDao, collection, requestInfo, true)
+			expr, err := search.FilterData(*collection.UpdateRule).BuildExpr(resolver)
+			if err != nil {
+				return err
+			}
+			resolver.UpdateQuery(query)
+			query.AndWhere(expr)
+		}
+
+		return query.One(models.NewRecord(collection))
+	})
+
+	return err == nil
+}
+
+// upload handles the local filesystem fallback of the direct
+// storage uploads by accepting the raw file content for a single
+// one-time upload token.
+func (api *fileApi) upload(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("", nil)
+	}
+
+	claims, _ := security.ParseJWT(c.PathParam("token"), api.app.Settings().RecordFileToken.Secret)
+	if claims == nil ||
+		cast.ToString(claims["type"]) != tokens.TypeUpload ||
+		cast.ToString(claims["collectionId"]) != collection.Id ||
+		cast.ToString(claims["id"]) == "" {
+		return NewForbiddenError("Invalid or expired upload token.", nil)
+	}
+
+	key := cast.ToString(claims["key"])
+	size := cast.ToInt64(claims["size"])
+	mimeType := cast.ToString(claims["mimeType"])
+
+	if !strings.HasPrefix(key, collection.BaseUploadsPath()+"/") {
+		return NewForbiddenError("Invalid or expired upload token.", nil)
+	}
+
+	if c.Request().Header.Get("Content-Type") != mimeType {
+		return NewBadRequestError("The request Content-Type doesn't match with the upload token.", nil)
+	}
+
+	if c.Request().ContentLength >= 0 && c.Request().ContentLength != size {
+		return NewBadRequestError("The uploaded file size doesn't match with the upload token.", nil)
+	}
+
+	// the token is valid only for a single upload
+	// (it is consumed before the upload to prevent concurrent uploads)
+	expires := time.Unix(cast.ToInt64(claims["exp"]), 0)
+	consumed, err := api.app.Dao().ConsumeUploadToken(cast.ToString(claims["id"]), expires)
+	if err != nil || !consumed {
+		return NewBadRequestError("The upload token has already been used.", err)
+	}
+
+	fs, err := api.app.NewFilesystem()
+	if err != nil {
+		return NewBadRequestError("Filesystem initialization failure.", err)
+	}
+	defer fs.Close()
+
+	// trace the file operations as part of the request
+	fs.SetContext(c.Request().Context())
+
+	body := &exactSizeReader{r: io.LimitReader(c.Request().Body, size+1), size: size}
+
+	if err := fs.UploadStream(body, key); err != nil {
+		if errors.Is(err, errUploadSizeMismatch) {
+			return NewBadRequestError("The uploaded file size doesn't match with the upload token.", nil)
+		}
+		return NewBadRequestError("Failed to upload the file.", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+var errUploadSizeMismatch = errors.New("upload size mismatch")
+
+// exactSizeReader is an [io.Reader] that fails with errUploadSizeMismatch
+// if the underlying reader content is not exactly size bytes.
+type exactSizeReader struct {
+	r    io.Reader
+	size int64
+	read int64
+}
+
+// Read implements the [io.Reader] interface.
+func (r *exactSizeReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+
+	r.read += int64(n)
+
+	if r.read > r.size || (errors.Is(err, io.EOF) && r.read != r.size) {
+		return n, errUploadSizeMismatch
+	}
+
+	return n, err
+}
+
 func (api *fileApi) download(c echo.Context) error {
 	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
 	if collection == nil {
@@ -155,13 +387,27 @@ func (api *fileApi) download(c echo.Context) error {
 	}
 	defer fsys.Close()
 
+	// trace the file operations as part of the request
+	fsys.SetContext(c.Request().Context())
+
 	originalPath := baseFilesPath + "/" + filename
 	servedPath := originalPath
 	servedName := filename
 
+	transform, transformErr := parseImageTransform(c, options)
+	if transformErr != nil {
+		return NewBadRequestError("Invalid or not allowed image transformation.", transformErr)
+	}
+
 	// check for valid thumb size param
 	thumbSize := c.QueryParam("thumb")
 	if thumbSize != "" && (list.ExistInSlice(thumbSize, defaultThumbSizes) || list.ExistInSlice(thumbSize, options.Thumbs)) {
+		transform.Size = thumbSize
+	} else {
+		transform.Dpr = 0 // the dpr is applicable only for the thumb sizes
+	}
+
+	if !transform.IsZero() {
 		// extract the original file meta attributes and check it existence
 		oAttrs, oAttrsErr := fsys.Attributes(originalPath)
 		if oAttrsErr != nil {
@@ -170,13 +416,21 @@ func (api *fileApi) download(c echo.Context) error {
 
 		// check if it is an image
 		if list.ExistInSlice(oAttrs.ContentType, imageContentTypes) {
-			// add thumb size as file suffix
-			servedName = thumbSize + "_" + filename
+			// add the transformation key (eg. thumb size) as file prefix
+			// and the output format as file extension
+			transformKey := transform.Key()
+			if transformKey == "" {
+				transformKey = "original"
+			}
+			servedName = transformKey + "_" + filename
+			if transform.Format != "" {
+				servedName += "." + transform.Format
+			}
 			servedPath = baseFilesPath + "/thumbs_" + filename + "/" + servedName
 
 			// create a new thumb if it doesn't exist
 			if exists, _ := fsys.Exists(servedPath); !exists {
-				if err := api.createThumb(c, fsys, originalPath, servedPath, thumbSize); err != nil {
+				if err := api.createThumb(c, fsys, originalPath, servedPath, transform); err != nil {
 					api.app.Logger().Warn(
 						"Fallback to original - failed to create thumb "+servedName,
 						slog.Any("error", err),
@@ -254,7 +508,7 @@ func (api *fileApi) createThumb(
 	fsys *filesystem.System,
 	originalPath string,
 	thumbPath string,
-	thumbSize string,
+	transform filesystem.ImageTransform,
 ) error {
 	ch := api.thumbGenPending.DoChan(thumbPath, func() (any, error) {
 		ctx, cancel := context.WithTimeout(c.Request().Context(), api.thumbGenMaxWait)
@@ -265,7 +519,12 @@ func (api *fileApi) createThumb(
 		}
 		defer api.thumbGenSem.Release(1)
 
-		return nil, fsys.CreateThumb(originalPath, thumbPath, thumbSize)
+		// preserve the default thumbs behavior for the plain thumb sizes
+		if transform == (filesystem.ImageTransform{Size: transform.Size}) {
+			return nil, fsys.CreateThumb(originalPath, thumbPath, transform.Size)
+		}
+
+		return nil, fsys.CreateImageTransform(originalPath, thumbPath, transform)
 	})
 
 	res := <-ch
@@ -274,3 +533,66 @@ func (api *fileApi) createThumb(
 
 	return res.Err
 }
+
+// parseImageTransform loads the image transformation query parameters
+// (format, quality, rotate, blur and dpr) and validates them
+// against the file field transforms allowlist.
+func parseImageTransform(c echo.Context, options *schema.FileOptions) (filesystem.ImageTransform, error) {
+	transform := filesystem.ImageTransform{}
+
+	allowed := options.Transforms
+	if allowed == nil {
+		allowed = &schema.FileTransformsOptions{}
+	}
+
+	errs := validation.Errors{}
+
+	if v := strings.ToLower(c.QueryParam("format")); v != "" {
+		if v == "jpg" {
+			v = "jpeg"
+		}
+		if !list.ExistInSlice(v, allowed.Formats) || !filesystem.HasImageFormat(v) {
+			errs["format"] = validation.NewError("validation_invalid_image_format", "The image format is not allowed or supported.")
+		}
+		transform.Format = v
+	}
+
+	if v := c.QueryParam("quality"); v != "" {
+		quality, err := strconv.Atoi(v)
+		if !allowed.Quality || err != nil || quality < 1 || quality > 100 {
+			errs["quality"] = validation.NewError("validation_invalid_image_quality", "The image quality is not allowed or is not an integer between 1 and 100.")
+		}
+		transform.Quality = quality
+	}
+
+	if v := c.QueryParam("rotate"); v != "" {
+		rotate, err := strconv.Atoi(v)
+		if !allowed.Rotate || err != nil || (rotate != 0 && rotate != 90 && rotate != 180 && rotate != 270) {
+			errs["rotate"] = validation.NewError("validation_invalid_image_rotate", "The image rotation is not allowed or is not one of 0, 90, 180 or 270.")
+		}
+		transform.Rotate = rotate
+	}
+
+	if v := c.QueryParam("blur"); v != "" {
+		blur, err := strconv.ParseFloat(v, 64)
+		if !allowed.Blur || err != nil || blur < 0 || blur > 50 {
+			errs["blur"] = validation.NewError("validation_invalid_image_blur", "The image blur is not allowed or is not a number between 0 and 50.")
+		}
+		// round to limit the number of the cached blur variants
+		transform.Blur = filesystem.QuantizeBlur(blur)
+	}
+
+	if v := c.QueryParam("dpr"); v != "" {
+		dpr, err := strconv.Atoi(v)
+		if err != nil || dpr < 1 || (dpr > 1 && dpr > allowed.MaxDpr) {
+			errs["dpr"] = validation.NewError("validation_invalid_image_dpr", "The image dpr is not allowed or is out of range.")
+		}
+		transform.Dpr = dpr
+	}
+
+	if len(errs) > 0 {
+		return filesystem.ImageTransform{}, errs
+	}
+
+	return transform, nil
+}
@@ -0,0 +1,274 @@
+package apis
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/forms"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/filesystem"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/tus"
+	"github.com/labstack/echo/v5"
+)
+
+// tusUploadExpiry is the max duration of a single resumable upload
+// before its received chunks are deleted.
+const tusUploadExpiry = 24 * time.Hour
+
+// tusChunkContentType is the required content type of the tus PATCH requests.
+const tusChunkContentType = "application/offset+octet-stream"
+
+// tusOptions handles the tus protocol capabilities discovery request.
+func (api *fileApi) tusOptions(c echo.Context) error {
+	setTusHeaders(c)
+	c.Response().Header().Set("Tus-Version", tus.Version)
+	c.Response().Header().Set("Tus-Extension", tus.Extensions)
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// tusCreate registers a new resumable upload for a single collection file field.
+//
+// The file field and name are read from the "field" and "filename"
+// Upload-Metadata keys and are validated the same way as the presigned uploads.
+func (api *fileApi) tusCreate(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("", nil)
+	}
+
+	if err := checkDirectUploadAccess(c, api.app, collection); err != nil {
+		return err
+	}
+
+	if err := checkTusResumable(c); err != nil {
+		return err
+	}
+
+	size, err := strconv.ParseInt(c.Request().Header.Get("Upload-Length"), 10, 64)
+	if err != nil || size < 0 {
+		return NewBadRequestError("Missing or invalid Upload-Length header.", err)
+	}
+
+	metadata, err := tus.ParseMetadata(c.Request().Header.Get("Upload-Metadata"))
+	if err != nil {
+		return NewBadRequestError("Invalid Upload-Metadata header.", err)
+	}
+
+	form := forms.NewRecordFilePresign(api.app, collection)
+	form.Field = metadata["field"]
+	form.Name = metadata["filename"]
+	form.Size = size
+	form.MimeType = metadata["filetype"]
+	if form.MimeType == "" {
+		form.MimeType = "application/octet-stream"
+	}
+
+	if err := form.Validate(); err != nil {
+		return NewBadRequestError("Failed to create the upload.", err)
+	}
+
+	// cleanup the abandoned uploads (if any)
+	if err := api.tusStore.DeleteExpired(); err != nil {
+		api.app.Logger().Debug(
+			"Failed to delete the expired tus uploads",
+			slog.String("error", err.Error()),
+		)
+	}
+
+	key := form.NewUploadKey()
+
+	info, err := api.tusStore.Create(size, map[string]string{
+		"collectionId": collection.Id,
+		"key":          key,
+		"creator":      tusCreator(c),
+	}, tusUploadExpiry)
+	if err != nil {
+		return NewBadRequestError("Failed to create the upload.", err)
+	}
+
+	setTusHeaders(c)
+	c.Response().Header().Set("Location", "/api/files/"+collection.Id+"/tus/"+info.Id)
+	c.Response().Header().Set("Upload-Expires", info.Expires.Format(http.TimeFormat))
+	c.Response().Header().Set("Upload-Key", key)
+
+	return c.NoContent(http.StatusCreated)
+}
+
+// tusHead returns the current offset of a pending resumable upload.
+func (api *fileApi) tusHead(c echo.Context) error {
+	info, err := api.findTusUpload(c)
+	if err != nil {
+		return err
+	}
+
+	setTusHeaders(c)
+	c.Response().Header().Set("Cache-Control", "no-store")
+	c.Response().Header().Set("Upload-Offset", strconv.FormatInt(info.Offset, 10))
+	c.Response().Header().Set("Upload-Length", strconv.FormatInt(info.Size, 10))
+	c.Response().Header().Set("Upload-Expires", info.Expires.Format(http.TimeFormat))
+	c.Response().Header().Set("Upload-Key", info.Metadata["key"])
+
+	return c.NoContent(http.StatusOK)
+}
+
+// tusPatch appends a single chunk to a pending resumable upload.
+//
+// Once all chunks are received, the file is moved to the collection
+// uploads storage dir and its key could be submitted as a record
+// file field value (the same as with the presigned uploads).
+func (api *fileApi) tusPatch(c echo.Context) error {
+	if err := checkTusResumable(c); err != nil {
+		return err
+	}
+
+	if c.Request().Header.Get("Content-Type") != tusChunkContentType {
+		return NewApiError(http.StatusUnsupportedMediaType, "The Content-Type header must be "+tusChunkContentType+".", nil)
+	}
+
+	offset, err := strconv.ParseInt(c.Request().Header.Get("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		return NewBadRequestError("Missing or invalid Upload-Offset header.", err)
+	}
+
+	info, err := api.findTusUpload(c)
+	if err != nil {
+		return err
+	}
+
+	unlock, err := api.tusStore.Lock(info.Id)
+	if err != nil {
+		return NewApiError(http.StatusLocked, "The upload is being modified by another request.", nil)
+	}
+	defer unlock()
+
+	info, err = api.tusStore.WriteChunk(info.Id, offset, c.Request().Body)
+	switch {
+	case errors.Is(err, tus.ErrNotFound):
+		return NewNotFoundError("", err)
+	case errors.Is(err, tus.ErrOffsetMismatch):
+		return NewApiError(http.StatusConflict, "The Upload-Offset header doesn't match with the current upload offset.", nil)
+	case errors.Is(err, tus.ErrSizeExceeded):
+		return NewApiError(http.StatusRequestEntityTooLarge, "The chunk exceeds the upload length.", nil)
+	case err != nil:
+		return NewBadRequestError("Failed to write the upload chunk.", err)
+	}
+
+	if info.IsComplete() {
+		if err := api.completeTusUpload(info); err != nil {
+			return NewBadRequestError("Failed to store the completed upload.", err)
+		}
+	}
+
+	setTusHeaders(c)
+	c.Response().Header().Set("Upload-Offset", strconv.FormatInt(info.Offset, 10))
+	c.Response().Header().Set("Upload-Key", info.Metadata["key"])
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// tusDelete terminates a pending resumable upload.
+func (api *fileApi) tusDelete(c echo.Context) error {
+	if err := checkTusResumable(c); err != nil {
+		return err
+	}
+
+	info, err := api.findTusUpload(c)
+	if err != nil {
+		return err
+	}
+
+	unlock, err := api.tusStore.Lock(info.Id)
+	if err != nil {
+		return NewApiError(http.StatusLocked, "The upload is being modified by another request.", nil)
+	}
+	defer unlock()
+
+	if err := api.tusStore.Delete(info.Id); err != nil {
+		return NewBadRequestError("Failed to delete the upload.", err)
+	}
+
+	setTusHeaders(c)
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (api *fileApi) findTusUpload(c echo.Context) (*tus.Info, error) {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return nil, NewNotFoundError("", nil)
+	}
+
+	info, err := api.tusStore.Get(c.PathParam("id"))
+	if err != nil || info.Metadata["collectionId"] != collection.Id {
+		return nil, NewNotFoundError("", err)
+	}
+
+	// only the upload creator can access it
+	if info.Metadata["creator"] != tusCreator(c) {
+		return nil, NewForbiddenError("The upload doesn't belong to the current request auth.", nil)
+	}
+
+	return info, nil
+}
+
+// tusCreator returns the identifier of the current request auth
+// (admin or auth record) that is stored with the created uploads
+// and is used to restrict the access to their follow-up requests.
+//
+// Returns empty string for guests (the guest uploads are accessible
+// by any guest that knows the random upload id).
+func tusCreator(c echo.Context) string {
+	if admin, _ := c.Get(ContextAdminKey).(*models.Admin); admin != nil {
+		return "admin:" + admin.Id
+	}
+
+	if record, _ := c.Get(ContextAuthRecordKey).(*models.Record); record != nil {
+		return "authRecord:" + record.Collection().Id + "/" + record.Id
+	}
+
+	return ""
+}
+
+// completeTusUpload uploads the fully received data to the storage
+// and deletes the local upload chunks.
+func (api *fileApi) completeTusUpload(info *tus.Info) error {
+	key := info.Metadata["key"]
+
+	file, err := filesystem.NewFileFromPath(api.tusStore.DataPath(info.Id))
+	if err != nil {
+		return err
+	}
+	file.OriginalName = path.Base(key)
+
+	fs, err := api.app.NewFilesystem()
+	if err != nil {
+		return err
+	}
+	defer fs.Close()
+
+	if err := fs.UploadFile(file, key); err != nil {
+		return err
+	}
+
+	return api.tusStore.Delete(info.Id)
+}
+
+// checkTusResumable checks whether the request tus protocol version is supported.
+func checkTusResumable(c echo.Context) error {
+	if strings.TrimSpace(c.Request().Header.Get("Tus-Resumable")) != tus.Version {
+		c.Response().Header().Set("Tus-Version", tus.Version)
+		return NewApiError(http.StatusPreconditionFailed, "Unsupported tus protocol version.", nil)
+	}
+
+	return nil
+}
+
+func setTusHeaders(c echo.Context) {
+	c.Response().Header().Set("Tus-Resumable", tus.Version)
+}
@@ -0,0 +1,382 @@
+package apis_test
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/apis"
+	"github.com/AlperRehaYAZGAN/postgresbase/core"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/tests"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/types"
+	"github.com/labstack/echo/v5"
+)
+
+func TestFileTusOptions(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:           "capabilities discovery",
+			Method:         http.MethodOptions,
+			Url:            "/api/files/demo5/tus",
+			ExpectedStatus: 204,
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, res *http.Response) {
+				expectedHeaders := map[string]string{
+					"Tus-Resumable": "1.0.0",
+					"Tus-Version":   "1.0.0",
+					"Tus-Extension": "creation,expiration,termination",
+				}
+				for k, v := range expectedHeaders {
+					if h := res.Header.Get(k); h != v {
+						t.Fatalf("Expected %s header %q, got %q", k, v, h)
+					}
+				}
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestFileTusCreate(t *testing.T) {
+	t.Parallel()
+
+	validMetadata := "field " + base64.StdEncoding.EncodeToString([]byte("file")) +
+		",filename " + base64.StdEncoding.EncodeToString([]byte("my video.mp4")) +
+		",filetype " + base64.StdEncoding.EncodeToString([]byte("video/mp4"))
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "missing collection",
+			Method:          http.MethodPost,
+			Url:             "/api/files/missing/tus",
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "guest in admin only collection",
+			Method: http.MethodPost,
+			Url:    "/api/files/demo1/tus",
+			RequestHeaders: map[string]string{
+				"Tus-Resumable":   "1.0.0",
+				"Upload-Length":   "10",
+				"Upload-Metadata": validMetadata,
+			},
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "guest not satisfying the collection create and update rules",
+			Method: http.MethodPost,
+			Url:    "/api/files/demo5/tus",
+			RequestHeaders: map[string]string{
+				"Tus-Resumable":   "1.0.0",
+				"Upload-Length":   "10",
+				"Upload-Metadata": validMetadata,
+			},
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "unsupported protocol version",
+			Method: http.MethodPost,
+			Url:    "/api/files/demo5/tus",
+			RequestHeaders: map[string]string{
+				"Tus-Resumable":   "0.2.2",
+				"Upload-Length":   "10",
+				"Upload-Metadata": validMetadata,
+			},
+			BeforeTestFunc:  allowDemo5GuestCreate,
+			ExpectedEvents:  map[string]int{"OnModelBeforeUpdate": 1, "OnModelAfterUpdate": 1},
+			ExpectedStatus:  412,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "invalid upload length",
+			Method: http.MethodPost,
+			Url:    "/api/files/demo5/tus",
+			RequestHeaders: map[string]string{
+				"Tus-Resumable":   "1.0.0",
+				"Upload-Length":   "abc",
+				"Upload-Metadata": validMetadata,
+			},
+			BeforeTestFunc:  allowDemo5GuestCreate,
+			ExpectedEvents:  map[string]int{"OnModelBeforeUpdate": 1, "OnModelAfterUpdate": 1},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "missing file field metadata",
+			Method: http.MethodPost,
+			Url:    "/api/files/demo5/tus",
+			RequestHeaders: map[string]string{
+				"Tus-Resumable": "1.0.0",
+				"Upload-Length": "10",
+			},
+			BeforeTestFunc: allowDemo5GuestCreate,
+			ExpectedEvents: map[string]int{"OnModelBeforeUpdate": 1, "OnModelAfterUpdate": 1},
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"field":{"code":"validation_required"`,
+				`"name":{"code":"validation_required"`,
+			},
+		},
+		{
+			Name:   "upload length exceeding the field max size",
+			Method: http.MethodPost,
+			Url:    "/api/files/demo5/tus",
+			RequestHeaders: map[string]string{
+				"Tus-Resumable":   "1.0.0",
+				"Upload-Length":   "5242881",
+				"Upload-Metadata": validMetadata,
+			},
+			BeforeTestFunc: allowDemo5GuestCreate,
+			ExpectedEvents: map[string]int{"OnModelBeforeUpdate": 1, "OnModelAfterUpdate": 1},
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"size":{"code":"validation_max_less_equal_than_required"`,
+			},
+		},
+		{
+			Name:   "valid upload",
+			Method: http.MethodPost,
+			Url:    "/api/files/demo5/tus",
+			RequestHeaders: map[string]string{
+				"Tus-Resumable":   "1.0.0",
+				"Upload-Length":   "10",
+				"Upload-Metadata": validMetadata,
+			},
+			BeforeTestFunc: allowDemo5GuestCreate,
+			ExpectedEvents: map[string]int{"OnModelBeforeUpdate": 1, "OnModelAfterUpdate": 1},
+			ExpectedStatus: 201,
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, res *http.Response) {
+				if h := res.Header.Get("Location"); !strings.HasPrefix(h, "/api/files/9n89pl5vkct6330/tus/") {
+					t.Fatalf("Unexpected Location header %q", h)
+				}
+
+				if h := res.Header.Get("Upload-Key"); !strings.HasPrefix(h, "9n89pl5vkct6330/_uploads/") || !strings.HasSuffix(h, "/my_video.mp4") {
+					t.Fatalf("Unexpected Upload-Key header %q", h)
+				}
+
+				if h := res.Header.Get("Upload-Expires"); h == "" {
+					t.Fatal("Expected non-empty Upload-Expires header")
+				}
+
+				// the pending uploads must survive the app restarts
+				id := path.Base(res.Header.Get("Location"))
+				if _, err := os.Stat(filepath.Join(app.DataDir(), core.LocalUploadsDirName, id)); err != nil {
+					t.Fatalf("Expected the upload %q to be stored in the uploads dir: %v", id, err)
+				}
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestFileTusUpload(t *testing.T) {
+	t.Parallel()
+
+	app, err := tests.NewTestApp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer app.Cleanup()
+
+	allowDemo5GuestCreate(t, app, nil)
+
+	e, err := apis.InitApi(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// optional request auth record
+	var authRecord *models.Record
+	e.Pre(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if authRecord != nil {
+				c.Set(apis.ContextAuthRecordKey, authRecord)
+			}
+			return next(c)
+		}
+	})
+
+	user, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	send := func(method string, url string, body string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		req.Header.Set("Tus-Resumable", "1.0.0")
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, req)
+
+		return recorder
+	}
+
+	create := func() (string, string) {
+		res := send(http.MethodPost, "/api/files/demo5/tus", "", map[string]string{
+			"Upload-Length": "10",
+			"Upload-Metadata": "field " + base64.StdEncoding.EncodeToString([]byte("file")) +
+				",filename " + base64.StdEncoding.EncodeToString([]byte("test.txt")) +
+				",filetype " + base64.StdEncoding.EncodeToString([]byte("text/plain")),
+		})
+		if res.Code != 201 {
+			t.Fatalf("Failed to create upload (%d): %s", res.Code, res.Body.String())
+		}
+
+		return res.Header().Get("Location"), res.Header().Get("Upload-Key")
+	}
+
+	chunkHeaders := func(offset string) map[string]string {
+		return map[string]string{
+			"Content-Type":  "application/offset+octet-stream",
+			"Upload-Offset": offset,
+		}
+	}
+
+	location, key := create()
+
+	// initial offset
+	res := send(http.MethodHead, location, "", nil)
+	if res.Code != 200 || res.Header().Get("Upload-Offset") != "0" || res.Header().Get("Upload-Length") != "10" {
+		t.Fatalf("Unexpected HEAD response (%d): %v", res.Code, res.Header())
+	}
+
+	// different request auth than the upload creator
+	authRecord = user
+	res = send(http.MethodHead, location, "", nil)
+	authRecord = nil
+	if res.Code != 403 {
+		t.Fatalf("Expected 403 for a different request auth, got %d", res.Code)
+	}
+
+	// different collection
+	res = send(http.MethodHead, strings.Replace(location, "9n89pl5vkct6330", "demo3", 1), "", nil)
+	if res.Code != 404 {
+		t.Fatalf("Expected 404 for a different collection, got %d", res.Code)
+	}
+
+	// invalid chunk content type
+	res = send(http.MethodPatch, location, "test", map[string]string{"Upload-Offset": "0", "Content-Type": "text/plain"})
+	if res.Code != 415 {
+		t.Fatalf("Expected 415 for invalid chunk content type, got %d", res.Code)
+	}
+
+	// offset mismatch
+	res = send(http.MethodPatch, location, "test", chunkHeaders("2"))
+	if res.Code != 409 {
+		t.Fatalf("Expected 409 for offset mismatch, got %d", res.Code)
+	}
+
+	// first chunk
+	res = send(http.MethodPatch, location, "test", chunkHeaders("0"))
+	if res.Code != 204 || res.Header().Get("Upload-Offset") != "4" {
+		t.Fatalf("Unexpected first chunk response (%d): %v", res.Code, res.Header())
+	}
+
+	// chunk exceeding the upload length
+	res = send(http.MethodPatch, location, "1234567890", chunkHeaders("4"))
+	if res.Code != 413 {
+		t.Fatalf("Expected 413 for too large chunk, got %d", res.Code)
+	}
+
+	// resume
+	res = send(http.MethodHead, location, "", nil)
+	if res.Code != 200 || res.Header().Get("Upload-Offset") != "4" {
+		t.Fatalf("Unexpected resume HEAD response (%d): %v", res.Code, res.Header())
+	}
+
+	// last chunk
+	res = send(http.MethodPatch, location, "123456", chunkHeaders("4"))
+	if res.Code != 204 || res.Header().Get("Upload-Offset") != "10" || res.Header().Get("Upload-Key") != key {
+		t.Fatalf("Unexpected last chunk response (%d): %v", res.Code, res.Header())
+	}
+
+	// the completed upload chunks should be deleted
+	res = send(http.MethodHead, location, "", nil)
+	if res.Code != 404 {
+		t.Fatalf("Expected 404 for completed upload, got %d", res.Code)
+	}
+
+	fs, err := app.NewFilesystem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	if exists, _ := fs.Exists(key); !exists {
+		t.Fatalf("Expected the completed upload to be stored as %q", key)
+	}
+
+	// attach to a new record
+	res = send(http.MethodPost, "/api/collections/demo5/records", `{"total":3,"file":"`+key+`"}`, map[string]string{
+		"Content-Type": "application/json",
+	})
+	if res.Code != 200 || !strings.Contains(res.Body.String(), `"file":"test_`) {
+		t.Fatalf("Failed to attach the completed upload (%d): %s", res.Code, res.Body.String())
+	}
+
+	// termination
+	location, _ = create()
+
+	res = send(http.MethodDelete, location, "", nil)
+	if res.Code != 204 {
+		t.Fatalf("Expected 204 for upload termination, got %d", res.Code)
+	}
+
+	res = send(http.MethodHead, location, "", nil)
+	if res.Code != 404 {
+		t.Fatalf("Expected 404 for terminated upload, got %d", res.Code)
+	}
+
+	// auth record upload
+	authRecord = user
+	location, _ = create()
+	authRecord = nil
+
+	for _, method := range []string{http.MethodHead, http.MethodPatch, http.MethodDelete} {
+		res = send(method, location, "test", chunkHeaders("0"))
+		if res.Code != 403 {
+			t.Fatalf("Expected 403 for guest %s request to an auth record upload, got %d", method, res.Code)
+		}
+	}
+
+	authRecord = user
+	res = send(http.MethodHead, location, "", nil)
+	authRecord = nil
+	if res.Code != 200 {
+		t.Fatalf("Expected 200 for the upload creator, got %d", res.Code)
+	}
+}
+
+// allowDemo5GuestCreate allows guests to create demo5 records since
+// the tus requests don't have a body to satisfy the collection create rule.
+func allowDemo5GuestCreate(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+	collection, err := app.Dao().FindCollectionByNameOrId("demo5")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collection.CreateRule = types.Pointer("")
+
+	if err := app.Dao().SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+}
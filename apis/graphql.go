@@ -0,0 +1,826 @@
+package apis
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/core"
+	"github.com/AlperRehaYAZGAN/postgresbase/daos"
+	"github.com/AlperRehaYAZGAN/postgresbase/forms"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/models/schema"
+	"github.com/AlperRehaYAZGAN/postgresbase/resolvers"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/graphql"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/inflector"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/search"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/types"
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/dbx"
+)
+
+// The max allowed nesting level and total number of fields of a single GraphQL operation.
+const (
+	graphqlMaxDepth      = 10
+	graphqlMaxComplexity = 1000
+)
+
+// bindGraphqlApi registers the GraphQL api endpoints.
+func bindGraphqlApi(app core.App, rg *echo.Group) {
+	api := graphqlApi{app: app}
+
+	subGroup := rg.Group("/graphql", ActivityLogger(app))
+	subGroup.GET("", api.schema, RequireAdminAuth())
+	subGroup.POST("", api.execute)
+}
+
+type graphqlApi struct {
+	app core.App
+}
+
+// schema returns the GraphQL schema definition language
+// representation of the current collections schema.
+func (api *graphqlApi) schema(c echo.Context) error {
+	gqlSchema, err := newGraphqlSchemaBuilder(api.app, c).build()
+	if err != nil {
+		return NewBadRequestError("Failed to generate the GraphQL schema.", err)
+	}
+
+	return c.String(http.StatusOK, gqlSchema.SDL())
+}
+
+func (api *graphqlApi) execute(c echo.Context) error {
+	req := graphql.Request{}
+	if err := c.Bind(&req); err != nil {
+		return NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	if req.Query == "" {
+		return NewBadRequestError("Missing GraphQL query.", nil)
+	}
+
+	gqlSchema, err := newGraphqlSchemaBuilder(api.app, c).build()
+	if err != nil {
+		return NewBadRequestError("Failed to generate the GraphQL schema.", err)
+	}
+
+	// the introspection exposes the full schema and, similar to
+	// the SDL endpoint, it is available only for admins
+	admin, _ := c.Get(ContextAdminKey).(*models.Admin)
+	gqlSchema.Introspection = admin != nil
+	gqlSchema.MaxDepth = graphqlMaxDepth
+	gqlSchema.MaxComplexity = graphqlMaxComplexity
+
+	result := gqlSchema.Execute(req)
+
+	// a hook has already written the response
+	if c.Response().Committed {
+		return nil
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// -------------------------------------------------------------------
+
+// graphqlError wraps an ApiError so that its code and
+// data are returned as GraphQL error extensions.
+type graphqlError struct {
+	*ApiError
+}
+
+// Extensions implements the [graphql.ExtendedError] interface.
+func (e graphqlError) Extensions() map[string]any {
+	return map[string]any{
+		"code": e.Code,
+		"data": e.Data,
+	}
+}
+
+func toGraphqlError(err error) error {
+	var apiErr *ApiError
+	if errors.As(err, &apiErr) {
+		return graphqlError{apiErr}
+	}
+
+	return err
+}
+
+// graphqlSchemaBuilder generates a GraphQL schema from the app collections.
+//
+// The schema is bound to a single request context because the resolvers
+// apply the collection API rules for the request auth state.
+type graphqlSchemaBuilder struct {
+	app core.App
+	c   echo.Context
+
+	// collection id -> record object type
+	objects map[string]*graphql.Object
+
+	// collection id -> graphql field name -> schema relation field
+	relations map[string]map[string]*schema.SchemaField
+}
+
+func newGraphqlSchemaBuilder(app core.App, c echo.Context) *graphqlSchemaBuilder {
+	return &graphqlSchemaBuilder{
+		app:       app,
+		c:         c,
+		objects:   map[string]*graphql.Object{},
+		relations: map[string]map[string]*schema.SchemaField{},
+	}
+}
+
+func (b *graphqlSchemaBuilder) build() (*graphql.Schema, error) {
+	collections := []*models.Collection{}
+	if err := b.app.Dao().CollectionQuery().OrderBy("name ASC").All(&collections); err != nil {
+		return nil, err
+	}
+
+	// register all record types first so that they could be referenced by the relation fields
+	for _, collection := range collections {
+		b.objects[collection.Id] = graphql.NewObject(
+			graphqlTypeName(collection.Name)+"Record",
+			"A single "+collection.Name+" collection record.",
+		)
+	}
+
+	query := graphql.NewObject("Query", "")
+	mutation := graphql.NewObject("Mutation", "")
+
+	for _, collection := range collections {
+		b.registerRecordFields(collection)
+		b.registerQueries(query, collection)
+		if !collection.IsView() {
+			b.registerMutations(mutation, collection)
+		}
+	}
+
+	result := &graphql.Schema{Query: query}
+	if len(mutation.Fields()) > 0 {
+		result.Mutation = mutation
+	}
+
+	return result, nil
+}
+
+func (b *graphqlSchemaBuilder) registerRecordFields(collection *models.Collection) {
+	obj := b.objects[collection.Id]
+	b.relations[collection.Id] = map[string]*schema.SchemaField{}
+
+	export := func(name string) graphql.ResolveFunc {
+		return func(p graphql.ResolveParams) (any, error) {
+			record, _ := p.Source.(*models.Record)
+			if record == nil {
+				return nil, nil
+			}
+			return record.PublicExport()[name], nil
+		}
+	}
+
+	obj.AddField(&graphql.FieldDefinition{Name: schema.FieldNameId, Type: graphql.NonNull(graphql.Scalar(graphql.ScalarID)), Resolve: export(schema.FieldNameId)})
+	obj.AddField(&graphql.FieldDefinition{Name: schema.FieldNameCollectionId, Type: graphql.NonNull(graphql.Scalar(graphql.ScalarString)), Resolve: export(schema.FieldNameCollectionId)})
+	obj.AddField(&graphql.FieldDefinition{Name: schema.FieldNameCollectionName, Type: graphql.NonNull(graphql.Scalar(graphql.ScalarString)), Resolve: export(schema.FieldNameCollectionName)})
+	obj.AddField(&graphql.FieldDefinition{Name: schema.FieldNameCreated, Type: graphql.Scalar(graphql.ScalarString), Resolve: export(schema.FieldNameCreated)})
+	obj.AddField(&graphql.FieldDefinition{Name: schema.FieldNameUpdated, Type: graphql.Scalar(graphql.ScalarString), Resolve: export(schema.FieldNameUpdated)})
+
+	if collection.IsAuth() {
+		obj.AddField(&graphql.FieldDefinition{Name: schema.FieldNameUsername, Type: graphql.NonNull(graphql.Scalar(graphql.ScalarString)), Resolve: export(schema.FieldNameUsername)})
+		obj.AddField(&graphql.FieldDefinition{Name: schema.FieldNameEmail, Type: graphql.Scalar(graphql.ScalarString), Resolve: export(schema.FieldNameEmail)})
+		obj.AddField(&graphql.FieldDefinition{Name: schema.FieldNameEmailVisibility, Type: graphql.NonNull(graphql.Scalar(graphql.ScalarBoolean)), Resolve: export(schema.FieldNameEmailVisibility)})
+		obj.AddField(&graphql.FieldDefinition{Name: schema.FieldNameVerified, Type: graphql.NonNull(graphql.Scalar(graphql.ScalarBoolean)), Resolve: export(schema.FieldNameVerified)})
+	}
+
+	for _, field := range collection.Schema.Fields() {
+		name := graphqlName(field.Name)
+
+		if field.Type == schema.FieldTypeRelation {
+			options, _ := field.Options.(*schema.RelationOptions)
+			if options == nil || b.objects[options.CollectionId] == nil {
+				continue // missing related collection
+			}
+
+			relType := graphql.ObjectType(b.objects[options.CollectionId])
+			if options.IsMultiple() {
+				relType = graphql.ListOf(graphql.NonNull(relType))
+			}
+
+			b.relations[collection.Id][name] = field
+
+			fieldName := field.Name
+			obj.AddField(&graphql.FieldDefinition{
+				Name:        name,
+				Description: "The related record(s) (resolved only if the related collection ViewRule is satisfied).",
+				Type:        relType,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					record, _ := p.Source.(*models.Record)
+					if record == nil {
+						return nil, nil
+					}
+					return record.Expand()[fieldName], nil
+				},
+			})
+			continue
+		}
+
+		fieldName := field.Name
+		obj.AddField(&graphql.FieldDefinition{
+			Name: name,
+			Type: graphqlFieldType(field),
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				record, _ := p.Source.(*models.Record)
+				if record == nil {
+					return nil, nil
+				}
+
+				value := record.Get(fieldName)
+				if dt, ok := value.(types.DateTime); ok && dt.IsZero() {
+					return nil, nil
+				}
+
+				return value, nil
+			},
+		})
+	}
+}
+
+func (b *graphqlSchemaBuilder) registerQueries(query *graphql.Object, collection *models.Collection) {
+	obj := b.objects[collection.Id]
+	name := graphqlName(collection.Name)
+
+	listObj := graphql.NewObject(obj.Name+"List", "Paginated "+collection.Name+" records list.")
+	listObj.AddField(&graphql.FieldDefinition{Name: "page", Type: graphql.NonNull(graphql.Scalar(graphql.ScalarInt))})
+	listObj.AddField(&graphql.FieldDefinition{Name: "perPage", Type: graphql.NonNull(graphql.Scalar(graphql.ScalarInt))})
+	listObj.AddField(&graphql.FieldDefinition{Name: "totalItems", Type: graphql.NonNull(graphql.Scalar(graphql.ScalarInt))})
+	listObj.AddField(&graphql.FieldDefinition{Name: "totalPages", Type: graphql.NonNull(graphql.Scalar(graphql.ScalarInt))})
+	listObj.AddField(&graphql.FieldDefinition{Name: "items", Type: graphql.NonNull(graphql.ListOf(graphql.NonNull(graphql.ObjectType(obj))))})
+
+	query.AddField(&graphql.FieldDefinition{
+		Name:        name + "List",
+		Description: "Returns a paginated " + collection.Name + " records list.",
+		Type:        graphql.NonNull(graphql.ObjectType(listObj)),
+		Args: []*graphql.ArgumentDefinition{
+			{Name: search.PageQueryParam, Type: graphql.Scalar(graphql.ScalarInt)},
+			{Name: search.PerPageQueryParam, Type: graphql.Scalar(graphql.ScalarInt)},
+			{Name: search.SortQueryParam, Type: graphql.Scalar(graphql.ScalarString)},
+			{Name: search.FilterQueryParam, Type: graphql.Scalar(graphql.ScalarString)},
+			{Name: search.SkipTotalQueryParam, Type: graphql.Scalar(graphql.ScalarBoolean)},
+		},
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			return b.resolve(collection, func(dao *daos.Dao) (any, error) {
+				return b.list(p, collection, dao)
+			})
+		},
+	})
+
+	query.AddField(&graphql.FieldDefinition{
+		Name:        name,
+		Description: "Returns a single " + collection.Name + " record by its id.",
+		Type:        graphql.ObjectType(obj),
+		Args: []*graphql.ArgumentDefinition{
+			{Name: "id", Type: graphql.NonNull(graphql.Scalar(graphql.ScalarID))},
+		},
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			return b.resolve(collection, func(dao *daos.Dao) (any, error) {
+				return b.view(p, collection, dao)
+			})
+		},
+	})
+}
+
+func (b *graphqlSchemaBuilder) registerMutations(mutation *graphql.Object, collection *models.Collection) {
+	obj := b.objects[collection.Id]
+	typeName := graphqlTypeName(collection.Name)
+
+	mutation.AddField(&graphql.FieldDefinition{
+		Name:        "create" + typeName,
+		Description: "Creates a new " + collection.Name + " record.",
+		Type:        graphql.NonNull(graphql.ObjectType(obj)),
+		Args: []*graphql.ArgumentDefinition{
+			{Name: "data", Type: graphql.NonNull(graphql.Scalar("JSON"))},
+		},
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			return b.resolve(collection, func(dao *daos.Dao) (any, error) {
+				return b.create(p, collection, dao)
+			})
+		},
+	})
+
+	mutation.AddField(&graphql.FieldDefinition{
+		Name:        "update" + typeName,
+		Description: "Updates a single " + collection.Name + " record.",
+		Type:        graphql.NonNull(graphql.ObjectType(obj)),
+		Args: []*graphql.ArgumentDefinition{
+			{Name: "id", Type: graphql.NonNull(graphql.Scalar(graphql.ScalarID))},
+			{Name: "data", Type: graphql.NonNull(graphql.Scalar("JSON"))},
+		},
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			return b.resolve(collection, func(dao *daos.Dao) (any, error) {
+				return b.update(p, collection, dao)
+			})
+		},
+	})
+
+	mutation.AddField(&graphql.FieldDefinition{
+		Name:        "delete" + typeName,
+		Description: "Deletes a single " + collection.Name + " record.",
+		Type:        graphql.NonNull(graphql.Scalar(graphql.ScalarBoolean)),
+		Args: []*graphql.ArgumentDefinition{
+			{Name: "id", Type: graphql.NonNull(graphql.Scalar(graphql.ScalarID))},
+		},
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			return b.resolve(collection, func(dao *daos.Dao) (any, error) {
+				return b.delete(p, collection, dao)
+			})
+		},
+	})
+}
+
+// requestInfo returns a copy of the current request info
+// mimicking the equivalent REST records api request.
+func (b *graphqlSchemaBuilder) requestInfo(method string, data map[string]any) *models.RequestInfo {
+	info := *RequestInfo(b.c)
+
+	info.Method = method
+	info.Data = data
+	if info.Data == nil {
+		info.Data = map[string]any{}
+	}
+
+	return &info
+}
+
+// resolve runs the provided collection resolver in a new transaction
+// bound to the request tenant (see [RequestTenantDao]).
+func (b *graphqlSchemaBuilder) resolve(collection *models.Collection, fn func(dao *daos.Dao) (any, error)) (any, error) {
+	dao, err := RequestTenantDao(b.c, b.app, collection)
+	if err != nil {
+		return nil, toGraphqlError(err)
+	}
+
+	var result any
+
+	err = dao.RunInTenantScope(func(scopedDao *daos.Dao) error {
+		var resolveErr error
+		result, resolveErr = fn(scopedDao)
+		return resolveErr
+	})
+
+	return result, toGraphqlError(err)
+}
+
+// expands returns the relation expand paths of the provided record object selection set.
+func (b *graphqlSchemaBuilder) expands(p graphql.ResolveParams, collectionId string, set graphql.SelectionSet, prefix string) []string {
+	result := []string{}
+
+	for _, field := range p.CollectFields(set) {
+		relField := b.relations[collectionId][field.Name]
+		if relField == nil {
+			continue
+		}
+
+		path := relField.Name
+		if prefix != "" {
+			path = prefix + "." + path
+		}
+		result = append(result, path)
+
+		options, _ := relField.Options.(*schema.RelationOptions)
+		if options != nil {
+			result = append(result, b.expands(p, options.CollectionId, field.SelectionSet, path)...)
+		}
+	}
+
+	return result
+}
+
+func (b *graphqlSchemaBuilder) enrich(dao *daos.Dao, records []*models.Record, expands []string) {
+	if err := EnrichRecords(b.c, dao, records, expands...); err != nil {
+		b.app.Logger().Debug("Failed to enrich GraphQL records", slog.String("error", err.Error()))
+	}
+}
+
+func (b *graphqlSchemaBuilder) list(p graphql.ResolveParams, collection *models.Collection, dao *daos.Dao) (any, error) {
+	requestInfo := b.requestInfo(http.MethodGet, nil)
+
+	params := url.Values{}
+	for _, name := range []string{search.PageQueryParam, search.PerPageQueryParam, search.SortQueryParam, search.FilterQueryParam, search.SkipTotalQueryParam} {
+		if v, ok := p.Args[name]; ok && v != nil {
+			params.Set(name, fmt.Sprint(v))
+		}
+	}
+
+	// forbid users and guests to query special filter/sort fields
+	checkInfo := *requestInfo
+	checkInfo.Query = map[string]any{
+		search.FilterQueryParam: params.Get(search.FilterQueryParam),
+		search.SortQueryParam:   params.Get(search.SortQueryParam),
+	}
+	if err := checkForAdminOnlyRuleFields(&checkInfo); err != nil {
+		return nil, err
+	}
+
+	if requestInfo.Admin == nil && collection.ListRule == nil {
+		// only admins can access if the rule is nil
+		return nil, NewForbiddenError("Only admins can perform this action.", nil)
+	}
+
+	fieldsResolver := resolvers.NewRecordFieldResolver(
+		dao,
+		collection,
+		requestInfo,
+		// hidden fields are searchable only by admins
+		requestInfo.Admin != nil,
+	)
+
+	searchProvider := search.NewProvider(fieldsResolver).
+		Query(dao.RecordQuery(collection))
+
+	if requestInfo.Admin == nil && collection.ListRule != nil {
+		searchProvider.AddFilter(search.FilterData(*collection.ListRule))
+	}
+
+	records := []*models.Record{}
+
+	result, err := searchProvider.ParseAndExec(params.Encode(), &records)
+	if err != nil {
+		return nil, NewBadRequestError("", err)
+	}
+
+	// collect the expands from the "items" selection
+	expands := []string{}
+	for _, field := range p.CollectFields(p.Field.SelectionSet) {
+		if field.Name == "items" {
+			expands = append(expands, b.expands(p, collection.Id, field.SelectionSet, "")...)
+		}
+	}
+
+	event := new(core.RecordsListEvent)
+	event.HttpContext = b.c
+	event.Collection = collection
+	event.Records = records
+	event.Result = result
+
+	err = b.app.OnRecordsListRequest().Trigger(event, func(e *core.RecordsListEvent) error {
+		b.enrich(dao, e.Records, expands)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"page":       event.Result.Page,
+		"perPage":    event.Result.PerPage,
+		"totalItems": event.Result.TotalItems,
+		"totalPages": event.Result.TotalPages,
+		"items":      event.Records,
+	}, nil
+}
+
+func (b *graphqlSchemaBuilder) view(p graphql.ResolveParams, collection *models.Collection, dao *daos.Dao) (any, error) {
+	recordId, _ := p.Args["id"].(string)
+
+	requestInfo := b.requestInfo(http.MethodGet, nil)
+
+	if requestInfo.Admin == nil && collection.ViewRule == nil {
+		// only admins can access if the rule is nil
+		return nil, NewForbiddenError("Only admins can perform this action.", nil)
+	}
+
+	ruleFunc := func(q *dbx.SelectQuery) error {
+		if requestInfo.Admin == nil && collection.ViewRule != nil && *collection.ViewRule != "" {
+			resolver := resolvers.NewRecordFieldResolver(dao, collection, requestInfo, true)
+			expr, err := search.FilterData(*collection.ViewRule).BuildExpr(resolver)
+			if err != nil {
+				return err
+			}
+			resolver.UpdateQuery(q)
+			q.AndWhere(expr)
+		}
+		return nil
+	}
+
+	record, fetchErr := dao.FindRecordById(collection.Id, recordId, ruleFunc)
+	if errors.Is(fetchErr, sql.ErrNoRows) {
+		return nil, nil // not found
+	}
+	if fetchErr != nil {
+		return nil, NewBadRequestError("Failed to fetch the requested record.", fetchErr)
+	}
+
+	expands := b.expands(p, collection.Id, p.Field.SelectionSet, "")
+
+	event := new(core.RecordViewEvent)
+	event.HttpContext = b.c
+	event.Collection = collection
+	event.Record = record
+
+	err := b.app.OnRecordViewRequest().Trigger(event, func(e *core.RecordViewEvent) error {
+		b.enrich(dao, []*models.Record{e.Record}, expands)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return event.Record, nil
+}
+
+func (b *graphqlSchemaBuilder) create(p graphql.ResolveParams, collection *models.Collection, dao *daos.Dao) (any, error) {
+	data, ok := p.Args["data"].(map[string]any)
+	if !ok {
+		return nil, NewBadRequestError("The data argument must be an object.", nil)
+	}
+
+	requestInfo := b.requestInfo(http.MethodPost, data)
+
+	if requestInfo.Admin == nil && collection.CreateRule == nil {
+		// only admins can access if the rule is nil
+		return nil, NewForbiddenError("Only admins can perform this action.", nil)
+	}
+
+	hasFullManageAccess := requestInfo.Admin != nil
+
+	// temporary save the record and check it against the create rule
+	if requestInfo.Admin == nil && collection.CreateRule != nil {
+		testRecord := models.NewRecord(collection)
+
+		// replace modifiers fields so that the resolved value is always
+		// available when accessing requestInfo.Data using just the field name
+		if requestInfo.HasModifierDataKeys() {
+			requestInfo.Data = testRecord.ReplaceModifers(requestInfo.Data)
+		}
+
+		// the dry submit runs in its own transaction and therefore
+		// it uses the request tenant dao instead of the resolver one
+		tenantDao, err := RequestTenantDao(b.c, b.app, collection)
+		if err != nil {
+			return nil, err
+		}
+
+		testForm := forms.NewRecordUpsert(b.app, testRecord)
+		testForm.SetDao(tenantDao)
+		testForm.SetFullManageAccess(true)
+		if err := testForm.LoadData(data); err != nil {
+			return nil, NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+		}
+
+		testErr := testForm.DrySubmit(func(txDao *daos.Dao) error {
+			createRuleFunc := func(q *dbx.SelectQuery) error {
+				if *collection.CreateRule == "" {
+					return nil // no create rule to resolve
+				}
+
+				resolver := resolvers.NewRecordFieldResolver(txDao, collection, requestInfo, true)
+				expr, err := search.FilterData(*collection.CreateRule).BuildExpr(resolver)
+				if err != nil {
+					return err
+				}
+				resolver.UpdateQuery(q)
+				q.AndWhere(expr)
+				return nil
+			}
+
+			foundRecord, err := txDao.FindRecordById(collection.Id, testRecord.Id, createRuleFunc)
+			if err != nil {
+				return fmt.Errorf("DrySubmit create rule failure: %w", err)
+			}
+			hasFullManageAccess = hasAuthManageAccess(txDao, foundRecord, requestInfo)
+			return nil
+		})
+
+		if testErr != nil {
+			return nil, NewBadRequestError("Failed to create record.", testErr)
+		}
+	}
+
+	record := models.NewRecord(collection)
+	form := forms.NewRecordUpsert(b.app, record)
+	form.SetDao(dao)
+	form.SetFullManageAccess(hasFullManageAccess)
+
+	if err := form.LoadData(data); err != nil {
+		return nil, NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	expands := b.expands(p, collection.Id, p.Field.SelectionSet, "")
+
+	event := new(core.RecordCreateEvent)
+	event.HttpContext = b.c
+	event.Collection = collection
+	event.Record = record
+	event.UploadedFiles = form.FilesToUpload()
+
+	// create the record
+	submitErr := form.Submit(func(next forms.InterceptorNextFunc[*models.Record]) forms.InterceptorNextFunc[*models.Record] {
+		return func(m *models.Record) error {
+			event.Record = m
+
+			return b.app.OnRecordBeforeCreateRequest().Trigger(event, func(e *core.RecordCreateEvent) error {
+				if err := next(e.Record); err != nil {
+					return NewBadRequestError("Failed to create record.", err)
+				}
+
+				b.enrich(dao, []*models.Record{e.Record}, expands)
+
+				return b.app.OnRecordAfterCreateRequest().Trigger(event)
+			})
+		}
+	})
+	if submitErr != nil {
+		return nil, graphqlSubmitError("Failed to create record.", submitErr)
+	}
+
+	return event.Record, nil
+}
+
+func (b *graphqlSchemaBuilder) update(p graphql.ResolveParams, collection *models.Collection, dao *daos.Dao) (any, error) {
+	recordId, _ := p.Args["id"].(string)
+
+	data, ok := p.Args["data"].(map[string]any)
+	if !ok {
+		return nil, NewBadRequestError("The data argument must be an object.", nil)
+	}
+
+	requestInfo := b.requestInfo(http.MethodPatch, data)
+
+	if requestInfo.Admin == nil && collection.UpdateRule == nil {
+		// only admins can access if the rule is nil
+		return nil, NewForbiddenError("Only admins can perform this action.", nil)
+	}
+
+	// eager fetch the record so that the modifier field values are replaced
+	// and available when accessing requestInfo.Data using just the field name
+	if requestInfo.HasModifierDataKeys() {
+		record, err := dao.FindRecordById(collection.Id, recordId)
+		if err != nil || record == nil {
+			return nil, NewNotFoundError("", err)
+		}
+		requestInfo.Data = record.ReplaceModifers(requestInfo.Data)
+	}
+
+	ruleFunc := func(q *dbx.SelectQuery) error {
+		if requestInfo.Admin == nil && collection.UpdateRule != nil && *collection.UpdateRule != "" {
+			resolver := resolvers.NewRecordFieldResolver(dao, collection, requestInfo, true)
+			expr, err := search.FilterData(*collection.UpdateRule).BuildExpr(resolver)
+			if err != nil {
+				return err
+			}
+			resolver.UpdateQuery(q)
+			q.AndWhere(expr)
+		}
+		return nil
+	}
+
+	record, fetchErr := dao.FindRecordById(collection.Id, recordId, ruleFunc)
+	if fetchErr != nil || record == nil {
+		return nil, NewNotFoundError("", fetchErr)
+	}
+
+	form := forms.NewRecordUpsert(b.app, record)
+	form.SetDao(dao)
+	form.SetFullManageAccess(requestInfo.Admin != nil || hasAuthManageAccess(dao, record, requestInfo))
+
+	if err := form.LoadData(data); err != nil {
+		return nil, NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	expands := b.expands(p, collection.Id, p.Field.SelectionSet, "")
+
+	event := new(core.RecordUpdateEvent)
+	event.HttpContext = b.c
+	event.Collection = collection
+	event.Record = record
+	event.UploadedFiles = form.FilesToUpload()
+
+	// update the record
+	submitErr := form.Submit(func(next forms.InterceptorNextFunc[*models.Record]) forms.InterceptorNextFunc[*models.Record] {
+		return func(m *models.Record) error {
+			event.Record = m
+
+			return b.app.OnRecordBeforeUpdateRequest().Trigger(event, func(e *core.RecordUpdateEvent) error {
+				if err := next(e.Record); err != nil {
+					return NewBadRequestError("Failed to update record.", err)
+				}
+
+				b.enrich(dao, []*models.Record{e.Record}, expands)
+
+				return b.app.OnRecordAfterUpdateRequest().Trigger(event)
+			})
+		}
+	})
+	if submitErr != nil {
+		return nil, graphqlSubmitError("Failed to update record.", submitErr)
+	}
+
+	return event.Record, nil
+}
+
+func (b *graphqlSchemaBuilder) delete(p graphql.ResolveParams, collection *models.Collection, dao *daos.Dao) (any, error) {
+	recordId, _ := p.Args["id"].(string)
+
+	requestInfo := b.requestInfo(http.MethodDelete, nil)
+
+	if requestInfo.Admin == nil && collection.DeleteRule == nil {
+		// only admins can access if the rule is nil
+		return nil, NewForbiddenError("Only admins can perform this action.", nil)
+	}
+
+	ruleFunc := func(q *dbx.SelectQuery) error {
+		if requestInfo.Admin == nil && collection.DeleteRule != nil && *collection.DeleteRule != "" {
+			resolver := resolvers.NewRecordFieldResolver(dao, collection, requestInfo, true)
+			expr, err := search.FilterData(*collection.DeleteRule).BuildExpr(resolver)
+			if err != nil {
+				return err
+			}
+			resolver.UpdateQuery(q)
+			q.AndWhere(expr)
+		}
+		return nil
+	}
+
+	record, fetchErr := dao.FindRecordById(collection.Id, recordId, ruleFunc)
+	if fetchErr != nil || record == nil {
+		return nil, NewNotFoundError("", fetchErr)
+	}
+
+	event := new(core.RecordDeleteEvent)
+	event.HttpContext = b.c
+	event.Collection = collection
+	event.Record = record
+
+	err := b.app.OnRecordBeforeDeleteRequest().Trigger(event, func(e *core.RecordDeleteEvent) error {
+		// delete the record
+		if err := dao.DeleteRecord(e.Record); err != nil {
+			return NewBadRequestError("Failed to delete record. Make sure that the record is not part of a required relation reference.", err)
+		}
+
+		return b.app.OnRecordAfterDeleteRequest().Trigger(event)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return true, nil
+}
+
+// graphqlSubmitError normalizes a record form submit error
+// so that the validation errors are available in the error data.
+func graphqlSubmitError(message string, err error) error {
+	var apiErr *ApiError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+
+	return NewBadRequestError(message, err)
+}
+
+// graphqlFieldType returns the GraphQL type of a non-relation collection schema field.
+func graphqlFieldType(field *schema.SchemaField) *graphql.Type {
+	var result *graphql.Type
+
+	switch field.Type {
+	case schema.FieldTypeNumber:
+		result = graphql.Scalar(graphql.ScalarFloat)
+		if options, ok := field.Options.(*schema.NumberOptions); ok && options.NoDecimal {
+			result = graphql.Scalar(graphql.ScalarInt)
+		}
+	case schema.FieldTypeBool:
+		result = graphql.Scalar(graphql.ScalarBoolean)
+	case schema.FieldTypeJson:
+		result = graphql.Scalar("JSON")
+	case schema.FieldTypeSelect:
+		result = graphql.Scalar(graphql.ScalarString)
+		if options, ok := field.Options.(*schema.SelectOptions); ok && options.IsMultiple() {
+			result = graphql.ListOf(graphql.NonNull(result))
+		}
+	case schema.FieldTypeFile:
+		result = graphql.Scalar(graphql.ScalarString)
+		if options, ok := field.Options.(*schema.FileOptions); ok && options.IsMultiple() {
+			result = graphql.ListOf(graphql.NonNull(result))
+		}
+	default:
+		result = graphql.Scalar(graphql.ScalarString)
+	}
+
+	return result
+}
+
+// graphqlName normalizes the provided collection or field
+// name so that it is a valid GraphQL name.
+func graphqlName(name string) string {
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		return "_" + name
+	}
+
+	return name
+}
+
+// graphqlTypeName returns the GraphQL object type name prefix
+// for the provided collection name (eg. "posts" -> "Posts").
+func graphqlTypeName(collectionName string) string {
+	return graphqlName(inflector.UcFirst(collectionName))
+}
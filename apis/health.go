@@ -1,23 +1,61 @@
 package apis
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/AlperRehaYAZGAN/postgresbase/core"
+	"github.com/AlperRehaYAZGAN/postgresbase/migrations"
+	"github.com/AlperRehaYAZGAN/postgresbase/migrations/logs"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/migrate"
 	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/dbx"
+)
+
+// healthCheckTimeout is the max duration of a single readiness check.
+const healthCheckTimeout = 5 * time.Second
+
+// healthCheckCacheTTL is the duration for which the results of the
+// external dependency readiness checks (storage, smtp) are reused
+// to avoid hitting the dependencies with every probe request.
+const healthCheckCacheTTL = 10 * time.Second
+
+const (
+	healthCheckStatusOk    = "ok"
+	healthCheckStatusError = "error"
 )
 
 // bindHealthApi registers the health api endpoint.
 func bindHealthApi(app core.App, rg *echo.Group) {
-	api := healthApi{app: app}
+	api := healthApi{app: app, cache: map[string]*healthCheckCacheEntry{}}
 
 	subGroup := rg.Group("/health")
 	subGroup.HEAD("", api.healthCheck)
 	subGroup.GET("", api.healthCheck)
+	subGroup.HEAD("/live", api.live)
+	subGroup.GET("/live", api.live)
+	subGroup.HEAD("/ready", api.ready)
+	subGroup.GET("/ready", api.ready)
 }
 
 type healthApi struct {
 	app core.App
+
+	cacheMux sync.Mutex
+	cache    map[string]*healthCheckCacheEntry
+}
+
+type healthCheckCacheEntry struct {
+	mux       sync.Mutex
+	err       error
+	checkedAt time.Time
 }
 
 type healthCheckResponse struct {
@@ -28,6 +66,21 @@ type healthCheckResponse struct {
 	} `json:"data"`
 }
 
+type healthReadyResponse struct {
+	Message string `json:"message"`
+	Code    int    `json:"code"`
+	Data    struct {
+		Checks map[string]*healthCheckResult `json:"checks"`
+	} `json:"data"`
+}
+
+type healthCheckResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// Duration is the check execution time in milliseconds.
+	Duration float64 `json:"duration"`
+}
+
 // healthCheck returns a 200 OK response if the server is healthy.
 func (api *healthApi) healthCheck(c echo.Context) error {
 	if c.Request().Method == http.MethodHead {
@@ -41,3 +94,223 @@ func (api *healthApi) healthCheck(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, resp)
 }
+
+// live returns a 200 OK response as long as the server is able to
+// serve requests (it doesn't check any of the app dependencies).
+func (api *healthApi) live(c echo.Context) error {
+	if c.Request().Method == http.MethodHead {
+		return c.NoContent(http.StatusOK)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"code":    http.StatusOK,
+		"message": "API is live.",
+		"data":    map[string]any{},
+	})
+}
+
+// ready runs the registered dependency checks and returns 200 OK
+// if all of them succeeded, otherwise - 503 Service Unavailable.
+//
+// The checks error messages are returned only for admin requests.
+func (api *healthApi) ready(c echo.Context) error {
+	event := new(core.HealthReadyEvent)
+	event.HttpContext = c
+	event.Checks = api.defaultReadyChecks()
+
+	return api.app.OnHealthReadyRequest().Trigger(event, func(e *core.HealthReadyEvent) error {
+		if e.HttpContext.Response().Committed {
+			return nil
+		}
+
+		results := runHealthChecks(e.HttpContext.Request().Context(), e.Checks)
+
+		admin, _ := e.HttpContext.Get(ContextAdminKey).(*models.Admin)
+
+		resp := new(healthReadyResponse)
+		resp.Code = http.StatusOK
+		resp.Message = "API is ready."
+		resp.Data.Checks = results
+
+		for _, result := range results {
+			if result.Status != healthCheckStatusOk {
+				resp.Code = http.StatusServiceUnavailable
+				resp.Message = "API is not ready."
+			}
+
+			if admin == nil {
+				result.Error = ""
+			}
+		}
+
+		if e.HttpContext.Request().Method == http.MethodHead {
+			return e.HttpContext.NoContent(resp.Code)
+		}
+
+		return e.HttpContext.JSON(resp.Code, resp)
+	})
+}
+
+// defaultReadyChecks returns the default app readiness checks.
+func (api *healthApi) defaultReadyChecks() map[string]core.HealthCheckFunc {
+	checks := map[string]core.HealthCheckFunc{
+		"db": func(ctx context.Context) error {
+			return api.app.DB().DB().PingContext(ctx)
+		},
+		"logsDb": func(ctx context.Context) error {
+			return api.app.LogsDB().DB().PingContext(ctx)
+		},
+		"migrations": func(ctx context.Context) error {
+			if err := checkPendingMigrations(ctx, api.app.DB(), migrations.AppMigrations); err != nil {
+				return err
+			}
+
+			return checkPendingMigrations(ctx, api.app.LogsDB(), logs.LogsMigrations)
+		},
+		"storage": api.cachedCheck("storage", func(ctx context.Context) error {
+			fsys, err := api.app.NewFilesystem()
+			if err != nil {
+				return err
+			}
+			defer fsys.Close()
+
+			fsys.SetContext(ctx)
+
+			// any response (including "not found") means that the storage is reachable
+			_, err = fsys.Exists("pb_health_check")
+
+			return err
+		}),
+	}
+
+	if smtp := api.app.Settings().Smtp; smtp.Enabled {
+		checks["smtp"] = api.cachedCheck("smtp", func(ctx context.Context) error {
+			dialer := net.Dialer{}
+
+			conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(smtp.Host, strconv.Itoa(smtp.Port)))
+			if err != nil {
+				return err
+			}
+
+			return conn.Close()
+		})
+	}
+
+	return checks
+}
+
+// cachedCheck wraps the provided check and reuses its
+// last result for healthCheckCacheTTL.
+//
+// Concurrent calls wait for the running check instead of starting a new one.
+// The results of the canceled checks (eg. on aborted request) are not cached.
+func (api *healthApi) cachedCheck(name string, check core.HealthCheckFunc) core.HealthCheckFunc {
+	api.cacheMux.Lock()
+	entry, ok := api.cache[name]
+	if !ok {
+		entry = &healthCheckCacheEntry{}
+		api.cache[name] = entry
+	}
+	api.cacheMux.Unlock()
+
+	return func(ctx context.Context) error {
+		entry.mux.Lock()
+		defer entry.mux.Unlock()
+
+		if !entry.checkedAt.IsZero() && time.Since(entry.checkedAt) < healthCheckCacheTTL {
+			return entry.err
+		}
+
+		err := check(ctx)
+
+		if ctx.Err() == nil {
+			entry.err = err
+			entry.checkedAt = time.Now()
+		}
+
+		return err
+	}
+}
+
+// checkPendingMigrations returns an error if the provided
+// db has any unapplied migrations from the migrations list.
+func checkPendingMigrations(ctx context.Context, db *dbx.DB, migrationsList migrate.MigrationsList) error {
+	pending, err := migrate.PendingMigrations(ctx, db, migrationsList)
+	if err != nil {
+		return err
+	}
+
+	if len(pending) > 0 {
+		return fmt.Errorf("%d pending migration(s), eg. %s", len(pending), pending[0])
+	}
+
+	return nil
+}
+
+// runHealthChecks concurrently executes the provided checks
+// (each one limited to healthCheckTimeout).
+func runHealthChecks(ctx context.Context, checks map[string]core.HealthCheckFunc) map[string]*healthCheckResult {
+	results := make(map[string]*healthCheckResult, len(checks))
+
+	var wg sync.WaitGroup
+
+	for name, check := range checks {
+		if check == nil {
+			continue
+		}
+
+		result := &healthCheckResult{}
+		results[name] = result
+
+		wg.Add(1)
+
+		go func(check core.HealthCheckFunc, result *healthCheckResult) {
+			defer wg.Done()
+
+			start := time.Now()
+
+			err := runHealthCheck(ctx, check)
+
+			result.Duration = float64(time.Since(start).Microseconds()) / 1000
+			if err != nil {
+				result.Status = healthCheckStatusError
+				result.Error = err.Error()
+			} else {
+				result.Status = healthCheckStatusOk
+			}
+		}(check, result)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// runHealthCheck executes a single check and waits for its
+// completion or for the healthCheckTimeout to expire (whichever is first).
+func runHealthCheck(ctx context.Context, check core.HealthCheckFunc) error {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("check panic: %v", r)
+			}
+		}()
+
+		done <- check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return errors.New("check timeout")
+		}
+		return ctx.Err()
+	}
+}
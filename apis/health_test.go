@@ -1,13 +1,22 @@
 package apis_test
 
 import (
+	"context"
+	"errors"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 
+	"github.com/AlperRehaYAZGAN/postgresbase/core"
 	"github.com/AlperRehaYAZGAN/postgresbase/tests"
+	"github.com/labstack/echo/v5"
 )
 
 func TestHealthAPI(t *testing.T) {
+	var smtpConnections atomic.Int32
+
 	t.Parallel()
 
 	scenarios := []tests.ApiScenario{
@@ -28,6 +37,157 @@ func TestHealthAPI(t *testing.T) {
 				`"canBackup":true`,
 			},
 		},
+		{
+			Name:           "HEAD live status",
+			Method:         http.MethodHead,
+			Url:            "/api/health/live",
+			ExpectedStatus: 200,
+		},
+		{
+			Name:           "GET live status",
+			Method:         http.MethodGet,
+			Url:            "/api/health/live",
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"code":200`,
+				`"data":{}`,
+			},
+		},
+		{
+			Name:           "HEAD ready status",
+			Method:         http.MethodHead,
+			Url:            "/api/health/ready",
+			ExpectedStatus: 200,
+			ExpectedEvents: map[string]int{"OnHealthReadyRequest": 1},
+		},
+		{
+			Name:           "GET ready status with the default checks",
+			Method:         http.MethodGet,
+			Url:            "/api/health/ready",
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"code":200`,
+				`"db":{"status":"ok"`,
+				`"logsDb":{"status":"ok"`,
+				`"migrations":{"status":"ok"`,
+				`"storage":{"status":"ok"`,
+			},
+			NotExpectedContent: []string{
+				`"smtp"`,
+			},
+			ExpectedEvents: map[string]int{"OnHealthReadyRequest": 1},
+		},
+		{
+			Name:   "GET ready status with unreachable smtp server",
+			Method: http.MethodGet,
+			Url:    "/api/health/ready",
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				// reserve a free port and release it immediately
+				listener, err := net.Listen("tcp", "127.0.0.1:0")
+				if err != nil {
+					t.Fatal(err)
+				}
+				addr := listener.Addr().(*net.TCPAddr)
+				listener.Close()
+
+				app.Settings().Smtp.Enabled = true
+				app.Settings().Smtp.Host = addr.IP.String()
+				app.Settings().Smtp.Port = addr.Port
+			},
+			ExpectedStatus: 503,
+			ExpectedContent: []string{
+				`"code":503`,
+				`"db":{"status":"ok"`,
+				`"smtp":{"status":"error"`,
+			},
+			// the check errors are visible only for admins
+			NotExpectedContent: []string{
+				`"error":`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnHealthReadyRequest": 1,
+				// regular response, not an api error
+				"OnBeforeApiError": 0,
+				"OnAfterApiError":  0,
+			},
+		},
+		{
+			Name:   "GET ready status with cached smtp check result",
+			Method: http.MethodGet,
+			Url:    "/api/health/ready",
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				listener, err := net.Listen("tcp", "127.0.0.1:0")
+				if err != nil {
+					t.Fatal(err)
+				}
+				t.Cleanup(func() { listener.Close() })
+
+				go func() {
+					for {
+						conn, err := listener.Accept()
+						if err != nil {
+							return
+						}
+						smtpConnections.Add(1)
+						conn.Close()
+					}
+				}()
+
+				addr := listener.Addr().(*net.TCPAddr)
+
+				app.Settings().Smtp.Enabled = true
+				app.Settings().Smtp.Host = addr.IP.String()
+				app.Settings().Smtp.Port = addr.Port
+
+				// warm up the checks cache
+				rec := httptest.NewRecorder()
+				e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/health/ready", nil))
+				if rec.Code != http.StatusOK {
+					t.Fatalf("Expected 200 warm up response, got %d", rec.Code)
+				}
+			},
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, res *http.Response) {
+				if total := smtpConnections.Load(); total != 1 {
+					t.Fatalf("Expected 1 smtp connection, got %d", total)
+				}
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"code":200`,
+				`"smtp":{"status":"ok"`,
+			},
+			ExpectedEvents: map[string]int{"OnHealthReadyRequest": 2},
+		},
+		{
+			Name:   "GET ready status with custom hook checks",
+			Method: http.MethodGet,
+			Url:    "/api/health/ready",
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				app.OnHealthReadyRequest().Add(func(e *core.HealthReadyEvent) error {
+					delete(e.Checks, "storage")
+
+					e.Checks["custom"] = func(ctx context.Context) error {
+						return errors.New("custom error")
+					}
+
+					return nil
+				})
+			},
+			ExpectedStatus: 503,
+			ExpectedContent: []string{
+				`"code":503`,
+				`"db":{"status":"ok"`,
+				`"custom":{"status":"error"`,
+			},
+			NotExpectedContent: []string{
+				`"storage"`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnHealthReadyRequest": 1,
+				"OnBeforeApiError":     0,
+				"OnAfterApiError":      0,
+			},
+		},
 	}
 
 	for _, scenario := range scenarios {
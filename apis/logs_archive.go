@@ -0,0 +1,95 @@
+package apis
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/core"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/types"
+	"github.com/labstack/echo/v5"
+)
+
+// bindLogsArchiveApi registers the logs archive api endpoints.
+func bindLogsArchiveApi(app core.App, rg *echo.Group) {
+	api := logsArchiveApi{app: app}
+
+	subGroup := rg.Group("/logs/archives", ActivityLogger(app))
+	subGroup.GET("", api.list, RequireAdminAuth())
+	subGroup.GET("/:key", api.download)
+}
+
+type logsArchiveApi struct {
+	app core.App
+}
+
+func (api *logsArchiveApi) list(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	fsys, err := api.app.NewBackupsFilesystem()
+	if err != nil {
+		return NewBadRequestError("Failed to load backups filesystem.", err)
+	}
+	defer fsys.Close()
+
+	fsys.SetContext(ctx)
+
+	files, err := fsys.List(core.LogsArchivePrefix)
+	if err != nil {
+		return NewBadRequestError("Failed to retrieve logs archive items. Raw error: \n"+err.Error(), nil)
+	}
+
+	result := make([]models.BackupFileInfo, 0, len(files))
+
+	for _, obj := range files {
+		if !core.IsLogsArchiveKey(obj.Key) {
+			continue
+		}
+
+		modified, _ := types.ParseDateTime(obj.ModTime)
+
+		result = append(result, models.BackupFileInfo{
+			Key:      obj.Key,
+			Size:     obj.Size,
+			Modified: modified,
+		})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func (api *logsArchiveApi) download(c echo.Context) error {
+	fileToken := c.QueryParam("token")
+
+	_, err := api.app.Dao().FindAdminByToken(
+		fileToken,
+		api.app.Settings().AdminFileToken.Secret,
+	)
+	if err != nil {
+		return NewForbiddenError("Insufficient permissions to access the resource.", err)
+	}
+
+	key := c.PathParam("key")
+	if !core.IsLogsArchiveKey(key) {
+		return NewNotFoundError("", nil)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	fsys, err := api.app.NewBackupsFilesystem()
+	if err != nil {
+		return NewBadRequestError("Failed to load backups filesystem.", err)
+	}
+	defer fsys.Close()
+
+	fsys.SetContext(ctx)
+
+	if exists, _ := fsys.Exists(key); !exists {
+		return NewNotFoundError("", nil)
+	}
+
+	return fsys.Serve(c.Response(), c.Request(), key, key)
+}
@@ -0,0 +1,225 @@
+package apis
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/daos"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/search"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/dbx"
+)
+
+const (
+	logsExportFormatNDJSON = "ndjson"
+	logsExportFormatCSV    = "csv"
+)
+
+// logsExportFlushEvery is the number of exported logs after which
+// the buffered response data is flushed to the client.
+const logsExportFlushEvery = 500
+
+// logsStatsMaxGroupBy is the max number of the stats groupBy keys.
+const logsStatsMaxGroupBy = 5
+
+var logsStatsGroupByRegex = regexp.MustCompile(`^[\w\.\:]*\w+$`)
+
+// export streams the filtered logs as NDJSON (default) or CSV.
+//
+// Supported query parameters:
+//   - filter - the same filter format as in the logs list
+//   - sort   - the same sort format as in the logs list (default to "created")
+//   - format - "ndjson" or "csv"
+func (api *logsApi) export(c echo.Context) error {
+	format := c.QueryParam("format")
+	if format == "" {
+		format = logsExportFormatNDJSON
+	}
+	if format != logsExportFormatNDJSON && format != logsExportFormatCSV {
+		return NewBadRequestError("Invalid export format.", validation.Errors{
+			"format": validation.NewError("validation_invalid_format", "Must be ndjson or csv."),
+		})
+	}
+
+	fieldResolver := search.NewSimpleFieldResolver(logFilterFields...)
+
+	query := api.app.LogsDao().LogQuery()
+
+	expr, err := logsFilterExpr(c, fieldResolver)
+	if err != nil {
+		return NewBadRequestError("Invalid filter format.", err)
+	}
+	if expr != nil {
+		query.AndWhere(expr)
+	}
+
+	sortFields := []search.SortField{{Name: "created", Direction: search.SortAsc}}
+	if sort := c.QueryParam(search.SortQueryParam); sort != "" {
+		sortFields = search.ParseSortFromString(sort)
+	}
+	for _, sortField := range sortFields {
+		sortExpr, err := sortField.BuildExpr(fieldResolver)
+		if err != nil {
+			return NewBadRequestError("Invalid sort format.", err)
+		}
+		if sortExpr != "" {
+			query.AndOrderBy(sortExpr)
+		}
+	}
+
+	rows, err := query.WithContext(c.Request().Context()).Rows()
+	if err != nil {
+		return NewBadRequestError("Failed to export the logs.", err)
+	}
+	defer rows.Close()
+
+	filename := "logs_export_" + time.Now().UTC().Format("20060102150405") + "." + format
+
+	res := c.Response()
+	if format == logsExportFormatCSV {
+		res.Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	} else {
+		res.Header().Set(echo.HeaderContentType, "application/x-ndjson")
+	}
+	res.Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+	res.WriteHeader(http.StatusOK)
+
+	var writeLog func(log *models.Log) error
+	var flush func() error
+
+	if format == logsExportFormatCSV {
+		w := csv.NewWriter(res)
+		if err := w.Write([]string{"id", "created", "level", "message", "data"}); err != nil {
+			return err
+		}
+
+		writeLog = func(log *models.Log) error {
+			data, err := json.Marshal(log.Data)
+			if err != nil {
+				return err
+			}
+
+			return w.Write([]string{
+				log.Id,
+				log.Created.String(),
+				strconv.Itoa(log.Level),
+				log.Message,
+				string(data),
+			})
+		}
+
+		flush = func() error {
+			w.Flush()
+			res.Flush()
+			return w.Error()
+		}
+	} else {
+		encoder := json.NewEncoder(res)
+
+		writeLog = func(log *models.Log) error {
+			return encoder.Encode(log)
+		}
+
+		flush = func() error {
+			res.Flush()
+			return nil
+		}
+	}
+
+	// note: the response is already committed so any further error
+	// is returned only to be logged (the export is truncated)
+	var total int
+	for rows.Next() {
+		log := &models.Log{}
+		if err := rows.ScanStruct(log); err != nil {
+			return err
+		}
+
+		if err := writeLog(log); err != nil {
+			return err
+		}
+
+		total++
+		if total%logsExportFlushEvery == 0 {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return flush()
+}
+
+// logsFilterExpr builds the logs filter expression from the request "filter" query param
+// (returns nil expression if no filter is set).
+func logsFilterExpr(c echo.Context, fieldResolver search.FieldResolver) (dbx.Expression, error) {
+	filter := c.QueryParam(search.FilterQueryParam)
+	if filter == "" {
+		return nil, nil
+	}
+
+	return search.FilterData(filter).BuildExpr(fieldResolver)
+}
+
+// logsStatsOptions loads the logs stats aggregation options from the request query params:
+//   - groupBy  - comma separated log data keys (eg. "status,method")
+//   - interval - the time bucket duration (eg. "15m", "24h"; default to 1h)
+//   - urlDepth - the number of path segments to keep when grouping by "url"
+func logsStatsOptions(c echo.Context) (daos.LogsStatsOptions, error) {
+	options := daos.LogsStatsOptions{}
+	errs := validation.Errors{}
+
+	if raw := c.QueryParam("groupBy"); raw != "" {
+		for _, key := range strings.Split(raw, ",") {
+			key = strings.TrimPrefix(strings.TrimSpace(key), "data.")
+			if !logsStatsGroupByRegex.MatchString(key) {
+				errs["groupBy"] = validation.NewError("validation_invalid_group_by", "Invalid group by key "+key+".")
+				break
+			}
+			options.GroupBy = append(options.GroupBy, key)
+		}
+
+		if len(options.GroupBy) > logsStatsMaxGroupBy {
+			errs["groupBy"] = validation.NewError(
+				"validation_too_many_group_by",
+				"Up to "+strconv.Itoa(logsStatsMaxGroupBy)+" group by keys are allowed.",
+			)
+		}
+	}
+
+	if raw := c.QueryParam("interval"); raw != "" {
+		interval, err := time.ParseDuration(raw)
+		if err != nil || interval < time.Minute || interval > 31*24*time.Hour {
+			errs["interval"] = validation.NewError(
+				"validation_invalid_interval",
+				"Must be a valid duration between 1m and 744h.",
+			)
+		}
+		options.Interval = interval
+	}
+
+	if raw := c.QueryParam("urlDepth"); raw != "" {
+		depth, err := strconv.Atoi(raw)
+		if err != nil || depth < 0 {
+			errs["urlDepth"] = validation.NewError("validation_invalid_url_depth", "Must be a non-negative integer.")
+		}
+		options.UrlDepth = depth
+	}
+
+	if len(errs) > 0 {
+		return options, errs
+	}
+
+	return options, nil
+}
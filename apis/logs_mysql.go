@@ -8,7 +8,6 @@ import (
 	"github.com/AlperRehaYAZGAN/postgresbase/models"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/search"
 	"github.com/labstack/echo/v5"
-	"github.com/pocketbase/dbx"
 )
 
 // bindLogsApi registers the request logs api endpoints.
@@ -18,6 +17,7 @@ func bindLogsApi(app core.App, rg *echo.Group) {
 	subGroup := rg.Group("/logs", RequireAdminAuth())
 	subGroup.GET("", api.list)
 	subGroup.GET("/stats", api.stats)
+	subGroup.GET("/export", api.export)
 	subGroup.GET("/:id", api.view)
 }
 
@@ -48,18 +48,17 @@ func (api *logsApi) list(c echo.Context) error {
 func (api *logsApi) stats(c echo.Context) error {
 	fieldResolver := search.NewSimpleFieldResolver(logFilterFields...)
 
-	filter := c.QueryParam(search.FilterQueryParam)
+	expr, err := logsFilterExpr(c, fieldResolver)
+	if err != nil {
+		return NewBadRequestError("Invalid filter format.", err)
+	}
 
-	var expr dbx.Expression
-	if filter != "" {
-		var err error
-		expr, err = search.FilterData(filter).BuildExpr(fieldResolver)
-		if err != nil {
-			return NewBadRequestError("Invalid filter format.", err)
-		}
+	options, err := logsStatsOptions(c)
+	if err != nil {
+		return NewBadRequestError("Invalid stats options.", err)
 	}
 
-	stats, err := api.app.LogsDao().LogsStats(expr)
+	stats, err := api.app.LogsDao().LogsStatsWithOptions(expr, options)
 	if err != nil {
 		return NewBadRequestError("Failed to generate logs stats.", err)
 	}
@@ -0,0 +1,85 @@
+package apis
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/core"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/labstack/echo/v5"
+)
+
+// bindMetricsApi registers the Prometheus metrics api endpoint.
+func bindMetricsApi(app core.App, rg *echo.Group) {
+	api := metricsApi{app: app}
+
+	rg.GET("/metrics", api.metrics, api.requireMetricsAccess())
+}
+
+type metricsApi struct {
+	app core.App
+}
+
+// requireMetricsAccess checks whether the metrics endpoint is enabled
+// and that the request is authorized either as admin or with the
+// configured metrics bearer token.
+func (api *metricsApi) requireMetricsAccess() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			config := api.app.Settings().Metrics
+
+			if !config.Enabled {
+				return NewNotFoundError("", nil)
+			}
+
+			if admin, _ := c.Get(ContextAdminKey).(*models.Admin); admin != nil {
+				return next(c)
+			}
+
+			token := strings.TrimPrefix(c.Request().Header.Get("Authorization"), "Bearer ")
+
+			if config.Token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(config.Token)) == 1 {
+				return next(c)
+			}
+
+			return NewUnauthorizedError("The request requires valid admin authorization or metrics token to be set.", nil)
+		}
+	}
+}
+
+// metrics writes the app metrics in the Prometheus text exposition format.
+func (api *metricsApi) metrics(c echo.Context) error {
+	c.Response().Header().Set(echo.HeaderContentType, "text/plain; version=0.0.4; charset=utf-8")
+	c.Response().WriteHeader(http.StatusOK)
+
+	return api.app.Metrics().Write(c.Response())
+}
+
+// recordRequestMetrics collects the request counter and latency metrics
+// partitioned by the matched route path (to avoid high cardinality labels).
+func recordRequestMetrics(app core.App, c echo.Context, status int, started time.Time) {
+	route := c.Path()
+	if route == "" {
+		route = "unknown"
+	}
+
+	method := strings.ToUpper(c.Request().Method)
+
+	app.Metrics().CounterVec(
+		"pb_http_requests_total",
+		"The total number of HTTP requests.",
+		"method", "route", "status",
+	).Inc(method, route, strconv.Itoa(status))
+
+	if !started.IsZero() {
+		app.Metrics().HistogramVec(
+			"pb_http_request_duration_seconds",
+			"The HTTP requests duration.",
+			nil,
+			"method", "route",
+		).Observe(time.Since(started).Seconds(), method, route)
+	}
+}
@@ -1,6 +1,7 @@
 package apis
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
@@ -10,13 +11,17 @@ import (
 	"time"
 
 	"github.com/AlperRehaYAZGAN/postgresbase/core"
+	"github.com/AlperRehaYAZGAN/postgresbase/daos"
 	"github.com/AlperRehaYAZGAN/postgresbase/models"
 	"github.com/AlperRehaYAZGAN/postgresbase/tokens"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/list"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/routine"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/security"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/tracing"
 	"github.com/labstack/echo/v5"
 	"github.com/spf13/cast"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Common request context keys used by the middlewares and api handlers.
@@ -25,8 +30,66 @@ const (
 	ContextAuthRecordKey string = "authRecord"
 	ContextCollectionKey string = "collection"
 	ContextExecStartKey  string = "execStart"
+	ContextFileScansKey  string = "fileScans"
+	ContextRequestIdKey  string = "requestId"
+	ContextTenantKey     string = "tenant"
 )
 
+// requestIdMaxLength is the max allowed length of a client provided request id.
+const requestIdMaxLength = 128
+
+// LoadRequestId middleware assigns a correlation id to the current request.
+//
+// It honors a valid client provided "X-Request-Id" header or generates a new one.
+// The request id is returned in the "X-Request-Id" response header and is stored in
+// the echo context (see [ContextRequestIdKey]) and in the request context
+// (see [core.RequestIdFromContext]), so that every log written with the request context
+// (eg. app.Logger().InfoContext(c.Request().Context(), ...)) or with the request-scoped
+// logger (eg. app.ContextLogger(c.Request().Context()).Info(...)) is correlated with the request.
+//
+// This middleware is expected to be already registered by default for all routes.
+func LoadRequestId() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			requestId := c.Request().Header.Get(echo.HeaderXRequestID)
+			if !isValidRequestId(requestId) {
+				requestId = security.PseudorandomString(20)
+			}
+
+			c.Set(ContextRequestIdKey, requestId)
+			c.SetRequest(c.Request().WithContext(core.ContextWithRequestId(c.Request().Context(), requestId)))
+			c.Response().Header().Set(echo.HeaderXRequestID, requestId)
+
+			return next(c)
+		}
+	}
+}
+
+// RequestId returns the correlation id of the current request
+// (or empty string if [LoadRequestId] is not registered).
+func RequestId(c echo.Context) string {
+	requestId, _ := c.Get(ContextRequestIdKey).(string)
+
+	return requestId
+}
+
+// isValidRequestId reports whether the client provided
+// request id is non-empty, short and with only safe characters.
+func isValidRequestId(requestId string) bool {
+	if requestId == "" || len(requestId) > requestIdMaxLength {
+		return false
+	}
+
+	for _, r := range requestId {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') &&
+			r != '-' && r != '_' && r != '.' && r != ':' {
+			return false
+		}
+	}
+
+	return true
+}
+
 // RequireGuestOnly middleware requires a request to NOT have a valid
 // Authorization header.
 //
@@ -239,10 +302,25 @@ func LoadAuthContext(app core.App) echo.MiddlewareFunc {
 					c.Set(ContextAdminKey, admin)
 				}
 			case tokens.TypeAuthRecord:
-				record, err := app.Dao().FindAuthRecordByToken(
-					token,
-					app.Settings().RecordAuthToken.Secret,
-				)
+				dao := app.Dao()
+
+				// the tenant of a multi-tenant collection record is not known yet
+				// and therefore the record is loaded with disabled tenant isolation
+				// (the token is still verified with the record token key)
+				collection, _ := core.FindCachedCollectionByNameOrId(app, cast.ToString(claims["collectionId"]))
+				if collection != nil && collection.IsMultiTenant() {
+					dao = dao.WithoutTenantIsolation()
+				}
+
+				var record *models.Record
+				err := dao.RunInTenantScope(func(scopedDao *daos.Dao) error {
+					var findErr error
+					record, findErr = scopedDao.FindAuthRecordByToken(
+						token,
+						app.Settings().RecordAuthToken.Secret,
+					)
+					return findErr
+				})
 				if err == nil && record != nil {
 					c.Set(ContextAuthRecordKey, record)
 				}
@@ -253,6 +331,86 @@ func LoadAuthContext(app core.App) echo.MiddlewareFunc {
 	}
 }
 
+// LoadTenantContext middleware resolves the request tenant and
+// loads its identifier into the request's context.
+//
+// For guests and admins the tenant is resolved from the configured
+// tenancy header or the leftmost request host subdomain label.
+//
+// For auth records the tenant is always the tenant column value of the
+// authenticated multi-tenant collection record. Requests with auth record
+// from one tenant and explicitly requested another tenant are rejected,
+// as well as requests with tenant header from auth records without tenant
+// (eg. records from a regular collection).
+//
+// This middleware is expected to be already registered by default for all routes
+// (after [LoadAuthContext]).
+func LoadTenantContext(app core.App) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			tenancy := app.Settings().Tenancy
+
+			var header string
+			if tenancy.Header != "" {
+				header = strings.TrimSpace(c.Request().Header.Get(tenancy.Header))
+			}
+
+			tenant := header
+			if tenant == "" && tenancy.Subdomain {
+				tenant = hostSubdomain(c.Request().Host)
+			}
+
+			record, _ := c.Get(ContextAuthRecordKey).(*models.Record)
+			if record != nil {
+				var recordTenant string
+
+				if record.Collection().IsMultiTenant() {
+					var err error
+					recordTenant, err = app.Dao().FindRecordTenant(record)
+					if err != nil {
+						return NewForbiddenError("Failed to resolve the auth record tenant.", err)
+					}
+				}
+
+				if recordTenant == "" && header != "" {
+					return NewForbiddenError("The authorized record is not allowed to select a tenant.", nil)
+				}
+
+				if recordTenant != "" && tenant != "" && tenant != recordTenant {
+					return NewForbiddenError("The authorized record doesn't belong to the requested tenant.", nil)
+				}
+
+				tenant = recordTenant
+			}
+
+			c.Set(ContextTenantKey, tenant)
+
+			return next(c)
+		}
+	}
+}
+
+// hostSubdomain returns the leftmost label of the provided host
+// if it has at least 3 labels (eg. "acme" for "acme.example.com:8090").
+//
+// Returns empty string for IP hosts.
+func hostSubdomain(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	if net.ParseIP(host) != nil {
+		return ""
+	}
+
+	parts := strings.Split(host, ".")
+	if len(parts) < 3 {
+		return ""
+	}
+
+	return parts[0]
+}
+
 // LoadCollectionContext middleware finds the collection with related
 // path identifier and loads it into the request context.
 //
@@ -278,6 +436,62 @@ func LoadCollectionContext(app core.App, optCollectionTypes ...string) echo.Midd
 	}
 }
 
+// TraceRequest middleware starts a new server span for each request
+// (continuing the trace of the W3C "traceparent" request header, if any)
+// and associates it with the request context.
+//
+// It is no-op if the app tracing is disabled.
+func TraceRequest(app core.App) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			tracer := app.Tracer()
+			if tracer == nil {
+				return next(c)
+			}
+
+			r := c.Request()
+			method := strings.ToUpper(r.Method)
+
+			route := c.Path()
+			if route == "" {
+				route = r.URL.Path
+			}
+
+			ctx, span := tracer.Start(
+				tracing.Extract(r.Context(), r.Header),
+				method+" "+route,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("http.method", method),
+					attribute.String("http.route", route),
+					attribute.String("http.target", r.URL.RequestURI()),
+				),
+			)
+			defer span.End()
+
+			c.SetRequest(r.WithContext(ctx))
+
+			err := next(c)
+
+			status := c.Response().Status
+			if err != nil {
+				tracing.RecordError(span, err)
+
+				var apiErr *ApiError
+				var httpErr *echo.HTTPError
+				if errors.As(err, &apiErr) {
+					status = apiErr.Code
+				} else if errors.As(err, &httpErr) {
+					status = httpErr.Code
+				}
+			}
+			span.SetAttributes(attribute.Int("http.status_code", status))
+
+			return err
+		}
+	}
+}
+
 // ActivityLogger middleware takes care to save the request information
 // into the logs database.
 //
@@ -298,6 +512,15 @@ func ActivityLogger(app core.App) echo.MiddlewareFunc {
 }
 
 func logRequest(app core.App, c echo.Context, err *ApiError) {
+	started := cast.ToTime(c.Get(ContextExecStartKey))
+
+	status := c.Response().Status
+	if err != nil {
+		status = err.Code
+	}
+
+	recordRequestMetrics(app, c, status, started)
+
 	// no logs retention
 	if app.Settings().Logs.MaxDays == 0 {
 		return
@@ -307,20 +530,16 @@ func logRequest(app core.App, c echo.Context, err *ApiError) {
 
 	attrs = append(attrs, slog.String("type", "request"))
 
-	started := cast.ToTime(c.Get(ContextExecStartKey))
 	if !started.IsZero() {
 		attrs = append(attrs, slog.Float64("execTime", float64(time.Since(started))/float64(time.Millisecond)))
 	}
 
 	httpRequest := c.Request()
-	httpResponse := c.Response()
 	method := strings.ToUpper(httpRequest.Method)
-	status := httpResponse.Status
 	requestUri := httpRequest.URL.RequestURI()
 
 	// parse the request error
 	if err != nil {
-		status = err.Code
 		attrs = append(
 			attrs,
 			slog.String("error", err.Message),
@@ -337,6 +556,7 @@ func logRequest(app core.App, c echo.Context, err *ApiError) {
 
 	attrs = append(
 		attrs,
+		slog.String("requestId", RequestId(c)),
 		slog.String("url", requestUri),
 		slog.String("method", method),
 		slog.Int("status", status),
@@ -345,6 +565,10 @@ func logRequest(app core.App, c echo.Context, err *ApiError) {
 		slog.String("userAgent", httpRequest.UserAgent()),
 	)
 
+	if scans := c.Get(ContextFileScansKey); scans != nil {
+		attrs = append(attrs, slog.Any("fileScans", scans))
+	}
+
 	if app.Settings().Logs.LogIp {
 		ip, _, _ := net.SplitHostPort(httpRequest.RemoteAddr)
 		attrs = append(
@@ -1,12 +1,21 @@
 package apis_test
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/AlperRehaYAZGAN/postgresbase/apis"
+	"github.com/AlperRehaYAZGAN/postgresbase/core"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
 	"github.com/AlperRehaYAZGAN/postgresbase/tests"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/tracing"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/types"
 	"github.com/labstack/echo/v5"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestRequireGuestOnly(t *testing.T) {
@@ -1012,3 +1021,405 @@ func TestLoadCollectionContext(t *testing.T) {
 		scenario.Test(t)
 	}
 }
+
+func TestLoadTenantContext(t *testing.T) {
+	t.Parallel()
+
+	addTenantRoute := func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+		e.AddRoute(echo.Route{
+			Method: http.MethodGet,
+			Path:   "/my/tenant",
+			Handler: func(c echo.Context) error {
+				tenant, _ := c.Get(apis.ContextTenantKey).(string)
+				return c.String(200, "tenant:["+tenant+"]")
+			},
+		})
+	}
+
+	// the auth record is loaded as route middleware because
+	// the tenant context is resolved in a pre middleware
+	addAuthTenantRoute := func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+		e.AddRoute(echo.Route{
+			Method: http.MethodGet,
+			Path:   "/my/tenant",
+			Handler: func(c echo.Context) error {
+				tenant, _ := c.Get(apis.ContextTenantKey).(string)
+				return c.String(200, "tenant:["+tenant+"]")
+			},
+			Middlewares: []echo.MiddlewareFunc{
+				mockRecordAuth(app, "users", "test@example.com"),
+				apis.LoadTenantContext(app),
+			},
+		})
+	}
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "without tenant",
+			Method:          http.MethodGet,
+			Url:             "/my/tenant",
+			BeforeTestFunc:  addTenantRoute,
+			ExpectedStatus:  200,
+			ExpectedContent: []string{"tenant:[]"},
+		},
+		{
+			Name:   "default tenancy header",
+			Method: http.MethodGet,
+			Url:    "/my/tenant",
+			RequestHeaders: map[string]string{
+				"X-Tenant": "acme",
+			},
+			BeforeTestFunc:  addTenantRoute,
+			ExpectedStatus:  200,
+			ExpectedContent: []string{"tenant:[acme]"},
+		},
+		{
+			Name:   "custom tenancy header",
+			Method: http.MethodGet,
+			Url:    "/my/tenant",
+			RequestHeaders: map[string]string{
+				"X-Tenant": "acme",
+				"X-Org":    "other",
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				app.Settings().Tenancy.Header = "X-Org"
+				addTenantRoute(t, app, e)
+			},
+			ExpectedStatus:  200,
+			ExpectedContent: []string{"tenant:[other]"},
+		},
+		{
+			Name:   "auth record without tenant + tenant header",
+			Method: http.MethodGet,
+			Url:    "/my/tenant",
+			RequestHeaders: map[string]string{
+				"X-Tenant": "acme",
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				addAuthTenantRoute(t, app, e)
+			},
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "auth record without tenant + subdomain",
+			Method: http.MethodGet,
+			Url:    "http://acme.example.com/my/tenant",
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				app.Settings().Tenancy.Subdomain = true
+				addAuthTenantRoute(t, app, e)
+			},
+			ExpectedStatus:  200,
+			ExpectedContent: []string{"tenant:[]"},
+		},
+		{
+			Name:            "subdomain with disabled subdomain resolution",
+			Method:          http.MethodGet,
+			Url:             "http://acme.example.com/my/tenant",
+			BeforeTestFunc:  addTenantRoute,
+			ExpectedStatus:  200,
+			ExpectedContent: []string{"tenant:[]"},
+		},
+		{
+			Name:   "subdomain with enabled subdomain resolution",
+			Method: http.MethodGet,
+			Url:    "http://acme.example.com:8090/my/tenant",
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				app.Settings().Tenancy.Subdomain = true
+				addTenantRoute(t, app, e)
+			},
+			ExpectedStatus:  200,
+			ExpectedContent: []string{"tenant:[acme]"},
+		},
+		{
+			Name:   "header with priority over the subdomain",
+			Method: http.MethodGet,
+			Url:    "http://acme.example.com/my/tenant",
+			RequestHeaders: map[string]string{
+				"X-Tenant": "other",
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				app.Settings().Tenancy.Subdomain = true
+				addTenantRoute(t, app, e)
+			},
+			ExpectedStatus:  200,
+			ExpectedContent: []string{"tenant:[other]"},
+		},
+		{
+			Name:   "subdomain of an ip host",
+			Method: http.MethodGet,
+			Url:    "http://127.0.0.1:8090/my/tenant",
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				app.Settings().Tenancy.Subdomain = true
+				addTenantRoute(t, app, e)
+			},
+			ExpectedStatus:  200,
+			ExpectedContent: []string{"tenant:[]"},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestRequestTenantDao(t *testing.T) {
+	t.Parallel()
+
+	addTenantRoute := func(multiTenant bool, middlewares ...echo.MiddlewareFunc) func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+		return func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+			collection, err := app.Dao().FindCollectionByNameOrId("demo1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			collection.Options = types.JsonMap{"multiTenant": multiTenant}
+			if err := app.Dao().Save(collection); err != nil {
+				t.Fatal(err)
+			}
+
+			e.AddRoute(echo.Route{
+				Method: http.MethodGet,
+				Path:   "/my/:collection",
+				Handler: func(c echo.Context) error {
+					collection, _ := c.Get(apis.ContextCollectionKey).(*models.Collection)
+
+					dao, err := apis.RequestTenantDao(c, app, collection)
+					if err != nil {
+						return err
+					}
+
+					return c.String(200, fmt.Sprintf("scope:%v", dao.HasTenantScope()))
+				},
+				Middlewares: append(middlewares, apis.LoadCollectionContext(app)),
+			})
+		}
+	}
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "guest without tenant + regular collection",
+			Method:          http.MethodGet,
+			Url:             "/my/demo1",
+			BeforeTestFunc:  addTenantRoute(false),
+			ExpectedStatus:  200,
+			ExpectedContent: []string{"scope:false"},
+			ExpectedEvents:  map[string]int{"OnModelBeforeUpdate": 1, "OnModelAfterUpdate": 1},
+		},
+		{
+			Name:   "guest with tenant + regular collection",
+			Method: http.MethodGet,
+			Url:    "/my/demo1",
+			RequestHeaders: map[string]string{
+				"X-Tenant": "acme",
+			},
+			BeforeTestFunc:  addTenantRoute(false),
+			ExpectedStatus:  200,
+			ExpectedContent: []string{"scope:true"},
+			ExpectedEvents:  map[string]int{"OnModelBeforeUpdate": 1, "OnModelAfterUpdate": 1},
+		},
+		{
+			Name:            "guest without tenant + multi-tenant collection",
+			Method:          http.MethodGet,
+			Url:             "/my/demo1",
+			BeforeTestFunc:  addTenantRoute(true),
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`, "Missing tenant."},
+			ExpectedEvents:  map[string]int{"OnModelBeforeUpdate": 1, "OnModelAfterUpdate": 1},
+		},
+		{
+			Name:   "guest with tenant + multi-tenant collection",
+			Method: http.MethodGet,
+			Url:    "/my/demo1",
+			RequestHeaders: map[string]string{
+				"X-Tenant": "acme",
+			},
+			BeforeTestFunc:  addTenantRoute(true),
+			ExpectedStatus:  200,
+			ExpectedContent: []string{"scope:true"},
+			ExpectedEvents:  map[string]int{"OnModelBeforeUpdate": 1, "OnModelAfterUpdate": 1},
+		},
+		{
+			Name:   "admin without tenant + multi-tenant collection",
+			Method: http.MethodGet,
+			Url:    "/my/demo1",
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				addTenantRoute(true, mockAdminAuth(app))(t, app, e)
+			},
+			ExpectedStatus:  200,
+			ExpectedContent: []string{"scope:true"},
+			ExpectedEvents:  map[string]int{"OnModelBeforeUpdate": 1, "OnModelAfterUpdate": 1},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestTraceRequest(t *testing.T) {
+	t.Parallel()
+
+	exporter := tracetest.NewInMemoryExporter()
+
+	scenario := tests.ApiScenario{
+		Method: http.MethodGet,
+		Url:    "/api/collections/demo2/records?filter=title%3D%27test1%27",
+		RequestHeaders: map[string]string{
+			"traceparent": "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		},
+		BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+			app.SetTracer(tracing.NewTracer("test", exporter, 1))
+		},
+		ExpectedStatus: 200,
+		ExpectedContent: []string{
+			`"totalItems":1`,
+		},
+		ExpectedEvents: map[string]int{"OnRecordsListRequest": 1},
+		AfterTestFunc: func(t *testing.T, app *tests.TestApp, res *http.Response) {
+			if err := app.Tracer().ForceFlush(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			spans := map[string]tracetest.SpanStub{}
+			dbSpans := []tracetest.SpanStub{}
+			for _, span := range exporter.GetSpans() {
+				spans[span.Name] = span
+				if span.Name == "db.query" {
+					dbSpans = append(dbSpans, span)
+				}
+			}
+
+			server, ok := spans["GET /api/collections/:collection/records"]
+			if !ok {
+				t.Fatalf("Missing the request server span in %v", spans)
+			}
+
+			if server.SpanKind != trace.SpanKindServer ||
+				server.SpanContext.TraceID().String() != "0af7651916cd43dd8448eb211c80319c" ||
+				server.Parent.SpanID().String() != "b7ad6b7169203331" {
+				t.Fatalf("Expected server span continuing the traceparent trace, got %+v", server)
+			}
+
+			children := []string{"search.Exec", "hook *core.RecordsListEvent"}
+			for _, name := range children {
+				if span, ok := spans[name]; !ok || span.Parent.SpanID() != server.SpanContext.SpanID() {
+					t.Fatalf("Expected %q child span of the request span, got %+v", name, span)
+				}
+			}
+
+			if len(dbSpans) == 0 {
+				t.Fatal("Expected db.query spans")
+			}
+
+			for _, span := range dbSpans {
+				if span.SpanContext.TraceID() != server.SpanContext.TraceID() {
+					t.Fatalf("Expected db span from the request trace, got %+v", span)
+				}
+
+				for _, attr := range span.Attributes {
+					if attr.Key == "db.statement" && strings.Contains(attr.Value.AsString(), "test1") {
+						t.Fatalf("Expected db.statement without the inlined params, got %q", attr.Value.AsString())
+					}
+				}
+			}
+		},
+	}
+
+	scenario.Test(t)
+}
+
+func TestLoadRequestId(t *testing.T) {
+	t.Parallel()
+
+	longId := strings.Repeat("a", 129)
+
+	// the handler responds with the request ids from the echo and request contexts
+	beforeTestFunc := func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+		e.AddRoute(echo.Route{
+			Method: http.MethodGet,
+			Path:   "/my/test",
+			Handler: func(c echo.Context) error {
+				return c.String(200, apis.RequestId(c)+"|"+core.RequestIdFromContext(c.Request().Context()))
+			},
+		})
+	}
+
+	expectHeaderFunc := func(expected string) func(t *testing.T, app *tests.TestApp, res *http.Response) {
+		return func(t *testing.T, app *tests.TestApp, res *http.Response) {
+			requestId := res.Header.Get("X-Request-Id")
+
+			if expected == "" {
+				if len(requestId) != 20 {
+					t.Fatalf("Expected generated 20 chars request id, got %q", requestId)
+				}
+			} else if requestId != expected {
+				t.Fatalf("Expected request id %q, got %q", expected, requestId)
+			}
+		}
+	}
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "without X-Request-Id header",
+			Method:          http.MethodGet,
+			Url:             "/my/test",
+			BeforeTestFunc:  beforeTestFunc,
+			ExpectedStatus:  200,
+			ExpectedContent: []string{"|"},
+			AfterTestFunc:   expectHeaderFunc(""),
+		},
+		{
+			Name:   "with invalid X-Request-Id header characters",
+			Method: http.MethodGet,
+			Url:    "/my/test",
+			RequestHeaders: map[string]string{
+				"X-Request-Id": "test id<script>",
+			},
+			BeforeTestFunc:     beforeTestFunc,
+			ExpectedStatus:     200,
+			ExpectedContent:    []string{"|"},
+			NotExpectedContent: []string{"test id"},
+			AfterTestFunc:      expectHeaderFunc(""),
+		},
+		{
+			Name:   "with too long X-Request-Id header",
+			Method: http.MethodGet,
+			Url:    "/my/test",
+			RequestHeaders: map[string]string{
+				"X-Request-Id": longId,
+			},
+			BeforeTestFunc:     beforeTestFunc,
+			ExpectedStatus:     200,
+			ExpectedContent:    []string{"|"},
+			NotExpectedContent: []string{longId},
+			AfterTestFunc:      expectHeaderFunc(""),
+		},
+		{
+			Name:   "with valid X-Request-Id header",
+			Method: http.MethodGet,
+			Url:    "/my/test",
+			RequestHeaders: map[string]string{
+				"X-Request-Id": "Test-id_1.2:3",
+			},
+			BeforeTestFunc:  beforeTestFunc,
+			ExpectedStatus:  200,
+			ExpectedContent: []string{"Test-id_1.2:3|Test-id_1.2:3"},
+			AfterTestFunc:   expectHeaderFunc("Test-id_1.2:3"),
+		},
+		{
+			Name:   "api error response",
+			Method: http.MethodGet,
+			Url:    "/api/missing",
+			RequestHeaders: map[string]string{
+				"X-Request-Id": "test_request_id",
+			},
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"requestId":"test_request_id"`},
+			AfterTestFunc:   expectHeaderFunc("test_request_id"),
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
@@ -0,0 +1,692 @@
+package apis
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/core"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/models/schema"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/openapi"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/types"
+	"github.com/labstack/echo/v5"
+)
+
+// bindOpenApiApi registers the OpenAPI spec api endpoint.
+func bindOpenApiApi(app core.App, rg *echo.Group) {
+	api := openApiApi{app: app}
+
+	rg.GET("/openapi.json", api.spec, ActivityLogger(app), RequireAdminAuth())
+}
+
+type openApiApi struct {
+	app core.App
+}
+
+func (api *openApiApi) spec(c echo.Context) error {
+	doc, err := GenerateOpenApiDocument(api.app, c.Echo().Router().Routes())
+	if err != nil {
+		return NewApiError(http.StatusInternalServerError, "Failed to generate the OpenAPI spec.", err)
+	}
+
+	return c.JSON(http.StatusOK, doc)
+}
+
+// GenerateOpenApiDocument generates an OpenAPI 3 document describing
+// the records api of the current app collections.
+//
+// Routes that are not part of the collection records api
+// (eg. custom routes registered via OnBeforeServe) are also included
+// with their method, path params and the default error responses.
+func GenerateOpenApiDocument(app core.App, routes echo.Routes) (*openapi.Document, error) {
+	collections := []*models.Collection{}
+	if err := app.Dao().CollectionQuery().OrderBy("name ASC").All(&collections); err != nil {
+		return nil, err
+	}
+
+	doc := openapi.NewDocument(app.Settings().Meta.AppName, "1.0.0")
+	if appUrl := app.Settings().Meta.AppUrl; appUrl != "" {
+		doc.Servers = []openapi.Server{{Url: strings.TrimRight(appUrl, "/")}}
+	}
+
+	registerOpenApiComponents(doc)
+
+	for _, collection := range collections {
+		registerOpenApiCollection(doc, collection)
+	}
+
+	registerOpenApiRoutes(doc, routes)
+
+	return doc, nil
+}
+
+// -------------------------------------------------------------------
+
+const openApiSecurityScheme = "AuthToken"
+
+var openApiMethods = []string{
+	http.MethodGet,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+}
+
+func registerOpenApiComponents(doc *openapi.Document) {
+	doc.Components.SecuritySchemes[openApiSecurityScheme] = &openapi.SecurityScheme{
+		Type:        "apiKey",
+		In:          "header",
+		Name:        "Authorization",
+		Description: "Admin or auth record token.",
+	}
+
+	doc.Components.Schemas["ApiError"] = &openapi.Schema{
+		Type:     "object",
+		Required: []string{"code", "message", "data"},
+		Properties: map[string]*openapi.Schema{
+			"code":    {Type: "integer"},
+			"message": {Type: "string"},
+			"data": {
+				Type:        "object",
+				Description: "Field validation errors (if any).",
+				AdditionalProperties: &openapi.Schema{
+					Type: "object",
+					Properties: map[string]*openapi.Schema{
+						"code":    {Type: "string"},
+						"message": {Type: "string"},
+					},
+				},
+			},
+		},
+	}
+
+	errorResponses := map[string]string{
+		"BadRequest":   "Something went wrong while processing your request.",
+		"Unauthorized": "The request requires valid record authorization token to be set.",
+		"Forbidden":    "You are not allowed to perform this request.",
+		"NotFound":     "The requested resource wasn't found.",
+	}
+	for name, description := range errorResponses {
+		doc.Components.Responses[name] = &openapi.Response{
+			Description: description,
+			Content:     openapi.JsonContent(openapi.SchemaRef("ApiError")),
+		}
+	}
+
+	doc.Components.Parameters["page"] = &openapi.Parameter{
+		Name:        "page",
+		In:          "query",
+		Description: "The page (aka. offset) of the paginated list.",
+		Schema:      &openapi.Schema{Type: "integer", Minimum: types.Pointer(1.0)},
+	}
+	doc.Components.Parameters["perPage"] = &openapi.Parameter{
+		Name:        "perPage",
+		In:          "query",
+		Description: "The max returned items per page.",
+		Schema:      &openapi.Schema{Type: "integer", Minimum: types.Pointer(0.0)},
+	}
+	doc.Components.Parameters["sort"] = &openapi.Parameter{
+		Name:        "sort",
+		In:          "query",
+		Description: "Comma separated list of fields to sort by (eg. -created,id).",
+		Schema:      &openapi.Schema{Type: "string"},
+	}
+	doc.Components.Parameters["filter"] = &openapi.Parameter{
+		Name:        "filter",
+		In:          "query",
+		Description: "Filter expression to apply to the list (eg. title~'abc' && created>'2022-01-01').",
+		Schema:      &openapi.Schema{Type: "string"},
+	}
+	doc.Components.Parameters["expand"] = &openapi.Parameter{
+		Name:        "expand",
+		In:          "query",
+		Description: "Comma separated list of relation fields to auto expand (eg. author,comments.user).",
+		Schema:      &openapi.Schema{Type: "string"},
+	}
+	doc.Components.Parameters["fields"] = &openapi.Parameter{
+		Name:        "fields",
+		In:          "query",
+		Description: "Comma separated list of fields to return in the JSON response.",
+		Schema:      &openapi.Schema{Type: "string"},
+	}
+	doc.Components.Parameters["skipTotal"] = &openapi.Parameter{
+		Name:        "skipTotal",
+		In:          "query",
+		Description: "Skips the total counts query (totalItems and totalPages will be -1).",
+		Schema:      &openapi.Schema{Type: "boolean"},
+	}
+}
+
+func registerOpenApiCollection(doc *openapi.Document, collection *models.Collection) {
+	tag := collection.Name
+	doc.AddTag(tag, "The "+collection.Name+" collection ("+collection.Type+") records api.")
+
+	recordName := collection.Name + "Record"
+	listName := collection.Name + "List"
+	bodyName := collection.Name + "Body"
+
+	doc.Components.Schemas[recordName] = openApiRecordSchema(collection)
+	doc.Components.Schemas[listName] = &openapi.Schema{
+		Type:     "object",
+		Required: []string{"page", "perPage", "totalItems", "totalPages", "items"},
+		Properties: map[string]*openapi.Schema{
+			"page":       {Type: "integer"},
+			"perPage":    {Type: "integer"},
+			"totalItems": {Type: "integer"},
+			"totalPages": {Type: "integer"},
+			"items":      {Type: "array", Items: openapi.SchemaRef(recordName)},
+		},
+	}
+
+	basePath := "/api/collections/" + collection.Name
+	recordsPath := basePath + "/records"
+	recordPath := recordsPath + "/{id}"
+	idParam := &openapi.Parameter{Name: "id", In: "path", Required: true, Schema: &openapi.Schema{Type: "string"}}
+	recordResponse := &openapi.Response{Description: "The " + collection.Name + " record.", Content: openapi.JsonContent(openapi.SchemaRef(recordName))}
+
+	doc.AddOperation(recordsPath, http.MethodGet, &openapi.Operation{
+		Tags:        []string{tag},
+		Summary:     "List " + collection.Name + " records",
+		OperationId: "list_" + collection.Name,
+		Description: openApiRuleDescription(collection.ListRule),
+		Security:    openApiRuleSecurity(collection.ListRule),
+		Parameters: []*openapi.Parameter{
+			openapi.ParameterRef("page"),
+			openapi.ParameterRef("perPage"),
+			openapi.ParameterRef("sort"),
+			openapi.ParameterRef("filter"),
+			openapi.ParameterRef("expand"),
+			openapi.ParameterRef("fields"),
+			openapi.ParameterRef("skipTotal"),
+		},
+		Responses: map[string]*openapi.Response{
+			"200": {Description: "Paginated " + collection.Name + " records list.", Content: openapi.JsonContent(openapi.SchemaRef(listName))},
+			"400": openapi.ResponseRef("BadRequest"),
+			"403": openapi.ResponseRef("Forbidden"),
+		},
+	})
+
+	doc.AddOperation(recordPath, http.MethodGet, &openapi.Operation{
+		Tags:        []string{tag},
+		Summary:     "View " + collection.Name + " record",
+		OperationId: "view_" + collection.Name,
+		Description: openApiRuleDescription(collection.ViewRule),
+		Security:    openApiRuleSecurity(collection.ViewRule),
+		Parameters: []*openapi.Parameter{
+			idParam,
+			openapi.ParameterRef("expand"),
+			openapi.ParameterRef("fields"),
+		},
+		Responses: map[string]*openapi.Response{
+			"200": recordResponse,
+			"403": openapi.ResponseRef("Forbidden"),
+			"404": openapi.ResponseRef("NotFound"),
+		},
+	})
+
+	if collection.IsView() {
+		return // view collections are read-only
+	}
+
+	doc.Components.Schemas[bodyName] = openApiRecordBodySchema(collection)
+	body := &openapi.RequestBody{
+		Required: true,
+		Content: map[string]*openapi.MediaType{
+			"application/json":    {Schema: openapi.SchemaRef(bodyName)},
+			"multipart/form-data": {Schema: openapi.SchemaRef(bodyName)},
+		},
+	}
+
+	doc.AddOperation(recordsPath, http.MethodPost, &openapi.Operation{
+		Tags:        []string{tag},
+		Summary:     "Create " + collection.Name + " record",
+		OperationId: "create_" + collection.Name,
+		Description: openApiRuleDescription(collection.CreateRule),
+		Security:    openApiRuleSecurity(collection.CreateRule),
+		Parameters: []*openapi.Parameter{
+			openapi.ParameterRef("expand"),
+			openapi.ParameterRef("fields"),
+		},
+		RequestBody: body,
+		Responses: map[string]*openapi.Response{
+			"200": recordResponse,
+			"400": openapi.ResponseRef("BadRequest"),
+			"403": openapi.ResponseRef("Forbidden"),
+		},
+	})
+
+	doc.AddOperation(recordPath, http.MethodPatch, &openapi.Operation{
+		Tags:        []string{tag},
+		Summary:     "Update " + collection.Name + " record",
+		OperationId: "update_" + collection.Name,
+		Description: openApiRuleDescription(collection.UpdateRule),
+		Security:    openApiRuleSecurity(collection.UpdateRule),
+		Parameters: []*openapi.Parameter{
+			idParam,
+			openapi.ParameterRef("expand"),
+			openapi.ParameterRef("fields"),
+		},
+		RequestBody: body,
+		Responses: map[string]*openapi.Response{
+			"200": recordResponse,
+			"400": openapi.ResponseRef("BadRequest"),
+			"403": openapi.ResponseRef("Forbidden"),
+			"404": openapi.ResponseRef("NotFound"),
+		},
+	})
+
+	doc.AddOperation(recordPath, http.MethodDelete, &openapi.Operation{
+		Tags:        []string{tag},
+		Summary:     "Delete " + collection.Name + " record",
+		OperationId: "delete_" + collection.Name,
+		Description: openApiRuleDescription(collection.DeleteRule),
+		Security:    openApiRuleSecurity(collection.DeleteRule),
+		Parameters:  []*openapi.Parameter{idParam},
+		Responses: map[string]*openapi.Response{
+			"204": {Description: "The record was successfully deleted."},
+			"400": openapi.ResponseRef("BadRequest"),
+			"403": openapi.ResponseRef("Forbidden"),
+			"404": openapi.ResponseRef("NotFound"),
+		},
+	})
+
+	if collection.IsAuth() {
+		registerOpenApiAuthCollection(doc, collection)
+	}
+}
+
+func registerOpenApiAuthCollection(doc *openapi.Document, collection *models.Collection) {
+	tag := collection.Name
+	basePath := "/api/collections/" + collection.Name
+	authOptions := collection.AuthOptions()
+
+	authResponseName := collection.Name + "AuthResponse"
+	doc.Components.Schemas[authResponseName] = &openapi.Schema{
+		Type:     "object",
+		Required: []string{"token", "record"},
+		Properties: map[string]*openapi.Schema{
+			"token":  {Type: "string"},
+			"record": openapi.SchemaRef(collection.Name + "Record"),
+			"meta":   {Type: "object", Description: "OAuth2 provider data (OAuth2 auth only)."},
+		},
+	}
+	authResponses := map[string]*openapi.Response{
+		"200": {Description: "Successful authentication.", Content: openapi.JsonContent(openapi.SchemaRef(authResponseName))},
+		"400": openapi.ResponseRef("BadRequest"),
+	}
+	noContentResponses := map[string]*openapi.Response{
+		"204": {Description: "The request was successfully processed."},
+		"400": openapi.ResponseRef("BadRequest"),
+	}
+
+	addOp := func(path string, method string, id string, summary string, body *openapi.Schema, responses map[string]*openapi.Response, security bool) {
+		op := &openapi.Operation{
+			Tags:        []string{tag},
+			Summary:     summary,
+			OperationId: id + "_" + collection.Name,
+			Responses:   responses,
+		}
+		if body != nil {
+			op.RequestBody = &openapi.RequestBody{Required: true, Content: openapi.JsonContent(body)}
+		}
+		if security {
+			op.Security = []openapi.SecurityRequirement{{openApiSecurityScheme: {}}}
+		}
+		doc.AddOperation(basePath+path, method, op)
+	}
+
+	addOp("/auth-methods", http.MethodGet, "authMethods", "List the allowed auth methods", nil, map[string]*openapi.Response{
+		"200": {Description: "The allowed auth methods.", Content: openapi.JsonContent(&openapi.Schema{
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"usernamePassword": {Type: "boolean"},
+				"emailPassword":    {Type: "boolean"},
+				"authProviders":    {Type: "array", Items: &openapi.Schema{Type: "object"}},
+			},
+		})},
+	}, false)
+
+	addOp("/auth-refresh", http.MethodPost, "authRefresh", "Refresh the auth record token", nil, map[string]*openapi.Response{
+		"200": authResponses["200"],
+		"401": openapi.ResponseRef("Unauthorized"),
+		"403": openapi.ResponseRef("Forbidden"),
+	}, true)
+
+	if authOptions.AllowEmailAuth || authOptions.AllowUsernameAuth {
+		addOp("/auth-with-password", http.MethodPost, "authWithPassword", "Authenticate with identity and password", openApiObjectSchema(
+			[]string{"identity", "password"},
+			map[string]*openapi.Schema{
+				"identity": {Type: "string", Description: "The auth record username or email address."},
+				"password": {Type: "string", Format: "password"},
+			},
+		), authResponses, false)
+	}
+
+	if authOptions.AllowOAuth2Auth {
+		addOp("/auth-with-oauth2", http.MethodPost, "authWithOAuth2", "Authenticate with an OAuth2 provider", openApiObjectSchema(
+			[]string{"provider", "code", "codeVerifier", "redirectUrl"},
+			map[string]*openapi.Schema{
+				"provider":     {Type: "string"},
+				"code":         {Type: "string"},
+				"codeVerifier": {Type: "string"},
+				"redirectUrl":  {Type: "string"},
+				"createData":   {Type: "object", Description: "Optional data used when creating a new auth record."},
+			},
+		), authResponses, false)
+	}
+
+	addOp("/request-password-reset", http.MethodPost, "requestPasswordReset", "Send a password reset email", openApiObjectSchema(
+		[]string{"email"},
+		map[string]*openapi.Schema{"email": {Type: "string", Format: "email"}},
+	), noContentResponses, false)
+
+	addOp("/confirm-password-reset", http.MethodPost, "confirmPasswordReset", "Confirm a password reset request", openApiObjectSchema(
+		[]string{"token", "password", "passwordConfirm"},
+		map[string]*openapi.Schema{
+			"token":           {Type: "string"},
+			"password":        {Type: "string", Format: "password"},
+			"passwordConfirm": {Type: "string", Format: "password"},
+		},
+	), noContentResponses, false)
+
+	addOp("/request-verification", http.MethodPost, "requestVerification", "Send a verification email", openApiObjectSchema(
+		[]string{"email"},
+		map[string]*openapi.Schema{"email": {Type: "string", Format: "email"}},
+	), noContentResponses, false)
+
+	addOp("/confirm-verification", http.MethodPost, "confirmVerification", "Confirm a verification request", openApiObjectSchema(
+		[]string{"token"},
+		map[string]*openapi.Schema{"token": {Type: "string"}},
+	), noContentResponses, false)
+
+	addOp("/request-email-change", http.MethodPost, "requestEmailChange", "Send an email change request", openApiObjectSchema(
+		[]string{"newEmail"},
+		map[string]*openapi.Schema{"newEmail": {Type: "string", Format: "email"}},
+	), map[string]*openapi.Response{
+		"204": noContentResponses["204"],
+		"400": openapi.ResponseRef("BadRequest"),
+		"401": openapi.ResponseRef("Unauthorized"),
+		"403": openapi.ResponseRef("Forbidden"),
+	}, true)
+
+	addOp("/confirm-email-change", http.MethodPost, "confirmEmailChange", "Confirm an email change request", openApiObjectSchema(
+		[]string{"token", "password"},
+		map[string]*openapi.Schema{
+			"token":    {Type: "string"},
+			"password": {Type: "string", Format: "password"},
+		},
+	), noContentResponses, false)
+}
+
+// registerOpenApiRoutes registers all routes that are not
+// already described by the document (eg. custom app routes).
+func registerOpenApiRoutes(doc *openapi.Document, routes echo.Routes) {
+	sorted := make(echo.Routes, len(routes))
+	copy(sorted, routes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Path() < sorted[j].Path()
+	})
+
+	for _, route := range sorted {
+		method := route.Method()
+		if !isOpenApiMethod(method) {
+			continue
+		}
+
+		rawPath := route.Path()
+
+		// skip the generic collection routes since they are
+		// already described individually for each collection,
+		// the Admin UI and the wildcard routes (eg. static files serving)
+		if strings.HasPrefix(rawPath, "/api/collections/:collection/") ||
+			strings.HasPrefix(rawPath, strings.TrimRight(trailedAdminPath, "/")) ||
+			strings.Contains(rawPath, "*") {
+			continue
+		}
+
+		path, params := openApiPath(rawPath)
+		if doc.HasOperation(path, method) {
+			continue
+		}
+
+		op := &openapi.Operation{
+			Tags: []string{openApiRouteTag(rawPath)},
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "Successful response."},
+				"400": openapi.ResponseRef("BadRequest"),
+				"401": openapi.ResponseRef("Unauthorized"),
+				"403": openapi.ResponseRef("Forbidden"),
+				"404": openapi.ResponseRef("NotFound"),
+			},
+			Security: []openapi.SecurityRequirement{{}, {openApiSecurityScheme: {}}},
+		}
+
+		for _, name := range params {
+			op.Parameters = append(op.Parameters, &openapi.Parameter{
+				Name:     name,
+				In:       "path",
+				Required: true,
+				Schema:   &openapi.Schema{Type: "string"},
+			})
+		}
+
+		doc.AddOperation(path, method, op)
+		doc.AddTag(op.Tags[0], "")
+	}
+}
+
+// openApiPath converts an echo route path into an OpenAPI path
+// (eg. "/api/items/:id" -> "/api/items/{id}") and returns it
+// together with the path params names.
+func openApiPath(path string) (string, []string) {
+	parts := strings.Split(path, "/")
+	params := []string{}
+
+	for i, part := range parts {
+		if strings.HasPrefix(part, ":") {
+			name := part[1:]
+			parts[i] = "{" + name + "}"
+			params = append(params, name)
+		}
+	}
+
+	return strings.Join(parts, "/"), params
+}
+
+// openApiRouteTag returns the tag name for a non-collection route
+// based on its first non-"api" path segment.
+func openApiRouteTag(path string) string {
+	for _, part := range strings.Split(path, "/") {
+		if part == "" || part == "api" || strings.HasPrefix(part, ":") {
+			continue
+		}
+
+		return part
+	}
+
+	return "default"
+}
+
+func isOpenApiMethod(method string) bool {
+	for _, m := range openApiMethods {
+		if m == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+func openApiRuleDescription(rule *string) string {
+	if rule == nil {
+		return "Only admins can perform this action."
+	}
+
+	if *rule == "" {
+		return "Everyone can perform this action."
+	}
+
+	return "Rule: `" + *rule + "`"
+}
+
+func openApiRuleSecurity(rule *string) []openapi.SecurityRequirement {
+	if rule != nil && *rule == "" {
+		return nil // public
+	}
+
+	return []openapi.SecurityRequirement{{openApiSecurityScheme: {}}}
+}
+
+func openApiObjectSchema(required []string, properties map[string]*openapi.Schema) *openapi.Schema {
+	return &openapi.Schema{
+		Type:       "object",
+		Required:   required,
+		Properties: properties,
+	}
+}
+
+// openApiRecordSchema returns the JSON schema of a single collection record response.
+func openApiRecordSchema(collection *models.Collection) *openapi.Schema {
+	result := &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			schema.FieldNameId:             {Type: "string", ReadOnly: true},
+			schema.FieldNameCollectionId:   {Type: "string", ReadOnly: true},
+			schema.FieldNameCollectionName: {Type: "string", ReadOnly: true},
+			schema.FieldNameExpand:         {Type: "object", ReadOnly: true, Description: "The expanded relations (if requested)."},
+		},
+		Required: []string{
+			schema.FieldNameId,
+			schema.FieldNameCollectionId,
+			schema.FieldNameCollectionName,
+		},
+	}
+
+	result.Properties[schema.FieldNameCreated] = &openapi.Schema{Type: "string", Format: "date-time", ReadOnly: true}
+	result.Properties[schema.FieldNameUpdated] = &openapi.Schema{Type: "string", Format: "date-time", ReadOnly: true}
+
+	// view collections have created/updated only if they are part of the view query
+	if !collection.IsView() {
+		result.Required = append(result.Required, schema.FieldNameCreated, schema.FieldNameUpdated)
+	}
+
+	if collection.IsAuth() {
+		result.Properties[schema.FieldNameUsername] = &openapi.Schema{Type: "string"}
+		result.Properties[schema.FieldNameEmail] = &openapi.Schema{Type: "string", Format: "email", Description: "Returned only if emailVisibility is true or for the owner and admins."}
+		result.Properties[schema.FieldNameEmailVisibility] = &openapi.Schema{Type: "boolean"}
+		result.Properties[schema.FieldNameVerified] = &openapi.Schema{Type: "boolean"}
+		result.Required = append(result.Required, schema.FieldNameUsername, schema.FieldNameEmailVisibility, schema.FieldNameVerified)
+	}
+
+	for _, field := range collection.Schema.Fields() {
+		result.Properties[field.Name] = openApiFieldSchema(field, false)
+		result.Required = append(result.Required, field.Name)
+	}
+
+	return result
+}
+
+// openApiRecordBodySchema returns the JSON schema of a create/update record request body.
+func openApiRecordBodySchema(collection *models.Collection) *openapi.Schema {
+	result := &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			schema.FieldNameId: {Type: "string", Description: "Optional custom record id (create only)."},
+		},
+	}
+
+	if collection.IsAuth() {
+		result.Properties[schema.FieldNameUsername] = &openapi.Schema{Type: "string"}
+		result.Properties[schema.FieldNameEmail] = &openapi.Schema{Type: "string", Format: "email"}
+		result.Properties[schema.FieldNameEmailVisibility] = &openapi.Schema{Type: "boolean"}
+		result.Properties[schema.FieldNameVerified] = &openapi.Schema{Type: "boolean"}
+		result.Properties["password"] = &openapi.Schema{Type: "string", Format: "password", WriteOnly: true}
+		result.Properties["passwordConfirm"] = &openapi.Schema{Type: "string", Format: "password", WriteOnly: true}
+		result.Properties["oldPassword"] = &openapi.Schema{Type: "string", Format: "password", WriteOnly: true, Description: "Required when changing the password of a non-admin authenticated record (update only)."}
+	}
+
+	for _, field := range collection.Schema.Fields() {
+		result.Properties[field.Name] = openApiFieldSchema(field, true)
+	}
+
+	return result
+}
+
+// openApiFieldSchema converts a single collection schema field into a JSON schema.
+//
+// If isInput is true, file fields are described as binary uploads.
+func openApiFieldSchema(field *schema.SchemaField, isInput bool) *openapi.Schema {
+	result := &openapi.Schema{}
+
+	switch field.Type {
+	case schema.FieldTypeNumber:
+		result.Type = "number"
+		if options, ok := field.Options.(*schema.NumberOptions); ok {
+			result.Minimum = options.Min
+			result.Maximum = options.Max
+			if options.NoDecimal {
+				result.Type = "integer"
+			}
+		}
+	case schema.FieldTypeBool:
+		result.Type = "boolean"
+	case schema.FieldTypeEmail:
+		result.Type = "string"
+		result.Format = "email"
+	case schema.FieldTypeUrl:
+		result.Type = "string"
+		result.Format = "uri"
+	case schema.FieldTypeEditor:
+		result.Type = "string"
+		result.Format = "html"
+	case schema.FieldTypeDate:
+		result.Type = "string"
+		result.Format = "date-time"
+	case schema.FieldTypeJson:
+		result.Nullable = true
+	case schema.FieldTypeSelect:
+		result.Type = "string"
+		if options, ok := field.Options.(*schema.SelectOptions); ok {
+			for _, v := range options.Values {
+				result.Enum = append(result.Enum, v)
+			}
+			if options.IsMultiple() {
+				result = openApiArraySchema(result, nil, &options.MaxSelect)
+			}
+		}
+	case schema.FieldTypeFile:
+		result.Type = "string"
+		if isInput {
+			result.Format = "binary"
+		}
+		if options, ok := field.Options.(*schema.FileOptions); ok && options.IsMultiple() {
+			result = openApiArraySchema(result, nil, &options.MaxSelect)
+		}
+	case schema.FieldTypeRelation:
+		result.Type = "string"
+		result.Description = "Related record id."
+		if options, ok := field.Options.(*schema.RelationOptions); ok && options.IsMultiple() {
+			result = openApiArraySchema(result, options.MinSelect, options.MaxSelect)
+			result.Description = "Related record ids."
+		}
+	default: // text
+		result.Type = "string"
+		if options, ok := field.Options.(*schema.TextOptions); ok {
+			result.MinLength = options.Min
+			result.MaxLength = options.Max
+			result.Pattern = options.Pattern
+		}
+	}
+
+	return result
+}
+
+func openApiArraySchema(items *openapi.Schema, minItems *int, maxItems *int) *openapi.Schema {
+	return &openapi.Schema{
+		Type:     "array",
+		Items:    items,
+		MinItems: minItems,
+		MaxItems: maxItems,
+	}
+}
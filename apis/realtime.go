@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/AlperRehaYAZGAN/postgresbase/core"
+	"github.com/AlperRehaYAZGAN/postgresbase/daos"
 	"github.com/AlperRehaYAZGAN/postgresbase/forms"
 	"github.com/AlperRehaYAZGAN/postgresbase/models"
 	"github.com/AlperRehaYAZGAN/postgresbase/resolvers"
@@ -47,8 +48,8 @@ func (api *realtimeApi) connect(c echo.Context) error {
 	api.app.SubscriptionsBroker().Register(client)
 	defer func() {
 		disconnectEvent := &core.RealtimeDisconnectEvent{
-			HttpContext: c,
-			Client:      client,
+			BaseRequestEvent: core.BaseRequestEvent{HttpContext: c},
+			Client:           client,
 		}
 
 		if err := api.app.OnRealtimeDisconnectRequest().Trigger(disconnectEvent); err != nil {
@@ -69,9 +70,9 @@ func (api *realtimeApi) connect(c echo.Context) error {
 	c.Response().Header().Set("X-Accel-Buffering", "no")
 
 	connectEvent := &core.RealtimeConnectEvent{
-		HttpContext: c,
-		Client:      client,
-		IdleTimeout: 5 * time.Minute,
+		BaseRequestEvent: core.BaseRequestEvent{HttpContext: c},
+		Client:           client,
+		IdleTimeout:      5 * time.Minute,
 	}
 
 	if err := api.app.OnRealtimeConnectRequest().Trigger(connectEvent); err != nil {
@@ -82,8 +83,8 @@ func (api *realtimeApi) connect(c echo.Context) error {
 
 	// signalize established connection (aka. fire "connect" message)
 	connectMsgEvent := &core.RealtimeMessageEvent{
-		HttpContext: c,
-		Client:      client,
+		BaseRequestEvent: core.BaseRequestEvent{HttpContext: c},
+		Client:           client,
 		Message: &subscriptions.Message{
 			Name: "PB_CONNECT",
 			Data: []byte(`{"clientId":"` + client.Id() + `"}`),
@@ -128,9 +129,9 @@ func (api *realtimeApi) connect(c echo.Context) error {
 			}
 
 			msgEvent := &core.RealtimeMessageEvent{
-				HttpContext: c,
-				Client:      client,
-				Message:     &msg,
+				BaseRequestEvent: core.BaseRequestEvent{HttpContext: c},
+				Client:           client,
+				Message:          &msg,
 			}
 			msgErr := api.app.OnRealtimeBeforeMessageSend().Trigger(msgEvent, func(e *core.RealtimeMessageEvent) error {
 				w := e.HttpContext.Response()
@@ -192,15 +193,16 @@ func (api *realtimeApi) setSubscriptions(c echo.Context) error {
 	}
 
 	event := &core.RealtimeSubscribeEvent{
-		HttpContext:   c,
-		Client:        client,
-		Subscriptions: form.Subscriptions,
+		BaseRequestEvent: core.BaseRequestEvent{HttpContext: c},
+		Client:           client,
+		Subscriptions:    form.Subscriptions,
 	}
 
 	return api.app.OnRealtimeBeforeSubscribeRequest().Trigger(event, func(e *core.RealtimeSubscribeEvent) error {
 		// update auth state
 		e.Client.Set(ContextAdminKey, e.HttpContext.Get(ContextAdminKey))
 		e.Client.Set(ContextAuthRecordKey, e.HttpContext.Get(ContextAuthRecordKey))
+		e.Client.Set(ContextTenantKey, e.HttpContext.Get(ContextTenantKey))
 
 		// unsubscribe from any previous existing subscriptions
 		e.Client.Unsubscribe()
@@ -343,7 +345,16 @@ func (api *realtimeApi) resolveRecord(model models.Model) (record *models.Record
 
 	// check if it is custom Record model struct (ignore "private" tables)
 	if record == nil && !strings.HasPrefix(model.TableName(), "_") {
-		record, _ = api.app.Dao().FindRecordById(model.TableName(), model.GetId())
+		collection := api.resolveRecordCollection(model)
+		if collection != nil && collection.IsMultiTenant() {
+			// the record tenant is not known yet
+			api.app.Dao().RunWithoutTenantIsolation(func(txDao *daos.Dao) error {
+				record, _ = txDao.FindRecordById(collection.Id, model.GetId())
+				return nil
+			})
+		} else {
+			record, _ = api.app.Dao().FindRecordById(model.TableName(), model.GetId())
+		}
 	}
 
 	return record
@@ -391,119 +402,150 @@ func (api *realtimeApi) broadcastRecord(action string, record *models.Record, dr
 
 	dryCacheKey := action + "/" + record.Id
 
-	for _, client := range clients {
-		client := client
+	// the multi-tenant records are broadcasted only to the clients of
+	// the record tenant (and to the admins without explicit tenant)
+	dao := api.app.Dao()
+	var recordTenant string
+	if collection.IsMultiTenant() {
+		var err error
+		recordTenant, err = dao.FindRecordTenant(record)
+		if err != nil {
+			return err
+		}
 
-		// note: not executed concurrently to avoid races and to ensure
-		// that the access checks are applied for the current record db state
-		for prefix, rule := range subscriptionRuleMap {
-			subs := client.Subscriptions(prefix)
-			if len(subs) == 0 {
-				continue
-			}
+		if recordTenant != "" {
+			dao = dao.WithTenant(recordTenant)
+		} else {
+			dao = dao.WithoutTenantIsolation()
+		}
+	}
 
-			for sub, options := range subs {
-				// create a clean record copy without expand and unknown fields
-				// because we don't know yet which exact fields the client subscription has permissions to access
-				cleanRecord := record.CleanCopy()
-
-				// mock request data
-				requestInfo := &models.RequestInfo{
-					Context: models.RequestInfoContextRealtime,
-					Method:  "GET",
-					Query:   options.Query,
-					Headers: options.Headers,
+	return dao.RunInTenantScope(func(scopedDao *daos.Dao) error {
+		for _, client := range clients {
+			client := client
+
+			if collection.IsMultiTenant() {
+				clientTenant, _ := client.Get(ContextTenantKey).(string)
+				if clientTenant == "" {
+					if admin, _ := client.Get(ContextAdminKey).(*models.Admin); admin == nil {
+						continue
+					}
+				} else if clientTenant != recordTenant {
+					continue
 				}
-				requestInfo.Admin, _ = client.Get(ContextAdminKey).(*models.Admin)
-				requestInfo.AuthRecord, _ = client.Get(ContextAuthRecordKey).(*models.Record)
+			}
 
-				if !api.canAccessRecord(cleanRecord, requestInfo, rule) {
+			// note: not executed concurrently to avoid races and to ensure
+			// that the access checks are applied for the current record db state
+			for prefix, rule := range subscriptionRuleMap {
+				subs := client.Subscriptions(prefix)
+				if len(subs) == 0 {
 					continue
 				}
 
-				rawExpand := cast.ToString(options.Query[expandQueryParam])
-				if rawExpand != "" {
-					expandErrs := api.app.Dao().ExpandRecord(cleanRecord, strings.Split(rawExpand, ","), expandFetch(api.app.Dao(), requestInfo))
-					if len(expandErrs) > 0 {
-						api.app.Logger().Debug(
-							"[broadcastRecord] expand errors",
-							slog.String("id", cleanRecord.Id),
-							slog.String("collectionName", cleanRecord.Collection().Name),
-							slog.String("sub", sub),
-							slog.String("expand", rawExpand),
-							slog.Any("errors", expandErrs),
-						)
+				for sub, options := range subs {
+					// create a clean record copy without expand and unknown fields
+					// because we don't know yet which exact fields the client subscription has permissions to access
+					cleanRecord := record.CleanCopy()
+
+					// mock request data
+					requestInfo := &models.RequestInfo{
+						Context: models.RequestInfoContextRealtime,
+						Method:  "GET",
+						Query:   options.Query,
+						Headers: options.Headers,
+					}
+					requestInfo.Admin, _ = client.Get(ContextAdminKey).(*models.Admin)
+					requestInfo.AuthRecord, _ = client.Get(ContextAuthRecordKey).(*models.Record)
+
+					if !api.canAccessRecord(scopedDao, cleanRecord, requestInfo, rule) {
+						continue
 					}
-				}
 
-				// ignore the auth record email visibility checks
-				// for auth owner, admin or manager
-				if collection.IsAuth() {
-					authId := extractAuthIdFromGetter(client)
-					if authId == cleanRecord.Id {
-						if api.canAccessRecord(cleanRecord, requestInfo, collection.AuthOptions().ManageRule) {
-							cleanRecord.IgnoreEmailVisibility(true)
+					rawExpand := cast.ToString(options.Query[expandQueryParam])
+					if rawExpand != "" {
+						expandErrs := scopedDao.ExpandRecord(cleanRecord, strings.Split(rawExpand, ","), expandFetch(scopedDao, requestInfo))
+						if len(expandErrs) > 0 {
+							api.app.Logger().Debug(
+								"[broadcastRecord] expand errors",
+								slog.String("id", cleanRecord.Id),
+								slog.String("collectionName", cleanRecord.Collection().Name),
+								slog.String("sub", sub),
+								slog.String("expand", rawExpand),
+								slog.Any("errors", expandErrs),
+							)
 						}
 					}
-				}
 
-				data := &recordData{
-					Action: action,
-					Record: cleanRecord,
-				}
+					// ignore the auth record email visibility checks
+					// for auth owner, admin or manager
+					if collection.IsAuth() {
+						authId := extractAuthIdFromGetter(client)
+						if authId == cleanRecord.Id {
+							if api.canAccessRecord(scopedDao, cleanRecord, requestInfo, collection.AuthOptions().ManageRule) {
+								cleanRecord.IgnoreEmailVisibility(true)
+							}
+						}
+					}
 
-				// check fields
-				rawFields := cast.ToString(options.Query[fieldsQueryParam])
-				if rawFields != "" {
-					decoded, err := rest.PickFields(cleanRecord, rawFields)
-					if err == nil {
-						data.Record = decoded
-					} else {
+					data := &recordData{
+						Action: action,
+						Record: cleanRecord,
+					}
+
+					// check fields
+					rawFields := cast.ToString(options.Query[fieldsQueryParam])
+					if rawFields != "" {
+						decoded, err := rest.PickFields(cleanRecord, rawFields)
+						if err == nil {
+							data.Record = decoded
+						} else {
+							api.app.Logger().Debug(
+								"[broadcastRecord] pick fields error",
+								slog.String("id", cleanRecord.Id),
+								slog.String("collectionName", cleanRecord.Collection().Name),
+								slog.String("sub", sub),
+								slog.String("fields", rawFields),
+								slog.String("error", err.Error()),
+							)
+						}
+					}
+
+					dataBytes, err := json.Marshal(data)
+					if err != nil {
 						api.app.Logger().Debug(
-							"[broadcastRecord] pick fields error",
+							"[broadcastRecord] data marshal error",
 							slog.String("id", cleanRecord.Id),
 							slog.String("collectionName", cleanRecord.Collection().Name),
-							slog.String("sub", sub),
-							slog.String("fields", rawFields),
 							slog.String("error", err.Error()),
 						)
+						continue
 					}
-				}
 
-				dataBytes, err := json.Marshal(data)
-				if err != nil {
-					api.app.Logger().Debug(
-						"[broadcastRecord] data marshal error",
-						slog.String("id", cleanRecord.Id),
-						slog.String("collectionName", cleanRecord.Collection().Name),
-						slog.String("error", err.Error()),
-					)
-					continue
-				}
-
-				msg := subscriptions.Message{
-					Name: sub,
-					Data: dataBytes,
-				}
+					msg := subscriptions.Message{
+						Name: sub,
+						Data: dataBytes,
+					}
 
-				if dryCache {
-					messages, ok := client.Get(dryCacheKey).([]subscriptions.Message)
-					if !ok {
-						messages = []subscriptions.Message{msg}
+					if dryCache {
+						messages, ok := client.Get(dryCacheKey).([]subscriptions.Message)
+						if !ok {
+							messages = []subscriptions.Message{msg}
+						} else {
+							messages = append(messages, msg)
+						}
+						client.Set(dryCacheKey, messages)
 					} else {
-						messages = append(messages, msg)
+						routine.FireAndForget(func() {
+							client.Send(msg)
+						})
 					}
-					client.Set(dryCacheKey, messages)
-				} else {
-					routine.FireAndForget(func() {
-						client.Send(msg)
-					})
 				}
 			}
 		}
-	}
 
-	return nil
+		return nil
+	})
 }
 
 // broadcastDryCachedRecord broadcasts all cached record related messages.
@@ -552,13 +594,14 @@ func extractAuthIdFromGetter(val getter) string {
 
 // canAccessRecord checks if the subscription client has access to the specified record model.
 func (api *realtimeApi) canAccessRecord(
+	dao *daos.Dao,
 	record *models.Record,
 	requestInfo *models.RequestInfo,
 	accessRule *string,
 ) bool {
 	// check the access rule
 	// ---
-	if ok, _ := api.app.Dao().CanAccessRecord(record, requestInfo, accessRule); !ok {
+	if ok, _ := dao.CanAccessRecord(record, requestInfo, accessRule); !ok {
 		return false
 	}
 
@@ -574,7 +617,7 @@ func (api *realtimeApi) canAccessRecord(
 	}
 
 	ruleFunc := func(q *dbx.SelectQuery) error {
-		resolver := resolvers.NewRecordFieldResolver(api.app.Dao(), record.Collection(), requestInfo, false)
+		resolver := resolvers.NewRecordFieldResolver(dao, record.Collection(), requestInfo, false)
 
 		expr, err := search.FilterData(filter).BuildExpr(resolver)
 		if err != nil {
@@ -587,7 +630,7 @@ func (api *realtimeApi) canAccessRecord(
 		return nil
 	}
 
-	_, err := api.app.Dao().FindRecordById(record.Collection().Id, record.Id, ruleFunc)
+	_, err := dao.FindRecordById(record.Collection().Id, record.Id, ruleFunc)
 
 	return err == nil
 }
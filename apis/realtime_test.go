@@ -243,6 +243,79 @@ func TestRealtimeSubscribe(t *testing.T) {
 	}
 }
 
+func TestRealtimeMultiTenantRecordEvent(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	apis.InitApi(testApp)
+
+	collection, err := tests.EnableTestTenantIsolation(testApp, "demo2", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	admin, err := testApp.Dao().FindAdminByEmail("test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newClient := func(tenant string, admin *models.Admin) subscriptions.Client {
+		client := subscriptions.NewDefaultClient()
+		client.Set(apis.ContextTenantKey, tenant)
+		if admin != nil {
+			client.Set(apis.ContextAdminKey, admin)
+		}
+		client.Subscribe("demo2/*")
+		testApp.SubscriptionsBroker().Register(client)
+		return client
+	}
+
+	scenarios := []struct {
+		name          string
+		client        subscriptions.Client
+		expectMessage bool
+	}{
+		{"guest without tenant", newClient("", nil), false},
+		{"guest with the record tenant", newClient("acme", nil), true},
+		{"guest with different tenant", newClient("other", nil), false},
+		{"admin without tenant", newClient("", admin), true},
+		{"admin with different tenant", newClient("other", admin), false},
+	}
+
+	record := models.NewRecord(collection)
+	record.Set("title", "tenant_test")
+
+	err = testApp.Dao().RunInTenantTransaction("acme", func(txDao *daos.Dao) error {
+		return txDao.SaveRecord(record)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			timeout := 100 * time.Millisecond
+			if s.expectMessage {
+				timeout = time.Second
+			}
+
+			select {
+			case msg := <-s.client.Channel():
+				if !s.expectMessage {
+					t.Fatalf("Expected no message, got %s", msg.Data)
+				}
+				if !strings.Contains(string(msg.Data), `"id":"`+record.Id+`"`) {
+					t.Fatalf("Expected the created record message, got %s", msg.Data)
+				}
+			case <-time.After(timeout):
+				if s.expectMessage {
+					t.Fatal("Expected the created record message, got none")
+				}
+			}
+		})
+	}
+}
+
 func TestRealtimeAuthRecordDeleteEvent(t *testing.T) {
 	testApp, _ := tests.NewTestApp()
 	defer testApp.Cleanup()
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"sort"
 
 	"github.com/AlperRehaYAZGAN/postgresbase/core"
@@ -18,6 +19,7 @@ import (
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/search"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/security"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/subscriptions"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/webauthn"
 	"github.com/labstack/echo/v5"
 	"github.com/pocketbase/dbx"
 	"golang.org/x/oauth2"
@@ -41,6 +43,12 @@ func bindRecordAuthApi(app core.App, rg *echo.Group) {
 	subGroup.POST("/auth-refresh", api.authRefresh, RequireSameContextRecordAuth())
 	subGroup.POST("/auth-with-oauth2", api.authWithOAuth2)
 	subGroup.POST("/auth-with-password", api.authWithPassword)
+	subGroup.POST("/request-otp", api.requestOTP)
+	subGroup.POST("/auth-with-otp", api.authWithOTP)
+	subGroup.POST("/request-webauthn-login", api.requestWebAuthnLogin)
+	subGroup.POST("/auth-with-webauthn", api.authWithWebAuthn)
+	subGroup.POST("/request-webauthn-registration", api.requestWebAuthnRegistration, RequireSameContextRecordAuth())
+	subGroup.POST("/confirm-webauthn-registration", api.confirmWebAuthnRegistration, RequireSameContextRecordAuth())
 	subGroup.POST("/request-password-reset", api.requestPasswordReset)
 	subGroup.POST("/confirm-password-reset", api.confirmPasswordReset)
 	subGroup.POST("/request-verification", api.requestVerification)
@@ -49,6 +57,8 @@ func bindRecordAuthApi(app core.App, rg *echo.Group) {
 	subGroup.POST("/confirm-email-change", api.confirmEmailChange)
 	subGroup.GET("/records/:id/external-auths", api.listExternalAuths, RequireAdminOrOwnerAuth("id"))
 	subGroup.DELETE("/records/:id/external-auths/:provider", api.unlinkExternalAuth, RequireAdminOrOwnerAuth("id"))
+	subGroup.GET("/records/:id/webauthn-credentials", api.listWebAuthnCredentials, RequireAdminOrOwnerAuth("id"))
+	subGroup.DELETE("/records/:id/webauthn-credentials/:credentialId", api.deleteWebAuthnCredential, RequireAdminOrOwnerAuth("id"))
 }
 
 type recordAuthApi struct {
@@ -98,10 +108,14 @@ func (api *recordAuthApi) authMethods(c echo.Context) error {
 		UsernamePassword bool           `json:"usernamePassword"`
 		EmailPassword    bool           `json:"emailPassword"`
 		OnlyVerified     bool           `json:"onlyVerified"`
+		OTP              bool           `json:"otp"`
+		WebAuthn         bool           `json:"webauthn"`
 	}{
 		UsernamePassword: authOptions.AllowUsernameAuth,
 		EmailPassword:    authOptions.AllowEmailAuth,
 		OnlyVerified:     authOptions.OnlyVerified,
+		OTP:              authOptions.AllowOTPAuth,
+		WebAuthn:         authOptions.AllowWebAuthnAuth,
 		AuthProviders:    []providerInfo{},
 	}
 
@@ -192,6 +206,11 @@ func (api *recordAuthApi) authWithOAuth2(c echo.Context) error {
 		fallbackAuthRecord = loggedAuthRecord
 	}
 
+	dao, err := RequestTenantDao(c, api.app, collection)
+	if err != nil {
+		return err
+	}
+
 	form := forms.NewRecordOAuth2Login(api.app, collection, fallbackAuthRecord)
 	if readErr := c.Bind(form); readErr != nil {
 		return NewBadRequestError("An error occurred while loading the submitted data.", readErr)
@@ -242,40 +261,44 @@ func (api *recordAuthApi) authWithOAuth2(c echo.Context) error {
 		})
 	})
 
-	_, _, submitErr := form.Submit(func(next forms.InterceptorNextFunc[*forms.RecordOAuth2LoginData]) forms.InterceptorNextFunc[*forms.RecordOAuth2LoginData] {
-		return func(data *forms.RecordOAuth2LoginData) error {
-			event.Record = data.Record
-			event.OAuth2User = data.OAuth2User
-			event.ProviderClient = data.ProviderClient
-			event.IsNewRecord = data.Record == nil
+	return dao.RunInTenantScope(func(scopedDao *daos.Dao) error {
+		form.SetDao(scopedDao)
 
-			return api.app.OnRecordBeforeAuthWithOAuth2Request().Trigger(event, func(e *core.RecordAuthWithOAuth2Event) error {
-				data.Record = e.Record
-				data.OAuth2User = e.OAuth2User
+		_, _, submitErr := form.Submit(func(next forms.InterceptorNextFunc[*forms.RecordOAuth2LoginData]) forms.InterceptorNextFunc[*forms.RecordOAuth2LoginData] {
+			return func(data *forms.RecordOAuth2LoginData) error {
+				event.Record = data.Record
+				event.OAuth2User = data.OAuth2User
+				event.ProviderClient = data.ProviderClient
+				event.IsNewRecord = data.Record == nil
 
-				if err := next(data); err != nil {
-					return NewBadRequestError("Failed to authenticate.", err)
-				}
+				return api.app.OnRecordBeforeAuthWithOAuth2Request().Trigger(event, func(e *core.RecordAuthWithOAuth2Event) error {
+					data.Record = e.Record
+					data.OAuth2User = e.OAuth2User
+
+					if err := next(data); err != nil {
+						return NewBadRequestError("Failed to authenticate.", err)
+					}
 
-				e.Record = data.Record
-				e.OAuth2User = data.OAuth2User
+					e.Record = data.Record
+					e.OAuth2User = data.OAuth2User
 
-				meta := struct {
-					*auth.AuthUser
-					IsNew bool `json:"isNew"`
-				}{
-					AuthUser: e.OAuth2User,
-					IsNew:    event.IsNewRecord,
-				}
+					meta := struct {
+						*auth.AuthUser
+						IsNew bool `json:"isNew"`
+					}{
+						AuthUser: e.OAuth2User,
+						IsNew:    event.IsNewRecord,
+					}
 
-				return api.app.OnRecordAfterAuthWithOAuth2Request().Trigger(event, func(e *core.RecordAuthWithOAuth2Event) error {
-					return RecordAuthResponse(api.app, e.HttpContext, e.Record, meta)
+					return api.app.OnRecordAfterAuthWithOAuth2Request().Trigger(event, func(e *core.RecordAuthWithOAuth2Event) error {
+						return RecordAuthResponse(api.app, e.HttpContext, e.Record, meta)
+					})
 				})
-			})
-		}
-	})
+			}
+		})
 
-	return submitErr
+		return submitErr
+	})
 }
 
 func (api *recordAuthApi) authWithPassword(c echo.Context) error {
@@ -284,6 +307,11 @@ func (api *recordAuthApi) authWithPassword(c echo.Context) error {
 		return NewNotFoundError("Missing collection context.", nil)
 	}
 
+	dao, err := RequestTenantDao(c, api.app, collection)
+	if err != nil {
+		return err
+	}
+
 	form := forms.NewRecordPasswordLogin(api.app, collection)
 	if readErr := c.Bind(form); readErr != nil {
 		return NewBadRequestError("An error occurred while loading the submitted data.", readErr)
@@ -295,21 +323,159 @@ func (api *recordAuthApi) authWithPassword(c echo.Context) error {
 	event.Password = form.Password
 	event.Identity = form.Identity
 
-	_, submitErr := form.Submit(func(next forms.InterceptorNextFunc[*models.Record]) forms.InterceptorNextFunc[*models.Record] {
-		return func(record *models.Record) error {
-			event.Record = record
+	return dao.RunInTenantScope(func(scopedDao *daos.Dao) error {
+		form.SetDao(scopedDao)
 
-			return api.app.OnRecordBeforeAuthWithPasswordRequest().Trigger(event, func(e *core.RecordAuthWithPasswordEvent) error {
-				if err := next(e.Record); err != nil {
-					return NewBadRequestError("Failed to authenticate.", err)
-				}
+		_, submitErr := form.Submit(func(next forms.InterceptorNextFunc[*models.Record]) forms.InterceptorNextFunc[*models.Record] {
+			return func(record *models.Record) error {
+				event.Record = record
 
-				return api.app.OnRecordAfterAuthWithPasswordRequest().Trigger(event, func(e *core.RecordAuthWithPasswordEvent) error {
-					return RecordAuthResponse(api.app, e.HttpContext, e.Record, nil)
+				return api.app.OnRecordBeforeAuthWithPasswordRequest().Trigger(event, func(e *core.RecordAuthWithPasswordEvent) error {
+					if err := next(e.Record); err != nil {
+						return NewBadRequestError("Failed to authenticate.", err)
+					}
+
+					return api.app.OnRecordAfterAuthWithPasswordRequest().Trigger(event, func(e *core.RecordAuthWithPasswordEvent) error {
+						return RecordAuthResponse(api.app, e.HttpContext, e.Record, nil)
+					})
 				})
+			}
+		})
+
+		return submitErr
+	})
+}
+
+func (api *recordAuthApi) requestOTP(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("Missing collection context.", nil)
+	}
+
+	if !collection.AuthOptions().AllowOTPAuth {
+		return NewBadRequestError("The collection is not configured to allow OTP authentication.", nil)
+	}
+
+	form := forms.NewRecordOTPRequest(api.app, collection)
+	if err := c.Bind(form); err != nil {
+		return NewBadRequestError("An error occurred while loading the submitted data.", err)
+	}
+
+	if err := form.Validate(); err != nil {
+		return NewBadRequestError("An error occurred while validating the form.", err)
+	}
+
+	var otp *models.OTP
+
+	dao, submitErr := RequestTenantDao(c, api.app, collection)
+	if submitErr == nil {
+		submitErr = dao.RunInTenantScope(func(scopedDao *daos.Dao) error {
+			form.SetDao(scopedDao)
+
+			var err error
+			otp, err = form.Submit(func(next forms.InterceptorNextFunc[*models.Record]) forms.InterceptorNextFunc[*models.Record] {
+				return func(record *models.Record) error {
+					// run in background because we don't need to show the result to the client
+					// (in its own tenant scope since the request one ends with the handler)
+					routine.FireAndForget(func() {
+						err := dao.RunInTenantScope(func(bgDao *daos.Dao) error {
+							form.SetDao(bgDao)
+							return next(record)
+						})
+						if err != nil {
+							api.app.Logger().Debug(
+								"Failed to send OTP email",
+								slog.String("error", err.Error()),
+							)
+						}
+					})
+
+					return nil
+				}
 			})
+
+			return err
+		})
+	}
+
+	// return a random (non-existing) OTP id on submit errors
+	// as a measure against emails enumeration
+	otpId := security.RandomSnowflakeId()
+	if submitErr == nil {
+		otpId = otp.Id
+	} else {
+		api.app.Logger().Debug(
+			"Failed to request OTP",
+			slog.String("error", submitErr.Error()),
+		)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"otpId": otpId})
+}
+
+func (api *recordAuthApi) authWithOTP(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("Missing collection context.", nil)
+	}
+
+	if !collection.AuthOptions().AllowOTPAuth {
+		return NewBadRequestError("The collection is not configured to allow OTP authentication.", nil)
+	}
+
+	dao, err := RequestTenantDao(c, api.app, collection)
+	if err != nil {
+		return err
+	}
+
+	form := forms.NewRecordOTPLogin(api.app, collection)
+	if readErr := c.Bind(form); readErr != nil {
+		return NewBadRequestError("An error occurred while loading the submitted data.", readErr)
+	}
+
+	event := new(core.RecordAuthWithOTPEvent)
+	event.HttpContext = c
+	event.Collection = collection
+
+	var submitErr error
+
+	err = dao.RunInTenantScope(func(scopedDao *daos.Dao) error {
+		form.SetDao(scopedDao)
+
+		var intercepted bool
+
+		_, submitErr = form.Submit(func(next forms.InterceptorNextFunc[*forms.RecordOTPLoginData]) forms.InterceptorNextFunc[*forms.RecordOTPLoginData] {
+			return func(data *forms.RecordOTPLoginData) error {
+				intercepted = true
+
+				event.Record = data.Record
+				event.OTP = data.OTP
+
+				return api.app.OnRecordBeforeAuthWithOTPRequest().Trigger(event, func(e *core.RecordAuthWithOTPEvent) error {
+					data.Record = e.Record
+
+					if err := next(data); err != nil {
+						return NewBadRequestError("Failed to authenticate.", err)
+					}
+
+					return api.app.OnRecordAfterAuthWithOTPRequest().Trigger(event, func(e *core.RecordAuthWithOTPEvent) error {
+						return RecordAuthResponse(api.app, e.HttpContext, e.Record, nil)
+					})
+				})
+			}
+		})
+
+		if intercepted {
+			return submitErr
 		}
+
+		// the OTP verification failed but its consumed attempt
+		// must persist (otherwise the attempts limit could be bypassed)
+		return nil
 	})
+	if err != nil {
+		return err
+	}
 
 	return submitErr
 }
@@ -338,31 +504,43 @@ func (api *recordAuthApi) requestPasswordReset(c echo.Context) error {
 	event.HttpContext = c
 	event.Collection = collection
 
-	submitErr := form.Submit(func(next forms.InterceptorNextFunc[*models.Record]) forms.InterceptorNextFunc[*models.Record] {
-		return func(record *models.Record) error {
-			event.Record = record
-
-			return api.app.OnRecordBeforeRequestPasswordResetRequest().Trigger(event, func(e *core.RecordRequestPasswordResetEvent) error {
-				// run in background because we don't need to show the result to the client
-				routine.FireAndForget(func() {
-					if err := next(e.Record); err != nil {
-						api.app.Logger().Debug(
-							"Failed to send password reset email",
-							slog.String("error", err.Error()),
-						)
-					}
-				})
-
-				return api.app.OnRecordAfterRequestPasswordResetRequest().Trigger(event, func(e *core.RecordRequestPasswordResetEvent) error {
-					if e.HttpContext.Response().Committed {
-						return nil
-					}
-
-					return e.HttpContext.NoContent(http.StatusNoContent)
-				})
+	dao, submitErr := RequestTenantDao(c, api.app, collection)
+	if submitErr == nil {
+		submitErr = dao.RunInTenantScope(func(scopedDao *daos.Dao) error {
+			form.SetDao(scopedDao)
+
+			return form.Submit(func(next forms.InterceptorNextFunc[*models.Record]) forms.InterceptorNextFunc[*models.Record] {
+				return func(record *models.Record) error {
+					event.Record = record
+
+					return api.app.OnRecordBeforeRequestPasswordResetRequest().Trigger(event, func(e *core.RecordRequestPasswordResetEvent) error {
+						// run in background because we don't need to show the result to the client
+						// (in its own tenant scope since the request one ends with the handler)
+						routine.FireAndForget(func() {
+							err := dao.RunInTenantScope(func(bgDao *daos.Dao) error {
+								form.SetDao(bgDao)
+								return next(e.Record)
+							})
+							if err != nil {
+								api.app.Logger().Debug(
+									"Failed to send password reset email",
+									slog.String("error", err.Error()),
+								)
+							}
+						})
+
+						return api.app.OnRecordAfterRequestPasswordResetRequest().Trigger(event, func(e *core.RecordRequestPasswordResetEvent) error {
+							if e.HttpContext.Response().Committed {
+								return nil
+							}
+
+							return e.HttpContext.NoContent(http.StatusNoContent)
+						})
+					})
+				}
 			})
-		}
-	})
+		})
+	}
 
 	// eagerly write 204 response and skip submit errors
 	// as a measure against emails enumeration
@@ -379,6 +557,11 @@ func (api *recordAuthApi) confirmPasswordReset(c echo.Context) error {
 		return NewNotFoundError("Missing collection context.", nil)
 	}
 
+	dao, err := recordTokenTenantDao(c, api.app, collection)
+	if err != nil {
+		return err
+	}
+
 	form := forms.NewRecordPasswordResetConfirm(api.app, collection)
 	if readErr := c.Bind(form); readErr != nil {
 		return NewBadRequestError("An error occurred while loading the submitted data.", readErr)
@@ -388,27 +571,31 @@ func (api *recordAuthApi) confirmPasswordReset(c echo.Context) error {
 	event.HttpContext = c
 	event.Collection = collection
 
-	_, submitErr := form.Submit(func(next forms.InterceptorNextFunc[*models.Record]) forms.InterceptorNextFunc[*models.Record] {
-		return func(record *models.Record) error {
-			event.Record = record
+	return dao.RunInTenantScope(func(scopedDao *daos.Dao) error {
+		form.SetDao(scopedDao)
 
-			return api.app.OnRecordBeforeConfirmPasswordResetRequest().Trigger(event, func(e *core.RecordConfirmPasswordResetEvent) error {
-				if err := next(e.Record); err != nil {
-					return NewBadRequestError("Failed to set new password.", err)
-				}
+		_, submitErr := form.Submit(func(next forms.InterceptorNextFunc[*models.Record]) forms.InterceptorNextFunc[*models.Record] {
+			return func(record *models.Record) error {
+				event.Record = record
 
-				return api.app.OnRecordAfterConfirmPasswordResetRequest().Trigger(event, func(e *core.RecordConfirmPasswordResetEvent) error {
-					if e.HttpContext.Response().Committed {
-						return nil
+				return api.app.OnRecordBeforeConfirmPasswordResetRequest().Trigger(event, func(e *core.RecordConfirmPasswordResetEvent) error {
+					if err := next(e.Record); err != nil {
+						return NewBadRequestError("Failed to set new password.", err)
 					}
 
-					return e.HttpContext.NoContent(http.StatusNoContent)
+					return api.app.OnRecordAfterConfirmPasswordResetRequest().Trigger(event, func(e *core.RecordConfirmPasswordResetEvent) error {
+						if e.HttpContext.Response().Committed {
+							return nil
+						}
+
+						return e.HttpContext.NoContent(http.StatusNoContent)
+					})
 				})
-			})
-		}
-	})
+			}
+		})
 
-	return submitErr
+		return submitErr
+	})
 }
 
 func (api *recordAuthApi) requestVerification(c echo.Context) error {
@@ -430,31 +617,43 @@ func (api *recordAuthApi) requestVerification(c echo.Context) error {
 	event.HttpContext = c
 	event.Collection = collection
 
-	submitErr := form.Submit(func(next forms.InterceptorNextFunc[*models.Record]) forms.InterceptorNextFunc[*models.Record] {
-		return func(record *models.Record) error {
-			event.Record = record
-
-			return api.app.OnRecordBeforeRequestVerificationRequest().Trigger(event, func(e *core.RecordRequestVerificationEvent) error {
-				// run in background because we don't need to show the result to the client
-				routine.FireAndForget(func() {
-					if err := next(e.Record); err != nil {
-						api.app.Logger().Debug(
-							"Failed to send verification email",
-							slog.String("error", err.Error()),
-						)
-					}
-				})
-
-				return api.app.OnRecordAfterRequestVerificationRequest().Trigger(event, func(e *core.RecordRequestVerificationEvent) error {
-					if e.HttpContext.Response().Committed {
-						return nil
-					}
-
-					return e.HttpContext.NoContent(http.StatusNoContent)
-				})
+	dao, submitErr := RequestTenantDao(c, api.app, collection)
+	if submitErr == nil {
+		submitErr = dao.RunInTenantScope(func(scopedDao *daos.Dao) error {
+			form.SetDao(scopedDao)
+
+			return form.Submit(func(next forms.InterceptorNextFunc[*models.Record]) forms.InterceptorNextFunc[*models.Record] {
+				return func(record *models.Record) error {
+					event.Record = record
+
+					return api.app.OnRecordBeforeRequestVerificationRequest().Trigger(event, func(e *core.RecordRequestVerificationEvent) error {
+						// run in background because we don't need to show the result to the client
+						// (in its own tenant scope since the request one ends with the handler)
+						routine.FireAndForget(func() {
+							err := dao.RunInTenantScope(func(bgDao *daos.Dao) error {
+								form.SetDao(bgDao)
+								return next(e.Record)
+							})
+							if err != nil {
+								api.app.Logger().Debug(
+									"Failed to send verification email",
+									slog.String("error", err.Error()),
+								)
+							}
+						})
+
+						return api.app.OnRecordAfterRequestVerificationRequest().Trigger(event, func(e *core.RecordRequestVerificationEvent) error {
+							if e.HttpContext.Response().Committed {
+								return nil
+							}
+
+							return e.HttpContext.NoContent(http.StatusNoContent)
+						})
+					})
+				}
 			})
-		}
-	})
+		})
+	}
 
 	// eagerly write 204 response and skip submit errors
 	// as a measure against users enumeration
@@ -471,6 +670,11 @@ func (api *recordAuthApi) confirmVerification(c echo.Context) error {
 		return NewNotFoundError("Missing collection context.", nil)
 	}
 
+	dao, err := recordTokenTenantDao(c, api.app, collection)
+	if err != nil {
+		return err
+	}
+
 	form := forms.NewRecordVerificationConfirm(api.app, collection)
 	if readErr := c.Bind(form); readErr != nil {
 		return NewBadRequestError("An error occurred while loading the submitted data.", readErr)
@@ -480,27 +684,31 @@ func (api *recordAuthApi) confirmVerification(c echo.Context) error {
 	event.HttpContext = c
 	event.Collection = collection
 
-	_, submitErr := form.Submit(func(next forms.InterceptorNextFunc[*models.Record]) forms.InterceptorNextFunc[*models.Record] {
-		return func(record *models.Record) error {
-			event.Record = record
+	return dao.RunInTenantScope(func(scopedDao *daos.Dao) error {
+		form.SetDao(scopedDao)
 
-			return api.app.OnRecordBeforeConfirmVerificationRequest().Trigger(event, func(e *core.RecordConfirmVerificationEvent) error {
-				if err := next(e.Record); err != nil {
-					return NewBadRequestError("An error occurred while submitting the form.", err)
-				}
+		_, submitErr := form.Submit(func(next forms.InterceptorNextFunc[*models.Record]) forms.InterceptorNextFunc[*models.Record] {
+			return func(record *models.Record) error {
+				event.Record = record
 
-				return api.app.OnRecordAfterConfirmVerificationRequest().Trigger(event, func(e *core.RecordConfirmVerificationEvent) error {
-					if e.HttpContext.Response().Committed {
-						return nil
+				return api.app.OnRecordBeforeConfirmVerificationRequest().Trigger(event, func(e *core.RecordConfirmVerificationEvent) error {
+					if err := next(e.Record); err != nil {
+						return NewBadRequestError("An error occurred while submitting the form.", err)
 					}
 
-					return e.HttpContext.NoContent(http.StatusNoContent)
+					return api.app.OnRecordAfterConfirmVerificationRequest().Trigger(event, func(e *core.RecordConfirmVerificationEvent) error {
+						if e.HttpContext.Response().Committed {
+							return nil
+						}
+
+						return e.HttpContext.NoContent(http.StatusNoContent)
+					})
 				})
-			})
-		}
-	})
+			}
+		})
 
-	return submitErr
+		return submitErr
+	})
 }
 
 func (api *recordAuthApi) requestEmailChange(c echo.Context) error {
@@ -514,6 +722,11 @@ func (api *recordAuthApi) requestEmailChange(c echo.Context) error {
 		return NewUnauthorizedError("The request requires valid auth record.", nil)
 	}
 
+	dao, err := RequestTenantDao(c, api.app, collection)
+	if err != nil {
+		return err
+	}
+
 	form := forms.NewRecordEmailChangeRequest(api.app, record)
 	if err := c.Bind(form); err != nil {
 		return NewBadRequestError("An error occurred while loading the submitted data.", err)
@@ -524,22 +737,26 @@ func (api *recordAuthApi) requestEmailChange(c echo.Context) error {
 	event.Collection = collection
 	event.Record = record
 
-	return form.Submit(func(next forms.InterceptorNextFunc[*models.Record]) forms.InterceptorNextFunc[*models.Record] {
-		return func(record *models.Record) error {
-			return api.app.OnRecordBeforeRequestEmailChangeRequest().Trigger(event, func(e *core.RecordRequestEmailChangeEvent) error {
-				if err := next(e.Record); err != nil {
-					return NewBadRequestError("Failed to request email change.", err)
-				}
+	return dao.RunInTenantScope(func(scopedDao *daos.Dao) error {
+		form.SetDao(scopedDao)
 
-				return api.app.OnRecordAfterRequestEmailChangeRequest().Trigger(event, func(e *core.RecordRequestEmailChangeEvent) error {
-					if e.HttpContext.Response().Committed {
-						return nil
+		return form.Submit(func(next forms.InterceptorNextFunc[*models.Record]) forms.InterceptorNextFunc[*models.Record] {
+			return func(record *models.Record) error {
+				return api.app.OnRecordBeforeRequestEmailChangeRequest().Trigger(event, func(e *core.RecordRequestEmailChangeEvent) error {
+					if err := next(e.Record); err != nil {
+						return NewBadRequestError("Failed to request email change.", err)
 					}
 
-					return e.HttpContext.NoContent(http.StatusNoContent)
+					return api.app.OnRecordAfterRequestEmailChangeRequest().Trigger(event, func(e *core.RecordRequestEmailChangeEvent) error {
+						if e.HttpContext.Response().Committed {
+							return nil
+						}
+
+						return e.HttpContext.NoContent(http.StatusNoContent)
+					})
 				})
-			})
-		}
+			}
+		})
 	})
 }
 
@@ -549,6 +766,11 @@ func (api *recordAuthApi) confirmEmailChange(c echo.Context) error {
 		return NewNotFoundError("Missing collection context.", nil)
 	}
 
+	dao, err := recordTokenTenantDao(c, api.app, collection)
+	if err != nil {
+		return err
+	}
+
 	form := forms.NewRecordEmailChangeConfirm(api.app, collection)
 	if readErr := c.Bind(form); readErr != nil {
 		return NewBadRequestError("An error occurred while loading the submitted data.", readErr)
@@ -558,27 +780,31 @@ func (api *recordAuthApi) confirmEmailChange(c echo.Context) error {
 	event.HttpContext = c
 	event.Collection = collection
 
-	_, submitErr := form.Submit(func(next forms.InterceptorNextFunc[*models.Record]) forms.InterceptorNextFunc[*models.Record] {
-		return func(record *models.Record) error {
-			event.Record = record
+	return dao.RunInTenantScope(func(scopedDao *daos.Dao) error {
+		form.SetDao(scopedDao)
 
-			return api.app.OnRecordBeforeConfirmEmailChangeRequest().Trigger(event, func(e *core.RecordConfirmEmailChangeEvent) error {
-				if err := next(e.Record); err != nil {
-					return NewBadRequestError("Failed to confirm email change.", err)
-				}
+		_, submitErr := form.Submit(func(next forms.InterceptorNextFunc[*models.Record]) forms.InterceptorNextFunc[*models.Record] {
+			return func(record *models.Record) error {
+				event.Record = record
 
-				return api.app.OnRecordAfterConfirmEmailChangeRequest().Trigger(event, func(e *core.RecordConfirmEmailChangeEvent) error {
-					if e.HttpContext.Response().Committed {
-						return nil
+				return api.app.OnRecordBeforeConfirmEmailChangeRequest().Trigger(event, func(e *core.RecordConfirmEmailChangeEvent) error {
+					if err := next(e.Record); err != nil {
+						return NewBadRequestError("Failed to confirm email change.", err)
 					}
 
-					return e.HttpContext.NoContent(http.StatusNoContent)
+					return api.app.OnRecordAfterConfirmEmailChangeRequest().Trigger(event, func(e *core.RecordConfirmEmailChangeEvent) error {
+						if e.HttpContext.Response().Committed {
+							return nil
+						}
+
+						return e.HttpContext.NoContent(http.StatusNoContent)
+					})
 				})
-			})
-		}
-	})
+			}
+		})
 
-	return submitErr
+		return submitErr
+	})
 }
 
 func (api *recordAuthApi) listExternalAuths(c echo.Context) error {
@@ -592,7 +818,17 @@ func (api *recordAuthApi) listExternalAuths(c echo.Context) error {
 		return NewNotFoundError("", nil)
 	}
 
-	record, err := api.app.Dao().FindRecordById(collection.Id, id)
+	dao, err := RequestTenantDao(c, api.app, collection)
+	if err != nil {
+		return err
+	}
+
+	var record *models.Record
+
+	err = dao.RunInTenantScope(func(scopedDao *daos.Dao) error {
+		record, err = scopedDao.FindRecordById(collection.Id, id)
+		return err
+	})
 	if err != nil || record == nil {
 		return NewNotFoundError("", err)
 	}
@@ -625,7 +861,17 @@ func (api *recordAuthApi) unlinkExternalAuth(c echo.Context) error {
 		return NewNotFoundError("", nil)
 	}
 
-	record, err := api.app.Dao().FindRecordById(collection.Id, id)
+	dao, err := RequestTenantDao(c, api.app, collection)
+	if err != nil {
+		return err
+	}
+
+	var record *models.Record
+
+	err = dao.RunInTenantScope(func(scopedDao *daos.Dao) error {
+		record, err = scopedDao.FindRecordById(collection.Id, id)
+		return err
+	})
 	if err != nil || record == nil {
 		return NewNotFoundError("", err)
 	}
@@ -656,6 +902,219 @@ func (api *recordAuthApi) unlinkExternalAuth(c echo.Context) error {
 	})
 }
 
+func (api *recordAuthApi) requestWebAuthnRegistration(c echo.Context) error {
+	record, _ := c.Get(ContextAuthRecordKey).(*models.Record)
+	if record == nil {
+		return NewNotFoundError("Missing auth record context.", nil)
+	}
+
+	if !record.Collection().AuthOptions().AllowWebAuthnAuth {
+		return NewBadRequestError("The collection is not configured to allow WebAuthn authentication.", nil)
+	}
+
+	form := forms.NewRecordWebAuthnRegistration(api.app, record, api.webauthnConfig(record.Collection()))
+
+	options, err := form.CreationOptions()
+	if err != nil {
+		return NewBadRequestError("Failed to create the WebAuthn registration options.", err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{"publicKey": options})
+}
+
+func (api *recordAuthApi) confirmWebAuthnRegistration(c echo.Context) error {
+	record, _ := c.Get(ContextAuthRecordKey).(*models.Record)
+	if record == nil {
+		return NewNotFoundError("Missing auth record context.", nil)
+	}
+
+	if !record.Collection().AuthOptions().AllowWebAuthnAuth {
+		return NewBadRequestError("The collection is not configured to allow WebAuthn authentication.", nil)
+	}
+
+	form := forms.NewRecordWebAuthnRegistration(api.app, record, api.webauthnConfig(record.Collection()))
+	if readErr := c.Bind(form); readErr != nil {
+		return NewBadRequestError("An error occurred while loading the submitted data.", readErr)
+	}
+
+	credential, submitErr := form.Submit()
+	if submitErr != nil {
+		return NewBadRequestError("Failed to register the WebAuthn credential.", submitErr)
+	}
+
+	return c.JSON(http.StatusOK, credential)
+}
+
+func (api *recordAuthApi) requestWebAuthnLogin(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("Missing collection context.", nil)
+	}
+
+	if !collection.AuthOptions().AllowWebAuthnAuth {
+		return NewBadRequestError("The collection is not configured to allow WebAuthn authentication.", nil)
+	}
+
+	form := forms.NewRecordWebAuthnLogin(api.app, collection, api.webauthnConfig(collection))
+
+	options, err := form.RequestOptions()
+	if err != nil {
+		return NewBadRequestError("Failed to create the WebAuthn login options.", err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{"publicKey": options})
+}
+
+func (api *recordAuthApi) authWithWebAuthn(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("Missing collection context.", nil)
+	}
+
+	if !collection.AuthOptions().AllowWebAuthnAuth {
+		return NewBadRequestError("The collection is not configured to allow WebAuthn authentication.", nil)
+	}
+
+	dao, err := RequestTenantDao(c, api.app, collection)
+	if err != nil {
+		return err
+	}
+
+	form := forms.NewRecordWebAuthnLogin(api.app, collection, api.webauthnConfig(collection))
+	if readErr := c.Bind(form); readErr != nil {
+		return NewBadRequestError("An error occurred while loading the submitted data.", readErr)
+	}
+
+	event := new(core.RecordAuthWithWebAuthnEvent)
+	event.HttpContext = c
+	event.Collection = collection
+
+	return dao.RunInTenantScope(func(scopedDao *daos.Dao) error {
+		form.SetDao(scopedDao)
+
+		_, submitErr := form.Submit(func(next forms.InterceptorNextFunc[*forms.RecordWebAuthnLoginData]) forms.InterceptorNextFunc[*forms.RecordWebAuthnLoginData] {
+			return func(data *forms.RecordWebAuthnLoginData) error {
+				event.Record = data.Record
+				event.Credential = data.Credential
+
+				return api.app.OnRecordBeforeAuthWithWebAuthnRequest().Trigger(event, func(e *core.RecordAuthWithWebAuthnEvent) error {
+					data.Record = e.Record
+
+					if err := next(data); err != nil {
+						return NewBadRequestError("Failed to authenticate.", err)
+					}
+
+					return api.app.OnRecordAfterAuthWithWebAuthnRequest().Trigger(event, func(e *core.RecordAuthWithWebAuthnEvent) error {
+						return RecordAuthResponse(api.app, e.HttpContext, e.Record, nil)
+					})
+				})
+			}
+		})
+
+		return submitErr
+	})
+}
+
+func (api *recordAuthApi) listWebAuthnCredentials(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("Missing collection context.", nil)
+	}
+
+	id := c.PathParam("id")
+	if id == "" {
+		return NewNotFoundError("", nil)
+	}
+
+	dao, err := RequestTenantDao(c, api.app, collection)
+	if err != nil {
+		return err
+	}
+
+	var record *models.Record
+
+	err = dao.RunInTenantScope(func(scopedDao *daos.Dao) error {
+		record, err = scopedDao.FindRecordById(collection.Id, id)
+		return err
+	})
+	if err != nil || record == nil {
+		return NewNotFoundError("", err)
+	}
+
+	credentials, err := api.app.Dao().FindAllWebAuthnCredentialsByRecord(record)
+	if err != nil {
+		return NewBadRequestError("Failed to fetch the WebAuthn credentials for the specified auth record.", err)
+	}
+
+	return c.JSON(http.StatusOK, credentials)
+}
+
+func (api *recordAuthApi) deleteWebAuthnCredential(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("Missing collection context.", nil)
+	}
+
+	id := c.PathParam("id")
+	credentialId := c.PathParam("credentialId")
+	if id == "" || credentialId == "" {
+		return NewNotFoundError("", nil)
+	}
+
+	dao, err := RequestTenantDao(c, api.app, collection)
+	if err != nil {
+		return err
+	}
+
+	var record *models.Record
+
+	err = dao.RunInTenantScope(func(scopedDao *daos.Dao) error {
+		record, err = scopedDao.FindRecordById(collection.Id, id)
+		return err
+	})
+	if err != nil || record == nil {
+		return NewNotFoundError("", err)
+	}
+
+	credential, err := api.app.Dao().FindWebAuthnCredentialByRecordAndId(record, credentialId)
+	if err != nil {
+		return NewNotFoundError("Missing WebAuthn credential.", err)
+	}
+
+	if err := api.app.Dao().DeleteWebAuthnCredential(credential); err != nil {
+		return NewBadRequestError("Cannot delete the WebAuthn credential.", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// webauthnConfig returns the WebAuthn relying party config of the
+// specified auth collection.
+//
+// If not explicitly set, the relying party id and origin
+// are resolved from the application url.
+func (api *recordAuthApi) webauthnConfig(collection *models.Collection) webauthn.Config {
+	authOptions := collection.AuthOptions()
+
+	config := webauthn.Config{
+		RPId:    authOptions.WebAuthnRPId,
+		RPName:  api.app.Settings().Meta.AppName,
+		Origins: authOptions.WebAuthnOrigins,
+	}
+
+	appUrl, _ := url.Parse(api.app.Settings().Meta.AppUrl)
+
+	if config.RPId == "" && appUrl != nil {
+		config.RPId = appUrl.Hostname()
+	}
+
+	if len(config.Origins) == 0 && appUrl != nil {
+		config.Origins = []string{appUrl.Scheme + "://" + appUrl.Host}
+	}
+
+	return config
+}
+
 // -------------------------------------------------------------------
 
 const (
@@ -1,18 +1,26 @@
 package apis_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/AlperRehaYAZGAN/postgresbase/apis"
 	"github.com/AlperRehaYAZGAN/postgresbase/core"
 	"github.com/AlperRehaYAZGAN/postgresbase/daos"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
 	"github.com/AlperRehaYAZGAN/postgresbase/tests"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/hook"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/subscriptions"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/types"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/webauthn"
 	"github.com/labstack/echo/v5"
 )
 
@@ -43,6 +51,8 @@ func TestRecordAuthMethodsList(t *testing.T) {
 				`"usernamePassword":true`,
 				`"emailPassword":true`,
 				`"onlyVerified":false`,
+				`"otp":false`,
+				`"webauthn":false`,
 				`"authProviders":[{`,
 				`"name":"gitlab"`,
 				`"state":`,
@@ -359,6 +369,81 @@ func TestRecordAuthWithPassword(t *testing.T) {
 	}
 }
 
+func TestRecordAuthWithPasswordMultiTenant(t *testing.T) {
+	t.Parallel()
+
+	enableTenantIsolation := func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+		_, err := tests.EnableTestTenantIsolation(app, "users", map[string]string{
+			"oap640cot4yru2s": "acme",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:   "without tenant",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/auth-with-password",
+			Body: strings.NewReader(`{
+				"identity":"test2_username",
+				"password":"1234567890"
+			}`),
+			BeforeTestFunc:  enableTenantIsolation,
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "with different tenant",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/auth-with-password",
+			Body: strings.NewReader(`{
+				"identity":"test2_username",
+				"password":"1234567890"
+			}`),
+			RequestHeaders: map[string]string{
+				"X-Tenant": "other",
+			},
+			BeforeTestFunc:  enableTenantIsolation,
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents: map[string]int{
+				"OnRecordBeforeAuthWithPasswordRequest": 1,
+			},
+		},
+		{
+			Name:   "with the record tenant",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/auth-with-password?expand=rel",
+			Body: strings.NewReader(`{
+				"identity":"test2_username",
+				"password":"1234567890"
+			}`),
+			RequestHeaders: map[string]string{
+				"X-Tenant": "acme",
+			},
+			BeforeTestFunc: enableTenantIsolation,
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"record":{`,
+				`"token":"`,
+				`"id":"oap640cot4yru2s"`,
+				`"email":"test2@example.com"`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnRecordBeforeAuthWithPasswordRequest": 1,
+				"OnRecordAfterAuthWithPasswordRequest":  1,
+				"OnRecordAuthRequest":                   1,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
 func TestRecordAuthRefresh(t *testing.T) {
 	t.Parallel()
 
@@ -488,6 +573,277 @@ func TestRecordAuthRefresh(t *testing.T) {
 	}
 }
 
+// enableTestOTPAuth enables the OTP auth for the specified test collection.
+func enableTestOTPAuth(t *testing.T, app *tests.TestApp, collectionName string) {
+	collection, err := app.Dao().FindCollectionByNameOrId(collectionName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := collection.AuthOptions()
+	options.AllowOTPAuth = true
+	collection.SetOptions(options)
+
+	dao := daos.New(app.Dao().DB()) // new dao to ignore hooks
+	if err := dao.SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := core.ReloadCachedCollections(app); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// createTestOTP creates a new OTP with "123456" password
+// for the specified test auth record.
+func createTestOTP(t *testing.T, app *tests.TestApp, collectionName string, email string) *models.OTP {
+	record, err := app.Dao().FindAuthRecordByEmail(collectionName, email)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otp := &models.OTP{
+		CollectionId: record.Collection().Id,
+		RecordId:     record.Id,
+		Email:        record.Email(),
+	}
+	otp.Id = "test_otp_id"
+	otp.MarkAsNew()
+	if err := otp.SetPassword("123456"); err != nil {
+		t.Fatal(err)
+	}
+
+	dao := daos.New(app.Dao().DB()) // new dao to ignore hooks
+	if err := dao.SaveOTP(otp); err != nil {
+		t.Fatal(err)
+	}
+
+	return otp
+}
+
+func TestRecordAuthRequestOTP(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "not an auth collection",
+			Method:          http.MethodPost,
+			Url:             "/api/collections/demo1/request-otp",
+			Body:            strings.NewReader(`{"email":"test@example.com"}`),
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:            "OTP auth not allowed",
+			Method:          http.MethodPost,
+			Url:             "/api/collections/users/request-otp",
+			Body:            strings.NewReader(`{"email":"test@example.com"}`),
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:            "empty data",
+			Method:          http.MethodPost,
+			Url:             "/api/collections/users/request-otp",
+			Body:            strings.NewReader(``),
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{"email":{"code":"validation_required","message":"Cannot be blank."}}`},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				enableTestOTPAuth(t, app, "users")
+			},
+		},
+		{
+			Name:            "invalid data",
+			Method:          http.MethodPost,
+			Url:             "/api/collections/users/request-otp",
+			Body:            strings.NewReader(`{"email`),
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				enableTestOTPAuth(t, app, "users")
+			},
+		},
+		{
+			Name:            "missing auth record",
+			Method:          http.MethodPost,
+			Url:             "/api/collections/users/request-otp",
+			Body:            strings.NewReader(`{"email":"missing@example.com"}`),
+			Delay:           100 * time.Millisecond,
+			ExpectedStatus:  200,
+			ExpectedContent: []string{`"otpId":"`},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				enableTestOTPAuth(t, app, "users")
+			},
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, res *http.Response) {
+				if app.TestMailer.TotalSend != 0 {
+					t.Fatalf("Expected no emails to be sent, got %d", app.TestMailer.TotalSend)
+				}
+			},
+		},
+		{
+			Name:            "existing auth record",
+			Method:          http.MethodPost,
+			Url:             "/api/collections/users/request-otp",
+			Body:            strings.NewReader(`{"email":"test@example.com"}`),
+			Delay:           100 * time.Millisecond,
+			ExpectedStatus:  200,
+			ExpectedContent: []string{`"otpId":"`},
+			ExpectedEvents: map[string]int{
+				"OnModelBeforeCreate":         1,
+				"OnModelAfterCreate":          1,
+				"OnMailerBeforeRecordOTPSend": 1,
+				"OnMailerAfterRecordOTPSend":  1,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				enableTestOTPAuth(t, app, "users")
+			},
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, res *http.Response) {
+				body := struct {
+					OTPId string `json:"otpId"`
+				}{}
+				if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+					t.Fatal(err)
+				}
+
+				if _, err := app.Dao().FindOTPById(body.OTPId); err != nil {
+					t.Fatalf("Expected the returned OTP to exist, got %v", err)
+				}
+
+				// the default template has only the code (without login link)
+				if strings.Contains(app.TestMailer.LastMessage.HTML, body.OTPId) {
+					t.Fatalf("Didn't expect the OTP id to be part of the email, got\n%s", app.TestMailer.LastMessage.HTML)
+				}
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestRecordAuthWithOTP(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "not an auth collection",
+			Method:          http.MethodPost,
+			Url:             "/api/collections/demo1/auth-with-otp",
+			Body:            strings.NewReader(`{"otpId":"test_otp_id","password":"123456"}`),
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:            "OTP auth not allowed",
+			Method:          http.MethodPost,
+			Url:             "/api/collections/users/auth-with-otp",
+			Body:            strings.NewReader(`{"otpId":"test_otp_id","password":"123456"}`),
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				createTestOTP(t, app, "users", "test@example.com")
+			},
+		},
+		{
+			Name:           "empty data",
+			Method:         http.MethodPost,
+			Url:            "/api/collections/users/auth-with-otp",
+			Body:           strings.NewReader(``),
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"otpId":{"code":"validation_required"`,
+				`"password":{"code":"validation_required"`,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				enableTestOTPAuth(t, app, "users")
+			},
+		},
+		{
+			Name:            "invalid password",
+			Method:          http.MethodPost,
+			Url:             "/api/collections/users/auth-with-otp",
+			Body:            strings.NewReader(`{"otpId":"test_otp_id","password":"654321"}`),
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				enableTestOTPAuth(t, app, "users")
+				createTestOTP(t, app, "users", "test@example.com")
+			},
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, res *http.Response) {
+				otp, err := app.Dao().FindOTPById("test_otp_id")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if otp.Attempts != 1 {
+					t.Fatalf("Expected 1 attempt, got %d", otp.Attempts)
+				}
+			},
+		},
+		{
+			Name:            "OTP from different collection",
+			Method:          http.MethodPost,
+			Url:             "/api/collections/users/auth-with-otp",
+			Body:            strings.NewReader(`{"otpId":"test_otp_id","password":"123456"}`),
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				enableTestOTPAuth(t, app, "users")
+				createTestOTP(t, app, "clients", "test@example.com")
+			},
+		},
+		{
+			Name:           "valid password",
+			Method:         http.MethodPost,
+			Url:            "/api/collections/users/auth-with-otp",
+			Body:           strings.NewReader(`{"otpId":"test_otp_id","password":"123456"}`),
+			ExpectedStatus: 204,
+			ExpectedEvents: map[string]int{
+				"OnRecordBeforeAuthWithOTPRequest": 1,
+				"OnRecordAfterAuthWithOTPRequest":  1,
+				// mark as verified
+				"OnModelBeforeUpdate": 1,
+				"OnModelAfterUpdate":  1,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				enableTestOTPAuth(t, app, "users")
+				createTestOTP(t, app, "users", "test@example.com")
+
+				app.OnRecordBeforeAuthWithOTPRequest().Add(func(e *core.RecordAuthWithOTPEvent) error {
+					if e.OTP == nil || e.OTP.Id != "test_otp_id" {
+						t.Fatalf("Expected the event OTP to be set, got %v", e.OTP)
+					}
+					return nil
+				})
+
+				// skip the auth token generation
+				app.OnRecordAfterAuthWithOTPRequest().Add(func(e *core.RecordAuthWithOTPEvent) error {
+					e.HttpContext.NoContent(http.StatusNoContent)
+					return hook.StopPropagation
+				})
+			},
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, res *http.Response) {
+				if _, err := app.Dao().FindOTPById("test_otp_id"); err == nil {
+					t.Fatal("Expected the OTP to be deleted")
+				}
+
+				record, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !record.Verified() {
+					t.Fatal("Expected the auth record to be verified")
+				}
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
 func TestRecordAuthRequestPasswordReset(t *testing.T) {
 	t.Parallel()
 
@@ -1709,3 +2065,574 @@ func TestRecordAuthOAuth2Redirect(t *testing.T) {
 		scenario.Test(t)
 	}
 }
+
+// enableTestWebAuthnAuth enables the WebAuthn auth for the specified test collection.
+func enableTestWebAuthnAuth(t *testing.T, app *tests.TestApp, collectionName string) {
+	collection, err := app.Dao().FindCollectionByNameOrId(collectionName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := collection.AuthOptions()
+	options.AllowWebAuthnAuth = true
+	collection.SetOptions(options)
+
+	dao := daos.New(app.Dao().DB()) // new dao to ignore hooks
+	if err := dao.SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := core.ReloadCachedCollections(app); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// mockRecordAuth returns a middleware that marks the request
+// as authenticated with the specified test auth record.
+func mockRecordAuth(app *tests.TestApp, collectionName string, email string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			record, err := app.Dao().FindAuthRecordByEmail(collectionName, email)
+			if err != nil {
+				return err
+			}
+
+			c.Set(apis.ContextAuthRecordKey, record)
+
+			return next(c)
+		}
+	}
+}
+
+// requestTestWebAuthnChallenge sends an internal WebAuthn options
+// request to the provided url and returns the generated challenge.
+func requestTestWebAuthnChallenge(t *testing.T, e *echo.Echo, url string) string {
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, url, nil))
+
+	body := struct {
+		PublicKey struct {
+			Challenge string `json:"challenge"`
+		} `json:"publicKey"`
+	}{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil || body.PublicKey.Challenge == "" {
+		t.Fatalf("Failed to request WebAuthn challenge: %v\n%s", err, recorder.Body.String())
+	}
+
+	return body.PublicKey.Challenge
+}
+
+// createTestWebAuthnCredential registers the authenticator
+// credential for the specified test auth record.
+func createTestWebAuthnCredential(
+	t *testing.T,
+	app *tests.TestApp,
+	authenticator *tests.TestAuthenticator,
+	collectionName string,
+	email string,
+) *models.WebAuthnCredential {
+	record, err := app.Dao().FindAuthRecordByEmail(collectionName, email)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authenticator.UserHandle = []byte(record.Id)
+
+	credential := &models.WebAuthnCredential{
+		CollectionId: record.Collection().Id,
+		RecordId:     record.Id,
+		Name:         "test",
+		CredentialId: webauthn.EncodeBase64(authenticator.CredentialId),
+		PublicKey:    webauthn.EncodeBase64(authenticator.PublicKey()),
+		SignCount:    int64(authenticator.SignCount),
+	}
+	credential.Id = "test_webauthn_id"
+	credential.MarkAsNew()
+
+	dao := daos.New(app.Dao().DB()) // new dao to ignore hooks
+	if err := dao.SaveWebAuthnCredential(credential); err != nil {
+		t.Fatal(err)
+	}
+
+	return credential
+}
+
+func newTestAuthenticator(t *testing.T) *tests.TestAuthenticator {
+	authenticator, err := tests.NewTestAuthenticator("localhost", "http://localhost:8090")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return authenticator
+}
+
+func TestRecordAuthRequestWebAuthnRegistration(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "unauthorized",
+			Method:          http.MethodPost,
+			Url:             "/api/collections/users/request-webauthn-registration",
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:            "WebAuthn auth not allowed",
+			Method:          http.MethodPost,
+			Url:             "/api/collections/users/request-webauthn-registration",
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				e.Pre(mockRecordAuth(app, "users", "test@example.com"))
+			},
+		},
+		{
+			Name:            "auth record from different collection",
+			Method:          http.MethodPost,
+			Url:             "/api/collections/users/request-webauthn-registration",
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				enableTestWebAuthnAuth(t, app, "users")
+				e.Pre(mockRecordAuth(app, "clients", "test@example.com"))
+			},
+		},
+		{
+			Name:           "WebAuthn auth allowed",
+			Method:         http.MethodPost,
+			Url:            "/api/collections/users/request-webauthn-registration",
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"challenge":"`,
+				`"rp":{"id":"localhost","name":"acme_test"}`,
+				`"user":{"id":"NHExeGxjbG1mbG9rdTMz","name":"test@example.com","displayName":"test@example.com"}`,
+				`"excludeCredentials":[{"type":"public-key","id":"`,
+				`"residentKey":"required"`,
+				`"attestation":"none"`,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				enableTestWebAuthnAuth(t, app, "users")
+				createTestWebAuthnCredential(t, app, newTestAuthenticator(t), "users", "test@example.com")
+				e.Pre(mockRecordAuth(app, "users", "test@example.com"))
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestRecordAuthConfirmWebAuthnRegistration(t *testing.T) {
+	t.Parallel()
+
+	authenticator := newTestAuthenticator(t)
+
+	validBody := new(bytes.Buffer)
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "unauthorized",
+			Method:          http.MethodPost,
+			Url:             "/api/collections/users/confirm-webauthn-registration",
+			Body:            strings.NewReader(`{}`),
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:            "WebAuthn auth not allowed",
+			Method:          http.MethodPost,
+			Url:             "/api/collections/users/confirm-webauthn-registration",
+			Body:            strings.NewReader(`{}`),
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				e.Pre(mockRecordAuth(app, "users", "test@example.com"))
+			},
+		},
+		{
+			Name:           "empty data",
+			Method:         http.MethodPost,
+			Url:            "/api/collections/users/confirm-webauthn-registration",
+			Body:           strings.NewReader(`{}`),
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"rawId":{"code":"validation_required"`,
+				`"type":{"code":"validation_required"`,
+				`"clientDataJSON":{"code":"validation_required"`,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				enableTestWebAuthnAuth(t, app, "users")
+				e.Pre(mockRecordAuth(app, "users", "test@example.com"))
+			},
+		},
+		{
+			Name:   "unknown challenge",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/confirm-webauthn-registration",
+			Body: func() io.Reader {
+				raw, _ := json.Marshal(map[string]any{"credential": authenticator.Register(webauthn.NewChallenge())})
+				return bytes.NewReader(raw)
+			}(),
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				enableTestWebAuthnAuth(t, app, "users")
+				e.Pre(mockRecordAuth(app, "users", "test@example.com"))
+			},
+		},
+		{
+			Name:           "valid registration",
+			Method:         http.MethodPost,
+			Url:            "/api/collections/users/confirm-webauthn-registration",
+			Body:           validBody,
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"name":"My key"`,
+				`"recordId":"4q1xlclmfloku33"`,
+				`"credentialId":"` + webauthn.EncodeBase64(authenticator.CredentialId) + `"`,
+			},
+			NotExpectedContent: []string{
+				`"publicKey"`,
+				`"signCount"`,
+			},
+			ExpectedEvents: map[string]int{
+				// used challenge + new credential
+				"OnModelBeforeCreate": 2,
+				"OnModelAfterCreate":  2,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				enableTestWebAuthnAuth(t, app, "users")
+				e.Pre(mockRecordAuth(app, "users", "test@example.com"))
+
+				challenge := requestTestWebAuthnChallenge(t, e, "/api/collections/users/request-webauthn-registration")
+
+				raw, _ := json.Marshal(map[string]any{
+					"name":       "My key",
+					"credential": authenticator.Register(challenge),
+				})
+				validBody.Write(raw)
+			},
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, res *http.Response) {
+				collection, _ := app.Dao().FindCollectionByNameOrId("users")
+
+				credential, err := app.Dao().FindWebAuthnCredentialByCredentialId(collection.Id, webauthn.EncodeBase64(authenticator.CredentialId))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if credential.PublicKey != webauthn.EncodeBase64(authenticator.PublicKey()) {
+					t.Fatalf("Expected the authenticator public key to be stored, got %q", credential.PublicKey)
+				}
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestRecordAuthRequestWebAuthnLogin(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "not an auth collection",
+			Method:          http.MethodPost,
+			Url:             "/api/collections/demo1/request-webauthn-login",
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:            "WebAuthn auth not allowed",
+			Method:          http.MethodPost,
+			Url:             "/api/collections/users/request-webauthn-login",
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:           "WebAuthn auth allowed",
+			Method:         http.MethodPost,
+			Url:            "/api/collections/users/request-webauthn-login",
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"challenge":"`,
+				`"rpId":"localhost"`,
+				`"allowCredentials":[]`,
+				`"userVerification":"preferred"`,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				enableTestWebAuthnAuth(t, app, "users")
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestRecordAuthWithWebAuthn(t *testing.T) {
+	t.Parallel()
+
+	// prepareLogin registers a new test authenticator credential
+	// and writes its login response for a new challenge into body
+	prepareLogin := func(t *testing.T, app *tests.TestApp, e *echo.Echo, body *bytes.Buffer, signCount uint32) {
+		enableTestWebAuthnAuth(t, app, "users")
+
+		authenticator := newTestAuthenticator(t)
+		authenticator.SignCount = signCount
+		createTestWebAuthnCredential(t, app, authenticator, "users", "test@example.com")
+
+		challenge := requestTestWebAuthnChallenge(t, e, "/api/collections/users/request-webauthn-login")
+
+		// reset the authenticator counter (aka. simulate a cloned
+		// authenticator in case of a non-zero stored signCount)
+		authenticator.SignCount = 0
+
+		credential, err := authenticator.Login(challenge)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		raw, _ := json.Marshal(map[string]any{"credential": credential})
+		body.Write(raw)
+	}
+
+	validBody := new(bytes.Buffer)
+	clonedBody := new(bytes.Buffer)
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "not an auth collection",
+			Method:          http.MethodPost,
+			Url:             "/api/collections/demo1/auth-with-webauthn",
+			Body:            strings.NewReader(`{}`),
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:            "WebAuthn auth not allowed",
+			Method:          http.MethodPost,
+			Url:             "/api/collections/users/auth-with-webauthn",
+			Body:            strings.NewReader(`{}`),
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:           "empty data",
+			Method:         http.MethodPost,
+			Url:            "/api/collections/users/auth-with-webauthn",
+			Body:           strings.NewReader(`{}`),
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"rawId":{"code":"validation_required"`,
+				`"clientDataJSON":{"code":"validation_required"`,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				enableTestWebAuthnAuth(t, app, "users")
+			},
+		},
+		{
+			Name:   "unknown challenge",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/auth-with-webauthn",
+			Body: func() io.Reader {
+				authenticator := newTestAuthenticator(t)
+				credential, _ := authenticator.Login(webauthn.NewChallenge())
+				raw, _ := json.Marshal(map[string]any{"credential": credential})
+				return bytes.NewReader(raw)
+			}(),
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				enableTestWebAuthnAuth(t, app, "users")
+			},
+		},
+		{
+			Name:            "non-increasing signature counter",
+			Method:          http.MethodPost,
+			Url:             "/api/collections/users/auth-with-webauthn",
+			Body:            clonedBody,
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				prepareLogin(t, app, e, clonedBody, 5)
+			},
+		},
+		{
+			Name:           "valid assertion",
+			Method:         http.MethodPost,
+			Url:            "/api/collections/users/auth-with-webauthn",
+			Body:           validBody,
+			ExpectedStatus: 204,
+			ExpectedEvents: map[string]int{
+				"OnRecordBeforeAuthWithWebAuthnRequest": 1,
+				"OnRecordAfterAuthWithWebAuthnRequest":  1,
+				// used challenge
+				"OnModelBeforeCreate": 1,
+				"OnModelAfterCreate":  1,
+				// sign count update
+				"OnModelBeforeUpdate": 1,
+				"OnModelAfterUpdate":  1,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				prepareLogin(t, app, e, validBody, 0)
+
+				app.OnRecordBeforeAuthWithWebAuthnRequest().Add(func(e *core.RecordAuthWithWebAuthnEvent) error {
+					if e.Credential == nil || e.Credential.Id != "test_webauthn_id" {
+						t.Fatalf("Expected the event credential to be set, got %v", e.Credential)
+					}
+					if e.Record == nil || e.Record.Id != "4q1xlclmfloku33" {
+						t.Fatalf("Expected the event record to be set, got %v", e.Record)
+					}
+					return nil
+				})
+
+				// skip the auth token generation
+				app.OnRecordAfterAuthWithWebAuthnRequest().Add(func(e *core.RecordAuthWithWebAuthnEvent) error {
+					e.HttpContext.NoContent(http.StatusNoContent)
+					return hook.StopPropagation
+				})
+			},
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, res *http.Response) {
+				record, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				credential, err := app.Dao().FindWebAuthnCredentialByRecordAndId(record, "test_webauthn_id")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if credential.SignCount != 1 {
+					t.Fatalf("Expected sign count 1, got %d", credential.SignCount)
+				}
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestRecordAuthWebAuthnCredentialsList(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "unauthorized",
+			Method:          http.MethodGet,
+			Url:             "/api/collections/users/records/4q1xlclmfloku33/webauthn-credentials",
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:            "authorized as different user",
+			Method:          http.MethodGet,
+			Url:             "/api/collections/users/records/4q1xlclmfloku33/webauthn-credentials",
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				createTestWebAuthnCredential(t, app, newTestAuthenticator(t), "users", "test@example.com")
+				e.Pre(mockRecordAuth(app, "users", "test2@example.com"))
+			},
+		},
+		{
+			Name:           "authorized as owner",
+			Method:         http.MethodGet,
+			Url:            "/api/collections/users/records/4q1xlclmfloku33/webauthn-credentials",
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"id":"test_webauthn_id"`,
+				`"recordId":"4q1xlclmfloku33"`,
+			},
+			NotExpectedContent: []string{
+				`"publicKey"`,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				createTestWebAuthnCredential(t, app, newTestAuthenticator(t), "users", "test@example.com")
+				e.Pre(mockRecordAuth(app, "users", "test@example.com"))
+			},
+		},
+		{
+			Name:            "authorized as admin",
+			Method:          http.MethodGet,
+			Url:             "/api/collections/users/records/4q1xlclmfloku33/webauthn-credentials",
+			ExpectedStatus:  200,
+			ExpectedContent: []string{`"id":"test_webauthn_id"`},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				createTestWebAuthnCredential(t, app, newTestAuthenticator(t), "users", "test@example.com")
+				e.Pre(mockAdminAuth(app))
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestRecordAuthDeleteWebAuthnCredential(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "unauthorized",
+			Method:          http.MethodDelete,
+			Url:             "/api/collections/users/records/4q1xlclmfloku33/webauthn-credentials/test_webauthn_id",
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:            "authorized as different user",
+			Method:          http.MethodDelete,
+			Url:             "/api/collections/users/records/4q1xlclmfloku33/webauthn-credentials/test_webauthn_id",
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				createTestWebAuthnCredential(t, app, newTestAuthenticator(t), "users", "test@example.com")
+				e.Pre(mockRecordAuth(app, "users", "test2@example.com"))
+			},
+		},
+		{
+			Name:            "missing credential",
+			Method:          http.MethodDelete,
+			Url:             "/api/collections/users/records/4q1xlclmfloku33/webauthn-credentials/missing",
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				e.Pre(mockRecordAuth(app, "users", "test@example.com"))
+			},
+		},
+		{
+			Name:           "authorized as owner",
+			Method:         http.MethodDelete,
+			Url:            "/api/collections/users/records/4q1xlclmfloku33/webauthn-credentials/test_webauthn_id",
+			ExpectedStatus: 204,
+			ExpectedEvents: map[string]int{
+				"OnModelBeforeDelete": 1,
+				"OnModelAfterDelete":  1,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				createTestWebAuthnCredential(t, app, newTestAuthenticator(t), "users", "test@example.com")
+				e.Pre(mockRecordAuth(app, "users", "test@example.com"))
+			},
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, res *http.Response) {
+				record, _ := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+
+				if _, err := app.Dao().FindWebAuthnCredentialByRecordAndId(record, "test_webauthn_id"); err == nil {
+					t.Fatal("Expected the credential to be deleted")
+				}
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
@@ -54,24 +54,35 @@ func (api *recordApi) list(c echo.Context) error {
 		return NewForbiddenError("Only admins can perform this action.", nil)
 	}
 
-	fieldsResolver := resolvers.NewRecordFieldResolver(
-		api.app.Dao(),
-		collection,
-		requestInfo,
-		// hidden fields are searchable only by admins
-		requestInfo.Admin != nil,
-	)
-
-	searchProvider := search.NewProvider(fieldsResolver).
-		Query(api.app.Dao().RecordQuery(collection))
-
-	if requestInfo.Admin == nil && collection.ListRule != nil {
-		searchProvider.AddFilter(search.FilterData(*collection.ListRule))
+	dao, err := RequestTenantDao(c, api.app, collection)
+	if err != nil {
+		return err
 	}
 
 	records := []*models.Record{}
 
-	result, err := searchProvider.ParseAndExec(c.QueryParams().Encode(), &records)
+	var result *search.Result
+
+	err = dao.RunInTenantScope(func(scopedDao *daos.Dao) error {
+		fieldsResolver := resolvers.NewRecordFieldResolver(
+			scopedDao,
+			collection,
+			requestInfo,
+			// hidden fields are searchable only by admins
+			requestInfo.Admin != nil,
+		)
+
+		searchProvider := search.NewProvider(fieldsResolver).
+			Query(scopedDao.RecordQuery(collection).WithContext(c.Request().Context()))
+
+		if requestInfo.Admin == nil && collection.ListRule != nil {
+			searchProvider.AddFilter(search.FilterData(*collection.ListRule))
+		}
+
+		var execErr error
+		result, execErr = searchProvider.ParseAndExec(c.QueryParams().Encode(), &records)
+		return execErr
+	})
 	if err != nil {
 		return NewBadRequestError("", err)
 	}
@@ -87,7 +98,10 @@ func (api *recordApi) list(c echo.Context) error {
 			return nil
 		}
 
-		if err := EnrichRecords(e.HttpContext, api.app.Dao(), e.Records); err != nil {
+		err := dao.RunInTenantScope(func(scopedDao *daos.Dao) error {
+			return EnrichRecords(e.HttpContext, scopedDao, e.Records)
+		})
+		if err != nil {
 			api.app.Logger().Debug("Failed to enrich list records", slog.String("error", err.Error()))
 		}
 
@@ -113,20 +127,31 @@ func (api *recordApi) view(c echo.Context) error {
 		return NewForbiddenError("Only admins can perform this action.", nil)
 	}
 
-	ruleFunc := func(q *dbx.SelectQuery) error {
-		if requestInfo.Admin == nil && collection.ViewRule != nil && *collection.ViewRule != "" {
-			resolver := resolvers.NewRecordFieldResolver(api.app.Dao(), collection, requestInfo, true)
-			expr, err := search.FilterData(*collection.ViewRule).BuildExpr(resolver)
-			if err != nil {
-				return err
+	dao, err := RequestTenantDao(c, api.app, collection)
+	if err != nil {
+		return err
+	}
+
+	var record *models.Record
+
+	fetchErr := dao.RunInTenantScope(func(scopedDao *daos.Dao) error {
+		ruleFunc := func(q *dbx.SelectQuery) error {
+			if requestInfo.Admin == nil && collection.ViewRule != nil && *collection.ViewRule != "" {
+				resolver := resolvers.NewRecordFieldResolver(scopedDao, collection, requestInfo, true)
+				expr, err := search.FilterData(*collection.ViewRule).BuildExpr(resolver)
+				if err != nil {
+					return err
+				}
+				resolver.UpdateQuery(q)
+				q.AndWhere(expr)
 			}
-			resolver.UpdateQuery(q)
-			q.AndWhere(expr)
+			return nil
 		}
-		return nil
-	}
 
-	record, fetchErr := api.app.Dao().FindRecordById(collection.Id, recordId, ruleFunc)
+		var err error
+		record, err = scopedDao.FindRecordById(collection.Id, recordId, ruleFunc)
+		return err
+	})
 	if fetchErr != nil || record == nil {
 		return NewNotFoundError("", fetchErr)
 	}
@@ -141,7 +166,10 @@ func (api *recordApi) view(c echo.Context) error {
 			return nil
 		}
 
-		if err := EnrichRecord(e.HttpContext, api.app.Dao(), e.Record); err != nil {
+		err := dao.RunInTenantScope(func(scopedDao *daos.Dao) error {
+			return EnrichRecord(e.HttpContext, scopedDao, e.Record)
+		})
+		if err != nil {
 			api.app.Logger().Debug(
 				"Failed to enrich view record",
 				slog.String("id", e.Record.Id),
@@ -167,6 +195,11 @@ func (api *recordApi) create(c echo.Context) error {
 		return NewForbiddenError("Only admins can perform this action.", nil)
 	}
 
+	dao, err := RequestTenantDao(c, api.app, collection)
+	if err != nil {
+		return err
+	}
+
 	hasFullManageAccess := requestInfo.Admin != nil
 
 	// temporary save the record and check it against the create rule
@@ -180,27 +213,28 @@ func (api *recordApi) create(c echo.Context) error {
 		}
 
 		testForm := forms.NewRecordUpsert(api.app, testRecord)
+		testForm.SetDao(dao)
 		testForm.SetFullManageAccess(true)
 		if err := testForm.LoadRequest(c.Request(), ""); err != nil {
 			return NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
 		}
 
-		createRuleFunc := func(q *dbx.SelectQuery) error {
-			if *collection.CreateRule == "" {
-				return nil // no create rule to resolve
-			}
+		testErr := testForm.DrySubmit(func(txDao *daos.Dao) error {
+			createRuleFunc := func(q *dbx.SelectQuery) error {
+				if *collection.CreateRule == "" {
+					return nil // no create rule to resolve
+				}
 
-			resolver := resolvers.NewRecordFieldResolver(api.app.Dao(), collection, requestInfo, true)
-			expr, err := search.FilterData(*collection.CreateRule).BuildExpr(resolver)
-			if err != nil {
-				return err
+				resolver := resolvers.NewRecordFieldResolver(txDao, collection, requestInfo, true)
+				expr, err := search.FilterData(*collection.CreateRule).BuildExpr(resolver)
+				if err != nil {
+					return err
+				}
+				resolver.UpdateQuery(q)
+				q.AndWhere(expr)
+				return nil
 			}
-			resolver.UpdateQuery(q)
-			q.AndWhere(expr)
-			return nil
-		}
 
-		testErr := testForm.DrySubmit(func(txDao *daos.Dao) error {
 			foundRecord, err := txDao.FindRecordById(collection.Id, testRecord.Id, createRuleFunc)
 			if err != nil {
 				return fmt.Errorf("DrySubmit create rule failure: %w", err)
@@ -230,33 +264,45 @@ func (api *recordApi) create(c echo.Context) error {
 	event.UploadedFiles = form.FilesToUpload()
 
 	// create the record
-	return form.Submit(func(next forms.InterceptorNextFunc[*models.Record]) forms.InterceptorNextFunc[*models.Record] {
-		return func(m *models.Record) error {
-			event.Record = m
+	// (the tenant transaction is started only after the request data is loaded)
+	return dao.RunInTenantScope(func(scopedDao *daos.Dao) error {
+		form.SetDao(scopedDao)
 
-			return api.app.OnRecordBeforeCreateRequest().Trigger(event, func(e *core.RecordCreateEvent) error {
-				if err := next(e.Record); err != nil {
-					return NewBadRequestError("Failed to create record.", err)
-				}
+		return form.Submit(func(next forms.InterceptorNextFunc[*models.Record]) forms.InterceptorNextFunc[*models.Record] {
+			return func(m *models.Record) error {
+				event.Record = m
 
-				if err := EnrichRecord(e.HttpContext, api.app.Dao(), e.Record); err != nil {
-					api.app.Logger().Debug(
-						"Failed to enrich create record",
-						slog.String("id", e.Record.Id),
-						slog.String("collectionName", e.Record.Collection().Name),
-						slog.String("error", err.Error()),
-					)
-				}
+				return api.app.OnRecordBeforeCreateRequest().Trigger(event, func(e *core.RecordCreateEvent) error {
+					err := next(e.Record)
+
+					// store the file scan results for the request log (if any)
+					if scans := form.FileScans(); len(scans) > 0 {
+						e.HttpContext.Set(ContextFileScansKey, scans)
+					}
+
+					if err != nil {
+						return NewBadRequestError("Failed to create record.", err)
+					}
 
-				return api.app.OnRecordAfterCreateRequest().Trigger(event, func(e *core.RecordCreateEvent) error {
-					if e.HttpContext.Response().Committed {
-						return nil
+					if err := EnrichRecord(e.HttpContext, scopedDao, e.Record); err != nil {
+						api.app.Logger().Debug(
+							"Failed to enrich create record",
+							slog.String("id", e.Record.Id),
+							slog.String("collectionName", e.Record.Collection().Name),
+							slog.String("error", err.Error()),
+						)
 					}
 
-					return e.HttpContext.JSON(http.StatusOK, e.Record)
+					return api.app.OnRecordAfterCreateRequest().Trigger(event, func(e *core.RecordCreateEvent) error {
+						if e.HttpContext.Response().Committed {
+							return nil
+						}
+
+						return e.HttpContext.JSON(http.StatusOK, e.Record)
+					})
 				})
-			})
-		}
+			}
+		})
 	})
 }
 
@@ -278,37 +324,55 @@ func (api *recordApi) update(c echo.Context) error {
 		return NewForbiddenError("Only admins can perform this action.", nil)
 	}
 
-	// eager fetch the record so that the modifier field values are replaced
-	// and available when accessing requestInfo.Data using just the field name
-	if requestInfo.HasModifierDataKeys() {
-		record, err := api.app.Dao().FindRecordById(collection.Id, recordId)
-		if err != nil || record == nil {
-			return NewNotFoundError("", err)
-		}
-		requestInfo.Data = record.ReplaceModifers(requestInfo.Data)
+	dao, err := RequestTenantDao(c, api.app, collection)
+	if err != nil {
+		return err
 	}
 
-	ruleFunc := func(q *dbx.SelectQuery) error {
-		if requestInfo.Admin == nil && collection.UpdateRule != nil && *collection.UpdateRule != "" {
-			resolver := resolvers.NewRecordFieldResolver(api.app.Dao(), collection, requestInfo, true)
-			expr, err := search.FilterData(*collection.UpdateRule).BuildExpr(resolver)
+	var record *models.Record
+	var hasFullManageAccess bool
+
+	fetchErr := dao.RunInTenantScope(func(scopedDao *daos.Dao) error {
+		// eager fetch the record so that the modifier field values are replaced
+		// and available when accessing requestInfo.Data using just the field name
+		if requestInfo.HasModifierDataKeys() {
+			record, err := scopedDao.FindRecordById(collection.Id, recordId)
 			if err != nil {
 				return err
 			}
-			resolver.UpdateQuery(q)
-			q.AndWhere(expr)
+			requestInfo.Data = record.ReplaceModifers(requestInfo.Data)
 		}
-		return nil
-	}
 
-	// fetch record
-	record, fetchErr := api.app.Dao().FindRecordById(collection.Id, recordId, ruleFunc)
+		ruleFunc := func(q *dbx.SelectQuery) error {
+			if requestInfo.Admin == nil && collection.UpdateRule != nil && *collection.UpdateRule != "" {
+				resolver := resolvers.NewRecordFieldResolver(scopedDao, collection, requestInfo, true)
+				expr, err := search.FilterData(*collection.UpdateRule).BuildExpr(resolver)
+				if err != nil {
+					return err
+				}
+				resolver.UpdateQuery(q)
+				q.AndWhere(expr)
+			}
+			return nil
+		}
+
+		// fetch record
+		var err error
+		record, err = scopedDao.FindRecordById(collection.Id, recordId, ruleFunc)
+		if err != nil {
+			return err
+		}
+
+		hasFullManageAccess = requestInfo.Admin != nil || hasAuthManageAccess(scopedDao, record, requestInfo)
+
+		return nil
+	})
 	if fetchErr != nil || record == nil {
 		return NewNotFoundError("", fetchErr)
 	}
 
 	form := forms.NewRecordUpsert(api.app, record)
-	form.SetFullManageAccess(requestInfo.Admin != nil || hasAuthManageAccess(api.app.Dao(), record, requestInfo))
+	form.SetFullManageAccess(hasFullManageAccess)
 
 	// load request
 	if err := form.LoadRequest(c.Request(), ""); err != nil {
@@ -322,33 +386,45 @@ func (api *recordApi) update(c echo.Context) error {
 	event.UploadedFiles = form.FilesToUpload()
 
 	// update the record
-	return form.Submit(func(next forms.InterceptorNextFunc[*models.Record]) forms.InterceptorNextFunc[*models.Record] {
-		return func(m *models.Record) error {
-			event.Record = m
+	// (the tenant transaction is started only after the request data is loaded)
+	return dao.RunInTenantScope(func(scopedDao *daos.Dao) error {
+		form.SetDao(scopedDao)
 
-			return api.app.OnRecordBeforeUpdateRequest().Trigger(event, func(e *core.RecordUpdateEvent) error {
-				if err := next(e.Record); err != nil {
-					return NewBadRequestError("Failed to update record.", err)
-				}
+		return form.Submit(func(next forms.InterceptorNextFunc[*models.Record]) forms.InterceptorNextFunc[*models.Record] {
+			return func(m *models.Record) error {
+				event.Record = m
 
-				if err := EnrichRecord(e.HttpContext, api.app.Dao(), e.Record); err != nil {
-					api.app.Logger().Debug(
-						"Failed to enrich update record",
-						slog.String("id", e.Record.Id),
-						slog.String("collectionName", e.Record.Collection().Name),
-						slog.String("error", err.Error()),
-					)
-				}
+				return api.app.OnRecordBeforeUpdateRequest().Trigger(event, func(e *core.RecordUpdateEvent) error {
+					err := next(e.Record)
+
+					// store the file scan results for the request log (if any)
+					if scans := form.FileScans(); len(scans) > 0 {
+						e.HttpContext.Set(ContextFileScansKey, scans)
+					}
 
-				return api.app.OnRecordAfterUpdateRequest().Trigger(event, func(e *core.RecordUpdateEvent) error {
-					if e.HttpContext.Response().Committed {
-						return nil
+					if err != nil {
+						return NewBadRequestError("Failed to update record.", err)
 					}
 
-					return e.HttpContext.JSON(http.StatusOK, e.Record)
+					if err := EnrichRecord(e.HttpContext, scopedDao, e.Record); err != nil {
+						api.app.Logger().Debug(
+							"Failed to enrich update record",
+							slog.String("id", e.Record.Id),
+							slog.String("collectionName", e.Record.Collection().Name),
+							slog.String("error", err.Error()),
+						)
+					}
+
+					return api.app.OnRecordAfterUpdateRequest().Trigger(event, func(e *core.RecordUpdateEvent) error {
+						if e.HttpContext.Response().Committed {
+							return nil
+						}
+
+						return e.HttpContext.JSON(http.StatusOK, e.Record)
+					})
 				})
-			})
-		}
+			}
+		})
 	})
 }
 
@@ -370,20 +446,31 @@ func (api *recordApi) delete(c echo.Context) error {
 		return NewForbiddenError("Only admins can perform this action.", nil)
 	}
 
-	ruleFunc := func(q *dbx.SelectQuery) error {
-		if requestInfo.Admin == nil && collection.DeleteRule != nil && *collection.DeleteRule != "" {
-			resolver := resolvers.NewRecordFieldResolver(api.app.Dao(), collection, requestInfo, true)
-			expr, err := search.FilterData(*collection.DeleteRule).BuildExpr(resolver)
-			if err != nil {
-				return err
+	dao, err := RequestTenantDao(c, api.app, collection)
+	if err != nil {
+		return err
+	}
+
+	var record *models.Record
+
+	fetchErr := dao.RunInTenantScope(func(scopedDao *daos.Dao) error {
+		ruleFunc := func(q *dbx.SelectQuery) error {
+			if requestInfo.Admin == nil && collection.DeleteRule != nil && *collection.DeleteRule != "" {
+				resolver := resolvers.NewRecordFieldResolver(scopedDao, collection, requestInfo, true)
+				expr, err := search.FilterData(*collection.DeleteRule).BuildExpr(resolver)
+				if err != nil {
+					return err
+				}
+				resolver.UpdateQuery(q)
+				q.AndWhere(expr)
 			}
-			resolver.UpdateQuery(q)
-			q.AndWhere(expr)
+			return nil
 		}
-		return nil
-	}
 
-	record, fetchErr := api.app.Dao().FindRecordById(collection.Id, recordId, ruleFunc)
+		var err error
+		record, err = scopedDao.FindRecordById(collection.Id, recordId, ruleFunc)
+		return err
+	})
 	if fetchErr != nil || record == nil {
 		return NewNotFoundError("", fetchErr)
 	}
@@ -395,7 +482,10 @@ func (api *recordApi) delete(c echo.Context) error {
 
 	return api.app.OnRecordBeforeDeleteRequest().Trigger(event, func(e *core.RecordDeleteEvent) error {
 		// delete the record
-		if err := api.app.Dao().DeleteRecord(e.Record); err != nil {
+		err := dao.RunInTenantScope(func(scopedDao *daos.Dao) error {
+			return scopedDao.DeleteRecord(e.Record)
+		})
+		if err != nil {
 			return NewBadRequestError("Failed to delete record. Make sure that the record is not part of a required relation reference.", err)
 		}
 
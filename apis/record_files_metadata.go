@@ -0,0 +1,121 @@
+package apis
+
+import (
+	"strings"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/daos"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/models/schema"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/rest"
+	"github.com/labstack/echo/v5"
+)
+
+// ContextFilesMetadataKey is the echo context key with the loaded files
+// metadata of the enriched records (map[filename]*models.FileMetadata).
+const ContextFilesMetadataKey = "filesMetadata"
+
+// fileMetaModifier is the name of the fields modifier that replaces
+// the file field name(s) with the related file metadata object(s).
+//
+// Example:
+//
+//	?fields=*,avatar:meta
+const fileMetaModifier = "meta"
+
+// loadFilesMetadata loads into the request context the files metadata
+// of the provided records and their expanded relations but only
+// if the ?fields query param contains a ":meta" modifier.
+func loadFilesMetadata(c echo.Context, dao *daos.Dao, records []*models.Record) error {
+	if !strings.Contains(c.QueryParam(fieldsQueryParam), ":"+fileMetaModifier) {
+		return nil // not requested
+	}
+
+	metadata, _ := c.Get(ContextFilesMetadataKey).(map[string]*models.FileMetadata)
+	if metadata == nil {
+		metadata = map[string]*models.FileMetadata{}
+	}
+
+	// group the records (including the expanded ones) by their collection
+	grouped := map[string][]string{}
+	collectRecordsWithFiles(records, grouped, map[*models.Record]struct{}{})
+
+	for collectionId, recordIds := range grouped {
+		items, err := dao.FindFilesMetadataByRecords(collectionId, recordIds...)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			metadata[item.Name] = item
+		}
+	}
+
+	c.Set(ContextFilesMetadataKey, metadata)
+
+	return nil
+}
+
+func collectRecordsWithFiles(records []*models.Record, grouped map[string][]string, visited map[*models.Record]struct{}) {
+	for _, record := range records {
+		if _, ok := visited[record]; ok {
+			continue
+		}
+		visited[record] = struct{}{}
+
+		collection := record.Collection()
+
+		for _, field := range collection.Schema.Fields() {
+			if field.Type == schema.FieldTypeFile {
+				grouped[collection.Id] = append(grouped[collection.Id], record.Id)
+				break
+			}
+		}
+
+		for key := range record.Expand() {
+			collectRecordsWithFiles(record.ExpandedAll(key), grouped, visited)
+		}
+	}
+}
+
+// newFileMetaModifier is the [rest.FieldModifierFactory] of the ":meta" fields modifier.
+func newFileMetaModifier(c echo.Context, args ...string) (rest.FieldModifier, error) {
+	metadata, _ := c.Get(ContextFilesMetadataKey).(map[string]*models.FileMetadata)
+
+	return &fileMetadataModifier{metadata: metadata}, nil
+}
+
+type fileMetadataModifier struct {
+	metadata map[string]*models.FileMetadata
+}
+
+// Modify implements the [rest.FieldModifier] interface.
+//
+// Replaces the file name(s) with the related file metadata object(s).
+// Files without stored metadata are returned as null (or skipped for multiple files).
+func (m *fileMetadataModifier) Modify(value any) (any, error) {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return nil, nil
+		}
+
+		if meta, ok := m.metadata[v]; ok {
+			return meta, nil
+		}
+
+		return nil, nil
+	case []any:
+		result := make([]*models.FileMetadata, 0, len(v))
+
+		for _, name := range v {
+			str, _ := name.(string)
+			if meta, ok := m.metadata[str]; ok {
+				result = append(result, meta)
+			}
+		}
+
+		return result, nil
+	}
+
+	return value, nil
+}
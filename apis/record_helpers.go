@@ -1,6 +1,7 @@
 package apis
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"log/slog"
@@ -15,8 +16,11 @@ import (
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/inflector"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/rest"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/search"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/tracing"
 	"github.com/labstack/echo/v5"
 	"github.com/pocketbase/dbx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const ContextRequestInfoKey = "requestInfo"
@@ -69,6 +73,49 @@ func RequestInfo(c echo.Context) *models.RequestInfo {
 	return result
 }
 
+// RequestTenantDao returns a new Dao bound to the request tenant
+// (see [LoadTenantContext]) for accessing the provided collection records.
+//
+// Admin requests without tenant have disabled tenant isolation.
+// Non-admin requests to a multi-tenant collection without tenant are rejected.
+//
+// Note that the tenant is applied only to the transactions
+// started with [daos.Dao.RunInTenantScope].
+//
+// The returned Dao is bound to the request context values (eg. the
+// request trace) but not to its cancellation.
+func RequestTenantDao(c echo.Context, app core.App, collection *models.Collection) (*daos.Dao, error) {
+	dao := app.Dao().WithContext(context.WithoutCancel(c.Request().Context()))
+
+	if tenant, _ := c.Get(ContextTenantKey).(string); tenant != "" {
+		return dao.WithTenant(tenant), nil
+	}
+
+	if collection == nil || !collection.IsMultiTenant() {
+		return dao, nil
+	}
+
+	if admin, _ := c.Get(ContextAdminKey).(*models.Admin); admin != nil {
+		return dao.WithoutTenantIsolation(), nil
+	}
+
+	return nil, NewForbiddenError("Missing tenant.", nil)
+}
+
+// recordTokenTenantDao is similar to [RequestTenantDao] but for the
+// auth record token confirmations (eg. password reset, verification).
+//
+// Since the confirmation links don't carry the auth record tenant,
+// requests to a multi-tenant collection without tenant have disabled
+// tenant isolation (the token is still verified with the record token key).
+func recordTokenTenantDao(c echo.Context, app core.App, collection *models.Collection) (*daos.Dao, error) {
+	if tenant, _ := c.Get(ContextTenantKey).(string); tenant == "" && collection.IsMultiTenant() {
+		return app.Dao().WithContext(context.WithoutCancel(c.Request().Context())).WithoutTenantIsolation(), nil
+	}
+
+	return RequestTenantDao(c, app, collection)
+}
+
 // RecordAuthResponse writes standardised json record auth response
 // into the specified request context.
 func RecordAuthResponse(
@@ -109,13 +156,24 @@ func RecordAuthResponse(
 			requestInfo := *RequestInfo(e.HttpContext)
 			requestInfo.Admin = nil
 			requestInfo.AuthRecord = e.Record
-			failed := app.Dao().ExpandRecord(
-				e.Record,
-				expands,
-				expandFetch(app.Dao(), &requestInfo),
-			)
-			if len(failed) > 0 {
-				app.Logger().Debug("[RecordAuthResponse] Failed to expand relations", slog.Any("errors", failed))
+
+			dao, err := RequestTenantDao(e.HttpContext, app, e.Record.Collection())
+			if err == nil {
+				err = dao.RunInTenantScope(func(scopedDao *daos.Dao) error {
+					failed := scopedDao.ExpandRecord(
+						e.Record,
+						expands,
+						expandFetch(scopedDao, &requestInfo),
+					)
+					if len(failed) > 0 {
+						app.Logger().Debug("[RecordAuthResponse] Failed to expand relations", slog.Any("errors", failed))
+					}
+
+					return nil
+				})
+			}
+			if err != nil {
+				app.Logger().Debug("[RecordAuthResponse] Failed to resolve the expand tenant scope", slog.String("error", err.Error()))
 			}
 		}
 
@@ -142,6 +200,7 @@ func RecordAuthResponse(
 //   - expands relations (if defaultExpands and/or ?expand query param is set)
 //   - ensures that the emails of the auth record and its expanded auth relations
 //     are visible only for the current logged admin, record owner or record with manage access
+//   - loads the files metadata (if the ?fields query param has a ":meta" modifier)
 func EnrichRecord(c echo.Context, dao *daos.Dao, record *models.Record, defaultExpands ...string) error {
 	return EnrichRecords(c, dao, []*models.Record{record}, defaultExpands...)
 }
@@ -150,6 +209,7 @@ func EnrichRecord(c echo.Context, dao *daos.Dao, record *models.Record, defaultE
 //   - expands relations (if defaultExpands and/or ?expand query param is set)
 //   - ensures that the emails of the auth records and their expanded auth relations
 //     are visible only for the current logged admin, record owner or record with manage access
+//   - loads the files metadata (if the ?fields query param has a ":meta" modifier)
 func EnrichRecords(c echo.Context, dao *daos.Dao, records []*models.Record, defaultExpands ...string) error {
 	requestInfo := RequestInfo(c)
 
@@ -161,13 +221,27 @@ func EnrichRecords(c echo.Context, dao *daos.Dao, records []*models.Record, defa
 	if param := c.QueryParam(expandQueryParam); param != "" {
 		expands = append(expands, strings.Split(param, ",")...)
 	}
-	if len(expands) == 0 {
-		return nil // nothing to expand
+	if len(expands) > 0 {
+		_, span := tracing.Start(
+			c.Request().Context(),
+			"records.expand",
+			trace.WithAttributes(attribute.String("expand", strings.Join(expands, ","))),
+		)
+
+		errs := dao.ExpandRecords(records, expands, expandFetch(dao, requestInfo))
+
+		if len(errs) > 0 {
+			err := fmt.Errorf("Failed to expand: %v", errs)
+			tracing.RecordError(span, err)
+			span.End()
+			return err
+		}
+
+		span.End()
 	}
 
-	errs := dao.ExpandRecords(records, expands, expandFetch(dao, requestInfo))
-	if len(errs) > 0 {
-		return fmt.Errorf("Failed to expand: %v", errs)
+	if err := loadFilesMetadata(c, dao, records); err != nil {
+		return fmt.Errorf("Failed to load the files metadata: %w", err)
 	}
 
 	return nil
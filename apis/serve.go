@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/AlperRehaYAZGAN/postgresbase/core"
+	"github.com/AlperRehaYAZGAN/postgresbase/daos"
 	"github.com/AlperRehaYAZGAN/postgresbase/migrations"
 	"github.com/AlperRehaYAZGAN/postgresbase/migrations/logs"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/list"
@@ -87,6 +88,18 @@ func Serve(app core.App, config ServeConfig) (*http.Server, error) {
 		Skipper:      middleware.DefaultSkipper,
 		AllowOrigins: config.AllowedOrigins,
 		AllowMethods: []string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodPatch, http.MethodPost, http.MethodDelete},
+		// expose the resumable (tus) uploads headers
+		ExposeHeaders: []string{
+			echo.HeaderXRequestID,
+			"Location",
+			"Tus-Resumable",
+			"Tus-Version",
+			"Tus-Extension",
+			"Upload-Offset",
+			"Upload-Length",
+			"Upload-Expires",
+			"Upload-Key",
+		},
 	}))
 
 	// start http server
@@ -248,6 +261,7 @@ func Serve(app core.App, config ServeConfig) (*http.Server, error) {
 type migrationsConnection struct {
 	DB             *dbx.DB
 	MigrationsList migrate.MigrationsList
+	TxInit         func(tx *dbx.Tx) error
 }
 
 func runMigrations(app core.App) error {
@@ -255,6 +269,7 @@ func runMigrations(app core.App) error {
 		{
 			DB:             app.DB(),
 			MigrationsList: migrations.AppMigrations,
+			TxInit:         daos.DisableTenantIsolation,
 		},
 		{
 			DB:             app.LogsDB(),
@@ -268,6 +283,10 @@ func runMigrations(app core.App) error {
 			return err
 		}
 
+		if c.TxInit != nil {
+			runner.SetTxInit(c.TxInit)
+		}
+
 		if _, err := runner.Up(); err != nil {
 			return err
 		}
@@ -27,7 +27,7 @@ type settingsApi struct {
 }
 
 func (api *settingsApi) list(c echo.Context) error {
-	settings, err := api.app.Settings().RedactClone()
+	settings, err := redactSettings(api.app)
 	if err != nil {
 		return NewBadRequestError("", err)
 	}
@@ -72,7 +72,7 @@ func (api *settingsApi) set(c echo.Context) error {
 						return nil
 					}
 
-					redactedSettings, err := api.app.Settings().RedactClone()
+					redactedSettings, err := redactSettings(api.app)
 					if err != nil {
 						return NewBadRequestError("", err)
 					}
@@ -84,6 +84,30 @@ func (api *settingsApi) set(c echo.Context) error {
 	})
 }
 
+// redactSettings returns a redacted copy of the app settings
+// (including the custom storage drivers secret config fields).
+func redactSettings(app core.App) (*settings.Settings, error) {
+	redacted, err := app.Settings().RedactClone()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, config := range []settings.StorageConfig{redacted.Storage, redacted.Backups.Storage} {
+		driver := app.StorageDriver(config.Driver)
+		if driver == nil {
+			continue
+		}
+
+		for _, field := range driver.SecretFields {
+			if v, ok := config.Config[field]; ok && v != "" {
+				config.Config[field] = settings.SecretMask
+			}
+		}
+	}
+
+	return redacted, nil
+}
+
 func (api *settingsApi) testS3(c echo.Context) error {
 	form := forms.NewTestS3Filesystem(api.app)
 
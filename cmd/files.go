@@ -0,0 +1,327 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/core"
+	"github.com/AlperRehaYAZGAN/postgresbase/forms"
+	"github.com/AlperRehaYAZGAN/postgresbase/models/settings"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/filesystem"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// NewFilesCommand creates and returns new command for managing
+// the app storage files (eg. orphaned files cleanup).
+func NewFilesCommand(app core.App) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "files",
+		Short: "Manages the app storage files",
+	}
+
+	command.AddCommand(filesGCCommand(app))
+	command.AddCommand(filesMigrateCommand(app))
+	command.AddCommand(filesMetadataCommand(app))
+
+	return command
+}
+
+func filesGCCommand(app core.App) *cobra.Command {
+	var dryRun bool
+	var minAge time.Duration
+
+	command := &cobra.Command{
+		Use:          "gc",
+		Example:      "files gc --dry-run",
+		Short:        "Deletes the storage files that are not referenced by any record",
+		SilenceUsage: true,
+		RunE: func(command *cobra.Command, args []string) error {
+			if !command.Flags().Changed("min-age") {
+				minAge = core.DefaultFilesGCMinAge
+				if hours := app.Settings().FilesGC.MinAge; hours > 0 {
+					minAge = time.Duration(hours) * time.Hour
+				}
+			}
+
+			if minAge < 0 {
+				return errors.New("The min age must be a positive duration.")
+			}
+
+			files, err := app.DeleteOrphanedFiles(command.Context(), minAge, dryRun)
+			if err != nil {
+				return fmt.Errorf("Failed to collect the orphaned files: %v", err)
+			}
+
+			var totalSize int64
+			for _, f := range files {
+				totalSize += f.Size
+				fmt.Println(f.Key)
+			}
+
+			if dryRun {
+				color.Yellow("Found %d orphaned file(s) (%d bytes). Nothing was deleted (dry-run).", len(files), totalSize)
+			} else {
+				color.Green("Successfully deleted %d orphaned file(s) (%d bytes)!", len(files), totalSize)
+			}
+
+			return nil
+		},
+	}
+
+	command.PersistentFlags().BoolVar(
+		&dryRun,
+		"dry-run",
+		false,
+		"only report the orphaned files without deleting them",
+	)
+
+	command.PersistentFlags().DurationVar(
+		&minAge,
+		"min-age",
+		core.DefaultFilesGCMinAge,
+		"min age of an unreferenced file before it is considered orphaned (default to the settings value)",
+	)
+
+	return command
+}
+
+func filesMetadataCommand(app core.App) *cobra.Command {
+	command := &cobra.Command{
+		Use:          "metadata",
+		Example:      "files metadata",
+		Short:        "Extracts and stores the missing metadata of the existing record files",
+		SilenceUsage: true,
+		RunE: func(command *cobra.Command, args []string) error {
+			total, err := app.BackfillFilesMetadata(command.Context())
+			if err != nil {
+				return fmt.Errorf("Failed to backfill the files metadata (rerun the command to resume): %v", err)
+			}
+
+			color.Green("Successfully backfilled the metadata of %d file(s)!", total)
+
+			return nil
+		},
+	}
+
+	return command
+}
+
+func filesMigrateCommand(app core.App) *cobra.Command {
+	var withBackups bool
+	var workers int
+	var verify bool
+	var rawConfig string
+	var rawBackupsConfig string
+
+	command := &cobra.Command{
+		Use:          "migrate [local|s3|driver]",
+		Example:      "files migrate s3 --backups",
+		Short:        "Copies the app storage files to the local, S3 or custom driver storage and switches the storage settings",
+		SilenceUsage: true,
+		RunE: func(command *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("Missing target storage argument (local, s3 or a registered storage driver name).")
+			}
+
+			target := args[0]
+			if target != storageLocal && target != storageS3 && app.StorageDriver(target) == nil {
+				return fmt.Errorf("Invalid target storage %q (local, s3 or a registered storage driver name).", target)
+			}
+
+			form := forms.NewSettingsUpsert(app)
+
+			mainStorage := storageSettings{Storage: form.Storage, S3: form.S3}
+			backupsStorage := storageSettings{Storage: form.Backups.Storage, S3: form.Backups.S3}
+
+			migrateMain := mainStorage.name() != target
+			migrateBackups := withBackups && backupsStorage.name() != target
+
+			if !migrateMain && !migrateBackups {
+				return fmt.Errorf("The app storage is already %s.", target)
+			}
+
+			options := filesystem.SyncOptions{
+				Workers: workers,
+				Verify:  verify,
+				OnFile: func(key string, skipped bool, err error) {
+					switch {
+					case err != nil:
+						color.Red("failed %s: %v", key, err)
+					case skipped:
+						fmt.Println("skipped " + key)
+					default:
+						fmt.Println("copied " + key)
+					}
+				},
+			}
+
+			if migrateMain {
+				to, err := mainStorage.target(target, rawConfig)
+				if err != nil {
+					return err
+				}
+
+				localDir := filepath.Join(app.DataDir(), core.LocalStorageDirName)
+				if err := migrateStorage(command, app, mainStorage, to, localDir, options); err != nil {
+					return err
+				}
+
+				form.Storage = to.Storage
+				form.S3 = to.S3
+			} else {
+				color.Yellow("The app storage is already %s.", target)
+			}
+
+			if withBackups {
+				if migrateBackups {
+					to, err := backupsStorage.target(target, rawBackupsConfig)
+					if err != nil {
+						return err
+					}
+
+					localDir := filepath.Join(app.DataDir(), core.LocalBackupsDirName)
+					if err := migrateStorage(command, app, backupsStorage, to, localDir, options); err != nil {
+						return err
+					}
+
+					form.Backups.Storage = to.Storage
+					form.Backups.S3 = to.S3
+				} else {
+					color.Yellow("The app backups storage is already %s.", target)
+				}
+			}
+
+			if err := form.Submit(); err != nil {
+				return fmt.Errorf("Failed to update the storage settings: %v", err)
+			}
+
+			color.Green("Successfully migrated the app storage to %s!", target)
+			return nil
+		},
+	}
+
+	command.PersistentFlags().BoolVar(
+		&withBackups,
+		"backups",
+		false,
+		"migrate also the app backups",
+	)
+
+	command.PersistentFlags().IntVar(
+		&workers,
+		"workers",
+		4,
+		"max number of the concurrently copied files",
+	)
+
+	command.PersistentFlags().BoolVar(
+		&verify,
+		"verify",
+		true,
+		"verify the sha256 checksum of the copied files",
+	)
+
+	command.PersistentFlags().StringVar(
+		&rawConfig,
+		"config",
+		"",
+		"the target storage driver JSON config (for custom storage drivers)",
+	)
+
+	command.PersistentFlags().StringVar(
+		&rawBackupsConfig,
+		"backups-config",
+		"",
+		"the target backups storage driver JSON config (for custom storage drivers)",
+	)
+
+	return command
+}
+
+const (
+	storageLocal = "local"
+	storageS3    = "s3"
+)
+
+// storageSettings defines the app storage or backups storage settings.
+type storageSettings struct {
+	Storage settings.StorageConfig
+	S3      settings.S3Config
+}
+
+// name returns the name of the storage that is used with the current
+// settings (the custom storage driver name, "s3" or "local").
+func (s storageSettings) name() string {
+	switch {
+	case s.Storage.Driver != "":
+		return s.Storage.Driver
+	case s.S3.Enabled:
+		return storageS3
+	default:
+		return storageLocal
+	}
+}
+
+// target returns a copy of the current storage settings
+// switched to the specified target storage.
+//
+// rawConfig is the optional JSON config of the target custom storage driver.
+func (s storageSettings) target(name string, rawConfig string) (storageSettings, error) {
+	result := storageSettings{S3: s.S3}
+
+	switch name {
+	case storageLocal:
+		result.S3.Enabled = false
+	case storageS3:
+		result.S3.Enabled = true
+		if err := result.S3.Validate(); err != nil {
+			return result, fmt.Errorf("Missing or invalid S3 settings: %v", err)
+		}
+	default:
+		result.Storage.Driver = name
+		if rawConfig != "" {
+			if err := json.Unmarshal([]byte(rawConfig), &result.Storage.Config); err != nil {
+				return result, fmt.Errorf("Invalid %q storage driver config: %v", name, err)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// migrateStorage copies all files from the "from" to the "to" storage
+// (localDir is the local filesystem dir of the storage).
+func migrateStorage(
+	command *cobra.Command,
+	app core.App,
+	from storageSettings,
+	to storageSettings,
+	localDir string,
+	options filesystem.SyncOptions,
+) error {
+	src, err := core.NewStorageFilesystem(app, from.Storage, from.S3, localDir)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := core.NewStorageFilesystem(app, to.Storage, to.S3, localDir)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	result, err := filesystem.Sync(command.Context(), src, dst, options)
+	if result != nil {
+		fmt.Printf("%d file(s) processed (%d copied, %d skipped, %d failed)\n", result.Total, result.Copied, result.Skipped, len(result.Failed))
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to copy the storage files (rerun the command to resume): %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,223 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/cmd"
+	"github.com/AlperRehaYAZGAN/postgresbase/core"
+	"github.com/AlperRehaYAZGAN/postgresbase/tests"
+)
+
+func TestFilesGCCommand(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	fsys, err := app.NewFilesystem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fsys.Close()
+
+	orphanKey := "_pb_users_auth_/4q1xlclmfloku33/orphan.txt"
+	if err := fsys.Upload([]byte("test"), orphanKey); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name         string
+		args         []string
+		expectError  bool
+		expectExists bool
+	}{
+		{
+			"negative min age",
+			[]string{"gc", "--min-age", "-1h"},
+			true,
+			true,
+		},
+		{
+			"default min age",
+			[]string{"gc"},
+			false,
+			true,
+		},
+		{
+			"dry-run",
+			[]string{"gc", "--dry-run", "--min-age", "0"},
+			false,
+			true,
+		},
+		{
+			"delete",
+			[]string{"gc", "--min-age", "0"},
+			false,
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		command := cmd.NewFilesCommand(app)
+		command.SetArgs(s.args)
+
+		err := command.Execute()
+
+		hasErr := err != nil
+		if s.expectError != hasErr {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+		}
+
+		if exists, _ := fsys.Exists(orphanKey); exists != s.expectExists {
+			t.Errorf("[%s] Expected the orphaned file exists %v, got %v", s.name, s.expectExists, exists)
+		}
+	}
+}
+
+func TestFilesMigrateCommand(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		name        string
+		args        []string
+		expectError bool
+	}{
+		{
+			"missing target",
+			[]string{"migrate"},
+			true,
+		},
+		{
+			"invalid target",
+			[]string{"migrate", "invalid"},
+			true,
+		},
+		{
+			"already local",
+			[]string{"migrate", "local"},
+			true,
+		},
+		{
+			"missing S3 settings",
+			[]string{"migrate", "s3"},
+			true,
+		},
+	}
+
+	for _, s := range scenarios {
+		command := cmd.NewFilesCommand(app)
+		command.SetArgs(s.args)
+
+		err := command.Execute()
+
+		hasErr := err != nil
+		if s.expectError != hasErr {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+		}
+	}
+
+	if app.Settings().S3.Enabled {
+		t.Fatal("Expected the S3 storage to remain disabled")
+	}
+}
+
+func TestFilesMigrateCommandStorageDriver(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		name          string
+		args          []string
+		expectError   bool
+		expectDriver  string
+		expectBackups string
+	}{
+		{
+			"main storage to the driver",
+			[]string{"migrate", core.MemoryStorageDriverName},
+			false,
+			core.MemoryStorageDriverName,
+			"",
+		},
+		{
+			"main storage already at the driver but not the backups",
+			[]string{"migrate", core.MemoryStorageDriverName, "--backups"},
+			false,
+			core.MemoryStorageDriverName,
+			core.MemoryStorageDriverName,
+		},
+		{
+			"both storages already at the driver",
+			[]string{"migrate", core.MemoryStorageDriverName, "--backups"},
+			true,
+			core.MemoryStorageDriverName,
+			core.MemoryStorageDriverName,
+		},
+		{
+			"main storage back to local",
+			[]string{"migrate", "local"},
+			false,
+			"",
+			core.MemoryStorageDriverName,
+		},
+	}
+
+	for _, s := range scenarios {
+		command := cmd.NewFilesCommand(app)
+		command.SetArgs(s.args)
+
+		err := command.Execute()
+
+		hasErr := err != nil
+		if s.expectError != hasErr {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+		}
+
+		if v := app.Settings().Storage.Driver; v != s.expectDriver {
+			t.Errorf("[%s] Expected storage driver %q, got %q", s.name, s.expectDriver, v)
+		}
+
+		if v := app.Settings().Backups.Storage.Driver; v != s.expectBackups {
+			t.Errorf("[%s] Expected backups storage driver %q, got %q", s.name, s.expectBackups, v)
+		}
+
+		fsys, err := app.NewFilesystem()
+		if err != nil {
+			t.Fatalf("[%s] Failed to open the app filesystem: %v", s.name, err)
+		}
+
+		if exists, _ := fsys.Exists("_pb_users_auth_/4q1xlclmfloku33/300_1SEi6Q6U72.png"); !exists {
+			t.Errorf("[%s] Expected the migrated storage file to exist", s.name)
+		}
+
+		fsys.Close()
+	}
+}
+
+func TestFilesMetadataCommand(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	command := cmd.NewFilesCommand(app)
+	command.SetArgs([]string{"metadata"})
+
+	if err := command.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := app.Dao().FindFilesMetadataByRecords("_pb_users_auth_", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 1 {
+		t.Fatalf("Expected 1 backfilled file metadata, got %d", len(items))
+	}
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/core"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/types"
+	"github.com/fatih/color"
+	"github.com/pocketbase/dbx"
+	"github.com/spf13/cobra"
+)
+
+// NewLogsCommand creates and returns new command for managing
+// the app logs (eg. archiving and re-importing archived logs).
+func NewLogsCommand(app core.App) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "logs",
+		Short: "Manages the app logs",
+	}
+
+	command.AddCommand(logsArchiveCommand(app))
+	command.AddCommand(logsImportCommand(app))
+
+	return command
+}
+
+func logsArchiveCommand(app core.App) *cobra.Command {
+	var olderThan time.Duration
+
+	command := &cobra.Command{
+		Use:          "archive",
+		Example:      "logs archive --older-than 72h",
+		Short:        "Exports the logs into compressed NDJSON files in the backups storage (the logs are not deleted)",
+		SilenceUsage: true,
+		RunE: func(command *cobra.Command, args []string) error {
+			if olderThan < 0 {
+				return errors.New("The older-than duration must be a positive duration.")
+			}
+
+			var expr dbx.Expression
+			if olderThan > 0 {
+				expr = dbx.NewExp("[[created]] <= {:date}", dbx.Params{
+					"date": time.Now().Add(-olderThan).UTC().Format(types.DefaultDateLayout),
+				})
+			}
+
+			keys, err := app.ArchiveLogs(command.Context(), expr)
+			for _, key := range keys {
+				fmt.Println(key)
+			}
+			if err != nil {
+				return fmt.Errorf("Failed to archive the logs: %v", err)
+			}
+
+			color.Green("Successfully created %d logs archive file(s)!", len(keys))
+			return nil
+		},
+	}
+
+	command.PersistentFlags().DurationVar(
+		&olderThan,
+		"older-than",
+		0,
+		"archive only the logs older than the specified duration (default to all logs)",
+	)
+
+	return command
+}
+
+func logsImportCommand(app core.App) *cobra.Command {
+	command := &cobra.Command{
+		Use:          "import [key]",
+		Example:      "logs import @logs_archive_2024-01-02_level0_20240105030405.ndjson.gz",
+		Short:        "Imports the logs from a backups storage logs archive file into the logs db",
+		SilenceUsage: true,
+		RunE: func(command *cobra.Command, args []string) error {
+			if len(args) != 1 || !core.IsLogsArchiveKey(args[0]) {
+				return errors.New("Missing or invalid logs archive file key argument.")
+			}
+
+			total, err := app.ImportLogsArchive(command.Context(), args[0])
+			if err != nil {
+				return fmt.Errorf("Failed to import the logs archive: %v", err)
+			}
+
+			color.Green("Successfully imported %d log(s)!", total)
+			return nil
+		},
+	}
+
+	return command
+}
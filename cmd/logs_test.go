@@ -0,0 +1,77 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/cmd"
+	"github.com/AlperRehaYAZGAN/postgresbase/tests"
+)
+
+func TestLogsArchiveAndImportCommands(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	if err := tests.MockLogsData(app); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name        string
+		args        []string
+		expectError bool
+	}{
+		{
+			"archive with negative older-than",
+			[]string{"archive", "--older-than", "-1h"},
+			true,
+		},
+		{
+			"archive",
+			[]string{"archive", "--older-than", "1h"},
+			false,
+		},
+		{
+			"import with missing key",
+			[]string{"import"},
+			true,
+		},
+		{
+			"import with invalid key",
+			[]string{"import", "test.zip"},
+			true,
+		},
+		{
+			"import with missing archive file",
+			[]string{"import", "@logs_archive_2022-05-01_level0_20220601000000.ndjson.gz"},
+			true,
+		},
+	}
+
+	for _, s := range scenarios {
+		command := cmd.NewLogsCommand(app)
+		command.SetArgs(s.args)
+
+		err := command.Execute()
+
+		hasErr := err != nil
+		if s.expectError != hasErr {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+		}
+	}
+
+	fsys, err := app.NewBackupsFilesystem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fsys.Close()
+
+	files, err := fsys.List("@logs_archive_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("Expected 2 logs archive files, got %d", len(files))
+	}
+}
@@ -6,14 +6,19 @@ package core
 import (
 	"context"
 	"log/slog"
+	"time"
 
 	"github.com/AlperRehaYAZGAN/postgresbase/daos"
 	"github.com/AlperRehaYAZGAN/postgresbase/models/settings"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/cron"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/filesystem"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/hook"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/mailer"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/metrics"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/scanner"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/store"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/subscriptions"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/tracing"
 	"github.com/pocketbase/dbx"
 )
 
@@ -52,6 +57,10 @@ type App interface {
 	// Logger returns the active app logger.
 	Logger() *slog.Logger
 
+	// ContextLogger returns a request-scoped child of the app logger
+	// that attaches the ctx request id to all of its log records.
+	ContextLogger(ctx context.Context) *slog.Logger
+
 	// DataDir returns the app data directory path.
 	DataDir() string
 
@@ -74,9 +83,29 @@ type App interface {
 	// SubscriptionsBroker returns the app realtime subscriptions broker instance.
 	SubscriptionsBroker() *subscriptions.Broker
 
+	// Cron returns the app cron instance used for scheduling
+	// the system and the user defined background jobs.
+	//
+	// The cron ticker is started automatically on app serve.
+	Cron() *cron.Cron
+
+	// Metrics returns the app metrics registry that is
+	// exposed by the Prometheus metrics endpoint (if enabled).
+	Metrics() *metrics.Registry
+
+	// Tracer returns the app traces exporter
+	// (it is nil if the tracing is disabled).
+	Tracer() *tracing.Tracer
+
 	// NewMailClient creates and returns a configured app mail client.
 	NewMailClient() mailer.Mailer
 
+	// NewFileScanner creates and returns a configured scanner.Scanner
+	// instance for scanning the record file uploads.
+	//
+	// Returns nil if the file uploads scanning is disabled.
+	NewFileScanner() (scanner.Scanner, error)
+
 	// NewFilesystem creates and returns a configured filesystem.System instance
 	// for managing regular app files (eg. collection uploads).
 	//
@@ -84,6 +113,10 @@ type App interface {
 	// after you are done working with it.
 	NewFilesystem() (*filesystem.System, error)
 
+	// StorageDriver returns the registered custom storage driver
+	// with the specified name (or nil if there is no such driver).
+	StorageDriver(name string) *StorageDriver
+
 	// NewBackupsFilesystem creates and returns a configured filesystem.System instance
 	// for managing app backups.
 	//
@@ -128,6 +161,31 @@ type App interface {
 	// NB! This feature is experimental and currently is expected to work only on UNIX based systems.
 	RestoreBackup(ctx context.Context, name string) error
 
+	// DeleteOrphanedFiles finds (and deletes if dryRun is false) the storage
+	// files older than minAge that are not referenced by any collection record.
+	//
+	// Please refer to the godoc of the specific core.App implementation
+	// for details on the cleanup procedures.
+	DeleteOrphanedFiles(ctx context.Context, minAge time.Duration, dryRun bool) ([]*OrphanedFile, error)
+
+	// BackfillFilesMetadata extracts and stores the metadata of the
+	// collection record files that don't have stored metadata yet.
+	//
+	// Returns the number of the backfilled files.
+	BackfillFilesMetadata(ctx context.Context) (int, error)
+
+	// ArchiveLogs exports the logs matching expr into gzip compressed
+	// NDJSON files (one per log day and level) in the backups filesystem.
+	//
+	// On success returns the keys of the created archive files.
+	ArchiveLogs(ctx context.Context, expr dbx.Expression) ([]string, error)
+
+	// ImportLogsArchive imports the logs from the specified backups
+	// filesystem logs archive file into the logs db.
+	//
+	// On success returns the number of the imported logs.
+	ImportLogsArchive(ctx context.Context, key string) (int, error)
+
 	// Restart restarts the current running application process.
 	//
 	// Currently it is relying on execve so it is supported only on UNIX based systems.
@@ -279,6 +337,23 @@ type App interface {
 	// triggered and called only if their event data origin matches the tags.
 	OnMailerAfterRecordChangeEmailSend(tags ...string) *hook.TaggedHook[*MailerRecordEvent]
 
+	// OnMailerBeforeRecordOTPSend hook is triggered right before
+	// sending a one-time password email to an auth record, allowing
+	// you to inspect and customize the email message that is being sent.
+	//
+	// If the optional "tags" list (Collection ids or names) is specified,
+	// then all event handlers registered via the created hook will be
+	// triggered and called only if their event data origin matches the tags.
+	OnMailerBeforeRecordOTPSend(tags ...string) *hook.TaggedHook[*MailerRecordEvent]
+
+	// OnMailerAfterRecordOTPSend hook is triggered after a
+	// one-time password email was successfully sent to an auth record.
+	//
+	// If the optional "tags" list (Collection ids or names) is specified,
+	// then all event handlers registered via the created hook will be
+	// triggered and called only if their event data origin matches the tags.
+	OnMailerAfterRecordOTPSend(tags ...string) *hook.TaggedHook[*MailerRecordEvent]
+
 	// ---------------------------------------------------------------
 	// Realtime API event hooks
 	// ---------------------------------------------------------------
@@ -516,6 +591,43 @@ type App interface {
 	// triggered and called only if their event data origin matches the tags.
 	OnRecordAfterAuthWithOAuth2Request(tags ...string) *hook.TaggedHook[*RecordAuthWithOAuth2Event]
 
+	// OnRecordBeforeAuthWithOTPRequest hook is triggered before each Record
+	// auth with one-time password API request (after the OTP validation and
+	// before deleting it and returning the new auth token).
+	//
+	// Could be used to additionally validate the request data or
+	// to implement completely different auth behavior.
+	//
+	// If the optional "tags" list (Collection ids or names) is specified,
+	// then all event handlers registered via the created hook will be
+	// triggered and called only if their event data origin matches the tags.
+	OnRecordBeforeAuthWithOTPRequest(tags ...string) *hook.TaggedHook[*RecordAuthWithOTPEvent]
+
+	// OnRecordAfterAuthWithOTPRequest hook is triggered after each
+	// successful Record auth with one-time password API request.
+	//
+	// If the optional "tags" list (Collection ids or names) is specified,
+	// then all event handlers registered via the created hook will be
+	// triggered and called only if their event data origin matches the tags.
+	OnRecordAfterAuthWithOTPRequest(tags ...string) *hook.TaggedHook[*RecordAuthWithOTPEvent]
+
+	// OnRecordBeforeAuthWithWebAuthnRequest hook is triggered before each Record
+	// auth with WebAuthn credential (aka. passkey) API request (after the
+	// assertion verification and before storing the new signature counter).
+	//
+	// If the optional "tags" list (Collection ids or names) is specified,
+	// then all event handlers registered via the created hook will be
+	// triggered and called only if their event data origin matches the tags.
+	OnRecordBeforeAuthWithWebAuthnRequest(tags ...string) *hook.TaggedHook[*RecordAuthWithWebAuthnEvent]
+
+	// OnRecordAfterAuthWithWebAuthnRequest hook is triggered after each
+	// successful Record auth with WebAuthn credential API request.
+	//
+	// If the optional "tags" list (Collection ids or names) is specified,
+	// then all event handlers registered via the created hook will be
+	// triggered and called only if their event data origin matches the tags.
+	OnRecordAfterAuthWithWebAuthnRequest(tags ...string) *hook.TaggedHook[*RecordAuthWithWebAuthnEvent]
+
 	// OnRecordBeforeAuthRefreshRequest hook is triggered before each Record
 	// auth refresh API request (right before generating a new auth token).
 	//
@@ -813,4 +925,15 @@ type App interface {
 	// OnCollectionsAfterImportRequest hook is triggered after each
 	// successful API collections import request.
 	OnCollectionsAfterImportRequest() *hook.Hook[*CollectionsImportEvent]
+
+	// ---------------------------------------------------------------
+	// Health API event hooks
+	// ---------------------------------------------------------------
+
+	// OnHealthReadyRequest hook is triggered on each API readiness
+	// health check request (before running the dependency checks).
+	//
+	// Could be used to register additional checks (or to remove
+	// some of the default ones) by modifying the event Checks map.
+	OnHealthReadyRequest() *hook.Hook[*HealthReadyEvent]
 }
@@ -9,20 +9,26 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/AlperRehaYAZGAN/postgresbase/daos"
 	"github.com/AlperRehaYAZGAN/postgresbase/models"
 	"github.com/AlperRehaYAZGAN/postgresbase/models/settings"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/cron"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/filesystem"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/hook"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/logger"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/mailer"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/metrics"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/routine"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/scanner"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/security"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/store"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/subscriptions"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/tracing"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/types"
 	"github.com/fatih/color"
 	"github.com/pocketbase/dbx"
@@ -35,9 +41,11 @@ const (
 	DefaultLogsMaxOpenConns int = 10
 	DefaultLogsMaxIdleConns int = 2
 
-	LocalStorageDirName string = "storage"
-	LocalBackupsDirName string = "backups"
-	LocalTempDirName    string = ".pb_temp_to_delete" // temp pb_data sub directory that will be deleted on each app.Bootstrap()
+	LocalStorageDirName    string = "storage"
+	LocalBackupsDirName    string = "backups"
+	LocalQuarantineDirName string = "quarantine"         // infected file uploads
+	LocalUploadsDirName    string = "uploads"            // pending resumable (tus) uploads
+	LocalTempDirName       string = ".pb_temp_to_delete" // temp pb_data sub directory that will be deleted on each app.Bootstrap()
 )
 
 var _ App = (*BaseApp)(nil)
@@ -54,6 +62,7 @@ type BaseApp struct {
 	dataMaxIdleConns int
 	logsMaxOpenConns int
 	logsMaxIdleConns int
+	storageDrivers   map[string]*StorageDriver
 
 	// internals
 	store               *store.Store[any]
@@ -61,7 +70,25 @@ type BaseApp struct {
 	dao                 *daos.Dao
 	logsDao             *daos.Dao
 	subscriptionsBroker *subscriptions.Broker
+	cron                *cron.Cron
+	fileScanner         scanner.Scanner
 	logger              *slog.Logger
+	metrics             *metrics.Registry
+
+	tracer       atomic.Pointer[tracing.Tracer]
+	tracerMux    sync.Mutex
+	tracerConfig settings.TracingConfig
+	customTracer bool
+
+	logSinksMux    sync.Mutex
+	logSinksConfig []settings.LogSinkConfig
+
+	logsArchiveMux sync.Mutex
+
+	// observers of all app event hooks
+	hookObservers         *hook.Observers
+	hookErrorsObserverId  string
+	hookTracingObserverId string
 
 	// app event hooks
 	onBeforeBootstrap *hook.Hook[*BootstrapEvent]
@@ -88,6 +115,8 @@ type BaseApp struct {
 	onMailerAfterRecordVerificationSend   *hook.Hook[*MailerRecordEvent]
 	onMailerBeforeRecordChangeEmailSend   *hook.Hook[*MailerRecordEvent]
 	onMailerAfterRecordChangeEmailSend    *hook.Hook[*MailerRecordEvent]
+	onMailerBeforeRecordOTPSend           *hook.Hook[*MailerRecordEvent]
+	onMailerAfterRecordOTPSend            *hook.Hook[*MailerRecordEvent]
 
 	// realtime api event hooks
 	onRealtimeConnectRequest         *hook.Hook[*RealtimeConnectEvent]
@@ -132,6 +161,10 @@ type BaseApp struct {
 	onRecordAfterAuthWithPasswordRequest      *hook.Hook[*RecordAuthWithPasswordEvent]
 	onRecordBeforeAuthWithOAuth2Request       *hook.Hook[*RecordAuthWithOAuth2Event]
 	onRecordAfterAuthWithOAuth2Request        *hook.Hook[*RecordAuthWithOAuth2Event]
+	onRecordBeforeAuthWithOTPRequest          *hook.Hook[*RecordAuthWithOTPEvent]
+	onRecordAfterAuthWithOTPRequest           *hook.Hook[*RecordAuthWithOTPEvent]
+	onRecordBeforeAuthWithWebAuthnRequest     *hook.Hook[*RecordAuthWithWebAuthnEvent]
+	onRecordAfterAuthWithWebAuthnRequest      *hook.Hook[*RecordAuthWithWebAuthnEvent]
 	onRecordBeforeAuthRefreshRequest          *hook.Hook[*RecordAuthRefreshEvent]
 	onRecordAfterAuthRefreshRequest           *hook.Hook[*RecordAuthRefreshEvent]
 	onRecordBeforeRequestPasswordResetRequest *hook.Hook[*RecordRequestPasswordResetEvent]
@@ -171,6 +204,9 @@ type BaseApp struct {
 	onCollectionAfterDeleteRequest   *hook.Hook[*CollectionDeleteEvent]
 	onCollectionsBeforeImportRequest *hook.Hook[*CollectionsImportEvent]
 	onCollectionsAfterImportRequest  *hook.Hook[*CollectionsImportEvent]
+
+	// health API event hooks
+	onHealthReadyRequest *hook.Hook[*HealthReadyEvent]
 }
 
 // BaseAppConfig defines a BaseApp configuration option
@@ -182,6 +218,11 @@ type BaseAppConfig struct {
 	DataMaxIdleConns int // default 20
 	LogsMaxOpenConns int // default to 100
 	LogsMaxIdleConns int // default to 5
+
+	// StorageDrivers is an optional list of custom storage drivers
+	// (keyed by their name) that could be selected through the
+	// app storage and backups settings.
+	StorageDrivers map[string]*StorageDriver
 }
 
 // NewBaseApp creates and returns a new BaseApp instance
@@ -189,6 +230,8 @@ type BaseAppConfig struct {
 //
 // To initialize the app, you need to call `app.Bootstrap()`.
 func NewBaseApp(config BaseAppConfig) *BaseApp {
+	hookObservers := &hook.Observers{}
+
 	app := &BaseApp{
 		isDev:               config.IsDev,
 		dataDir:             config.DataDir,
@@ -197,121 +240,135 @@ func NewBaseApp(config BaseAppConfig) *BaseApp {
 		dataMaxIdleConns:    config.DataMaxIdleConns,
 		logsMaxOpenConns:    config.LogsMaxOpenConns,
 		logsMaxIdleConns:    config.LogsMaxIdleConns,
+		storageDrivers:      config.StorageDrivers,
 		store:               store.New[any](nil),
 		settings:            settings.New(),
 		subscriptionsBroker: subscriptions.NewBroker(),
+		cron:                cron.New(),
+		metrics:             metrics.NewRegistry(),
+		hookObservers:       hookObservers,
 
 		// app event hooks
-		onBeforeBootstrap: &hook.Hook[*BootstrapEvent]{},
-		onAfterBootstrap:  &hook.Hook[*BootstrapEvent]{},
-		onBeforeServe:     &hook.Hook[*ServeEvent]{},
-		onBeforeApiError:  &hook.Hook[*ApiErrorEvent]{},
-		onAfterApiError:   &hook.Hook[*ApiErrorEvent]{},
-		onTerminate:       &hook.Hook[*TerminateEvent]{},
+		onBeforeBootstrap: hook.NewObservedHook[*BootstrapEvent](hookObservers),
+		onAfterBootstrap:  hook.NewObservedHook[*BootstrapEvent](hookObservers),
+		onBeforeServe:     hook.NewObservedHook[*ServeEvent](hookObservers),
+		onBeforeApiError:  hook.NewObservedHook[*ApiErrorEvent](hookObservers),
+		onAfterApiError:   hook.NewObservedHook[*ApiErrorEvent](hookObservers),
+		onTerminate:       hook.NewObservedHook[*TerminateEvent](hookObservers),
 
 		// dao event hooks
-		onModelBeforeCreate: &hook.Hook[*ModelEvent]{},
-		onModelAfterCreate:  &hook.Hook[*ModelEvent]{},
-		onModelBeforeUpdate: &hook.Hook[*ModelEvent]{},
-		onModelAfterUpdate:  &hook.Hook[*ModelEvent]{},
-		onModelBeforeDelete: &hook.Hook[*ModelEvent]{},
-		onModelAfterDelete:  &hook.Hook[*ModelEvent]{},
+		onModelBeforeCreate: hook.NewObservedHook[*ModelEvent](hookObservers),
+		onModelAfterCreate:  hook.NewObservedHook[*ModelEvent](hookObservers),
+		onModelBeforeUpdate: hook.NewObservedHook[*ModelEvent](hookObservers),
+		onModelAfterUpdate:  hook.NewObservedHook[*ModelEvent](hookObservers),
+		onModelBeforeDelete: hook.NewObservedHook[*ModelEvent](hookObservers),
+		onModelAfterDelete:  hook.NewObservedHook[*ModelEvent](hookObservers),
 
 		// mailer event hooks
-		onMailerBeforeAdminResetPasswordSend:  &hook.Hook[*MailerAdminEvent]{},
-		onMailerAfterAdminResetPasswordSend:   &hook.Hook[*MailerAdminEvent]{},
-		onMailerBeforeRecordResetPasswordSend: &hook.Hook[*MailerRecordEvent]{},
-		onMailerAfterRecordResetPasswordSend:  &hook.Hook[*MailerRecordEvent]{},
-		onMailerBeforeRecordVerificationSend:  &hook.Hook[*MailerRecordEvent]{},
-		onMailerAfterRecordVerificationSend:   &hook.Hook[*MailerRecordEvent]{},
-		onMailerBeforeRecordChangeEmailSend:   &hook.Hook[*MailerRecordEvent]{},
-		onMailerAfterRecordChangeEmailSend:    &hook.Hook[*MailerRecordEvent]{},
+		onMailerBeforeAdminResetPasswordSend:  hook.NewObservedHook[*MailerAdminEvent](hookObservers),
+		onMailerAfterAdminResetPasswordSend:   hook.NewObservedHook[*MailerAdminEvent](hookObservers),
+		onMailerBeforeRecordResetPasswordSend: hook.NewObservedHook[*MailerRecordEvent](hookObservers),
+		onMailerAfterRecordResetPasswordSend:  hook.NewObservedHook[*MailerRecordEvent](hookObservers),
+		onMailerBeforeRecordVerificationSend:  hook.NewObservedHook[*MailerRecordEvent](hookObservers),
+		onMailerAfterRecordVerificationSend:   hook.NewObservedHook[*MailerRecordEvent](hookObservers),
+		onMailerBeforeRecordChangeEmailSend:   hook.NewObservedHook[*MailerRecordEvent](hookObservers),
+		onMailerAfterRecordChangeEmailSend:    hook.NewObservedHook[*MailerRecordEvent](hookObservers),
+		onMailerBeforeRecordOTPSend:           hook.NewObservedHook[*MailerRecordEvent](hookObservers),
+		onMailerAfterRecordOTPSend:            hook.NewObservedHook[*MailerRecordEvent](hookObservers),
 
 		// realtime API event hooks
-		onRealtimeConnectRequest:         &hook.Hook[*RealtimeConnectEvent]{},
-		onRealtimeDisconnectRequest:      &hook.Hook[*RealtimeDisconnectEvent]{},
-		onRealtimeBeforeMessageSend:      &hook.Hook[*RealtimeMessageEvent]{},
-		onRealtimeAfterMessageSend:       &hook.Hook[*RealtimeMessageEvent]{},
-		onRealtimeBeforeSubscribeRequest: &hook.Hook[*RealtimeSubscribeEvent]{},
-		onRealtimeAfterSubscribeRequest:  &hook.Hook[*RealtimeSubscribeEvent]{},
+		onRealtimeConnectRequest:         hook.NewObservedHook[*RealtimeConnectEvent](hookObservers),
+		onRealtimeDisconnectRequest:      hook.NewObservedHook[*RealtimeDisconnectEvent](hookObservers),
+		onRealtimeBeforeMessageSend:      hook.NewObservedHook[*RealtimeMessageEvent](hookObservers),
+		onRealtimeAfterMessageSend:       hook.NewObservedHook[*RealtimeMessageEvent](hookObservers),
+		onRealtimeBeforeSubscribeRequest: hook.NewObservedHook[*RealtimeSubscribeEvent](hookObservers),
+		onRealtimeAfterSubscribeRequest:  hook.NewObservedHook[*RealtimeSubscribeEvent](hookObservers),
 
 		// settings API event hooks
-		onSettingsListRequest:         &hook.Hook[*SettingsListEvent]{},
-		onSettingsBeforeUpdateRequest: &hook.Hook[*SettingsUpdateEvent]{},
-		onSettingsAfterUpdateRequest:  &hook.Hook[*SettingsUpdateEvent]{},
+		onSettingsListRequest:         hook.NewObservedHook[*SettingsListEvent](hookObservers),
+		onSettingsBeforeUpdateRequest: hook.NewObservedHook[*SettingsUpdateEvent](hookObservers),
+		onSettingsAfterUpdateRequest:  hook.NewObservedHook[*SettingsUpdateEvent](hookObservers),
 
 		// file API event hooks
-		onFileDownloadRequest:    &hook.Hook[*FileDownloadEvent]{},
-		onFileBeforeTokenRequest: &hook.Hook[*FileTokenEvent]{},
-		onFileAfterTokenRequest:  &hook.Hook[*FileTokenEvent]{},
+		onFileDownloadRequest:    hook.NewObservedHook[*FileDownloadEvent](hookObservers),
+		onFileBeforeTokenRequest: hook.NewObservedHook[*FileTokenEvent](hookObservers),
+		onFileAfterTokenRequest:  hook.NewObservedHook[*FileTokenEvent](hookObservers),
 
 		// admin API event hooks
-		onAdminsListRequest:                      &hook.Hook[*AdminsListEvent]{},
-		onAdminViewRequest:                       &hook.Hook[*AdminViewEvent]{},
-		onAdminBeforeCreateRequest:               &hook.Hook[*AdminCreateEvent]{},
-		onAdminAfterCreateRequest:                &hook.Hook[*AdminCreateEvent]{},
-		onAdminBeforeUpdateRequest:               &hook.Hook[*AdminUpdateEvent]{},
-		onAdminAfterUpdateRequest:                &hook.Hook[*AdminUpdateEvent]{},
-		onAdminBeforeDeleteRequest:               &hook.Hook[*AdminDeleteEvent]{},
-		onAdminAfterDeleteRequest:                &hook.Hook[*AdminDeleteEvent]{},
-		onAdminAuthRequest:                       &hook.Hook[*AdminAuthEvent]{},
-		onAdminBeforeAuthWithPasswordRequest:     &hook.Hook[*AdminAuthWithPasswordEvent]{},
-		onAdminAfterAuthWithPasswordRequest:      &hook.Hook[*AdminAuthWithPasswordEvent]{},
-		onAdminBeforeAuthRefreshRequest:          &hook.Hook[*AdminAuthRefreshEvent]{},
-		onAdminAfterAuthRefreshRequest:           &hook.Hook[*AdminAuthRefreshEvent]{},
-		onAdminBeforeRequestPasswordResetRequest: &hook.Hook[*AdminRequestPasswordResetEvent]{},
-		onAdminAfterRequestPasswordResetRequest:  &hook.Hook[*AdminRequestPasswordResetEvent]{},
-		onAdminBeforeConfirmPasswordResetRequest: &hook.Hook[*AdminConfirmPasswordResetEvent]{},
-		onAdminAfterConfirmPasswordResetRequest:  &hook.Hook[*AdminConfirmPasswordResetEvent]{},
+		onAdminsListRequest:                      hook.NewObservedHook[*AdminsListEvent](hookObservers),
+		onAdminViewRequest:                       hook.NewObservedHook[*AdminViewEvent](hookObservers),
+		onAdminBeforeCreateRequest:               hook.NewObservedHook[*AdminCreateEvent](hookObservers),
+		onAdminAfterCreateRequest:                hook.NewObservedHook[*AdminCreateEvent](hookObservers),
+		onAdminBeforeUpdateRequest:               hook.NewObservedHook[*AdminUpdateEvent](hookObservers),
+		onAdminAfterUpdateRequest:                hook.NewObservedHook[*AdminUpdateEvent](hookObservers),
+		onAdminBeforeDeleteRequest:               hook.NewObservedHook[*AdminDeleteEvent](hookObservers),
+		onAdminAfterDeleteRequest:                hook.NewObservedHook[*AdminDeleteEvent](hookObservers),
+		onAdminAuthRequest:                       hook.NewObservedHook[*AdminAuthEvent](hookObservers),
+		onAdminBeforeAuthWithPasswordRequest:     hook.NewObservedHook[*AdminAuthWithPasswordEvent](hookObservers),
+		onAdminAfterAuthWithPasswordRequest:      hook.NewObservedHook[*AdminAuthWithPasswordEvent](hookObservers),
+		onAdminBeforeAuthRefreshRequest:          hook.NewObservedHook[*AdminAuthRefreshEvent](hookObservers),
+		onAdminAfterAuthRefreshRequest:           hook.NewObservedHook[*AdminAuthRefreshEvent](hookObservers),
+		onAdminBeforeRequestPasswordResetRequest: hook.NewObservedHook[*AdminRequestPasswordResetEvent](hookObservers),
+		onAdminAfterRequestPasswordResetRequest:  hook.NewObservedHook[*AdminRequestPasswordResetEvent](hookObservers),
+		onAdminBeforeConfirmPasswordResetRequest: hook.NewObservedHook[*AdminConfirmPasswordResetEvent](hookObservers),
+		onAdminAfterConfirmPasswordResetRequest:  hook.NewObservedHook[*AdminConfirmPasswordResetEvent](hookObservers),
 
 		// record auth API event hooks
-		onRecordAuthRequest:                       &hook.Hook[*RecordAuthEvent]{},
-		onRecordBeforeAuthWithPasswordRequest:     &hook.Hook[*RecordAuthWithPasswordEvent]{},
-		onRecordAfterAuthWithPasswordRequest:      &hook.Hook[*RecordAuthWithPasswordEvent]{},
-		onRecordBeforeAuthWithOAuth2Request:       &hook.Hook[*RecordAuthWithOAuth2Event]{},
-		onRecordAfterAuthWithOAuth2Request:        &hook.Hook[*RecordAuthWithOAuth2Event]{},
-		onRecordBeforeAuthRefreshRequest:          &hook.Hook[*RecordAuthRefreshEvent]{},
-		onRecordAfterAuthRefreshRequest:           &hook.Hook[*RecordAuthRefreshEvent]{},
-		onRecordBeforeRequestPasswordResetRequest: &hook.Hook[*RecordRequestPasswordResetEvent]{},
-		onRecordAfterRequestPasswordResetRequest:  &hook.Hook[*RecordRequestPasswordResetEvent]{},
-		onRecordBeforeConfirmPasswordResetRequest: &hook.Hook[*RecordConfirmPasswordResetEvent]{},
-		onRecordAfterConfirmPasswordResetRequest:  &hook.Hook[*RecordConfirmPasswordResetEvent]{},
-		onRecordBeforeRequestVerificationRequest:  &hook.Hook[*RecordRequestVerificationEvent]{},
-		onRecordAfterRequestVerificationRequest:   &hook.Hook[*RecordRequestVerificationEvent]{},
-		onRecordBeforeConfirmVerificationRequest:  &hook.Hook[*RecordConfirmVerificationEvent]{},
-		onRecordAfterConfirmVerificationRequest:   &hook.Hook[*RecordConfirmVerificationEvent]{},
-		onRecordBeforeRequestEmailChangeRequest:   &hook.Hook[*RecordRequestEmailChangeEvent]{},
-		onRecordAfterRequestEmailChangeRequest:    &hook.Hook[*RecordRequestEmailChangeEvent]{},
-		onRecordBeforeConfirmEmailChangeRequest:   &hook.Hook[*RecordConfirmEmailChangeEvent]{},
-		onRecordAfterConfirmEmailChangeRequest:    &hook.Hook[*RecordConfirmEmailChangeEvent]{},
-		onRecordListExternalAuthsRequest:          &hook.Hook[*RecordListExternalAuthsEvent]{},
-		onRecordBeforeUnlinkExternalAuthRequest:   &hook.Hook[*RecordUnlinkExternalAuthEvent]{},
-		onRecordAfterUnlinkExternalAuthRequest:    &hook.Hook[*RecordUnlinkExternalAuthEvent]{},
+		onRecordAuthRequest:                       hook.NewObservedHook[*RecordAuthEvent](hookObservers),
+		onRecordBeforeAuthWithPasswordRequest:     hook.NewObservedHook[*RecordAuthWithPasswordEvent](hookObservers),
+		onRecordAfterAuthWithPasswordRequest:      hook.NewObservedHook[*RecordAuthWithPasswordEvent](hookObservers),
+		onRecordBeforeAuthWithOAuth2Request:       hook.NewObservedHook[*RecordAuthWithOAuth2Event](hookObservers),
+		onRecordAfterAuthWithOAuth2Request:        hook.NewObservedHook[*RecordAuthWithOAuth2Event](hookObservers),
+		onRecordBeforeAuthWithOTPRequest:          hook.NewObservedHook[*RecordAuthWithOTPEvent](hookObservers),
+		onRecordAfterAuthWithOTPRequest:           hook.NewObservedHook[*RecordAuthWithOTPEvent](hookObservers),
+		onRecordBeforeAuthWithWebAuthnRequest:     hook.NewObservedHook[*RecordAuthWithWebAuthnEvent](hookObservers),
+		onRecordAfterAuthWithWebAuthnRequest:      hook.NewObservedHook[*RecordAuthWithWebAuthnEvent](hookObservers),
+		onRecordBeforeAuthRefreshRequest:          hook.NewObservedHook[*RecordAuthRefreshEvent](hookObservers),
+		onRecordAfterAuthRefreshRequest:           hook.NewObservedHook[*RecordAuthRefreshEvent](hookObservers),
+		onRecordBeforeRequestPasswordResetRequest: hook.NewObservedHook[*RecordRequestPasswordResetEvent](hookObservers),
+		onRecordAfterRequestPasswordResetRequest:  hook.NewObservedHook[*RecordRequestPasswordResetEvent](hookObservers),
+		onRecordBeforeConfirmPasswordResetRequest: hook.NewObservedHook[*RecordConfirmPasswordResetEvent](hookObservers),
+		onRecordAfterConfirmPasswordResetRequest:  hook.NewObservedHook[*RecordConfirmPasswordResetEvent](hookObservers),
+		onRecordBeforeRequestVerificationRequest:  hook.NewObservedHook[*RecordRequestVerificationEvent](hookObservers),
+		onRecordAfterRequestVerificationRequest:   hook.NewObservedHook[*RecordRequestVerificationEvent](hookObservers),
+		onRecordBeforeConfirmVerificationRequest:  hook.NewObservedHook[*RecordConfirmVerificationEvent](hookObservers),
+		onRecordAfterConfirmVerificationRequest:   hook.NewObservedHook[*RecordConfirmVerificationEvent](hookObservers),
+		onRecordBeforeRequestEmailChangeRequest:   hook.NewObservedHook[*RecordRequestEmailChangeEvent](hookObservers),
+		onRecordAfterRequestEmailChangeRequest:    hook.NewObservedHook[*RecordRequestEmailChangeEvent](hookObservers),
+		onRecordBeforeConfirmEmailChangeRequest:   hook.NewObservedHook[*RecordConfirmEmailChangeEvent](hookObservers),
+		onRecordAfterConfirmEmailChangeRequest:    hook.NewObservedHook[*RecordConfirmEmailChangeEvent](hookObservers),
+		onRecordListExternalAuthsRequest:          hook.NewObservedHook[*RecordListExternalAuthsEvent](hookObservers),
+		onRecordBeforeUnlinkExternalAuthRequest:   hook.NewObservedHook[*RecordUnlinkExternalAuthEvent](hookObservers),
+		onRecordAfterUnlinkExternalAuthRequest:    hook.NewObservedHook[*RecordUnlinkExternalAuthEvent](hookObservers),
 
 		// record crud API event hooks
-		onRecordsListRequest:        &hook.Hook[*RecordsListEvent]{},
-		onRecordViewRequest:         &hook.Hook[*RecordViewEvent]{},
-		onRecordBeforeCreateRequest: &hook.Hook[*RecordCreateEvent]{},
-		onRecordAfterCreateRequest:  &hook.Hook[*RecordCreateEvent]{},
-		onRecordBeforeUpdateRequest: &hook.Hook[*RecordUpdateEvent]{},
-		onRecordAfterUpdateRequest:  &hook.Hook[*RecordUpdateEvent]{},
-		onRecordBeforeDeleteRequest: &hook.Hook[*RecordDeleteEvent]{},
-		onRecordAfterDeleteRequest:  &hook.Hook[*RecordDeleteEvent]{},
+		onRecordsListRequest:        hook.NewObservedHook[*RecordsListEvent](hookObservers),
+		onRecordViewRequest:         hook.NewObservedHook[*RecordViewEvent](hookObservers),
+		onRecordBeforeCreateRequest: hook.NewObservedHook[*RecordCreateEvent](hookObservers),
+		onRecordAfterCreateRequest:  hook.NewObservedHook[*RecordCreateEvent](hookObservers),
+		onRecordBeforeUpdateRequest: hook.NewObservedHook[*RecordUpdateEvent](hookObservers),
+		onRecordAfterUpdateRequest:  hook.NewObservedHook[*RecordUpdateEvent](hookObservers),
+		onRecordBeforeDeleteRequest: hook.NewObservedHook[*RecordDeleteEvent](hookObservers),
+		onRecordAfterDeleteRequest:  hook.NewObservedHook[*RecordDeleteEvent](hookObservers),
 
 		// collection API event hooks
-		onCollectionsListRequest:         &hook.Hook[*CollectionsListEvent]{},
-		onCollectionViewRequest:          &hook.Hook[*CollectionViewEvent]{},
-		onCollectionBeforeCreateRequest:  &hook.Hook[*CollectionCreateEvent]{},
-		onCollectionAfterCreateRequest:   &hook.Hook[*CollectionCreateEvent]{},
-		onCollectionBeforeUpdateRequest:  &hook.Hook[*CollectionUpdateEvent]{},
-		onCollectionAfterUpdateRequest:   &hook.Hook[*CollectionUpdateEvent]{},
-		onCollectionBeforeDeleteRequest:  &hook.Hook[*CollectionDeleteEvent]{},
-		onCollectionAfterDeleteRequest:   &hook.Hook[*CollectionDeleteEvent]{},
-		onCollectionsBeforeImportRequest: &hook.Hook[*CollectionsImportEvent]{},
-		onCollectionsAfterImportRequest:  &hook.Hook[*CollectionsImportEvent]{},
+		onCollectionsListRequest:         hook.NewObservedHook[*CollectionsListEvent](hookObservers),
+		onCollectionViewRequest:          hook.NewObservedHook[*CollectionViewEvent](hookObservers),
+		onCollectionBeforeCreateRequest:  hook.NewObservedHook[*CollectionCreateEvent](hookObservers),
+		onCollectionAfterCreateRequest:   hook.NewObservedHook[*CollectionCreateEvent](hookObservers),
+		onCollectionBeforeUpdateRequest:  hook.NewObservedHook[*CollectionUpdateEvent](hookObservers),
+		onCollectionAfterUpdateRequest:   hook.NewObservedHook[*CollectionUpdateEvent](hookObservers),
+		onCollectionBeforeDeleteRequest:  hook.NewObservedHook[*CollectionDeleteEvent](hookObservers),
+		onCollectionAfterDeleteRequest:   hook.NewObservedHook[*CollectionDeleteEvent](hookObservers),
+		onCollectionsBeforeImportRequest: hook.NewObservedHook[*CollectionsImportEvent](hookObservers),
+		onCollectionsAfterImportRequest:  hook.NewObservedHook[*CollectionsImportEvent](hookObservers),
+
+		// health API event hooks
+		onHealthReadyRequest: hook.NewObservedHook[*HealthReadyEvent](hookObservers),
 	}
 
 	app.registerDefaultHooks()
+	app.registerMetricsCollectors()
 
 	return app
 }
@@ -333,6 +390,20 @@ func (app *BaseApp) Logger() *slog.Logger {
 	return app.logger
 }
 
+// ContextLogger returns a request-scoped child of the app logger that
+// attaches the ctx request id (see [ContextWithRequestId]) to all of its
+// log records, including the ones written without ctx (eg. logger.Info(...)).
+//
+// It returns the app logger as it is if ctx doesn't have a request id.
+func (app *BaseApp) ContextLogger(ctx context.Context) *slog.Logger {
+	requestId := RequestIdFromContext(ctx)
+	if requestId == "" {
+		return app.Logger()
+	}
+
+	return app.Logger().With(slog.String("requestId", requestId))
+}
+
 // Bootstrap initializes the application
 // (aka. create data dir, open db connections, load settings, etc.).
 //
@@ -366,9 +437,14 @@ func (app *BaseApp) Bootstrap() error {
 		return err
 	}
 
+	app.initHookErrorsMetrics()
+	app.initHookTracing()
+
 	// we don't check for an error because the db migrations may have not been executed yet
 	app.RefreshSettings()
 
+	app.initCronPauseState()
+
 	// cleanup the pb_data temp directory (if any)
 	os.RemoveAll(filepath.Join(app.DataDir(), LocalTempDirName))
 
@@ -396,6 +472,16 @@ func (app *BaseApp) ResetBootstrapState() error {
 		}
 	}
 
+	if app.hookErrorsObserverId != "" {
+		app.hookObservers.RemoveErrorObserver(app.hookErrorsObserverId)
+		app.hookErrorsObserverId = ""
+	}
+
+	if app.hookTracingObserverId != "" {
+		app.hookObservers.RemoveTriggerObserver(app.hookTracingObserverId)
+		app.hookTracingObserverId = ""
+	}
+
 	app.dao = nil
 	app.logsDao = nil
 
@@ -489,6 +575,11 @@ func (app *BaseApp) SubscriptionsBroker() *subscriptions.Broker {
 	return app.subscriptionsBroker
 }
 
+// Cron returns the app cron instance.
+func (app *BaseApp) Cron() *cron.Cron {
+	return app.cron
+}
+
 // NewMailClient creates and returns a new SMTP or Sendmail client
 // based on the current app settings.
 func (app *BaseApp) NewMailClient() mailer.Mailer {
@@ -507,47 +598,90 @@ func (app *BaseApp) NewMailClient() mailer.Mailer {
 	return &mailer.Sendmail{}
 }
 
-// NewFilesystem creates a new local or S3 filesystem instance
+// SetFileScanner replaces the default settings based ClamAV file scanner
+// with a custom one (eg. a third party antivirus api client).
+//
+// Set it to nil to restore the default behavior.
+func (app *BaseApp) SetFileScanner(fileScanner scanner.Scanner) {
+	app.fileScanner = fileScanner
+}
+
+// NewFileScanner creates and returns a new file scanner based on the
+// current app settings (or the custom one set with [BaseApp.SetFileScanner]).
+//
+// Returns nil if the file uploads scanning is disabled.
+func (app *BaseApp) NewFileScanner() (scanner.Scanner, error) {
+	config := app.Settings().FileScan
+
+	if !config.Enabled {
+		return nil, nil
+	}
+
+	if app.fileScanner != nil {
+		return app.fileScanner, nil
+	}
+
+	return scanner.NewClamAV(config.Address, time.Duration(config.Timeout)*time.Second)
+}
+
+// NewFilesystem creates a new local, S3 or custom storage driver filesystem instance
 // for managing regular app files (eg. collection uploads)
 // based on the current app settings.
 //
 // NB! Make sure to call Close() on the returned result
 // after you are done working with it.
+//
+// If the app encryption key is set, the encrypted files are
+// transparently decrypted on read and, if the file encryption is
+// enabled in the settings, the new files are encrypted on write.
+//
+// The deduplicated files blob references are tracked with the app Dao.
 func (app *BaseApp) NewFilesystem() (*filesystem.System, error) {
-	if app.settings != nil && app.settings.S3.Enabled {
-		return filesystem.NewS3(
-			app.settings.S3.Bucket,
-			app.settings.S3.Region,
-			app.settings.S3.Endpoint,
-			app.settings.S3.AccessKey,
-			app.settings.S3.Secret,
-			app.settings.S3.ForcePathStyle,
-		)
+	var storage settings.StorageConfig
+	var s3 settings.S3Config
+	if app.settings != nil {
+		storage = app.settings.Storage
+		s3 = app.settings.S3
+	}
+
+	fsys, err := NewStorageFilesystem(app, storage, s3, filepath.Join(app.DataDir(), LocalStorageDirName))
+	if err != nil {
+		return nil, err
+	}
+
+	encrypt := app.settings != nil && app.settings.FileEncryption.Enabled
+
+	if encryptionKey := os.Getenv(app.EncryptionEnv()); encryptionKey != "" {
+		if err := fsys.SetEncryptionKey(encryptionKey, encrypt); err != nil && encrypt {
+			fsys.Close()
+			return nil, err
+		}
+	} else if encrypt {
+		fsys.Close()
+		return nil, errors.New("the file encryption requires the app encryption key to be set")
 	}
 
-	// fallback to local filesystem
-	return filesystem.NewLocal(filepath.Join(app.DataDir(), LocalStorageDirName))
+	if app.dao != nil {
+		fsys.SetDedupIndex(app.dao)
+	}
+
+	return fsys, nil
 }
 
-// NewFilesystem creates a new local or S3 filesystem instance
+// NewBackupsFilesystem creates a new local, S3 or custom storage driver filesystem instance
 // for managing app backups based on the current app settings.
 //
 // NB! Make sure to call Close() on the returned result
 // after you are done working with it.
 func (app *BaseApp) NewBackupsFilesystem() (*filesystem.System, error) {
-	if app.settings != nil && app.settings.Backups.S3.Enabled {
-		return filesystem.NewS3(
-			app.settings.Backups.S3.Bucket,
-			app.settings.Backups.S3.Region,
-			app.settings.Backups.S3.Endpoint,
-			app.settings.Backups.S3.AccessKey,
-			app.settings.Backups.S3.Secret,
-			app.settings.Backups.S3.ForcePathStyle,
-		)
+	var storage settings.StorageConfig
+	var s3 settings.S3Config
+	if app.settings != nil {
+		storage = app.settings.Backups.Storage
+		s3 = app.settings.Backups.S3
 	}
 
-	// fallback to local filesystem
-	return filesystem.NewLocal(filepath.Join(app.DataDir(), LocalBackupsDirName))
+	return NewStorageFilesystem(app, storage, s3, filepath.Join(app.DataDir(), LocalBackupsDirName))
 }
 
 // Restart restarts (aka. replaces) the current running application process.
@@ -599,13 +733,19 @@ func (app *BaseApp) RefreshSettings() error {
 		return err
 	}
 
-	// reload handler level (if initialized)
+	// reload handler level and sinks (if initialized)
 	if app.Logger() != nil {
 		if h, ok := app.Logger().Handler().(*logger.BatchHandler); ok {
 			h.SetLevel(app.getLoggerMinLevel())
+			app.refreshLogSinks(h)
 		}
 	}
 
+	// reload the tracer (if the tracing settings have changed)
+	app.tracerMux.Lock()
+	app.refreshTracer()
+	app.tracerMux.Unlock()
+
 	return nil
 }
 
@@ -701,6 +841,14 @@ func (app *BaseApp) OnMailerAfterRecordChangeEmailSend(tags ...string) *hook.Tag
 	return hook.NewTaggedHook(app.onMailerAfterRecordChangeEmailSend, tags...)
 }
 
+func (app *BaseApp) OnMailerBeforeRecordOTPSend(tags ...string) *hook.TaggedHook[*MailerRecordEvent] {
+	return hook.NewTaggedHook(app.onMailerBeforeRecordOTPSend, tags...)
+}
+
+func (app *BaseApp) OnMailerAfterRecordOTPSend(tags ...string) *hook.TaggedHook[*MailerRecordEvent] {
+	return hook.NewTaggedHook(app.onMailerAfterRecordOTPSend, tags...)
+}
+
 // -------------------------------------------------------------------
 // Realtime API event hooks
 // -------------------------------------------------------------------
@@ -857,6 +1005,22 @@ func (app *BaseApp) OnRecordAfterAuthWithOAuth2Request(tags ...string) *hook.Tag
 	return hook.NewTaggedHook(app.onRecordAfterAuthWithOAuth2Request, tags...)
 }
 
+func (app *BaseApp) OnRecordBeforeAuthWithOTPRequest(tags ...string) *hook.TaggedHook[*RecordAuthWithOTPEvent] {
+	return hook.NewTaggedHook(app.onRecordBeforeAuthWithOTPRequest, tags...)
+}
+
+func (app *BaseApp) OnRecordAfterAuthWithOTPRequest(tags ...string) *hook.TaggedHook[*RecordAuthWithOTPEvent] {
+	return hook.NewTaggedHook(app.onRecordAfterAuthWithOTPRequest, tags...)
+}
+
+func (app *BaseApp) OnRecordBeforeAuthWithWebAuthnRequest(tags ...string) *hook.TaggedHook[*RecordAuthWithWebAuthnEvent] {
+	return hook.NewTaggedHook(app.onRecordBeforeAuthWithWebAuthnRequest, tags...)
+}
+
+func (app *BaseApp) OnRecordAfterAuthWithWebAuthnRequest(tags ...string) *hook.TaggedHook[*RecordAuthWithWebAuthnEvent] {
+	return hook.NewTaggedHook(app.onRecordAfterAuthWithWebAuthnRequest, tags...)
+}
+
 func (app *BaseApp) OnRecordBeforeAuthRefreshRequest(tags ...string) *hook.TaggedHook[*RecordAuthRefreshEvent] {
 	return hook.NewTaggedHook(app.onRecordBeforeAuthRefreshRequest, tags...)
 }
@@ -1005,6 +1169,14 @@ func (app *BaseApp) OnCollectionsAfterImportRequest() *hook.Hook[*CollectionsImp
 	return app.onCollectionsAfterImportRequest
 }
 
+// -------------------------------------------------------------------
+// Health API event hooks
+// -------------------------------------------------------------------
+
+func (app *BaseApp) OnHealthReadyRequest() *hook.Hook[*HealthReadyEvent] {
+	return app.onHealthReadyRequest
+}
+
 // -------------------------------------------------------------------
 // Helpers
 // -------------------------------------------------------------------
@@ -1035,6 +1207,9 @@ func (app *BaseApp) initLogsDB() error {
 	nonconcurrentDB.DB().SetMaxIdleConns(1)
 	nonconcurrentDB.DB().SetConnMaxIdleTime(3 * time.Minute)
 
+	app.attachDBMetrics(concurrentDB, "logs")
+	app.attachDBMetrics(nonconcurrentDB, "logs")
+
 	app.logsDao = daos.NewMultiDB(concurrentDB, nonconcurrentDB)
 
 	return nil
@@ -1077,6 +1252,13 @@ func (app *BaseApp) initDataDB() error {
 		concurrentDB.ExecLogFunc = nonconcurrentDB.ExecLogFunc
 	}
 
+	app.attachDBMetrics(concurrentDB, "data")
+	app.attachDBMetrics(nonconcurrentDB, "data")
+	app.attachDBTracing(concurrentDB, "data")
+	app.attachDBTracing(nonconcurrentDB, "data")
+	app.attachSlowQueryLog(concurrentDB, "data")
+	app.attachSlowQueryLog(nonconcurrentDB, "data")
+
 	app.dao = app.createDaoWithHooks(concurrentDB, nonconcurrentDB)
 
 	return nil
@@ -1179,17 +1361,52 @@ func (app *BaseApp) registerDefaultHooks() {
 		return nil
 	})
 
+	// start the cron ticker on serve and stop it on app termination
+	app.OnBeforeServe().Add(func(e *ServeEvent) error {
+		app.Cron().Start()
+		return nil
+	})
+	app.OnTerminate().Add(func(e *TerminateEvent) error {
+		app.Cron().Stop()
+		return nil
+	})
+
+	// flush the remaining traces on app termination
+	app.OnTerminate().Add(func(e *TerminateEvent) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := app.Tracer().Shutdown(ctx); err != nil {
+			app.Logger().Debug("Failed to flush the remaining traces", slog.String("error", err.Error()))
+		}
+
+		return nil
+	})
+
 	if err := app.initAutobackupHooks(); err != nil {
 		app.Logger().Error("Failed to init auto backup hooks", slog.String("error", err.Error()))
 	}
 
+	if err := app.initMaterializedViewsHooks(); err != nil {
+		app.Logger().Error("Failed to init materialized views hooks", slog.String("error", err.Error()))
+	}
+
+	if err := app.initFilesGCHooks(); err != nil {
+		app.Logger().Error("Failed to init files gc hooks", slog.String("error", err.Error()))
+	}
+
+	if err := app.initLogsArchiveHooks(); err != nil {
+		app.Logger().Error("Failed to init logs archive hooks", slog.String("error", err.Error()))
+	}
+
 	registerCachedCollectionsAppHooks(app)
 }
 
 // getLoggerMinLevel returns the logger min level based on the
 // app configurations (dev mode, settings, etc.).
 //
-// If not in dev mode - returns the level from the app settings.
+// If not in dev mode - returns the lowest level from the app logs
+// settings and the enabled logs sinks.
 //
 // If the app is in dev mode it returns -9999 level allowing to print
 // practically all logs to the terminal.
@@ -1202,32 +1419,57 @@ func (app *BaseApp) getLoggerMinLevel() slog.Level {
 		minLevel = -9999
 	} else if app.Settings() != nil {
 		minLevel = slog.Level(app.Settings().Logs.MinLevel)
+
+		for _, sink := range app.Settings().Logs.Sinks {
+			if sink.Enabled && slog.Level(sink.MinLevel) < minLevel {
+				minLevel = slog.Level(sink.MinLevel)
+			}
+		}
 	}
 
 	return minLevel
 }
 
+// isDBLog reports whether the provided log should be persisted in the logs db.
+func (app *BaseApp) isDBLog(log *logger.Log) bool {
+	return app.Settings().Logs.MaxDays > 0 && log.Level >= slog.Level(app.Settings().Logs.MinLevel)
+}
+
 func (app *BaseApp) initLogger() error {
 	duration := 3 * time.Second
 	ticker := time.NewTicker(duration)
 	done := make(chan bool)
 
-	handler := logger.NewBatchHandler(logger.BatchOptions{
+	// reset the sinks state in case of a bootstrap restart
+	app.logSinksMux.Lock()
+	app.logSinksConfig = nil
+	app.logSinksMux.Unlock()
+
+	var handler *logger.BatchHandler
+
+	handler = logger.NewBatchHandler(logger.BatchOptions{
 		Level:     app.getLoggerMinLevel(),
 		BatchSize: 200,
 		BeforeAddFunc: func(ctx context.Context, log *logger.Log) bool {
+			// correlate the logs written within a request context
+			if requestId := RequestIdFromContext(ctx); requestId != "" {
+				if _, ok := log.Data["requestId"]; !ok {
+					log.Data["requestId"] = requestId
+				}
+			}
+
 			if app.IsDev() {
 				printLog(log)
+			}
 
-				// manually check the log level and skip if necessary
-				if log.Level < slog.Level(app.Settings().Logs.MinLevel) {
-					return false
-				}
+			// the sinks have their own level filters
+			if len(handler.Sinks()) == 0 && !app.isDBLog(log) {
+				return false
 			}
 
 			ticker.Reset(duration)
 
-			return app.Settings().Logs.MaxDays > 0
+			return true
 		},
 		WriteFunc: func(ctx context.Context, logs []*logger.Log) error {
 			if !app.IsBootstrapped() || app.Settings().Logs.MaxDays == 0 {
@@ -1239,6 +1481,11 @@ func (app *BaseApp) initLogger() error {
 			app.LogsDao().RunInTransaction(func(txDao *daos.Dao) error {
 				model := &models.Log{}
 				for _, l := range logs {
+					// skip the logs queued only for the sinks
+					if !app.isDBLog(l) {
+						continue
+					}
+
 					model.MarkAsNew()
 					// note: using pseudorandom for a slightly better performance
 					// !CHANGED: id changed to snowflake
@@ -1258,7 +1505,12 @@ func (app *BaseApp) initLogger() error {
 			})
 
 			// delete old logs
+			// (when the logs archive is enabled they are deleted by the logs archive cron job)
 			// ---
+			if app.Settings().Logs.Archive {
+				return nil
+			}
+
 			logsMaxDays := app.Settings().Logs.MaxDays
 			now := time.Now()
 			lastLogsDeletedAt := cast.ToTime(app.Store().Get("lastLogsDeletedAt"))
@@ -1295,6 +1547,9 @@ func (app *BaseApp) initLogger() error {
 		// write all remaining logs before ticker.Stop to avoid races with ResetBootstrap user calls
 		handler.WriteAll(context.Background())
 
+		// release the sinks resources (opened files, connections, etc.)
+		handler.SetSinks()
+
 		ticker.Stop()
 
 		done <- true
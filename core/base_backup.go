@@ -15,7 +15,6 @@ import (
 	"github.com/AlperRehaYAZGAN/postgresbase/daos"
 	"github.com/AlperRehaYAZGAN/postgresbase/models"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/archive"
-	"github.com/AlperRehaYAZGAN/postgresbase/tools/cron"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/filesystem"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/inflector"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/osutils"
@@ -58,7 +57,7 @@ func (app *BaseApp) CreateBackup(ctx context.Context, name string) error {
 	defer app.Store().Remove(StoreKeyActiveBackup)
 
 	// root dir entries to exclude from the backup generation
-	exclude := []string{LocalBackupsDirName, LocalTempDirName}
+	exclude := []string{LocalBackupsDirName, LocalTempDirName, LocalUploadsDirName}
 
 	// make sure that the special temp directory exists
 	// note: it needs to be inside the current pb_data to avoid "cross-device link" errors
@@ -240,14 +239,14 @@ func (app *BaseApp) RestoreBackup(ctx context.Context, name string) error {
 	return nil
 }
 
+// AutobackupCronJobId is the id of the app cron job used for the scheduled backups.
+const AutobackupCronJobId string = "@autobackup"
+
 // initAutobackupHooks registers the autobackup app serve hooks.
 func (app *BaseApp) initAutobackupHooks() error {
-	c := cron.New()
 	isServe := false
 
 	loadJob := func() {
-		c.Stop()
-
 		// make sure that app.Settings() is always up to date
 		//
 		// @todo remove with the refactoring as core.App and daos.Dao will be one.
@@ -260,10 +259,12 @@ func (app *BaseApp) initAutobackupHooks() error {
 
 		rawSchedule := app.Settings().Backups.Cron
 		if rawSchedule == "" || !isServe || !app.IsBootstrapped() {
+			app.Cron().Remove(AutobackupCronJobId)
 			return
 		}
 
-		c.Add("@autobackup", rawSchedule, func() {
+		// note: replacing the existing job preserves its paused state
+		app.Cron().Add(AutobackupCronJobId, rawSchedule, func() {
 			const autoPrefix = "@auto_pb_backup_"
 
 			name := app.generateBackupName(autoPrefix)
@@ -323,9 +324,6 @@ func (app *BaseApp) initAutobackupHooks() error {
 				}
 			}
 		})
-
-		// restart the ticker
-		c.Start()
 	}
 
 	// load on app serve
@@ -335,12 +333,6 @@ func (app *BaseApp) initAutobackupHooks() error {
 		return nil
 	})
 
-	// stop the ticker on app termination
-	app.OnTerminate().Add(func(e *TerminateEvent) error {
-		c.Stop()
-		return nil
-	})
-
 	// reload on app settings change
 	app.OnModelAfterUpdate((&models.Param{}).TableName()).Add(func(e *ModelEvent) error {
 		p := e.Model.(*models.Param)
@@ -162,3 +162,50 @@ func getEntryNames(entries []fs.DirEntry) []string {
 
 	return names
 }
+
+func TestAutobackupCronJobPausedState(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().Backups.Cron = "0 0 * * *"
+	if err := app.Dao().SaveSettings(app.Settings()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.OnBeforeServe().Trigger(&core.ServeEvent{App: app}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Cron().Pause(core.AutobackupCronJobId); err != nil {
+		t.Fatalf("Expected the autobackup job to be registered, got %v", err)
+	}
+
+	// reload the job with a new schedule
+	app.Settings().Backups.Cron = "0 1 * * *"
+	if err := app.Dao().SaveSettings(app.Settings()); err != nil {
+		t.Fatal(err)
+	}
+
+	job := app.Cron().Job(core.AutobackupCronJobId)
+	if job == nil {
+		t.Fatal("Expected the autobackup job to be registered")
+	}
+
+	if job.Expression() != "0 1 * * *" {
+		t.Fatalf("Expected the job to be reloaded, got expression %q", job.Expression())
+	}
+
+	if !job.IsPaused() {
+		t.Fatal("Expected the job paused state to be preserved")
+	}
+
+	// disable the autobackups
+	app.Settings().Backups.Cron = ""
+	if err := app.Dao().SaveSettings(app.Settings()); err != nil {
+		t.Fatal(err)
+	}
+
+	if app.Cron().Job(core.AutobackupCronJobId) != nil {
+		t.Fatal("Expected the autobackup job to be removed")
+	}
+}
@@ -0,0 +1,36 @@
+package core
+
+import (
+	"encoding/json"
+	"log/slog"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+)
+
+// initCronPauseState restores the persisted paused cron jobs and
+// registers a cron pause observer that persists their changes
+// so that the paused jobs remain paused after app restart.
+func (app *BaseApp) initCronPauseState() {
+	// we don't check for an error because the db migrations may have not been executed yet
+	if param, err := app.Dao().FindParamByKey(models.ParamCronPausedJobs); err == nil {
+		pausedJobIds := []string{}
+
+		if err := json.Unmarshal(param.Value, &pausedJobIds); err != nil {
+			app.Logger().Warn(
+				"[Cron] Failed to load the paused jobs",
+				slog.String("error", err.Error()),
+			)
+		} else {
+			app.cron.SetPausedJobs(pausedJobIds)
+		}
+	}
+
+	app.cron.SetPauseObserver(func(pausedJobIds []string) {
+		if err := app.Dao().SaveParam(models.ParamCronPausedJobs, pausedJobIds); err != nil {
+			app.Logger().Error(
+				"[Cron] Failed to persist the paused jobs",
+				slog.String("error", err.Error()),
+			)
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/tests"
+)
+
+func TestBaseAppCronPauseState(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Cron().MustAdd("test", "* * * * *", func() {})
+
+	if err := app.Cron().Pause("test"); err != nil {
+		t.Fatal(err)
+	}
+
+	param, err := app.Dao().FindParamByKey(models.ParamCronPausedJobs)
+	if err != nil {
+		t.Fatalf("Expected the paused jobs to be persisted, got %v", err)
+	}
+	if v := param.Value.String(); v != `["test"]` {
+		t.Fatalf("Expected the persisted paused jobs %q, got %q", `["test"]`, v)
+	}
+
+	// simulate app restart
+	app.Cron().RemoveAll()
+	app.Cron().SetPausedJobs(nil)
+	if err := app.Bootstrap(); err != nil {
+		t.Fatal(err)
+	}
+
+	app.Cron().MustAdd("test", "* * * * *", func() {})
+	if !app.Cron().Job("test").IsPaused() {
+		t.Fatal("Expected the restored test job to be paused")
+	}
+
+	if err := app.Cron().Resume("test"); err != nil {
+		t.Fatal(err)
+	}
+
+	param, err = app.Dao().FindParamByKey(models.ParamCronPausedJobs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := param.Value.String(); v != `[]` {
+		t.Fatalf("Expected no persisted paused jobs, got %q", v)
+	}
+}
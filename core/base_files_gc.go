@@ -0,0 +1,249 @@
+package core
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/daos"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/models/schema"
+	"github.com/pocketbase/dbx"
+)
+
+// FilesGCCronJobId is the id of the app cron job used for the
+// scheduled orphaned files cleanup.
+const FilesGCCronJobId string = "@filesgc"
+
+// DefaultFilesGCMinAge is the default min age of an unreferenced
+// storage file before it is considered orphaned.
+const DefaultFilesGCMinAge = 24 * time.Hour
+
+// OrphanedFile defines a single storage file that is not referenced
+// by any collection record.
+type OrphanedFile struct {
+	CollectionId string    `json:"collectionId"`
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	ModTime      time.Time `json:"modTime"`
+}
+
+// DeleteOrphanedFiles finds (and deletes if dryRun is false) the storage
+// files of the app collections that are no longer referenced by their records.
+//
+// This includes:
+//   - the files left from failed record saves, replaced or renamed file fields
+//   - the thumbs (and other image transformations) of the orphaned files
+//   - the stale direct uploads that were never attached to a record
+//
+// Only files older than minAge are considered to avoid deleting the files
+// of the records that are still being saved.
+//
+// The returned slice contains the found (or the successfully deleted
+// if dryRun is false) orphaned files.
+func (app *BaseApp) DeleteOrphanedFiles(ctx context.Context, minAge time.Duration, dryRun bool) ([]*OrphanedFile, error) {
+	collections, err := app.Dao().FindCollectionsByType(models.CollectionTypeBase)
+	if err != nil {
+		return nil, err
+	}
+
+	authCollections, err := app.Dao().FindCollectionsByType(models.CollectionTypeAuth)
+	if err != nil {
+		return nil, err
+	}
+	collections = append(collections, authCollections...)
+
+	fsys, err := app.NewFilesystem()
+	if err != nil {
+		return nil, err
+	}
+	defer fsys.Close()
+
+	maxModTime := time.Now().Add(-minAge)
+
+	result := []*OrphanedFile{}
+
+	for _, collection := range collections {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		referenced, err := app.findReferencedFiles(collection)
+		if err != nil {
+			return result, err
+		}
+
+		prefix := collection.BaseFilesPath() + "/"
+
+		objects, err := fsys.List(prefix)
+		if err != nil {
+			return result, err
+		}
+
+		for _, obj := range objects {
+			if obj.IsDir || obj.ModTime.After(maxModTime) {
+				continue
+			}
+
+			// <recordId>/<filename>
+			// <recordId>/thumbs_<filename>/<thumb>
+			// _uploads/<random>/<filename>
+			parts := strings.SplitN(strings.TrimPrefix(obj.Key, prefix), "/", 3)
+			if len(parts) >= 2 {
+				originalKey := parts[0] + "/" + strings.TrimPrefix(parts[1], "thumbs_")
+				if len(parts) == 2 || strings.HasPrefix(parts[1], "thumbs_") {
+					if _, ok := referenced[originalKey]; ok {
+						continue
+					}
+				}
+			}
+
+			if !dryRun {
+				if err := fsys.Delete(obj.Key); err != nil {
+					app.Logger().Warn(
+						"Failed to delete orphaned file",
+						slog.String("key", obj.Key),
+						slog.String("error", err.Error()),
+					)
+					continue
+				}
+			}
+
+			result = append(result, &OrphanedFile{
+				CollectionId: collection.Id,
+				Key:          obj.Key,
+				Size:         obj.Size,
+				ModTime:      obj.ModTime,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// findReferencedFiles returns the "recordId/filename" keys of
+// all files referenced by the collection records.
+func (app *BaseApp) findReferencedFiles(collection *models.Collection) (map[string]struct{}, error) {
+	result := map[string]struct{}{}
+
+	fileFields := []string{}
+	for _, field := range collection.Schema.Fields() {
+		if field.Type == schema.FieldTypeFile {
+			fileFields = append(fileFields, field.Name)
+		}
+	}
+
+	if len(fileFields) == 0 {
+		return result, nil
+	}
+
+	collectReferenced := func(txDao *daos.Dao) error {
+		rows, err := txDao.RecordQuery(collection).
+			Select(append([]string{collection.Name + ".id"}, prefixColumns(collection.Name, fileFields)...)...).
+			Rows()
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			row := dbx.NullStringMap{}
+			if err := rows.ScanMap(row); err != nil {
+				return err
+			}
+
+			record := models.NewRecordFromNullStringMap(collection, row)
+
+			for _, name := range fileFields {
+				for _, filename := range record.GetStringSlice(name) {
+					result[record.Id+"/"+filename] = struct{}{}
+				}
+			}
+		}
+
+		return rows.Err()
+	}
+
+	var err error
+	if collection.IsMultiTenant() {
+		// collect the files of all tenants
+		// (otherwise all multi-tenant records files are considered orphaned)
+		err = app.Dao().RunWithoutTenantIsolation(collectReferenced)
+	} else {
+		err = collectReferenced(app.Dao())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func prefixColumns(table string, columns []string) []string {
+	result := make([]string, len(columns))
+
+	for i, c := range columns {
+		result[i] = table + "." + c
+	}
+
+	return result
+}
+
+// initFilesGCHooks registers the scheduled orphaned files cleanup app hooks.
+func (app *BaseApp) initFilesGCHooks() error {
+	isServe := false
+
+	loadJob := func() {
+		app.Cron().Remove(FilesGCCronJobId)
+
+		rawSchedule := app.Settings().FilesGC.Cron
+		if rawSchedule == "" || !isServe || !app.IsBootstrapped() {
+			return
+		}
+
+		app.Cron().Add(FilesGCCronJobId, rawSchedule, func() {
+			minAge := DefaultFilesGCMinAge
+			if hours := app.Settings().FilesGC.MinAge; hours > 0 {
+				minAge = time.Duration(hours) * time.Hour
+			}
+
+			files, err := app.DeleteOrphanedFiles(context.Background(), minAge, false)
+			if err != nil {
+				app.Logger().Error(
+					"[Files GC cron] Failed to delete orphaned files",
+					slog.String("error", err.Error()),
+				)
+				return
+			}
+
+			if len(files) > 0 {
+				app.Logger().Info(
+					"[Files GC cron] Deleted orphaned files",
+					slog.Int("total", len(files)),
+				)
+			}
+		})
+	}
+
+	// load on app serve
+	app.OnBeforeServe().Add(func(e *ServeEvent) error {
+		isServe = true
+		loadJob()
+		return nil
+	})
+
+	// reload on app settings change
+	app.OnModelAfterUpdate((&models.Param{}).TableName()).Add(func(e *ModelEvent) error {
+		p := e.Model.(*models.Param)
+		if p == nil || p.Key != models.ParamAppSettings {
+			return nil
+		}
+
+		loadJob()
+
+		return nil
+	})
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package core_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/tests"
+)
+
+func TestDeleteOrphanedFiles(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	fsys, err := app.NewFilesystem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fsys.Close()
+
+	orphans := []string{
+		"_pb_users_auth_/4q1xlclmfloku33/orphan.txt",
+		"_pb_users_auth_/4q1xlclmfloku33/thumbs_orphan.png/100x100_orphan.png",
+		"_pb_users_auth_/missing_record/test.txt",
+		"_pb_users_auth_/_uploads/abc/test.txt",
+	}
+	for _, key := range orphans {
+		if err := fsys.Upload([]byte("test"), key); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	referenced := []string{
+		"_pb_users_auth_/4q1xlclmfloku33/300_1SEi6Q6U72.png",
+		"_pb_users_auth_/4q1xlclmfloku33/thumbs_300_1SEi6Q6U72.png/100x100_300_1SEi6Q6U72.png",
+	}
+
+	// too young files
+	result, err := app.DeleteOrphanedFiles(context.Background(), time.Hour, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range result {
+		for _, key := range orphans {
+			if f.Key == key {
+				t.Fatalf("Expected %q to be skipped because of its min age", key)
+			}
+		}
+	}
+
+	// dry-run
+	result, err = app.DeleteOrphanedFiles(context.Background(), 0, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := map[string]bool{}
+	for _, f := range result {
+		found[f.Key] = true
+	}
+
+	for _, key := range orphans {
+		if !found[key] {
+			t.Errorf("Expected %q to be reported as orphaned file", key)
+		}
+
+		if exists, _ := fsys.Exists(key); !exists {
+			t.Errorf("Expected %q to not be deleted in dry-run mode", key)
+		}
+	}
+
+	for _, key := range referenced {
+		if found[key] {
+			t.Errorf("Expected referenced file %q to not be reported as orphaned", key)
+		}
+	}
+
+	// delete
+	if _, err := app.DeleteOrphanedFiles(context.Background(), 0, false); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range orphans {
+		if exists, _ := fsys.Exists(key); exists {
+			t.Errorf("Expected %q to be deleted", key)
+		}
+	}
+
+	for _, key := range referenced {
+		if exists, _ := fsys.Exists(key); !exists {
+			t.Errorf("Expected referenced file %q to not be deleted", key)
+		}
+	}
+}
+
+func TestDeleteOrphanedFilesMultiTenant(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	if _, err := tests.EnableTestTenantIsolation(app, "users", map[string]string{"4q1xlclmfloku33": "acme"}); err != nil {
+		t.Fatal(err)
+	}
+
+	fsys, err := app.NewFilesystem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fsys.Close()
+
+	orphan := "_pb_users_auth_/4q1xlclmfloku33/orphan.txt"
+	if err := fsys.Upload([]byte("test"), orphan); err != nil {
+		t.Fatal(err)
+	}
+
+	referenced := []string{
+		"_pb_users_auth_/4q1xlclmfloku33/300_1SEi6Q6U72.png",
+		"_pb_users_auth_/4q1xlclmfloku33/thumbs_300_1SEi6Q6U72.png/100x100_300_1SEi6Q6U72.png",
+	}
+
+	if _, err := app.DeleteOrphanedFiles(context.Background(), 0, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if exists, _ := fsys.Exists(orphan); exists {
+		t.Errorf("Expected %q to be deleted", orphan)
+	}
+
+	for _, key := range referenced {
+		if exists, _ := fsys.Exists(key); !exists {
+			t.Errorf("Expected referenced multi-tenant record file %q to not be deleted", key)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package core
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/daos"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/models/schema"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/filesystem"
+	"github.com/pocketbase/dbx"
+)
+
+// BackfillFilesMetadata extracts and stores the metadata of the
+// collection record files that don't have stored metadata yet
+// (eg. files uploaded before the files metadata support or
+// files with previously failed metadata extraction).
+//
+// The files with failed metadata extraction are logged and skipped.
+//
+// Returns the number of the backfilled files.
+func (app *BaseApp) BackfillFilesMetadata(ctx context.Context) (int, error) {
+	collections, err := app.Dao().FindCollectionsByType(models.CollectionTypeBase)
+	if err != nil {
+		return 0, err
+	}
+
+	authCollections, err := app.Dao().FindCollectionsByType(models.CollectionTypeAuth)
+	if err != nil {
+		return 0, err
+	}
+	collections = append(collections, authCollections...)
+
+	var total int
+
+	for _, collection := range collections {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		missing, err := app.findFilesWithoutMetadata(collection)
+		if err != nil {
+			return total, err
+		}
+
+		for _, m := range missing {
+			if err := ctx.Err(); err != nil {
+				return total, err
+			}
+
+			key := collection.BaseFilesPath() + "/" + m.RecordId + "/" + m.Name
+
+			file, err := filesystem.NewFileFromObject(app.NewFilesystem, key)
+			if err == nil {
+				var meta *filesystem.Metadata
+				meta, err = filesystem.ExtractMetadata(file)
+				if err == nil {
+					m.Size = meta.Size
+					m.MimeType = meta.MimeType
+					m.Width = meta.Width
+					m.Height = meta.Height
+					m.Duration = meta.Duration
+					m.Checksum = meta.Checksum
+
+					err = app.Dao().SaveFileMetadata(m)
+				}
+			}
+
+			if err != nil {
+				app.Logger().Warn(
+					"Failed to backfill file metadata",
+					slog.String("key", key),
+					slog.String("error", err.Error()),
+				)
+				continue
+			}
+
+			total++
+		}
+	}
+
+	return total, nil
+}
+
+// findFilesWithoutMetadata returns the (not persisted) metadata models
+// of the collection record files without stored metadata.
+func (app *BaseApp) findFilesWithoutMetadata(collection *models.Collection) ([]*models.FileMetadata, error) {
+	result := []*models.FileMetadata{}
+
+	fileFields := []string{}
+	for _, field := range collection.Schema.Fields() {
+		if field.Type == schema.FieldTypeFile {
+			fileFields = append(fileFields, field.Name)
+		}
+	}
+
+	if len(fileFields) == 0 {
+		return result, nil
+	}
+
+	existing := map[string]struct{}{}
+
+	rows := []dbx.NullStringMap{}
+	err := app.Dao().FileMetadataQuery().
+		Select("recordId", "field", "name").
+		AndWhere(dbx.HashExp{"collectionId": collection.Id}).
+		All(&rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		existing[row["recordId"].String+"/"+row["field"].String+"/"+row["name"].String] = struct{}{}
+	}
+
+	collectMissing := func(txDao *daos.Dao) error {
+		rows, err := txDao.RecordQuery(collection).
+			Select(append([]string{collection.Name + ".id"}, prefixColumns(collection.Name, fileFields)...)...).
+			Rows()
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			row := dbx.NullStringMap{}
+			if err := rows.ScanMap(row); err != nil {
+				return err
+			}
+
+			record := models.NewRecordFromNullStringMap(collection, row)
+
+			for _, field := range fileFields {
+				for _, name := range record.GetStringSlice(field) {
+					if _, ok := existing[record.Id+"/"+field+"/"+name]; ok {
+						continue
+					}
+
+					result = append(result, &models.FileMetadata{
+						CollectionId: collection.Id,
+						RecordId:     record.Id,
+						Field:        field,
+						Name:         name,
+					})
+				}
+			}
+		}
+
+		return rows.Err()
+	}
+
+	if collection.IsMultiTenant() {
+		// collect the files of all tenants
+		err = app.Dao().RunWithoutTenantIsolation(collectMissing)
+	} else {
+		err = collectMissing(app.Dao())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
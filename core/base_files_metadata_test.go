@@ -0,0 +1,51 @@
+package core_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/tests"
+)
+
+func TestBackfillFilesMetadata(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	items, err := app.Dao().FindFilesMetadataByRecords("_pb_users_auth_", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 0 {
+		t.Fatalf("Expected no files metadata before the backfill, got %d", len(items))
+	}
+
+	total, err := app.BackfillFilesMetadata(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total == 0 {
+		t.Fatal("Expected at least 1 backfilled file")
+	}
+
+	items, err = app.Dao().FindFilesMetadataByRecords("_pb_users_auth_", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("Expected 1 file metadata, got %d", len(items))
+	}
+	if items[0].Name != "300_1SEi6Q6U72.png" || items[0].Field != "avatar" || items[0].Width == 0 || items[0].Checksum == "" {
+		t.Fatalf("Unexpected file metadata %#v", items[0])
+	}
+
+	// rerun should skip the already backfilled files
+	total, err = app.BackfillFilesMetadata(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 0 {
+		t.Fatalf("Expected 0 backfilled files on rerun, got %d", total)
+	}
+}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/models/settings"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/logger"
+)
+
+// refreshLogSinks (re)initializes the logger handler sinks
+// if the logs sinks settings have changed.
+func (app *BaseApp) refreshLogSinks(handler *logger.BatchHandler) {
+	app.logSinksMux.Lock()
+	defer app.logSinksMux.Unlock()
+
+	configs := []settings.LogSinkConfig{}
+	for _, config := range app.Settings().Logs.Sinks {
+		if config.Enabled {
+			configs = append(configs, config)
+		}
+	}
+
+	if app.logSinksConfig != nil && reflect.DeepEqual(configs, app.logSinksConfig) {
+		return // no changes
+	}
+	app.logSinksConfig = configs
+
+	sinks := make([]logger.Sink, 0, len(configs))
+
+	for _, config := range configs {
+		sink, err := app.newLogSink(config)
+		if err != nil {
+			app.Logger().Error(
+				"Failed to initialize log sink",
+				slog.String("type", config.Type),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+
+		sinks = append(sinks, logger.NewFilteredSink(sink, slog.Level(config.MinLevel), config.Redact...))
+	}
+
+	handler.SetSinks(sinks...)
+}
+
+// newLogSink creates a new logger sink from the provided sink config.
+func (app *BaseApp) newLogSink(config settings.LogSinkConfig) (logger.Sink, error) {
+	switch config.Type {
+	case settings.LogSinkTypeFile:
+		path := config.Path
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(app.DataDir(), path)
+		}
+
+		file, err := logger.NewRotatingFile(path, int64(config.MaxSize)*1024*1024, config.MaxBackups)
+		if err != nil {
+			return nil, err
+		}
+
+		return logger.NewJSONSink(file), nil
+	case settings.LogSinkTypeSyslog:
+		return logger.NewSyslogSink(config.Network, config.Address, config.Tag), nil
+	case settings.LogSinkTypeOTLP:
+		serviceName := app.Settings().Tracing.ServiceName
+		if serviceName == "" {
+			serviceName = "postgresbase"
+		}
+
+		return logger.NewOTLPSink(config.Endpoint, serviceName), nil
+	case settings.LogSinkTypeStdout:
+		return logger.NewJSONSink(os.Stdout), nil
+	default:
+		return nil, fmt.Errorf("unsupported log sink type %q", config.Type)
+	}
+}
@@ -0,0 +1,116 @@
+package core_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/models/settings"
+	"github.com/AlperRehaYAZGAN/postgresbase/tests"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/logger"
+	"github.com/pocketbase/dbx"
+)
+
+func TestBaseAppLogSinks(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	handler, ok := app.Logger().Handler().(*logger.BatchHandler)
+	if !ok {
+		t.Fatalf("Expected BatchHandler, got %v", app.Logger().Handler())
+	}
+
+	if total := len(handler.Sinks()); total != 0 {
+		t.Fatalf("Expected no sinks, got %d", total)
+	}
+
+	app.Settings().Logs.MinLevel = 0
+	app.Settings().Logs.Sinks = []settings.LogSinkConfig{
+		{
+			Enabled:  true,
+			Type:     settings.LogSinkTypeFile,
+			Path:     "test_sink.log",
+			MinLevel: -4,
+			Redact:   []string{"secret"},
+		},
+		{
+			// disabled
+			Type: settings.LogSinkTypeStdout,
+		},
+	}
+	if err := app.Dao().SaveSettings(app.Settings()); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.RefreshSettings(); err != nil {
+		t.Fatal(err)
+	}
+
+	sinks := handler.Sinks()
+	if len(sinks) != 1 {
+		t.Fatalf("Expected 1 sink, got %d", len(sinks))
+	}
+
+	// the handler level should allow the sink min level
+	if !handler.Enabled(context.Background(), -4) {
+		t.Fatal("Expected the debug level to be enabled")
+	}
+
+	// refresh without changes
+	if err := app.RefreshSettings(); err != nil {
+		t.Fatal(err)
+	}
+	if s := handler.Sinks(); len(s) != 1 || s[0] != sinks[0] {
+		t.Fatal("Expected the sink to be reused")
+	}
+
+	app.Logger().Debug("test_sink_debug", "secret", "abc", "other", "123")
+
+	if err := handler.WriteAll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(app.DataDir(), "test_sink.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectations := []string{`"message":"test_sink_debug"`, `"secret":"******"`, `"other":"123"`}
+	for _, expected := range expectations {
+		if !strings.Contains(string(content), expected) {
+			t.Fatalf("Missing %s in\n%s", expected, content)
+		}
+	}
+
+	if strings.Contains(string(content), "abc") {
+		t.Fatalf("Expected the secret attribute to be redacted, got\n%s", content)
+	}
+
+	// the debug log shouldn't be persisted in the logs db (MinLevel 0)
+	total := 0
+	err = app.LogsDao().LogQuery().Select("count(*)").AndWhere(dbx.HashExp{"message": "test_sink_debug"}).Row(&total)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 0 {
+		t.Fatalf("Expected the debug log to not be persisted, found %d", total)
+	}
+
+	// disable the sinks
+	app.Settings().Logs.Sinks = nil
+	if err := app.Dao().SaveSettings(app.Settings()); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.RefreshSettings(); err != nil {
+		t.Fatal(err)
+	}
+
+	if total := len(handler.Sinks()); total != 0 {
+		t.Fatalf("Expected no sinks, got %d", total)
+	}
+
+	if handler.Enabled(context.Background(), -4) {
+		t.Fatal("Expected the debug level to be disabled")
+	}
+}
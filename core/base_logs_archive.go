@@ -0,0 +1,393 @@
+package core
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/daos"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/filesystem"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/security"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/types"
+	"github.com/pocketbase/dbx"
+)
+
+// LogsArchivePrefix is the backups filesystem key prefix of the logs archive files.
+const LogsArchivePrefix = "@logs_archive_"
+
+// logsArchiveExt is the logs archive files extension (gzip compressed NDJSON).
+const logsArchiveExt = ".ndjson.gz"
+
+// LogsArchiveCronJobId is the id of the app cron job used for the
+// scheduled archival and deletion of the expired logs.
+const LogsArchiveCronJobId string = "@logsarchive"
+
+// logsArchiveCronSchedule is the schedule of the logs archive cron job (hourly).
+const logsArchiveCronSchedule = "0 * * * *"
+
+// IsLogsArchiveKey reports whether the provided backups filesystem key is a logs archive file.
+func IsLogsArchiveKey(key string) bool {
+	return strings.HasPrefix(key, LogsArchivePrefix) &&
+		strings.HasSuffix(key, logsArchiveExt) &&
+		!strings.ContainsAny(key, `/\`)
+}
+
+// ArchiveLogs exports the logs matching expr into gzip compressed
+// NDJSON files (one per log day and level) in the backups filesystem.
+//
+// The archived logs are not deleted.
+//
+// The archive files are named "@logs_archive_{day}_level{level}_{archivedAt}.ndjson.gz",
+// eg. "@logs_archive_2024-01-02_level0_20240105030405.ndjson.gz".
+//
+// On success returns the keys of the created archive files.
+func (app *BaseApp) ArchiveLogs(ctx context.Context, expr dbx.Expression) ([]string, error) {
+	keys, _, err := app.archiveLogs(ctx, expr)
+
+	return keys, err
+}
+
+// ArchiveExpiredLogs exports the logs that are no longer allowed by
+// the current logs settings (aka. older than Logs.MaxDays or with level
+// lower than Logs.MinLevel) into archive files (see [BaseApp.ArchiveLogs])
+// and deletes them.
+//
+// Only the logs that were successfully uploaded are deleted, aka.
+// on archive error all logs are preserved for the next attempt.
+//
+// Returns the keys of the created archive files.
+func (app *BaseApp) ArchiveExpiredLogs(ctx context.Context) ([]string, error) {
+	// prevent concurrent runs archiving the same logs
+	if !app.logsArchiveMux.TryLock() {
+		return nil, errors.New("logs archive is already in progress")
+	}
+	defer app.logsArchiveMux.Unlock()
+
+	logsSettings := app.Settings().Logs
+
+	keys, ids, err := app.archiveLogs(ctx, ExpiredLogsExpr(logsSettings.MaxDays, logsSettings.MinLevel))
+	if err != nil {
+		return keys, err
+	}
+
+	if err := app.LogsDao().DeleteLogsByIds(ids); err != nil {
+		return keys, fmt.Errorf("failed to delete the archived logs: %w", err)
+	}
+
+	// no logs are allowed -> try to reclaim preserved disk space after the delete
+	if logsSettings.MaxDays == 0 && len(ids) > 0 {
+		app.LogsDao().Vacuum()
+	}
+
+	return keys, nil
+}
+
+// ExpiredLogsExpr returns a logs filter expression matching the logs that are
+// older than maxDays or with level lower than minLevel.
+func ExpiredLogsExpr(maxDays int, minLevel int) dbx.Expression {
+	createdBefore := time.Now().AddDate(0, 0, -1*maxDays).UTC().Format(types.DefaultDateLayout)
+
+	return dbx.NewExp("[[created]] <= {:date} OR [[level]] < {:level}", dbx.Params{
+		"date":  createdBefore,
+		"level": minLevel,
+	})
+}
+
+// archiveLogs exports the logs matching expr into the backups filesystem
+// and returns the keys of the created archive files and the ids of the archived logs.
+func (app *BaseApp) archiveLogs(ctx context.Context, expr dbx.Expression) ([]string, []string, error) {
+	// make sure that the special temp directory exists
+	// note: it needs to be inside the current pb_data to avoid "cross-device link" errors
+	tempDir := filepath.Join(app.DataDir(), LocalTempDirName, "logs_archive_"+security.PseudorandomString(6))
+	if err := os.MkdirAll(tempDir, os.ModePerm); err != nil {
+		return nil, nil, fmt.Errorf("failed to create a temp dir: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	query := app.LogsDao().LogQuery().OrderBy("created ASC")
+	if expr != nil {
+		query.AndWhere(expr)
+	}
+
+	rows, err := query.WithContext(ctx).Rows()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	writers := map[string]*logsArchiveWriter{}
+	defer func() {
+		for _, w := range writers {
+			w.Close()
+		}
+	}()
+
+	for rows.Next() {
+		log := &models.Log{}
+		if err := rows.ScanStruct(log); err != nil {
+			return nil, nil, err
+		}
+
+		name := fmt.Sprintf("%s_level%d", log.Created.Time().UTC().Format("2006-01-02"), log.Level)
+
+		w, ok := writers[name]
+		if !ok {
+			w, err = newLogsArchiveWriter(filepath.Join(tempDir, name))
+			if err != nil {
+				return nil, nil, err
+			}
+			writers[name] = w
+		}
+
+		if err := w.Write(log); err != nil {
+			return nil, nil, err
+		}
+
+		ids = append(ids, log.Id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if len(writers) == 0 {
+		return []string{}, nil, nil // nothing to archive
+	}
+
+	fsys, err := app.NewBackupsFilesystem()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer fsys.Close()
+
+	fsys.SetContext(ctx)
+
+	names := make([]string, 0, len(writers))
+	for name := range writers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	archivedAt := time.Now().UTC().Format("20060102150405")
+
+	keys := make([]string, 0, len(names))
+
+	for _, name := range names {
+		w := writers[name]
+
+		if err := w.Close(); err != nil {
+			return keys, nil, err
+		}
+
+		file, err := filesystem.NewFileFromPath(w.path)
+		if err != nil {
+			return keys, nil, err
+		}
+
+		key := LogsArchivePrefix + name + "_" + archivedAt + logsArchiveExt
+		file.OriginalName = key
+		file.Name = key
+
+		if err := fsys.UploadFile(file, key); err != nil {
+			return keys, nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, ids, nil
+}
+
+// ImportLogsArchive imports the logs from the specified
+// backups filesystem logs archive file into the logs db.
+//
+// The logs that already exist (aka. with the same id) are skipped.
+//
+// Note that the imported logs are still subject to the logs retention
+// settings, aka. they may be deleted (and archived again) on the next logs cleanup.
+//
+// On success returns the number of the imported logs.
+func (app *BaseApp) ImportLogsArchive(ctx context.Context, key string) (int, error) {
+	if !IsLogsArchiveKey(key) {
+		return 0, fmt.Errorf("%q is not a valid logs archive file key", key)
+	}
+
+	fsys, err := app.NewBackupsFilesystem()
+	if err != nil {
+		return 0, err
+	}
+	defer fsys.Close()
+
+	fsys.SetContext(ctx)
+
+	br, err := fsys.GetFile(key)
+	if err != nil {
+		return 0, err
+	}
+	defer br.Close()
+
+	gz, err := gzip.NewReader(br)
+	if err != nil {
+		return 0, err
+	}
+	defer gz.Close()
+
+	var imported int
+
+	err = app.LogsDao().RunInTransaction(func(txDao *daos.Dao) error {
+		decoder := json.NewDecoder(gz)
+
+		for {
+			log := &models.Log{}
+
+			if err := decoder.Decode(log); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return fmt.Errorf("failed to decode log line %d: %w", imported+1, err)
+			}
+
+			if log.Id == "" {
+				continue
+			}
+
+			if existing, _ := txDao.FindLogById(log.Id); existing != nil {
+				continue // already imported
+			}
+
+			log.MarkAsNew()
+
+			if err := txDao.SaveLog(log); err != nil {
+				return err
+			}
+
+			imported++
+		}
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return imported, nil
+}
+
+// -------------------------------------------------------------------
+
+// logsArchiveWriter writes logs as gzip compressed NDJSON into a local file.
+type logsArchiveWriter struct {
+	path    string
+	file    *os.File
+	buf     *bufio.Writer
+	gz      *gzip.Writer
+	encoder *json.Encoder
+	closed  bool
+}
+
+func newLogsArchiveWriter(path string) (*logsArchiveWriter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bufio.NewWriter(file)
+	gz := gzip.NewWriter(buf)
+
+	return &logsArchiveWriter{
+		path:    path,
+		file:    file,
+		buf:     buf,
+		gz:      gz,
+		encoder: json.NewEncoder(gz),
+	}, nil
+}
+
+// Write appends the provided log as a single JSON line.
+func (w *logsArchiveWriter) Write(log *models.Log) error {
+	return w.encoder.Encode(log)
+}
+
+// Close flushes the remaining data and closes the underlying file.
+//
+// It is safe to call Close multiple times.
+func (w *logsArchiveWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	return errors.Join(w.gz.Close(), w.buf.Flush(), w.file.Close())
+}
+
+// -------------------------------------------------------------------
+
+// initLogsArchiveHooks registers the scheduled expired logs archive app hooks.
+func (app *BaseApp) initLogsArchiveHooks() error {
+	isServe := false
+
+	run := func() {
+		keys, err := app.ArchiveExpiredLogs(context.Background())
+		if err != nil {
+			app.Logger().Error(
+				"[Logs archive cron] Failed to archive the expired logs",
+				slog.String("error", err.Error()),
+			)
+			return
+		}
+
+		if len(keys) > 0 {
+			app.Logger().Info(
+				"[Logs archive cron] Archived the expired logs",
+				slog.Any("files", keys),
+			)
+		}
+	}
+
+	loadJob := func() {
+		app.Cron().Remove(LogsArchiveCronJobId)
+
+		if !app.Settings().Logs.Archive || !isServe || !app.IsBootstrapped() {
+			return
+		}
+
+		app.Cron().Add(LogsArchiveCronJobId, logsArchiveCronSchedule, run)
+	}
+
+	// load on app serve
+	app.OnBeforeServe().Add(func(e *ServeEvent) error {
+		isServe = true
+		loadJob()
+		return nil
+	})
+
+	// reload on app settings change
+	app.OnModelAfterUpdate((&models.Param{}).TableName()).Add(func(e *ModelEvent) error {
+		p := e.Model.(*models.Param)
+		if p == nil || p.Key != models.ParamAppSettings {
+			return nil
+		}
+
+		loadJob()
+
+		// apply the new logs retention settings without waiting for the next cron tick
+		if isServe && app.Settings().Logs.Archive {
+			go run()
+		}
+
+		return nil
+	})
+
+	return nil
+}
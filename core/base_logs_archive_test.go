@@ -0,0 +1,188 @@
+package core_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/core"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/tests"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/types"
+	"github.com/pocketbase/dbx"
+)
+
+func TestIsLogsArchiveKey(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		key      string
+		expected bool
+	}{
+		{"", false},
+		{"test.zip", false},
+		{"@logs_archive_2022-05-01_level0_20220601000000.zip", false},
+		{"logs_archive_2022-05-01_level0_20220601000000.ndjson.gz", false},
+		{"@logs_archive_../test.ndjson.gz", false},
+		{"@logs_archive_2022-05-01_level0_20220601000000.ndjson.gz", true},
+	}
+
+	for _, s := range scenarios {
+		if result := core.IsLogsArchiveKey(s.key); result != s.expected {
+			t.Errorf("[%s] Expected %v, got %v", s.key, s.expected, result)
+		}
+	}
+}
+
+func TestBaseAppArchiveAndImportLogs(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	if err := tests.MockLogsData(app); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	// nothing to archive
+	keys, err := app.ArchiveLogs(ctx, dbx.NewExp("[[created]] <= '2022-01-01 00:00:00.000Z'"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 0 {
+		t.Fatalf("Expected no archive files, got %v", keys)
+	}
+
+	keys, err = app.ArchiveLogs(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedPrefixes := []string{
+		"@logs_archive_2022-05-01_level0_",
+		"@logs_archive_2022-05-02_level8_",
+	}
+	if len(keys) != len(expectedPrefixes) {
+		t.Fatalf("Expected %d archive files, got %v", len(expectedPrefixes), keys)
+	}
+	for i, prefix := range expectedPrefixes {
+		if !strings.HasPrefix(keys[i], prefix) || !core.IsLogsArchiveKey(keys[i]) {
+			t.Fatalf("Expected archive key %d to start with %q, got %q", i, prefix, keys[i])
+		}
+	}
+
+	// the archived logs must be preserved
+	if total := countLogs(t, app); total != 2 {
+		t.Fatalf("Expected 2 logs, got %d", total)
+	}
+
+	if _, err := app.ImportLogsArchive(ctx, "test.zip"); err == nil {
+		t.Fatal("Expected invalid key error, got nil")
+	}
+
+	// already existing logs
+	imported, err := app.ImportLogsArchive(ctx, keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported != 0 {
+		t.Fatalf("Expected 0 imported logs, got %d", imported)
+	}
+
+	if err := app.LogsDao().DeleteOldLogs(time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	imported, err = app.ImportLogsArchive(ctx, keys[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported != 1 {
+		t.Fatalf("Expected 1 imported log, got %d", imported)
+	}
+
+	log, err := app.LogsDao().FindLogById("f2133873-44fb-9f38-bf82-c918f53b310d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if log.Message != "test_message2" || log.Level != 8 || log.Data["status"] != 400.0 {
+		t.Fatalf("Unexpected imported log %v", log)
+	}
+	expectedCreated := time.Date(2022, 5, 2, 10, 0, 0, 123*int(time.Millisecond), time.UTC)
+	if !log.Created.Time().Equal(expectedCreated) {
+		t.Fatalf("Expected the original created date %s, got %s", expectedCreated, log.Created.Time())
+	}
+}
+
+func TestBaseAppArchiveExpiredLogs(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	if err := tests.MockLogsData(app); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	// recent log that shouldn't be archived
+	recent := &models.Log{Message: "recent", Level: 4, Data: types.JsonMap{}}
+	if err := app.LogsDao().SaveLog(recent); err != nil {
+		t.Fatal(err)
+	}
+
+	app.Settings().Logs.MaxDays = 7
+	app.Settings().Logs.MinLevel = 0
+
+	// failed upload -> no logs should be deleted
+	app.Settings().Backups.S3.Enabled = true
+	app.Settings().Backups.S3.Endpoint = "http://127.0.0.1:0"
+	app.Settings().Backups.S3.Bucket = "test"
+	if _, err := app.ArchiveExpiredLogs(ctx); err == nil {
+		t.Fatal("Expected archive upload error, got nil")
+	}
+	if total := countLogs(t, app); total != 3 {
+		t.Fatalf("Expected 3 logs after the failed archive, got %d", total)
+	}
+	app.Settings().Backups.S3.Enabled = false
+
+	keys, err := app.ArchiveExpiredLogs(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("Expected 2 archive files, got %v", keys)
+	}
+
+	if total := countLogs(t, app); total != 1 {
+		t.Fatalf("Expected 1 remaining log, got %d", total)
+	}
+
+	if _, err := app.LogsDao().FindLogById(recent.Id); err != nil {
+		t.Fatalf("Expected the recent log to be preserved, got %v", err)
+	}
+
+	// the archived logs can be restored
+	imported, err := app.ImportLogsArchive(ctx, keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported != 1 {
+		t.Fatalf("Expected 1 imported log, got %d", imported)
+	}
+}
+
+func countLogs(t *testing.T, app *tests.TestApp) int {
+	var total int
+
+	err := app.LogsDao().LogQuery().Select("count(*)").Row(&total)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return total
+}
@@ -0,0 +1,153 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/daos"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/cron"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/metrics"
+	"github.com/pocketbase/dbx"
+)
+
+// Metrics returns the app metrics registry.
+func (app *BaseApp) Metrics() *metrics.Registry {
+	return app.metrics
+}
+
+// registerMetricsCollectors registers the app level point-in-time
+// metrics (db pools stats, realtime clients) and the cron jobs observer.
+func (app *BaseApp) registerMetricsCollectors() {
+	dbConnections := app.metrics.GaugeVec(
+		"pb_db_connections",
+		"The number of db connections by their state.",
+		"db", "pool", "state",
+	)
+	dbMaxOpenConnections := app.metrics.GaugeVec(
+		"pb_db_max_open_connections",
+		"The maximum number of open db connections.",
+		"db", "pool",
+	)
+	dbWaitCount := app.metrics.GaugeVec(
+		"pb_db_wait_count",
+		"The total number of connections waited for.",
+		"db", "pool",
+	)
+	dbWaitDuration := app.metrics.GaugeVec(
+		"pb_db_wait_duration_seconds",
+		"The total time blocked waiting for a new connection.",
+		"db", "pool",
+	)
+	realtimeClients := app.metrics.GaugeVec(
+		"pb_realtime_clients",
+		"The number of the connected realtime clients.",
+	)
+	cronJobDuration := app.metrics.HistogramVec(
+		"pb_cron_job_duration_seconds",
+		"The cron jobs execution duration.",
+		nil,
+		"job",
+	)
+
+	app.metrics.OnCollect(func() {
+		pools := []struct {
+			db  string
+			dao *daos.Dao
+		}{
+			{"data", app.Dao()},
+			{"logs", app.LogsDao()},
+		}
+
+		for _, p := range pools {
+			if p.dao == nil {
+				continue
+			}
+
+			builders := map[string]dbx.Builder{
+				"concurrent":    p.dao.ConcurrentDB(),
+				"nonconcurrent": p.dao.NonconcurrentDB(),
+			}
+
+			for pool, builder := range builders {
+				db, ok := builder.(*dbx.DB)
+				if !ok {
+					continue
+				}
+
+				stats := db.DB().Stats()
+
+				dbConnections.Set(float64(stats.OpenConnections), p.db, pool, "open")
+				dbConnections.Set(float64(stats.InUse), p.db, pool, "in_use")
+				dbConnections.Set(float64(stats.Idle), p.db, pool, "idle")
+				dbMaxOpenConnections.Set(float64(stats.MaxOpenConnections), p.db, pool)
+				dbWaitCount.Set(float64(stats.WaitCount), p.db, pool)
+				dbWaitDuration.Set(stats.WaitDuration.Seconds(), p.db, pool)
+			}
+		}
+
+		realtimeClients.Set(float64(app.SubscriptionsBroker().TotalClients()))
+	})
+
+	app.cron.SetObserver(func(job *cron.Job, duration time.Duration) {
+		cronJobDuration.Observe(duration.Seconds(), job.Id())
+	})
+}
+
+// initHookErrorsMetrics registers an app hooks errors observer
+// that counts the hook handler errors by their origin event type.
+func (app *BaseApp) initHookErrorsMetrics() {
+	hookErrors := app.metrics.CounterVec(
+		"pb_hook_errors_total",
+		"The total number of hook handler errors by event type.",
+		"event",
+	)
+
+	app.hookErrorsObserverId = app.hookObservers.AddErrorObserver(func(data any, err error) {
+		hookErrors.Inc(fmt.Sprintf("%T", data))
+	})
+}
+
+// attachDBMetrics wraps the db query and exec log functions
+// to collect the db queries duration and errors metrics.
+func (app *BaseApp) attachDBMetrics(db *dbx.DB, dbName string) {
+	queryDuration := app.metrics.HistogramVec(
+		"pb_db_query_duration_seconds",
+		"The db queries execution duration.",
+		nil,
+		"db", "type",
+	)
+	queryErrors := app.metrics.CounterVec(
+		"pb_db_query_errors_total",
+		"The total number of failed db queries.",
+		"db", "type",
+	)
+
+	observe := func(t time.Duration, queryType string, err error) {
+		queryDuration.Observe(t.Seconds(), dbName, queryType)
+
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			queryErrors.Inc(dbName, queryType)
+		}
+	}
+
+	queryLogFunc := db.QueryLogFunc
+	db.QueryLogFunc = func(ctx context.Context, t time.Duration, sql string, rows *sql.Rows, err error) {
+		observe(t, "query", err)
+
+		if queryLogFunc != nil {
+			queryLogFunc(ctx, t, sql, rows, err)
+		}
+	}
+
+	execLogFunc := db.ExecLogFunc
+	db.ExecLogFunc = func(ctx context.Context, t time.Duration, sql string, result sql.Result, err error) {
+		observe(t, "exec", err)
+
+		if execLogFunc != nil {
+			execLogFunc(ctx, t, sql, result, err)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package core_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/core"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/tests"
+)
+
+func TestBaseAppMetrics(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	if app.Metrics() == nil {
+		t.Fatal("Expected the metrics registry to be initialized")
+	}
+
+	// trigger some db queries
+	if _, err := app.Dao().FindAdminByEmail("test@example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	// trigger a hook error
+	app.OnModelBeforeCreate().Add(func(e *core.ModelEvent) error {
+		return errors.New("test")
+	})
+	app.Dao().Save(&models.Admin{Email: "new@example.com"})
+
+	// nested hook error (should be counted only at its origin)
+	app.OnMailerBeforeAdminResetPasswordSend().Add(func(e *core.MailerAdminEvent) error {
+		return app.Dao().Save(&models.Admin{Email: "new2@example.com"})
+	})
+	app.OnMailerBeforeAdminResetPasswordSend().Trigger(&core.MailerAdminEvent{})
+
+	var sb strings.Builder
+	if err := app.Metrics().Write(&sb); err != nil {
+		t.Fatal(err)
+	}
+
+	expectations := []string{
+		`pb_db_query_duration_seconds_count{db="data",type="query"}`,
+		`pb_db_connections{db="data",pool="concurrent",state="open"}`,
+		`pb_db_connections{db="logs",pool="nonconcurrent",state="idle"}`,
+		`pb_hook_errors_total{event="*core.ModelEvent"} 2`,
+		"pb_realtime_clients 0",
+	}
+
+	for _, expected := range expectations {
+		if !strings.Contains(sb.String(), expected) {
+			t.Errorf("Missing expected %q in\n%s", expected, sb.String())
+		}
+	}
+
+	if strings.Contains(sb.String(), "MailerAdminEvent") {
+		t.Errorf("Expected the propagated nested hook error to not be counted in\n%s", sb.String())
+	}
+}
@@ -0,0 +1,160 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/dbutils"
+	"github.com/pocketbase/dbx"
+)
+
+const (
+	// slowQueryExplainTimeout is the max duration of a slow query plan capture.
+	slowQueryExplainTimeout = 5 * time.Second
+
+	// slowQueryMaxExplains is the max number of concurrent slow query
+	// plan captures per db (the plans of the slow queries over the
+	// limit are skipped).
+	slowQueryMaxExplains = 2
+)
+
+// attachSlowQueryLog wraps the db query and exec log functions
+// to log the statements exceeding the Logs.SlowQueryThreshold setting.
+//
+// The logged sql statement and plan are with redacted params values
+// (see [dbutils.SanitizeSQL]).
+func (app *BaseApp) attachSlowQueryLog(db *dbx.DB, dbName string) {
+	explainSem := make(chan struct{}, slowQueryMaxExplains)
+
+	check := func(ctx context.Context, t time.Duration, operation string, query string, err error) {
+		if app.Settings() == nil || app.Logger() == nil {
+			return
+		}
+
+		threshold := app.Settings().Logs.SlowQueryThreshold
+		if threshold <= 0 || t < time.Duration(threshold)*time.Millisecond {
+			return
+		}
+
+		attrs := []any{
+			slog.String("type", "slowQuery"),
+			slog.String("db", dbName),
+			slog.String("operation", operation),
+			slog.String("sql", dbutils.SanitizeSQL(query)),
+			slog.Float64("execTime", float64(t)/float64(time.Millisecond)),
+			slog.String("route", RouteFromContext(ctx)),
+		}
+
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			attrs = append(attrs, slog.String("error", err.Error()))
+		}
+
+		if !app.Settings().Logs.SlowQueryExplain || !canExplainQuery(db, query) {
+			app.Logger().WarnContext(ctx, "Slow query", attrs...)
+			return
+		}
+
+		select {
+		case explainSem <- struct{}{}:
+		default:
+			// too many concurrent plan captures
+			attrs = append(attrs, slog.Bool("planSkipped", true))
+			app.Logger().WarnContext(ctx, "Slow query", attrs...)
+			return
+		}
+
+		// capture the plan in the background to avoid further slowing down the request
+		go func() {
+			defer func() { <-explainSem }()
+
+			plan, explainErr := explainQuery(db, query)
+			if explainErr != nil {
+				attrs = append(attrs, slog.String("planError", explainErr.Error()))
+			} else {
+				attrs = append(attrs, slog.Any("plan", plan))
+			}
+
+			app.Logger().WarnContext(ctx, "Slow query", attrs...)
+		}()
+	}
+
+	queryLogFunc := db.QueryLogFunc
+	db.QueryLogFunc = func(ctx context.Context, t time.Duration, sql string, rows *sql.Rows, err error) {
+		check(ctx, t, "query", sql, err)
+
+		if queryLogFunc != nil {
+			queryLogFunc(ctx, t, sql, rows, err)
+		}
+	}
+
+	execLogFunc := db.ExecLogFunc
+	db.ExecLogFunc = func(ctx context.Context, t time.Duration, sql string, result sql.Result, err error) {
+		check(ctx, t, "exec", sql, err)
+
+		if execLogFunc != nil {
+			execLogFunc(ctx, t, sql, result, err)
+		}
+	}
+}
+
+// canExplainQuery reports whether a plan could be captured for the provided query
+// (only SELECT statements on Postgres are supported).
+func canExplainQuery(db *dbx.DB, query string) bool {
+	switch db.DriverName() {
+	case "postgres", "pgx":
+	default:
+		return false
+	}
+
+	query = strings.ToUpper(strings.TrimSpace(query))
+
+	return strings.HasPrefix(query, "SELECT") || strings.HasPrefix(query, "WITH")
+}
+
+// explainQuery returns the "EXPLAIN (FORMAT JSON)" plan of the provided query
+// (the query itself is not executed).
+//
+// The literal values of the plan conditions are replaced with "?" placeholders.
+func explainQuery(db *dbx.DB, query string) (any, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), slowQueryExplainTimeout)
+	defer cancel()
+
+	var raw string
+
+	// use the underlying sql.DB to prevent logging the explain query itself
+	// and to avoid the dbx placeholders parsing of the already inlined params
+	if err := db.DB().QueryRowContext(ctx, "EXPLAIN (FORMAT JSON) "+query).Scan(&raw); err != nil {
+		return nil, err
+	}
+
+	var plan any
+	if err := json.Unmarshal([]byte(raw), &plan); err != nil {
+		return nil, err
+	}
+
+	return sanitizePlan(plan), nil
+}
+
+// sanitizePlan replaces the literal values in the string
+// fields of the provided json plan (eg. "Filter" or "Index Cond").
+func sanitizePlan(plan any) any {
+	switch v := plan.(type) {
+	case string:
+		return dbutils.SanitizeSQL(v)
+	case []any:
+		for i, item := range v {
+			v[i] = sanitizePlan(item)
+		}
+	case map[string]any:
+		for k, item := range v {
+			v[k] = sanitizePlan(item)
+		}
+	}
+
+	return plan
+}
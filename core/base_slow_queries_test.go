@@ -0,0 +1,76 @@
+package core_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/core"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/tests"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/logger"
+	"github.com/pocketbase/dbx"
+)
+
+func TestBaseAppSlowQueryLog(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().Logs.MaxDays = 1
+	app.Settings().Logs.MinLevel = int(slog.LevelInfo)
+	app.Settings().Logs.SlowQueryThreshold = 1
+	app.Settings().Logs.SlowQueryExplain = true // should be ignored for sqlite
+
+	slowQuery := "WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x+1 FROM c WHERE x < {:max}) SELECT count(*), {:secret} FROM c"
+
+	// note: the sqlite driver steps through the result rows lazily so we use
+	// Execute to ensure that the statement duration is fully measured
+	_, err := app.Dao().DB().NewQuery(slowQuery).
+		Bind(dbx.Params{"max": 500000, "secret": "test_secret"}).
+		WithContext(core.ContextWithRequestId(core.ContextWithRoute(context.Background(), "/api/test_slow"), "test_id")).
+		Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// disabled slow queries log
+	app.Settings().Logs.SlowQueryThreshold = 0
+	if _, err := app.Dao().DB().NewQuery(slowQuery + " -- disabled").Bind(dbx.Params{"max": 500000, "secret": "test_secret"}).Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	app.Logger().Handler().(*logger.BatchHandler).WriteAll(context.Background())
+
+	logs := []*models.Log{}
+	err = app.LogsDao().LogQuery().AndWhere(dbx.HashExp{"message": "Slow query"}).All(&logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(logs) != 1 {
+		t.Fatalf("Expected 1 slow query log, got %d", len(logs))
+	}
+
+	l := logs[0]
+
+	if l.Level != int(slog.LevelWarn) {
+		t.Fatalf("Expected WARN level, got %d", l.Level)
+	}
+
+	if l.Data["type"] != "slowQuery" || l.Data["db"] != "data" || l.Data["route"] != "/api/test_slow" || l.Data["requestId"] != "test_id" {
+		t.Fatalf("Unexpected log data %v", l.Data)
+	}
+
+	expectedSQL := "WITH RECURSIVE c(x) AS (SELECT ? UNION ALL SELECT x+? FROM c WHERE x < ?) SELECT count(*), ? FROM c"
+	if l.Data["sql"] != expectedSQL {
+		t.Fatalf("Unexpected logged sql %v", l.Data["sql"])
+	}
+
+	if execTime, _ := l.Data["execTime"].(float64); execTime < 1 {
+		t.Fatalf("Expected execTime >= 1, got %v", l.Data["execTime"])
+	}
+
+	if _, ok := l.Data["plan"]; ok {
+		t.Fatal("Didn't expect plan capture for non-postgres db")
+	}
+}
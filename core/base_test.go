@@ -50,6 +50,10 @@ func TestNewBaseApp(t *testing.T) {
 	if app.subscriptionsBroker == nil {
 		t.Fatal("expected subscriptionsBroker to be set, got nil")
 	}
+
+	if app.cron == nil {
+		t.Fatal("expected cron to be set, got nil")
+	}
 }
 
 func TestBaseAppBootstrap(t *testing.T) {
@@ -195,6 +199,10 @@ func TestBaseAppGetters(t *testing.T) {
 		t.Fatalf("Expected app.SubscriptionsBroker %v, got %v", app.SubscriptionsBroker(), app.subscriptionsBroker)
 	}
 
+	if app.cron != app.Cron() {
+		t.Fatalf("Expected app.Cron %v, got %v", app.Cron(), app.cron)
+	}
+
 	if app.onBeforeServe != app.OnBeforeServe() || app.OnBeforeServe() == nil {
 		t.Fatalf("Getter app.OnBeforeServe does not match or nil (%v vs %v)", app.OnBeforeServe(), app.onBeforeServe)
 	}
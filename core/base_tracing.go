@@ -0,0 +1,174 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/models/settings"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/dbutils"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/tracing"
+	"github.com/pocketbase/dbx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracer returns the app traces exporter (or nil if tracing is disabled).
+func (app *BaseApp) Tracer() *tracing.Tracer {
+	return app.tracer.Load()
+}
+
+// SetTracer replaces the default settings based OTLP tracer
+// with a custom one (eg. with an in-process exporter for tests).
+//
+// Set it to nil to restore the default behavior.
+func (app *BaseApp) SetTracer(tracer *tracing.Tracer) {
+	app.tracerMux.Lock()
+	defer app.tracerMux.Unlock()
+
+	app.customTracer = tracer != nil
+	app.tracerConfig = settings.TracingConfig{}
+
+	app.replaceTracer(tracer)
+
+	if !app.customTracer {
+		app.refreshTracer()
+	}
+}
+
+// refreshTracer (re)initializes the OTLP tracer if the tracing settings has changed.
+//
+// It is expected to be called with locked tracerMux.
+func (app *BaseApp) refreshTracer() {
+	if app.customTracer || app.settings == nil {
+		return
+	}
+
+	config := app.settings.Tracing
+	if !config.Enabled {
+		config = settings.TracingConfig{}
+	}
+
+	if config == app.tracerConfig {
+		return // no changes
+	}
+	app.tracerConfig = config
+
+	if !config.Enabled {
+		app.replaceTracer(nil)
+		return
+	}
+
+	exporter, err := tracing.NewOTLPExporter(config.Endpoint, nil)
+	if err != nil {
+		app.Logger().Error("Failed to initialize the OTLP traces exporter", slog.String("error", err.Error()))
+		app.replaceTracer(nil)
+		return
+	}
+
+	app.replaceTracer(tracing.NewTracer(config.ServiceName, exporter, config.SampleRatio))
+}
+
+// replaceTracer replaces the current app tracer and shutdowns the old one (if any).
+func (app *BaseApp) replaceTracer(tracer *tracing.Tracer) {
+	old := app.tracer.Swap(tracer)
+
+	if old != nil && old != tracer {
+		// flush the remaining spans in the background
+		go old.Shutdown(context.Background())
+	}
+}
+
+// initHookTracing registers an app hooks trigger observer that
+// traces the hook triggers.
+//
+// The hooks of the events bound to a traced context (see [ContextEvent])
+// are traced as part of the context trace and all other hooks
+// (eg. the mailer or the model hooks outside of a request) start a new trace.
+func (app *BaseApp) initHookTracing() {
+	app.hookTracingObserverId = app.hookObservers.AddTriggerObserver(func(data any) func(err error) {
+		tracer := app.Tracer()
+		if tracer == nil {
+			return nil
+		}
+
+		var ctx context.Context
+		if e, ok := data.(ContextEvent); ok {
+			ctx = e.Context()
+		}
+
+		name := fmt.Sprintf("hook %T", data)
+
+		var span trace.Span
+		if trace.SpanContextFromContext(ctx).IsValid() {
+			_, span = tracing.Start(ctx, name)
+		} else {
+			_, span = tracer.Start(context.Background(), name)
+		}
+
+		if !span.IsRecording() {
+			return nil
+		}
+
+		return func(err error) {
+			tracing.RecordError(span, err)
+			span.End()
+		}
+	})
+}
+
+// attachDBTracing wraps the db query and exec log functions to trace
+// the queries executed with a traced context (eg. with a Dao bound to
+// the request context, see [daos.Dao.WithContext]).
+//
+// The traced statements are without the inlined params values.
+func (app *BaseApp) attachDBTracing(db *dbx.DB, dbName string) {
+	traceQuery := func(ctx context.Context, t time.Duration, operation string, query string, err error) {
+		if ctx == nil {
+			return
+		}
+
+		end := time.Now()
+
+		_, span := tracing.Start(
+			ctx,
+			"db."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithTimestamp(end.Add(-t)),
+			trace.WithAttributes(
+				attribute.String("db.system", db.DriverName()),
+				attribute.String("db.name", dbName),
+				attribute.String("db.statement", dbutils.SanitizeSQL(query)),
+			),
+		)
+		if !span.IsRecording() {
+			return
+		}
+
+		if !errors.Is(err, sql.ErrNoRows) {
+			tracing.RecordError(span, err)
+		}
+		span.End(trace.WithTimestamp(end))
+	}
+
+	queryLogFunc := db.QueryLogFunc
+	db.QueryLogFunc = func(ctx context.Context, t time.Duration, sql string, rows *sql.Rows, err error) {
+		traceQuery(ctx, t, "query", sql, err)
+
+		if queryLogFunc != nil {
+			queryLogFunc(ctx, t, sql, rows, err)
+		}
+	}
+
+	execLogFunc := db.ExecLogFunc
+	db.ExecLogFunc = func(ctx context.Context, t time.Duration, sql string, result sql.Result, err error) {
+		traceQuery(ctx, t, "exec", sql, err)
+
+		if execLogFunc != nil {
+			execLogFunc(ctx, t, sql, result, err)
+		}
+	}
+}
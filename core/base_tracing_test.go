@@ -0,0 +1,141 @@
+package core_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/core"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/tests"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/tracing"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestBaseAppTracer(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	if app.Tracer() != nil {
+		t.Fatal("Expected nil tracer for disabled tracing")
+	}
+
+	// enable from the settings
+	app.Settings().Tracing.Enabled = true
+	app.Settings().Tracing.Endpoint = "http://localhost:4318/v1/traces"
+	if err := app.Dao().SaveSettings(app.Settings()); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.RefreshSettings(); err != nil {
+		t.Fatal(err)
+	}
+
+	settingsTracer := app.Tracer()
+	if settingsTracer == nil {
+		t.Fatal("Expected non-nil settings tracer")
+	}
+
+	// refresh without changes
+	if err := app.RefreshSettings(); err != nil {
+		t.Fatal(err)
+	}
+	if app.Tracer() != settingsTracer {
+		t.Fatal("Expected the settings tracer to be reused")
+	}
+
+	// custom tracer
+	customTracer := tracing.NewTracer("test", tracetest.NewInMemoryExporter(), 1)
+	app.SetTracer(customTracer)
+	if app.Tracer() != customTracer {
+		t.Fatal("Expected the custom tracer")
+	}
+
+	// should be ignored for custom tracers
+	app.Settings().Tracing.Enabled = false
+	if err := app.Dao().SaveSettings(app.Settings()); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.RefreshSettings(); err != nil {
+		t.Fatal(err)
+	}
+	if app.Tracer() != customTracer {
+		t.Fatal("Expected the custom tracer to remain after settings refresh")
+	}
+
+	// restore the settings tracer (which is disabled)
+	app.SetTracer(nil)
+	if app.Tracer() != nil {
+		t.Fatal("Expected nil tracer after restoring the disabled settings tracer")
+	}
+}
+
+func TestBaseAppHookAndDBTracing(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	exporter := tracetest.NewInMemoryExporter()
+	app.SetTracer(tracing.NewTracer("test", exporter, 1))
+
+	ctx, root := app.Tracer().Start(context.Background(), "root")
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record := models.NewRecord(collection)
+	record.Set("title", "secret_title")
+
+	// model hooks and queries with a dao bound to a traced context
+	if err := app.Dao().WithContext(ctx).SaveRecord(record); err != nil {
+		t.Fatal(err)
+	}
+
+	root.End()
+
+	// hooks without context
+	app.OnMailerBeforeAdminResetPasswordSend().Trigger(&core.MailerAdminEvent{})
+
+	if err := app.Tracer().ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var modelHooks, dbExecs, mailerHooks int
+
+	for _, span := range exporter.GetSpans() {
+		switch span.Name {
+		case "hook *core.ModelEvent":
+			modelHooks++
+			if span.Parent.SpanID() != root.SpanContext().SpanID() {
+				t.Fatalf("Expected model hook span child of the root span, got %+v", span)
+			}
+		case "db.exec":
+			if span.SpanContext.TraceID() != root.SpanContext().TraceID() {
+				continue
+			}
+			dbExecs++
+			for _, attr := range span.Attributes {
+				if attr.Key == "db.statement" && strings.Contains(attr.Value.AsString(), "secret_title") {
+					t.Fatalf("Expected db.statement without the inlined params, got %q", attr.Value.AsString())
+				}
+			}
+		case "hook *core.MailerAdminEvent":
+			mailerHooks++
+			if span.Parent.IsValid() {
+				t.Fatalf("Expected mailer hook root span, got %+v", span)
+			}
+		}
+	}
+
+	if modelHooks != 2 {
+		t.Fatalf("Expected 2 model hook spans (before and after create), got %d", modelHooks)
+	}
+
+	if dbExecs == 0 {
+		t.Fatal("Expected db.exec spans")
+	}
+
+	if mailerHooks != 1 {
+		t.Fatalf("Expected 1 mailer hook span, got %d", mailerHooks)
+	}
+}
@@ -0,0 +1,101 @@
+package core
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+)
+
+// MaterializedViewCronJobIdPrefix is the id prefix of the app cron jobs
+// used for the scheduled materialized view refreshes
+// (the full job id is the prefix + the view collection id).
+const MaterializedViewCronJobIdPrefix string = "@refresh_"
+
+// initMaterializedViewsHooks registers the materialized views refresh app hooks.
+func (app *BaseApp) initMaterializedViewsHooks() error {
+	isServe := false
+
+	loadJobs := func() {
+		// remove the old jobs
+		// (note: the cron remembers the paused job ids and re-adding them preserves their paused state)
+		for _, job := range app.Cron().Jobs() {
+			if strings.HasPrefix(job.Id(), MaterializedViewCronJobIdPrefix) {
+				app.Cron().Remove(job.Id())
+			}
+		}
+
+		if !isServe || !app.IsBootstrapped() {
+			return
+		}
+
+		collections, err := app.Dao().FindCollectionsByType(models.CollectionTypeView)
+		if err != nil {
+			app.Logger().Debug(
+				"[Materialized view cron] Failed to load the view collections",
+				slog.String("error", err.Error()),
+			)
+			return
+		}
+
+		for _, collection := range collections {
+			options := collection.ViewOptions()
+			if !options.Materialized || options.RefreshCron == "" {
+				continue
+			}
+
+			collectionId := collection.Id
+			jobId := MaterializedViewCronJobIdPrefix + collectionId
+
+			err := app.Cron().Add(jobId, options.RefreshCron, func() {
+				// always reload the collection to ensure that
+				// the latest name and indexes are used
+				c, err := app.Dao().FindCollectionByNameOrId(collectionId)
+				if err != nil {
+					app.Logger().Debug(
+						"[Materialized view cron] Failed to load the view collection",
+						slog.String("collectionId", collectionId),
+						slog.String("error", err.Error()),
+					)
+					return
+				}
+
+				if err := app.Dao().RefreshMaterializedView(c); err != nil {
+					app.Logger().Error(
+						"[Materialized view cron] Failed to refresh view",
+						slog.String("collectionId", c.Id),
+						slog.String("collectionName", c.Name),
+						slog.String("error", err.Error()),
+					)
+				}
+			})
+			if err != nil {
+				app.Logger().Debug(
+					"[Materialized view cron] Failed to register refresh job",
+					slog.String("collectionId", collectionId),
+					slog.String("error", err.Error()),
+				)
+				continue
+			}
+		}
+	}
+
+	// load on app serve
+	app.OnBeforeServe().Add(func(e *ServeEvent) error {
+		isServe = true
+		loadJobs()
+		return nil
+	})
+
+	// reload on collections change
+	collectionsChangeFunc := func(e *ModelEvent) error {
+		loadJobs()
+		return nil
+	}
+	collectionsTable := (&models.Collection{}).TableName()
+	app.OnModelAfterCreate(collectionsTable).Add(collectionsChangeFunc)
+	app.OnModelAfterUpdate(collectionsTable).Add(collectionsChangeFunc)
+	app.OnModelAfterDelete(collectionsTable).Add(collectionsChangeFunc)
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package core
+
+import "context"
+
+type routeContextKey struct{}
+
+type requestIdContextKey struct{}
+
+// ContextWithRequestId returns a copy of ctx with the provided
+// originating request correlation id.
+//
+// The request id is attached to every log record written
+// with ctx (eg. app.Logger().InfoContext(ctx, ...)) or
+// with the ctx request-scoped logger (see [BaseApp.ContextLogger]).
+func ContextWithRequestId(ctx context.Context, requestId string) context.Context {
+	return context.WithValue(ctx, requestIdContextKey{}, requestId)
+}
+
+// RequestIdFromContext returns the request correlation id associated
+// with ctx (or empty string if there is none).
+func RequestIdFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	requestId, _ := ctx.Value(requestIdContextKey{}).(string)
+
+	return requestId
+}
+
+// ContextWithRoute returns a copy of ctx with the provided
+// originating request route (eg. "/api/collections/:collection/records").
+//
+// The route is used as additional information in the
+// contextual logs (eg. the slow queries log).
+func ContextWithRoute(ctx context.Context, route string) context.Context {
+	return context.WithValue(ctx, routeContextKey{}, route)
+}
+
+// RouteFromContext returns the request route associated
+// with ctx (or empty string if there is none).
+func RouteFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	route, _ := ctx.Value(routeContextKey{}).(string)
+
+	return route
+}
@@ -0,0 +1,95 @@
+package core_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/core"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/tests"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/logger"
+	"github.com/pocketbase/dbx"
+)
+
+func TestRouteContext(t *testing.T) {
+	if route := core.RouteFromContext(context.Background()); route != "" {
+		t.Fatalf("Expected empty route, got %q", route)
+	}
+
+	ctx := core.ContextWithRoute(context.Background(), "/api/test")
+	if route := core.RouteFromContext(ctx); route != "/api/test" {
+		t.Fatalf("Expected route /api/test, got %q", route)
+	}
+}
+
+func TestRequestIdContext(t *testing.T) {
+	if requestId := core.RequestIdFromContext(context.Background()); requestId != "" {
+		t.Fatalf("Expected empty request id, got %q", requestId)
+	}
+
+	ctx := core.ContextWithRequestId(context.Background(), "test_id")
+	if requestId := core.RequestIdFromContext(ctx); requestId != "test_id" {
+		t.Fatalf("Expected request id test_id, got %q", requestId)
+	}
+}
+
+func TestBaseAppLoggerRequestId(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().Logs.MaxDays = 1
+	app.Settings().Logs.MinLevel = int(slog.LevelInfo)
+
+	ctx := core.ContextWithRequestId(context.Background(), "test_id")
+
+	app.Logger().InfoContext(ctx, "request_id_test1")
+	app.Logger().InfoContext(ctx, "request_id_test2", slog.String("requestId", "custom_id"))
+	app.Logger().Info("request_id_test3")
+
+	app.Logger().Handler().(*logger.BatchHandler).WriteAll(context.Background())
+
+	expected := map[string]any{
+		"request_id_test1": "test_id",
+		"request_id_test2": "custom_id",
+		"request_id_test3": nil,
+	}
+
+	for message, requestId := range expected {
+		l := &models.Log{}
+		if err := app.LogsDao().LogQuery().AndWhere(dbx.HashExp{"message": message}).One(l); err != nil {
+			t.Fatalf("[%s] %v", message, err)
+		}
+
+		if l.Data["requestId"] != requestId {
+			t.Fatalf("[%s] Expected requestId %v, got %v", message, requestId, l.Data["requestId"])
+		}
+	}
+}
+
+func TestBaseAppContextLogger(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().Logs.MaxDays = 1
+	app.Settings().Logs.MinLevel = int(slog.LevelInfo)
+
+	if app.ContextLogger(context.Background()) != app.Logger() {
+		t.Fatal("Expected the app logger for ctx without request id")
+	}
+
+	ctx := core.ContextWithRequestId(context.Background(), "test_id")
+
+	app.ContextLogger(ctx).Info("context_logger_test")
+
+	app.Logger().Handler().(*logger.BatchHandler).WriteAll(context.Background())
+
+	l := &models.Log{}
+	if err := app.LogsDao().LogQuery().AndWhere(dbx.HashExp{"message": "context_logger_test"}).One(l); err != nil {
+		t.Fatal(err)
+	}
+
+	if l.Data["requestId"] != "test_id" {
+		t.Fatalf("Expected requestId test_id, got %v", l.Data["requestId"])
+	}
+}
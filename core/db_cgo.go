@@ -4,6 +4,7 @@ package core
 
 import (
 	"database/sql"
+	"fmt"
 
 	"github.com/mattn/go-sqlite3"
 	"github.com/pocketbase/dbx"
@@ -31,8 +32,11 @@ func init() {
 					PRAGMA temp_store         = MEMORY;
 					PRAGMA cache_size         = -16000;
 				`, nil)
+				if err != nil {
+					return err
+				}
 
-				return err
+				return registerConfigFuncs(conn)
 			},
 		},
 	)
@@ -48,3 +52,59 @@ func connectDB(dbPath string) (*dbx.DB, error) {
 
 	return db, nil
 }
+
+// registerConfigFuncs registers SQLite equivalents of the Postgres
+// set_config and current_setting run-time parameters functions
+// used by the multi-tenant collections (see [daos.Dao.WithTenant]).
+//
+// The parameters are stored per connection and the transaction local
+// ones are reset at the start and end of each transaction.
+//
+// Note that SQLite doesn't have row-level security policies and therefore
+// the tenant isolation itself is not enforced (the functions allow only
+// exercising the multi-tenant code paths with the SQLite db, eg. in tests).
+func registerConfigFuncs(conn *sqlite3.SQLiteConn) error {
+	settings := map[string]string{}
+	localSettings := map[string]string{}
+
+	err := conn.RegisterFunc("set_config", func(name string, value string, isLocal bool) string {
+		if isLocal {
+			// similar to Postgres, the local parameters have no effect outside of a transaction
+			if !conn.AutoCommit() {
+				localSettings[name] = value
+			}
+		} else {
+			settings[name] = value
+		}
+		return value
+	}, false)
+	if err != nil {
+		return err
+	}
+
+	err = conn.RegisterFunc("current_setting", func(name string, missingOk bool) (string, error) {
+		if v, ok := localSettings[name]; ok {
+			return v, nil
+		}
+
+		if v, ok := settings[name]; ok || missingOk {
+			return v, nil
+		}
+
+		return "", fmt.Errorf("unrecognized configuration parameter %q", name)
+	}, false)
+	if err != nil {
+		return err
+	}
+
+	// reset the transaction local parameters on BEGIN, COMMIT and ROLLBACK
+	// (the commit and rollback hooks are not invoked for read-only transactions)
+	conn.RegisterAuthorizer(func(action int, _, _, _ string) int {
+		if action == sqlite3.SQLITE_TRANSACTION {
+			clear(localSettings)
+		}
+		return sqlite3.SQLITE_OK
+	})
+
+	return nil
+}
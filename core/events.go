@@ -1,6 +1,7 @@
 package core
 
 import (
+	"context"
 	"net/http"
 	"time"
 
@@ -23,6 +24,18 @@ var (
 	_ hook.Tagger = (*BaseCollectionEvent)(nil)
 )
 
+var (
+	_ ContextEvent = (*BaseRequestEvent)(nil)
+	_ ContextEvent = (*ModelEvent)(nil)
+)
+
+// ContextEvent defines the hook events that are bound to a context
+// (eg. the api request events).
+type ContextEvent interface {
+	// Context returns the event context (or nil if there is none).
+	Context() context.Context
+}
+
 type BaseModelEvent struct {
 	Model models.Model
 }
@@ -61,6 +74,18 @@ func (e *BaseCollectionEvent) Tags() []string {
 	return tags
 }
 
+type BaseRequestEvent struct {
+	HttpContext echo.Context
+}
+
+func (e *BaseRequestEvent) Context() context.Context {
+	if e.HttpContext == nil || e.HttpContext.Request() == nil {
+		return nil
+	}
+
+	return e.HttpContext.Request().Context()
+}
+
 // -------------------------------------------------------------------
 // Serve events data
 // -------------------------------------------------------------------
@@ -82,8 +107,9 @@ type ServeEvent struct {
 }
 
 type ApiErrorEvent struct {
-	HttpContext echo.Context
-	Error       error
+	BaseRequestEvent
+
+	Error error
 }
 
 // -------------------------------------------------------------------
@@ -96,6 +122,14 @@ type ModelEvent struct {
 	Dao *daos.Dao
 }
 
+func (e *ModelEvent) Context() context.Context {
+	if e.Dao == nil {
+		return nil
+	}
+
+	return e.Dao.Context()
+}
+
 // -------------------------------------------------------------------
 // Mailer events data
 // -------------------------------------------------------------------
@@ -121,24 +155,28 @@ type MailerAdminEvent struct {
 // -------------------------------------------------------------------
 
 type RealtimeConnectEvent struct {
-	HttpContext echo.Context
+	BaseRequestEvent
+
 	Client      subscriptions.Client
 	IdleTimeout time.Duration
 }
 
 type RealtimeDisconnectEvent struct {
-	HttpContext echo.Context
-	Client      subscriptions.Client
+	BaseRequestEvent
+
+	Client subscriptions.Client
 }
 
 type RealtimeMessageEvent struct {
-	HttpContext echo.Context
-	Client      subscriptions.Client
-	Message     *subscriptions.Message
+	BaseRequestEvent
+
+	Client  subscriptions.Client
+	Message *subscriptions.Message
 }
 
 type RealtimeSubscribeEvent struct {
-	HttpContext   echo.Context
+	BaseRequestEvent
+
 	Client        subscriptions.Client
 	Subscriptions []string
 }
@@ -148,12 +186,14 @@ type RealtimeSubscribeEvent struct {
 // -------------------------------------------------------------------
 
 type SettingsListEvent struct {
-	HttpContext      echo.Context
+	BaseRequestEvent
+
 	RedactedSettings *settings.Settings
 }
 
 type SettingsUpdateEvent struct {
-	HttpContext echo.Context
+	BaseRequestEvent
+
 	OldSettings *settings.Settings
 	NewSettings *settings.Settings
 }
@@ -164,40 +204,40 @@ type SettingsUpdateEvent struct {
 
 type RecordsListEvent struct {
 	BaseCollectionEvent
+	BaseRequestEvent
 
-	HttpContext echo.Context
-	Records     []*models.Record
-	Result      *search.Result
+	Records []*models.Record
+	Result  *search.Result
 }
 
 type RecordViewEvent struct {
 	BaseCollectionEvent
+	BaseRequestEvent
 
-	HttpContext echo.Context
-	Record      *models.Record
+	Record *models.Record
 }
 
 type RecordCreateEvent struct {
 	BaseCollectionEvent
+	BaseRequestEvent
 
-	HttpContext   echo.Context
 	Record        *models.Record
 	UploadedFiles map[string][]*filesystem.File
 }
 
 type RecordUpdateEvent struct {
 	BaseCollectionEvent
+	BaseRequestEvent
 
-	HttpContext   echo.Context
 	Record        *models.Record
 	UploadedFiles map[string][]*filesystem.File
 }
 
 type RecordDeleteEvent struct {
 	BaseCollectionEvent
+	BaseRequestEvent
 
-	HttpContext echo.Context
-	Record      *models.Record
+	Record *models.Record
 }
 
 // -------------------------------------------------------------------
@@ -206,26 +246,26 @@ type RecordDeleteEvent struct {
 
 type RecordAuthEvent struct {
 	BaseCollectionEvent
+	BaseRequestEvent
 
-	HttpContext echo.Context
-	Record      *models.Record
-	Token       string
-	Meta        any
+	Record *models.Record
+	Token  string
+	Meta   any
 }
 
 type RecordAuthWithPasswordEvent struct {
 	BaseCollectionEvent
+	BaseRequestEvent
 
-	HttpContext echo.Context
-	Record      *models.Record
-	Identity    string
-	Password    string
+	Record   *models.Record
+	Identity string
+	Password string
 }
 
 type RecordAuthWithOAuth2Event struct {
 	BaseCollectionEvent
+	BaseRequestEvent
 
-	HttpContext    echo.Context
 	ProviderName   string
 	ProviderClient auth.Provider
 	Record         *models.Record
@@ -233,67 +273,83 @@ type RecordAuthWithOAuth2Event struct {
 	IsNewRecord    bool
 }
 
+type RecordAuthWithOTPEvent struct {
+	BaseCollectionEvent
+	BaseRequestEvent
+
+	Record *models.Record
+	OTP    *models.OTP
+}
+
+type RecordAuthWithWebAuthnEvent struct {
+	BaseCollectionEvent
+	BaseRequestEvent
+
+	Record     *models.Record
+	Credential *models.WebAuthnCredential
+}
+
 type RecordAuthRefreshEvent struct {
 	BaseCollectionEvent
+	BaseRequestEvent
 
-	HttpContext echo.Context
-	Record      *models.Record
+	Record *models.Record
 }
 
 type RecordRequestPasswordResetEvent struct {
 	BaseCollectionEvent
+	BaseRequestEvent
 
-	HttpContext echo.Context
-	Record      *models.Record
+	Record *models.Record
 }
 
 type RecordConfirmPasswordResetEvent struct {
 	BaseCollectionEvent
+	BaseRequestEvent
 
-	HttpContext echo.Context
-	Record      *models.Record
+	Record *models.Record
 }
 
 type RecordRequestVerificationEvent struct {
 	BaseCollectionEvent
+	BaseRequestEvent
 
-	HttpContext echo.Context
-	Record      *models.Record
+	Record *models.Record
 }
 
 type RecordConfirmVerificationEvent struct {
 	BaseCollectionEvent
+	BaseRequestEvent
 
-	HttpContext echo.Context
-	Record      *models.Record
+	Record *models.Record
 }
 
 type RecordRequestEmailChangeEvent struct {
 	BaseCollectionEvent
+	BaseRequestEvent
 
-	HttpContext echo.Context
-	Record      *models.Record
+	Record *models.Record
 }
 
 type RecordConfirmEmailChangeEvent struct {
 	BaseCollectionEvent
+	BaseRequestEvent
 
-	HttpContext echo.Context
-	Record      *models.Record
+	Record *models.Record
 }
 
 type RecordListExternalAuthsEvent struct {
 	BaseCollectionEvent
+	BaseRequestEvent
 
-	HttpContext   echo.Context
 	Record        *models.Record
 	ExternalAuths []*models.ExternalAuth
 }
 
 type RecordUnlinkExternalAuthEvent struct {
 	BaseCollectionEvent
+	BaseRequestEvent
 
-	HttpContext  echo.Context
 	Record       *models.Record
 	ExternalAuth *models.ExternalAuth
 }
@@ -303,57 +359,67 @@ type RecordUnlinkExternalAuthEvent struct {
 // -------------------------------------------------------------------
 
 type AdminsListEvent struct {
-	HttpContext echo.Context
-	Admins      []*models.Admin
-	Result      *search.Result
+	BaseRequestEvent
+
+	Admins []*models.Admin
+	Result *search.Result
 }
 
 type AdminViewEvent struct {
-	HttpContext echo.Context
-	Admin       *models.Admin
+	BaseRequestEvent
+
+	Admin *models.Admin
 }
 
 type AdminCreateEvent struct {
-	HttpContext echo.Context
-	Admin       *models.Admin
+	BaseRequestEvent
+
+	Admin *models.Admin
 }
 
 type AdminUpdateEvent struct {
-	HttpContext echo.Context
-	Admin       *models.Admin
+	BaseRequestEvent
+
+	Admin *models.Admin
 }
 
 type AdminDeleteEvent struct {
-	HttpContext echo.Context
-	Admin       *models.Admin
+	BaseRequestEvent
+
+	Admin *models.Admin
 }
 
 type AdminAuthEvent struct {
-	HttpContext echo.Context
-	Admin       *models.Admin
-	Token       string
+	BaseRequestEvent
+
+	Admin *models.Admin
+	Token string
 }
 
 type AdminAuthWithPasswordEvent struct {
-	HttpContext echo.Context
-	Admin       *models.Admin
-	Identity    string
-	Password    string
+	BaseRequestEvent
+
+	Admin    *models.Admin
+	Identity string
+	Password string
 }
 
 type AdminAuthRefreshEvent struct {
-	HttpContext echo.Context
-	Admin       *models.Admin
+	BaseRequestEvent
+
+	Admin *models.Admin
 }
 
 type AdminRequestPasswordResetEvent struct {
-	HttpContext echo.Context
-	Admin       *models.Admin
+	BaseRequestEvent
+
+	Admin *models.Admin
 }
 
 type AdminConfirmPasswordResetEvent struct {
-	HttpContext echo.Context
-	Admin       *models.Admin
+	BaseRequestEvent
+
+	Admin *models.Admin
 }
 
 // -------------------------------------------------------------------
@@ -361,37 +427,35 @@ type AdminConfirmPasswordResetEvent struct {
 // -------------------------------------------------------------------
 
 type CollectionsListEvent struct {
-	HttpContext echo.Context
+	BaseRequestEvent
+
 	Collections []*models.Collection
 	Result      *search.Result
 }
 
 type CollectionViewEvent struct {
 	BaseCollectionEvent
-
-	HttpContext echo.Context
+	BaseRequestEvent
 }
 
 type CollectionCreateEvent struct {
 	BaseCollectionEvent
-
-	HttpContext echo.Context
+	BaseRequestEvent
 }
 
 type CollectionUpdateEvent struct {
 	BaseCollectionEvent
-
-	HttpContext echo.Context
+	BaseRequestEvent
 }
 
 type CollectionDeleteEvent struct {
 	BaseCollectionEvent
-
-	HttpContext echo.Context
+	BaseRequestEvent
 }
 
 type CollectionsImportEvent struct {
-	HttpContext echo.Context
+	BaseRequestEvent
+
 	Collections []*models.Collection
 }
 
@@ -401,17 +465,32 @@ type CollectionsImportEvent struct {
 
 type FileTokenEvent struct {
 	BaseModelEvent
+	BaseRequestEvent
 
-	HttpContext echo.Context
-	Token       string
+	Token string
 }
 
 type FileDownloadEvent struct {
 	BaseCollectionEvent
+	BaseRequestEvent
 
-	HttpContext echo.Context
-	Record      *models.Record
-	FileField   *schema.SchemaField
-	ServedPath  string
-	ServedName  string
+	Record     *models.Record
+	FileField  *schema.SchemaField
+	ServedPath string
+	ServedName string
+}
+
+// -------------------------------------------------------------------
+// Health API events data
+// -------------------------------------------------------------------
+
+// HealthCheckFunc defines a single readiness dependency check.
+//
+// The provided ctx is canceled when the check timeout expires.
+type HealthCheckFunc func(ctx context.Context) error
+
+type HealthReadyEvent struct {
+	BaseRequestEvent
+
+	Checks map[string]HealthCheckFunc
 }
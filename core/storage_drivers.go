@@ -0,0 +1,137 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/models/settings"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/filesystem"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"gocloud.dev/blob/memblob"
+)
+
+// MemoryStorageDriverName is the conventional name of the in-memory storage driver.
+const MemoryStorageDriverName = "memory"
+
+// StorageDriver defines a pluggable files storage driver
+// (eg. Azure Blob, GCS, SFTP, in-memory, etc.) that could be
+// registered with [BaseAppConfig.StorageDrivers] and selected
+// through the app storage and backups settings.
+type StorageDriver struct {
+	// NewConfig returns a new empty driver config instance (usually a struct pointer)
+	// into which the raw settings driver config is decoded.
+	//
+	// If the config implements [validation.Validatable], it is
+	// validated on settings save.
+	//
+	// Could be nil if the driver doesn't have any config options.
+	NewConfig func() any
+
+	// SecretFields is an optional list of the driver config keys
+	// that are redacted in the settings API responses.
+	SecretFields []string
+
+	// Open opens a new filesystem instance with the decoded driver config.
+	//
+	// Use [filesystem.NewFromBucket] to create the filesystem from any gocloud blob driver.
+	Open func(config any) (*filesystem.System, error)
+}
+
+// DecodeConfig decodes and validates the provided raw driver config.
+func (d *StorageDriver) DecodeConfig(rawConfig map[string]any) (any, error) {
+	if d.NewConfig == nil {
+		return nil, nil
+	}
+
+	config := d.NewConfig()
+
+	raw, err := json.Marshal(rawConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(raw, config); err != nil {
+		return nil, err
+	}
+
+	if v, ok := config.(validation.Validatable); ok {
+		if err := v.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return config, nil
+}
+
+// NewMemoryStorageDriver creates a new in-memory storage driver
+// (mostly useful for tests).
+//
+// All filesystem instances opened by the same driver share the same bucket.
+func NewMemoryStorageDriver() *StorageDriver {
+	bucket := memblob.OpenBucket(nil)
+
+	return &StorageDriver{
+		Open: func(config any) (*filesystem.System, error) {
+			return filesystem.NewFromBucket(bucket, true), nil
+		},
+	}
+}
+
+// StorageDriver returns the registered storage driver with the specified name
+// (returns nil if there is no such driver).
+func (app *BaseApp) StorageDriver(name string) *StorageDriver {
+	return app.storageDrivers[name]
+}
+
+// NewStorageFilesystem opens a new raw filesystem (aka. without files
+// encryption and deduplication) for the provided storage settings.
+//
+// The storage is resolved in the same order as [BaseApp.NewFilesystem]:
+// the registered custom storage driver (if storage.Driver is set),
+// the S3 storage (if s3.Enabled is set) or the localDir filesystem.
+//
+// NB! Make sure to call Close() on the returned result
+// after you are done working with it.
+func NewStorageFilesystem(app App, storage settings.StorageConfig, s3 settings.S3Config, localDir string) (*filesystem.System, error) {
+	if storage.Driver != "" {
+		return newDriverFilesystem(app, storage)
+	}
+
+	if s3.Enabled {
+		return filesystem.NewS3(
+			s3.Bucket,
+			s3.Region,
+			s3.Endpoint,
+			s3.AccessKey,
+			s3.Secret,
+			s3.ForcePathStyle,
+		)
+	}
+
+	return filesystem.NewLocal(localDir)
+}
+
+// newDriverFilesystem opens a new filesystem with the registered storage driver from the provided config.
+func newDriverFilesystem(app App, config settings.StorageConfig) (*filesystem.System, error) {
+	driver := app.StorageDriver(config.Driver)
+	if driver == nil {
+		return nil, fmt.Errorf("missing storage driver %q", config.Driver)
+	}
+
+	decoded, err := driver.DecodeConfig(config.Config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %q storage driver config: %w", config.Driver, err)
+	}
+
+	fsys, err := driver.Open(decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	if fsys == nil {
+		return nil, errors.New("the storage driver returned nil filesystem")
+	}
+
+	return fsys, nil
+}
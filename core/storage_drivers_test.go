@@ -0,0 +1,150 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/filesystem"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type testStorageDriverConfig struct {
+	Bucket string `json:"bucket"`
+	Secret string `json:"secret"`
+}
+
+func (c testStorageDriverConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Bucket, validation.Required),
+	)
+}
+
+func TestStorageDriverDecodeConfig(t *testing.T) {
+	driver := &StorageDriver{
+		NewConfig: func() any {
+			return &testStorageDriverConfig{}
+		},
+	}
+
+	scenarios := []struct {
+		name        string
+		raw         map[string]any
+		expectError bool
+	}{
+		{"nil", nil, true},
+		{"invalid type", map[string]any{"bucket": 123}, true},
+		{"missing required", map[string]any{"secret": "test"}, true},
+		{"valid", map[string]any{"bucket": "test", "secret": "test"}, false},
+	}
+
+	for _, s := range scenarios {
+		config, err := driver.DecodeConfig(s.raw)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		decoded, ok := config.(*testStorageDriverConfig)
+		if !ok || decoded.Bucket != "test" || decoded.Secret != "test" {
+			t.Errorf("[%s] Unexpected decoded config %v", s.name, config)
+		}
+	}
+
+	// driver without config
+	if config, err := (&StorageDriver{}).DecodeConfig(map[string]any{"a": 1}); err != nil || config != nil {
+		t.Fatalf("Expected nil config and error, got %v (%v)", config, err)
+	}
+}
+
+func TestBaseAppStorageDriverFilesystem(t *testing.T) {
+	var openedConfig any
+
+	memory := NewMemoryStorageDriver()
+
+	app := NewBaseApp(BaseAppConfig{
+		DataDir: t.TempDir(),
+		StorageDrivers: map[string]*StorageDriver{
+			MemoryStorageDriverName: memory,
+			"test": {
+				NewConfig: func() any {
+					return &testStorageDriverConfig{}
+				},
+				Open: func(config any) (*filesystem.System, error) {
+					openedConfig = config
+					return memory.Open(nil)
+				},
+			},
+			"failing": {
+				Open: func(config any) (*filesystem.System, error) {
+					return nil, errors.New("test")
+				},
+			},
+		},
+	})
+
+	if app.StorageDriver("missing") != nil {
+		t.Fatal("Expected nil missing driver")
+	}
+
+	if app.StorageDriver(MemoryStorageDriverName) != memory {
+		t.Fatal("Expected the registered memory driver")
+	}
+
+	scenarios := []struct {
+		name        string
+		driver      string
+		config      map[string]any
+		expectError bool
+	}{
+		{"missing driver", "missing", nil, true},
+		{"failing driver", "failing", nil, true},
+		{"invalid config", "test", map[string]any{}, true},
+		{"valid config", "test", map[string]any{"bucket": "test"}, false},
+		{"memory", MemoryStorageDriverName, nil, false},
+	}
+
+	for _, s := range scenarios {
+		app.Settings().Storage.Driver = s.driver
+		app.Settings().Storage.Config = s.config
+		app.Settings().Backups.Storage.Driver = s.driver
+		app.Settings().Backups.Storage.Config = s.config
+
+		fsys, err := app.NewFilesystem()
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		backupsFsys, backupsErr := app.NewBackupsFilesystem()
+		if (backupsErr != nil) != s.expectError {
+			t.Errorf("[%s] Expected backups hasErr %v, got %v (%v)", s.name, s.expectError, backupsErr != nil, backupsErr)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		// the memory driver filesystems share the same bucket
+		if err := fsys.Upload([]byte("test"), s.name+".txt"); err != nil {
+			t.Fatalf("[%s] Failed to upload: %v", s.name, err)
+		}
+		fsys.Close()
+
+		if exists, _ := backupsFsys.Exists(s.name + ".txt"); !exists {
+			t.Fatalf("[%s] Expected the file to exist in the shared memory bucket", s.name)
+		}
+		backupsFsys.Close()
+	}
+
+	if c, ok := openedConfig.(*testStorageDriverConfig); !ok || c.Bucket != "test" {
+		t.Fatalf("Expected the decoded config to be passed to Open, got %v", openedConfig)
+	}
+}
@@ -5,6 +5,7 @@
 package daos
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -43,9 +44,16 @@ type Dao struct {
 
 	// ModelQueryTimeout is the default max duration of a running ModelQuery().
 	//
-	// This field has no effect if an explicit query context is already specified.
+	// This field has no effect if the query context already has a deadline.
 	ModelQueryTimeout time.Duration
 
+	// tenant scope of the new transactions (see WithTenant and WithoutTenantIsolation)
+	tenant       string
+	tenantBypass bool
+
+	// the context of the dao queries (see WithContext)
+	ctx context.Context
+
 	// write hooks
 	BeforeCreateFunc func(eventDao *Dao, m models.Model, action func() error) error
 	AfterCreateFunc  func(eventDao *Dao, m models.Model) error
@@ -78,6 +86,31 @@ func (dao *Dao) NonconcurrentDB() dbx.Builder {
 	return dao.nonconcurrentDB
 }
 
+// WithContext returns a new Dao which queries and transactions
+// are bound to the provided context (eg. for request tracing).
+//
+// It has no effect on the queries of an already started transaction.
+func (dao *Dao) WithContext(ctx context.Context) *Dao {
+	clone := dao.Clone()
+	clone.ctx = ctx
+
+	if db, ok := dao.concurrentDB.(*dbx.DB); ok {
+		clone.concurrentDB = db.WithContext(ctx)
+	}
+
+	if db, ok := dao.nonconcurrentDB.(*dbx.DB); ok {
+		clone.nonconcurrentDB = db.WithContext(ctx)
+	}
+
+	return clone
+}
+
+// Context returns the context the current Dao is bound to
+// (or nil if there is none).
+func (dao *Dao) Context() context.Context {
+	return dao.ctx
+}
+
 // Clone returns a new Dao with the same configuration options as the current one.
 func (dao *Dao) Clone() *Dao {
 	clone := *dao
@@ -144,6 +177,9 @@ func (dao *Dao) RunInTransaction(fn func(txDao *Dao) error) error {
 		txDao.AfterCreateFunc = dao.AfterCreateFunc
 		txDao.AfterUpdateFunc = dao.AfterUpdateFunc
 		txDao.AfterDeleteFunc = dao.AfterDeleteFunc
+		txDao.tenant = dao.tenant
+		txDao.tenantBypass = dao.tenantBypass
+		txDao.ctx = dao.ctx
 
 		return fn(txDao)
 	case *dbx.DB:
@@ -151,6 +187,13 @@ func (dao *Dao) RunInTransaction(fn func(txDao *Dao) error) error {
 
 		txError := txOrDB.Transactional(func(tx *dbx.Tx) error {
 			txDao := New(tx)
+			txDao.tenant = dao.tenant
+			txDao.tenantBypass = dao.tenantBypass
+			txDao.ctx = dao.ctx
+
+			if err := txDao.applyTenantScope(); err != nil {
+				return err
+			}
 
 			if dao.BeforeCreateFunc != nil {
 				txDao.BeforeCreateFunc = func(eventDao *Dao, m models.Model, action func() error) error {
@@ -16,12 +16,19 @@ var defaultRetryIntervals = []int{100, 250, 350, 500, 700, 1000}
 
 func execLockRetry(timeout time.Duration, maxRetries int) dbx.ExecHookFunc {
 	return func(q *dbx.Query, op func() error) error {
-		if q.Context() == nil {
-			cancelCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		originalCtx := q.Context()
+
+		parentCtx := originalCtx
+		if parentCtx == nil {
+			parentCtx = context.Background()
+		}
+
+		if _, hasDeadline := parentCtx.Deadline(); !hasDeadline {
+			cancelCtx, cancel := context.WithTimeout(parentCtx, timeout)
 			defer func() {
 				cancel()
 				//nolint:staticcheck
-				q.WithContext(nil) // reset
+				q.WithContext(originalCtx) // reset
 			}()
 			q.WithContext(cancelCtx)
 		}
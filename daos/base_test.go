@@ -1,6 +1,7 @@
 package daos_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -245,6 +246,48 @@ func TestDaoModelQueryCancellation(t *testing.T) {
 	}
 }
 
+func TestDaoWithContext(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	type ctxKey struct{}
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "test")
+
+	dao := testApp.Dao().WithContext(ctx)
+
+	if testApp.Dao().Context() != nil {
+		t.Fatal("Expected the original dao to remain without context")
+	}
+
+	if dao.Context() != ctx {
+		t.Fatalf("Expected the dao to be bound to ctx, got %v", dao.Context())
+	}
+
+	if q := dao.DB().NewQuery("SELECT 1"); q.Context() != ctx {
+		t.Fatalf("Expected the dao queries to be bound to ctx, got %v", q.Context())
+	}
+
+	dao.RunInTransaction(func(txDao *daos.Dao) error {
+		if txDao.Context() != ctx {
+			t.Fatalf("Expected the transaction dao to be bound to ctx, got %v", txDao.Context())
+		}
+
+		if q := txDao.DB().NewQuery("SELECT 1"); q.Context() != ctx {
+			t.Fatalf("Expected the transaction queries to be bound to ctx, got %v", q.Context())
+		}
+
+		return nil
+	})
+
+	// the model query timeout should be still applied
+	dao.ModelQueryTimeout = 0 * time.Millisecond
+	m := &models.Admin{}
+	if err := dao.ModelQuery(m).One(m); err == nil {
+		t.Fatal("Expected to be cancelled, got nil")
+	}
+}
+
 func TestDaoFindById(t *testing.T) {
 	testApp, _ := tests.NewTestApp()
 	defer testApp.Cleanup()
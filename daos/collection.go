@@ -352,10 +352,11 @@ func (dao *Dao) ImportCollections(
 
 // saveViewCollection persists the provided View collection changes:
 //   - deletes the old related SQL view (if any)
-//   - creates a new SQL view with the latest newCollection.Options.Query
+//   - creates a new SQL view (or materialized view) with the latest newCollection.Options.Query
 //   - generates a new schema based on newCollection.Options.Query
 //   - updates newCollection.Schema based on the generated view table info and query
 //   - saves the newCollection
+//   - creates the newCollection.Indexes (materialized views only)
 //
 // This method returns an error if newCollection is not a "view".
 func (dao *Dao) saveViewCollection(newCollection, oldCollection *models.Collection) error {
@@ -386,13 +387,25 @@ func (dao *Dao) saveViewCollection(newCollection, oldCollection *models.Collecti
 		}
 
 		// (re)create the view
-		if err := txDao.SaveView(newCollection.Name, query); err != nil {
-			return err
+		if newCollection.IsMaterializedView() {
+			if err := txDao.SaveMaterializedView(newCollection.Name, query); err != nil {
+				return err
+			}
+		} else {
+			if err := txDao.SaveView(newCollection.Name, query); err != nil {
+				return err
+			}
 		}
 
 		newCollection.Schema = viewSchema
 
-		return txDao.Save(newCollection)
+		if err := txDao.Save(newCollection); err != nil {
+			return err
+		}
+
+		// the materialized view indexes are dropped together with the old view
+		// so they need to be recreated every time
+		return txDao.createCollectionIndexes(newCollection)
 	})
 }
 
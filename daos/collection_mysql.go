@@ -363,6 +363,10 @@ func (dao *Dao) saveViewCollection(newCollection, oldCollection *models.Collecti
 		return errors.New("not a view collection")
 	}
 
+	if newCollection.IsMaterializedView() {
+		return errMaterializedViewsNotSupported
+	}
+
 	return dao.RunInTransaction(func(txDao *Dao) error {
 		query := newCollection.ViewOptions().Query
 
@@ -0,0 +1,197 @@
+package daos
+
+import (
+	"database/sql"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/list"
+	"github.com/pocketbase/dbx"
+)
+
+const (
+	fileBlobsTableName    = "_fileBlobs"
+	fileBlobRefsTableName = "_fileBlobRefs"
+)
+
+// AddFileBlobRef registers fileKey as a reference to the
+// content-addressed blob stored at blobKey.
+//
+// If fileKey was a reference to another blob, the old reference
+// is released and onLastRef is called if it was the last one.
+//
+// It implements the [filesystem.DedupIndex] interface.
+func (dao *Dao) AddFileBlobRef(fileKey string, blobKey string, onLastRef func(blobKey string) error) error {
+	return dao.RunInTransaction(func(txDao *Dao) error {
+		oldBlobKey, err := txDao.deleteFileBlobRef(fileKey)
+		if err != nil {
+			return err
+		}
+
+		if oldBlobKey != blobKey {
+			if oldBlobKey != "" {
+				if err := txDao.decrementFileBlobRefs(map[string]int{oldBlobKey: 1}, onLastRef); err != nil {
+					return err
+				}
+			}
+
+			if err := txDao.incrementFileBlobRefs(blobKey); err != nil {
+				return err
+			}
+		}
+
+		_, err = txDao.NonconcurrentDB().Insert(fileBlobRefsTableName, dbx.Params{
+			"fileKey": fileKey,
+			"blobKey": blobKey,
+		}).Execute()
+
+		return err
+	})
+}
+
+// ReleaseFileBlobRefs releases the blob references of the specified
+// file keys with a single batched query and calls onLastRef for each
+// blob whose last reference was released.
+//
+// The blob rows remain locked until onLastRef completes so that
+// a concurrent AddFileBlobRef call wouldn't reuse a deleted blob.
+//
+// Returns the number of the released references
+// (the file keys that are not blob references are ignored).
+//
+// It implements the [filesystem.DedupIndex] interface.
+func (dao *Dao) ReleaseFileBlobRefs(fileKeys []string, onLastRef func(blobKey string) error) (int, error) {
+	if len(fileKeys) == 0 {
+		return 0, nil
+	}
+
+	// fast check to avoid starting a transaction for the regular files
+	var exists int
+	err := dao.ConcurrentDB().Select("(1)").
+		From(fileBlobRefsTableName).
+		Where(dbx.In("fileKey", list.ToInterfaceSlice(fileKeys)...)).
+		Limit(1).
+		Row(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var released int
+
+	err = dao.RunInTransaction(func(txDao *Dao) error {
+		blobKeys, err := txDao.deleteFileBlobRefs(fileKeys)
+		if err != nil {
+			return err
+		}
+
+		released = len(blobKeys)
+
+		releasedPerBlob := make(map[string]int, len(blobKeys))
+		for _, blobKey := range blobKeys {
+			releasedPerBlob[blobKey]++
+		}
+
+		return txDao.decrementFileBlobRefs(releasedPerBlob, onLastRef)
+	})
+
+	return released, err
+}
+
+// deleteFileBlobRef deletes the fileKey blob reference row
+// and returns the key of its blob (if any).
+func (dao *Dao) deleteFileBlobRef(fileKey string) (string, error) {
+	blobKeys, err := dao.deleteFileBlobRefs([]string{fileKey})
+	if err != nil || len(blobKeys) == 0 {
+		return "", err
+	}
+
+	return blobKeys[0], nil
+}
+
+// decrementFileBlobRefs decrements the references count of the blobs
+// with the specified number of released references and calls onLastRef
+// for each blob that has no more references.
+func (dao *Dao) decrementFileBlobRefs(releasedPerBlob map[string]int, onLastRef func(blobKey string) error) error {
+	if len(releasedPerBlob) == 0 {
+		return nil
+	}
+
+	blobKeys := make([]string, 0, len(releasedPerBlob))
+	for blobKey := range releasedPerBlob {
+		blobKeys = append(blobKeys, blobKey)
+	}
+	sort.Strings(blobKeys)
+
+	// group the blobs per their released references count
+	// (usually 1) to decrement them with a single query per group
+	groups := map[int][]any{}
+	for _, blobKey := range blobKeys {
+		n := releasedPerBlob[blobKey]
+		groups[n] = append(groups[n], blobKey)
+	}
+
+	// the update locks the blob rows until the end of the transaction
+	for n, group := range groups {
+		_, err := dao.NonconcurrentDB().Update(
+			fileBlobsTableName,
+			dbx.Params{"refs": dbx.NewExp("[[refs]] - {:released}", dbx.Params{"released": n})},
+			dbx.In("blobKey", group...),
+		).Execute()
+		if err != nil {
+			return err
+		}
+	}
+
+	referenced := []string{}
+	err := dao.NonconcurrentDB().Select("blobKey").
+		From(fileBlobsTableName).
+		Where(dbx.In("blobKey", list.ToInterfaceSlice(blobKeys)...)).
+		AndWhere(dbx.NewExp("[[refs]] > 0")).
+		Column(&referenced)
+	if err != nil {
+		return err
+	}
+
+	unreferenced := []any{}
+	for _, blobKey := range blobKeys {
+		if list.ExistInSlice(blobKey, referenced) {
+			continue // still referenced
+		}
+
+		if onLastRef != nil {
+			if err := onLastRef(blobKey); err != nil {
+				return err
+			}
+		}
+
+		unreferenced = append(unreferenced, blobKey)
+	}
+
+	if len(unreferenced) == 0 {
+		return nil
+	}
+
+	_, err = dao.NonconcurrentDB().Delete(fileBlobsTableName, dbx.In("blobKey", unreferenced...)).Execute()
+
+	return err
+}
+
+// fileKeysInExp builds a raw "fileKey IN (...)" condition with its bind
+// params for the dialect specific queries that cannot use [dbx.In].
+func fileKeysInExp(fileKeys []string) (string, dbx.Params) {
+	placeholders := make([]string, len(fileKeys))
+	params := make(dbx.Params, len(fileKeys))
+
+	for i, fileKey := range fileKeys {
+		name := "fileKey" + strconv.Itoa(i)
+		placeholders[i] = "{:" + name + "}"
+		params[name] = fileKey
+	}
+
+	return "[[fileKey]] IN (" + strings.Join(placeholders, ", ") + ")", params
+}
@@ -0,0 +1,32 @@
+//go:build !mysql
+package daos
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+// incrementFileBlobRefs atomically increments the references count
+// of the blobKey blob (the blob row is created if missing).
+func (dao *Dao) incrementFileBlobRefs(blobKey string) error {
+	_, err := dao.NonconcurrentDB().NewQuery(
+		"INSERT INTO {{" + fileBlobsTableName + "}} ([[blobKey]], [[refs]]) VALUES ({:blobKey}, 1) " +
+			"ON CONFLICT ([[blobKey]]) DO UPDATE SET [[refs]] = {{" + fileBlobsTableName + "}}.[[refs]] + 1",
+	).Bind(dbx.Params{"blobKey": blobKey}).Execute()
+
+	return err
+}
+
+// deleteFileBlobRefs deletes the blob reference rows of the specified
+// file keys and returns the blob key of each deleted row
+// (the rows deleted by a concurrent release are not returned).
+func (dao *Dao) deleteFileBlobRefs(fileKeys []string) ([]string, error) {
+	where, params := fileKeysInExp(fileKeys)
+
+	blobKeys := []string{}
+
+	err := dao.NonconcurrentDB().NewQuery(
+		"DELETE FROM {{" + fileBlobRefsTableName + "}} WHERE " + where + " RETURNING [[blobKey]]",
+	).Bind(params).Column(&blobKeys)
+
+	return blobKeys, err
+}
@@ -0,0 +1,43 @@
+//go:build mysql
+package daos
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+// incrementFileBlobRefs atomically increments the references count
+// of the blobKey blob (the blob row is created if missing).
+func (dao *Dao) incrementFileBlobRefs(blobKey string) error {
+	_, err := dao.NonconcurrentDB().NewQuery(
+		"INSERT INTO {{" + fileBlobsTableName + "}} ([[blobKey]], [[refs]]) VALUES ({:blobKey}, 1) " +
+			"ON DUPLICATE KEY UPDATE [[refs]] = [[refs]] + 1",
+	).Bind(dbx.Params{"blobKey": blobKey}).Execute()
+
+	return err
+}
+
+// deleteFileBlobRefs deletes the blob reference rows of the specified
+// file keys and returns the blob key of each deleted row
+// (the rows deleted by a concurrent release are not returned).
+//
+// It is expected to be called within a transaction since the
+// reference rows are locked before their deletion.
+func (dao *Dao) deleteFileBlobRefs(fileKeys []string) ([]string, error) {
+	where, params := fileKeysInExp(fileKeys)
+
+	blobKeys := []string{}
+
+	// mysql doesn't support DELETE ... RETURNING
+	err := dao.NonconcurrentDB().NewQuery(
+		"SELECT [[blobKey]] FROM {{" + fileBlobRefsTableName + "}} WHERE " + where + " FOR UPDATE",
+	).Bind(params).Column(&blobKeys)
+	if err != nil || len(blobKeys) == 0 {
+		return blobKeys, err
+	}
+
+	_, err = dao.NonconcurrentDB().NewQuery(
+		"DELETE FROM {{" + fileBlobRefsTableName + "}} WHERE " + where,
+	).Bind(params).Execute()
+
+	return blobKeys, err
+}
@@ -0,0 +1,70 @@
+package daos_test
+
+import (
+	"testing"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/tests"
+)
+
+func TestFileBlobRefs(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	deleted := []string{}
+	onLastRef := func(blobKey string) error {
+		deleted = append(deleted, blobKey)
+		return nil
+	}
+
+	refs := map[string]string{
+		"a/1.txt": "blob1",
+		"a/2.txt": "blob1",
+		"a/3.txt": "blob2",
+	}
+	for fileKey, blobKey := range refs {
+		if err := app.Dao().AddFileBlobRef(fileKey, blobKey, onLastRef); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// re-adding the same reference is a no-op
+	if err := app.Dao().AddFileBlobRef("a/1.txt", "blob1", onLastRef); err != nil {
+		t.Fatal(err)
+	}
+
+	// replace the only blob2 reference
+	if err := app.Dao().AddFileBlobRef("a/3.txt", "blob1", onLastRef); err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 1 || deleted[0] != "blob2" {
+		t.Fatalf("Expected blob2 to be released, got %v", deleted)
+	}
+
+	if released, err := app.Dao().ReleaseFileBlobRefs([]string{"missing.txt"}, onLastRef); err != nil || released != 0 {
+		t.Fatalf("Expected missing reference to not be released, got %v (%v)", released, err)
+	}
+
+	released, err := app.Dao().ReleaseFileBlobRefs([]string{"a/1.txt", "missing.txt"}, onLastRef)
+	if err != nil || released != 1 {
+		t.Fatalf("Expected 1 released reference, got %v (%v)", released, err)
+	}
+	if len(deleted) != 1 {
+		t.Fatalf("Expected blob1 to be still referenced, got %v", deleted)
+	}
+
+	// release the remaining references of the same blob in a single batch
+	released, err = app.Dao().ReleaseFileBlobRefs([]string{"a/2.txt", "a/3.txt"}, onLastRef)
+	if err != nil || released != 2 {
+		t.Fatalf("Expected 2 released references, got %v (%v)", released, err)
+	}
+	if len(deleted) != 2 || deleted[1] != "blob1" {
+		t.Fatalf("Expected blob1 to be released, got %v", deleted)
+	}
+
+	// already released
+	if released, err := app.Dao().ReleaseFileBlobRefs([]string{"a/2.txt"}, onLastRef); err != nil || released != 0 {
+		t.Fatalf("Expected already released reference to not be released again, got %v (%v)", released, err)
+	}
+}
@@ -0,0 +1,84 @@
+package daos
+
+import (
+	"errors"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/list"
+	"github.com/pocketbase/dbx"
+)
+
+// FileMetadataQuery returns a new FileMetadata select query.
+func (dao *Dao) FileMetadataQuery() *dbx.SelectQuery {
+	return dao.ModelQuery(&models.FileMetadata{})
+}
+
+// FindFilesMetadataByRecords returns the files metadata
+// of the specified collection records.
+func (dao *Dao) FindFilesMetadataByRecords(collectionId string, recordIds ...string) ([]*models.FileMetadata, error) {
+	result := []*models.FileMetadata{}
+
+	if len(recordIds) == 0 {
+		return result, nil
+	}
+
+	err := dao.FileMetadataQuery().
+		AndWhere(dbx.HashExp{"collectionId": collectionId}).
+		AndWhere(dbx.In("recordId", list.ToInterfaceSlice(list.ToUniqueStringSlice(recordIds))...)).
+		OrderBy("created ASC").
+		All(&result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// SaveFileMetadata upserts the provided FileMetadata model
+// (the model is matched by its collection, record, field and file name).
+func (dao *Dao) SaveFileMetadata(model *models.FileMetadata) error {
+	if model.CollectionId == "" || model.RecordId == "" || model.Field == "" || model.Name == "" {
+		return errors.New("Missing required FileMetadata fields.")
+	}
+
+	if model.IsNew() {
+		existing := &models.FileMetadata{}
+
+		err := dao.FileMetadataQuery().
+			AndWhere(dbx.HashExp{
+				"collectionId": model.CollectionId,
+				"recordId":     model.RecordId,
+				"field":        model.Field,
+				"name":         model.Name,
+			}).
+			Limit(1).
+			One(existing)
+
+		if err == nil {
+			model.Id = existing.Id
+			model.Created = existing.Created
+			model.MarkAsNotNew()
+		}
+	}
+
+	return dao.Save(model)
+}
+
+// DeleteFilesMetadata deletes the files metadata of the specified record.
+//
+// If names are provided, deletes only the metadata of the matching files.
+func (dao *Dao) DeleteFilesMetadata(collectionId string, recordId string, names ...string) error {
+	expr := dbx.And(dbx.HashExp{
+		"collectionId": collectionId,
+		"recordId":     recordId,
+	})
+
+	if len(names) > 0 {
+		expr = dbx.And(expr, dbx.In("name", list.ToInterfaceSlice(names)...))
+	}
+
+	_, err := dao.NonconcurrentDB().Delete((&models.FileMetadata{}).TableName(), expr).Execute()
+
+	return err
+}
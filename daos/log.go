@@ -1,9 +1,16 @@
 package daos
 
 import (
+	"database/sql"
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/list"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/types"
 	"github.com/pocketbase/dbx"
 )
@@ -29,9 +36,47 @@ func (dao *Dao) FindLogById(id string) (*models.Log, error) {
 	return model, nil
 }
 
+// DefaultLogsStatsInterval is the default logs stats time bucket size.
+const DefaultLogsStatsInterval = time.Hour
+
+// LogsStatsOptions defines the optional logs stats aggregation settings.
+type LogsStatsOptions struct {
+	// Interval is the stats time bucket size
+	// (default to [DefaultLogsStatsInterval]).
+	Interval time.Duration
+
+	// GroupBy is an optional list of log data keys to additionally
+	// group the stats by (eg. "status", "method", "auth", "url").
+	//
+	// Nested keys could be specified with dot notation (eg. "details.code").
+	GroupBy []string
+
+	// UrlDepth limits the "url" group value to its first UrlDepth
+	// path segments (eg. "/api/collections" for UrlDepth 2).
+	//
+	// The url query string is always stripped when grouping by "url".
+	// Zero or negative value means the full url path.
+	UrlDepth int
+}
+
 type LogsStatsItem struct {
 	Total int            `db:"total" json:"total"`
 	Date  types.DateTime `db:"date" json:"date"`
+
+	// Group holds the GroupBy log data key values of the stats item
+	// (available only when LogsStatsOptions.GroupBy is set).
+	Group map[string]any `db:"-" json:"group,omitempty"`
+
+	// ExecTime holds the request logs execution time percentiles in ms
+	// (available only if at least one of the grouped logs has "execTime").
+	ExecTime *LogsStatsExecTime `db:"-" json:"execTime,omitempty"`
+}
+
+// LogsStatsExecTime defines the logs stats execution time percentiles (in ms).
+type LogsStatsExecTime struct {
+	P50 float64 `json:"p50"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
 }
 
 // LogsStats returns hourly grouped requests logs statistics.
@@ -51,6 +96,332 @@ func (dao *Dao) LogsStats(expr dbx.Expression) ([]*LogsStatsItem, error) {
 	return result, err
 }
 
+// LogsStatsWithOptions returns the logs statistics grouped
+// by the options time interval and log data keys.
+//
+// The default (empty) options fallback to [Dao.LogsStats].
+//
+// The stats are aggregated by the db for Postgres (date_trunc, percentile_cont)
+// and on the application side for the other db drivers.
+func (dao *Dao) LogsStatsWithOptions(expr dbx.Expression, options LogsStatsOptions) ([]*LogsStatsItem, error) {
+	if options.Interval <= 0 && len(options.GroupBy) == 0 {
+		return dao.LogsStats(expr)
+	}
+
+	if options.Interval <= 0 {
+		options.Interval = DefaultLogsStatsInterval
+	}
+
+	if isPostgresBuilder(dao.DB()) {
+		return dao.aggregateLogsStats(expr, options)
+	}
+
+	return dao.collectLogsStats(expr, options)
+}
+
+// logsStatsTruncUnits lists the date_trunc units of the
+// logs stats intervals that are aligned with them.
+var logsStatsTruncUnits = map[time.Duration]string{
+	time.Minute:    "minute",
+	time.Hour:      "hour",
+	24 * time.Hour: "day",
+}
+
+// logsStatsQuery builds the Postgres logs stats aggregation query.
+//
+// The selected columns are "total", "date", "g{i}" (the jsonb value
+// of the i-th GroupBy key) and the "p50", "p95", "p99" execTime percentiles.
+func logsStatsQuery(db dbx.Builder, options LogsStatsOptions) *dbx.SelectQuery {
+	var dateExpr string
+	if unit, ok := logsStatsTruncUnits[options.Interval]; ok {
+		dateExpr = "date_trunc('" + unit + "', [[created]] AT TIME ZONE 'UTC')"
+	} else {
+		seconds := strconv.FormatInt(max(int64(options.Interval/time.Second), 1), 10)
+		dateExpr = "to_timestamp(floor(extract(epoch FROM [[created]]) / " + seconds + ") * " + seconds + ") AT TIME ZONE 'UTC'"
+	}
+
+	columns := []string{"count(*) AS total", dateExpr + " AS date"}
+	groupBy := []string{"date"}
+	params := dbx.Params{}
+
+	for i, key := range options.GroupBy {
+		alias := "g" + strconv.Itoa(i)
+
+		pathParams := []string{}
+		for j, part := range strings.Split(key, ".") {
+			name := alias + "_" + strconv.Itoa(j)
+			params[name] = part
+			pathParams = append(pathParams, "{:"+name+"}")
+		}
+		path := strings.Join(pathParams, ", ")
+
+		valueExpr := "jsonb_extract_path([[data]]::jsonb, " + path + ")"
+
+		if key == "url" {
+			urlExpr := "split_part(split_part(jsonb_extract_path_text([[data]]::jsonb, " + path + "), '?', 1), '#', 1)"
+			if options.UrlDepth > 0 {
+				urlExpr = "'/' || array_to_string((string_to_array(regexp_replace(" + urlExpr + ", '^/', ''), '/'))[1:" + strconv.Itoa(options.UrlDepth) + "], '/')"
+			}
+			valueExpr = "CASE WHEN jsonb_typeof(" + valueExpr + ") = 'string' THEN to_jsonb(" + urlExpr + ") ELSE " + valueExpr + " END"
+		}
+
+		columns = append(columns, valueExpr+" AS "+alias)
+		groupBy = append(groupBy, alias)
+	}
+
+	execTimeExpr := "CASE WHEN json_typeof([[data]]->'execTime') = 'number' THEN ([[data]]->>'execTime')::float8 END"
+	for _, p := range []string{"50", "95", "99"} {
+		columns = append(columns, "percentile_cont(0."+p+") WITHIN GROUP (ORDER BY "+execTimeExpr+") AS p"+p)
+	}
+
+	return db.Select(columns...).
+		From((&models.Log{}).TableName()).
+		GroupBy(groupBy...).
+		Bind(params)
+}
+
+// aggregateLogsStats returns the logs stats aggregated by the (Postgres) db.
+func (dao *Dao) aggregateLogsStats(expr dbx.Expression, options LogsStatsOptions) ([]*LogsStatsItem, error) {
+	query := logsStatsQuery(dao.DB(), options)
+
+	if expr != nil {
+		query.AndWhere(expr)
+	}
+
+	rows, err := query.Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []*LogsStatsItem{}
+
+	for rows.Next() {
+		var date time.Time
+		var p50, p95, p99 sql.NullFloat64
+		rawGroup := make([]sql.NullString, len(options.GroupBy))
+
+		item := &LogsStatsItem{}
+
+		dest := []any{&item.Total, &date}
+		for i := range rawGroup {
+			dest = append(dest, &rawGroup[i])
+		}
+		dest = append(dest, &p50, &p95, &p99)
+
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		item.Date, _ = types.ParseDateTime(date)
+
+		if len(options.GroupBy) > 0 {
+			item.Group = make(map[string]any, len(options.GroupBy))
+			for i, key := range options.GroupBy {
+				var value any
+				if rawGroup[i].Valid {
+					if err := json.Unmarshal([]byte(rawGroup[i].String), &value); err != nil {
+						return nil, err
+					}
+				}
+				item.Group[key] = value
+			}
+		}
+
+		if p50.Valid {
+			item.ExecTime = &LogsStatsExecTime{
+				P50: roundPercentile(p50.Float64),
+				P95: roundPercentile(p95.Float64),
+				P99: roundPercentile(p99.Float64),
+			}
+		}
+
+		result = append(result, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return logsStatsSortKey(result[i].Date.Time(), result[i].Group) < logsStatsSortKey(result[j].Date.Time(), result[j].Group)
+	})
+
+	return result, nil
+}
+
+// collectLogsStats returns the logs stats aggregated on the application side.
+func (dao *Dao) collectLogsStats(expr dbx.Expression, options LogsStatsOptions) ([]*LogsStatsItem, error) {
+	query := dao.LogQuery().Select("created", "data")
+
+	if expr != nil {
+		query.AndWhere(expr)
+	}
+
+	rows, err := query.Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type bucket struct {
+		item      *LogsStatsItem
+		sortKey   string
+		execTimes []float64
+	}
+
+	buckets := map[string]*bucket{}
+
+	for rows.Next() {
+		log := &models.Log{}
+		if err := rows.ScanStruct(log); err != nil {
+			return nil, err
+		}
+
+		date := log.Created.Time().UTC().Truncate(options.Interval)
+
+		var group map[string]any
+		if len(options.GroupBy) > 0 {
+			group = make(map[string]any, len(options.GroupBy))
+			for _, key := range options.GroupBy {
+				group[key] = logsStatsGroupValue(log.Data, key, options.UrlDepth)
+			}
+		}
+
+		sortKey := logsStatsSortKey(date, group)
+
+		b, ok := buckets[sortKey]
+		if !ok {
+			dt, _ := types.ParseDateTime(date)
+			b = &bucket{
+				item:    &LogsStatsItem{Date: dt, Group: group},
+				sortKey: sortKey,
+			}
+			buckets[sortKey] = b
+		}
+
+		b.item.Total++
+
+		if execTime, ok := log.Data["execTime"].(float64); ok {
+			b.execTimes = append(b.execTimes, execTime)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sorted := make([]*bucket, 0, len(buckets))
+	for _, b := range buckets {
+		sorted = append(sorted, b)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].sortKey < sorted[j].sortKey
+	})
+
+	result := make([]*LogsStatsItem, len(sorted))
+
+	for i, b := range sorted {
+		if len(b.execTimes) > 0 {
+			sort.Float64s(b.execTimes)
+
+			b.item.ExecTime = &LogsStatsExecTime{
+				P50: percentile(b.execTimes, 50),
+				P95: percentile(b.execTimes, 95),
+				P99: percentile(b.execTimes, 99),
+			}
+		}
+
+		result[i] = b.item
+	}
+
+	return result, nil
+}
+
+// logsStatsSortKey returns the stats item sort key
+// (ordered by date and then by the group values).
+func logsStatsSortKey(date time.Time, group map[string]any) string {
+	// note: json.Marshal sorts the map keys
+	encodedGroup, _ := json.Marshal(group)
+
+	return date.UTC().Format(time.RFC3339Nano) + string(encodedGroup)
+}
+
+// isPostgresBuilder reports whether the provided db builder is for a Postgres db.
+func isPostgresBuilder(db dbx.Builder) bool {
+	switch v := db.(type) {
+	case *dbx.DB:
+		db = v.Builder
+	case *dbx.Tx:
+		db = v.Builder
+	}
+
+	_, ok := db.(*dbx.PgsqlBuilder)
+
+	return ok
+}
+
+// logsStatsGroupValue returns the value of the dot separated key path
+// from the provided log data (or nil if missing).
+func logsStatsGroupValue(data types.JsonMap, key string, urlDepth int) any {
+	var value any = map[string]any(data)
+
+	for _, part := range strings.Split(key, ".") {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = m[part]
+	}
+
+	if key == "url" {
+		if url, ok := value.(string); ok {
+			return logsStatsUrlPrefix(url, urlDepth)
+		}
+	}
+
+	return value
+}
+
+// logsStatsUrlPrefix strips the query string of the provided url
+// and returns its first depth path segments (if depth > 0).
+func logsStatsUrlPrefix(url string, depth int) string {
+	if i := strings.IndexAny(url, "?#"); i >= 0 {
+		url = url[:i]
+	}
+
+	if depth <= 0 {
+		return url
+	}
+
+	segments := strings.Split(strings.TrimPrefix(url, "/"), "/")
+	if len(segments) > depth {
+		segments = segments[:depth]
+	}
+
+	return "/" + strings.Join(segments, "/")
+}
+
+// percentile returns the p-th percentile of the sorted values
+// using linear interpolation between the closest ranks
+// (rounded to 3 decimal places).
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+
+	return roundPercentile(sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower)))
+}
+
+// roundPercentile rounds the provided percentile value to 3 decimal places.
+func roundPercentile(value float64) float64 {
+	return math.Round(value*1000) / 1000
+}
+
 // DeleteOldLogs delete all requests that are created before createdBefore.
 func (dao *Dao) DeleteOldLogs(createdBefore time.Time) error {
 	formattedDate := createdBefore.UTC().Format(types.DefaultDateLayout)
@@ -61,6 +432,27 @@ func (dao *Dao) DeleteOldLogs(createdBefore time.Time) error {
 	return err
 }
 
+// deleteLogsByIdsBatchSize is the max number of ids per DeleteLogsByIds delete statement.
+const deleteLogsByIdsBatchSize = 500
+
+// DeleteLogsByIds deletes the logs with the provided ids
+// (in batches to avoid exceeding the statement params limit).
+func (dao *Dao) DeleteLogsByIds(ids []string) error {
+	for i := 0; i < len(ids); i += deleteLogsByIdsBatchSize {
+		batch := ids[i:min(i+deleteLogsByIdsBatchSize, len(ids))]
+
+		_, err := dao.NonconcurrentDB().Delete(
+			(&models.Log{}).TableName(),
+			dbx.In("id", list.ToInterfaceSlice(batch)...),
+		).Execute()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // SaveLog upserts the provided Log model.
 func (dao *Dao) SaveLog(log *models.Log) error {
 	return dao.Save(log)
@@ -0,0 +1,78 @@
+package daos
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+)
+
+func TestLogsStatsQuery(t *testing.T) {
+	t.Parallel()
+
+	db := dbx.NewFromDB(nil, "postgres")
+
+	scenarios := []struct {
+		name           string
+		options        LogsStatsOptions
+		expectedParts  []string
+		expectedParams dbx.Params
+	}{
+		{
+			"date_trunc aligned interval",
+			LogsStatsOptions{Interval: time.Hour},
+			[]string{
+				`date_trunc('hour', [[created]] AT TIME ZONE 'UTC') AS "date"`,
+				`percentile_cont(0.50) WITHIN GROUP (ORDER BY CASE WHEN json_typeof([[data]]->'execTime') = 'number' THEN ([[data]]->>'execTime')::float8 END) AS "p50"`,
+				`percentile_cont(0.99) WITHIN GROUP`,
+				`GROUP BY "date"`,
+			},
+			dbx.Params{},
+		},
+		{
+			"custom interval with nested and url group keys",
+			LogsStatsOptions{Interval: 30 * time.Minute, GroupBy: []string{"details.code", "url"}, UrlDepth: 2},
+			[]string{
+				`to_timestamp(floor(extract(epoch FROM [[created]]) / 1800) * 1800) AT TIME ZONE 'UTC' AS "date"`,
+				`jsonb_extract_path([[data]]::jsonb, {:g0_0}, {:g0_1}) AS "g0"`,
+				`[1:2], '/')) ELSE jsonb_extract_path([[data]]::jsonb, {:g1_0}) END AS "g1"`,
+				`GROUP BY "date", "g0", "g1"`,
+			},
+			dbx.Params{"g0_0": "details", "g0_1": "code", "g1_0": "url"},
+		},
+	}
+
+	for _, s := range scenarios {
+		query := logsStatsQuery(db, s.options).Build()
+
+		sql := query.SQL()
+		for _, part := range s.expectedParts {
+			if !strings.Contains(sql, part) {
+				t.Errorf("[%s] Expected %s\nin\n%s", s.name, part, sql)
+			}
+		}
+
+		params := query.Params()
+		if len(params) != len(s.expectedParams) {
+			t.Errorf("[%s] Expected params %v, got %v", s.name, s.expectedParams, params)
+		}
+		for k, v := range s.expectedParams {
+			if params[k] != v {
+				t.Errorf("[%s] Expected param %s %v, got %v", s.name, k, v, params[k])
+			}
+		}
+	}
+}
+
+func TestIsPostgresBuilder(t *testing.T) {
+	t.Parallel()
+
+	if !isPostgresBuilder(dbx.NewFromDB(nil, "postgres")) {
+		t.Fatal("Expected postgres builder")
+	}
+
+	if isPostgresBuilder(dbx.NewFromDB(nil, "sqlite")) {
+		t.Fatal("Didn't expect postgres builder")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/AlperRehaYAZGAN/postgresbase/daos"
 	"github.com/AlperRehaYAZGAN/postgresbase/models"
 	"github.com/AlperRehaYAZGAN/postgresbase/tests"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/types"
@@ -80,6 +81,94 @@ func TestLogsStats(t *testing.T) {
 	}
 }
 
+func TestLogsStatsWithOptions(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	if _, err := app.LogsDB().Delete("_logs", nil).Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	mockLogs := []struct {
+		created string
+		data    types.JsonMap
+	}{
+		{"2022-05-01 10:10:00.000Z", types.JsonMap{"status": 200, "method": "GET", "url": "/api/collections/a/records?page=1", "execTime": 1}},
+		{"2022-05-01 10:20:00.000Z", types.JsonMap{"status": 200, "method": "GET", "url": "/api/collections/b/records", "execTime": 2}},
+		{"2022-05-01 10:30:00.000Z", types.JsonMap{"status": 200, "method": "GET", "url": "/api/collections/c/records", "execTime": 3}},
+		{"2022-05-01 10:40:00.000Z", types.JsonMap{"status": 400, "method": "POST", "url": "/api/admins", "execTime": 4}},
+		{"2022-05-01 12:10:00.000Z", types.JsonMap{"status": 200, "method": "GET", "url": "/api/collections/a/records", "execTime": 5}},
+		{"2022-05-01 12:20:00.000Z", types.JsonMap{"type": "custom"}},
+	}
+
+	for _, ml := range mockLogs {
+		log := &models.Log{Data: ml.data}
+		log.Created, _ = types.ParseDateTime(ml.created)
+		if err := app.LogsDao().SaveLog(log); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scenarios := []struct {
+		name     string
+		expr     dbx.Expression
+		options  daos.LogsStatsOptions
+		expected string
+	}{
+		{
+			"default (LogsStats)",
+			dbx.NewExp("[[created]] < {:date}", dbx.Params{"date": "2022-05-01 10:25:00.000Z"}),
+			daos.LogsStatsOptions{},
+			`[{"total":1,"date":"2022-05-01 10:10:00.000"},{"total":1,"date":"2022-05-01 10:20:00.000"}]`,
+		},
+		{
+			"hourly",
+			nil,
+			daos.LogsStatsOptions{Interval: time.Hour},
+			`[{"total":4,"date":"2022-05-01 10:00:00.000","execTime":{"p50":2.5,"p95":3.85,"p99":3.97}},` +
+				`{"total":2,"date":"2022-05-01 12:00:00.000","execTime":{"p50":5,"p95":5,"p99":5}}]`,
+		},
+		{
+			"daily with filter",
+			dbx.NewExp("[[created]] < {:date}", dbx.Params{"date": "2022-05-01 12:00:00.000Z"}),
+			daos.LogsStatsOptions{Interval: 24 * time.Hour},
+			`[{"total":4,"date":"2022-05-01 00:00:00.000","execTime":{"p50":2.5,"p95":3.85,"p99":3.97}}]`,
+		},
+		{
+			"group by status and method",
+			nil,
+			daos.LogsStatsOptions{Interval: 24 * time.Hour, GroupBy: []string{"status", "method"}},
+			`[{"total":4,"date":"2022-05-01 00:00:00.000","group":{"method":"GET","status":200},"execTime":{"p50":2.5,"p95":4.7,"p99":4.94}},` +
+				`{"total":1,"date":"2022-05-01 00:00:00.000","group":{"method":"POST","status":400},"execTime":{"p50":4,"p95":4,"p99":4}},` +
+				`{"total":1,"date":"2022-05-01 00:00:00.000","group":{"method":null,"status":null}}]`,
+		},
+		{
+			"group by url prefix",
+			dbx.NewExp("[[created]] < {:date}", dbx.Params{"date": "2022-05-01 12:15:00.000Z"}),
+			daos.LogsStatsOptions{Interval: 24 * time.Hour, GroupBy: []string{"url"}, UrlDepth: 3},
+			`[{"total":1,"date":"2022-05-01 00:00:00.000","group":{"url":"/api/admins"},"execTime":{"p50":4,"p95":4,"p99":4}},` +
+				`{"total":2,"date":"2022-05-01 00:00:00.000","group":{"url":"/api/collections/a"},"execTime":{"p50":3,"p95":4.8,"p99":4.96}},` +
+				`{"total":1,"date":"2022-05-01 00:00:00.000","group":{"url":"/api/collections/b"},"execTime":{"p50":2,"p95":2,"p99":2}},` +
+				`{"total":1,"date":"2022-05-01 00:00:00.000","group":{"url":"/api/collections/c"},"execTime":{"p50":3,"p95":3,"p99":3}}]`,
+		},
+	}
+
+	for _, s := range scenarios {
+		result, err := app.LogsDao().LogsStatsWithOptions(s.expr, s.options)
+		if err != nil {
+			t.Errorf("[%s] %v", s.name, err)
+			continue
+		}
+
+		encoded, _ := json.Marshal(result)
+		if string(encoded) != s.expected {
+			t.Errorf("[%s] Expected \n%s, \ngot \n%s", s.name, s.expected, encoded)
+		}
+	}
+}
+
 func TestDeleteOldLogs(t *testing.T) {
 	t.Parallel()
 
@@ -122,6 +211,37 @@ func TestDeleteOldLogs(t *testing.T) {
 	}
 }
 
+func TestDeleteLogsByIds(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	tests.MockLogsData(app)
+
+	if err := app.LogsDao().DeleteLogsByIds(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := []string{"missing", "873f2133-9f38-44fb-bf82-c8f53b310d91"}
+	if err := app.LogsDao().DeleteLogsByIds(ids); err != nil {
+		t.Fatal(err)
+	}
+
+	var total int
+	if err := app.LogsDao().LogQuery().Select("count(*)").Row(&total); err != nil {
+		t.Fatal(err)
+	}
+
+	if total != 1 {
+		t.Fatalf("Expected 1 remaining log, got %d", total)
+	}
+
+	if _, err := app.LogsDao().FindLogById(ids[1]); err == nil {
+		t.Fatalf("Expected log %q to be deleted", ids[1])
+	}
+}
+
 func TestSaveLog(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,115 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/types"
+	"github.com/pocketbase/dbx"
+)
+
+// OTPQuery returns a new OTP select query.
+func (dao *Dao) OTPQuery() *dbx.SelectQuery {
+	return dao.ModelQuery(&models.OTP{})
+}
+
+// FindOTPById finds a single OTP model by its id.
+func (dao *Dao) FindOTPById(id string) (*models.OTP, error) {
+	model := &models.OTP{}
+
+	err := dao.OTPQuery().
+		AndWhere(dbx.HashExp{"id": id}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// FindRecordOTP finds the last created OTP of the specified auth record.
+func (dao *Dao) FindRecordOTP(collectionId string, recordId string) (*models.OTP, error) {
+	model := &models.OTP{}
+
+	err := dao.OTPQuery().
+		AndWhere(dbx.HashExp{
+			"collectionId": collectionId,
+			"recordId":     recordId,
+		}).
+		OrderBy("created DESC").
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// SaveOTP upserts the provided OTP model.
+func (dao *Dao) SaveOTP(otp *models.OTP) error {
+	return dao.Save(otp)
+}
+
+// DeleteOTP deletes the provided OTP model.
+func (dao *Dao) DeleteOTP(otp *models.OTP) error {
+	return dao.Delete(otp)
+}
+
+// ConsumeOTPAttempt atomically increments the attempts counter of
+// the provided OTP model.
+//
+// Returns false if the OTP has already reached [models.OTPMaxAttempts]
+// (or no longer exists), aka. when it can't be used for verification anymore.
+func (dao *Dao) ConsumeOTPAttempt(otp *models.OTP) (bool, error) {
+	result, err := dao.NonconcurrentDB().Update(
+		otp.TableName(),
+		dbx.Params{"attempts": dbx.NewExp("[[attempts]] + 1")},
+		dbx.And(
+			dbx.HashExp{"id": otp.Id},
+			dbx.NewExp("[[attempts]] < {:max}", dbx.Params{"max": models.OTPMaxAttempts}),
+		),
+	).Execute()
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if affected == 0 {
+		return false, nil
+	}
+
+	otp.Attempts++
+
+	return true, nil
+}
+
+// DeleteRecordOTPs deletes all OTPs of the specified auth record.
+func (dao *Dao) DeleteRecordOTPs(collectionId string, recordId string) error {
+	_, err := dao.NonconcurrentDB().Delete((&models.OTP{}).TableName(), dbx.HashExp{
+		"collectionId": collectionId,
+		"recordId":     recordId,
+	}).Execute()
+
+	return err
+}
+
+// DeleteExpiredOTPs deletes the OTPs of the specified collection
+// that were created before createdBefore.
+func (dao *Dao) DeleteExpiredOTPs(collectionId string, createdBefore time.Time) error {
+	formattedDate := createdBefore.UTC().Format(types.DefaultDateLayout)
+
+	_, err := dao.NonconcurrentDB().Delete((&models.OTP{}).TableName(), dbx.And(
+		dbx.HashExp{"collectionId": collectionId},
+		dbx.NewExp("[[created]] < {:date}", dbx.Params{"date": formattedDate}),
+	)).Execute()
+
+	return err
+}
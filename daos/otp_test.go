@@ -0,0 +1,189 @@
+package daos_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/tests"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/types"
+)
+
+func createTestOTP(t *testing.T, app *tests.TestApp, record *models.Record, created types.DateTime) *models.OTP {
+	otp := &models.OTP{
+		CollectionId: record.Collection().Id,
+		RecordId:     record.Id,
+	}
+	otp.Created = created
+
+	if err := otp.SetPassword("123456"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Dao().SaveOTP(otp); err != nil {
+		t.Fatal(err)
+	}
+
+	return otp
+}
+
+func TestOTPQuery(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	expected := "SELECT {{_otps}}.* FROM `_otps`"
+
+	sql := app.Dao().OTPQuery().Build().SQL()
+	if sql != expected {
+		t.Errorf("Expected sql %s, got %s", expected, sql)
+	}
+}
+
+func TestFindRecordOTP(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record1, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record2, err := app.Dao().FindAuthRecordByEmail("users", "test2@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otp := createTestOTP(t, app, record1, types.NowDateTime())
+
+	found, err := app.Dao().FindRecordOTP(record1.Collection().Id, record1.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Id != otp.Id {
+		t.Fatalf("Expected OTP %q, got %q", otp.Id, found.Id)
+	}
+
+	if _, err := app.Dao().FindRecordOTP(record2.Collection().Id, record2.Id); err == nil {
+		t.Fatal("Expected error for record without OTP")
+	}
+
+	// only one OTP per auth record is allowed
+	duplicate := &models.OTP{CollectionId: record1.Collection().Id, RecordId: record1.Id}
+	duplicate.SetPassword("123456")
+	if err := app.Dao().SaveOTP(duplicate); err == nil {
+		t.Fatal("Expected duplicated record OTP error")
+	}
+}
+
+func TestConsumeOTPAttempt(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otp := createTestOTP(t, app, record, types.NowDateTime())
+
+	for i := 1; i <= models.OTPMaxAttempts; i++ {
+		ok, err := app.Dao().ConsumeOTPAttempt(otp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatalf("(%d) Expected the attempt to be consumed", i)
+		}
+		if otp.Attempts != i {
+			t.Fatalf("(%d) Expected %d attempts, got %d", i, i, otp.Attempts)
+		}
+	}
+
+	ok, err := app.Dao().ConsumeOTPAttempt(otp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("Expected the max attempts limit to be reached")
+	}
+
+	saved, err := app.Dao().FindOTPById(otp.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Attempts != models.OTPMaxAttempts {
+		t.Fatalf("Expected %d stored attempts, got %d", models.OTPMaxAttempts, saved.Attempts)
+	}
+}
+
+func TestDeleteExpiredOTPs(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record1, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record2, err := app.Dao().FindAuthRecordByEmail("users", "test2@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old, _ := types.ParseDateTime(time.Now().Add(-1 * time.Hour))
+
+	oldOTP := createTestOTP(t, app, record1, old)
+	newOTP := createTestOTP(t, app, record2, types.NowDateTime())
+
+	if err := app.Dao().DeleteExpiredOTPs(record1.Collection().Id, time.Now().Add(-10*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.Dao().FindOTPById(oldOTP.Id); err == nil {
+		t.Fatal("Expected the old OTP to be deleted")
+	}
+
+	if _, err := app.Dao().FindOTPById(newOTP.Id); err != nil {
+		t.Fatalf("Expected the new OTP to remain, got %v", err)
+	}
+}
+
+func TestDeleteRecordOTPs(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record1, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record2, err := app.Dao().FindAuthRecordByEmail("users", "test2@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otp1 := createTestOTP(t, app, record1, types.NowDateTime())
+	otp2 := createTestOTP(t, app, record2, types.NowDateTime())
+
+	if err := app.Dao().DeleteRecord(record1); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.Dao().FindOTPById(otp1.Id); err == nil {
+		t.Fatal("Expected the deleted record OTP to be deleted")
+	}
+
+	if _, err := app.Dao().FindOTPById(otp2.Id); err != nil {
+		t.Fatalf("Expected the other record OTP to remain, got %v", err)
+	}
+}
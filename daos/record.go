@@ -583,6 +583,8 @@ func (dao *Dao) CanAccessRecord(record *models.Record, requestInfo *models.Reque
 //
 // If record.IsNew() is true, the method will perform a create, otherwise an update.
 // To explicitly mark a record for update you can use record.MarkAsNotNew().
+//
+// Changing the email of an auth record deletes its pending one-time passwords.
 func (dao *Dao) SaveRecord(record *models.Record) error {
 	if record.Collection().IsAuth() {
 		if record.Username() == "" {
@@ -604,6 +606,17 @@ func (dao *Dao) SaveRecord(record *models.Record) error {
 				return errors.New("the auth record ID must be unique across all auth collections")
 			}
 		}
+
+		// invalidate the one-time passwords sent to the old email
+		if !record.IsNew() && record.OriginalCopy().Email() != record.Email() {
+			return dao.RunInTransaction(func(txDao *Dao) error {
+				if err := txDao.Save(record); err != nil {
+					return err
+				}
+
+				return txDao.DeleteRecordOTPs(record.Collection().Id, record.Id)
+			})
+		}
 	}
 
 	return dao.Save(record)
@@ -627,8 +640,8 @@ func (dao *Dao) DeleteRecord(record *models.Record) error {
 	}
 
 	return dao.RunInTransaction(func(txDao *Dao) error {
-		// manually trigger delete on any linked external auth to ensure
-		// that the `OnModel*` hooks are triggered
+		// manually trigger delete on any linked external auth and
+		// webauthn credential to ensure that the `OnModel*` hooks are triggered
 		if record.Collection().IsAuth() {
 			// note: the select is outside of the transaction to minimize
 			// SQLITE_BUSY errors when mixing read&write in a single transaction
@@ -641,6 +654,16 @@ func (dao *Dao) DeleteRecord(record *models.Record) error {
 					return err
 				}
 			}
+
+			credentials, err := dao.FindAllWebAuthnCredentialsByRecord(record)
+			if err != nil {
+				return err
+			}
+			for _, credential := range credentials {
+				if err := txDao.DeleteWebAuthnCredential(credential); err != nil {
+					return err
+				}
+			}
 		}
 
 		// delete the record before the relation references to ensure that there
@@ -649,6 +672,23 @@ func (dao *Dao) DeleteRecord(record *models.Record) error {
 			return err
 		}
 
+		// delete the auth record one-time passwords (if any)
+		if record.Collection().IsAuth() {
+			if err := txDao.DeleteRecordOTPs(record.Collection().Id, record.Id); err != nil {
+				return err
+			}
+		}
+
+		// delete the record files metadata (if any)
+		for _, field := range record.Collection().Schema.Fields() {
+			if field.Type == schema.FieldTypeFile {
+				if err := txDao.DeleteFilesMetadata(record.Collection().Id, record.Id); err != nil {
+					return err
+				}
+				break
+			}
+		}
+
 		return txDao.cascadeRecordDelete(record, refs)
 	})
 }
@@ -77,6 +77,10 @@ func (dao *Dao) SyncRecordTableSchema(newCollection *models.Collection, oldColle
 				}
 			}
 
+			if err := txDao.syncTenantPolicy(newCollection, nil); err != nil {
+				return err
+			}
+
 			return txDao.createCollectionIndexes(newCollection)
 		}
 
@@ -161,6 +165,10 @@ func (dao *Dao) SyncRecordTableSchema(newCollection *models.Collection, oldColle
 			return err
 		}
 
+		if err := txDao.syncTenantPolicy(newCollection, oldCollection); err != nil {
+			return err
+		}
+
 		return txDao.createCollectionIndexes(newCollection)
 	})
 }
@@ -285,8 +293,8 @@ func (dao *Dao) normalizeSingleVsMultipleFieldChanges(newCollection, oldCollecti
 }
 
 func (dao *Dao) dropCollectionIndex(collection *models.Collection) error {
-	if collection.IsView() {
-		return nil // views don't have indexes
+	if collection.IsView() && !collection.IsMaterializedView() {
+		return nil // regular views don't have indexes
 	}
 
 	return dao.RunInTransaction(func(txDao *Dao) error {
@@ -307,8 +315,8 @@ func (dao *Dao) dropCollectionIndex(collection *models.Collection) error {
 }
 
 func (dao *Dao) createCollectionIndexes(collection *models.Collection) error {
-	if collection.IsView() {
-		return nil // views don't have indexes
+	if collection.IsView() && !collection.IsMaterializedView() {
+		return nil // regular views don't have indexes
 	}
 
 	return dao.RunInTransaction(func(txDao *Dao) error {
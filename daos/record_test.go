@@ -1102,6 +1102,46 @@ func TestSaveRecord(t *testing.T) {
 	}
 }
 
+func TestSaveRecordEmailChangeDeletesOTPs(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otp := &models.OTP{
+		CollectionId: record.Collection().Id,
+		RecordId:     record.Id,
+		Email:        record.Email(),
+	}
+	otp.SetPassword("123456")
+	if err := app.Dao().SaveOTP(otp); err != nil {
+		t.Fatal(err)
+	}
+
+	// update without email change
+	record.Set("name", "test_update")
+	if err := app.Dao().SaveRecord(record); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.Dao().FindOTPById(otp.Id); err != nil {
+		t.Fatalf("Expected the OTP to remain, got %v", err)
+	}
+
+	// update with email change
+	record.SetEmail("test_new@example.com")
+	if err := app.Dao().SaveRecord(record); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.Dao().FindOTPById(otp.Id); err == nil {
+		t.Fatal("Expected the OTP to be deleted")
+	}
+}
+
 func TestSaveRecordWithIdFromOtherCollection(t *testing.T) {
 	t.Parallel()
 
@@ -1151,9 +1191,10 @@ func TestDeleteRecord(t *testing.T) {
 		t.Fatal("(rec0) Didn't expect to succeed deleting unsaved record")
 	}
 
-	// delete existing record + external auths
+	// delete existing record + external auths + webauthn credentials
 	// ---
 	rec1, _ := app.Dao().FindRecordById("users", "4q1xlclmfloku33")
+	createTestWebAuthnCredential(t, app, rec1, "test_credential")
 	if err := app.Dao().DeleteRecord(rec1); err != nil {
 		t.Fatalf("(rec1) Expected nil, got error %v", err)
 	}
@@ -1165,6 +1206,10 @@ func TestDeleteRecord(t *testing.T) {
 	if auths, _ := app.Dao().FindAllExternalAuthsByRecord(rec1); len(auths) > 0 {
 		t.Fatalf("(rec1) Expected external auths to be deleted, got %v", auths)
 	}
+	// check if the webauthn credentials were deleted
+	if credentials, _ := app.Dao().FindAllWebAuthnCredentialsByRecord(rec1); len(credentials) > 0 {
+		t.Fatalf("(rec1) Expected webauthn credentials to be deleted, got %v", credentials)
+	}
 
 	// delete existing record while being part of a non-cascade required relation
 	// ---
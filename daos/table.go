@@ -21,12 +21,11 @@ func (dao *Dao) HasTable(tableName string) bool {
 
 	// postgres version
 	// !CHANGED: fetch table information from information_schema
-	err := dao.DB().Select("count(*)").
-		From("information_schema.tables").
-		AndWhere(dbx.HashExp{"table_type": []any{"BASE TABLE", "VIEW"}}).
-		AndWhere(dbx.NewExp("LOWER([[table_name]])=LOWER({:tableName})", dbx.Params{"tableName": tableName})).
-		Limit(1).
-		Row(&exists)
+	// (the materialized views are checked as part of the same query since they are not in the information_schema)
+	err := dao.DB().NewQuery(
+		"SELECT EXISTS (SELECT 1 FROM information_schema.tables WHERE [[table_type]] IN ('BASE TABLE', 'VIEW') AND LOWER([[table_name]])=LOWER({:tableName})" +
+			materializedViewsExistsUnion + ")",
+	).Bind(dbx.Params{"tableName": tableName}).Row(&exists)
 
 	return err == nil && exists
 }
@@ -17,6 +17,10 @@ func TestHasTable(t *testing.T) {
 	app, _ := tests.NewTestApp()
 	defer app.Cleanup()
 
+	if err := app.Dao().SaveMaterializedView("matview1", "SELECT 1 as id"); err != nil {
+		t.Fatal(err)
+	}
+
 	scenarios := []struct {
 		tableName string
 		expected  bool
@@ -25,8 +29,9 @@ func TestHasTable(t *testing.T) {
 		{"test", false},
 		{"_admins", true},
 		{"demo3", true},
-		{"DEMO3", true}, // table names are case insensitives by default
-		{"view1", true}, // view
+		{"DEMO3", true},    // table names are case insensitives by default
+		{"view1", true},    // view
+		{"MATVIEW1", true}, // materialized view
 	}
 
 	for i, scenario := range scenarios {
@@ -0,0 +1,223 @@
+//go:build !mysql
+
+package daos
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/models/schema"
+	"github.com/pocketbase/dbx"
+)
+
+// TenantSettingName is the name of the Postgres run-time parameter
+// with the current transaction tenant used by the multi-tenant
+// collections row-level security policies.
+const TenantSettingName = "app.tenant"
+
+// TenantBypassSettingName is the name of the Postgres run-time parameter
+// that, when set to "on", disables the multi-tenant collections
+// row-level security policies for the current transaction.
+const TenantBypassSettingName = "app.tenant_bypass"
+
+const tenantPolicyName = "_pb_tenant_isolation_"
+
+// WithTenant returns a new Dao which transactions are bound to the
+// provided tenant (aka. SET LOCAL app.tenant) and therefore restricted
+// to the tenant records of the multi-tenant collections.
+//
+// Note that the tenant is applied only to the transactions started with
+// the returned Dao (see [Dao.RunInTenantScope]). All other queries are
+// executed without tenant and don't match any multi-tenant collection rows.
+//
+// The transactions are started from the concurrent db pool so that
+// the tenant requests don't block the other app write operations.
+func (dao *Dao) WithTenant(tenant string) *Dao {
+	clone := dao.Clone()
+	clone.nonconcurrentDB = dao.concurrentDB
+	clone.tenant = tenant
+	clone.tenantBypass = false
+
+	return clone
+}
+
+// WithoutTenantIsolation returns a new Dao which transactions have
+// explicitly disabled multi-tenant collections row-level security policies
+// (eg. for admins, cron jobs and other system operations).
+//
+// Similar to [Dao.WithTenant], the bypass is applied only to the
+// transactions started with the returned Dao.
+func (dao *Dao) WithoutTenantIsolation() *Dao {
+	clone := dao.Clone()
+	clone.nonconcurrentDB = dao.concurrentDB
+	clone.tenant = ""
+	clone.tenantBypass = true
+
+	return clone
+}
+
+// WithTenantScopeOf returns a new Dao with the same tenant scope
+// as the provided scopeDao (or the current Dao if scopeDao doesn't have one).
+//
+// This is usually helpful when a new Dao needs to be created
+// from within a tenant transaction (eg. for dry submits).
+func (dao *Dao) WithTenantScopeOf(scopeDao *Dao) *Dao {
+	switch {
+	case scopeDao.tenantBypass:
+		return dao.WithoutTenantIsolation()
+	case scopeDao.tenant != "":
+		return dao.WithTenant(scopeDao.tenant)
+	default:
+		return dao
+	}
+}
+
+// HasTenantScope reports whether the current Dao is bound to a tenant
+// or has disabled tenant isolation (see [Dao.WithTenant] and [Dao.WithoutTenantIsolation]).
+func (dao *Dao) HasTenantScope() bool {
+	return dao.tenant != "" || dao.tenantBypass
+}
+
+// RunInTenantScope runs fn in a new transaction with the current Dao
+// tenant scope or directly with the current Dao if it doesn't have one.
+func (dao *Dao) RunInTenantScope(fn func(scopedDao *Dao) error) error {
+	if !dao.HasTenantScope() {
+		return fn(dao)
+	}
+
+	return dao.RunInTransaction(fn)
+}
+
+// RunInTenantTransaction runs fn in a new transaction bound to the provided
+// tenant and restricts all queries within the transaction to the tenant
+// records of the multi-tenant collections (see [Dao.WithTenant]).
+func (dao *Dao) RunInTenantTransaction(tenant string, fn func(txDao *Dao) error) error {
+	if tenant == "" {
+		return errors.New("missing tenant")
+	}
+
+	return dao.WithTenant(tenant).RunInTransaction(fn)
+}
+
+// RunWithoutTenantIsolation runs fn in a new transaction with disabled
+// multi-tenant collections row-level security policies (see [Dao.WithoutTenantIsolation]).
+func (dao *Dao) RunWithoutTenantIsolation(fn func(txDao *Dao) error) error {
+	return dao.WithoutTenantIsolation().RunInTransaction(fn)
+}
+
+// DisableTenantIsolation disables the multi-tenant collections row-level
+// security policies for the current db transaction
+// (eg. used as migrations runner transaction initializer).
+func DisableTenantIsolation(tx *dbx.Tx) error {
+	_, err := tx.NewQuery("SELECT set_config({:name}, 'on', true)").
+		Bind(dbx.Params{"name": TenantBypassSettingName}).
+		Execute()
+
+	return err
+}
+
+// applyTenantScope sets the tenant run-time parameters of the
+// current transaction Dao (if it has a tenant scope).
+func (dao *Dao) applyTenantScope() error {
+	if !dao.HasTenantScope() {
+		return nil
+	}
+
+	bypass := "off"
+	if dao.tenantBypass {
+		bypass = "on"
+	}
+
+	_, err := dao.DB().NewQuery("SELECT set_config({:tenantName}, {:tenant}, true), set_config({:bypassName}, {:bypass}, true)").
+		Bind(dbx.Params{
+			"tenantName": TenantSettingName,
+			"tenant":     dao.tenant,
+			"bypassName": TenantBypassSettingName,
+			"bypass":     bypass,
+		}).
+		Execute()
+	if err != nil {
+		return fmt.Errorf("failed to set the transaction tenant: %w", err)
+	}
+
+	return nil
+}
+
+// FindRecordTenant returns the tenant of the provided multi-tenant collection record.
+//
+// The lookup is performed with disabled tenant isolation.
+func (dao *Dao) FindRecordTenant(record *models.Record) (string, error) {
+	if !record.Collection().IsMultiTenant() {
+		return "", errors.New("not a multi-tenant collection record")
+	}
+
+	var tenant string
+
+	err := dao.RunWithoutTenantIsolation(func(txDao *Dao) error {
+		return txDao.DB().NewQuery(fmt.Sprintf(
+			"SELECT COALESCE([[%s]], '') FROM {{%s}} WHERE [[id]] = {:id} LIMIT 1",
+			schema.FieldNameTenant,
+			record.TableName(),
+		)).Bind(dbx.Params{"id": record.Id}).Row(&tenant)
+	})
+
+	return tenant, err
+}
+
+// syncTenantPolicy creates (or removes) the tenant column and
+// row-level security policy of the provided collection table.
+//
+// The tenant column value defaults to the current transaction tenant
+// and the policy allows access only to the current tenant records.
+// Queries without tenant don't match any rows, unless the tenant isolation
+// is explicitly disabled (eg. for admins, cron jobs and migrations).
+//
+// On multi-tenancy disable, only the policy is removed and
+// the tenant column is kept to preserve the existing data.
+func (dao *Dao) syncTenantPolicy(newCollection, oldCollection *models.Collection) error {
+	tableName := newCollection.Name
+
+	if !newCollection.IsMultiTenant() {
+		if oldCollection == nil || !oldCollection.IsMultiTenant() {
+			return nil // nothing to remove
+		}
+
+		_, err := dao.DB().NewQuery(fmt.Sprintf(
+			`
+			DROP POLICY IF EXISTS [[%[2]s]] ON {{%[1]s}};
+			ALTER TABLE {{%[1]s}} NO FORCE ROW LEVEL SECURITY;
+			ALTER TABLE {{%[1]s}} DISABLE ROW LEVEL SECURITY;
+			`,
+			tableName,
+			tenantPolicyName,
+		)).Execute()
+
+		return err
+	}
+
+	currentTenant := fmt.Sprintf("current_setting('%s', true)", TenantSettingName)
+	bypass := fmt.Sprintf("COALESCE(current_setting('%s', true), '') = 'on'", TenantBypassSettingName)
+	policy := fmt.Sprintf("(COALESCE(%[1]s, '') <> '' AND [[%[2]s]] = %[1]s) OR %[3]s", currentTenant, schema.FieldNameTenant, bypass)
+
+	_, err := dao.DB().NewQuery(fmt.Sprintf(
+		`
+		ALTER TABLE {{%[1]s}} ADD COLUMN IF NOT EXISTS [[%[2]s]] TEXT DEFAULT %[3]s;
+		CREATE INDEX IF NOT EXISTS [[_%[4]s_tenant_idx]] ON {{%[1]s}} ([[%[2]s]]);
+		ALTER TABLE {{%[1]s}} ENABLE ROW LEVEL SECURITY;
+		ALTER TABLE {{%[1]s}} FORCE ROW LEVEL SECURITY;
+		DROP POLICY IF EXISTS [[%[5]s]] ON {{%[1]s}};
+		CREATE POLICY [[%[5]s]] ON {{%[1]s}}
+			USING (%[6]s)
+			WITH CHECK (%[6]s);
+		`,
+		tableName,
+		schema.FieldNameTenant,
+		currentTenant,
+		newCollection.Id,
+		tenantPolicyName,
+		policy,
+	)).Execute()
+
+	return err
+}
@@ -0,0 +1,105 @@
+//go:build mysql
+
+package daos
+
+import (
+	"errors"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/pocketbase/dbx"
+)
+
+// TenantSettingName is the name of the Postgres run-time parameter
+// with the current transaction tenant (not used by MySQL).
+const TenantSettingName = "app.tenant"
+
+// TenantBypassSettingName is the name of the Postgres run-time parameter
+// that disables the tenant isolation (not used by MySQL).
+const TenantBypassSettingName = "app.tenant_bypass"
+
+var errMultiTenancyNotSupported = errors.New("multi-tenancy is not supported by MySQL")
+
+// WithTenant returns a new Dao bound to the provided tenant.
+//
+// Multi-tenancy is not supported by MySQL and all
+// transactions started with the returned Dao fail.
+func (dao *Dao) WithTenant(tenant string) *Dao {
+	clone := dao.Clone()
+	clone.tenant = tenant
+	clone.tenantBypass = false
+
+	return clone
+}
+
+// WithoutTenantIsolation returns a new Dao with the same configuration
+// options as the current one (MySQL doesn't have tenant isolation).
+func (dao *Dao) WithoutTenantIsolation() *Dao {
+	clone := dao.Clone()
+	clone.tenant = ""
+	clone.tenantBypass = true
+
+	return clone
+}
+
+// DisableTenantIsolation is no-op for MySQL since
+// it doesn't have multi-tenant collections.
+func DisableTenantIsolation(tx *dbx.Tx) error {
+	return nil
+}
+
+// WithTenantScopeOf returns a new Dao with the same tenant scope
+// as the provided scopeDao (or the current Dao if scopeDao doesn't have one).
+//
+// This is usually helpful when a new Dao needs to be created
+// from within a tenant transaction (eg. for dry submits).
+func (dao *Dao) WithTenantScopeOf(scopeDao *Dao) *Dao {
+	switch {
+	case scopeDao.tenantBypass:
+		return dao.WithoutTenantIsolation()
+	case scopeDao.tenant != "":
+		return dao.WithTenant(scopeDao.tenant)
+	default:
+		return dao
+	}
+}
+
+// HasTenantScope reports whether the current Dao is bound to a tenant
+// or has disabled tenant isolation (see [Dao.WithTenant] and [Dao.WithoutTenantIsolation]).
+func (dao *Dao) HasTenantScope() bool {
+	return dao.tenant != "" || dao.tenantBypass
+}
+
+// RunInTenantScope runs fn in a new transaction with the current Dao
+// tenant scope or directly with the current Dao if it doesn't have one.
+func (dao *Dao) RunInTenantScope(fn func(scopedDao *Dao) error) error {
+	if !dao.HasTenantScope() {
+		return fn(dao)
+	}
+
+	return dao.RunInTransaction(fn)
+}
+
+// RunInTenantTransaction is not supported by MySQL and always returns an error.
+func (dao *Dao) RunInTenantTransaction(tenant string, fn func(txDao *Dao) error) error {
+	return errMultiTenancyNotSupported
+}
+
+// RunWithoutTenantIsolation runs fn in a new transaction
+// (MySQL doesn't have tenant isolation).
+func (dao *Dao) RunWithoutTenantIsolation(fn func(txDao *Dao) error) error {
+	return dao.RunInTransaction(fn)
+}
+
+// applyTenantScope fails for tenant bound Dao since MySQL doesn't support multi-tenancy.
+func (dao *Dao) applyTenantScope() error {
+	if dao.tenant != "" {
+		return errMultiTenancyNotSupported
+	}
+
+	return nil
+}
+
+// FindRecordTenant is not supported by MySQL and always returns an error.
+func (dao *Dao) FindRecordTenant(record *models.Record) (string, error) {
+	return "", errMultiTenancyNotSupported
+}
@@ -0,0 +1,54 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/types"
+	"github.com/pocketbase/dbx"
+)
+
+const uploadTokensTableName = "_uploadTokens"
+
+// ConsumeUploadToken atomically marks the upload token with the
+// provided id as used until its expires date.
+//
+// Returns false if the token has already been consumed.
+func (dao *Dao) ConsumeUploadToken(id string, expires time.Time) (bool, error) {
+	now := types.NowDateTime()
+
+	// cleanup the expired tokens since they can't be used anymore
+	_, err := dao.NonconcurrentDB().Delete(
+		uploadTokensTableName,
+		dbx.NewExp("[[expires]] < {:now}", dbx.Params{"now": now}),
+	).Execute()
+	if err != nil {
+		return false, err
+	}
+
+	expiresDate, err := types.ParseDateTime(expires)
+	if err != nil {
+		return false, err
+	}
+
+	// note: concurrent inserts of the same token are
+	// additionally guarded by the primary key constraint
+	result, err := dao.NonconcurrentDB().NewQuery(
+		"INSERT INTO {{" + uploadTokensTableName + "}} ([[id]], [[expires]], [[created]]) " +
+			"SELECT {:id}, {:expires}, {:created} " +
+			"WHERE NOT EXISTS (SELECT 1 FROM {{" + uploadTokensTableName + "}} WHERE [[id]] = {:id})",
+	).Bind(dbx.Params{
+		"id":      id,
+		"expires": expiresDate,
+		"created": now,
+	}).Execute()
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
@@ -0,0 +1,46 @@
+package daos_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/tests"
+)
+
+func TestConsumeUploadToken(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	expires := time.Now().Add(time.Hour)
+
+	ok, err := app.Dao().ConsumeUploadToken("test", expires)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("Expected the token to be consumed")
+	}
+
+	ok, err = app.Dao().ConsumeUploadToken("test", expires)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("Expected the token to be already consumed")
+	}
+
+	// expired tokens cleanup
+	if _, err := app.Dao().ConsumeUploadToken("expired", time.Now().Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	ok, err = app.Dao().ConsumeUploadToken("expired", expires)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("Expected the expired token record to be deleted")
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/AlperRehaYAZGAN/postgresbase/models"
 	"github.com/AlperRehaYAZGAN/postgresbase/models/schema"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/dbutils"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/inflector"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/list"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/security"
@@ -20,15 +21,21 @@ import (
 
 // DeleteView drops the specified view name.
 //
+// The view could be either a regular or a materialized one.
+//
 // This method is a no-op if a view with the provided name doesn't exist.
 //
 // Be aware that this method is vulnerable to SQL injection and the
 // "name" argument must come only from trusted input!
 func (dao *Dao) DeleteView(name string) error {
-	_, err := dao.DB().NewQuery(fmt.Sprintf(
-		"DROP VIEW IF EXISTS {{%s}}",
-		name,
-	)).Execute()
+	// postgres requires an explicit "MATERIALIZED" keyword
+	// in order to drop a materialized view
+	dropQuery := "DROP VIEW IF EXISTS {{%s}}"
+	if dao.IsMaterializedView(name) {
+		dropQuery = "DROP MATERIALIZED VIEW IF EXISTS {{%s}}"
+	}
+
+	_, err := dao.DB().NewQuery(fmt.Sprintf(dropQuery, name)).Execute()
 
 	return err
 }
@@ -38,6 +45,79 @@ func (dao *Dao) DeleteView(name string) error {
 // Be aware that this method is vulnerable to SQL injection and the
 // "selectQuery" argument must come only from trusted input!
 func (dao *Dao) SaveView(name string, selectQuery string) error {
+	return dao.saveView(name, selectQuery, false)
+}
+
+// SaveMaterializedView creates (or updates already existing) persistent
+// SQL materialized view and populates it with the selectQuery result.
+//
+// Be aware that this method is vulnerable to SQL injection and the
+// "selectQuery" argument must come only from trusted input!
+func (dao *Dao) SaveMaterializedView(name string, selectQuery string) error {
+	return dao.saveView(name, selectQuery, true)
+}
+
+// materializedViewsExistsUnion extends the [Dao.HasTable] query
+// with a check for materialized view with the same name.
+const materializedViewsExistsUnion = " UNION ALL SELECT 1 FROM pg_matviews WHERE LOWER([[matviewname]])=LOWER({:tableName})"
+
+// IsMaterializedView checks if a materialized view with the provided name exists (case insensitive).
+func (dao *Dao) IsMaterializedView(name string) bool {
+	var exists bool
+
+	// !CHANGED: materialized views are not part of the information_schema
+	err := dao.DB().Select("count(*)").
+		From("pg_matviews").
+		AndWhere(dbx.NewExp("LOWER([[matviewname]])=LOWER({:name})", dbx.Params{"name": name})).
+		Limit(1).
+		Row(&exists)
+
+	return err == nil && exists
+}
+
+// RefreshMaterializedView refreshes the data of the provided
+// materialized view collection by rerunning its stored query.
+//
+// The refresh is performed with CONCURRENTLY (aka. without locking
+// concurrent selects) only if the collection has a unique "id" index.
+func (dao *Dao) RefreshMaterializedView(collection *models.Collection) error {
+	if !collection.IsMaterializedView() {
+		return errors.New("not a materialized view collection")
+	}
+
+	refreshQuery := "REFRESH MATERIALIZED VIEW {{%s}}"
+	if hasUniqueIdIndex(collection) {
+		refreshQuery = "REFRESH MATERIALIZED VIEW CONCURRENTLY {{%s}}"
+	}
+
+	// the materialized views data is shared and therefore it is
+	// refreshed with disabled multi-tenant collections isolation
+	return dao.RunWithoutTenantIsolation(func(txDao *Dao) error {
+		_, err := txDao.DB().NewQuery(fmt.Sprintf(refreshQuery, collection.Name)).Execute()
+
+		return err
+	})
+}
+
+// hasUniqueIdIndex checks whether the collection has a unique
+// non-partial index only on its "id" column, which is required
+// by postgres for the concurrent materialized view refresh.
+func hasUniqueIdIndex(collection *models.Collection) bool {
+	for _, raw := range collection.Indexes {
+		parsed := dbutils.ParseIndex(raw)
+
+		if parsed.Unique &&
+			parsed.Where == "" &&
+			len(parsed.Columns) == 1 &&
+			strings.EqualFold(trimRawIdentifier(parsed.Columns[0].Name), schema.FieldNameId) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (dao *Dao) saveView(name string, selectQuery string, materialized bool) error {
 	return dao.RunInTransaction(func(txDao *Dao) error {
 		// delete old view (if exists)
 		if err := txDao.DeleteView(name); err != nil {
@@ -53,15 +133,29 @@ func (dao *Dao) SaveView(name string, selectQuery string) error {
 			return errors.New("multiple statements are not supported")
 		}
 
+		createQuery := "CREATE VIEW {{%s}} AS SELECT * FROM (%s)"
+		if materialized {
+			createQuery = "CREATE MATERIALIZED VIEW {{%s}} AS SELECT * FROM (%s)"
+		}
+
 		// (re)create the view
 		//
 		// note: the query is wrapped in a secondary SELECT as a rudimentary
 		// measure to discourage multiple inline sql statements execution.
-		viewQuery := fmt.Sprintf("CREATE VIEW {{%s}} AS SELECT * FROM (%s)", name, selectQuery)
+		viewQuery := fmt.Sprintf(createQuery, name, selectQuery)
 		if _, err := txDao.DB().NewQuery(viewQuery).Execute(); err != nil {
 			return err
 		}
 
+		// materialized views are not listed in the information_schema
+		// and are checked separately
+		if materialized {
+			if !txDao.IsMaterializedView(name) {
+				return fmt.Errorf("failed to create materialized view %s", name)
+			}
+			return nil
+		}
+
 		// fetch the view table info to ensure that the view was created
 		// because missing tables or columns won't return an error
 		if _, err := txDao.TableInfo(name); err != nil {
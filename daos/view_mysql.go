@@ -76,6 +76,26 @@ func (dao *Dao) SaveView(name string, selectQuery string) error {
 	})
 }
 
+// SaveMaterializedView is not supported by MySQL and always returns an error.
+func (dao *Dao) SaveMaterializedView(name string, selectQuery string) error {
+	return errMaterializedViewsNotSupported
+}
+
+// materializedViewsExistsUnion is empty since MySQL doesn't support materialized views.
+const materializedViewsExistsUnion = ""
+
+// IsMaterializedView always returns false since MySQL doesn't support materialized views.
+func (dao *Dao) IsMaterializedView(name string) bool {
+	return false
+}
+
+// RefreshMaterializedView is not supported by MySQL and always returns an error.
+func (dao *Dao) RefreshMaterializedView(collection *models.Collection) error {
+	return errMaterializedViewsNotSupported
+}
+
+var errMaterializedViewsNotSupported = errors.New("materialized views are not supported by MySQL")
+
 // CreateViewSchema creates a new view schema from the provided select query.
 //
 // There are some caveats:
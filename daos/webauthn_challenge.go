@@ -0,0 +1,39 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/types"
+	"github.com/pocketbase/dbx"
+)
+
+// UseWebAuthnChallenge marks the provided WebAuthn challenge model as used.
+//
+// Returns an error if the challenge was already used
+// (aka. a model with the same id already exists).
+//
+// The expired used challenges are deleted as part of the call.
+func (dao *Dao) UseWebAuthnChallenge(challenge *models.WebAuthnChallenge) error {
+	if err := dao.DeleteExpiredWebAuthnChallenges(); err != nil {
+		return err
+	}
+
+	// always insert to fail on reuse
+	challenge.MarkAsNew()
+
+	return dao.Save(challenge)
+}
+
+// DeleteExpiredWebAuthnChallenges deletes all used WebAuthn
+// challenges whose expiration time has already passed.
+func (dao *Dao) DeleteExpiredWebAuthnChallenges() error {
+	formattedDate := time.Now().UTC().Format(types.DefaultDateLayout)
+
+	_, err := dao.NonconcurrentDB().Delete((&models.WebAuthnChallenge{}).TableName(), dbx.NewExp(
+		"[[expires]] < {:date}",
+		dbx.Params{"date": formattedDate},
+	)).Execute()
+
+	return err
+}
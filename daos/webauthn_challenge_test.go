@@ -0,0 +1,61 @@
+package daos_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/tests"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/types"
+)
+
+func newTestWebAuthnChallenge(t *testing.T, id string, collectionId string, expires time.Time) *models.WebAuthnChallenge {
+	model := &models.WebAuthnChallenge{CollectionId: collectionId}
+	model.Id = id
+
+	var err error
+	if model.Expires, err = types.ParseDateTime(expires); err != nil {
+		t.Fatal(err)
+	}
+
+	return model
+}
+
+func TestUseWebAuthnChallenge(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	totalChallenges := func() int {
+		var total int
+		if err := app.Dao().ModelQuery(&models.WebAuthnChallenge{}).Select("count(*)").Row(&total); err != nil {
+			t.Fatal(err)
+		}
+		return total
+	}
+
+	expired := newTestWebAuthnChallenge(t, "expired", collection.Id, time.Now().Add(-time.Minute))
+	if err := app.Dao().Save(expired); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Dao().UseWebAuthnChallenge(newTestWebAuthnChallenge(t, "test", collection.Id, time.Now().Add(time.Minute))); err != nil {
+		t.Fatalf("Expected the challenge to be marked as used, got %v", err)
+	}
+
+	if total := totalChallenges(); total != 1 {
+		t.Fatalf("Expected only the new challenge to remain, got %d", total)
+	}
+
+	// reuse
+	if err := app.Dao().UseWebAuthnChallenge(newTestWebAuthnChallenge(t, "test", collection.Id, time.Now().Add(time.Minute))); err == nil {
+		t.Fatal("Expected error for already used challenge")
+	}
+
+}
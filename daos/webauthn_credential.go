@@ -0,0 +1,88 @@
+package daos
+
+import (
+	"errors"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/pocketbase/dbx"
+)
+
+// WebAuthnCredentialQuery returns a new WebAuthnCredential select query.
+func (dao *Dao) WebAuthnCredentialQuery() *dbx.SelectQuery {
+	return dao.ModelQuery(&models.WebAuthnCredential{})
+}
+
+// FindAllWebAuthnCredentialsByRecord returns all WebAuthnCredential
+// models linked to the provided auth record.
+func (dao *Dao) FindAllWebAuthnCredentialsByRecord(authRecord *models.Record) ([]*models.WebAuthnCredential, error) {
+	credentials := []*models.WebAuthnCredential{}
+
+	err := dao.WebAuthnCredentialQuery().
+		AndWhere(dbx.HashExp{
+			"collectionId": authRecord.Collection().Id,
+			"recordId":     authRecord.Id,
+		}).
+		OrderBy("created ASC").
+		All(&credentials)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return credentials, nil
+}
+
+// FindWebAuthnCredentialByRecordAndId returns the WebAuthnCredential
+// model with the specified id linked to the provided auth record.
+func (dao *Dao) FindWebAuthnCredentialByRecordAndId(authRecord *models.Record, id string) (*models.WebAuthnCredential, error) {
+	model := &models.WebAuthnCredential{}
+
+	err := dao.WebAuthnCredentialQuery().
+		AndWhere(dbx.HashExp{
+			"id":           id,
+			"collectionId": authRecord.Collection().Id,
+			"recordId":     authRecord.Id,
+		}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// FindWebAuthnCredentialByCredentialId returns the WebAuthnCredential model
+// with the specified base64url encoded authenticator credential id.
+func (dao *Dao) FindWebAuthnCredentialByCredentialId(collectionId string, credentialId string) (*models.WebAuthnCredential, error) {
+	model := &models.WebAuthnCredential{}
+
+	err := dao.WebAuthnCredentialQuery().
+		AndWhere(dbx.HashExp{
+			"collectionId": collectionId,
+			"credentialId": credentialId,
+		}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// SaveWebAuthnCredential upserts the provided WebAuthnCredential model.
+func (dao *Dao) SaveWebAuthnCredential(model *models.WebAuthnCredential) error {
+	if model.CollectionId == "" || model.RecordId == "" || model.CredentialId == "" || model.PublicKey == "" {
+		return errors.New("Missing required WebAuthnCredential fields.")
+	}
+
+	return dao.Save(model)
+}
+
+// DeleteWebAuthnCredential deletes the provided WebAuthnCredential model.
+func (dao *Dao) DeleteWebAuthnCredential(model *models.WebAuthnCredential) error {
+	return dao.Delete(model)
+}
@@ -0,0 +1,197 @@
+package daos_test
+
+import (
+	"testing"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/tests"
+)
+
+func createTestWebAuthnCredential(t *testing.T, app *tests.TestApp, record *models.Record, credentialId string) *models.WebAuthnCredential {
+	credential := &models.WebAuthnCredential{
+		CollectionId: record.Collection().Id,
+		RecordId:     record.Id,
+		Name:         "test",
+		CredentialId: credentialId,
+		PublicKey:    "test_key",
+	}
+
+	if err := app.Dao().SaveWebAuthnCredential(credential); err != nil {
+		t.Fatal(err)
+	}
+
+	return credential
+}
+
+func TestWebAuthnCredentialQuery(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	expected := "SELECT {{_webauthnCredentials}}.* FROM `_webauthnCredentials`"
+
+	sql := app.Dao().WebAuthnCredentialQuery().Build().SQL()
+	if sql != expected {
+		t.Errorf("Expected sql %s, got %s", expected, sql)
+	}
+}
+
+func TestFindAllWebAuthnCredentialsByRecord(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record1, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record2, err := app.Dao().FindAuthRecordByEmail("users", "test2@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c1 := createTestWebAuthnCredential(t, app, record1, "a")
+	c2 := createTestWebAuthnCredential(t, app, record1, "b")
+	createTestWebAuthnCredential(t, app, record2, "c")
+
+	result, err := app.Dao().FindAllWebAuthnCredentialsByRecord(record1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 credentials, got %d", len(result))
+	}
+
+	for _, c := range result {
+		if c.Id != c1.Id && c.Id != c2.Id {
+			t.Fatalf("Unexpected credential %v", c)
+		}
+	}
+}
+
+func TestFindWebAuthnCredentialByRecordAndId(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record1, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record2, err := app.Dao().FindAuthRecordByEmail("users", "test2@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	credential := createTestWebAuthnCredential(t, app, record1, "a")
+
+	if _, err := app.Dao().FindWebAuthnCredentialByRecordAndId(record2, credential.Id); err == nil {
+		t.Fatal("Expected error for different auth record")
+	}
+
+	result, err := app.Dao().FindWebAuthnCredentialByRecordAndId(record1, credential.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Id != credential.Id {
+		t.Fatalf("Expected credential %q, got %q", credential.Id, result.Id)
+	}
+}
+
+func TestFindWebAuthnCredentialByCredentialId(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	credential := createTestWebAuthnCredential(t, app, record, "a")
+
+	scenarios := []struct {
+		collectionId string
+		credentialId string
+		expectError  bool
+	}{
+		{"", "", true},
+		{record.Collection().Id, "missing", true},
+		{"missing", "a", true},
+		{record.Collection().Id, "a", false},
+	}
+
+	for i, s := range scenarios {
+		result, err := app.Dao().FindWebAuthnCredentialByCredentialId(s.collectionId, s.credentialId)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if !hasErr && result.Id != credential.Id {
+			t.Errorf("(%d) Expected credential %q, got %q", i, credential.Id, result.Id)
+		}
+	}
+}
+
+func TestSaveWebAuthnCredential(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// missing required fields
+	if err := app.Dao().SaveWebAuthnCredential(&models.WebAuthnCredential{}); err == nil {
+		t.Fatal("Expected error for empty model")
+	}
+
+	credential := createTestWebAuthnCredential(t, app, record, "a")
+
+	// duplicated credential id
+	duplicated := &models.WebAuthnCredential{
+		CollectionId: record.Collection().Id,
+		RecordId:     record.Id,
+		CredentialId: credential.CredentialId,
+		PublicKey:    "test_key",
+	}
+	if err := app.Dao().SaveWebAuthnCredential(duplicated); err == nil {
+		t.Fatal("Expected unique constraint error")
+	}
+}
+
+func TestDeleteWebAuthnCredential(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	credential := createTestWebAuthnCredential(t, app, record, "a")
+
+	if err := app.Dao().DeleteWebAuthnCredential(credential); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.Dao().FindWebAuthnCredentialByRecordAndId(record, credential.Id); err == nil {
+		t.Fatal("Expected the credential to be deleted")
+	}
+}
@@ -394,7 +394,7 @@ func (form *CollectionUpsert) checkRule(value any) error {
 func (form *CollectionUpsert) checkIndexes(value any) error {
 	v, _ := value.(types.JsonArray[string])
 
-	if form.Type == models.CollectionTypeView && len(v) > 0 {
+	if form.Type == models.CollectionTypeView && !form.isMaterializedView() && len(v) > 0 {
 		return validation.NewError(
 			"validation_indexes_not_supported",
 			"The collection doesn't support indexes.",
@@ -433,6 +433,21 @@ func (form *CollectionUpsert) checkOptions(value any) error {
 	v, _ := value.(types.JsonMap)
 
 	switch form.Type {
+	case models.CollectionTypeBase:
+		options := models.CollectionBaseOptions{}
+		if err := decodeOptions(v, &options); err != nil {
+			return err
+		}
+
+		// check the generic validations
+		if err := options.Validate(); err != nil {
+			return err
+		}
+
+		// additional form specific validations
+		if err := form.checkMultiTenant(options.MultiTenant); err != nil {
+			return validation.Errors{"multiTenant": err}
+		}
 	case models.CollectionTypeAuth:
 		options := models.CollectionAuthOptions{}
 		if err := decodeOptions(v, &options); err != nil {
@@ -448,6 +463,9 @@ func (form *CollectionUpsert) checkOptions(value any) error {
 		if err := form.checkRule(options.ManageRule); err != nil {
 			return validation.Errors{"manageRule": err}
 		}
+		if err := form.checkMultiTenant(options.MultiTenant); err != nil {
+			return validation.Errors{"multiTenant": err}
+		}
 	case models.CollectionTypeView:
 		options := models.CollectionViewOptions{}
 		if err := decodeOptions(v, &options); err != nil {
@@ -473,6 +491,34 @@ func (form *CollectionUpsert) checkOptions(value any) error {
 	return nil
 }
 
+// checkMultiTenant ensures that the schema of a multi-tenant collection
+// doesn't have a field with the same name as the reserved tenant column.
+func (form *CollectionUpsert) checkMultiTenant(multiTenant bool) error {
+	if multiTenant && form.Schema.GetFieldByName(schema.FieldNameTenant) != nil {
+		return validation.NewError(
+			"validation_tenant_field_conflict",
+			fmt.Sprintf("Multi-tenant collections cannot have a schema field named %q.", schema.FieldNameTenant),
+		)
+	}
+
+	return nil
+}
+
+// isMaterializedView checks whether the form options
+// describe a materialized view collection.
+func (form *CollectionUpsert) isMaterializedView() bool {
+	if form.Type != models.CollectionTypeView {
+		return false
+	}
+
+	options := models.CollectionViewOptions{}
+	if err := decodeOptions(form.Options, &options); err != nil {
+		return false
+	}
+
+	return options.Materialized
+}
+
 func decodeOptions(options types.JsonMap, result any) error {
 	raw, err := options.MarshalJSON()
 	if err != nil {
@@ -516,15 +562,20 @@ func (form *CollectionUpsert) Submit(interceptors ...InterceptorFunc[*models.Col
 		form.collection.Name = form.Name
 	}
 
-	// view schema is autogenerated on save and cannot have indexes
+	// view schema is autogenerated on save
 	if !form.collection.IsView() {
 		form.collection.Schema = form.Schema
+	}
 
+	// only base, auth and materialized view collections can have indexes
+	if !form.collection.IsView() || form.isMaterializedView() {
 		// normalize indexes format
 		form.collection.Indexes = make(types.JsonArray[string], len(form.Indexes))
 		for i, rawIdx := range form.Indexes {
 			form.collection.Indexes[i] = dbutils.ParseIndex(rawIdx).Build()
 		}
+	} else {
+		form.collection.Indexes = types.JsonArray[string]{}
 	}
 
 	form.collection.ListRule = form.ListRule
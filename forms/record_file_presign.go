@@ -0,0 +1,219 @@
+package forms
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/core"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/models/schema"
+	"github.com/AlperRehaYAZGAN/postgresbase/tokens"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/filesystem"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/security"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/types"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// uploadNameInvalidCharsRegex matches the upload file name characters that are
+// replaced with "_" when the direct upload storage key is generated.
+var uploadNameInvalidCharsRegex = regexp.MustCompile(`[^\w\.\-]+`)
+
+// uploadKeyRegex matches the direct upload key part after the collection uploads path
+// (random dir + sanitized file name).
+var uploadKeyRegex = regexp.MustCompile(`^\w+/[\w\-][\w\.\-]*$`)
+
+// PresignedUpload defines a single prepared direct storage upload.
+type PresignedUpload struct {
+	// Key is the storage key of the uploaded file that could be
+	// later submitted as a file field value to attach it to a record.
+	Key string `json:"key"`
+
+	// Url is the url where the file content should be sent.
+	Url string `json:"url"`
+
+	// Method is the http method of the upload request.
+	Method string `json:"method"`
+
+	// Headers are the headers that must be sent with the upload request.
+	Headers map[string]string `json:"headers"`
+
+	// Expires is the upload url expiration date.
+	Expires types.DateTime `json:"expires"`
+}
+
+// RecordFilePresign is a form that prepares a direct storage upload
+// for a single collection file field (eg. S3 presigned url).
+//
+// For filesystems that don't support presigned urls (eg. the local filesystem),
+// the upload url points to the app files api with a signed one-time upload token.
+type RecordFilePresign struct {
+	app        core.App
+	collection *models.Collection
+
+	Field    string `form:"field" json:"field"`
+	Name     string `form:"name" json:"name"`
+	Size     int64  `form:"size" json:"size"`
+	MimeType string `form:"mimeType" json:"mimeType"`
+}
+
+// NewRecordFilePresign creates a new [RecordFilePresign] form initialized
+// with from the provided [core.App] and [models.Collection] instances.
+func NewRecordFilePresign(app core.App, collection *models.Collection) *RecordFilePresign {
+	return &RecordFilePresign{
+		app:        app,
+		collection: collection,
+	}
+}
+
+// Validate makes the form validatable by implementing [validation.Validatable] interface.
+func (form *RecordFilePresign) Validate() error {
+	if err := validation.ValidateStruct(form,
+		validation.Field(&form.Field, validation.Required, validation.By(form.checkField)),
+		validation.Field(&form.Name, validation.Required, validation.Length(1, 255)),
+		validation.Field(&form.MimeType, validation.Required, validation.Length(1, 255)),
+	); err != nil {
+		return err
+	}
+
+	options := form.fieldOptions()
+
+	return validation.ValidateStruct(form,
+		validation.Field(&form.Size, validation.Required, validation.Min(int64(1)), validation.Max(int64(options.MaxSize))),
+		validation.Field(&form.MimeType, validation.By(form.checkMimeType(options.MimeTypes))),
+	)
+}
+
+func (form *RecordFilePresign) checkField(value any) error {
+	v, _ := value.(string)
+
+	if form.collection.IsView() {
+		return validation.NewError("validation_view_collection", "View collections don't support file uploads.")
+	}
+
+	field := form.collection.Schema.GetFieldByName(v)
+	if field == nil || field.Type != schema.FieldTypeFile {
+		return validation.NewError("validation_invalid_file_field", "Missing or invalid file field.")
+	}
+
+	return nil
+}
+
+func (form *RecordFilePresign) checkMimeType(validTypes []string) validation.RuleFunc {
+	return func(value any) error {
+		v, _ := value.(string)
+
+		if len(validTypes) == 0 {
+			return nil // no restrictions
+		}
+
+		mediaType, _, err := mime.ParseMediaType(v)
+		if err == nil {
+			for _, t := range validTypes {
+				if strings.EqualFold(t, mediaType) {
+					return nil // valid
+				}
+			}
+		}
+
+		return validation.NewError(
+			"validation_invalid_mime_type",
+			"The mime type must be one of: "+strings.Join(validTypes, ", ")+".",
+		)
+	}
+}
+
+func (form *RecordFilePresign) fieldOptions() *schema.FileOptions {
+	field := form.collection.Schema.GetFieldByName(form.Field)
+	if field == nil {
+		return &schema.FileOptions{}
+	}
+
+	field.InitOptions()
+
+	options, _ := field.Options.(*schema.FileOptions)
+	if options == nil {
+		return &schema.FileOptions{}
+	}
+
+	return options
+}
+
+// NewUploadKey generates a new unique storage key for the form file
+// that could be later submitted as a record file field value.
+func (form *RecordFilePresign) NewUploadKey() string {
+	name := strings.TrimLeft(uploadNameInvalidCharsRegex.ReplaceAllString(form.Name, "_"), ".")
+	if length := len(name); length == 0 {
+		name = "file"
+	} else if length > 150 {
+		// keep only the last 150 characters to preserve the extension
+		name = name[length-150:]
+	}
+
+	return form.collection.BaseUploadsPath() + "/" + security.RandomString(32) + "/" + name
+}
+
+// Submit validates the form and prepares a new direct storage upload.
+//
+// You can optionally provide a list of InterceptorFunc to further
+// modify the form behavior before generating the upload url.
+func (form *RecordFilePresign) Submit(interceptors ...InterceptorFunc[*PresignedUpload]) (*PresignedUpload, error) {
+	if err := form.Validate(); err != nil {
+		return nil, err
+	}
+
+	duration := time.Duration(form.app.Settings().RecordFileToken.Duration) * time.Second
+
+	upload := &PresignedUpload{
+		Key:    form.NewUploadKey(),
+		Method: http.MethodPut,
+		Headers: map[string]string{
+			"Content-Type": form.MimeType,
+		},
+	}
+	upload.Expires, _ = types.ParseDateTime(time.Now().Add(duration))
+
+	interceptorsErr := runInterceptors(upload, func(u *PresignedUpload) error {
+		fs, err := form.app.NewFilesystem()
+		if err != nil {
+			return err
+		}
+		defer fs.Close()
+
+		u.Url, err = fs.SignedUploadURL(u.Key, form.Size, form.MimeType, duration)
+		if err == nil {
+			return nil
+		}
+
+		if !errors.Is(err, filesystem.ErrSignedURLNotSupported) {
+			return err
+		}
+
+		// fallback to a one-time upload token
+		token, err := tokens.NewUploadToken(form.app, form.collection, u.Key, form.Size, form.MimeType)
+		if err != nil {
+			return err
+		}
+
+		u.Url = strings.TrimRight(form.app.Settings().Meta.AppUrl, "/") + "/api/files/" + form.collection.Id + "/uploads/" + token
+
+		return nil
+	}, interceptors...)
+
+	if interceptorsErr != nil {
+		return nil, interceptorsErr
+	}
+
+	return upload, nil
+}
+
+// isUploadKey checks whether the provided value is a valid
+// direct storage upload key of the specified collection.
+func isUploadKey(collection *models.Collection, value string) bool {
+	prefix := collection.BaseUploadsPath() + "/"
+
+	return strings.HasPrefix(value, prefix) && uploadKeyRegex.MatchString(strings.TrimPrefix(value, prefix))
+}
@@ -0,0 +1,114 @@
+package forms_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/forms"
+	"github.com/AlperRehaYAZGAN/postgresbase/tests"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+func TestRecordFilePresignValidate(t *testing.T) {
+	t.Parallel()
+
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	scenarios := []struct {
+		name           string
+		collectionName string
+		data           string
+		expectedErrors []string
+	}{
+		{
+			"empty data",
+			"demo5",
+			`{}`,
+			[]string{"field", "name", "mimeType"},
+		},
+		{
+			"view collection",
+			"view2",
+			`{"field":"file_many","name":"test.txt","size":4,"mimeType":"text/plain"}`,
+			[]string{"field"},
+		},
+		{
+			"missing field",
+			"demo5",
+			`{"field":"missing","name":"test.txt","size":4,"mimeType":"text/plain"}`,
+			[]string{"field"},
+		},
+		{
+			"non file field",
+			"demo5",
+			`{"field":"total","name":"test.txt","size":4,"mimeType":"text/plain"}`,
+			[]string{"field"},
+		},
+		{
+			"zero size",
+			"demo5",
+			`{"field":"file","name":"test.txt","size":0,"mimeType":"text/plain"}`,
+			[]string{"size"},
+		},
+		{
+			"size bigger than the field max size",
+			"demo5",
+			`{"field":"file","name":"test.txt","size":5242881,"mimeType":"text/plain"}`,
+			[]string{"size"},
+		},
+		{
+			"not allowed mime type",
+			"users",
+			`{"field":"avatar","name":"test.txt","size":4,"mimeType":"text/plain"}`,
+			[]string{"mimeType"},
+		},
+		{
+			"allowed mime type with parameters",
+			"users",
+			`{"field":"avatar","name":"test.png","size":4,"mimeType":"IMAGE/PNG; foo=bar"}`,
+			[]string{},
+		},
+		{
+			"field without mime type restrictions",
+			"demo5",
+			`{"field":"file","name":"test.txt","size":5242880,"mimeType":"text/plain"}`,
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		collection, err := testApp.Dao().FindCollectionByNameOrId(s.collectionName)
+		if err != nil {
+			t.Fatalf("[%s] Failed to fetch collection: %v", s.name, err)
+		}
+
+		form := forms.NewRecordFilePresign(testApp, collection)
+
+		// load data
+		loadErr := json.Unmarshal([]byte(s.data), form)
+		if loadErr != nil {
+			t.Errorf("[%s] Failed to load form data: %v", s.name, loadErr)
+			continue
+		}
+
+		result := form.Validate()
+
+		// parse errors
+		errs, ok := result.(validation.Errors)
+		if !ok && result != nil {
+			t.Errorf("[%s] Failed to parse errors %v", s.name, result)
+			continue
+		}
+
+		// check errors
+		if len(errs) > len(s.expectedErrors) {
+			t.Errorf("[%s] Expected error keys %v, got %v", s.name, s.expectedErrors, errs)
+		}
+		for _, k := range s.expectedErrors {
+			if _, ok := errs[k]; !ok {
+				t.Errorf("[%s] Missing expected error key %q in %v", s.name, k, errs)
+			}
+		}
+	}
+}
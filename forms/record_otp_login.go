@@ -0,0 +1,128 @@
+package forms
+
+import (
+	"errors"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/core"
+	"github.com/AlperRehaYAZGAN/postgresbase/daos"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// RecordOTPLoginData defines the data passed to the [RecordOTPLogin] interceptors.
+type RecordOTPLoginData struct {
+	Record *models.Record
+	OTP    *models.OTP
+}
+
+// RecordOTPLogin is an auth record one-time password login form.
+type RecordOTPLogin struct {
+	app        core.App
+	dao        *daos.Dao
+	collection *models.Collection
+
+	OTPId    string `form:"otpId" json:"otpId"`
+	Password string `form:"password" json:"password"`
+}
+
+// NewRecordOTPLogin creates a new [RecordOTPLogin] form initialized
+// with from the provided [core.App] and [models.Collection] instances.
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewRecordOTPLogin(app core.App, collection *models.Collection) *RecordOTPLogin {
+	return &RecordOTPLogin{
+		app:        app,
+		dao:        app.Dao(),
+		collection: collection,
+	}
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *RecordOTPLogin) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// Validate makes the form validatable by implementing [validation.Validatable] interface.
+func (form *RecordOTPLogin) Validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.OTPId, validation.Required, validation.Length(1, 255)),
+		validation.Field(&form.Password, validation.Required, validation.Length(1, 100)),
+	)
+}
+
+// Submit validates and submits the form.
+// On success returns the authorized record model.
+//
+// Each submit counts as a verification attempt and the OTP is
+// invalidated after [models.OTPMaxAttempts] attempts.
+// The OTP is accepted only if it was sent to the current auth record email.
+// On successful login all OTPs of the auth record are deleted.
+//
+// You can optionally provide a list of InterceptorFunc to
+// further modify the form behavior before persisting it.
+func (form *RecordOTPLogin) Submit(interceptors ...InterceptorFunc[*RecordOTPLoginData]) (*models.Record, error) {
+	if err := form.Validate(); err != nil {
+		return nil, err
+	}
+
+	invalidErr := errors.New("Invalid or expired one-time password.")
+
+	otp, err := form.dao.FindOTPById(form.OTPId)
+	if err != nil ||
+		otp.CollectionId != form.collection.Id ||
+		otp.HasExpired(form.collection.AuthOptions().OTPDurationOrDefault()) {
+		return nil, invalidErr
+	}
+
+	ok, err := form.dao.ConsumeOTPAttempt(otp)
+	if err != nil {
+		return nil, err
+	}
+	if !ok || !otp.ValidatePassword(form.Password) {
+		return nil, invalidErr
+	}
+
+	authRecord, err := form.dao.FindRecordById(form.collection.Id, otp.RecordId)
+	if err != nil {
+		return nil, invalidErr
+	}
+
+	// the OTP was sent to an old auth record email
+	if otp.Email == "" || otp.Email != authRecord.Email() {
+		return nil, invalidErr
+	}
+
+	data := &RecordOTPLoginData{
+		Record: authRecord,
+		OTP:    otp,
+	}
+
+	interceptorsErr := runInterceptors(data, func(data *RecordOTPLoginData) error {
+		authRecord = data.Record
+		if authRecord == nil {
+			return invalidErr
+		}
+
+		return form.dao.RunInTransaction(func(txDao *daos.Dao) error {
+			if err := txDao.DeleteRecordOTPs(form.collection.Id, otp.RecordId); err != nil {
+				return err
+			}
+
+			// the code was delivered to the record email
+			// so we can consider it as verified
+			if !authRecord.Verified() {
+				authRecord.SetVerified(true)
+				return txDao.SaveRecord(authRecord)
+			}
+
+			return nil
+		})
+	}, interceptors...)
+
+	if interceptorsErr != nil {
+		return nil, interceptorsErr
+	}
+
+	return authRecord, nil
+}
@@ -0,0 +1,225 @@
+package forms_test
+
+import (
+	"testing"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/forms"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/tests"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/types"
+)
+
+func TestRecordOTPLoginValidate(t *testing.T) {
+	t.Parallel()
+
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	authCollection, err := testApp.Dao().FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name        string
+		otpId       string
+		password    string
+		expectError bool
+	}{
+		{"empty data", "", "", true},
+		{"missing password", "test", "", true},
+		{"missing otpId", "", "123456", true},
+		{"valid data", "test", "123456", false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			form := forms.NewRecordOTPLogin(testApp, authCollection)
+			form.OTPId = s.otpId
+			form.Password = s.password
+
+			err := form.Validate()
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr to be %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+		})
+	}
+}
+
+func TestRecordOTPLoginSubmit(t *testing.T) {
+	t.Parallel()
+
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	authCollection, err := testApp.Dao().FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// unverified users record
+	authRecord, err := testApp.Dao().FindAuthRecordByEmail(authCollection.Id, "test2@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherCollection, err := testApp.Dao().FindCollectionByNameOrId("clients")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// other users records
+	// (note: an auth record could have only one active OTP)
+	record1, err := testApp.Dao().FindAuthRecordByEmail(authCollection.Id, "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	record3, err := testApp.Dao().FindAuthRecordByEmail(authCollection.Id, "test3@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newOTP := func(collectionId string, record *models.Record, attempts int, created types.DateTime) *models.OTP {
+		otp := &models.OTP{
+			CollectionId: collectionId,
+			RecordId:     record.Id,
+			Email:        record.Email(),
+			Attempts:     attempts,
+		}
+		otp.SetPassword("123456")
+		otp.Created = created
+		if err := testApp.Dao().SaveOTP(otp); err != nil {
+			t.Fatal(err)
+		}
+		return otp
+	}
+
+	expiredDate, _ := types.ParseDateTime("2023-01-01 00:00:00.000Z")
+
+	otherCollectionOTP := newOTP(otherCollection.Id, authRecord, 0, types.NowDateTime())
+	expiredOTP := newOTP(authCollection.Id, record1, 0, expiredDate)
+	maxAttemptsOTP := newOTP(authCollection.Id, record3, models.OTPMaxAttempts, types.NowDateTime())
+	validOTP := newOTP(authCollection.Id, authRecord, 0, types.NowDateTime())
+
+	scenarios := []struct {
+		name          string
+		otpId         string
+		password      string
+		expectError   bool
+		expectAttempt int
+	}{
+		{"missing otp", "missing", "123456", true, -1},
+		{"otp from different collection", otherCollectionOTP.Id, "123456", true, 0},
+		{"expired otp", expiredOTP.Id, "123456", true, 0},
+		{"otp with max attempts", maxAttemptsOTP.Id, "123456", true, models.OTPMaxAttempts},
+		{"invalid password", validOTP.Id, "654321", true, 1},
+		{"valid password", validOTP.Id, "123456", false, -1},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			form := forms.NewRecordOTPLogin(testApp, authCollection)
+			form.OTPId = s.otpId
+			form.Password = s.password
+
+			interceptorCalls := 0
+			interceptor := func(next forms.InterceptorNextFunc[*forms.RecordOTPLoginData]) forms.InterceptorNextFunc[*forms.RecordOTPLoginData] {
+				return func(data *forms.RecordOTPLoginData) error {
+					interceptorCalls++
+
+					if data.OTP == nil || data.OTP.Id != s.otpId {
+						t.Fatalf("Expected interceptor OTP %q, got %v", s.otpId, data.OTP)
+					}
+
+					return next(data)
+				}
+			}
+
+			record, err := form.Submit(interceptor)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr to be %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if s.expectAttempt >= 0 {
+				otp, err := testApp.Dao().FindOTPById(s.otpId)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if otp.Attempts != s.expectAttempt {
+					t.Fatalf("Expected %d attempts, got %d", s.expectAttempt, otp.Attempts)
+				}
+			}
+
+			if hasErr {
+				if interceptorCalls != 0 {
+					t.Fatalf("Expected interceptor to not be called, got %d", interceptorCalls)
+				}
+				return
+			}
+
+			if interceptorCalls != 1 {
+				t.Fatalf("Expected interceptor to be called once, got %d", interceptorCalls)
+			}
+
+			if record.Id != authRecord.Id {
+				t.Fatalf("Expected record %q, got %q", authRecord.Id, record.Id)
+			}
+
+			if !record.Verified() {
+				t.Fatal("Expected the auth record to be marked as verified")
+			}
+
+			// the auth record OTP should be deleted
+			if _, err := testApp.Dao().FindOTPById(validOTP.Id); err == nil {
+				t.Fatalf("Expected OTP %q to be deleted", validOTP.Id)
+			}
+
+			// the other records and collections OTPs should remain
+			for _, id := range []string{expiredOTP.Id, maxAttemptsOTP.Id, otherCollectionOTP.Id} {
+				if _, err := testApp.Dao().FindOTPById(id); err != nil {
+					t.Fatalf("Expected OTP %q to remain, got %v", id, err)
+				}
+			}
+		})
+	}
+}
+
+func TestRecordOTPLoginSubmitOldEmail(t *testing.T) {
+	t.Parallel()
+
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	authCollection, err := testApp.Dao().FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authRecord, err := testApp.Dao().FindAuthRecordByEmail(authCollection.Id, "test2@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// OTP sent to a previous auth record email
+	otp := &models.OTP{
+		CollectionId: authCollection.Id,
+		RecordId:     authRecord.Id,
+		Email:        "old@example.com",
+	}
+	otp.SetPassword("123456")
+	if err := testApp.Dao().SaveOTP(otp); err != nil {
+		t.Fatal(err)
+	}
+
+	form := forms.NewRecordOTPLogin(testApp, authCollection)
+	form.OTPId = otp.Id
+	form.Password = "123456"
+
+	if _, err := form.Submit(); err == nil {
+		t.Fatal("Expected the OTP sent to the old email to be rejected")
+	}
+}
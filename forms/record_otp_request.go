@@ -0,0 +1,134 @@
+package forms
+
+import (
+	"errors"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/core"
+	"github.com/AlperRehaYAZGAN/postgresbase/daos"
+	"github.com/AlperRehaYAZGAN/postgresbase/mails"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/security"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+// RecordOTPRequest is an auth record one-time password request form.
+type RecordOTPRequest struct {
+	app             core.App
+	dao             *daos.Dao
+	collection      *models.Collection
+	resendThreshold float64 // in seconds
+
+	Email string `form:"email" json:"email"`
+}
+
+// NewRecordOTPRequest creates a new [RecordOTPRequest] form initialized
+// with from the provided [core.App] and [models.Collection] instances.
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewRecordOTPRequest(app core.App, collection *models.Collection) *RecordOTPRequest {
+	return &RecordOTPRequest{
+		app:             app,
+		dao:             app.Dao(),
+		collection:      collection,
+		resendThreshold: 60, // 1 min
+	}
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *RecordOTPRequest) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// Validate makes the form validatable by implementing [validation.Validatable] interface.
+//
+// This method doesn't verify that auth record with `form.Email` exists (this is done on Submit).
+func (form *RecordOTPRequest) Validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(
+			&form.Email,
+			validation.Required,
+			validation.Length(1, 255),
+			is.EmailFormat,
+		),
+	)
+}
+
+// Submit validates the form, generates a new one-time password
+// and sends it to the `form.Email` auth record.
+//
+// The new OTP replaces (aka. invalidates) the previous auth record OTP
+// and inherits its failed attempts if it is still valid.
+//
+// The returned OTP model has its id always populated, even if the
+// interceptors chain is still running (eg. in a background goroutine).
+//
+// You can optionally provide a list of InterceptorFunc to further
+// modify the form behavior before persisting it.
+func (form *RecordOTPRequest) Submit(interceptors ...InterceptorFunc[*models.Record]) (*models.OTP, error) {
+	if err := form.Validate(); err != nil {
+		return nil, err
+	}
+
+	authRecord, err := form.dao.FindAuthRecordByEmail(form.collection.Id, form.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	authOptions := form.collection.AuthOptions()
+
+	otp := &models.OTP{CollectionId: form.collection.Id}
+
+	lastOTP, _ := form.dao.FindRecordOTP(form.collection.Id, authRecord.Id)
+	if lastOTP != nil && !lastOTP.IsOutdated(authOptions.OTPDurationOrDefault()) {
+		if time.Since(lastOTP.Created.Time()).Seconds() < form.resendThreshold {
+			return nil, errors.New("You've already requested a one-time password.")
+		}
+
+		if lastOTP.Attempts >= models.OTPMaxAttempts {
+			return nil, errors.New("Too many failed one-time password attempts.")
+		}
+
+		otp.Attempts = lastOTP.Attempts
+	}
+
+	otp.RefreshId()
+
+	interceptorsErr := runInterceptors(authRecord, func(m *models.Record) error {
+		code := security.RandomStringWithAlphabet(authOptions.OTPLengthOrDefault(), "0123456789")
+
+		otp.RecordId = m.Id
+		otp.Email = m.Email()
+		if err := otp.SetPassword(code); err != nil {
+			return err
+		}
+
+		txErr := form.dao.RunInTransaction(func(txDao *daos.Dao) error {
+			// invalidate the previous auth record OTP
+			if err := txDao.DeleteRecordOTPs(form.collection.Id, m.Id); err != nil {
+				return err
+			}
+
+			// cleanup the no longer valid collection OTPs
+			expiredBefore := time.Now().Add(-authOptions.OTPDurationOrDefault())
+			if err := txDao.DeleteExpiredOTPs(form.collection.Id, expiredBefore); err != nil {
+				return err
+			}
+
+			return txDao.SaveOTP(otp)
+		})
+		if txErr != nil {
+			return txErr
+		}
+
+		return mails.SendRecordOTP(form.app, m, otp, code)
+	}, interceptors...)
+
+	if interceptorsErr != nil {
+		return nil, interceptorsErr
+	}
+
+	return otp, nil
+}
@@ -0,0 +1,227 @@
+package forms_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/forms"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/tests"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/types"
+)
+
+func TestRecordOTPRequestSubmit(t *testing.T) {
+	t.Parallel()
+
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	authCollection, err := testApp.Dao().FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name        string
+		jsonData    string
+		expectError bool
+	}{
+		{"empty email", `{"email":""}`, true},
+		{"invalid email", `{"email":"invalid"}`, true},
+		{"missing auth record", `{"email":"missing@example.com"}`, true},
+		{"existing auth record", `{"email":"test@example.com"}`, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			testApp.TestMailer.TotalSend = 0 // reset
+
+			form := forms.NewRecordOTPRequest(testApp, authCollection)
+
+			// load data
+			loadErr := json.Unmarshal([]byte(s.jsonData), form)
+			if loadErr != nil {
+				t.Fatalf("Failed to load form data: %v", loadErr)
+			}
+
+			interceptorCalls := 0
+			interceptor := func(next forms.InterceptorNextFunc[*models.Record]) forms.InterceptorNextFunc[*models.Record] {
+				return func(r *models.Record) error {
+					interceptorCalls++
+					return next(r)
+				}
+			}
+
+			otp, err := form.Submit(interceptor)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr to be %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			expectInterceptorCalls := 1
+			if s.expectError {
+				expectInterceptorCalls = 0
+			}
+			if interceptorCalls != expectInterceptorCalls {
+				t.Fatalf("Expected interceptor to be called %d, got %d", expectInterceptorCalls, interceptorCalls)
+			}
+
+			if hasErr {
+				if testApp.TestMailer.TotalSend != 0 {
+					t.Fatalf("Expected no emails to be sent, got %d", testApp.TestMailer.TotalSend)
+				}
+				return
+			}
+
+			if testApp.TestMailer.TotalSend != 1 {
+				t.Fatalf("Expected 1 email to be sent, got %d", testApp.TestMailer.TotalSend)
+			}
+
+			saved, err := testApp.Dao().FindOTPById(otp.Id)
+			if err != nil {
+				t.Fatalf("Expected the OTP to be saved, got %v", err)
+			}
+
+			if saved.CollectionId != authCollection.Id {
+				t.Fatalf("Expected collectionId %q, got %q", authCollection.Id, saved.CollectionId)
+			}
+
+			if saved.PasswordHash == "" || saved.Attempts != 0 {
+				t.Fatalf("Expected hashed password and 0 attempts, got %q and %d", saved.PasswordHash, saved.Attempts)
+			}
+		})
+	}
+}
+
+func TestRecordOTPRequestSubmitWithPreviousOTP(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name             string
+		lastCreated      time.Duration // ago
+		lastAttempts     int
+		expectError      bool
+		expectedAttempts int
+	}{
+		{"within the resend threshold", 30 * time.Second, 0, true, 0},
+		{"valid with failed attempts", 90 * time.Second, 2, false, 2},
+		{"valid with max attempts", 90 * time.Second, models.OTPMaxAttempts, true, 0},
+		{"outdated with max attempts", 10 * time.Minute, models.OTPMaxAttempts, false, 0},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			testApp, _ := tests.NewTestApp()
+			defer testApp.Cleanup()
+
+			authRecord, err := testApp.Dao().FindAuthRecordByEmail("users", "test@example.com")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			lastOTP := &models.OTP{
+				CollectionId: authRecord.Collection().Id,
+				RecordId:     authRecord.Id,
+				Attempts:     s.lastAttempts,
+			}
+			lastOTP.Created, _ = types.ParseDateTime(time.Now().Add(-s.lastCreated))
+			if err := lastOTP.SetPassword("123456"); err != nil {
+				t.Fatal(err)
+			}
+			if err := testApp.Dao().SaveOTP(lastOTP); err != nil {
+				t.Fatal(err)
+			}
+
+			form := forms.NewRecordOTPRequest(testApp, authRecord.Collection())
+			form.Email = authRecord.Email()
+
+			otp, err := form.Submit()
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr to be %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				if testApp.TestMailer.TotalSend != 0 {
+					t.Fatalf("Expected no emails to be sent, got %d", testApp.TestMailer.TotalSend)
+				}
+
+				if _, err := testApp.Dao().FindOTPById(lastOTP.Id); err != nil {
+					t.Fatalf("Expected the last OTP to be preserved, got %v", err)
+				}
+				return
+			}
+
+			if _, err := testApp.Dao().FindOTPById(lastOTP.Id); err == nil {
+				t.Fatal("Expected the last OTP to be invalidated")
+			}
+
+			saved, err := testApp.Dao().FindOTPById(otp.Id)
+			if err != nil {
+				t.Fatalf("Expected the OTP to be saved, got %v", err)
+			}
+
+			if saved.Attempts != s.expectedAttempts {
+				t.Fatalf("Expected %d attempts, got %d", s.expectedAttempts, saved.Attempts)
+			}
+
+			if saved.Email != authRecord.Email() {
+				t.Fatalf("Expected the OTP email %q, got %q", authRecord.Email(), saved.Email)
+			}
+		})
+	}
+}
+
+func TestRecordOTPRequestInterceptors(t *testing.T) {
+	t.Parallel()
+
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	authCollection, err := testApp.Dao().FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := forms.NewRecordOTPRequest(testApp, authCollection)
+	form.Email = "test@example.com"
+
+	interceptorErr := errors.New("test_error")
+
+	interceptor1Called := false
+	interceptor1 := func(next forms.InterceptorNextFunc[*models.Record]) forms.InterceptorNextFunc[*models.Record] {
+		return func(r *models.Record) error {
+			interceptor1Called = true
+			return next(r)
+		}
+	}
+
+	interceptor2Called := false
+	interceptor2 := func(next forms.InterceptorNextFunc[*models.Record]) forms.InterceptorNextFunc[*models.Record] {
+		return func(r *models.Record) error {
+			interceptor2Called = true
+			return interceptorErr
+		}
+	}
+
+	_, submitErr := form.Submit(interceptor1, interceptor2)
+	if submitErr != interceptorErr {
+		t.Fatalf("Expected submitError %v, got %v", interceptorErr, submitErr)
+	}
+
+	if !interceptor1Called {
+		t.Fatalf("Expected interceptor1 to be called")
+	}
+
+	if !interceptor2Called {
+		t.Fatalf("Expected interceptor2 to be called")
+	}
+
+	if testApp.TestMailer.TotalSend != 0 {
+		t.Fatalf("Expected no emails to be sent, got %d", testApp.TestMailer.TotalSend)
+	}
+}
@@ -1,11 +1,13 @@
 package forms
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -14,9 +16,11 @@ import (
 	"github.com/AlperRehaYAZGAN/postgresbase/forms/validators"
 	"github.com/AlperRehaYAZGAN/postgresbase/models"
 	"github.com/AlperRehaYAZGAN/postgresbase/models/schema"
+	"github.com/AlperRehaYAZGAN/postgresbase/models/settings"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/filesystem"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/list"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/rest"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/scanner"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/security"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
@@ -36,6 +40,8 @@ type RecordUpsert struct {
 
 	filesToUpload map[string][]*filesystem.File
 	filesToDelete []string // names list
+	fileScans     []*RecordFileScan
+	filesMetadata []*models.FileMetadata
 
 	// base model fields
 	Id string `json:"id"`
@@ -54,6 +60,15 @@ type RecordUpsert struct {
 	data map[string]any
 }
 
+// RecordFileScan defines a single scanned record upload file result.
+type RecordFileScan struct {
+	Field     string `json:"field"`
+	Name      string `json:"name"`
+	Infected  bool   `json:"infected"`
+	Signature string `json:"signature,omitempty"`
+	Action    string `json:"action,omitempty"`
+}
+
 // NewRecordUpsert creates a new [RecordUpsert] form with initializer
 // config created from the provided [core.App] and [models.Record] instances
 // (for create you could pass a pointer to an empty Record - models.NewRecord(collection)).
@@ -259,6 +274,12 @@ func (form *RecordUpsert) FilesToDelete() []string {
 	return form.filesToDelete
 }
 
+// FileScans returns the scan results of the files uploaded with the last form submit
+// (if the file uploads scanning is enabled).
+func (form *RecordUpsert) FileScans() []*RecordFileScan {
+	return form.fileScans
+}
+
 // AddFiles adds the provided file(s) to the specified file field.
 //
 // If the file field is a SINGLE-value file field (aka. "Max Select = 1"),
@@ -428,6 +449,15 @@ func (form *RecordUpsert) LoadData(requestData map[string]any) error {
 		oldNames := form.record.GetStringSlice(key)
 		submittedNames := list.ToUniqueStringSlice(value)
 
+		// extract the keys of the files uploaded directly to the storage (if any)
+		uploadKeys := []string{}
+		for i := len(submittedNames) - 1; i >= 0; i-- {
+			if isUploadKey(form.record.Collection(), submittedNames[i]) {
+				uploadKeys = append([]string{submittedNames[i]}, uploadKeys...)
+				submittedNames = append(submittedNames[:i], submittedNames[i+1:]...)
+			}
+		}
+
 		// ensure that all submitted names are existing to prevent accidental files deletions
 		if len(submittedNames) > len(oldNames) || len(list.SubtractSlice(submittedNames, oldNames)) != 0 {
 			return validation.Errors{
@@ -463,6 +493,30 @@ func (form *RecordUpsert) LoadData(requestData map[string]any) error {
 		if len(submittedNames) > 0 && len(list.SubtractSlice(submittedNames, oldNames)) == 0 {
 			form.data[key] = submittedNames
 		}
+
+		// -----------------------------------------------------------
+		// Attach the files uploaded directly to the storage
+		// -----------------------------------------------------------
+
+		if len(uploadKeys) > 0 {
+			files := make([]*filesystem.File, 0, len(uploadKeys))
+			for _, uploadKey := range uploadKeys {
+				file, err := filesystem.NewFileFromObject(form.app.NewFilesystem, uploadKey)
+				if err != nil {
+					return validation.Errors{
+						key: validation.NewError(
+							"validation_missing_uploaded_file",
+							"The field contains missing or expired uploaded files.",
+						),
+					}
+				}
+				files = append(files, file)
+			}
+
+			if err := form.AddFiles(key, files...); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
@@ -707,10 +761,12 @@ func (form *RecordUpsert) DrySubmit(callback func(txDao *daos.Dao) error) error
 	if form.dao.ConcurrentDB() == form.dao.NonconcurrentDB() {
 		// it is already in a transaction and therefore use the app concurrent db pool
 		// to prevent "transaction has already been committed or rolled back" error
-		dryDao = daos.New(form.app.Dao().ConcurrentDB())
+		// (preserving the transaction tenant scope, if any)
+		dryDao = daos.New(form.app.Dao().ConcurrentDB()).WithTenantScopeOf(form.dao)
 	} else {
 		// otherwise use the form noncurrent dao db pool
-		dryDao = daos.New(form.dao.NonconcurrentDB())
+		// (preserving the form dao tenant scope, if any)
+		dryDao = form.dao.WithoutHooks()
 	}
 
 	return dryDao.RunInTransaction(func(txDao *daos.Dao) error {
@@ -754,6 +810,11 @@ func (form *RecordUpsert) Submit(interceptors ...InterceptorFunc[*models.Record]
 			form.record.MarkAsNew()
 		}
 
+		// scan the new files before storing them (if enabled)
+		if err := form.processFilesToScan(); err != nil {
+			return err
+		}
+
 		dao := form.dao.Clone()
 
 		// upload new files (if any)
@@ -765,7 +826,7 @@ func (form *RecordUpsert) Submit(interceptors ...InterceptorFunc[*models.Record]
 		dao.BeforeCreateFunc = func(eventDao *daos.Dao, m models.Model, action func() error) error {
 			newAction := func() error {
 				if m.TableName() == form.record.TableName() && m.GetId() == form.record.GetId() {
-					if err := form.processFilesToUpload(); err != nil {
+					if err := form.processFilesToUpload(eventDao); err != nil {
 						return err
 					}
 				}
@@ -783,7 +844,7 @@ func (form *RecordUpsert) Submit(interceptors ...InterceptorFunc[*models.Record]
 		dao.BeforeUpdateFunc = func(eventDao *daos.Dao, m models.Model, action func() error) error {
 			newAction := func() error {
 				if m.TableName() == form.record.TableName() && m.GetId() == form.record.GetId() {
-					if err := form.processFilesToUpload(); err != nil {
+					if err := form.processFilesToUpload(eventDao); err != nil {
 						return err
 					}
 				}
@@ -800,8 +861,23 @@ func (form *RecordUpsert) Submit(interceptors ...InterceptorFunc[*models.Record]
 		// ---
 
 		// persist the record model
-		if err := dao.SaveRecord(form.record); err != nil {
-			return form.prepareError(err)
+		if len(form.filesToUpload) == 0 && len(form.filesToDelete) == 0 {
+			if err := dao.SaveRecord(form.record); err != nil {
+				return form.prepareError(err)
+			}
+		} else {
+			// persist the record model together with the uploaded
+			// and deleted files metadata
+			txErr := dao.RunInTransaction(func(txDao *daos.Dao) error {
+				if err := txDao.SaveRecord(form.record); err != nil {
+					return err
+				}
+
+				return form.processFilesMetadata(txDao)
+			})
+			if txErr != nil {
+				return form.prepareError(txErr)
+			}
 		}
 
 		// delete old files (if any)
@@ -815,11 +891,23 @@ func (form *RecordUpsert) Submit(interceptors ...InterceptorFunc[*models.Record]
 			)
 		}
 
+		// delete the attached direct upload files (if any)
+		if err := form.processUploadedObjectsToDelete(); err != nil {
+			form.app.Logger().Debug(
+				"Failed to delete attached uploaded files",
+				slog.String("error", err.Error()),
+			)
+		}
+
 		return nil
 	}, interceptors...)
 }
 
-func (form *RecordUpsert) processFilesToUpload() error {
+// processFilesToUpload uploads the new record files.
+//
+// The deduplicated files blob references are registered
+// with the provided dao (usually the record save event one).
+func (form *RecordUpsert) processFilesToUpload(dao *daos.Dao) error {
 	if len(form.filesToUpload) == 0 {
 		return nil // no parsed file fields
 	}
@@ -828,7 +916,7 @@ func (form *RecordUpsert) processFilesToUpload() error {
 		return errors.New("the record doesn't have an id")
 	}
 
-	fs, err := form.app.NewFilesystem()
+	fs, err := form.newFilesystem(dao)
 	if err != nil {
 		return err
 	}
@@ -837,12 +925,30 @@ func (form *RecordUpsert) processFilesToUpload() error {
 	var uploadErrors []error // list of upload errors
 	var uploaded []string    // list of uploaded file paths
 
+	form.filesMetadata = nil
+
+	dedup := form.record.Collection().IsFilesDedup()
+
 	for fieldKey := range form.filesToUpload {
 		for i, file := range form.filesToUpload[fieldKey] {
 			path := form.record.BaseFilesPath() + "/" + file.Name
-			if err := fs.UploadFile(file, path); err == nil {
+
+			var err error
+			if dedup {
+				// store only a reference to the content-addressed blob
+				err = fs.UploadFileDedup(file, path)
+			} else if r, ok := file.Reader.(*filesystem.ObjectReader); ok {
+				// already stored file (eg. direct upload)
+				err = fs.Copy(r.Key, path)
+			} else {
+				err = fs.UploadFile(file, path)
+			}
+
+			if err == nil {
 				// keep track of the already uploaded file
 				uploaded = append(uploaded, path)
+
+				form.collectFileMetadata(fieldKey, file)
 			} else {
 				// store the upload error
 				uploadErrors = append(uploadErrors, fmt.Errorf("file %d: %v", i, err))
@@ -852,7 +958,7 @@ func (form *RecordUpsert) processFilesToUpload() error {
 
 	if len(uploadErrors) > 0 {
 		// cleanup - try to delete the successfully uploaded files (if any)
-		form.deleteFilesByNamesList(uploaded)
+		form.deleteFilesByNamesList(dao, uploaded)
 
 		return fmt.Errorf("failed to upload all files: %v", uploadErrors)
 	}
@@ -860,14 +966,215 @@ func (form *RecordUpsert) processFilesToUpload() error {
 	return nil
 }
 
+// collectFileMetadata extracts the metadata of the provided uploaded file
+// so that it could be persisted after the record save.
+//
+// The metadata extraction is best-effort and failures are only logged
+// (the missing metadata could be later restored with the "files metadata" command).
+func (form *RecordUpsert) collectFileMetadata(fieldKey string, file *filesystem.File) {
+	meta, err := filesystem.ExtractMetadata(file)
+	if err != nil {
+		form.app.Logger().Warn(
+			"Failed to extract file metadata",
+			slog.String("file", file.Name),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	form.filesMetadata = append(form.filesMetadata, &models.FileMetadata{
+		CollectionId: form.record.Collection().Id,
+		RecordId:     form.record.Id,
+		Field:        fieldKey,
+		Name:         file.Name,
+		Size:         meta.Size,
+		MimeType:     meta.MimeType,
+		Width:        meta.Width,
+		Height:       meta.Height,
+		Duration:     meta.Duration,
+		Checksum:     meta.Checksum,
+	})
+}
+
+// processFilesMetadata persists the metadata of the uploaded files
+// and deletes the metadata of the files marked for deletion.
+//
+// The files metadata is internal bookkeeping data and it is
+// intentionally persisted without triggering the model hooks.
+func (form *RecordUpsert) processFilesMetadata(txDao *daos.Dao) error {
+	if len(form.filesToDelete) == 0 && len(form.filesMetadata) == 0 {
+		return nil // nothing to sync
+	}
+
+	dao := daos.NewMultiDB(txDao.ConcurrentDB(), txDao.NonconcurrentDB())
+
+	if len(form.filesToDelete) > 0 {
+		if err := dao.DeleteFilesMetadata(form.record.Collection().Id, form.record.Id, form.filesToDelete...); err != nil {
+			return err
+		}
+	}
+
+	for _, meta := range form.filesMetadata {
+		if err := dao.SaveFileMetadata(meta); err != nil {
+			return err
+		}
+	}
+
+	form.filesMetadata = nil
+
+	return nil
+}
+
+// processFilesToScan scans the new files with the app file scanner (if enabled).
+//
+// Depending on the app settings, the infected files either fail the
+// form submit with a validation error or are moved to the local
+// quarantine dir and removed from the record (the submit still fails
+// if this leaves a required file field empty).
+func (form *RecordUpsert) processFilesToScan() error {
+	form.fileScans = nil
+
+	if len(form.filesToUpload) == 0 {
+		return nil // no parsed file fields
+	}
+
+	fileScanner, err := form.app.NewFileScanner()
+	if err != nil {
+		return err
+	}
+	if fileScanner == nil {
+		return nil // disabled
+	}
+
+	action := form.app.Settings().FileScan.Action
+
+	validationErrs := validation.Errors{}
+
+	for fieldKey, files := range form.filesToUpload {
+		clean := make([]*filesystem.File, 0, len(files))
+
+		for _, file := range files {
+			result, err := scanFile(fileScanner, file)
+			if err != nil {
+				return fmt.Errorf("failed to scan file %q: %w", file.OriginalName, err)
+			}
+
+			scan := &RecordFileScan{
+				Field:     fieldKey,
+				Name:      file.Name,
+				Infected:  result.Infected,
+				Signature: result.Signature,
+			}
+			form.fileScans = append(form.fileScans, scan)
+
+			if !result.Infected {
+				clean = append(clean, file)
+				continue
+			}
+
+			scan.Action = action
+
+			if action == settings.FileScanActionQuarantine {
+				if err := form.quarantineFile(file); err != nil {
+					return err
+				}
+
+				form.record.Set(fieldKey, list.SubtractSlice(
+					form.record.GetStringSlice(fieldKey),
+					[]string{file.Name},
+				))
+			} else {
+				validationErrs[fieldKey] = validation.NewError(
+					"validation_infected_file",
+					fmt.Sprintf("The file %q is infected (%s).", file.OriginalName, result.Signature),
+				)
+			}
+		}
+
+		form.filesToUpload[fieldKey] = clean
+
+		// reject the submit if the quarantined files leave the required field empty
+		if _, ok := validationErrs[fieldKey]; !ok && len(clean) < len(files) {
+			field := form.record.Collection().Schema.GetFieldByName(fieldKey)
+			if field != nil && field.Required && len(form.record.GetStringSlice(fieldKey)) == 0 {
+				validationErrs[fieldKey] = validation.NewError(
+					"validation_infected_file",
+					"The uploaded files are infected and were quarantined.",
+				)
+			}
+		}
+	}
+
+	if len(validationErrs) > 0 {
+		return validationErrs
+	}
+
+	return nil
+}
+
+// quarantineFile stores the provided file in the local quarantine dir
+// (outside of the app storage to prevent serving it).
+func (form *RecordUpsert) quarantineFile(file *filesystem.File) error {
+	fs, err := filesystem.NewLocal(filepath.Join(form.app.DataDir(), core.LocalQuarantineDirName))
+	if err != nil {
+		return err
+	}
+	defer fs.Close()
+
+	return fs.UploadFile(file, form.record.BaseFilesPath()+"/"+file.Name)
+}
+
+func scanFile(fileScanner scanner.Scanner, file *filesystem.File) (*scanner.Result, error) {
+	f, err := file.Reader.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return fileScanner.Scan(context.Background(), f)
+}
+
 func (form *RecordUpsert) processFilesToDelete() (err error) {
-	form.filesToDelete, err = form.deleteFilesByNamesList(form.filesToDelete)
+	form.filesToDelete, err = form.deleteFilesByNamesList(form.dao, form.filesToDelete)
 	return
 }
 
+// processUploadedObjectsToDelete deletes the original storage objects
+// of the attached direct upload files since they were already copied
+// to the record files dir.
+func (form *RecordUpsert) processUploadedObjectsToDelete() error {
+	keys := []string{}
+	for _, files := range form.filesToUpload {
+		for _, file := range files {
+			if r, ok := file.Reader.(*filesystem.ObjectReader); ok {
+				keys = append(keys, r.Key)
+			}
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil // nothing to delete
+	}
+
+	fs, err := form.newFilesystem(form.dao)
+	if err != nil {
+		return err
+	}
+	defer fs.Close()
+
+	var deleteErrors []error
+	for _, key := range keys {
+		if err := fs.Delete(key); err != nil {
+			deleteErrors = append(deleteErrors, err)
+		}
+	}
+
+	return errors.Join(deleteErrors...)
+}
+
 // deleteFiles deletes a list of record files by their names.
 // Returns the failed/remaining files.
-func (form *RecordUpsert) deleteFilesByNamesList(filenames []string) ([]string, error) {
+func (form *RecordUpsert) deleteFilesByNamesList(dao *daos.Dao, filenames []string) ([]string, error) {
 	if len(filenames) == 0 {
 		return filenames, nil // nothing to delete
 	}
@@ -876,7 +1183,7 @@ func (form *RecordUpsert) deleteFilesByNamesList(filenames []string) ([]string,
 		return filenames, errors.New("the record doesn't have an id")
 	}
 
-	fs, err := form.app.NewFilesystem()
+	fs, err := form.newFilesystem(dao)
 	if err != nil {
 		return filenames, err
 	}
@@ -907,6 +1214,19 @@ func (form *RecordUpsert) deleteFilesByNamesList(filenames []string) ([]string,
 	return filenames, nil
 }
 
+// newFilesystem creates a new app filesystem instance that
+// tracks the deduplicated files blob references with the provided dao.
+func (form *RecordUpsert) newFilesystem(dao *daos.Dao) (*filesystem.System, error) {
+	fs, err := form.app.NewFilesystem()
+	if err != nil {
+		return nil, err
+	}
+
+	fs.SetDedupIndex(dao)
+
+	return fs, nil
+}
+
 // prepareError parses the provided error and tries to return
 // user-friendly validation error(s).
 func (form *RecordUpsert) prepareError(err error) error {
@@ -17,10 +17,12 @@ import (
 	"github.com/AlperRehaYAZGAN/postgresbase/forms"
 	"github.com/AlperRehaYAZGAN/postgresbase/models"
 	"github.com/AlperRehaYAZGAN/postgresbase/models/schema"
+	"github.com/AlperRehaYAZGAN/postgresbase/models/settings"
 	"github.com/AlperRehaYAZGAN/postgresbase/tests"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/filesystem"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/list"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/rest"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/security"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/types"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/labstack/echo/v5"
@@ -1225,3 +1227,317 @@ func TestRecordUpsertUploadFailure(t *testing.T) {
 		}
 	}
 }
+
+func TestRecordUpsertFileScan(t *testing.T) {
+	server, err := tests.NewTestClamd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	scenarios := []struct {
+		name              string
+		action            string
+		requiredField     bool
+		expectError       bool
+		expectQuarantined bool
+	}{
+		{"reject", settings.FileScanActionReject, false, true, false},
+		{"quarantine", settings.FileScanActionQuarantine, false, false, true},
+		{"quarantine all files of a required field", settings.FileScanActionQuarantine, true, true, true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			app, _ := tests.NewTestApp()
+			defer app.Cleanup()
+
+			app.Settings().FileScan.Enabled = true
+			app.Settings().FileScan.Address = server.Address()
+			app.Settings().FileScan.Action = s.action
+
+			collection, err := app.Dao().FindCollectionByNameOrId("demo3")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			clean, _ := filesystem.NewFileFromBytes([]byte("test"), "clean.txt")
+			infected, _ := filesystem.NewFileFromBytes([]byte(tests.EicarTestSignature), "infected.txt")
+
+			files := []*filesystem.File{clean, infected}
+
+			if s.requiredField {
+				collection.Schema.GetFieldByName("files").Required = true
+				if err := app.Dao().SaveCollection(collection); err != nil {
+					t.Fatal(err)
+				}
+
+				files = []*filesystem.File{infected}
+			}
+
+			record := models.NewRecord(collection)
+
+			form := forms.NewRecordUpsert(app, record)
+			form.LoadData(map[string]any{"title": "scan_test"})
+			form.AddFiles("files", files...)
+
+			submitErr := form.Submit()
+
+			hasErr := submitErr != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, submitErr)
+			}
+
+			if hasErr {
+				errs, ok := submitErr.(validation.Errors)
+				if !ok || errs["files"] == nil {
+					t.Fatalf("Expected files validation error, got %v", submitErr)
+				}
+			}
+
+			scans := form.FileScans()
+			if len(scans) != len(files) {
+				t.Fatalf("Expected %d file scans, got %v", len(files), scans)
+			}
+			for _, scan := range scans {
+				isInfected := scan.Name == infected.Name
+				if scan.Infected != isInfected {
+					t.Fatalf("Expected %q infected %v, got %v", scan.Name, isInfected, scan.Infected)
+				}
+				if isInfected && (scan.Signature != "Eicar-Signature" || scan.Action != s.action) {
+					t.Fatalf("Unexpected infected file scan %+v", scan)
+				}
+			}
+
+			quarantinePath := filepath.Join(app.DataDir(), core.LocalQuarantineDirName, record.BaseFilesPath(), infected.Name)
+			if _, err := os.Stat(quarantinePath); (err == nil) != s.expectQuarantined {
+				t.Fatalf("Expected quarantined %v, got %v", s.expectQuarantined, err)
+			}
+
+			if s.expectError {
+				return
+			}
+
+			if files := record.GetStringSlice("files"); len(files) != 1 || files[0] != clean.Name {
+				t.Fatalf("Expected only the clean file to be attached, got %v", files)
+			}
+
+			if !hasRecordFile(app, record, clean.Name) {
+				t.Fatalf("Missing clean file %q", clean.Name)
+			}
+
+			if hasRecordFile(app, record, infected.Name) {
+				t.Fatalf("Expected infected file %q to not be stored", infected.Name)
+			}
+		})
+	}
+}
+
+func TestRecordUpsertFilesDedup(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	collection.Options["dedupFiles"] = true
+
+	file1, _ := filesystem.NewFileFromBytes([]byte("test"), "test1.txt")
+	file2, _ := filesystem.NewFileFromBytes([]byte("test"), "test2.txt")
+
+	record := models.NewRecord(collection)
+
+	form := forms.NewRecordUpsert(app, record)
+	form.LoadData(map[string]any{"title": "dedup_test"})
+	form.AddFiles("files", file1, file2)
+
+	if err := form.Submit(); err != nil {
+		t.Fatalf("Failed to submit the form: %v", err)
+	}
+
+	fs, err := app.NewFilesystem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	for _, name := range record.GetStringSlice("files") {
+		if isDedup, err := fs.IsDedup(record.BaseFilesPath() + "/" + name); err != nil || !isDedup {
+			t.Fatalf("Expected %q to be a dedup file, got %v (%v)", name, isDedup, err)
+		}
+	}
+
+	objects, err := fs.List(filesystem.DedupBlobsPrefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(objects) != 1 {
+		t.Fatalf("Expected 1 blob object, got %d", len(objects))
+	}
+
+	// deleting the files releases the blob only after its last reference
+	for i, name := range record.GetStringSlice("files") {
+		if err := fs.Delete(record.BaseFilesPath() + "/" + name); err != nil {
+			t.Fatal(err)
+		}
+
+		objects, err := fs.List(filesystem.DedupBlobsPrefix)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := 1
+		if i == 1 {
+			expected = 0
+		}
+
+		if len(objects) != expected {
+			t.Fatalf("[%d] Expected %d blob objects, got %d", i, expected, len(objects))
+		}
+	}
+}
+
+func TestRecordUpsertFilesMetadata(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file1, _ := filesystem.NewFileFromBytes([]byte("test"), "test1.txt")
+	file2, _ := filesystem.NewFileFromBytes([]byte("other"), "test2.txt")
+
+	record := models.NewRecord(collection)
+
+	form := forms.NewRecordUpsert(app, record)
+	form.LoadData(map[string]any{"title": "metadata_test"})
+	form.AddFiles("files", file1, file2)
+
+	if err := form.Submit(); err != nil {
+		t.Fatalf("Failed to submit the form: %v", err)
+	}
+
+	items, err := app.Dao().FindFilesMetadataByRecords(collection.Id, record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 files metadata, got %d", len(items))
+	}
+
+	for _, item := range items {
+		if item.Field != "files" {
+			t.Fatalf("Expected field %q, got %q", "files", item.Field)
+		}
+
+		var expectedSize int64
+		switch item.Name {
+		case file1.Name:
+			expectedSize = 4
+		case file2.Name:
+			expectedSize = 5
+		default:
+			t.Fatalf("Unexpected file metadata %q", item.Name)
+		}
+
+		if item.Size != expectedSize {
+			t.Fatalf("[%s] Expected size %d, got %d", item.Name, expectedSize, item.Size)
+		}
+
+		if item.MimeType != "text/plain; charset=utf-8" || item.Checksum == "" {
+			t.Fatalf("[%s] Unexpected metadata %v", item.Name, item)
+		}
+	}
+
+	// remove one of the files
+	form2 := forms.NewRecordUpsert(app, record)
+	form2.RemoveFiles("files", file1.Name)
+
+	if err := form2.Submit(); err != nil {
+		t.Fatalf("Failed to submit the form: %v", err)
+	}
+
+	items, err = app.Dao().FindFilesMetadataByRecords(collection.Id, record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 1 || items[0].Name != file2.Name {
+		t.Fatalf("Expected only the %q file metadata, got %v", file2.Name, items)
+	}
+
+	// delete the record
+	if err := app.Dao().DeleteRecord(record); err != nil {
+		t.Fatal(err)
+	}
+
+	items, err = app.Dao().FindFilesMetadataByRecords(collection.Id, record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 0 {
+		t.Fatalf("Expected the record files metadata to be deleted, got %v", items)
+	}
+}
+
+func TestRecordUpsertFilesMetadataUploaded(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs, err := app.NewFilesystem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	// direct (presigned or tus) upload
+	uploadKey := collection.BaseUploadsPath() + "/" + security.RandomString(32) + "/uploaded.txt"
+	if err := fs.Upload([]byte("uploaded"), uploadKey); err != nil {
+		t.Fatal(err)
+	}
+
+	record := models.NewRecord(collection)
+
+	form := forms.NewRecordUpsert(app, record)
+	if err := form.LoadData(map[string]any{
+		"title": "uploaded_metadata_test",
+		"files": []string{uploadKey},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := form.Submit(); err != nil {
+		t.Fatalf("Failed to submit the form: %v", err)
+	}
+
+	items, err := app.Dao().FindFilesMetadataByRecords(collection.Id, record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := record.GetStringSlice("files")
+	if len(items) != 1 || len(names) != 1 || items[0].Name != names[0] {
+		t.Fatalf("Expected the uploaded file metadata, got %v (%v)", items, names)
+	}
+
+	if items[0].Size != 8 || items[0].Checksum == "" {
+		t.Fatalf("Unexpected metadata %v", items[0])
+	}
+}
@@ -0,0 +1,192 @@
+package forms
+
+import (
+	"errors"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/core"
+	"github.com/AlperRehaYAZGAN/postgresbase/daos"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/security"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/types"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/webauthn"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// RecordWebAuthnLoginData defines the data passed to the [RecordWebAuthnLogin] interceptors.
+type RecordWebAuthnLoginData struct {
+	Record     *models.Record
+	Credential *models.WebAuthnCredential
+}
+
+// RecordWebAuthnLogin is an auth record WebAuthn
+// resident credential (aka. passkey) login form.
+type RecordWebAuthnLogin struct {
+	app        core.App
+	dao        *daos.Dao
+	collection *models.Collection
+	config     webauthn.Config
+
+	Credential WebAuthnCredentialData `form:"credential" json:"credential"`
+}
+
+// NewRecordWebAuthnLogin creates a new [RecordWebAuthnLogin] form
+// initialized with from the provided [core.App], [models.Collection]
+// and relying party config instances.
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewRecordWebAuthnLogin(
+	app core.App,
+	collection *models.Collection,
+	config webauthn.Config,
+) *RecordWebAuthnLogin {
+	return &RecordWebAuthnLogin{
+		app:        app,
+		dao:        app.Dao(),
+		collection: collection,
+		config:     config,
+	}
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *RecordWebAuthnLogin) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// RequestOptions generates a new signed login challenge and
+// returns the related client credential request options.
+func (form *RecordWebAuthnLogin) RequestOptions() (*webauthn.RequestOptions, error) {
+	challenge := webauthn.NewSignedChallenge(form.challengeSecret(), form.challengeOwner(), webauthn.DefaultChallengeDuration)
+
+	return form.config.NewRequestOptions(challenge, webauthn.DefaultChallengeDuration), nil
+}
+
+// Validate makes the form validatable by implementing [validation.Validatable] interface.
+func (form *RecordWebAuthnLogin) Validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.Credential),
+	)
+}
+
+// Submit validates and submits the form.
+// On success returns the authorized record model.
+//
+// You can optionally provide a list of InterceptorFunc to
+// further modify the form behavior before persisting it.
+func (form *RecordWebAuthnLogin) Submit(interceptors ...InterceptorFunc[*RecordWebAuthnLoginData]) (*models.Record, error) {
+	if err := form.Validate(); err != nil {
+		return nil, err
+	}
+
+	invalidErr := errors.New("Invalid WebAuthn credential.")
+
+	clientDataJSON, err := webauthn.DecodeBase64(form.Credential.Response.ClientDataJSON)
+	if err != nil {
+		return nil, invalidErr
+	}
+
+	rawAuthData, err := webauthn.DecodeBase64(form.Credential.Response.AuthenticatorData)
+	if err != nil {
+		return nil, invalidErr
+	}
+
+	signature, err := webauthn.DecodeBase64(form.Credential.Response.Signature)
+	if err != nil {
+		return nil, invalidErr
+	}
+
+	rawId, err := webauthn.DecodeBase64(form.Credential.RawId)
+	if err != nil || len(rawId) == 0 {
+		return nil, invalidErr
+	}
+
+	clientData, err := webauthn.ParseClientData(clientDataJSON)
+	if err != nil {
+		return nil, invalidErr
+	}
+
+	challengeExpires, err := webauthn.ParseSignedChallenge(clientData.Challenge, form.challengeSecret(), form.challengeOwner())
+	if err != nil {
+		return nil, invalidErr
+	}
+
+	credential, err := form.dao.FindWebAuthnCredentialByCredentialId(form.collection.Id, webauthn.EncodeBase64(rawId))
+	if err != nil {
+		return nil, invalidErr
+	}
+
+	authRecord, err := form.dao.FindRecordById(form.collection.Id, credential.RecordId)
+	if err != nil {
+		return nil, invalidErr
+	}
+
+	// the user handle is optional for the non-discoverable credentials
+	if form.Credential.Response.UserHandle != "" {
+		userHandle, err := webauthn.DecodeBase64(form.Credential.Response.UserHandle)
+		if err != nil || string(userHandle) != authRecord.Id {
+			return nil, invalidErr
+		}
+	}
+
+	publicKey, err := webauthn.DecodeBase64(credential.PublicKey)
+	if err != nil {
+		return nil, invalidErr
+	}
+
+	authData, err := form.config.VerifyAssertion(clientData.Challenge, publicKey, clientDataJSON, rawAuthData, signature)
+	if err != nil {
+		return nil, invalidErr
+	}
+
+	// a non-increasing signature counter could indicate a cloned authenticator
+	// (authenticators that don't support counters always return 0)
+	newSignCount := int64(authData.SignCount)
+	if (newSignCount != 0 || credential.SignCount != 0) && newSignCount <= credential.SignCount {
+		return nil, invalidErr
+	}
+
+	if err := form.useChallenge(clientData.Challenge, challengeExpires); err != nil {
+		return nil, invalidErr
+	}
+
+	data := &RecordWebAuthnLoginData{
+		Record:     authRecord,
+		Credential: credential,
+	}
+
+	interceptorsErr := runInterceptors(data, func(data *RecordWebAuthnLoginData) error {
+		authRecord = data.Record
+		if authRecord == nil {
+			return invalidErr
+		}
+
+		credential.SignCount = newSignCount
+
+		return form.dao.SaveWebAuthnCredential(credential)
+	}, interceptors...)
+
+	if interceptorsErr != nil {
+		return nil, interceptorsErr
+	}
+
+	return authRecord, nil
+}
+
+func (form *RecordWebAuthnLogin) challengeOwner() string {
+	return "login:" + form.collection.Id
+}
+
+func (form *RecordWebAuthnLogin) challengeSecret() string {
+	return form.app.Settings().RecordAuthToken.Secret
+}
+
+// useChallenge marks the already verified challenge as used to prevent
+// replaying the same assertion until the challenge expiration.
+func (form *RecordWebAuthnLogin) useChallenge(challenge string, expires time.Time) error {
+	model := &models.WebAuthnChallenge{CollectionId: form.collection.Id}
+	model.Id = security.SHA256(challenge)
+	model.Expires, _ = types.ParseDateTime(expires)
+
+	return form.dao.UseWebAuthnChallenge(model)
+}
@@ -0,0 +1,157 @@
+package forms_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/forms"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/tests"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/webauthn"
+)
+
+func TestRecordWebAuthnLoginRequestOptions(t *testing.T) {
+	t.Parallel()
+
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	authCollection, err := testApp.Dao().FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := forms.NewRecordWebAuthnLogin(testApp, authCollection, testWebAuthnConfig)
+
+	options, err := form.RequestOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if options.RPId != testWebAuthnConfig.RPId {
+		t.Fatalf("Expected rpId %q, got %q", testWebAuthnConfig.RPId, options.RPId)
+	}
+
+	if _, err := webauthn.ParseSignedChallenge(options.Challenge, testApp.Settings().RecordAuthToken.Secret, "login:"+authCollection.Id); err != nil {
+		t.Fatalf("Expected a signed login challenge, got %v", err)
+	}
+
+	// no pending challenges limit
+	for i := 0; i < 5; i++ {
+		if _, err := form.RequestOptions(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRecordWebAuthnLoginSubmit(t *testing.T) {
+	t.Parallel()
+
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	authCollection, err := testApp.Dao().FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authRecord, err := testApp.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authenticator, err := tests.NewTestAuthenticator("localhost", "http://localhost:8090")
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticator.UserHandle = []byte(authRecord.Id)
+
+	credential := &models.WebAuthnCredential{
+		CollectionId: authCollection.Id,
+		RecordId:     authRecord.Id,
+		CredentialId: webauthn.EncodeBase64(authenticator.CredentialId),
+		PublicKey:    webauthn.EncodeBase64(authenticator.PublicKey()),
+	}
+	if err := testApp.Dao().SaveWebAuthnCredential(credential); err != nil {
+		t.Fatal(err)
+	}
+
+	var lastChallenge string
+
+	loginWithChallenge := func(challenge string, userHandle []byte) (*models.Record, error) {
+		form := forms.NewRecordWebAuthnLogin(testApp, authCollection, testWebAuthnConfig)
+
+		authenticator.UserHandle = userHandle
+		raw, err := authenticator.Login(challenge)
+		if err != nil {
+			t.Fatal(err)
+		}
+		loadTestWebAuthnCredential(t, raw, &form.Credential)
+
+		return form.Submit()
+	}
+
+	login := func(userHandle []byte) (*models.Record, error) {
+		options, err := forms.NewRecordWebAuthnLogin(testApp, authCollection, testWebAuthnConfig).RequestOptions()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lastChallenge = options.Challenge
+
+		return loginWithChallenge(options.Challenge, userHandle)
+	}
+
+	// mismatched user handle
+	// ---
+	if _, err := login([]byte("invalid")); err == nil {
+		t.Fatal("Expected error for mismatched user handle")
+	}
+
+	// successful login
+	// ---
+	record, err := login([]byte(authRecord.Id))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if record.Id != authRecord.Id {
+		t.Fatalf("Expected record %q, got %q", authRecord.Id, record.Id)
+	}
+
+	updated, err := testApp.Dao().FindWebAuthnCredentialByRecordAndId(authRecord, credential.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if updated.SignCount != int64(authenticator.SignCount) {
+		t.Fatalf("Expected sign count %d, got %d", authenticator.SignCount, updated.SignCount)
+	}
+
+	// replayed challenge (with valid signature counter)
+	// ---
+	if _, err := loginWithChallenge(lastChallenge, []byte(authRecord.Id)); err == nil {
+		t.Fatal("Expected error for already used challenge")
+	}
+
+	// non-increasing signature counter
+	// ---
+	authenticator.SignCount = 0
+	if _, err := login([]byte(authRecord.Id)); err == nil {
+		t.Fatal("Expected error for non-increasing signature counter")
+	}
+
+	// challenge issued for a different collection
+	// ---
+	otherChallenge := webauthn.NewSignedChallenge(testApp.Settings().RecordAuthToken.Secret, "login:other", time.Minute)
+	if _, err := loginWithChallenge(otherChallenge, []byte(authRecord.Id)); err == nil {
+		t.Fatal("Expected error for challenge issued for a different collection")
+	}
+
+	// expired challenge
+	// ---
+	expiredChallenge := webauthn.NewSignedChallenge(testApp.Settings().RecordAuthToken.Secret, "login:"+authCollection.Id, -time.Second)
+	if _, err := loginWithChallenge(expiredChallenge, []byte(authRecord.Id)); err == nil {
+		t.Fatal("Expected error for expired challenge")
+	}
+}
@@ -0,0 +1,217 @@
+package forms
+
+import (
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/core"
+	"github.com/AlperRehaYAZGAN/postgresbase/daos"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/security"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/types"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/webauthn"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// WebAuthnCredentialData defines the client PublicKeyCredential
+// data (with base64url encoded binary fields) of a WebAuthn ceremony.
+type WebAuthnCredentialData struct {
+	Id       string                     `form:"id" json:"id"`
+	RawId    string                     `form:"rawId" json:"rawId"`
+	Type     string                     `form:"type" json:"type"`
+	Response WebAuthnCredentialResponse `form:"response" json:"response"`
+}
+
+// WebAuthnCredentialResponse defines the client authenticator
+// response (with base64url encoded binary fields) of a WebAuthn ceremony.
+type WebAuthnCredentialResponse struct {
+	ClientDataJSON    string `form:"clientDataJSON" json:"clientDataJSON"`
+	AttestationObject string `form:"attestationObject" json:"attestationObject"`
+	AuthenticatorData string `form:"authenticatorData" json:"authenticatorData"`
+	Signature         string `form:"signature" json:"signature"`
+	UserHandle        string `form:"userHandle" json:"userHandle"`
+}
+
+// Validate makes [WebAuthnCredentialData] validatable by implementing [validation.Validatable] interface.
+func (d WebAuthnCredentialData) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.RawId, validation.Required),
+		validation.Field(&d.Type, validation.Required, validation.In(webauthn.CredentialTypePublicKey)),
+		validation.Field(&d.Response),
+	)
+}
+
+// Validate makes [WebAuthnCredentialResponse] validatable by implementing [validation.Validatable] interface.
+func (r WebAuthnCredentialResponse) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.ClientDataJSON, validation.Required),
+	)
+}
+
+// RecordWebAuthnRegistration is an auth record WebAuthn
+// credential (aka. passkey) registration form.
+type RecordWebAuthnRegistration struct {
+	app        core.App
+	dao        *daos.Dao
+	record     *models.Record
+	config     webauthn.Config
+
+	Name       string                 `form:"name" json:"name"`
+	Credential WebAuthnCredentialData `form:"credential" json:"credential"`
+}
+
+// NewRecordWebAuthnRegistration creates a new [RecordWebAuthnRegistration]
+// form initialized with from the provided [core.App], [models.Record]
+// and relying party config instances.
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewRecordWebAuthnRegistration(
+	app core.App,
+	authRecord *models.Record,
+	config webauthn.Config,
+) *RecordWebAuthnRegistration {
+	return &RecordWebAuthnRegistration{
+		app:    app,
+		dao:    app.Dao(),
+		record: authRecord,
+		config: config,
+	}
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *RecordWebAuthnRegistration) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// CreationOptions generates a new signed registration challenge and
+// returns the related client credential creation options.
+func (form *RecordWebAuthnRegistration) CreationOptions() (*webauthn.CreationOptions, error) {
+	credentials, err := form.dao.FindAllWebAuthnCredentialsByRecord(form.record)
+	if err != nil {
+		return nil, err
+	}
+
+	excludeIds := make([]string, 0, len(credentials))
+	for _, c := range credentials {
+		excludeIds = append(excludeIds, c.CredentialId)
+	}
+
+	challenge := webauthn.NewSignedChallenge(form.challengeSecret(), form.challengeOwner(), webauthn.DefaultChallengeDuration)
+
+	name := form.record.Email()
+	if name == "" {
+		name = form.record.Username()
+	}
+
+	user := webauthn.User{
+		Id:          webauthn.EncodeBase64([]byte(form.record.Id)),
+		Name:        name,
+		DisplayName: name,
+	}
+
+	return form.config.NewCreationOptions(challenge, webauthn.DefaultChallengeDuration, user, excludeIds), nil
+}
+
+// Validate makes the form validatable by implementing [validation.Validatable] interface.
+func (form *RecordWebAuthnRegistration) Validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.Name, validation.Length(0, 100)),
+		validation.Field(&form.Credential),
+	)
+}
+
+// Submit validates and submits the form.
+// On success returns the newly created credential model.
+//
+// You can optionally provide a list of InterceptorFunc to
+// further modify the form behavior before persisting it.
+func (form *RecordWebAuthnRegistration) Submit(interceptors ...InterceptorFunc[*models.WebAuthnCredential]) (*models.WebAuthnCredential, error) {
+	if err := form.Validate(); err != nil {
+		return nil, err
+	}
+
+	invalidErr := errors.New("Invalid or expired WebAuthn registration.")
+
+	clientDataJSON, err := webauthn.DecodeBase64(form.Credential.Response.ClientDataJSON)
+	if err != nil {
+		return nil, invalidErr
+	}
+
+	attestationObject, err := webauthn.DecodeBase64(form.Credential.Response.AttestationObject)
+	if err != nil {
+		return nil, invalidErr
+	}
+
+	clientData, err := webauthn.ParseClientData(clientDataJSON)
+	if err != nil {
+		return nil, invalidErr
+	}
+
+	challengeExpires, err := webauthn.ParseSignedChallenge(clientData.Challenge, form.challengeSecret(), form.challengeOwner())
+	if err != nil {
+		return nil, invalidErr
+	}
+
+	authData, err := form.config.VerifyRegistration(clientData.Challenge, clientDataJSON, attestationObject)
+	if err != nil {
+		return nil, invalidErr
+	}
+
+	credentialId := webauthn.EncodeBase64(authData.CredentialId)
+
+	existing, _ := form.dao.FindWebAuthnCredentialByCredentialId(form.record.Collection().Id, credentialId)
+	if existing != nil {
+		return nil, errors.New("The WebAuthn credential is already registered.")
+	}
+
+	if err := form.useChallenge(clientData.Challenge, challengeExpires); err != nil {
+		return nil, invalidErr
+	}
+
+	credential := &models.WebAuthnCredential{
+		CollectionId: form.record.Collection().Id,
+		RecordId:     form.record.Id,
+		Name:         form.Name,
+		CredentialId: credentialId,
+		PublicKey:    webauthn.EncodeBase64(authData.PublicKey),
+		SignCount:    int64(authData.SignCount),
+		AAGUID:       hex.EncodeToString(authData.AAGUID),
+	}
+
+	if credential.Name == "" {
+		credential.Name = "Passkey"
+	}
+
+	interceptorsErr := runInterceptors(credential, func(m *models.WebAuthnCredential) error {
+		credential = m
+		return form.dao.SaveWebAuthnCredential(m)
+	}, interceptors...)
+
+	if interceptorsErr != nil {
+		return nil, interceptorsErr
+	}
+
+	return credential, nil
+}
+
+func (form *RecordWebAuthnRegistration) challengeOwner() string {
+	return "register:" + form.record.Collection().Id + ":" + form.record.Id
+}
+
+// challengeSecret returns the registration challenge signing secret
+// (the record token key invalidates the pending challenges on password change).
+func (form *RecordWebAuthnRegistration) challengeSecret() string {
+	return form.record.TokenKey() + form.app.Settings().RecordAuthToken.Secret
+}
+
+// useChallenge marks the already verified challenge as used
+// to prevent its reuse until the challenge expiration.
+func (form *RecordWebAuthnRegistration) useChallenge(challenge string, expires time.Time) error {
+	model := &models.WebAuthnChallenge{CollectionId: form.record.Collection().Id}
+	model.Id = security.SHA256(challenge)
+	model.Expires, _ = types.ParseDateTime(expires)
+
+	return form.dao.UseWebAuthnChallenge(model)
+}
@@ -0,0 +1,231 @@
+package forms_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/forms"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/tests"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/webauthn"
+)
+
+var testWebAuthnConfig = webauthn.Config{
+	RPId:    "localhost",
+	RPName:  "test",
+	Origins: []string{"http://localhost:8090"},
+}
+
+// loadTestWebAuthnCredential loads the PublicKeyCredential JSON map
+// (eg. generated by [tests.TestAuthenticator]) into the provided form data.
+func loadTestWebAuthnCredential(t *testing.T, raw map[string]any, data *forms.WebAuthnCredentialData) {
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := json.Unmarshal(encoded, data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecordWebAuthnRegistrationCreationOptions(t *testing.T) {
+	t.Parallel()
+
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	authRecord, err := testApp.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := forms.NewRecordWebAuthnRegistration(testApp, authRecord, testWebAuthnConfig)
+
+	options, err := form.CreationOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if options.User.Id != webauthn.EncodeBase64([]byte(authRecord.Id)) {
+		t.Fatalf("Expected the user handle to be the record id, got %q", options.User.Id)
+	}
+
+	if options.User.Name != authRecord.Email() {
+		t.Fatalf("Expected user name %q, got %q", authRecord.Email(), options.User.Name)
+	}
+
+	secret := authRecord.TokenKey() + testApp.Settings().RecordAuthToken.Secret
+	owner := "register:" + authRecord.Collection().Id + ":" + authRecord.Id
+	if _, err := webauthn.ParseSignedChallenge(options.Challenge, secret, owner); err != nil {
+		t.Fatalf("Expected a signed registration challenge, got %v", err)
+	}
+
+	// changing the record token key invalidates the pending challenges
+	authRecord.RefreshTokenKey()
+	if _, err := webauthn.ParseSignedChallenge(options.Challenge, authRecord.TokenKey()+testApp.Settings().RecordAuthToken.Secret, owner); err == nil {
+		t.Fatal("Expected the challenge to be invalidated after token key change")
+	}
+}
+
+func TestRecordWebAuthnRegistrationValidate(t *testing.T) {
+	t.Parallel()
+
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	authRecord, err := testApp.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name        string
+		data        forms.WebAuthnCredentialData
+		expectError bool
+	}{
+		{"empty data", forms.WebAuthnCredentialData{}, true},
+		{
+			"invalid type",
+			forms.WebAuthnCredentialData{
+				RawId:    "test",
+				Type:     "invalid",
+				Response: forms.WebAuthnCredentialResponse{ClientDataJSON: "test"},
+			},
+			true,
+		},
+		{
+			"missing client data",
+			forms.WebAuthnCredentialData{
+				RawId: "test",
+				Type:  webauthn.CredentialTypePublicKey,
+			},
+			true,
+		},
+		{
+			"valid data",
+			forms.WebAuthnCredentialData{
+				RawId:    "test",
+				Type:     webauthn.CredentialTypePublicKey,
+				Response: forms.WebAuthnCredentialResponse{ClientDataJSON: "test"},
+			},
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			form := forms.NewRecordWebAuthnRegistration(testApp, authRecord, testWebAuthnConfig)
+			form.Credential = s.data
+
+			err := form.Validate()
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr to be %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+		})
+	}
+}
+
+func TestRecordWebAuthnRegistrationSubmit(t *testing.T) {
+	t.Parallel()
+
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	authRecord, err := testApp.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherRecord, err := testApp.Dao().FindAuthRecordByEmail("users", "test2@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authenticator, err := tests.NewTestAuthenticator("localhost", "http://localhost:8090")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// challenge issued for a different auth record
+	// ---
+	otherChallenge := webauthn.NewSignedChallenge(
+		otherRecord.TokenKey()+testApp.Settings().RecordAuthToken.Secret,
+		"register:"+otherRecord.Collection().Id+":"+otherRecord.Id,
+		time.Minute,
+	)
+
+	form := forms.NewRecordWebAuthnRegistration(testApp, authRecord, testWebAuthnConfig)
+	loadTestWebAuthnCredential(t, authenticator.Register(otherChallenge), &form.Credential)
+	if _, err := form.Submit(); err == nil {
+		t.Fatal("Expected error for challenge issued for a different auth record")
+	}
+
+	// successful registration
+	// ---
+	form = forms.NewRecordWebAuthnRegistration(testApp, authRecord, testWebAuthnConfig)
+	options, err := form.CreationOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loadTestWebAuthnCredential(t, authenticator.Register(options.Challenge), &form.Credential)
+
+	interceptorCalls := 0
+	interceptor := func(next forms.InterceptorNextFunc[*models.WebAuthnCredential]) forms.InterceptorNextFunc[*models.WebAuthnCredential] {
+		return func(m *models.WebAuthnCredential) error {
+			interceptorCalls++
+			return next(m)
+		}
+	}
+
+	credential, err := form.Submit(interceptor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if interceptorCalls != 1 {
+		t.Fatalf("Expected interceptor to be called 1 time, got %d", interceptorCalls)
+	}
+
+	if credential.Name != "Passkey" {
+		t.Fatalf("Expected the default credential name, got %q", credential.Name)
+	}
+
+	if credential.CredentialId != webauthn.EncodeBase64(authenticator.CredentialId) {
+		t.Fatalf("Expected credential id %q, got %q", webauthn.EncodeBase64(authenticator.CredentialId), credential.CredentialId)
+	}
+
+	if _, err := testApp.Dao().FindWebAuthnCredentialByRecordAndId(authRecord, credential.Id); err != nil {
+		t.Fatalf("Expected the credential to be saved, got %v", err)
+	}
+
+	// replayed challenge (with a different authenticator)
+	// ---
+	otherAuthenticator, err := tests.NewTestAuthenticator("localhost", "http://localhost:8090")
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayForm := forms.NewRecordWebAuthnRegistration(testApp, authRecord, testWebAuthnConfig)
+	loadTestWebAuthnCredential(t, otherAuthenticator.Register(options.Challenge), &replayForm.Credential)
+	if _, err := replayForm.Submit(); err == nil {
+		t.Fatal("Expected error for already used challenge")
+	}
+
+	// already registered credential
+	// ---
+	form = forms.NewRecordWebAuthnRegistration(testApp, authRecord, testWebAuthnConfig)
+	options, err = form.CreationOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(options.ExcludeCredentials) != 1 || options.ExcludeCredentials[0].Id != credential.CredentialId {
+		t.Fatalf("Expected the registered credential to be excluded, got %v", options.ExcludeCredentials)
+	}
+	loadTestWebAuthnCredential(t, authenticator.Register(options.Challenge), &form.Credential)
+	if _, err := form.Submit(); err == nil {
+		t.Fatal("Expected error for already registered credential")
+	}
+}
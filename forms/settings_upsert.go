@@ -2,14 +2,12 @@ package forms
 
 import (
 	"os"
-	"time"
 
 	"github.com/AlperRehaYAZGAN/postgresbase/core"
 	"github.com/AlperRehaYAZGAN/postgresbase/daos"
 	"github.com/AlperRehaYAZGAN/postgresbase/models"
 	"github.com/AlperRehaYAZGAN/postgresbase/models/settings"
-	"github.com/AlperRehaYAZGAN/postgresbase/tools/types"
-	"github.com/pocketbase/dbx"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
 
 // SettingsUpsert is a [settings.Settings] upsert (create/update) form.
@@ -44,7 +42,59 @@ func (form *SettingsUpsert) SetDao(dao *daos.Dao) {
 
 // Validate makes the form validatable by implementing [validation.Validatable] interface.
 func (form *SettingsUpsert) Validate() error {
-	return form.Settings.Validate()
+	if err := form.Settings.Validate(); err != nil {
+		return err
+	}
+
+	storageErrs := validation.Errors{}
+	if err := form.checkStorageDriver(form.Settings.Storage); err != nil {
+		storageErrs["storage"] = err
+	}
+	if err := form.checkStorageDriver(form.Settings.Backups.Storage); err != nil {
+		storageErrs["backups"] = validation.Errors{"storage": err}
+	}
+	if len(storageErrs) > 0 {
+		return storageErrs
+	}
+
+	// the encryption key is needed to wrap the stored files data keys
+	if form.Settings.FileEncryption.Enabled && len(os.Getenv(form.app.EncryptionEnv())) != 32 {
+		return validation.Errors{"fileEncryption": validation.Errors{
+			"enabled": validation.NewError(
+				"validation_missing_encryption_key",
+				"The file encryption requires a valid 32 chars app encryption key.",
+			),
+		}}
+	}
+
+	return nil
+}
+
+// checkStorageDriver checks whether the config storage driver
+// is registered and validates its driver specific config.
+func (form *SettingsUpsert) checkStorageDriver(config settings.StorageConfig) error {
+	if config.Driver == "" {
+		return nil // fallback to S3 or local
+	}
+
+	driver := form.app.StorageDriver(config.Driver)
+	if driver == nil {
+		return validation.Errors{
+			"driver": validation.NewError("validation_unknown_storage_driver", "Unknown storage driver."),
+		}
+	}
+
+	if _, err := driver.DecodeConfig(config.Config); err != nil {
+		if errs, ok := err.(validation.Errors); ok {
+			return validation.Errors{"config": errs}
+		}
+
+		return validation.Errors{
+			"config": validation.NewError("validation_invalid_storage_config", "Invalid storage driver config."),
+		}
+	}
+
+	return nil
 }
 
 // Submit validates the form and upserts the loaded settings.
@@ -72,12 +122,15 @@ func (form *SettingsUpsert) Submit(interceptors ...InterceptorFunc[*settings.Set
 			return err
 		}
 
+		// the logs not matching the new settings are archived
+		// and deleted in the background by the logs archive cron job
+		if form.Settings.Logs.Archive {
+			return nil
+		}
+
 		// try to clear old logs not matching the new settings
-		createdBefore := time.Now().AddDate(0, 0, -1*form.Settings.Logs.MaxDays).UTC().Format(types.DefaultDateLayout)
-		expr := dbx.NewExp("[[created]] <= {:date} OR [[level]] < {:level}", dbx.Params{
-			"date":  createdBefore,
-			"level": form.Settings.Logs.MinLevel,
-		})
+		expr := core.ExpiredLogsExpr(form.Settings.Logs.MaxDays, form.Settings.Logs.MinLevel)
+
 		form.app.LogsDao().NonconcurrentDB().Delete((&models.Log{}).TableName(), expr).Execute()
 
 		// no logs are allowed -> try to reclaim preserved disk space after the previous delete operation
@@ -64,6 +64,30 @@ func TestSettingsUpsertValidateAndSubmit(t *testing.T) {
 			true,
 			nil,
 		},
+		// failure - unknown storage drivers
+		{
+			`{"storage": {"driver": "missing"}, "backups": {"storage": {"driver": "missing"}}}`,
+			false,
+			[]string{"storage", "backups"},
+		},
+		// success - registered storage drivers
+		{
+			`{"storage": {"driver": "memory"}, "backups": {"storage": {"driver": "memory"}}}`,
+			false,
+			nil,
+		},
+		// failure - file encryption without encryption key
+		{
+			`{"fileEncryption": {"enabled": true}}`,
+			false,
+			[]string{"fileEncryption"},
+		},
+		// success - file encryption with encryption key
+		{
+			`{"fileEncryption": {"enabled": true}}`,
+			true,
+			nil,
+		},
 	}
 
 	for i, s := range scenarios {
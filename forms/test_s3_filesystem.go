@@ -16,7 +16,7 @@ const (
 	s3FilesystemBackups = "backups"
 )
 
-// TestS3Filesystem defines a S3 filesystem connection test.
+// TestS3Filesystem defines a S3 or custom storage driver filesystem connection test.
 type TestS3Filesystem struct {
 	app core.App
 
@@ -40,34 +40,52 @@ func (form *TestS3Filesystem) Validate() error {
 	)
 }
 
-// Submit validates and performs a S3 filesystem connection test.
+// Submit validates and performs a S3 or custom storage driver filesystem connection test.
+//
+// The custom storage driver (if configured) has precedence over the S3 settings.
 func (form *TestS3Filesystem) Submit() error {
 	if err := form.Validate(); err != nil {
 		return err
 	}
 
 	var s3Config settings.S3Config
+	var storageConfig settings.StorageConfig
 
 	if form.Filesystem == s3FilesystemBackups {
 		s3Config = form.app.Settings().Backups.S3
+		storageConfig = form.app.Settings().Backups.Storage
 	} else {
 		s3Config = form.app.Settings().S3
+		storageConfig = form.app.Settings().Storage
 	}
 
-	if !s3Config.Enabled {
-		return errors.New("S3 storage filesystem is not enabled")
-	}
-
-	fsys, err := filesystem.NewS3(
-		s3Config.Bucket,
-		s3Config.Region,
-		s3Config.Endpoint,
-		s3Config.AccessKey,
-		s3Config.Secret,
-		s3Config.ForcePathStyle,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to initialize the S3 filesystem: %w", err)
+	var fsys *filesystem.System
+	var err error
+
+	switch {
+	case storageConfig.Driver != "":
+		if form.Filesystem == s3FilesystemBackups {
+			fsys, err = form.app.NewBackupsFilesystem()
+		} else {
+			fsys, err = form.app.NewFilesystem()
+		}
+		if err != nil {
+			return fmt.Errorf("failed to initialize the %q storage driver filesystem: %w", storageConfig.Driver, err)
+		}
+	case s3Config.Enabled:
+		fsys, err = filesystem.NewS3(
+			s3Config.Bucket,
+			s3Config.Region,
+			s3Config.Endpoint,
+			s3Config.AccessKey,
+			s3Config.Secret,
+			s3Config.ForcePathStyle,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to initialize the S3 filesystem: %w", err)
+		}
+	default:
+		return errors.New("neither S3 nor custom storage driver filesystem is enabled")
 	}
 	defer fsys.Close()
 
@@ -105,3 +105,32 @@ func TestS3FilesystemSubmitFailure(t *testing.T) {
 		}
 	}
 }
+
+func TestS3FilesystemSubmitStorageDriver(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().Storage.Driver = "memory"
+	app.Settings().Backups.Storage.Driver = "memory"
+
+	for _, name := range []string{"storage", "backups"} {
+		form := forms.NewTestS3Filesystem(app)
+		form.Filesystem = name
+
+		if err := form.Submit(); err != nil {
+			t.Fatalf("[%s] Expected nil, got %v", name, err)
+		}
+	}
+
+	// missing driver
+	app.Settings().Storage.Driver = "missing"
+
+	form := forms.NewTestS3Filesystem(app)
+	form.Filesystem = "storage"
+
+	if err := form.Submit(); err == nil {
+		t.Fatal("Expected error for missing storage driver, got nil")
+	}
+}
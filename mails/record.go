@@ -3,6 +3,7 @@ package mails
 import (
 	"html/template"
 	"net/mail"
+	"strings"
 
 	"github.com/AlperRehaYAZGAN/postgresbase/core"
 	"github.com/AlperRehaYAZGAN/postgresbase/mails/templates"
@@ -135,6 +136,49 @@ func SendRecordChangeEmail(app core.App, record *models.Record, newEmail string)
 	})
 }
 
+// SendRecordOTP sends a one-time password email with the plain
+// code (and the optional template login link) to the specified auth record.
+func SendRecordOTP(app core.App, authRecord *models.Record, otp *models.OTP, code string) error {
+	mailClient := app.NewMailClient()
+
+	emailTemplate := app.Settings().Meta.OTPTemplate
+	emailTemplate.ActionUrl = strings.ReplaceAll(emailTemplate.ActionUrl, settings.EmailPlaceholderOTPId, otp.Id)
+	emailTemplate.Body = strings.ReplaceAll(emailTemplate.Body, settings.EmailPlaceholderOTPId, otp.Id)
+
+	subject, body, err := resolveEmailTemplate(app, code, emailTemplate)
+	if err != nil {
+		return err
+	}
+
+	message := &mailer.Message{
+		From: mail.Address{
+			Name:    app.Settings().Meta.SenderName,
+			Address: app.Settings().Meta.SenderAddress,
+		},
+		To:      []mail.Address{{Address: authRecord.Email()}},
+		Subject: subject,
+		HTML:    body,
+	}
+
+	event := new(core.MailerRecordEvent)
+	event.MailClient = mailClient
+	event.Message = message
+	event.Collection = authRecord.Collection()
+	event.Record = authRecord
+	event.Meta = map[string]any{
+		"otpId": otp.Id,
+		"code":  code,
+	}
+
+	return app.OnMailerBeforeRecordOTPSend().Trigger(event, func(e *core.MailerRecordEvent) error {
+		if err := e.MailClient.Send(e.Message); err != nil {
+			return err
+		}
+
+		return app.OnMailerAfterRecordOTPSend().Trigger(e)
+	})
+}
+
 func resolveEmailTemplate(
 	app core.App,
 	token string,
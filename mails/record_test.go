@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/AlperRehaYAZGAN/postgresbase/mails"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/models/settings"
 	"github.com/AlperRehaYAZGAN/postgresbase/tests"
 )
 
@@ -91,3 +93,45 @@ func TestSendRecordChangeEmail(t *testing.T) {
 		}
 	}
 }
+
+func TestSendRecordOTP(t *testing.T) {
+	t.Parallel()
+
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	user, _ := testApp.Dao().FindFirstRecordByData("users", "email", "test@example.com")
+
+	otp := &models.OTP{}
+	otp.Id = "test_otp_id"
+
+	err := mails.SendRecordOTP(testApp, user, otp, "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if testApp.TestMailer.TotalSend != 1 {
+		t.Fatalf("Expected one email to be sent, got %d", testApp.TestMailer.TotalSend)
+	}
+
+	if !strings.Contains(testApp.TestMailer.LastMessage.HTML, "<strong>123456</strong>") {
+		t.Fatalf("Couldn't find the code in\n %s", testApp.TestMailer.LastMessage.HTML)
+	}
+
+	if strings.Contains(testApp.TestMailer.LastMessage.HTML, "href=") {
+		t.Fatalf("Didn't expect a login link in\n %s", testApp.TestMailer.LastMessage.HTML)
+	}
+
+	// custom template with login link
+	testApp.Settings().Meta.OTPTemplate.ActionUrl = "https://example.com/otp/" + settings.EmailPlaceholderOTPId + "?code=" + settings.EmailPlaceholderToken
+	testApp.Settings().Meta.OTPTemplate.Body = `<a href="` + settings.EmailPlaceholderActionUrl + `">Sign in</a>`
+
+	if err := mails.SendRecordOTP(testApp, user, otp, "123456"); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedLink := "https://example.com/otp/test_otp_id?code=123456"
+	if !strings.Contains(testApp.TestMailer.LastMessage.HTML, expectedLink) {
+		t.Fatalf("Couldn't find %s \nin\n %s", expectedLink, testApp.TestMailer.LastMessage.HTML)
+	}
+}
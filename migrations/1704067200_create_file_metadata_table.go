@@ -0,0 +1,39 @@
+//go:build !mysql
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+// Creates the _fileMetadata table that stores the metadata
+// (size, mime type, dimensions, etc.) of the uploaded record files.
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			CREATE TABLE IF NOT EXISTS {{_fileMetadata}} (
+				[[id]]           VARCHAR(32) PRIMARY KEY NOT NULL,
+				[[collectionId]] TEXT NOT NULL,
+				[[recordId]]     TEXT NOT NULL,
+				[[field]]        TEXT NOT NULL,
+				[[name]]         TEXT NOT NULL,
+				[[size]]         BIGINT DEFAULT 0 NOT NULL,
+				[[mimeType]]     TEXT DEFAULT '' NOT NULL,
+				[[width]]        INTEGER DEFAULT 0 NOT NULL,
+				[[height]]       INTEGER DEFAULT 0 NOT NULL,
+				[[duration]]     DOUBLE PRECISION DEFAULT 0 NOT NULL,
+				[[checksum]]     TEXT DEFAULT '' NOT NULL,
+				[[created]]      TIMESTAMPTZ NOT NULL,
+				[[updated]]      TIMESTAMPTZ NOT NULL,
+				---
+				FOREIGN KEY ([[collectionId]]) REFERENCES {{_collections}} ([[id]]) ON UPDATE CASCADE ON DELETE CASCADE
+			);
+
+			CREATE UNIQUE INDEX IF NOT EXISTS _fileMetadata_record_file_idx on {{_fileMetadata}} ([[collectionId]], [[recordId]], [[field]], [[name]]);
+		`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropTable("_fileMetadata").Execute()
+		return err
+	})
+}
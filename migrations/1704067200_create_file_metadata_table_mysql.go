@@ -0,0 +1,38 @@
+//go:build mysql
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+// Creates the _fileMetadata table that stores the metadata
+// (size, mime type, dimensions, etc.) of the uploaded record files.
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			CREATE TABLE IF NOT EXISTS {{_fileMetadata}} (
+				[[id]]           VARCHAR(100) NOT NULL,
+				[[collectionId]] VARCHAR(255) NOT NULL,
+				[[recordId]]     VARCHAR(255) NOT NULL,
+				[[field]]        VARCHAR(255) NOT NULL,
+				[[name]]         VARCHAR(255) NOT NULL,
+				[[size]]         BIGINT DEFAULT 0 NOT NULL,
+				[[mimeType]]     VARCHAR(255) DEFAULT '' NOT NULL,
+				[[width]]        INT DEFAULT 0 NOT NULL,
+				[[height]]       INT DEFAULT 0 NOT NULL,
+				[[duration]]     DOUBLE DEFAULT 0 NOT NULL,
+				[[checksum]]     VARCHAR(64) DEFAULT '' NOT NULL,
+				[[created]]      TIMESTAMP DEFAULT NOW() NOT NULL,
+				[[updated]]      TIMESTAMP DEFAULT NOW() NOT NULL,
+				PRIMARY KEY ([[id]]),
+				UNIQUE KEY [[_fileMetadata_record_file_idx]] ([[collectionId]],[[recordId]],[[field]],[[name]]),
+				CONSTRAINT [[_fileMetadata_collectionId]] FOREIGN KEY ([[collectionId]]) REFERENCES [[_collections]] ([[id]]) ON DELETE CASCADE ON UPDATE CASCADE
+			);
+		`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropTable("_fileMetadata").Execute()
+		return err
+	})
+}
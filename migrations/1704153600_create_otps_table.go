@@ -0,0 +1,33 @@
+//go:build !mysql
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+// Creates the _otps table that stores the hashed
+// auth records one-time passwords (email codes and magic links).
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			CREATE TABLE IF NOT EXISTS {{_otps}} (
+				[[id]]           VARCHAR(32) PRIMARY KEY NOT NULL,
+				[[collectionId]] TEXT NOT NULL,
+				[[recordId]]     TEXT NOT NULL,
+				[[passwordHash]] TEXT NOT NULL,
+				[[attempts]]     INTEGER DEFAULT 0 NOT NULL,
+				[[created]]      TIMESTAMPTZ NOT NULL,
+				[[updated]]      TIMESTAMPTZ NOT NULL,
+				---
+				FOREIGN KEY ([[collectionId]]) REFERENCES {{_collections}} ([[id]]) ON UPDATE CASCADE ON DELETE CASCADE
+			);
+
+			CREATE INDEX IF NOT EXISTS _otps_record_idx on {{_otps}} ([[collectionId]], [[recordId]]);
+		`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropTable("_otps").Execute()
+		return err
+	})
+}
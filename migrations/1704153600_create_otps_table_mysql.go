@@ -0,0 +1,32 @@
+//go:build mysql
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+// Creates the _otps table that stores the hashed
+// auth records one-time passwords (email codes and magic links).
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			CREATE TABLE IF NOT EXISTS {{_otps}} (
+				[[id]]           VARCHAR(100) NOT NULL,
+				[[collectionId]] VARCHAR(255) NOT NULL,
+				[[recordId]]     VARCHAR(255) NOT NULL,
+				[[passwordHash]] VARCHAR(255) NOT NULL,
+				[[attempts]]     INT DEFAULT 0 NOT NULL,
+				[[created]]      TIMESTAMP DEFAULT NOW() NOT NULL,
+				[[updated]]      TIMESTAMP DEFAULT NOW() NOT NULL,
+				PRIMARY KEY ([[id]]),
+				KEY [[_otps_record_idx]] ([[collectionId]],[[recordId]]),
+				CONSTRAINT [[_otps_collectionId]] FOREIGN KEY ([[collectionId]]) REFERENCES [[_collections]] ([[id]]) ON DELETE CASCADE ON UPDATE CASCADE
+			);
+		`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropTable("_otps").Execute()
+		return err
+	})
+}
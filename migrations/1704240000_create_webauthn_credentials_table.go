@@ -0,0 +1,37 @@
+//go:build !mysql
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+// Creates the _webauthnCredentials table that stores
+// the auth records WebAuthn public key credentials (aka. passkeys).
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			CREATE TABLE IF NOT EXISTS {{_webauthnCredentials}} (
+				[[id]]           VARCHAR(32) PRIMARY KEY NOT NULL,
+				[[collectionId]] TEXT NOT NULL,
+				[[recordId]]     TEXT NOT NULL,
+				[[name]]         TEXT DEFAULT '' NOT NULL,
+				[[credentialId]] TEXT NOT NULL,
+				[[publicKey]]    TEXT NOT NULL,
+				[[signCount]]    BIGINT DEFAULT 0 NOT NULL,
+				[[aaguid]]       TEXT DEFAULT '' NOT NULL,
+				[[created]]      TIMESTAMPTZ NOT NULL,
+				[[updated]]      TIMESTAMPTZ NOT NULL,
+				---
+				FOREIGN KEY ([[collectionId]]) REFERENCES {{_collections}} ([[id]]) ON UPDATE CASCADE ON DELETE CASCADE
+			);
+
+			CREATE UNIQUE INDEX IF NOT EXISTS _webauthnCredentials_collection_credential_idx on {{_webauthnCredentials}} ([[collectionId]], [[credentialId]]);
+			CREATE INDEX IF NOT EXISTS _webauthnCredentials_record_idx on {{_webauthnCredentials}} ([[collectionId]], [[recordId]]);
+		`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropTable("_webauthnCredentials").Execute()
+		return err
+	})
+}
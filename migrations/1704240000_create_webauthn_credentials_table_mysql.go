@@ -0,0 +1,36 @@
+//go:build mysql
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+// Creates the _webauthnCredentials table that stores
+// the auth records WebAuthn public key credentials (aka. passkeys).
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			CREATE TABLE IF NOT EXISTS {{_webauthnCredentials}} (
+				[[id]]           VARCHAR(100) NOT NULL,
+				[[collectionId]] VARCHAR(255) NOT NULL,
+				[[recordId]]     VARCHAR(255) NOT NULL,
+				[[name]]         VARCHAR(255) DEFAULT '' NOT NULL,
+				[[credentialId]] VARCHAR(1400) NOT NULL,
+				[[publicKey]]    TEXT NOT NULL,
+				[[signCount]]    BIGINT DEFAULT 0 NOT NULL,
+				[[aaguid]]       VARCHAR(32) DEFAULT '' NOT NULL,
+				[[created]]      TIMESTAMP DEFAULT NOW() NOT NULL,
+				[[updated]]      TIMESTAMP DEFAULT NOW() NOT NULL,
+				PRIMARY KEY ([[id]]),
+				UNIQUE KEY [[_webauthnCredentials_collection_credential_idx]] ([[collectionId]],[[credentialId]](255)),
+				KEY [[_webauthnCredentials_record_idx]] ([[collectionId]],[[recordId]]),
+				CONSTRAINT [[_webauthnCredentials_collectionId]] FOREIGN KEY ([[collectionId]]) REFERENCES [[_collections]] ([[id]]) ON DELETE CASCADE ON UPDATE CASCADE
+			);
+		`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropTable("_webauthnCredentials").Execute()
+		return err
+	})
+}
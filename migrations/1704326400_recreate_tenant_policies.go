@@ -0,0 +1,34 @@
+//go:build !mysql
+package migrations
+
+import (
+	"github.com/AlperRehaYAZGAN/postgresbase/daos"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/pocketbase/dbx"
+)
+
+// Recreates the multi-tenant collections row-level security policies
+// so that the queries without tenant no longer match any rows
+// (unless the tenant isolation is explicitly disabled).
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		dao := daos.New(db)
+
+		collections := []*models.Collection{}
+		if err := dao.CollectionQuery().All(&collections); err != nil {
+			return err
+		}
+
+		for _, c := range collections {
+			if !c.IsMultiTenant() {
+				continue
+			}
+
+			if err := dao.SyncRecordTableSchema(c, c); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, nil)
+}
@@ -0,0 +1,27 @@
+//go:build !mysql
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+// Creates the _uploadTokens table that stores the ids of the
+// consumed direct upload tokens to ensure that they are single-use.
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			CREATE TABLE IF NOT EXISTS {{_uploadTokens}} (
+				[[id]]      VARCHAR(100) PRIMARY KEY NOT NULL,
+				[[expires]] TIMESTAMPTZ NOT NULL,
+				[[created]] TIMESTAMPTZ NOT NULL
+			);
+
+			CREATE INDEX IF NOT EXISTS _uploadTokens_expires_idx on {{_uploadTokens}} ([[expires]]);
+		`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropTable("_uploadTokens").Execute()
+		return err
+	})
+}
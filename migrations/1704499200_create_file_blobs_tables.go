@@ -0,0 +1,38 @@
+//go:build !mysql
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+// Creates the _fileBlobs and _fileBlobRefs tables that store the
+// references count of the deduplicated files content-addressed blobs.
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			CREATE TABLE IF NOT EXISTS {{_fileBlobs}} (
+				[[blobKey]] VARCHAR(255) PRIMARY KEY NOT NULL,
+				[[refs]]    INTEGER DEFAULT 0 NOT NULL
+			);
+
+			CREATE TABLE IF NOT EXISTS {{_fileBlobRefs}} (
+				[[fileKey]] VARCHAR(255) PRIMARY KEY NOT NULL,
+				[[blobKey]] VARCHAR(255) NOT NULL
+			);
+
+			CREATE INDEX IF NOT EXISTS _fileBlobRefs_blobKey_idx on {{_fileBlobRefs}} ([[blobKey]]);
+		`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		tables := []string{"_fileBlobRefs", "_fileBlobs"}
+
+		for _, name := range tables {
+			if _, err := db.DropTable(name).Execute(); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
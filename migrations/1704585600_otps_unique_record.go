@@ -0,0 +1,31 @@
+//go:build !mysql
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+// Replaces the _otps record index with a unique one
+// so that an auth record could have only one active OTP.
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		// the OTPs are short lived so it is fine to invalidate the existing ones
+		_, err := db.NewQuery(`
+			DELETE FROM {{_otps}};
+
+			DROP INDEX IF EXISTS _otps_record_idx;
+
+			CREATE UNIQUE INDEX IF NOT EXISTS _otps_record_unique_idx on {{_otps}} ([[collectionId]], [[recordId]]);
+		`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			DROP INDEX IF EXISTS _otps_record_unique_idx;
+
+			CREATE INDEX IF NOT EXISTS _otps_record_idx on {{_otps}} ([[collectionId]], [[recordId]]);
+		`).Execute()
+
+		return err
+	})
+}
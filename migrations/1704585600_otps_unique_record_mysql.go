@@ -0,0 +1,35 @@
+//go:build mysql
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+// Replaces the _otps record index with a unique one
+// so that an auth record could have only one active OTP.
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		// the OTPs are short lived so it is fine to invalidate the existing ones
+		if _, err := db.NewQuery("DELETE FROM {{_otps}}").Execute(); err != nil {
+			return err
+		}
+
+		// note: the unique index is created first because the
+		// collectionId foreign key requires an index on it
+		if _, err := db.NewQuery("CREATE UNIQUE INDEX [[_otps_record_unique_idx]] ON {{_otps}} ([[collectionId]],[[recordId]])").Execute(); err != nil {
+			return err
+		}
+
+		_, err := db.NewQuery("DROP INDEX [[_otps_record_idx]] ON {{_otps}}").Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		if _, err := db.NewQuery("CREATE INDEX [[_otps_record_idx]] ON {{_otps}} ([[collectionId]],[[recordId]])").Execute(); err != nil {
+			return err
+		}
+
+		_, err := db.NewQuery("DROP INDEX [[_otps_record_unique_idx]] ON {{_otps}}").Execute()
+
+		return err
+	})
+}
@@ -0,0 +1,31 @@
+//go:build !mysql
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+// Creates the _webauthnChallenges table that stores the hashes of the
+// already used WebAuthn ceremony challenges until their expiration.
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			CREATE TABLE IF NOT EXISTS {{_webauthnChallenges}} (
+				[[id]]           VARCHAR(64) PRIMARY KEY NOT NULL,
+				[[collectionId]] TEXT NOT NULL,
+				[[expires]]      TIMESTAMPTZ NOT NULL,
+				[[created]]      TIMESTAMPTZ NOT NULL,
+				[[updated]]      TIMESTAMPTZ NOT NULL,
+				---
+				FOREIGN KEY ([[collectionId]]) REFERENCES {{_collections}} ([[id]]) ON UPDATE CASCADE ON DELETE CASCADE
+			);
+
+			CREATE INDEX IF NOT EXISTS _webauthnChallenges_expires_idx on {{_webauthnChallenges}} ([[expires]]);
+		`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropTable("_webauthnChallenges").Execute()
+		return err
+	})
+}
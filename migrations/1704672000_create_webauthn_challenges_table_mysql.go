@@ -0,0 +1,30 @@
+//go:build mysql
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+// Creates the _webauthnChallenges table that stores the hashes of the
+// already used WebAuthn ceremony challenges until their expiration.
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			CREATE TABLE IF NOT EXISTS {{_webauthnChallenges}} (
+				[[id]]           VARCHAR(64) NOT NULL,
+				[[collectionId]] VARCHAR(255) NOT NULL,
+				[[expires]]      TIMESTAMP NOT NULL,
+				[[created]]      TIMESTAMP DEFAULT NOW() NOT NULL,
+				[[updated]]      TIMESTAMP DEFAULT NOW() NOT NULL,
+				PRIMARY KEY ([[id]]),
+				KEY [[_webauthnChallenges_expires_idx]] ([[expires]]),
+				CONSTRAINT [[_webauthnChallenges_collectionId]] FOREIGN KEY ([[collectionId]]) REFERENCES [[_collections]] ([[id]]) ON DELETE CASCADE ON UPDATE CASCADE
+			);
+		`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropTable("_webauthnChallenges").Execute()
+		return err
+	})
+}
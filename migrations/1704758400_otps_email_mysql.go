@@ -0,0 +1,25 @@
+//go:build mysql
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+// Adds the _otps email column that stores the address the one-time
+// password was sent to (so that it could be invalidated on email change).
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		// the OTPs are short lived so it is fine to invalidate the existing ones
+		if _, err := db.NewQuery("DELETE FROM {{_otps}}").Execute(); err != nil {
+			return err
+		}
+
+		_, err := db.AddColumn("_otps", "email", "VARCHAR(255) DEFAULT '' NOT NULL").Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropColumn("_otps", "email").Execute()
+
+		return err
+	})
+}
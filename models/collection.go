@@ -2,8 +2,10 @@ package models
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/AlperRehaYAZGAN/postgresbase/models/schema"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/cron"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/types"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
@@ -49,6 +51,13 @@ func (m *Collection) BaseFilesPath() string {
 	return m.Id
 }
 
+// BaseUploadsPath returns the storage dir path used for the collection
+// files that were uploaded directly to the storage but are not attached
+// to a record yet (eg. with a presigned url).
+func (m *Collection) BaseUploadsPath() string {
+	return m.BaseFilesPath() + "/_uploads"
+}
+
 // IsBase checks if the current collection has "base" type.
 func (m *Collection) IsBase() bool {
 	return m.Type == CollectionTypeBase
@@ -64,6 +73,38 @@ func (m *Collection) IsView() bool {
 	return m.Type == CollectionTypeView
 }
 
+// IsMultiTenant checks if the current collection is a "base" or "auth"
+// collection with enabled "multiTenant" option.
+func (m *Collection) IsMultiTenant() bool {
+	switch m.Type {
+	case CollectionTypeBase:
+		return m.BaseOptions().MultiTenant
+	case CollectionTypeAuth:
+		return m.AuthOptions().MultiTenant
+	default:
+		return false
+	}
+}
+
+// IsFilesDedup checks if the current collection is a "base" or "auth"
+// collection with enabled "dedupFiles" option.
+func (m *Collection) IsFilesDedup() bool {
+	switch m.Type {
+	case CollectionTypeBase:
+		return m.BaseOptions().DedupFiles
+	case CollectionTypeAuth:
+		return m.AuthOptions().DedupFiles
+	default:
+		return false
+	}
+}
+
+// IsMaterializedView checks if the current collection has "view" type
+// with enabled "materialized" option.
+func (m *Collection) IsMaterializedView() bool {
+	return m.IsView() && m.ViewOptions().Materialized
+}
+
 // MarshalJSON implements the [json.Marshaler] interface.
 func (m Collection) MarshalJSON() ([]byte, error) {
 	type alias Collection // prevent recursion
@@ -162,6 +203,13 @@ func (m *Collection) SetOptions(typedOptions any) error {
 
 // CollectionBaseOptions defines the "base" Collection.Options fields.
 type CollectionBaseOptions struct {
+	// MultiTenant enables the tenant column and row-level security
+	// isolation of the collection records.
+	MultiTenant bool `form:"multiTenant" json:"multiTenant,omitempty"`
+
+	// DedupFiles enables the content-addressed storage of the collection
+	// record files, aka. files with the same content are stored only once.
+	DedupFiles bool `form:"dedupFiles" json:"dedupFiles,omitempty"`
 }
 
 // Validate implements [validation.Validatable] interface.
@@ -182,6 +230,63 @@ type CollectionAuthOptions struct {
 	OnlyVerified       bool     `form:"onlyVerified" json:"onlyVerified"`
 	OnlyEmailDomains   []string `form:"onlyEmailDomains" json:"onlyEmailDomains"`
 	MinPasswordLength  int      `form:"minPasswordLength" json:"minPasswordLength"`
+
+	// MultiTenant enables the tenant column and row-level security
+	// isolation of the collection records.
+	MultiTenant bool `form:"multiTenant" json:"multiTenant,omitempty"`
+
+	// DedupFiles enables the content-addressed storage of the collection
+	// record files, aka. files with the same content are stored only once.
+	DedupFiles bool `form:"dedupFiles" json:"dedupFiles,omitempty"`
+
+	// AllowOTPAuth enables the passwordless auth with an emailed
+	// one-time password (code and magic link).
+	AllowOTPAuth bool `form:"allowOTPAuth" json:"allowOTPAuth,omitempty"`
+
+	// OTPDuration is the one-time password validity duration in seconds
+	// (fallbacks to [DefaultOTPDuration] if not set).
+	OTPDuration int `form:"otpDuration" json:"otpDuration,omitempty"`
+
+	// OTPLength is the number of digits of the generated one-time password
+	// (fallbacks to [DefaultOTPLength] if not set).
+	OTPLength int `form:"otpLength" json:"otpLength,omitempty"`
+
+	// AllowWebAuthnAuth enables the WebAuthn public key credentials
+	// (aka. passkeys) registration and authentication.
+	AllowWebAuthnAuth bool `form:"allowWebAuthnAuth" json:"allowWebAuthnAuth,omitempty"`
+
+	// WebAuthnRPId is the WebAuthn relying party id
+	// (fallbacks to the app url hostname if not set).
+	WebAuthnRPId string `form:"webAuthnRPId" json:"webAuthnRPId,omitempty"`
+
+	// WebAuthnOrigins is a list with the allowed WebAuthn ceremonies
+	// origins (fallbacks to the app url origin if not set).
+	WebAuthnOrigins []string `form:"webAuthnOrigins" json:"webAuthnOrigins,omitempty"`
+}
+
+// Default auth collection one-time password options.
+const (
+	DefaultOTPDuration = 180 // 3 min
+	DefaultOTPLength   = 6
+)
+
+// OTPDurationOrDefault returns the OTPDuration option as
+// [time.Duration] (or its default value if not set).
+func (o CollectionAuthOptions) OTPDurationOrDefault() time.Duration {
+	if o.OTPDuration <= 0 {
+		return DefaultOTPDuration * time.Second
+	}
+
+	return time.Duration(o.OTPDuration) * time.Second
+}
+
+// OTPLengthOrDefault returns the OTPLength option (or its default value if not set).
+func (o CollectionAuthOptions) OTPLengthOrDefault() int {
+	if o.OTPLength <= 0 {
+		return DefaultOTPLength
+	}
+
+	return o.OTPLength
 }
 
 // Validate implements [validation.Validatable] interface.
@@ -202,6 +307,10 @@ func (o CollectionAuthOptions) Validate() error {
 			validation.Min(5),
 			validation.Max(72),
 		),
+		validation.Field(&o.OTPDuration, validation.Min(10), validation.Max(86400)),
+		validation.Field(&o.OTPLength, validation.Min(4), validation.Max(10)),
+		validation.Field(&o.WebAuthnRPId, validation.Length(1, 255), is.DNSName),
+		validation.Field(&o.WebAuthnOrigins, validation.Each(is.URL)),
 	)
 }
 
@@ -210,11 +319,39 @@ func (o CollectionAuthOptions) Validate() error {
 // CollectionViewOptions defines the "view" Collection.Options fields.
 type CollectionViewOptions struct {
 	Query string `form:"query" json:"query"`
+
+	// Materialized indicates whether to persist the view as materialized view,
+	// aka. the query result is stored and must be refreshed explicitly.
+	Materialized bool `form:"materialized" json:"materialized,omitempty"`
+
+	// RefreshCron is an optional cron expression to schedule
+	// automatic refreshes of the materialized view, eg. "*/30 * * * *".
+	//
+	// Leave it empty to refresh the view only on demand.
+	RefreshCron string `form:"refreshCron" json:"refreshCron,omitempty"`
 }
 
 // Validate implements [validation.Validatable] interface.
 func (o CollectionViewOptions) Validate() error {
 	return validation.ValidateStruct(&o,
 		validation.Field(&o.Query, validation.Required),
+		validation.Field(
+			&o.RefreshCron,
+			validation.When(!o.Materialized, validation.Empty),
+			validation.By(checkCronExpression),
+		),
 	)
 }
+
+func checkCronExpression(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil // nothing to check
+	}
+
+	if _, err := cron.NewSchedule(v); err != nil {
+		return validation.NewError("validation_invalid_cron", err.Error())
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package models_test
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/AlperRehaYAZGAN/postgresbase/models"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/list"
@@ -32,6 +33,19 @@ func TestCollectionBaseFilesPath(t *testing.T) {
 	}
 }
 
+func TestCollectionBaseUploadsPath(t *testing.T) {
+	t.Parallel()
+
+	m := models.Collection{}
+
+	m.RefreshId()
+
+	expected := m.Id + "/_uploads"
+	if m.BaseUploadsPath() != expected {
+		t.Fatalf("Expected path %s, got %s", expected, m.BaseUploadsPath())
+	}
+}
+
 func TestCollectionIsBase(t *testing.T) {
 	t.Parallel()
 
@@ -74,6 +88,72 @@ func TestCollectionIsAuth(t *testing.T) {
 	}
 }
 
+func TestCollectionIsMaterializedView(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		collection models.Collection
+		expected   bool
+	}{
+		{models.Collection{}, false},
+		{models.Collection{Type: models.CollectionTypeBase, Options: types.JsonMap{"materialized": true}}, false},
+		{models.Collection{Type: models.CollectionTypeView}, false},
+		{models.Collection{Type: models.CollectionTypeView, Options: types.JsonMap{"materialized": false}}, false},
+		{models.Collection{Type: models.CollectionTypeView, Options: types.JsonMap{"materialized": true}}, true},
+	}
+
+	for i, s := range scenarios {
+		result := s.collection.IsMaterializedView()
+		if result != s.expected {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expected, result)
+		}
+	}
+}
+
+func TestCollectionIsMultiTenant(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		collection models.Collection
+		expected   bool
+	}{
+		{models.Collection{}, false},
+		{models.Collection{Type: models.CollectionTypeBase}, false},
+		{models.Collection{Type: models.CollectionTypeBase, Options: types.JsonMap{"multiTenant": true}}, true},
+		{models.Collection{Type: models.CollectionTypeAuth, Options: types.JsonMap{"multiTenant": true}}, true},
+		{models.Collection{Type: models.CollectionTypeView, Options: types.JsonMap{"multiTenant": true}}, false},
+	}
+
+	for i, s := range scenarios {
+		result := s.collection.IsMultiTenant()
+		if result != s.expected {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expected, result)
+		}
+	}
+}
+
+func TestCollectionIsFilesDedup(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		collection models.Collection
+		expected   bool
+	}{
+		{models.Collection{}, false},
+		{models.Collection{Type: models.CollectionTypeBase}, false},
+		{models.Collection{Type: models.CollectionTypeBase, Options: types.JsonMap{"dedupFiles": true}}, true},
+		{models.Collection{Type: models.CollectionTypeAuth, Options: types.JsonMap{"dedupFiles": true}}, true},
+		{models.Collection{Type: models.CollectionTypeView, Options: types.JsonMap{"dedupFiles": true}}, false},
+	}
+
+	for i, s := range scenarios {
+		result := s.collection.IsFilesDedup()
+		if result != s.expected {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expected, result)
+		}
+	}
+}
+
 func TestCollectionMarshalJSON(t *testing.T) {
 	t.Parallel()
 
@@ -437,6 +517,42 @@ func TestCollectionAuthOptionsValidate(t *testing.T) {
 			},
 			[]string{},
 		},
+		{
+			"OTP options with invalid data",
+			models.CollectionAuthOptions{
+				AllowOTPAuth: true,
+				OTPDuration:  5,
+				OTPLength:    20,
+			},
+			[]string{"otpDuration", "otpLength"},
+		},
+		{
+			"OTP options with valid data",
+			models.CollectionAuthOptions{
+				AllowOTPAuth: true,
+				OTPDuration:  300,
+				OTPLength:    8,
+			},
+			[]string{},
+		},
+		{
+			"WebAuthn options with invalid data",
+			models.CollectionAuthOptions{
+				AllowWebAuthnAuth: true,
+				WebAuthnRPId:      "invalid rp id",
+				WebAuthnOrigins:   []string{"https://example.com", "invalid"},
+			},
+			[]string{"webAuthnRPId", "webAuthnOrigins"},
+		},
+		{
+			"WebAuthn options with valid data",
+			models.CollectionAuthOptions{
+				AllowWebAuthnAuth: true,
+				WebAuthnRPId:      "localhost",
+				WebAuthnOrigins:   []string{"http://localhost:8090", "https://example.com"},
+			},
+			[]string{},
+		},
 		{
 			"all fields with valid data",
 			models.CollectionAuthOptions{
@@ -489,6 +605,23 @@ func TestCollectionViewOptionsValidate(t *testing.T) {
 			models.CollectionViewOptions{},
 			[]string{"query"},
 		},
+		{
+			"refresh cron without materialized",
+			models.CollectionViewOptions{
+				Query:       "test123",
+				RefreshCron: "* * * * *",
+			},
+			[]string{"refreshCron"},
+		},
+		{
+			"materialized with invalid refresh cron",
+			models.CollectionViewOptions{
+				Query:        "test123",
+				Materialized: true,
+				RefreshCron:  "invalid",
+			},
+			[]string{"refreshCron"},
+		},
 		{
 			"valid data",
 			models.CollectionViewOptions{
@@ -496,6 +629,15 @@ func TestCollectionViewOptionsValidate(t *testing.T) {
 			},
 			[]string{},
 		},
+		{
+			"valid materialized data",
+			models.CollectionViewOptions{
+				Query:        "test123",
+				Materialized: true,
+				RefreshCron:  "*/30 * * * *",
+			},
+			[]string{},
+		},
 	}
 
 	for _, s := range scenarios {
@@ -520,3 +662,28 @@ func TestCollectionViewOptionsValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestCollectionAuthOptionsOTPDefaults(t *testing.T) {
+	t.Parallel()
+
+	options := models.CollectionAuthOptions{}
+
+	if v := options.OTPDurationOrDefault(); v != models.DefaultOTPDuration*time.Second {
+		t.Fatalf("Expected the default OTP duration, got %v", v)
+	}
+
+	if v := options.OTPLengthOrDefault(); v != models.DefaultOTPLength {
+		t.Fatalf("Expected the default OTP length, got %v", v)
+	}
+
+	options.OTPDuration = 60
+	options.OTPLength = 8
+
+	if v := options.OTPDurationOrDefault(); v != 60*time.Second {
+		t.Fatalf("Expected 60s OTP duration, got %v", v)
+	}
+
+	if v := options.OTPLengthOrDefault(); v != 8 {
+		t.Fatalf("Expected 8 OTP length, got %v", v)
+	}
+}
@@ -0,0 +1,11 @@
+package models
+
+import "github.com/AlperRehaYAZGAN/postgresbase/tools/types"
+
+// CronJobInfo defines the public details of a single registered app cron job.
+type CronJobInfo struct {
+	Id         string         `json:"id"`
+	Expression string         `json:"expression"`
+	Paused     bool           `json:"paused"`
+	NextRun    types.DateTime `json:"nextRun"`
+}
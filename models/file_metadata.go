@@ -0,0 +1,23 @@
+package models
+
+var _ Model = (*FileMetadata)(nil)
+
+// FileMetadata defines the metadata of a single uploaded record file.
+type FileMetadata struct {
+	BaseModel
+
+	CollectionId string  `db:"collectionId" json:"collectionId"`
+	RecordId     string  `db:"recordId" json:"recordId"`
+	Field        string  `db:"field" json:"field"`
+	Name         string  `db:"name" json:"name"`
+	Size         int64   `db:"size" json:"size"`
+	MimeType     string  `db:"mimeType" json:"mimeType"`
+	Width        int     `db:"width" json:"width"`
+	Height       int     `db:"height" json:"height"`
+	Duration     float64 `db:"duration" json:"duration"`
+	Checksum     string  `db:"checksum" json:"checksum"`
+}
+
+func (m *FileMetadata) TableName() string {
+	return "_fileMetadata"
+}
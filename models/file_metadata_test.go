@@ -0,0 +1,16 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+)
+
+func TestFileMetadataTableName(t *testing.T) {
+	t.Parallel()
+
+	m := models.FileMetadata{}
+	if m.TableName() != "_fileMetadata" {
+		t.Fatalf("Unexpected table name, got %q", m.TableName())
+	}
+}
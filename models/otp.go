@@ -0,0 +1,76 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var _ Model = (*OTP)(nil)
+
+// OTPMaxAttempts is the max number of failed verification attempts
+// after which the OTP is invalidated.
+//
+// The attempts of a still valid OTP are carried over to its replacement,
+// aka. they are limited per auth record within the OTP duration.
+const OTPMaxAttempts = 5
+
+// OTP defines a single auth record one-time password (aka. email code or magic link).
+//
+// Only the bcrypt hash of the plain password is stored.
+//
+// Email is the address the password was sent to and the OTP
+// is valid only while it matches the auth record email.
+type OTP struct {
+	BaseModel
+
+	CollectionId string `db:"collectionId" json:"collectionId"`
+	RecordId     string `db:"recordId" json:"recordId"`
+	Email        string `db:"email" json:"email"`
+	PasswordHash string `db:"passwordHash" json:"-"`
+	Attempts     int    `db:"attempts" json:"attempts"`
+}
+
+func (m *OTP) TableName() string {
+	return "_otps"
+}
+
+// SetPassword hashes and sets the provided plain one-time password.
+func (m *OTP) SetPassword(password string) error {
+	if password == "" {
+		return errors.New("The provided plain password is empty")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	m.PasswordHash = string(hash)
+
+	return nil
+}
+
+// ValidatePassword validates a plain password against the OTP password hash.
+func (m *OTP) ValidatePassword(password string) bool {
+	err := bcrypt.CompareHashAndPassword([]byte(m.PasswordHash), []byte(password))
+
+	return err == nil
+}
+
+// HasExpired checks whether the OTP was created more than
+// duration ago or has reached the max failed attempts limit.
+func (m *OTP) HasExpired(duration time.Duration) bool {
+	if m.Attempts >= OTPMaxAttempts {
+		return true
+	}
+
+	return m.IsOutdated(duration)
+}
+
+// IsOutdated checks whether the OTP was created more than duration ago
+// (regardless of its attempts).
+func (m *OTP) IsOutdated(duration time.Duration) bool {
+	return m.Created.Time().Add(duration).Before(time.Now())
+}
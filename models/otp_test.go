@@ -0,0 +1,83 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/types"
+)
+
+func TestOTPTableName(t *testing.T) {
+	m := models.OTP{}
+	if m.TableName() != "_otps" {
+		t.Fatalf("Unexpected table name, got %q", m.TableName())
+	}
+}
+
+func TestOTPPassword(t *testing.T) {
+	m := models.OTP{}
+
+	if err := m.SetPassword(""); err == nil {
+		t.Fatal("Expected empty password error")
+	}
+
+	if err := m.SetPassword("123456"); err != nil {
+		t.Fatal(err)
+	}
+
+	if m.PasswordHash == "" || m.PasswordHash == "123456" {
+		t.Fatalf("Expected the password to be hashed, got %q", m.PasswordHash)
+	}
+
+	if m.ValidatePassword("654321") {
+		t.Fatal("Expected invalid password")
+	}
+
+	if !m.ValidatePassword("123456") {
+		t.Fatal("Expected valid password")
+	}
+}
+
+func TestOTPHasExpired(t *testing.T) {
+	scenarios := []struct {
+		created  time.Time
+		attempts int
+		expected bool
+	}{
+		{time.Now(), 0, false},
+		{time.Now(), models.OTPMaxAttempts - 1, false},
+		{time.Now(), models.OTPMaxAttempts, true},
+		{time.Now().Add(-2 * time.Minute), 0, true},
+	}
+
+	for i, s := range scenarios {
+		m := models.OTP{Attempts: s.attempts}
+		m.Created, _ = types.ParseDateTime(s.created)
+
+		if result := m.HasExpired(time.Minute); result != s.expected {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expected, result)
+		}
+	}
+}
+
+func TestOTPIsOutdated(t *testing.T) {
+	scenarios := []struct {
+		created  time.Time
+		attempts int
+		expected bool
+	}{
+		{time.Now(), 0, false},
+		{time.Now(), models.OTPMaxAttempts, false},
+		{time.Now().Add(-2 * time.Minute), 0, true},
+	}
+
+	for i, s := range scenarios {
+		m := models.OTP{Attempts: s.attempts}
+		m.Created, _ = types.ParseDateTime(s.created)
+
+		if result := m.IsOutdated(time.Minute); result != s.expected {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expected, result)
+		}
+	}
+}
@@ -7,7 +7,8 @@ import (
 var _ Model = (*Param)(nil)
 
 const (
-	ParamAppSettings = "settings"
+	ParamAppSettings    = "settings"
+	ParamCronPausedJobs = "cronPausedJobs"
 )
 
 type Param struct {
@@ -48,6 +48,7 @@ const (
 	FieldNamePasswordHash           string = "passwordHash"
 	FieldNameLastResetSentAt        string = "lastResetSentAt"
 	FieldNameLastVerificationSentAt string = "lastVerificationSentAt"
+	FieldNameTenant                 string = "tenant"
 )
 
 // BaseModelFieldNames returns the field names that all models have (id, created, updated).
@@ -655,6 +656,12 @@ type FileOptions struct {
 	MaxSelect int      `form:"maxSelect" json:"maxSelect"`
 	MaxSize   int      `form:"maxSize" json:"maxSize"`
 	Protected bool     `form:"protected" json:"protected"`
+
+	// Transforms defines the allowed on-the-fly image transformations
+	// of the field files (in addition to the Thumbs sizes).
+	//
+	// If nil, only the Thumbs sizes are allowed.
+	Transforms *FileTransformsOptions `form:"transforms" json:"transforms,omitempty"`
 }
 
 func (o FileOptions) Validate() error {
@@ -665,9 +672,50 @@ func (o FileOptions) Validate() error {
 			validation.NotIn("0x0", "0x0t", "0x0b", "0x0f"),
 			validation.Match(filesystem.ThumbSizeRegex),
 		)),
+		validation.Field(&o.Transforms),
 	)
 }
 
+// FileTransformsOptions defines the allowed image transformations of a file field.
+type FileTransformsOptions struct {
+	// Formats is the list of the allowed output formats
+	// (note that "avif" is allowed only if it has a registered
+	// encoder, see [filesystem.RegisterImageFormat]).
+	Formats []string `form:"formats" json:"formats"`
+
+	// Quality allows custom output encoding quality.
+	Quality bool `form:"quality" json:"quality"`
+
+	// Rotate allows 90, 180 and 270 degrees rotations.
+	Rotate bool `form:"rotate" json:"rotate"`
+
+	// Blur allows gaussian blur.
+	Blur bool `form:"blur" json:"blur"`
+
+	// MaxDpr is the max allowed device pixel ratio thumb size multiplier
+	// (0 and 1 disable the multiplier).
+	MaxDpr int `form:"maxDpr" json:"maxDpr"`
+}
+
+func (o FileTransformsOptions) Validate() error {
+	return validation.ValidateStruct(&o,
+		validation.Field(&o.Formats, validation.Each(
+			validation.In("jpeg", "png", "gif", "webp", "avif"),
+			validation.By(checkImageFormatEncoder),
+		)),
+		validation.Field(&o.MaxDpr, validation.Min(0), validation.Max(4)),
+	)
+}
+
+func checkImageFormatEncoder(value any) error {
+	v, _ := value.(string)
+	if v == "" || filesystem.HasImageFormat(v) {
+		return nil
+	}
+
+	return validation.NewError("validation_unsupported_image_format", "The image format doesn't have a registered encoder.")
+}
+
 // IsMultiple implements MultiValuer interface and checks whether the
 // current field options support multiple values.
 func (o FileOptions) IsMultiple() bool {
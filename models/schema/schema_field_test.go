@@ -2139,6 +2139,44 @@ func TestFileOptionsValidate(t *testing.T) {
 			},
 			[]string{},
 		},
+		{
+			"invalid transforms",
+			schema.FileOptions{
+				MaxSize:   1,
+				MaxSelect: 2,
+				Transforms: &schema.FileTransformsOptions{
+					Formats: []string{"jpeg", "bmp"},
+					MaxDpr:  5,
+				},
+			},
+			[]string{"transforms"},
+		},
+		{
+			"transforms with formats without registered encoder",
+			schema.FileOptions{
+				MaxSize:   1,
+				MaxSelect: 2,
+				Transforms: &schema.FileTransformsOptions{
+					Formats: []string{"jpeg", "webp", "avif"},
+				},
+			},
+			[]string{"transforms"},
+		},
+		{
+			"valid transforms",
+			schema.FileOptions{
+				MaxSize:   1,
+				MaxSelect: 2,
+				Transforms: &schema.FileTransformsOptions{
+					Formats: []string{"jpeg", "png", "gif"},
+					Quality: true,
+					Rotate:  true,
+					Blur:    true,
+					MaxDpr:  3,
+				},
+			},
+			[]string{},
+		},
 	}
 
 	checkFieldOptionsScenarios(t, scenarios)
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 	"sync"
 
@@ -24,11 +25,19 @@ const SecretMask string = "******"
 type Settings struct {
 	mux sync.RWMutex
 
-	Meta    MetaConfig    `form:"meta" json:"meta"`
-	Logs    LogsConfig    `form:"logs" json:"logs"`
-	Smtp    SmtpConfig    `form:"smtp" json:"smtp"`
-	S3      S3Config      `form:"s3" json:"s3"`
-	Backups BackupsConfig `form:"backups" json:"backups"`
+	Meta     MetaConfig     `form:"meta" json:"meta"`
+	Logs     LogsConfig     `form:"logs" json:"logs"`
+	Smtp     SmtpConfig     `form:"smtp" json:"smtp"`
+	S3       S3Config       `form:"s3" json:"s3"`
+	Storage  StorageConfig  `form:"storage" json:"storage"`
+	Backups  BackupsConfig  `form:"backups" json:"backups"`
+	Tenancy  TenancyConfig  `form:"tenancy" json:"tenancy"`
+	FilesGC  FilesGCConfig  `form:"filesGC" json:"filesGC"`
+	FileScan FileScanConfig `form:"fileScan" json:"fileScan"`
+	Metrics  MetricsConfig  `form:"metrics" json:"metrics"`
+	Tracing  TracingConfig  `form:"tracing" json:"tracing"`
+
+	FileEncryption FileEncryptionConfig `form:"fileEncryption" json:"fileEncryption"`
 
 	AdminAuthToken           TokenConfig `form:"adminAuthToken" json:"adminAuthToken"`
 	AdminPasswordResetToken  TokenConfig `form:"adminPasswordResetToken" json:"adminPasswordResetToken"`
@@ -81,6 +90,7 @@ func New() *Settings {
 			VerificationTemplate:       defaultVerificationTemplate,
 			ResetPasswordTemplate:      defaultResetPasswordTemplate,
 			ConfirmEmailChangeTemplate: defaultConfirmEmailChangeTemplate,
+			OTPTemplate:                defaultOTPTemplate,
 		},
 		Logs: LogsConfig{
 			MaxDays: 5,
@@ -97,6 +107,20 @@ func New() *Settings {
 		Backups: BackupsConfig{
 			CronMaxKeep: 3,
 		},
+		Tenancy: TenancyConfig{
+			Header: "X-Tenant",
+		},
+		FilesGC: FilesGCConfig{
+			MinAge: 24,
+		},
+		FileScan: FileScanConfig{
+			Action:  FileScanActionReject,
+			Timeout: 30,
+		},
+		Tracing: TracingConfig{
+			ServiceName: "postgresbase",
+			SampleRatio: 1,
+		},
 		AdminAuthToken: TokenConfig{
 			Secret:   security.RandomString(50),
 			Duration: 1209600, // 14 days
@@ -225,7 +249,13 @@ func (s *Settings) Validate() error {
 		validation.Field(&s.RecordFileToken),
 		validation.Field(&s.Smtp),
 		validation.Field(&s.S3),
+		validation.Field(&s.Storage),
 		validation.Field(&s.Backups),
+		validation.Field(&s.Tenancy),
+		validation.Field(&s.FilesGC),
+		validation.Field(&s.FileScan),
+		validation.Field(&s.Metrics),
+		validation.Field(&s.Tracing),
 		validation.Field(&s.GoogleAuth),
 		validation.Field(&s.FacebookAuth),
 		validation.Field(&s.GithubAuth),
@@ -288,6 +318,7 @@ func (s *Settings) RedactClone() (*Settings, error) {
 		&clone.Smtp.Password,
 		&clone.S3.Secret,
 		&clone.Backups.S3.Secret,
+		&clone.Metrics.Token,
 		&clone.AdminAuthToken.Secret,
 		&clone.AdminPasswordResetToken.Secret,
 		&clone.AdminFileToken.Secret,
@@ -457,6 +488,30 @@ func (c S3Config) Validate() error {
 
 // -------------------------------------------------------------------
 
+// StorageConfig defines a custom storage driver config.
+type StorageConfig struct {
+	// Driver is the name of the registered storage driver to use
+	// (see core.BaseAppConfig.StorageDrivers).
+	//
+	// Leave it empty to fallback to the S3 or local filesystem.
+	Driver string `form:"driver" json:"driver"`
+
+	// Config is the driver specific config options
+	// (the driver is responsible for validating them).
+	Config map[string]any `form:"config" json:"config"`
+}
+
+// Validate makes StorageConfig validatable by implementing [validation.Validatable] interface.
+func (c StorageConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Driver, validation.Length(1, 100), validation.Match(storageDriverNameRegex)),
+	)
+}
+
+var storageDriverNameRegex = regexp.MustCompile(`^[\w\-]+$`)
+
+// -------------------------------------------------------------------
+
 type BackupsConfig struct {
 	// Cron is a cron expression to schedule auto backups, eg. "* * * * *".
 	//
@@ -471,12 +526,17 @@ type BackupsConfig struct {
 
 	// S3 is an optional S3 storage config specifying where to store the app backups.
 	S3 S3Config `form:"s3" json:"s3"`
+
+	// Storage is an optional custom storage driver config specifying
+	// where to store the app backups (it has precedence over the S3 config).
+	Storage StorageConfig `form:"storage" json:"storage"`
 }
 
 // Validate makes BackupsConfig validatable by implementing [validation.Validatable] interface.
 func (c BackupsConfig) Validate() error {
 	return validation.ValidateStruct(&c,
 		validation.Field(&c.S3),
+		validation.Field(&c.Storage),
 		validation.Field(&c.Cron, validation.By(checkCronExpression)),
 		validation.Field(
 			&c.CronMaxKeep,
@@ -486,6 +546,161 @@ func (c BackupsConfig) Validate() error {
 	)
 }
 
+// -------------------------------------------------------------------
+
+type FilesGCConfig struct {
+	// Cron is a cron expression to schedule the orphaned storage files
+	// cleanup, eg. "0 3 * * *".
+	//
+	// Leave it empty to disable the scheduled cleanup.
+	Cron string `form:"cron" json:"cron"`
+
+	// MinAge is the min age (in hours) of an unreferenced storage file
+	// before it is considered orphaned.
+	//
+	// It prevents deleting the files of the records that are still being saved
+	// and the direct uploads that are not yet attached to a record.
+	MinAge int `form:"minAge" json:"minAge"`
+}
+
+// Validate makes FilesGCConfig validatable by implementing [validation.Validatable] interface.
+func (c FilesGCConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Cron, validation.By(checkCronExpression)),
+		validation.Field(&c.MinAge, validation.Min(1)),
+	)
+}
+
+// -------------------------------------------------------------------
+
+const (
+	FileScanActionReject     = "reject"
+	FileScanActionQuarantine = "quarantine"
+)
+
+type FileScanConfig struct {
+	// Enabled enables the record file uploads scanning.
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// Address is the ClamAV daemon (clamd) socket address,
+	// eg. "tcp://127.0.0.1:3310" or "unix:///var/run/clamav/clamd.ctl".
+	Address string `form:"address" json:"address"`
+
+	// Action specifies what to do with the infected uploads:
+	//   - "reject" - fails the record save with a validation error
+	//   - "quarantine" - moves the file to the local quarantine dir
+	//     and saves the record without it
+	Action string `form:"action" json:"action"`
+
+	// Timeout is the max duration (in seconds) of a single file scan.
+	Timeout int `form:"timeout" json:"timeout"`
+}
+
+// Validate makes FileScanConfig validatable by implementing [validation.Validatable] interface.
+func (c FileScanConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(
+			&c.Address,
+			validation.When(c.Enabled, validation.Required),
+			validation.Match(clamdAddressRegex),
+		),
+		validation.Field(
+			&c.Action,
+			validation.When(c.Enabled, validation.Required),
+			validation.In(FileScanActionReject, FileScanActionQuarantine),
+		),
+		validation.Field(&c.Timeout, validation.Min(1)),
+	)
+}
+
+var clamdAddressRegex = regexp.MustCompile(`^(tcp://[^/\s]+|unix:///\S+)$`)
+
+// -------------------------------------------------------------------
+
+type FileEncryptionConfig struct {
+	// Enabled enables the encryption at rest of the newly stored files
+	// with the app encryption key (aka. the EncryptionEnv app config).
+	//
+	// The already encrypted files remain readable even after disabling it
+	// as long as the app encryption key is set.
+	Enabled bool `form:"enabled" json:"enabled"`
+}
+
+// -------------------------------------------------------------------
+
+type TenancyConfig struct {
+	// Header is the name of the request header used to resolve
+	// the tenant of the multi-tenant collection requests, eg. "X-Tenant".
+	//
+	// Leave it empty to disable the header tenant resolution.
+	Header string `form:"header" json:"header"`
+
+	// Subdomain enables the tenant resolution from the leftmost
+	// request host label (eg. "acme" for "acme.example.com").
+	Subdomain bool `form:"subdomain" json:"subdomain"`
+}
+
+// Validate makes TenancyConfig validatable by implementing [validation.Validatable] interface.
+func (c TenancyConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(
+			&c.Header,
+			validation.Length(1, 100),
+			validation.Match(headerNameRegex),
+		),
+	)
+}
+
+var headerNameRegex = regexp.MustCompile(`^[\w\-]+$`)
+
+// -------------------------------------------------------------------
+
+type MetricsConfig struct {
+	// Enabled enables the Prometheus metrics endpoint (GET /api/metrics).
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// Token is an optional bearer token that could be used by the
+	// metrics scrapers as alternative to the admin auth
+	// (eg. "Authorization: Bearer YOUR_TOKEN").
+	Token string `form:"token" json:"token"`
+}
+
+// Validate makes MetricsConfig validatable by implementing [validation.Validatable] interface.
+func (c MetricsConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Token, validation.Length(30, 255)),
+	)
+}
+
+// -------------------------------------------------------------------
+
+type TracingConfig struct {
+	// Enabled enables the OpenTelemetry traces export.
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// Endpoint is the OTLP/HTTP traces collector url
+	// (eg. "http://localhost:4318/v1/traces").
+	Endpoint string `form:"endpoint" json:"endpoint"`
+
+	// ServiceName is the exported "service.name" resource attribute.
+	ServiceName string `form:"serviceName" json:"serviceName"`
+
+	// SampleRatio is the ratio (0-1) of the sampled new traces.
+	//
+	// The traces continued from an incoming "traceparent" header
+	// respect the sampling decision of the caller.
+	SampleRatio float64 `form:"sampleRatio" json:"sampleRatio"`
+}
+
+// Validate makes TracingConfig validatable by implementing [validation.Validatable] interface.
+func (c TracingConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Endpoint, is.URL, validation.When(c.Enabled, validation.Required)),
+		validation.Field(&c.ServiceName, validation.When(c.Enabled, validation.Required), validation.Length(0, 100)),
+		validation.Field(&c.SampleRatio, validation.Min(0.0), validation.Max(1.0)),
+	)
+}
+
 func checkCronExpression(value any) error {
 	v, _ := value.(string)
 	if v == "" {
@@ -511,6 +726,7 @@ type MetaConfig struct {
 	VerificationTemplate       EmailTemplate `form:"verificationTemplate" json:"verificationTemplate"`
 	ResetPasswordTemplate      EmailTemplate `form:"resetPasswordTemplate" json:"resetPasswordTemplate"`
 	ConfirmEmailChangeTemplate EmailTemplate `form:"confirmEmailChangeTemplate" json:"confirmEmailChangeTemplate"`
+	OTPTemplate                EmailTemplate `form:"otpTemplate" json:"otpTemplate"`
 }
 
 // Validate makes MetaConfig validatable by implementing [validation.Validatable] interface.
@@ -523,6 +739,25 @@ func (c MetaConfig) Validate() error {
 		validation.Field(&c.VerificationTemplate, validation.Required),
 		validation.Field(&c.ResetPasswordTemplate, validation.Required),
 		validation.Field(&c.ConfirmEmailChangeTemplate, validation.Required),
+		// note: Skip prevents the default EmailTemplate.Validate call
+		validation.Field(&c.OTPTemplate, validation.Required, validation.By(checkOTPTemplate), validation.Skip),
+	)
+}
+
+// checkOTPTemplate validates the OTP email template.
+//
+// Unlike the other email templates, it requires only the {TOKEN}
+// (aka. the code) body placeholder and the ActionUrl link is optional.
+func checkOTPTemplate(value any) error {
+	t, _ := value.(EmailTemplate)
+
+	return validation.ValidateStruct(&t,
+		validation.Field(&t.Subject, validation.Required),
+		validation.Field(
+			&t.Body,
+			validation.Required,
+			validation.By(checkPlaceholderParams(EmailPlaceholderToken)),
+		),
 	)
 }
 
@@ -617,12 +852,111 @@ type LogsConfig struct {
 	MaxDays  int  `form:"maxDays" json:"maxDays"`
 	MinLevel int  `form:"minLevel" json:"minLevel"`
 	LogIp    bool `form:"logIp" json:"logIp"`
+
+	// Sinks are extra logs destinations that receive the app logs
+	// in parallel with (and independently from) the logs db.
+	Sinks []LogSinkConfig `form:"sinks" json:"sinks"`
+
+	// Archive enables the archival of the expired logs into gzip compressed
+	// NDJSON files in the backups filesystem before their deletion.
+	//
+	// The expired logs are archived and deleted by an hourly background job
+	// (and once after each settings change).
+	Archive bool `form:"archive" json:"archive"`
+
+	// SlowQueryThreshold is the min execution time (in ms) of a data db
+	// statement to be logged as a WARN "slowQuery" log (0 disables it).
+	SlowQueryThreshold int `form:"slowQueryThreshold" json:"slowQueryThreshold"`
+
+	// SlowQueryExplain enables the "EXPLAIN (FORMAT JSON)" plan capture
+	// of the logged slow SELECT queries (Postgres only).
+	//
+	// The plans are captured in the background with a limited concurrency
+	// (the plans of the slow queries over the limit are skipped).
+	SlowQueryExplain bool `form:"slowQueryExplain" json:"slowQueryExplain"`
 }
 
 // Validate makes LogsConfig validatable by implementing [validation.Validatable] interface.
 func (c LogsConfig) Validate() error {
 	return validation.ValidateStruct(&c,
 		validation.Field(&c.MaxDays, validation.Min(0)),
+		validation.Field(&c.SlowQueryThreshold, validation.Min(0)),
+		validation.Field(&c.Sinks, validation.Length(0, 20)),
+	)
+}
+
+const (
+	LogSinkTypeStdout = "stdout"
+	LogSinkTypeFile   = "file"
+	LogSinkTypeSyslog = "syslog"
+	LogSinkTypeOTLP   = "otlp"
+)
+
+type LogSinkConfig struct {
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// Type is the sink type - "stdout", "file", "syslog" or "otlp".
+	Type string `form:"type" json:"type"`
+
+	// MinLevel is the min level of the logs written to the sink
+	// (it is independent from the logs db MinLevel).
+	MinLevel int `form:"minLevel" json:"minLevel"`
+
+	// Redact is a list of log data attribute paths whose values
+	// will be masked before writing to the sink, eg. "auth", "details.password".
+	//
+	// Use "*" to match any key at the specific path segment, eg. "*.token".
+	Redact []string `form:"redact" json:"redact"`
+
+	// Path is the "file" sink logs file path
+	// (relative paths are resolved against the app data dir).
+	Path string `form:"path" json:"path"`
+
+	// MaxSize is the max "file" sink logs file size in MB
+	// before rotation (0 means no rotation).
+	MaxSize int `form:"maxSize" json:"maxSize"`
+
+	// MaxBackups is the max number of the rotated "file" sink logs files to keep.
+	MaxBackups int `form:"maxBackups" json:"maxBackups"`
+
+	// Network is the "syslog" sink server network - "udp" (default), "tcp" or "unix".
+	Network string `form:"network" json:"network"`
+
+	// Address is the "syslog" sink server address, eg. "localhost:514".
+	Address string `form:"address" json:"address"`
+
+	// Tag is the "syslog" sink messages app name (default to "postgresbase").
+	Tag string `form:"tag" json:"tag"`
+
+	// Endpoint is the "otlp" sink OTLP/HTTP logs collector url
+	// (eg. "http://localhost:4318/v1/logs").
+	Endpoint string `form:"endpoint" json:"endpoint"`
+}
+
+// Validate makes LogSinkConfig validatable by implementing [validation.Validatable] interface.
+func (c LogSinkConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(
+			&c.Type,
+			validation.Required,
+			validation.In(LogSinkTypeStdout, LogSinkTypeFile, LogSinkTypeSyslog, LogSinkTypeOTLP),
+		),
+		validation.Field(&c.Redact, validation.Each(validation.Required, validation.Length(1, 255))),
+		validation.Field(
+			&c.Path,
+			validation.When(c.Type == LogSinkTypeFile, validation.Required),
+			validation.Length(0, 500),
+		),
+		validation.Field(&c.MaxSize, validation.Min(0)),
+		validation.Field(&c.MaxBackups, validation.Min(0)),
+		validation.Field(&c.Network, validation.In("udp", "tcp", "unix")),
+		validation.Field(
+			&c.Address,
+			validation.When(c.Type == LogSinkTypeSyslog, validation.Required),
+			validation.Length(0, 255),
+		),
+		validation.Field(&c.Tag, validation.Length(0, 48)),
+		validation.Field(&c.Endpoint, is.URL, validation.When(c.Type == LogSinkTypeOTLP, validation.Required)),
 	)
 }
 
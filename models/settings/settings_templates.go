@@ -6,6 +6,7 @@ const (
 	EmailPlaceholderAppUrl    string = "{APP_URL}"
 	EmailPlaceholderToken     string = "{TOKEN}"
 	EmailPlaceholderActionUrl string = "{ACTION_URL}"
+	EmailPlaceholderOTPId     string = "{OTP_ID}"
 )
 
 var defaultVerificationTemplate = EmailTemplate{
@@ -52,3 +53,17 @@ var defaultConfirmEmailChangeTemplate = EmailTemplate{
 </p>`,
 	ActionUrl: EmailPlaceholderAppUrl + "/_/#/auth/confirm-email-change/" + EmailPlaceholderToken,
 }
+
+// note: the default OTP template doesn't have a login link because the
+// code is expected to be submitted by the app that requested it
+// (custom templates could still link to the app with the OTP_ID and TOKEN placeholders).
+var defaultOTPTemplate = EmailTemplate{
+	Subject: "Your " + EmailPlaceholderAppName + " login code",
+	Body: `<p>Hello,</p>
+<p>Your one-time login code is: <strong>` + EmailPlaceholderToken + `</strong></p>
+<p><i>If you didn't ask for a login code, you can ignore this email.</i></p>
+<p>
+  Thanks,<br/>
+  ` + EmailPlaceholderAppName + ` team
+</p>`,
+}
@@ -269,6 +269,7 @@ func TestSettingsRedactClone(t *testing.T) {
 	s1.Smtp.Password = testSecret
 	s1.S3.Secret = testSecret
 	s1.Backups.S3.Secret = testSecret
+	s1.Metrics.Token = testSecret
 	s1.AdminAuthToken.Secret = testSecret
 	s1.AdminPasswordResetToken.Secret = testSecret
 	s1.AdminFileToken.Secret = testSecret
@@ -626,6 +627,12 @@ func TestMetaConfigValidate(t *testing.T) {
 		Body:      "test" + settings.EmailPlaceholderActionUrl,
 	}
 
+	// the OTP template requires only the code placeholder in its body
+	otpTemplate := settings.EmailTemplate{
+		Subject: "test",
+		Body:    "test" + settings.EmailPlaceholderToken,
+	}
+
 	scenarios := []struct {
 		config      settings.MetaConfig
 		expectError bool
@@ -645,6 +652,7 @@ func TestMetaConfigValidate(t *testing.T) {
 				VerificationTemplate:       invalidTemplate,
 				ResetPasswordTemplate:      invalidTemplate,
 				ConfirmEmailChangeTemplate: invalidTemplate,
+				OTPTemplate:                invalidTemplate,
 			},
 			true,
 		},
@@ -658,6 +666,21 @@ func TestMetaConfigValidate(t *testing.T) {
 				VerificationTemplate:       noPlaceholdersTemplate,
 				ResetPasswordTemplate:      noPlaceholdersTemplate,
 				ConfirmEmailChangeTemplate: noPlaceholdersTemplate,
+				OTPTemplate:                noPlaceholdersTemplate,
+			},
+			true,
+		},
+		// invalid data (OTP template without the code placeholder)
+		{
+			settings.MetaConfig{
+				AppName:                    "test",
+				AppUrl:                     "https://example.com",
+				SenderName:                 "test",
+				SenderAddress:              "test@example.com",
+				VerificationTemplate:       withPlaceholdersTemplate,
+				ResetPasswordTemplate:      withPlaceholdersTemplate,
+				ConfirmEmailChangeTemplate: withPlaceholdersTemplate,
+				OTPTemplate:                withPlaceholdersTemplate,
 			},
 			true,
 		},
@@ -671,6 +694,7 @@ func TestMetaConfigValidate(t *testing.T) {
 				VerificationTemplate:       withPlaceholdersTemplate,
 				ResetPasswordTemplate:      withPlaceholdersTemplate,
 				ConfirmEmailChangeTemplate: withPlaceholdersTemplate,
+				OTPTemplate:                otpTemplate,
 			},
 			false,
 		},
@@ -757,6 +781,288 @@ func TestBackupsConfigValidate(t *testing.T) {
 	}
 }
 
+func TestTenancyConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		config         settings.TenancyConfig
+		expectedErrors []string
+	}{
+		{
+			"zero value",
+			settings.TenancyConfig{},
+			[]string{},
+		},
+		{
+			"invalid header name",
+			settings.TenancyConfig{
+				Header: "X Tenant:",
+			},
+			[]string{"header"},
+		},
+		{
+			"valid data",
+			settings.TenancyConfig{
+				Header:    "X-Tenant_Id",
+				Subdomain: true,
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		result := s.config.Validate()
+
+		// parse errors
+		errs, ok := result.(validation.Errors)
+		if !ok && result != nil {
+			t.Errorf("[%s] Failed to parse errors %v", s.name, result)
+			continue
+		}
+
+		// check errors
+		if len(errs) > len(s.expectedErrors) {
+			t.Errorf("[%s] Expected error keys %v, got %v", s.name, s.expectedErrors, errs)
+		}
+		for _, k := range s.expectedErrors {
+			if _, ok := errs[k]; !ok {
+				t.Errorf("[%s] Missing expected error key %q in %v", s.name, k, errs)
+			}
+		}
+	}
+}
+
+func TestMetricsConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		config         settings.MetricsConfig
+		expectedErrors []string
+	}{
+		{
+			"zero value",
+			settings.MetricsConfig{},
+			[]string{},
+		},
+		{
+			"too short token",
+			settings.MetricsConfig{
+				Enabled: true,
+				Token:   "test",
+			},
+			[]string{"token"},
+		},
+		{
+			"valid data",
+			settings.MetricsConfig{
+				Enabled: true,
+				Token:   strings.Repeat("a", 30),
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		result := s.config.Validate()
+
+		// parse errors
+		errs, ok := result.(validation.Errors)
+		if !ok && result != nil {
+			t.Errorf("[%s] Failed to parse errors %v", s.name, result)
+			continue
+		}
+
+		// check errors
+		if len(errs) > len(s.expectedErrors) {
+			t.Errorf("[%s] Expected error keys %v, got %v", s.name, s.expectedErrors, errs)
+		}
+		for _, k := range s.expectedErrors {
+			if _, ok := errs[k]; !ok {
+				t.Errorf("[%s] Missing expected error key %q in %v", s.name, k, errs)
+			}
+		}
+	}
+}
+
+func TestTracingConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		config         settings.TracingConfig
+		expectedErrors []string
+	}{
+		{
+			"zero value",
+			settings.TracingConfig{},
+			[]string{},
+		},
+		{
+			"enabled with missing required fields",
+			settings.TracingConfig{
+				Enabled: true,
+			},
+			[]string{"endpoint", "serviceName"},
+		},
+		{
+			"invalid data",
+			settings.TracingConfig{
+				Endpoint:    "invalid",
+				SampleRatio: 1.5,
+			},
+			[]string{"endpoint", "sampleRatio"},
+		},
+		{
+			"valid data",
+			settings.TracingConfig{
+				Enabled:     true,
+				Endpoint:    "http://localhost:4318/v1/traces",
+				ServiceName: "test",
+				SampleRatio: 0.5,
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		result := s.config.Validate()
+
+		// parse errors
+		errs, ok := result.(validation.Errors)
+		if !ok && result != nil {
+			t.Errorf("[%s] Failed to parse errors %v", s.name, result)
+			continue
+		}
+
+		// check errors
+		if len(errs) > len(s.expectedErrors) {
+			t.Errorf("[%s] Expected error keys %v, got %v", s.name, s.expectedErrors, errs)
+		}
+		for _, k := range s.expectedErrors {
+			if _, ok := errs[k]; !ok {
+				t.Errorf("[%s] Missing expected error key %q in %v", s.name, k, errs)
+			}
+		}
+	}
+}
+
+func TestFilesGCConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		config         settings.FilesGCConfig
+		expectedErrors []string
+	}{
+		{
+			"zero value",
+			settings.FilesGCConfig{},
+			[]string{},
+		},
+		{
+			"invalid data",
+			settings.FilesGCConfig{
+				Cron:   "invalid",
+				MinAge: -1,
+			},
+			[]string{"cron", "minAge"},
+		},
+		{
+			"valid data",
+			settings.FilesGCConfig{
+				Cron:   "0 3 * * *",
+				MinAge: 1,
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		result := s.config.Validate()
+
+		// parse errors
+		errs, ok := result.(validation.Errors)
+		if !ok && result != nil {
+			t.Errorf("[%s] Failed to parse errors %v", s.name, result)
+			continue
+		}
+
+		// check errors
+		if len(errs) > len(s.expectedErrors) {
+			t.Errorf("[%s] Expected error keys %v, got %v", s.name, s.expectedErrors, errs)
+		}
+		for _, k := range s.expectedErrors {
+			if _, ok := errs[k]; !ok {
+				t.Errorf("[%s] Missing expected error key %q in %v", s.name, k, errs)
+			}
+		}
+	}
+}
+
+func TestFileScanConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		config         settings.FileScanConfig
+		expectedErrors []string
+	}{
+		{
+			"zero value",
+			settings.FileScanConfig{},
+			[]string{},
+		},
+		{
+			"enabled with missing data",
+			settings.FileScanConfig{
+				Enabled: true,
+			},
+			[]string{"address", "action"},
+		},
+		{
+			"invalid data",
+			settings.FileScanConfig{
+				Address: "127.0.0.1:3310",
+				Action:  "invalid",
+				Timeout: -1,
+			},
+			[]string{"address", "action", "timeout"},
+		},
+		{
+			"valid tcp address",
+			settings.FileScanConfig{
+				Enabled: true,
+				Address: "tcp://127.0.0.1:3310",
+				Action:  settings.FileScanActionReject,
+				Timeout: 10,
+			},
+			[]string{},
+		},
+		{
+			"valid unix address",
+			settings.FileScanConfig{
+				Enabled: true,
+				Address: "unix:///var/run/clamav/clamd.ctl",
+				Action:  settings.FileScanActionQuarantine,
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		result := s.config.Validate()
+
+		// parse errors
+		errs, ok := result.(validation.Errors)
+		if !ok && result != nil {
+			t.Errorf("[%s] Failed to parse errors %v", s.name, result)
+			continue
+		}
+
+		// check errors
+		if len(errs) > len(s.expectedErrors) {
+			t.Errorf("[%s] Expected error keys %v, got %v", s.name, s.expectedErrors, errs)
+		}
+		for _, k := range s.expectedErrors {
+			if _, ok := errs[k]; !ok {
+				t.Errorf("[%s] Missing expected error key %q in %v", s.name, k, errs)
+			}
+		}
+	}
+}
+
 func TestEmailTemplateValidate(t *testing.T) {
 	scenarios := []struct {
 		emailTemplate  settings.EmailTemplate
@@ -896,11 +1202,31 @@ func TestLogsConfigValidate(t *testing.T) {
 			settings.LogsConfig{MaxDays: -10},
 			true,
 		},
+		// invalid slow query threshold
+		{
+			settings.LogsConfig{MaxDays: 1, SlowQueryThreshold: -1},
+			true,
+		},
+		// invalid sink
+		{
+			settings.LogsConfig{MaxDays: 1, Sinks: []settings.LogSinkConfig{{Type: "invalid"}}},
+			true,
+		},
 		// valid data
 		{
 			settings.LogsConfig{MaxDays: 1},
 			false,
 		},
+		// valid data with slow queries log
+		{
+			settings.LogsConfig{MaxDays: 1, SlowQueryThreshold: 100, SlowQueryExplain: true},
+			false,
+		},
+		// valid data with sinks
+		{
+			settings.LogsConfig{MaxDays: 1, Sinks: []settings.LogSinkConfig{{Type: settings.LogSinkTypeStdout}}},
+			false,
+		},
 	}
 
 	for i, scenario := range scenarios {
@@ -916,6 +1242,94 @@ func TestLogsConfigValidate(t *testing.T) {
 	}
 }
 
+func TestLogSinkConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		name        string
+		config      settings.LogSinkConfig
+		expectError bool
+	}{
+		{
+			"zero values",
+			settings.LogSinkConfig{},
+			true,
+		},
+		{
+			"invalid type",
+			settings.LogSinkConfig{Type: "invalid"},
+			true,
+		},
+		{
+			"empty redact rule",
+			settings.LogSinkConfig{Type: settings.LogSinkTypeStdout, Redact: []string{""}},
+			true,
+		},
+		{
+			"valid stdout sink",
+			settings.LogSinkConfig{Type: settings.LogSinkTypeStdout, MinLevel: -4, Redact: []string{"auth", "*.password"}},
+			false,
+		},
+		{
+			"file sink without path",
+			settings.LogSinkConfig{Type: settings.LogSinkTypeFile},
+			true,
+		},
+		{
+			"file sink with negative rotation options",
+			settings.LogSinkConfig{Type: settings.LogSinkTypeFile, Path: "logs.json", MaxSize: -1, MaxBackups: -1},
+			true,
+		},
+		{
+			"valid file sink",
+			settings.LogSinkConfig{Type: settings.LogSinkTypeFile, Path: "logs.json", MaxSize: 10, MaxBackups: 3},
+			false,
+		},
+		{
+			"syslog sink without address",
+			settings.LogSinkConfig{Type: settings.LogSinkTypeSyslog},
+			true,
+		},
+		{
+			"syslog sink with invalid network",
+			settings.LogSinkConfig{Type: settings.LogSinkTypeSyslog, Address: "localhost:514", Network: "invalid"},
+			true,
+		},
+		{
+			"valid syslog sink",
+			settings.LogSinkConfig{Type: settings.LogSinkTypeSyslog, Address: "localhost:514", Network: "tcp"},
+			false,
+		},
+		{
+			"otlp sink without endpoint",
+			settings.LogSinkConfig{Type: settings.LogSinkTypeOTLP},
+			true,
+		},
+		{
+			"otlp sink with invalid endpoint",
+			settings.LogSinkConfig{Type: settings.LogSinkTypeOTLP, Endpoint: "invalid"},
+			true,
+		},
+		{
+			"valid otlp sink",
+			settings.LogSinkConfig{Type: settings.LogSinkTypeOTLP, Endpoint: "http://localhost:4318/v1/logs"},
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.config.Validate()
+
+			if result != nil && !s.expectError {
+				t.Fatalf("Didn't expect error, got %v", result)
+			}
+
+			if result == nil && s.expectError {
+				t.Fatal("Expected error, got nil")
+			}
+		})
+	}
+}
+
 func TestAuthProviderConfigValidate(t *testing.T) {
 	scenarios := []struct {
 		config      settings.AuthProviderConfig
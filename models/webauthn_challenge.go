@@ -0,0 +1,20 @@
+package models
+
+import "github.com/AlperRehaYAZGAN/postgresbase/tools/types"
+
+var _ Model = (*WebAuthnChallenge)(nil)
+
+// WebAuthnChallenge defines a single already used WebAuthn ceremony challenge.
+//
+// The model id is the sha256 hash of the challenge and the model
+// is kept only until the challenge expiration to prevent its reuse.
+type WebAuthnChallenge struct {
+	BaseModel
+
+	CollectionId string         `db:"collectionId" json:"collectionId"`
+	Expires      types.DateTime `db:"expires" json:"expires"`
+}
+
+func (m *WebAuthnChallenge) TableName() string {
+	return "_webauthnChallenges"
+}
@@ -0,0 +1,29 @@
+package models
+
+var _ Model = (*WebAuthnCredential)(nil)
+
+// WebAuthnCredential defines a single auth record WebAuthn
+// public key credential (aka. passkey).
+type WebAuthnCredential struct {
+	BaseModel
+
+	CollectionId string `db:"collectionId" json:"collectionId"`
+	RecordId     string `db:"recordId" json:"recordId"`
+	Name         string `db:"name" json:"name"`
+
+	// CredentialId is the base64url encoded authenticator credential id.
+	CredentialId string `db:"credentialId" json:"credentialId"`
+
+	// PublicKey is the base64url encoded COSE_Key credential public key.
+	PublicKey string `db:"publicKey" json:"-"`
+
+	// SignCount is the last known authenticator signature counter.
+	SignCount int64 `db:"signCount" json:"-"`
+
+	// AAGUID is the hex encoded authenticator model identifier.
+	AAGUID string `db:"aaguid" json:"aaguid"`
+}
+
+func (m *WebAuthnCredential) TableName() string {
+	return "_webauthnCredentials"
+}
@@ -23,7 +23,6 @@ import (
 	"github.com/AlperRehaYAZGAN/postgresbase/models"
 	"github.com/AlperRehaYAZGAN/postgresbase/models/schema"
 	"github.com/AlperRehaYAZGAN/postgresbase/tokens"
-	"github.com/AlperRehaYAZGAN/postgresbase/tools/cron"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/filesystem"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/hook"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/inflector"
@@ -80,6 +79,14 @@ func hooksBinds(app core.App, loader *goja.Runtime, executors *vmsPool) {
 				}
 
 				err := executors.run(func(executor *goja.Runtime) error {
+					// bind the request-scoped app logger (if the event is part of a request)
+					for _, arg := range handlerArgs {
+						if e, ok := arg.(core.ContextEvent); ok {
+							defer bindRequestApp(executor, app, e.Context())()
+							break
+						}
+					}
+
 					executor.Set("__args", handlerArgs)
 					res, err := executor.RunProgram(pr)
 					executor.Set("__args", goja.Undefined())
@@ -109,14 +116,10 @@ func hooksBinds(app core.App, loader *goja.Runtime, executors *vmsPool) {
 }
 
 func cronBinds(app core.App, loader *goja.Runtime, executors *vmsPool) {
-	scheduler := cron.New()
-
-	var wasServeTriggered bool
-
 	loader.Set("cronAdd", func(jobId, cronExpr, handler string) {
 		pr := goja.MustCompile("", "{("+handler+").apply(undefined)}", true)
 
-		err := scheduler.Add(jobId, cronExpr, func() {
+		err := app.Cron().Add(jobId, cronExpr, func() {
 			err := executors.run(func(executor *goja.Runtime) error {
 				_, err := executor.RunProgram(pr)
 				return err
@@ -133,42 +136,29 @@ func cronBinds(app core.App, loader *goja.Runtime, executors *vmsPool) {
 		if err != nil {
 			panic("[cronAdd] failed to register cron job " + jobId + ": " + err.Error())
 		}
-
-		// start the ticker (if not already)
-		if wasServeTriggered && scheduler.Total() > 0 && !scheduler.HasStarted() {
-			scheduler.Start()
-		}
 	})
 
 	loader.Set("cronRemove", func(jobId string) {
-		scheduler.Remove(jobId)
-
-		// stop the ticker if there are no other jobs
-		if scheduler.Total() == 0 {
-			scheduler.Stop()
-		}
+		app.Cron().Remove(jobId)
 	})
 
-	app.OnBeforeServe().Add(func(e *core.ServeEvent) error {
-		// start the ticker (if not already)
-		if scheduler.Total() > 0 && !scheduler.HasStarted() {
-			scheduler.Start()
-		}
-
-		wasServeTriggered = true
+	loader.Set("cronPause", func(jobId string) error {
+		return app.Cron().Pause(jobId)
+	})
 
-		return nil
+	loader.Set("cronResume", func(jobId string) error {
+		return app.Cron().Resume(jobId)
 	})
 }
 
 func routerBinds(app core.App, loader *goja.Runtime, executors *vmsPool) {
 	loader.Set("routerAdd", func(method string, path string, handler goja.Value, middlewares ...goja.Value) {
-		wrappedMiddlewares, err := wrapMiddlewares(executors, middlewares...)
+		wrappedMiddlewares, err := wrapMiddlewares(app, executors, middlewares...)
 		if err != nil {
 			panic("[routerAdd] failed to wrap middlewares: " + err.Error())
 		}
 
-		wrappedHandler, err := wrapHandler(executors, handler)
+		wrappedHandler, err := wrapHandler(app, executors, handler)
 		if err != nil {
 			panic("[routerAdd] failed to wrap handler: " + err.Error())
 		}
@@ -181,7 +171,7 @@ func routerBinds(app core.App, loader *goja.Runtime, executors *vmsPool) {
 	})
 
 	loader.Set("routerUse", func(middlewares ...goja.Value) {
-		wrappedMiddlewares, err := wrapMiddlewares(executors, middlewares...)
+		wrappedMiddlewares, err := wrapMiddlewares(app, executors, middlewares...)
 		if err != nil {
 			panic("[routerUse] failed to wrap middlewares: " + err.Error())
 		}
@@ -193,7 +183,7 @@ func routerBinds(app core.App, loader *goja.Runtime, executors *vmsPool) {
 	})
 
 	loader.Set("routerPre", func(middlewares ...goja.Value) {
-		wrappedMiddlewares, err := wrapMiddlewares(executors, middlewares...)
+		wrappedMiddlewares, err := wrapMiddlewares(app, executors, middlewares...)
 		if err != nil {
 			panic("[routerPre] failed to wrap middlewares: " + err.Error())
 		}
@@ -205,7 +195,7 @@ func routerBinds(app core.App, loader *goja.Runtime, executors *vmsPool) {
 	})
 }
 
-func wrapHandler(executors *vmsPool, handler goja.Value) (echo.HandlerFunc, error) {
+func wrapHandler(app core.App, executors *vmsPool, handler goja.Value) (echo.HandlerFunc, error) {
 	if handler == nil {
 		return nil, errors.New("handler must be non-nil")
 	}
@@ -219,6 +209,8 @@ func wrapHandler(executors *vmsPool, handler goja.Value) (echo.HandlerFunc, erro
 
 		wrappedHandler := func(c echo.Context) error {
 			return executors.run(func(executor *goja.Runtime) error {
+				defer bindRequestApp(executor, app, c.Request().Context())()
+
 				executor.Set("__args", []any{c})
 				res, err := executor.RunProgram(pr)
 				executor.Set("__args", goja.Undefined())
@@ -240,7 +232,7 @@ func wrapHandler(executors *vmsPool, handler goja.Value) (echo.HandlerFunc, erro
 	}
 }
 
-func wrapMiddlewares(executors *vmsPool, rawMiddlewares ...goja.Value) ([]echo.MiddlewareFunc, error) {
+func wrapMiddlewares(app core.App, executors *vmsPool, rawMiddlewares ...goja.Value) ([]echo.MiddlewareFunc, error) {
 	wrappedMiddlewares := make([]echo.MiddlewareFunc, len(rawMiddlewares))
 
 	for i, m := range rawMiddlewares {
@@ -258,6 +250,8 @@ func wrapMiddlewares(executors *vmsPool, rawMiddlewares ...goja.Value) ([]echo.M
 			wrappedMiddlewares[i] = func(next echo.HandlerFunc) echo.HandlerFunc {
 				return func(c echo.Context) error {
 					return executors.run(func(executor *goja.Runtime) error {
+						defer bindRequestApp(executor, app, c.Request().Context())()
+
 						executor.Set("__args", []any{next})
 						executor.Set("__args2", []any{c})
 						res, err := executor.RunProgram(pr)
@@ -283,6 +277,35 @@ func wrapMiddlewares(executors *vmsPool, rawMiddlewares ...goja.Value) ([]echo.M
 	return wrappedMiddlewares, nil
 }
 
+// requestApp is a [core.App] wrapper with a request-scoped logger.
+type requestApp struct {
+	core.App
+
+	logger *slog.Logger
+}
+
+// Logger returns the request-scoped app logger (see [core.App.ContextLogger]).
+func (app *requestApp) Logger() *slog.Logger {
+	return app.logger
+}
+
+// bindRequestApp replaces the executor "$app" with a [requestApp] if ctx
+// has a request id, so that the records of the "$app.logger()" calls are
+// correlated with the request.
+//
+// It returns a function to restore the original "$app".
+func bindRequestApp(executor *goja.Runtime, app core.App, ctx context.Context) func() {
+	if core.RequestIdFromContext(ctx) == "" {
+		return func() {}
+	}
+
+	executor.Set("$app", &requestApp{App: app, logger: app.ContextLogger(ctx)})
+
+	return func() {
+		executor.Set("$app", app)
+	}
+}
+
 func baseBinds(vm *goja.Runtime) {
 	vm.SetFieldNameMapper(FieldMapper{})
 
@@ -469,6 +492,7 @@ func mailsBinds(vm *goja.Runtime) {
 	obj.Set("sendRecordPasswordReset", mails.SendRecordPasswordReset)
 	obj.Set("sendRecordVerification", mails.SendRecordVerification)
 	obj.Set("sendRecordChangeEmail", mails.SendRecordChangeEmail)
+	obj.Set("sendRecordOTP", mails.SendRecordOTP)
 }
 
 func tokensBinds(vm *goja.Runtime) {
@@ -603,6 +627,8 @@ func formsBinds(vm *goja.Runtime) {
 	registerFactoryAsConstructor(vm, "RecordEmailChangeConfirmForm", forms.NewRecordEmailChangeConfirm)
 	registerFactoryAsConstructor(vm, "RecordEmailChangeRequestForm", forms.NewRecordEmailChangeRequest)
 	registerFactoryAsConstructor(vm, "RecordOAuth2LoginForm", forms.NewRecordOAuth2Login)
+	registerFactoryAsConstructor(vm, "RecordOTPLoginForm", forms.NewRecordOTPLogin)
+	registerFactoryAsConstructor(vm, "RecordOTPRequestForm", forms.NewRecordOTPRequest)
 	registerFactoryAsConstructor(vm, "RecordPasswordLoginForm", forms.NewRecordPasswordLogin)
 	registerFactoryAsConstructor(vm, "RecordPasswordResetConfirmForm", forms.NewRecordPasswordResetConfirm)
 	registerFactoryAsConstructor(vm, "RecordPasswordResetRequestForm", forms.NewRecordPasswordResetRequest)
@@ -635,6 +661,7 @@ func apisBinds(vm *goja.Runtime) {
 
 	// record helpers
 	obj.Set("requestInfo", apis.RequestInfo)
+	obj.Set("requestId", apis.RequestId)
 	obj.Set("recordAuthResponse", apis.RecordAuthResponse)
 	obj.Set("enrichRecord", apis.EnrichRecord)
 	obj.Set("enrichRecords", apis.EnrichRecords)
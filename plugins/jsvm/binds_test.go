@@ -1,9 +1,11 @@
 package jsvm
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
@@ -20,6 +22,7 @@ import (
 	"github.com/AlperRehaYAZGAN/postgresbase/models/schema"
 	"github.com/AlperRehaYAZGAN/postgresbase/tests"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/filesystem"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/logger"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/mailer"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/security"
 	"github.com/dop251/goja"
@@ -595,7 +598,7 @@ func TestMailsBindsCount(t *testing.T) {
 	vm := goja.New()
 	mailsBinds(vm)
 
-	testBindsCount(vm, "$mails", 4, t)
+	testBindsCount(vm, "$mails", 5, t)
 }
 
 func TestMailsBinds(t *testing.T) {
@@ -978,7 +981,7 @@ func TestFormsBinds(t *testing.T) {
 	vm := goja.New()
 	formsBinds(vm)
 
-	testBindsCount(vm, "this", 20, t)
+	testBindsCount(vm, "this", 22, t)
 }
 
 func TestApisBindsCount(t *testing.T) {
@@ -989,7 +992,7 @@ func TestApisBindsCount(t *testing.T) {
 	apisBinds(vm)
 
 	testBindsCount(vm, "this", 6, t)
-	testBindsCount(vm, "$apis", 14, t)
+	testBindsCount(vm, "$apis", 15, t)
 }
 
 func TestApisBindsApiError(t *testing.T) {
@@ -1336,7 +1339,7 @@ func TestCronBindsCount(t *testing.T) {
 	vm := goja.New()
 	cronBinds(app, vm, nil)
 
-	testBindsCount(vm, "this", 2, t)
+	testBindsCount(vm, "this", 4, t)
 }
 
 func TestHooksBindsCount(t *testing.T) {
@@ -1346,7 +1349,7 @@ func TestHooksBindsCount(t *testing.T) {
 	vm := goja.New()
 	hooksBinds(app, vm, nil)
 
-	testBindsCount(vm, "this", 88, t)
+	testBindsCount(vm, "this", 95, t)
 }
 
 func TestHooksBinds(t *testing.T) {
@@ -1525,6 +1528,74 @@ func TestRouterBinds(t *testing.T) {
 	}
 }
 
+func TestRouterBindsRequestLogger(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().Logs.MaxDays = 1
+	app.Settings().Logs.MinLevel = int(slog.LevelInfo)
+
+	vmFactory := func() *goja.Runtime {
+		vm := goja.New()
+		baseBinds(vm)
+		vm.Set("$app", app)
+		return vm
+	}
+
+	pool := newPool(1, vmFactory)
+
+	vm := vmFactory()
+	routerBinds(app, vm, pool)
+
+	_, err := vm.RunString(`
+		routerAdd("GET", "/test", (c) => {
+			$app.logger().info("jsvm_request_log")
+
+			return c.noContent(204)
+		})
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := apis.InitApi(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.OnBeforeServe().Trigger(&core.ServeEvent{App: app, Router: e}); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("X-Request-Id", "jsvm_test_id")
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != 204 {
+		t.Fatalf("Expected status 204, got %d", rec.Code)
+	}
+
+	// the original $app should be restored after the request
+	pool.run(func(executor *goja.Runtime) error {
+		if executor.Get("$app").Export() != app {
+			t.Fatal("Expected the original $app to be restored")
+		}
+		return nil
+	})
+
+	app.Logger().Handler().(*logger.BatchHandler).WriteAll(context.Background())
+
+	l := &models.Log{}
+	if err := app.LogsDao().LogQuery().AndWhere(dbx.HashExp{"message": "jsvm_request_log"}).One(l); err != nil {
+		t.Fatal(err)
+	}
+
+	if l.Data["requestId"] != "jsvm_test_id" {
+		t.Fatalf("Expected requestId jsvm_test_id, got %v", l.Data["requestId"])
+	}
+}
+
 func TestFilepathBindsCount(t *testing.T) {
 	app, _ := tests.NewTestApp()
 	defer app.Cleanup()
@@ -45,6 +45,37 @@ declare function cronAdd(
  */
 declare function cronRemove(jobId: string): void;
 
+/**
+ * CronPause pauses a single registered cron job, preventing it from
+ * being executed until cronResume() is called.
+ *
+ * Example:
+ *
+ * ```js
+ * cronPause("hello")
+ * ```
+ *
+ * _Note that this method is available only in pb_hooks context._
+ *
+ * @group PocketBase
+ */
+declare function cronPause(jobId: string): void;
+
+/**
+ * CronResume resumes a single previously paused cron job.
+ *
+ * Example:
+ *
+ * ```js
+ * cronResume("hello")
+ * ```
+ *
+ * _Note that this method is available only in pb_hooks context._
+ *
+ * @group PocketBase
+ */
+declare function cronResume(jobId: string): void;
+
 // -------------------------------------------------------------------
 // routerBinds
 // -------------------------------------------------------------------
@@ -977,6 +1008,7 @@ declare namespace $apis {
   let requireAdminOrOwnerAuth:   apis.requireAdminOrOwnerAuth
   let activityLogger:            apis.activityLogger
   let requestInfo:               apis.requestInfo
+  let requestId:                 apis.requestId
   let recordAuthResponse:        apis.recordAuthResponse
   let gzip:                      middleware.gzip
   let bodyLimit:                 middleware.bodyLimit
@@ -1088,10 +1120,12 @@ declare function migrate(
 /** @group PocketBase */declare function onFileDownloadRequest(handler: (e: core.FileDownloadEvent) => void, ...tags: string[]): void
 /** @group PocketBase */declare function onMailerAfterAdminResetPasswordSend(handler: (e: core.MailerAdminEvent) => void): void
 /** @group PocketBase */declare function onMailerAfterRecordChangeEmailSend(handler: (e: core.MailerRecordEvent) => void, ...tags: string[]): void
+/** @group PocketBase */declare function onMailerAfterRecordOTPSend(handler: (e: core.MailerRecordEvent) => void, ...tags: string[]): void
 /** @group PocketBase */declare function onMailerAfterRecordResetPasswordSend(handler: (e: core.MailerRecordEvent) => void, ...tags: string[]): void
 /** @group PocketBase */declare function onMailerAfterRecordVerificationSend(handler: (e: core.MailerRecordEvent) => void, ...tags: string[]): void
 /** @group PocketBase */declare function onMailerBeforeAdminResetPasswordSend(handler: (e: core.MailerAdminEvent) => void): void
 /** @group PocketBase */declare function onMailerBeforeRecordChangeEmailSend(handler: (e: core.MailerRecordEvent) => void, ...tags: string[]): void
+/** @group PocketBase */declare function onMailerBeforeRecordOTPSend(handler: (e: core.MailerRecordEvent) => void, ...tags: string[]): void
 /** @group PocketBase */declare function onMailerBeforeRecordResetPasswordSend(handler: (e: core.MailerRecordEvent) => void, ...tags: string[]): void
 /** @group PocketBase */declare function onMailerBeforeRecordVerificationSend(handler: (e: core.MailerRecordEvent) => void, ...tags: string[]): void
 /** @group PocketBase */declare function onModelAfterCreate(handler: (e: core.ModelEvent) => void, ...tags: string[]): void
@@ -1108,6 +1142,8 @@ declare function migrate(
 /** @group PocketBase */declare function onRealtimeDisconnectRequest(handler: (e: core.RealtimeDisconnectEvent) => void): void
 /** @group PocketBase */declare function onRecordAfterAuthRefreshRequest(handler: (e: core.RecordAuthRefreshEvent) => void, ...tags: string[]): void
 /** @group PocketBase */declare function onRecordAfterAuthWithOAuth2Request(handler: (e: core.RecordAuthWithOAuth2Event) => void, ...tags: string[]): void
+/** @group PocketBase */declare function onRecordAfterAuthWithOTPRequest(handler: (e: core.RecordAuthWithOTPEvent) => void, ...tags: string[]): void
+/** @group PocketBase */declare function onRecordAfterAuthWithWebAuthnRequest(handler: (e: core.RecordAuthWithWebAuthnEvent) => void, ...tags: string[]): void
 /** @group PocketBase */declare function onRecordAfterAuthWithPasswordRequest(handler: (e: core.RecordAuthWithPasswordEvent) => void, ...tags: string[]): void
 /** @group PocketBase */declare function onRecordAfterConfirmEmailChangeRequest(handler: (e: core.RecordConfirmEmailChangeEvent) => void, ...tags: string[]): void
 /** @group PocketBase */declare function onRecordAfterConfirmPasswordResetRequest(handler: (e: core.RecordConfirmPasswordResetEvent) => void, ...tags: string[]): void
@@ -1122,6 +1158,8 @@ declare function migrate(
 /** @group PocketBase */declare function onRecordAuthRequest(handler: (e: core.RecordAuthEvent) => void, ...tags: string[]): void
 /** @group PocketBase */declare function onRecordBeforeAuthRefreshRequest(handler: (e: core.RecordAuthRefreshEvent) => void, ...tags: string[]): void
 /** @group PocketBase */declare function onRecordBeforeAuthWithOAuth2Request(handler: (e: core.RecordAuthWithOAuth2Event) => void, ...tags: string[]): void
+/** @group PocketBase */declare function onRecordBeforeAuthWithOTPRequest(handler: (e: core.RecordAuthWithOTPEvent) => void, ...tags: string[]): void
+/** @group PocketBase */declare function onRecordBeforeAuthWithWebAuthnRequest(handler: (e: core.RecordAuthWithWebAuthnEvent) => void, ...tags: string[]): void
 /** @group PocketBase */declare function onRecordBeforeAuthWithPasswordRequest(handler: (e: core.RecordAuthWithPasswordEvent) => void, ...tags: string[]): void
 /** @group PocketBase */declare function onRecordBeforeConfirmEmailChangeRequest(handler: (e: core.RecordConfirmEmailChangeEvent) => void, ...tags: string[]): void
 /** @group PocketBase */declare function onRecordBeforeConfirmPasswordResetRequest(handler: (e: core.RecordConfirmPasswordResetEvent) => void, ...tags: string[]): void
@@ -5477,6 +5515,13 @@ namespace mails {
    */
   (app: CoreApp, record: models.Record, newEmail: string): void
  }
+ interface sendRecordOTP {
+  /**
+   * SendRecordOTP sends a one-time password email with the plain
+   * code and its login link to the specified auth record.
+   */
+  (app: CoreApp, authRecord: models.Record, otp: models.OTP, code: string): void
+ }
 }
 
 /**
@@ -6051,6 +6096,100 @@ namespace forms {
    */
   submit(...interceptors: InterceptorFunc<RecordOAuth2LoginData | undefined>[]): [(models.Record), (auth.AuthUser)]
  }
+ /**
+  * RecordOTPLoginData defines the data passed to the [RecordOTPLogin] interceptors.
+  */
+ interface RecordOTPLoginData {
+  record?: models.Record
+  otp?: models.OTP
+ }
+ /**
+  * RecordOTPLogin is an auth record one-time password login form.
+  */
+ interface RecordOTPLogin {
+  otpId: string
+  password: string
+ }
+ interface newRecordOTPLogin {
+  /**
+   * NewRecordOTPLogin creates a new [RecordOTPLogin] form initialized
+   * with from the provided [CoreApp] and [models.Collection] instances.
+   * 
+   * If you want to submit the form as part of a transaction,
+   * you can change the default Dao via [SetDao()].
+   */
+  (app: CoreApp, collection: models.Collection): (RecordOTPLogin)
+ }
+ interface RecordOTPLogin {
+  /**
+   * SetDao replaces the default form Dao instance with the provided one.
+   */
+  setDao(dao: daos.Dao): void
+ }
+ interface RecordOTPLogin {
+  /**
+   * Validate makes the form validatable by implementing [validation.Validatable] interface.
+   */
+  validate(): void
+ }
+ interface RecordOTPLogin {
+  /**
+   * Submit validates and submits the form.
+   * On success returns the authorized record model.
+   * 
+   * Each submit counts as a verification attempt and the OTP is
+   * invalidated after [models.OTPMaxAttempts] attempts.
+   * The OTP is accepted only if it was sent to the current auth record email.
+   * On successful login all OTPs of the auth record are deleted.
+   * 
+   * You can optionally provide a list of InterceptorFunc to
+   * further modify the form behavior before persisting it.
+   */
+  submit(...interceptors: InterceptorFunc<RecordOTPLoginData | undefined>[]): (models.Record)
+ }
+ /**
+  * RecordOTPRequest is an auth record one-time password request form.
+  */
+ interface RecordOTPRequest {
+  email: string
+ }
+ interface newRecordOTPRequest {
+  /**
+   * NewRecordOTPRequest creates a new [RecordOTPRequest] form initialized
+   * with from the provided [CoreApp] and [models.Collection] instances.
+   * 
+   * If you want to submit the form as part of a transaction,
+   * you can change the default Dao via [SetDao()].
+   */
+  (app: CoreApp, collection: models.Collection): (RecordOTPRequest)
+ }
+ interface RecordOTPRequest {
+  /**
+   * SetDao replaces the default form Dao instance with the provided one.
+   */
+  setDao(dao: daos.Dao): void
+ }
+ interface RecordOTPRequest {
+  /**
+   * Validate makes the form validatable by implementing [validation.Validatable] interface.
+   * 
+   * This method doesn't verify that auth record with `form.Email` exists (this is done on Submit).
+   */
+  validate(): void
+ }
+ interface RecordOTPRequest {
+  /**
+   * Submit validates the form, generates a new one-time password
+   * and sends it to the `form.Email` auth record.
+   * 
+   * The returned OTP model has its id always populated, even if the
+   * interceptors chain is still running (eg. in a background goroutine).
+   * 
+   * You can optionally provide a list of InterceptorFunc to further
+   * modify the form behavior before persisting it.
+   */
+  submit(...interceptors: InterceptorFunc<models.Record | undefined>[]): (models.OTP)
+ }
  /**
   * RecordPasswordLogin is record username/email + password login form.
   */
@@ -6500,6 +6639,11 @@ namespace apis {
  interface ApiError {
   code: number
   message: string
+  /**
+   * RequestId is the correlation id of the failed request
+   * (it is populated by the default api error handler).
+   */
+  requestId: string
   data: _TygojaDict
  }
  interface ApiError {
@@ -6739,6 +6883,13 @@ namespace apis {
    */
   (c: echo.Context): (models.RequestInfo)
  }
+ interface requestId {
+  /**
+   * RequestId returns the correlation id of the current request
+   * (or empty string if [LoadRequestId] is not registered).
+   */
+  (c: echo.Context): string
+ }
  interface recordAuthResponse {
   /**
    * RecordAuthResponse writes standardised json record auth response
@@ -10765,6 +10916,50 @@ namespace models {
  interface ExternalAuth {
   tableName(): string
  }
+ type _subWanMd = BaseModel
+ /**
+  * WebAuthnCredential defines a single auth record WebAuthn
+  * public key credential (aka. passkey).
+  */
+ interface WebAuthnCredential extends _subWanMd {
+  collectionId: string
+  recordId: string
+  name: string
+  /**
+   * CredentialId is the base64url encoded authenticator credential id.
+   */
+  credentialId: string
+  /**
+   * AAGUID is the hex encoded authenticator model identifier.
+   */
+  aaguid: string
+ }
+ interface WebAuthnCredential {
+  tableName(): string
+ }
+ type _subOtpMd = BaseModel
+ interface OTP extends _subOtpMd {
+  collectionId: string
+  recordId: string
+  email: string
+  attempts: number
+ }
+ interface OTP {
+  tableName(): string
+  /**
+   * SetPassword hashes and sets the provided plain one-time password.
+   */
+  setPassword(password: string): void
+  /**
+   * ValidatePassword validates a plain password against the OTP password hash.
+   */
+  validatePassword(password: string): boolean
+  /**
+   * HasExpired checks whether the OTP was created more than
+   * duration ago or has reached the max failed attempts limit.
+   */
+  hasExpired(duration: time.Duration): boolean
+ }
  type _subkdarp = BaseModel
  interface Record extends _subkdarp {
  }
@@ -13129,6 +13324,22 @@ namespace daos {
    */
   nonconcurrentDB(): dbx.Builder
  }
+ interface Dao {
+  /**
+   * WithContext returns a new Dao which queries and transactions
+   * are bound to the provided context (eg. for request tracing).
+   * 
+   * It has no effect on the queries of an already started transaction.
+   */
+  withContext(ctx: context.Context): (Dao)
+ }
+ interface Dao {
+  /**
+   * Context returns the context the current Dao is bound to
+   * (or nil if there is none).
+   */
+  context(): context.Context
+ }
  interface Dao {
   /**
    * Clone returns a new Dao with the same configuration options as the current one.
@@ -13515,6 +13726,8 @@ namespace daos {
    * 
    * If record.IsNew() is true, the method will perform a create, otherwise an update.
    * To explicitly mark a record for update you can use record.MarkAsNotNew().
+   * 
+   * Changing the email of an auth record deletes its pending one-time passwords.
    */
   saveRecord(record: models.Record): void
  }
@@ -13715,6 +13928,11 @@ namespace core {
    * Logger returns the active app logger.
    */
   logger(): (slog.Logger)
+  /**
+   * ContextLogger returns a request-scoped child of the app logger
+   * that attaches the ctx request id to all of its log records.
+   */
+  contextLogger(ctx: context.Context): (slog.Logger)
   /**
    * DataDir returns the app data directory path.
    */
@@ -13967,6 +14185,25 @@ namespace core {
    * triggered and called only if their event data origin matches the tags.
    */
   onMailerAfterRecordChangeEmailSend(...tags: string[]): (hook.TaggedHook<MailerRecordEvent | undefined>)
+  /**
+   * OnMailerBeforeRecordOTPSend hook is triggered right before
+   * sending a one-time password email to an auth record, allowing
+   * you to inspect and customize the email message that is being sent.
+   *
+   * If the optional "tags" list (Collection ids or names) is specified,
+   * then all event handlers registered via the created hook will be
+   * triggered and called only if their event data origin matches the tags.
+   */
+  onMailerBeforeRecordOTPSend(...tags: string[]): (hook.TaggedHook<MailerRecordEvent | undefined>)
+  /**
+   * OnMailerAfterRecordOTPSend hook is triggered after a
+   * one-time password email was successfully sent to an auth record.
+   *
+   * If the optional "tags" list (Collection ids or names) is specified,
+   * then all event handlers registered via the created hook will be
+   * triggered and called only if their event data origin matches the tags.
+   */
+  onMailerAfterRecordOTPSend(...tags: string[]): (hook.TaggedHook<MailerRecordEvent | undefined>)
   /**
    * OnRealtimeConnectRequest hook is triggered right before establishing
    * the SSE client connection.
@@ -14218,6 +14455,47 @@ namespace core {
    * triggered and called only if their event data origin matches the tags.
    */
   onRecordAfterAuthWithOAuth2Request(...tags: string[]): (hook.TaggedHook<RecordAuthWithOAuth2Event | undefined>)
+  /**
+   * OnRecordBeforeAuthWithOTPRequest hook is triggered before each Record
+   * auth with one-time password API request (after the OTP validation and
+   * before deleting it and returning the new auth token).
+   *
+   * Could be used to additionally validate the request data or
+   * to implement completely different auth behavior.
+   *
+   * If the optional "tags" list (Collection ids or names) is specified,
+   * then all event handlers registered via the created hook will be
+   * triggered and called only if their event data origin matches the tags.
+   */
+  onRecordBeforeAuthWithOTPRequest(...tags: string[]): (hook.TaggedHook<RecordAuthWithOTPEvent | undefined>)
+  /**
+   * OnRecordAfterAuthWithOTPRequest hook is triggered after each
+   * successful Record auth with one-time password API request.
+   *
+   * If the optional "tags" list (Collection ids or names) is specified,
+   * then all event handlers registered via the created hook will be
+   * triggered and called only if their event data origin matches the tags.
+   */
+  onRecordAfterAuthWithOTPRequest(...tags: string[]): (hook.TaggedHook<RecordAuthWithOTPEvent | undefined>)
+  /**
+   * OnRecordBeforeAuthWithWebAuthnRequest hook is triggered before each Record
+   * auth with WebAuthn credential (aka. passkey) API request (after the
+   * assertion verification and before storing the new signature counter).
+   *
+   * If the optional "tags" list (Collection ids or names) is specified,
+   * then all event handlers registered via the created hook will be
+   * triggered and called only if their event data origin matches the tags.
+   */
+  onRecordBeforeAuthWithWebAuthnRequest(...tags: string[]): (hook.TaggedHook<RecordAuthWithWebAuthnEvent | undefined>)
+  /**
+   * OnRecordAfterAuthWithWebAuthnRequest hook is triggered after each
+   * successful Record auth with WebAuthn credential API request.
+   *
+   * If the optional "tags" list (Collection ids or names) is specified,
+   * then all event handlers registered via the created hook will be
+   * triggered and called only if their event data origin matches the tags.
+   */
+  onRecordAfterAuthWithWebAuthnRequest(...tags: string[]): (hook.TaggedHook<RecordAuthWithWebAuthnEvent | undefined>)
   /**
    * OnRecordBeforeAuthRefreshRequest hook is triggered before each Record
    * auth refresh API request (right before generating a new auth token).
@@ -17934,6 +18212,18 @@ namespace core {
   oAuth2User?: auth.AuthUser
   isNewRecord: boolean
  }
+ type _subOtpEv = BaseCollectionEvent
+ interface RecordAuthWithOTPEvent extends _subOtpEv {
+  httpContext: echo.Context
+  record?: models.Record
+  otp?: models.OTP
+ }
+ type _subWanEv = BaseCollectionEvent
+ interface RecordAuthWithWebAuthnEvent extends _subWanEv {
+  httpContext: echo.Context
+  record?: models.Record
+  credential?: models.WebAuthnCredential
+ }
  type _subdXIjq = BaseCollectionEvent
  interface RecordAuthRefreshEvent extends _subdXIjq {
   httpContext: echo.Context
@@ -19332,6 +19622,26 @@ namespace core {
  interface BaseCollectionEvent {
   tags(): Array<string>
  }
+ interface BaseRequestEvent {
+  httpContext: echo.Context
+ }
+ interface BaseRequestEvent {
+  context(): context.Context
+ }
+ /**
+  * ContextEvent defines the hook events that are bound to a context
+  * (eg. the api request events).
+  */
+ interface ContextEvent {
+  [key:string]: any;
+  /**
+   * Context returns the event context (or nil if there is none).
+   */
+  context(): context.Context
+ }
+ interface ModelEvent {
+  context(): context.Context
+ }
 }
 
 /**
@@ -61,6 +61,37 @@ declare function cronAdd(
  */
 declare function cronRemove(jobId: string): void;
 
+/**
+ * CronPause pauses a single registered cron job, preventing it from
+ * being executed until cronResume() is called.
+ *
+ * Example:
+ *
+ * ` + "```" + `js
+ * cronPause("hello")
+ * ` + "```" + `
+ *
+ * _Note that this method is available only in pb_hooks context._
+ *
+ * @group PocketBase
+ */
+declare function cronPause(jobId: string): void;
+
+/**
+ * CronResume resumes a single previously paused cron job.
+ *
+ * Example:
+ *
+ * ` + "```" + `js
+ * cronResume("hello")
+ * ` + "```" + `
+ *
+ * _Note that this method is available only in pb_hooks context._
+ *
+ * @group PocketBase
+ */
+declare function cronResume(jobId: string): void;
+
 // -------------------------------------------------------------------
 // routerBinds
 // -------------------------------------------------------------------
@@ -589,6 +620,7 @@ declare namespace $mails {
   let sendRecordPasswordReset: mails.sendRecordPasswordReset
   let sendRecordVerification:  mails.sendRecordVerification
   let sendRecordChangeEmail:   mails.sendRecordChangeEmail
+  let sendRecordOTP:           mails.sendRecordOTP
 }
 
 // -------------------------------------------------------------------
@@ -836,6 +868,24 @@ declare class RecordOAuth2LoginForm implements forms.RecordOAuth2Login {
   constructor(app: CoreApp, collection: models.Collection, optAuthRecord?: models.Record)
 }
 
+interface RecordOTPLoginForm extends forms.RecordOTPLogin{} // merge
+/**
+ * @inheritDoc
+ * @group PocketBase
+ */
+declare class RecordOTPLoginForm implements forms.RecordOTPLogin {
+  constructor(app: CoreApp, collection: models.Collection)
+}
+
+interface RecordOTPRequestForm extends forms.RecordOTPRequest{} // merge
+/**
+ * @inheritDoc
+ * @group PocketBase
+ */
+declare class RecordOTPRequestForm implements forms.RecordOTPRequest {
+  constructor(app: CoreApp, collection: models.Collection)
+}
+
 interface RecordPasswordLoginForm extends forms.RecordPasswordLogin{} // merge
 /**
  * @inheritDoc
@@ -993,6 +1043,7 @@ declare namespace $apis {
   let requireAdminOrOwnerAuth:   apis.requireAdminOrOwnerAuth
   let activityLogger:            apis.activityLogger
   let requestInfo:               apis.requestInfo
+  let requestId:                 apis.requestId
   let recordAuthResponse:        apis.recordAuthResponse
   let gzip:                      middleware.gzip
   let bodyLimit:                 middleware.bodyLimit
@@ -24,6 +24,7 @@ import (
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/AlperRehaYAZGAN/postgresbase/core"
+	"github.com/AlperRehaYAZGAN/postgresbase/daos"
 	"github.com/AlperRehaYAZGAN/postgresbase/migrations"
 	"github.com/AlperRehaYAZGAN/postgresbase/models"
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/inflector"
@@ -144,6 +145,9 @@ func (p *plugin) createCommand() *cobra.Command {
 					return err
 				}
 
+				// the migrations are not restricted by the multi-tenant collections policies
+				runner.SetTxInit(daos.DisableTenantIsolation)
+
 				if err := runner.Run(args...); err != nil {
 					return err
 				}
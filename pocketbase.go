@@ -56,6 +56,9 @@ type Config struct {
 	DataMaxIdleConns int // default to core.DefaultDataMaxIdleConns
 	LogsMaxOpenConns int // default to core.DefaultLogsMaxOpenConns
 	LogsMaxIdleConns int // default to core.DefaultLogsMaxIdleConns
+
+	// optional custom storage drivers (see core.BaseAppConfig.StorageDrivers)
+	StorageDrivers map[string]*core.StorageDriver
 }
 
 // New creates a new PocketBase instance with the default configuration.
@@ -123,6 +126,7 @@ func NewWithConfig(config Config) *PocketBase {
 		DataMaxIdleConns: config.DataMaxIdleConns,
 		LogsMaxOpenConns: config.LogsMaxOpenConns,
 		LogsMaxIdleConns: config.LogsMaxIdleConns,
+		StorageDrivers:   config.StorageDrivers,
 	})}
 
 	// hide the default help command (allow only `--help` flag)
@@ -136,6 +140,8 @@ func NewWithConfig(config Config) *PocketBase {
 func (pb *PocketBase) Start() error {
 	// register system commands
 	pb.RootCmd.AddCommand(cmd.NewAdminCommand(pb))
+	pb.RootCmd.AddCommand(cmd.NewFilesCommand(pb))
+	pb.RootCmd.AddCommand(cmd.NewLogsCommand(pb))
 	pb.RootCmd.AddCommand(cmd.NewServeCommand(pb, !pb.hideStartBanner))
 
 	return pb.Execute()
@@ -398,6 +398,11 @@ func (r *runner) processActiveProps() (*search.ResolverResult, error) {
 
 			cleanFieldName := inflector.Columnify(field.Name)
 
+			// the ":meta" modifier requires a file metadata field (eg. avatar:meta.width)
+			if modifier == metaModifier {
+				return nil, fmt.Errorf("missing file metadata field in %q", r.fieldName)
+			}
+
 			// arrayable fields with ":length" modifier
 			// -------------------------------------------------------
 			if modifier == lengthModifier && list.ExistInSlice(field.Type, schema.ArraybleFieldTypes()) {
@@ -477,6 +482,12 @@ func (r *runner) processActiveProps() (*search.ResolverResult, error) {
 			return result, nil
 		}
 
+		// file field with ":meta" modifier -> resolve the next prop as file metadata column
+		// (eg. avatar:meta.width)
+		if strings.HasSuffix(prop, ":"+metaModifier) {
+			return r.processFileMetaField(collection, strings.TrimSuffix(prop, ":"+metaModifier), r.activeProps[i+1:])
+		}
+
 		field := collection.Schema.GetFieldByName(prop)
 
 		// json field -> treat the rest of the props as json path
@@ -716,6 +727,100 @@ func (r *runner) processActiveProps() (*search.ResolverResult, error) {
 	return nil, fmt.Errorf("failed to resolve field %q", r.fieldName)
 }
 
+// fileMetaColumns is the list of the resolvable file metadata columns
+// and whether they are numeric.
+var fileMetaColumns = map[string]bool{
+	"name":     false,
+	"mimeType": false,
+	"checksum": false,
+	"size":     true,
+	"width":    true,
+	"height":   true,
+	"duration": true,
+}
+
+func (r *runner) processFileMetaField(collection *models.Collection, fieldName string, props []string) (*search.ResolverResult, error) {
+	field := collection.Schema.GetFieldByName(fieldName)
+	if field == nil {
+		if r.nullifyMisingField {
+			return &search.ResolverResult{Identifier: "NULL"}, nil
+		}
+		return nil, fmt.Errorf("unknown field %q", fieldName)
+	}
+
+	if field.Type != schema.FieldTypeFile {
+		return nil, fmt.Errorf("field %q is not a file field", fieldName)
+	}
+
+	if len(props) != 1 {
+		return nil, fmt.Errorf("invalid file metadata path in %q", r.fieldName)
+	}
+
+	isNumeric, ok := fileMetaColumns[props[0]]
+	if !ok {
+		return nil, fmt.Errorf("unknown file metadata field %q", props[0])
+	}
+
+	field.InitOptions()
+	options, ok := field.Options.(*schema.FileOptions)
+	if !ok {
+		return nil, fmt.Errorf("failed to initialize field %q options", fieldName)
+	}
+
+	cleanFieldName := inflector.Columnify(field.Name)
+	metaTable := (&models.FileMetadata{}).TableName()
+
+	// join the files metadata to the main query
+	// ---
+	collectionPlaceholder := "fmc" + security.PseudorandomString(5)
+	fieldPlaceholder := "fmf" + security.PseudorandomString(5)
+	metaAlias := r.activeTableAlias + "_" + cleanFieldName + "_meta"
+
+	r.resolver.registerJoin(
+		metaTable,
+		metaAlias,
+		dbx.NewExp(
+			fmt.Sprintf(
+				"[[%s.recordId]] = [[%s.id]] AND [[%s.collectionId]] = {:%s} AND [[%s.field]] = {:%s}",
+				metaAlias, r.activeTableAlias, metaAlias, collectionPlaceholder, metaAlias, fieldPlaceholder,
+			),
+			dbx.Params{collectionPlaceholder: collection.Id, fieldPlaceholder: field.Name},
+		),
+	)
+
+	result := &search.ResolverResult{
+		NoCoalesce: isNumeric,
+		Identifier: fmt.Sprintf("[[%s.%s]]", metaAlias, props[0]),
+	}
+
+	// join the files metadata to the multi-match subquery
+	// ---
+	if options.IsMultiple() {
+		r.withMultiMatch = true
+	}
+
+	if r.withMultiMatch {
+		metaAlias2 := r.multiMatchActiveTableAlias + "_" + cleanFieldName + "_meta"
+
+		r.multiMatch.joins = append(r.multiMatch.joins, &join{
+			tableName:  metaTable,
+			tableAlias: metaAlias2,
+			on: dbx.NewExp(
+				fmt.Sprintf(
+					"[[%s.recordId]] = [[%s.id]] AND [[%s.collectionId]] = {:%s} AND [[%s.field]] = {:%s}",
+					metaAlias2, r.multiMatchActiveTableAlias, metaAlias2, collectionPlaceholder, metaAlias2, fieldPlaceholder,
+				),
+				dbx.Params{collectionPlaceholder: collection.Id, fieldPlaceholder: field.Name},
+			),
+		})
+		r.multiMatch.valueIdentifier = fmt.Sprintf("[[%s.%s]]", metaAlias2, props[0])
+
+		result.MultiMatchSubQuery = r.multiMatch
+	}
+
+	return result, nil
+}
+
 func resolvableSystemFieldNames(collection *models.Collection) []string {
 	result := schema.BaseModelFieldNames()
 
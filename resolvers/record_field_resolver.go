@@ -19,6 +19,7 @@ const (
 	eachModifier   string = "each"
 	issetModifier  string = "isset"
 	lengthModifier string = "length"
+	metaModifier   string = "meta"
 )
 
 // list of auth filter fields that don't require join with the auth
@@ -153,6 +154,7 @@ func (r *RecordFieldResolver) UpdateQuery(query *dbx.SelectQuery) error {
 //	@request.data.someRelation.name
 //	@request.data.someField
 //	@request.data.someSelect:each
+//	someFile:meta.width
 //	@request.data.someField:isset
 //	@collection.product.name
 func (r *RecordFieldResolver) Resolve(fieldName string) (*search.ResolverResult, error) {
@@ -294,7 +296,8 @@ func splitModifier(combined string) (string, string, error) {
 	switch parts[1] {
 	case issetModifier,
 		eachModifier,
-		lengthModifier:
+		lengthModifier,
+		metaModifier:
 		return parts[0], parts[1], nil
 	}
 
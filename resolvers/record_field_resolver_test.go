@@ -498,6 +498,14 @@ func TestRecordFieldResolverResolveSchemaFields(t *testing.T) {
 		{"@collection.demo4.updated", false, "[[__collection_demo4.updated]]"},
 		{"@collection.demo4.self_rel_many.missing", true, ""},
 		{"@collection.demo4.self_rel_many.self_rel_one.self_rel_many.self_rel_one.title", false, "[[__collection_demo4_self_rel_many_self_rel_one_self_rel_many_self_rel_one.title]]"},
+
+		// file metadata
+		{"@collection.demo1.file_one:meta", true, ""},
+		{"@collection.demo1.file_one:meta.missing", true, ""},
+		{"@collection.demo1.file_one:meta.width.height", true, ""},
+		{"@collection.demo1.text:meta.width", true, ""},
+		{"@collection.demo1.file_one:meta.width", false, "[[__collection_demo1_file_one_meta.width]]"},
+		{"@collection.demo1.file_many:meta.mimeType", false, "[[__collection_demo1_file_many_meta.mimeType]]"},
 	}
 
 	for _, s := range scenarios {
@@ -108,6 +108,9 @@ func NewTestApp(optTestDataDir ...string) (*TestApp, error) {
 	app := core.NewBaseApp(core.BaseAppConfig{
 		DataDir:       tempDir,
 		EncryptionEnv: "pb_test_env",
+		StorageDrivers: map[string]*core.StorageDriver{
+			core.MemoryStorageDriverName: core.NewMemoryStorageDriver(),
+		},
 	})
 
 	// load data dir and db connections
@@ -222,6 +225,22 @@ func NewTestApp(optTestDataDir ...string) (*TestApp, error) {
 		return t.registerEventCall("OnRecordAfterAuthWithOAuth2Request")
 	})
 
+	t.OnRecordBeforeAuthWithOTPRequest().Add(func(e *core.RecordAuthWithOTPEvent) error {
+		return t.registerEventCall("OnRecordBeforeAuthWithOTPRequest")
+	})
+
+	t.OnRecordAfterAuthWithOTPRequest().Add(func(e *core.RecordAuthWithOTPEvent) error {
+		return t.registerEventCall("OnRecordAfterAuthWithOTPRequest")
+	})
+
+	t.OnRecordBeforeAuthWithWebAuthnRequest().Add(func(e *core.RecordAuthWithWebAuthnEvent) error {
+		return t.registerEventCall("OnRecordBeforeAuthWithWebAuthnRequest")
+	})
+
+	t.OnRecordAfterAuthWithWebAuthnRequest().Add(func(e *core.RecordAuthWithWebAuthnEvent) error {
+		return t.registerEventCall("OnRecordAfterAuthWithWebAuthnRequest")
+	})
+
 	t.OnRecordBeforeAuthRefreshRequest().Add(func(e *core.RecordAuthRefreshEvent) error {
 		return t.registerEventCall("OnRecordBeforeAuthRefreshRequest")
 	})
@@ -322,6 +341,14 @@ func NewTestApp(optTestDataDir ...string) (*TestApp, error) {
 		return t.registerEventCall("OnMailerAfterRecordChangeEmailSend")
 	})
 
+	t.OnMailerBeforeRecordOTPSend().Add(func(e *core.MailerRecordEvent) error {
+		return t.registerEventCall("OnMailerBeforeRecordOTPSend")
+	})
+
+	t.OnMailerAfterRecordOTPSend().Add(func(e *core.MailerRecordEvent) error {
+		return t.registerEventCall("OnMailerAfterRecordOTPSend")
+	})
+
 	t.OnRealtimeConnectRequest().Add(func(e *core.RealtimeConnectEvent) error {
 		return t.registerEventCall("OnRealtimeConnectRequest")
 	})
@@ -398,6 +425,10 @@ func NewTestApp(optTestDataDir ...string) (*TestApp, error) {
 		return t.registerEventCall("OnCollectionsAfterImportRequest")
 	})
 
+	t.OnHealthReadyRequest().Add(func(e *core.HealthReadyEvent) error {
+		return t.registerEventCall("OnHealthReadyRequest")
+	})
+
 	t.OnAdminsListRequest().Add(func(e *core.AdminsListEvent) error {
 		return t.registerEventCall("OnAdminsListRequest")
 	})
@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+)
+
+// EicarTestSignature is the standard antivirus test file content
+// that is reported as infected by the [TestClamd] server.
+const EicarTestSignature = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// TestClamd is a minimal ClamAV daemon stand-in TCP server
+// that supports only the INSTREAM command.
+//
+// Streams containing [EicarTestSignature] are reported as infected
+// with "Eicar-Signature" and all others as clean.
+type TestClamd struct {
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	mux        sync.Mutex
+	totalScans int
+}
+
+// NewTestClamd starts a new [TestClamd] server on a random local port.
+//
+// NB! Make sure to call Close() after you are done working with it.
+func NewTestClamd() (*TestClamd, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &TestClamd{listener: listener}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return // closed
+			}
+
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				s.handle(conn)
+			}()
+		}
+	}()
+
+	return s, nil
+}
+
+// Address returns the server address in the "tcp://host:port" format.
+func (s *TestClamd) Address() string {
+	return "tcp://" + s.listener.Addr().String()
+}
+
+// TotalScans returns the number of the completed stream scans.
+func (s *TestClamd) TotalScans() int {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	return s.totalScans
+}
+
+// Close stops the server.
+func (s *TestClamd) Close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+func (s *TestClamd) handle(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+
+	command, err := r.ReadString(0)
+	if err != nil || command != "zINSTREAM\x00" {
+		conn.Write([]byte("UNKNOWN COMMAND\x00"))
+		return
+	}
+
+	var data bytes.Buffer
+
+	for {
+		var size uint32
+		if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+			return
+		}
+
+		if size == 0 {
+			break
+		}
+
+		if _, err := io.CopyN(&data, r, int64(size)); err != nil {
+			return
+		}
+	}
+
+	s.mux.Lock()
+	s.totalScans++
+	s.mux.Unlock()
+
+	if bytes.Contains(data.Bytes(), []byte(EicarTestSignature)) {
+		conn.Write([]byte("stream: Eicar-Signature FOUND\x00"))
+	} else {
+		conn.Write([]byte("stream: OK\x00"))
+	}
+}
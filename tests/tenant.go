@@ -0,0 +1,151 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/core"
+	"github.com/AlperRehaYAZGAN/postgresbase/daos"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/models/schema"
+	"github.com/pocketbase/dbx"
+)
+
+// EnableTestTenantIsolation marks the specified test app collection as
+// multi-tenant and emulates its fail-closed tenant isolation policy.
+//
+// Since the test SQLite db doesn't have row-level security, the collection
+// table is replaced with a view that filters the records by the current
+// transaction tenant (see [daos.Dao.WithTenant] and [daos.Dao.WithoutTenantIsolation]),
+// aka. queries without tenant scope don't match any of the collection records.
+//
+// recordTenants is an optional map with the tenants of
+// the existing collection records (keyed by their id).
+func EnableTestTenantIsolation(app core.App, collectionNameOrId string, recordTenants map[string]string) (*models.Collection, error) {
+	collection, err := app.Dao().FindCollectionByNameOrId(collectionNameOrId)
+	if err != nil {
+		return nil, err
+	}
+
+	if collection.IsView() {
+		return nil, errors.New("view collections can't be multi-tenant")
+	}
+
+	if collection.Options == nil {
+		collection.Options = map[string]any{}
+	}
+	collection.Options["multiTenant"] = true
+
+	rawOptions, err := json.Marshal(collection.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	dataTable := collection.Name + "_rls"
+	tenantColumn := schema.FieldNameTenant
+
+	currentTenant := fmt.Sprintf("current_setting('%s', true)", daos.TenantSettingName)
+	bypass := fmt.Sprintf("COALESCE(current_setting('%s', true), '') = 'on'", daos.TenantBypassSettingName)
+	policy := func(tenantExpr string) string {
+		return fmt.Sprintf("((COALESCE(%[1]s, '') <> '' AND %[2]s = %[1]s) OR %[3]s)", currentTenant, tenantExpr, bypass)
+	}
+
+	err = app.Dao().RunInTransaction(func(txDao *daos.Dao) error {
+		_, err := txDao.DB().Update(
+			collection.TableName(),
+			dbx.Params{"options": string(rawOptions)},
+			dbx.HashExp{"id": collection.Id},
+		).Execute()
+		if err != nil {
+			return err
+		}
+
+		_, err = txDao.DB().NewQuery(fmt.Sprintf(
+			"ALTER TABLE {{%s}} ADD COLUMN [[%s]] TEXT DEFAULT '' NOT NULL",
+			collection.Name,
+			tenantColumn,
+		)).Execute()
+		if err != nil {
+			return err
+		}
+
+		for id, tenant := range recordTenants {
+			_, err := txDao.DB().Update(
+				collection.Name,
+				dbx.Params{tenantColumn: tenant},
+				dbx.HashExp{"id": id},
+			).Execute()
+			if err != nil {
+				return err
+			}
+		}
+
+		columns := []string{}
+		err = txDao.DB().NewQuery("SELECT name FROM pragma_table_info({:table})").
+			Bind(dbx.Params{"table": collection.Name}).
+			Column(&columns)
+		if err != nil {
+			return err
+		}
+
+		quotedColumns := make([]string, 0, len(columns))
+		newValues := make([]string, 0, len(columns))
+		updateSets := make([]string, 0, len(columns))
+		for _, c := range columns {
+			if c == tenantColumn {
+				continue
+			}
+			quotedColumns = append(quotedColumns, "[["+c+"]]")
+			newValues = append(newValues, "NEW.[["+c+"]]")
+			updateSets = append(updateSets, "[["+c+"]] = NEW.[["+c+"]]")
+		}
+
+		newTenant := fmt.Sprintf("COALESCE(NULLIF(NEW.[[%s]], ''), %s, '')", tenantColumn, currentTenant)
+
+		_, err = txDao.DB().NewQuery(fmt.Sprintf(
+			`
+			ALTER TABLE {{%[1]s}} RENAME TO {{%[2]s}};
+
+			CREATE VIEW {{%[1]s}} AS SELECT * FROM {{%[2]s}} WHERE %[3]s;
+
+			CREATE TRIGGER [[%[2]s_insert]] INSTEAD OF INSERT ON {{%[1]s}}
+			BEGIN
+				SELECT RAISE(ABORT, 'new row violates row-level security policy') WHERE NOT %[4]s;
+				INSERT INTO {{%[2]s}} (%[5]s, [[%[6]s]]) VALUES (%[7]s, %[8]s);
+			END;
+
+			CREATE TRIGGER [[%[2]s_update]] INSTEAD OF UPDATE ON {{%[1]s}}
+			BEGIN
+				UPDATE {{%[2]s}} SET %[9]s WHERE [[id]] = OLD.[[id]];
+			END;
+
+			CREATE TRIGGER [[%[2]s_delete]] INSTEAD OF DELETE ON {{%[1]s}}
+			BEGIN
+				DELETE FROM {{%[2]s}} WHERE [[id]] = OLD.[[id]];
+			END;
+			`,
+			collection.Name,
+			dataTable,
+			policy("[["+tenantColumn+"]]"),
+			policy(newTenant),
+			strings.Join(quotedColumns, ", "),
+			tenantColumn,
+			strings.Join(newValues, ", "),
+			newTenant,
+			strings.Join(updateSets, ", "),
+		)).Execute()
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := core.ReloadCachedCollections(app); err != nil {
+		return nil, err
+	}
+
+	return app.Dao().FindCollectionByNameOrId(collection.Id)
+}
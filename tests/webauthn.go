@@ -0,0 +1,204 @@
+package tests
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/webauthn"
+)
+
+// TestAuthenticator is a minimal software WebAuthn authenticator
+// with a single ES256 resident credential (aka. passkey) and
+// "none" attestation that could be used for testing the
+// registration and authentication ceremonies.
+type TestAuthenticator struct {
+	RPId         string
+	Origin       string
+	CredentialId []byte
+	UserHandle   []byte
+	SignCount    uint32
+
+	key *ecdsa.PrivateKey
+}
+
+// NewTestAuthenticator creates a new TestAuthenticator instance
+// with a random credential for the specified relying party.
+func NewTestAuthenticator(rpId string, origin string) (*TestAuthenticator, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	credentialId := make([]byte, 16)
+	if _, err := rand.Read(credentialId); err != nil {
+		return nil, err
+	}
+
+	return &TestAuthenticator{
+		RPId:         rpId,
+		Origin:       origin,
+		CredentialId: credentialId,
+		key:          key,
+	}, nil
+}
+
+// PublicKey returns the COSE_Key encoded credential public key.
+func (a *TestAuthenticator) PublicKey() []byte {
+	x := make([]byte, 32)
+	y := make([]byte, 32)
+	a.key.X.FillBytes(x)
+	a.key.Y.FillBytes(y)
+
+	return encodeTestCBOR(map[int64]any{
+		1:  int64(2),  // kty: EC2
+		3:  int64(-7), // alg: ES256
+		-1: int64(1),  // crv: P-256
+		-2: x,
+		-3: y,
+	})
+}
+
+// Register creates a registration ceremony response for the
+// specified challenge and returns it as PublicKeyCredential JSON map.
+func (a *TestAuthenticator) Register(challenge string) map[string]any {
+	clientData := a.clientData(webauthn.ClientDataTypeCreate, challenge)
+
+	// attested credential data
+	attested := make([]byte, 16, 16+2+len(a.CredentialId))
+	attested = binary.BigEndian.AppendUint16(attested, uint16(len(a.CredentialId)))
+	attested = append(attested, a.CredentialId...)
+	attested = append(attested, a.PublicKey()...)
+
+	authData := a.authenticatorData(webauthn.FlagAttestedCredentialData)
+	authData = append(authData, attested...)
+
+	attestationObject := encodeTestCBOR(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": authData,
+	})
+
+	return map[string]any{
+		"id":    webauthn.EncodeBase64(a.CredentialId),
+		"rawId": webauthn.EncodeBase64(a.CredentialId),
+		"type":  "public-key",
+		"response": map[string]any{
+			"clientDataJSON":    webauthn.EncodeBase64(clientData),
+			"attestationObject": webauthn.EncodeBase64(attestationObject),
+		},
+	}
+}
+
+// Login creates an authentication ceremony response for the
+// specified challenge and returns it as PublicKeyCredential JSON map.
+func (a *TestAuthenticator) Login(challenge string) (map[string]any, error) {
+	a.SignCount++
+
+	clientData := a.clientData(webauthn.ClientDataTypeGet, challenge)
+	clientDataHash := sha256.Sum256(clientData)
+
+	authData := a.authenticatorData(0)
+
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"id":    webauthn.EncodeBase64(a.CredentialId),
+		"rawId": webauthn.EncodeBase64(a.CredentialId),
+		"type":  "public-key",
+		"response": map[string]any{
+			"clientDataJSON":    webauthn.EncodeBase64(clientData),
+			"authenticatorData": webauthn.EncodeBase64(authData),
+			"signature":         webauthn.EncodeBase64(signature),
+			"userHandle":        webauthn.EncodeBase64(a.UserHandle),
+		},
+	}, nil
+}
+
+func (a *TestAuthenticator) clientData(typ string, challenge string) []byte {
+	raw, _ := json.Marshal(map[string]any{
+		"type":      typ,
+		"challenge": challenge,
+		"origin":    a.Origin,
+	})
+
+	return raw
+}
+
+func (a *TestAuthenticator) authenticatorData(flags byte) []byte {
+	rpIdHash := sha256.Sum256([]byte(a.RPId))
+
+	result := append([]byte{}, rpIdHash[:]...)
+	result = append(result, flags|webauthn.FlagUserPresent|webauthn.FlagUserVerified)
+	result = binary.BigEndian.AppendUint32(result, a.SignCount)
+
+	return result
+}
+
+// encodeTestCBOR encodes the provided value as canonical CBOR.
+//
+// Only the types used by [TestAuthenticator] are supported
+// (int64, string, []byte, map[string]any and map[int64]any).
+func encodeTestCBOR(value any) []byte {
+	header := func(major byte, arg uint64) []byte {
+		switch {
+		case arg < 24:
+			return []byte{major<<5 | byte(arg)}
+		case arg <= 0xff:
+			return []byte{major<<5 | 24, byte(arg)}
+		case arg <= 0xffff:
+			return binary.BigEndian.AppendUint16([]byte{major<<5 | 25}, uint16(arg))
+		default:
+			return binary.BigEndian.AppendUint32([]byte{major<<5 | 26}, uint32(arg))
+		}
+	}
+
+	switch v := value.(type) {
+	case int64:
+		if v < 0 {
+			return header(1, uint64(-1-v))
+		}
+		return header(0, uint64(v))
+	case string:
+		return append(header(3, uint64(len(v))), v...)
+	case []byte:
+		return append(header(2, uint64(len(v))), v...)
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		result := header(5, uint64(len(v)))
+		for _, k := range keys {
+			result = append(result, encodeTestCBOR(k)...)
+			result = append(result, encodeTestCBOR(v[k])...)
+		}
+		return result
+	case map[int64]any:
+		keys := make([]int64, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+		result := header(5, uint64(len(v)))
+		for _, k := range keys {
+			result = append(result, encodeTestCBOR(k)...)
+			result = append(result, encodeTestCBOR(v[k])...)
+		}
+		return result
+	}
+
+	panic("unsupported test CBOR value")
+}
@@ -4,4 +4,5 @@ package tokens
 const (
 	TypeAdmin      = "admin"
 	TypeAuthRecord = "authRecord"
+	TypeUpload     = "upload"
 )
@@ -0,0 +1,35 @@
+package tokens
+
+import (
+	"github.com/AlperRehaYAZGAN/postgresbase/core"
+	"github.com/AlperRehaYAZGAN/postgresbase/models"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/security"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// NewUploadToken generates and returns a new one-time token that allows
+// uploading a single file with the specified size and mime type
+// to the fileKey storage location.
+//
+// The token "id" claim is used to ensure that the token
+// is consumed only once (see Dao.ConsumeUploadToken).
+func NewUploadToken(
+	app core.App,
+	collection *models.Collection,
+	fileKey string,
+	size int64,
+	mimeType string,
+) (string, error) {
+	return security.NewJWT(
+		jwt.MapClaims{
+			"id":           security.RandomString(30),
+			"type":         TypeUpload,
+			"collectionId": collection.Id,
+			"key":          fileKey,
+			"size":         size,
+			"mimeType":     mimeType,
+		},
+		app.Settings().RecordFileToken.Secret,
+		app.Settings().RecordFileToken.Duration,
+	)
+}
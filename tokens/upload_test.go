@@ -0,0 +1,47 @@
+package tokens_test
+
+import (
+	"testing"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/tests"
+	"github.com/AlperRehaYAZGAN/postgresbase/tokens"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/security"
+	"github.com/spf13/cast"
+)
+
+func TestNewUploadToken(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := collection.BaseUploadsPath() + "/abc/test.txt"
+
+	token, err := tokens.NewUploadToken(app, collection, key, 10, "text/plain")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := security.ParseJWT(token, app.Settings().RecordFileToken.Secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedClaims := map[string]any{
+		"type":         tokens.TypeUpload,
+		"collectionId": collection.Id,
+		"key":          key,
+		"size":         int64(10),
+		"mimeType":     "text/plain",
+	}
+	for k, v := range expectedClaims {
+		if cast.ToString(claims[k]) != cast.ToString(v) {
+			t.Fatalf("Expected %s claim %v, got %v", k, v, claims[k])
+		}
+	}
+}
@@ -11,23 +11,26 @@ package cron
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 )
 
-type job struct {
-	schedule *Schedule
-	run      func()
-}
-
 // Cron is a crontab-like struct for tasks/jobs scheduling.
 type Cron struct {
 	timezone   *time.Location
 	ticker     *time.Ticker
 	startTimer *time.Timer
-	jobs       map[string]*job
+	jobs       map[string]*Job
 	interval   time.Duration
 	tickerDone chan bool
+	observer   func(job *Job, duration time.Duration)
+
+	// paused holds the ids of the paused jobs (including the not
+	// registered ones) so that the pause state survives re-adding a job
+	paused        map[string]struct{}
+	pauseObserver func(pausedJobIds []string)
+	pauseMux      sync.Mutex
 
 	sync.RWMutex
 }
@@ -41,7 +44,8 @@ func New() *Cron {
 	return &Cron{
 		interval:   1 * time.Minute,
 		timezone:   time.UTC,
-		jobs:       map[string]*job{},
+		jobs:       map[string]*Job{},
+		paused:     map[string]struct{}{},
 		tickerDone: make(chan bool),
 	}
 }
@@ -69,6 +73,66 @@ func (c *Cron) SetTimezone(l *time.Location) {
 	c.timezone = l
 }
 
+// Timezone returns the current cron tick timezone.
+func (c *Cron) Timezone() *time.Location {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.timezone
+}
+
+// SetObserver registers a function that is called after each
+// scheduled or manual (see Run()) job execution with the job
+// run duration (eg. for metrics collection).
+//
+// Pass nil to remove the current observer.
+func (c *Cron) SetObserver(fn func(job *Job, duration time.Duration)) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.observer = fn
+}
+
+// SetPauseObserver registers a function that is called after each
+// Pause() and Resume() call with the ids of all paused jobs
+// (eg. for persisting the pause state between restarts).
+//
+// Pass nil to remove the current pause observer.
+func (c *Cron) SetPauseObserver(fn func(pausedJobIds []string)) {
+	c.pauseMux.Lock()
+	defer c.pauseMux.Unlock()
+
+	c.pauseObserver = fn
+}
+
+// SetPausedJobs replaces the ids of the paused jobs
+// (the ids of not yet registered jobs are also allowed).
+//
+// It doesn't trigger the pause observer.
+func (c *Cron) SetPausedJobs(jobIds []string) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.paused = make(map[string]struct{}, len(jobIds))
+	for _, id := range jobIds {
+		c.paused[id] = struct{}{}
+	}
+
+	for id, j := range c.jobs {
+		_, isPaused := c.paused[id]
+		j.paused.Store(isPaused)
+	}
+}
+
+// PausedJobs returns the sorted ids of the paused jobs
+// (including the ones that are not registered yet).
+func (c *Cron) PausedJobs() []string {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.pausedJobIds()
+}
+
 // MustAdd is similar to Add() but panic on failure.
 func (c *Cron) MustAdd(jobId string, cronExpr string, run func()) {
 	if err := c.Add(jobId, cronExpr, run); err != nil {
@@ -81,6 +145,9 @@ func (c *Cron) MustAdd(jobId string, cronExpr string, run func()) {
 // If there is already a job with the provided id, then the old job
 // will be replaced with the new one.
 //
+// The job is registered as paused if its id was paused before
+// (even if the job was removed in the meantime).
+//
 // cronExpr is a regular cron expression, eg. "0 */3 * * *" (aka. at minute 0 past every 3rd hour).
 // Check cron.NewSchedule() for the supported tokens.
 func (c *Cron) Add(jobId string, cronExpr string, run func()) error {
@@ -96,11 +163,19 @@ func (c *Cron) Add(jobId string, cronExpr string, run func()) error {
 		return fmt.Errorf("failed to add new cron job: %w", err)
 	}
 
-	c.jobs[jobId] = &job{
-		schedule: schedule,
-		run:      run,
+	j := &Job{
+		id:         jobId,
+		expression: cronExpr,
+		schedule:   schedule,
+		run:        run,
+	}
+
+	if _, ok := c.paused[jobId]; ok {
+		j.paused.Store(true)
 	}
 
+	c.jobs[jobId] = j
+
 	return nil
 }
 
@@ -117,7 +192,108 @@ func (c *Cron) RemoveAll() {
 	c.Lock()
 	defer c.Unlock()
 
-	c.jobs = map[string]*job{}
+	c.jobs = map[string]*Job{}
+}
+
+// Jobs returns a list with all registered cron jobs sorted by their id.
+func (c *Cron) Jobs() []*Job {
+	c.RLock()
+	defer c.RUnlock()
+
+	result := make([]*Job, 0, len(c.jobs))
+	for _, j := range c.jobs {
+		result = append(result, j)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].id < result[j].id
+	})
+
+	return result
+}
+
+// Job returns a single registered cron job by its id
+// (or nil if there is no such job).
+func (c *Cron) Job(jobId string) *Job {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.jobs[jobId]
+}
+
+// Pause pauses a single registered cron job, preventing it from
+// being executed by the cron ticker until Resume() is called.
+func (c *Cron) Pause(jobId string) error {
+	return c.setPaused(jobId, true)
+}
+
+// Resume resumes a single previously paused cron job.
+func (c *Cron) Resume(jobId string) error {
+	return c.setPaused(jobId, false)
+}
+
+// Run executes a single registered cron job in the current goroutine
+// (regardless of its paused state) and notifies the job observer (if any).
+func (c *Cron) Run(jobId string) error {
+	c.RLock()
+	j, ok := c.jobs[jobId]
+	observer := c.observer
+	c.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("missing cron job with id %q", jobId)
+	}
+
+	runJob(j, observer)
+
+	return nil
+}
+
+func (c *Cron) setPaused(jobId string, paused bool) error {
+	// serialize the pause changes to ensure that
+	// the observer receives them in the same order
+	c.pauseMux.Lock()
+	defer c.pauseMux.Unlock()
+
+	c.Lock()
+
+	j, ok := c.jobs[jobId]
+	if !ok {
+		c.Unlock()
+		return fmt.Errorf("missing cron job with id %q", jobId)
+	}
+
+	j.paused.Store(paused)
+
+	if paused {
+		c.paused[jobId] = struct{}{}
+	} else {
+		delete(c.paused, jobId)
+	}
+
+	pausedJobIds := c.pausedJobIds()
+
+	c.Unlock()
+
+	if c.pauseObserver != nil {
+		c.pauseObserver(pausedJobIds)
+	}
+
+	return nil
+}
+
+// pausedJobIds returns the sorted ids of the paused jobs.
+//
+// NB! The caller is expected to hold the cron lock.
+func (c *Cron) pausedJobIds() []string {
+	result := make([]string, 0, len(c.paused))
+	for id := range c.paused {
+		result = append(result, id)
+	}
+
+	sort.Strings(result)
+
+	return result
 }
 
 // Total returns the current total number of registered cron jobs.
@@ -199,9 +375,23 @@ func (c *Cron) runDue(t time.Time) {
 
 	moment := NewMoment(t.In(c.timezone))
 
+	observer := c.observer
+
 	for _, j := range c.jobs {
-		if j.schedule.IsDue(moment) {
-			go j.run()
+		if !j.IsPaused() && j.schedule.IsDue(moment) {
+			go runJob(j, observer)
 		}
 	}
 }
+
+// runJob executes j and calls the observer (if any) with its run duration.
+func runJob(j *Job, observer func(job *Job, duration time.Duration)) {
+	if observer == nil {
+		j.Run()
+		return
+	}
+
+	start := time.Now()
+	j.Run()
+	observer(j, time.Since(start))
+}
@@ -2,6 +2,9 @@ package cron
 
 import (
 	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -261,3 +264,216 @@ func TestCronStartStop(t *testing.T) {
 		t.Fatalf("Expected %d test2, got %d", expectedCalls, test2)
 	}
 }
+
+func TestCronJobs(t *testing.T) {
+	t.Parallel()
+
+	c := New()
+
+	if v := len(c.Jobs()); v != 0 {
+		t.Fatalf("Expected 0 jobs, got %v", v)
+	}
+
+	c.MustAdd("test2", "* * * * *", func() {})
+	c.MustAdd("test1", "1 2 3 4 5", func() {})
+	c.MustAdd("test3", "@daily", func() {})
+
+	jobs := c.Jobs()
+
+	expected := []string{"test1:1 2 3 4 5", "test2:* * * * *", "test3:@daily"}
+
+	if len(jobs) != len(expected) {
+		t.Fatalf("Expected %d jobs, got %d", len(expected), len(jobs))
+	}
+
+	for i, j := range jobs {
+		if v := j.Id() + ":" + j.Expression(); v != expected[i] {
+			t.Fatalf("Expected job %d to be %q, got %q", i, expected[i], v)
+		}
+	}
+
+	if j := c.Job("test1"); j == nil || j.Id() != "test1" {
+		t.Fatalf("Expected job test1, got %v", j)
+	}
+
+	if j := c.Job("missing"); j != nil {
+		t.Fatalf("Expected nil job, got %v", j)
+	}
+}
+
+func TestCronPauseResume(t *testing.T) {
+	t.Parallel()
+
+	c := New()
+
+	var calls1, calls2 atomic.Int32
+
+	c.MustAdd("test1", "* * * * *", func() { calls1.Add(1) })
+	c.MustAdd("test2", "* * * * *", func() { calls2.Add(1) })
+
+	if err := c.Pause("missing"); err == nil {
+		t.Fatal("Expected Pause error for missing job")
+	}
+
+	if err := c.Resume("missing"); err == nil {
+		t.Fatal("Expected Resume error for missing job")
+	}
+
+	if err := c.Pause("test1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !c.Job("test1").IsPaused() {
+		t.Fatal("Expected test1 to be paused")
+	}
+
+	// replacing the job should preserve the paused state
+	c.MustAdd("test1", "* * * * *", func() { calls1.Add(1) })
+	if !c.Job("test1").IsPaused() {
+		t.Fatal("Expected the replaced test1 job to remain paused")
+	}
+
+	// removing and re-adding the job should preserve the paused state
+	c.Remove("test1")
+	c.MustAdd("test1", "* * * * *", func() { calls1.Add(1) })
+	if !c.Job("test1").IsPaused() {
+		t.Fatal("Expected the re-added test1 job to remain paused")
+	}
+
+	c.runDue(time.Now())
+	time.Sleep(100 * time.Millisecond)
+
+	if v := calls1.Load(); v != 0 {
+		t.Fatalf("Expected the paused test1 job to not be executed, got %d calls", v)
+	}
+
+	if v := calls2.Load(); v != 1 {
+		t.Fatalf("Expected test2 job to be executed once, got %d calls", v)
+	}
+
+	if err := c.Resume("test1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Job("test1").IsPaused() {
+		t.Fatal("Expected test1 to be resumed")
+	}
+}
+
+func TestCronSetObserver(t *testing.T) {
+	t.Parallel()
+
+	var mux sync.Mutex
+	observed := map[string]int{}
+
+	c := New()
+
+	c.SetInterval(200 * time.Millisecond)
+
+	c.SetObserver(func(job *Job, duration time.Duration) {
+		mux.Lock()
+		defer mux.Unlock()
+
+		if duration < 10*time.Millisecond {
+			t.Errorf("Expected at least 10ms duration for job %q, got %v", job.Id(), duration)
+		}
+
+		observed[job.Id()]++
+	})
+
+	c.MustAdd("test", "* * * * *", func() {
+		time.Sleep(10 * time.Millisecond)
+	})
+
+	c.Start()
+
+	time.Sleep(300 * time.Millisecond)
+
+	c.Stop()
+
+	time.Sleep(50 * time.Millisecond) // wait for the job to complete
+
+	mux.Lock()
+	defer mux.Unlock()
+
+	if observed["test"] == 0 {
+		t.Fatalf("Expected the test job to be observed, got %v", observed)
+	}
+}
+
+func TestCronPauseObserverAndPausedJobs(t *testing.T) {
+	t.Parallel()
+
+	c := New()
+
+	c.MustAdd("test1", "* * * * *", func() {})
+	c.MustAdd("test2", "* * * * *", func() {})
+
+	observed := [][]string{}
+	c.SetPauseObserver(func(pausedJobIds []string) {
+		observed = append(observed, pausedJobIds)
+	})
+
+	c.Pause("test2")
+	c.Pause("test1")
+	c.Pause("missing") // not observed
+	c.Resume("test2")
+
+	expected := `[["test2"] ["test1" "test2"] ["test1"]]`
+	if v := fmt.Sprintf("%q", observed); v != expected {
+		t.Fatalf("Expected observed %s, got %s", expected, v)
+	}
+
+	// restore with not yet registered jobs
+	c.SetPausedJobs([]string{"test2", "test3"})
+
+	if c.Job("test1").IsPaused() || !c.Job("test2").IsPaused() {
+		t.Fatal("Expected only test2 to be paused")
+	}
+
+	c.MustAdd("test3", "* * * * *", func() {})
+	if !c.Job("test3").IsPaused() {
+		t.Fatal("Expected the newly added test3 to be paused")
+	}
+
+	if v := fmt.Sprintf("%q", c.PausedJobs()); v != `["test2" "test3"]` {
+		t.Fatalf("Expected paused jobs %s, got %s", `["test2" "test3"]`, v)
+	}
+
+	if len(observed) != 3 {
+		t.Fatalf("Expected SetPausedJobs to not be observed, got %v", observed)
+	}
+}
+
+func TestCronRun(t *testing.T) {
+	t.Parallel()
+
+	c := New()
+
+	var calls atomic.Int32
+	c.MustAdd("test", "* * * * *", func() { calls.Add(1) })
+
+	var observed atomic.Int32
+	c.SetObserver(func(job *Job, duration time.Duration) {
+		observed.Add(1)
+	})
+
+	if err := c.Run("missing"); err == nil {
+		t.Fatal("Expected Run error for missing job")
+	}
+
+	// paused jobs can be still run manually
+	c.Pause("test")
+
+	if err := c.Run("test"); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := calls.Load(); v != 1 {
+		t.Fatalf("Expected the test job to be executed once, got %d", v)
+	}
+
+	if v := observed.Load(); v != 1 {
+		t.Fatalf("Expected the manual run to be observed once, got %d", v)
+	}
+}
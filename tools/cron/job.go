@@ -0,0 +1,53 @@
+package cron
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Job defines a single registered cron job.
+type Job struct {
+	schedule   *Schedule
+	run        func()
+	id         string
+	expression string
+	paused     atomic.Bool
+}
+
+// Id returns the cron job id.
+func (j *Job) Id() string {
+	return j.id
+}
+
+// Expression returns the plain cron job schedule expression.
+func (j *Job) Expression() string {
+	return j.expression
+}
+
+// IsPaused reports whether the job is currently paused
+// (aka. whether it will be skipped by the cron ticker).
+func (j *Job) IsPaused() bool {
+	return j.paused.Load()
+}
+
+// NextRun returns the next time after t when the job is scheduled to run.
+//
+// It returns a zero time.Time if the job is paused or its
+// schedule doesn't have any matching moment in the near future.
+func (j *Job) NextRun(t time.Time) time.Time {
+	if j.IsPaused() {
+		return time.Time{}
+	}
+
+	return j.schedule.Next(t)
+}
+
+// Run executes the cron job function.
+//
+// Note that the job is executed even if it is paused and that the
+// cron observer is not notified (use [Cron.Run] for that).
+func (j *Job) Run() {
+	if j.run != nil {
+		j.run()
+	}
+}
@@ -0,0 +1,49 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJobRun(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+
+	c := New()
+	c.MustAdd("test", "* * * * *", func() { calls++ })
+
+	j := c.Job("test")
+
+	j.Run()
+
+	// paused jobs could still be executed manually
+	c.Pause("test")
+	j.Run()
+
+	if calls != 2 {
+		t.Fatalf("Expected 2 calls, got %d", calls)
+	}
+}
+
+func TestJobNextRun(t *testing.T) {
+	t.Parallel()
+
+	c := New()
+	c.MustAdd("test", "30 * * * *", func() {})
+
+	j := c.Job("test")
+
+	date := time.Date(2023, 5, 9, 15, 20, 0, 0, time.UTC)
+
+	expected := time.Date(2023, 5, 9, 15, 30, 0, 0, time.UTC)
+	if v := j.NextRun(date); !v.Equal(expected) {
+		t.Fatalf("Expected next run %v, got %v", expected, v)
+	}
+
+	c.Pause("test")
+
+	if v := j.NextRun(date); !v.IsZero() {
+		t.Fatalf("Expected zero next run for paused job, got %v", v)
+	}
+}
@@ -62,6 +62,49 @@ func (s *Schedule) IsDue(m *Moment) bool {
 	return true
 }
 
+// nextSearchLimit is the max period that Next() will scan for a matching moment.
+const nextSearchLimit = 5 * 366 * 24 * time.Hour
+
+// Next returns the first moment after t (with minute precision)
+// that satisfies the current Schedule.
+//
+// The returned time is in the same location as t.
+// If there is no matching moment within the next 5 years
+// (eg. "0 0 31 2 *") a zero time.Time is returned.
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+	limit := next.Add(nextSearchLimit)
+
+	for next.Before(limit) {
+		if _, ok := s.Months[int(next.Month())]; !ok {
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+
+		_, dayOk := s.Days[next.Day()]
+		_, dayOfWeekOk := s.DaysOfWeek[int(next.Weekday())]
+		if !dayOk || !dayOfWeekOk {
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+
+		if _, ok := s.Hours[next.Hour()]; !ok {
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+
+		if _, ok := s.Minutes[next.Minute()]; !ok {
+			next = next.Add(time.Minute)
+			continue
+		}
+
+		return next
+	}
+
+	return time.Time{}
+}
+
 var macros = map[string]string{
 	"@yearly":   "0 0 1 1 *",
 	"@annually": "0 0 1 1 *",
@@ -2,6 +2,7 @@ package cron_test
 
 import (
 	"encoding/json"
+	"fmt"
 	"testing"
 	"time"
 
@@ -402,3 +403,48 @@ func TestScheduleIsDue(t *testing.T) {
 		}
 	}
 }
+
+func TestScheduleNext(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		cronExpr string
+		date     string
+		expected string
+	}{
+		{"* * * * *", "2023-05-09 15:20:30", "2023-05-09 15:21:00"},
+		{"*/15 * * * *", "2023-05-09 15:20:00", "2023-05-09 15:30:00"},
+		{"0 * * * *", "2023-05-09 15:00:00", "2023-05-09 16:00:00"},
+		{"30 2 * * *", "2023-05-09 15:20:00", "2023-05-10 02:30:00"},
+		{"0 0 * * 0", "2023-05-09 15:20:00", "2023-05-14 00:00:00"},
+		{"0 0 1 * *", "2023-12-09 15:20:00", "2024-01-01 00:00:00"},
+		{"@yearly", "2023-05-09 15:20:00", "2024-01-01 00:00:00"},
+		{"0 0 29 2 *", "2023-05-09 15:20:00", "2024-02-29 00:00:00"},
+		{"0 0 31 2 *", "2023-05-09 15:20:00", ""}, // impossible date
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%s", i, s.cronExpr), func(t *testing.T) {
+			schedule, err := cron.NewSchedule(s.cronExpr)
+			if err != nil {
+				t.Fatalf("Unexpected cron error: %v", err)
+			}
+
+			date, err := time.Parse("2006-01-02 15:04:05", s.date)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			next := schedule.Next(date)
+
+			var result string
+			if !next.IsZero() {
+				result = next.Format("2006-01-02 15:04:05")
+			}
+
+			if result != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, result)
+			}
+		})
+	}
+}
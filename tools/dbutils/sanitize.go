@@ -0,0 +1,72 @@
+package dbutils
+
+import "strings"
+
+// SanitizeSQL replaces the literal values of the provided SQL statement
+// (quoted strings, numbers, hex blobs and the dbx "<nil>" params)
+// with "?" placeholders.
+//
+// It is intended for logging and tracing the dbx statements with
+// inlined params without exposing the param values, eg.:
+//
+//	SELECT * FROM users WHERE email='test@example.com' LIMIT 1
+//	// ->
+//	SELECT * FROM users WHERE email=? LIMIT ?
+//
+// The quoted identifiers (eg. "name" or `name`) are preserved.
+func SanitizeSQL(query string) string {
+	var result strings.Builder
+	result.Grow(len(query))
+
+	for i := 0; i < len(query); {
+		c := query[i]
+
+		switch {
+		case c == '\'':
+			// skip until the closing quote ('' is an escaped quote)
+			j := i + 1
+			for ; j < len(query); j++ {
+				if query[j] != '\'' {
+					continue
+				}
+
+				if j+1 < len(query) && query[j+1] == '\'' {
+					j++
+					continue
+				}
+
+				break
+			}
+			result.WriteByte('?')
+			i = j + 1
+		case (c == '"' || c == '`') && strings.IndexByte(query[i+1:], c) >= 0:
+			end := i + 1 + strings.IndexByte(query[i+1:], c)
+			result.WriteString(query[i : end+1])
+			i = end + 1
+		case isDigit(c) && (i == 0 || !isIdentifierChar(query[i-1])):
+			// numbers, decimals, exponents and hex blobs (eg. 0x1f)
+			j := i + 1
+			for j < len(query) && (isIdentifierChar(query[j]) || query[j] == '.') {
+				j++
+			}
+			result.WriteByte('?')
+			i = j
+		case strings.HasPrefix(query[i:], "<nil>"):
+			result.WriteByte('?')
+			i += len("<nil>")
+		default:
+			result.WriteByte(c)
+			i++
+		}
+	}
+
+	return result.String()
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentifierChar(c byte) bool {
+	return isDigit(c) || c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
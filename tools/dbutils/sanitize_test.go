@@ -0,0 +1,44 @@
+package dbutils_test
+
+import (
+	"testing"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/dbutils"
+)
+
+func TestSanitizeSQL(t *testing.T) {
+	scenarios := []struct {
+		query    string
+		expected string
+	}{
+		{``, ``},
+		{`SELECT 1`, `SELECT ?`},
+		{
+			`SELECT * FROM "users" WHERE "email"='test@example.com' LIMIT 1`,
+			`SELECT * FROM "users" WHERE "email"=? LIMIT ?`,
+		},
+		{
+			"SELECT `a1`, t2.col3 FROM t2 WHERE x=-1.5e10 AND y=0x1f2e AND z=<nil>",
+			"SELECT `a1`, t2.col3 FROM t2 WHERE x=-? AND y=? AND z=?",
+		},
+		{
+			`UPDATE {{demo}} SET [[title]]='it''s "quoted"', [[count]]=[[count]]+10 WHERE [[id]]=$1`,
+			`UPDATE {{demo}} SET [[title]]=?, [[count]]=[[count]]+? WHERE [[id]]=$1`,
+		},
+		{
+			`SELECT 'unterminated`,
+			`SELECT ?`,
+		},
+		{
+			`SELECT "unterminated 'secret'`,
+			`SELECT "unterminated ?`,
+		},
+	}
+
+	for i, s := range scenarios {
+		result := dbutils.SanitizeSQL(s.query)
+		if result != s.expected {
+			t.Errorf("[%d] Expected\n%s\ngot\n%s", i, s.expected, result)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package filesystem
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/gabriel-vasile/mimetype"
+	"gocloud.dev/blob"
+	"gocloud.dev/gcerrors"
+)
+
+// DedupBlobsPrefix is the storage key prefix of the content-addressed file blobs.
+//
+// The blobs are scoped per the top level dir of the file key (usually
+// the collection id) and are stored under "_blobs/{scope}/{hash[:2]}/{hash}".
+const DedupBlobsPrefix = "_blobs/"
+
+// dedupBlobMetaKey is the metadata key of the file pointer objects
+// that holds the sha256 hash of the referenced blob.
+const dedupBlobMetaKey = "pb-blob"
+
+// ErrMissingDedupIndex is returned when trying to store
+// a deduplicated file without a registered [DedupIndex].
+var ErrMissingDedupIndex = errors.New("the deduplicated files require a dedup index")
+
+// DedupIndex defines the storage of the content-addressed blob references.
+//
+// The index is expected to be shared between all app instances
+// and to change the blob references count atomically
+// (eg. [daos.Dao] implements it with the "_fileBlobs" table).
+type DedupIndex interface {
+	// AddFileBlobRef registers fileKey as a reference to blobKey.
+	//
+	// If fileKey was a reference to another blob, the old reference
+	// is released and onLastRef is called if it was the last one.
+	AddFileBlobRef(fileKey string, blobKey string, onLastRef func(blobKey string) error) error
+
+	// ReleaseFileBlobRefs releases the blob references of the specified
+	// file keys in a batch and calls onLastRef for each blob whose
+	// last reference was released.
+	//
+	// Returns the number of the released references
+	// (the file keys that are not blob references are ignored).
+	ReleaseFileBlobRefs(fileKeys []string, onLastRef func(blobKey string) error) (int, error)
+}
+
+// SetDedupIndex registers the index of the deduplicated files blob references.
+//
+// Without an index the deduplicated files can be still read but
+// they cannot be created and are deleted as regular files.
+func (s *System) SetDedupIndex(index DedupIndex) {
+	s.dedupIndex = index
+}
+
+// UploadFileDedup uploads the provided file to the fileKey location
+// as a reference to a content-addressed blob, aka. files with the
+// same content and scope are stored only once.
+//
+// The fileKey location holds only an empty pointer object and the
+// blob content is transparently resolved on read (see [System.NewContentReader]).
+// The blob is deleted when its last reference is deleted.
+func (s *System) UploadFileDedup(file *File, fileKey string) error {
+	if s.dedupIndex == nil {
+		return ErrMissingDedupIndex
+	}
+
+	f, err := file.Reader.Open()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	hash := hex.EncodeToString(h.Sum(nil))
+
+	// rewind
+	f.Seek(0, io.SeekStart)
+
+	mt, err := mimetype.DetectReader(f)
+	if err != nil {
+		return err
+	}
+
+	// rewind
+	f.Seek(0, io.SeekStart)
+
+	originalName := file.OriginalName
+	if len(originalName) > 255 {
+		// keep only the first 255 chars as a very rudimentary measure
+		// to prevent the metadata to grow too big in size
+		originalName = originalName[:255]
+	}
+
+	blobKey := dedupBlobKey(fileKey, hash)
+
+	// register the reference first so that a concurrent release
+	// of the last previous reference wouldn't delete the blob
+	if err := s.dedupIndex.AddFileBlobRef(fileKey, blobKey, s.deleteBlob); err != nil {
+		return err
+	}
+
+	exists, err := s.bucket.Exists(s.ctx, blobKey)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		w, err := s.newWriter(blobKey, &blob.WriterOptions{ContentType: mt.String()})
+		if err != nil {
+			return err
+		}
+
+		if _, err := io.Copy(w, f); err != nil {
+			w.Close()
+			return err
+		}
+
+		if err := w.Close(); err != nil {
+			return err
+		}
+	}
+
+	return s.writePointer(hash, fileKey, &blob.WriterOptions{
+		ContentType: mt.String(),
+		Metadata: map[string]string{
+			"original-filename": originalName,
+		},
+	})
+}
+
+// IsDedup checks whether the fileKey file is a reference to a content-addressed blob.
+func (s *System) IsDedup(fileKey string) (bool, error) {
+	attrs, err := s.bucket.Attributes(s.ctx, fileKey)
+	if err != nil {
+		return false, err
+	}
+
+	return attrs.Metadata[dedupBlobMetaKey] != "", nil
+}
+
+// resolveAttributes returns the attributes of the fileKey file content,
+// aka. the referenced blob ones in case of a deduplicated file.
+func (s *System) resolveAttributes(fileKey string) (string, *blob.Attributes, error) {
+	attrs, err := s.bucket.Attributes(s.ctx, fileKey)
+	if err != nil {
+		return "", nil, err
+	}
+
+	hash := attrs.Metadata[dedupBlobMetaKey]
+	if hash == "" {
+		return fileKey, attrs, nil
+	}
+
+	blobKey := dedupBlobKey(fileKey, hash)
+
+	blobAttrs, err := s.bucket.Attributes(s.ctx, blobKey)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return blobKey, blobAttrs, nil
+}
+
+// copyBlobRef creates a new dstKey reference to the srcKey blob with the specified hash.
+//
+// If dstKey is from a different scope, the blob is copied to the dstKey scope (if missing).
+func (s *System) copyBlobRef(hash string, srcKey string, dstKey string, attrs *blob.Attributes) error {
+	if s.dedupIndex == nil {
+		return ErrMissingDedupIndex
+	}
+
+	dstBlobKey := dedupBlobKey(dstKey, hash)
+
+	if err := s.dedupIndex.AddFileBlobRef(dstKey, dstBlobKey, s.deleteBlob); err != nil {
+		return err
+	}
+
+	if srcBlobKey := dedupBlobKey(srcKey, hash); srcBlobKey != dstBlobKey {
+		exists, err := s.bucket.Exists(s.ctx, dstBlobKey)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			if err := s.bucket.Copy(s.ctx, dstBlobKey, srcBlobKey, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	return s.writePointer(hash, dstKey, &blob.WriterOptions{
+		ContentType: attrs.ContentType,
+		Metadata:    attrs.Metadata,
+	})
+}
+
+// writePointer writes the fileKey pointer object to the blob with the specified hash.
+func (s *System) writePointer(hash string, fileKey string, opts *blob.WriterOptions) error {
+	metadata := make(map[string]string, len(opts.Metadata)+1)
+	for k, v := range opts.Metadata {
+		metadata[k] = v
+	}
+	metadata[dedupBlobMetaKey] = hash
+
+	pointerOpts := *opts
+	pointerOpts.Metadata = metadata
+
+	return s.writeEmpty(fileKey, &pointerOpts)
+}
+
+// releaseBlobRefsBatchSize is the max number of file keys
+// released with a single dedup index call.
+const releaseBlobRefsBatchSize = 500
+
+// releaseBlobRefs releases the blob references of the specified file keys (if any).
+//
+// The keys that cannot be file pointers (dirs, thumbs and blobs) are skipped.
+//
+// Returns the number of the released references
+// (0 if there is no registered dedup index).
+func (s *System) releaseBlobRefs(fileKeys ...string) (int, error) {
+	if s.dedupIndex == nil {
+		return 0, nil
+	}
+
+	candidates := make([]string, 0, len(fileKeys))
+	for _, fileKey := range fileKeys {
+		if isBlobRefCandidate(fileKey) {
+			candidates = append(candidates, fileKey)
+		}
+	}
+
+	var released int
+
+	for i := 0; i < len(candidates); i += releaseBlobRefsBatchSize {
+		batch := candidates[i:min(i+releaseBlobRefsBatchSize, len(candidates))]
+
+		n, err := s.dedupIndex.ReleaseFileBlobRefs(batch, s.deleteBlob)
+		released += n
+		if err != nil {
+			return released, err
+		}
+	}
+
+	return released, nil
+}
+
+// isBlobRefCandidate checks whether fileKey could be a deduplicated file pointer
+// (the dirs, the generated thumbs and the blobs themselves are never pointers).
+func isBlobRefCandidate(fileKey string) bool {
+	if fileKey == "" || strings.HasSuffix(fileKey, "/") || strings.HasPrefix(fileKey, DedupBlobsPrefix) {
+		return false
+	}
+
+	return !strings.HasPrefix(path.Base(path.Dir(fileKey)), "thumbs_")
+}
+
+// deleteBlob deletes the blob stored at blobKey (if exists).
+func (s *System) deleteBlob(blobKey string) error {
+	if err := s.bucket.Delete(s.ctx, blobKey); err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+		return err
+	}
+
+	return nil
+}
+
+func (s *System) writeEmpty(key string, opts *blob.WriterOptions) error {
+	if opts == nil {
+		opts = &blob.WriterOptions{}
+	}
+
+	if opts.ContentType == "" {
+		opts.ContentType = "application/octet-stream"
+	}
+
+	w, err := s.bucket.NewWriter(s.ctx, key, opts)
+	if err != nil {
+		return err
+	}
+
+	return w.Close()
+}
+
+// dedupBlobKey returns the storage key of the blob with the
+// specified hash within the scope of the provided fileKey.
+func dedupBlobKey(fileKey string, hash string) string {
+	scope, _, _ := strings.Cut(fileKey, "/")
+
+	return DedupBlobsPrefix + scope + "/" + hash[:2] + "/" + hash
+}
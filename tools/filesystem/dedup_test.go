@@ -0,0 +1,284 @@
+package filesystem_test
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/filesystem"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/security"
+)
+
+func TestFileSystemUploadFileDedup(t *testing.T) {
+	fs, err := filesystem.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	index := newTestDedupIndex()
+	fs.SetDedupIndex(index)
+
+	file1, _ := filesystem.NewFileFromBytes([]byte("test"), "test1.txt")
+	file2, _ := filesystem.NewFileFromBytes([]byte("test"), "test2.txt")
+	file3, _ := filesystem.NewFileFromBytes([]byte("other"), "test3.txt")
+
+	keys := map[string]*filesystem.File{
+		"a/r1/" + file1.Name: file1,
+		"a/r2/" + file2.Name: file2,
+		"b/r3/" + file3.Name: file3,
+	}
+
+	for key, file := range keys {
+		if err := fs.UploadFileDedup(file, key); err != nil {
+			t.Fatalf("[%s] Failed to upload: %v", key, err)
+		}
+	}
+
+	assertBlobs(t, fs, 2)
+
+	for key, file := range keys {
+		if isDedup, err := fs.IsDedup(key); err != nil || !isDedup {
+			t.Fatalf("[%s] Expected dedup file, got %v (%v)", key, isDedup, err)
+		}
+
+		attrs, err := fs.Attributes(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if attrs.Metadata["original-filename"] != file.OriginalName {
+			t.Fatalf("[%s] Expected original filename %q, got %q", key, file.OriginalName, attrs.Metadata["original-filename"])
+		}
+
+		expected := "test"
+		if file == file3 {
+			expected = "other"
+		}
+
+		r, err := fs.NewContentReader(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := io.ReadAll(r)
+		r.Close()
+
+		if string(content) != expected {
+			t.Fatalf("[%s] Expected content %q, got %q", key, expected, content)
+		}
+	}
+
+	// serve
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/", nil)
+	if err := fs.Serve(res, req, "a/r1/"+file1.Name, file1.Name); err != nil {
+		t.Fatal(err)
+	}
+	if body := res.Body.String(); body != "test" {
+		t.Fatalf("Expected served content %q, got %q", "test", body)
+	}
+
+	// copy within the same scope only adds a new reference
+	if err := fs.Copy("b/r3/"+file3.Name, "b/r4/copy.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if isDedup, _ := fs.IsDedup("b/r4/copy.txt"); !isDedup {
+		t.Fatal("Expected the copy to be a dedup file")
+	}
+	assertBlobs(t, fs, 2)
+
+	// copy to a different scope copies the blob
+	if err := fs.Copy("a/r1/"+file1.Name, "c/r5/copy.txt"); err != nil {
+		t.Fatal(err)
+	}
+	assertBlobs(t, fs, 3)
+
+	// the blob is kept while still referenced
+	if err := fs.Delete("a/r1/" + file1.Name); err != nil {
+		t.Fatal(err)
+	}
+	assertBlobs(t, fs, 3)
+
+	if errs := fs.DeletePrefix("a/"); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	assertBlobs(t, fs, 2)
+
+	// the thumbs are never checked for blob references
+	if err := fs.Upload([]byte("thumb"), "b/r3/thumbs_"+file3.Name+"/10x10_"+file3.Name); err != nil {
+		t.Fatal(err)
+	}
+	index.releaseCalls = 0
+	if err := fs.Delete("b/r3/thumbs_" + file3.Name + "/10x10_" + file3.Name); err != nil {
+		t.Fatal(err)
+	}
+	if index.releaseCalls != 0 {
+		t.Fatalf("Expected no release calls for the thumb, got %d", index.releaseCalls)
+	}
+
+	// the prefix references are released with a single batch
+	if errs := fs.DeletePrefix("b/"); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	assertBlobs(t, fs, 1)
+	if index.releaseCalls != 1 {
+		t.Fatalf("Expected 1 batched release call, got %d", index.releaseCalls)
+	}
+
+	// overwrite the last reference with a different content
+	if err := fs.UploadFileDedup(file3, "c/r5/copy.txt"); err != nil {
+		t.Fatal(err)
+	}
+	assertBlobs(t, fs, 1)
+
+	if err := fs.Delete("c/r5/copy.txt"); err != nil {
+		t.Fatal(err)
+	}
+	assertBlobs(t, fs, 0)
+
+	if exists, _ := fs.Exists("c/r5/copy.txt"); exists {
+		t.Fatal("Expected the reference to be deleted")
+	}
+
+	if len(index.refs) != 0 || len(index.blobs) != 0 {
+		t.Fatalf("Expected empty dedup index, got %v, %v", index.refs, index.blobs)
+	}
+}
+
+func TestFileSystemUploadFileDedupWithoutIndex(t *testing.T) {
+	fs, err := filesystem.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	file, _ := filesystem.NewFileFromBytes([]byte("test"), "test.txt")
+
+	err = fs.UploadFileDedup(file, "a/test.txt")
+	if !errors.Is(err, filesystem.ErrMissingDedupIndex) {
+		t.Fatalf("Expected ErrMissingDedupIndex, got %v", err)
+	}
+
+	assertBlobs(t, fs, 0)
+}
+
+func TestFileSystemUploadFileDedupEncrypted(t *testing.T) {
+	fs, err := filesystem.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	fs.SetDedupIndex(newTestDedupIndex())
+
+	if err := fs.SetEncryptionKey(security.RandomString(32), true); err != nil {
+		t.Fatal(err)
+	}
+
+	file, _ := filesystem.NewFileFromBytes([]byte("test"), "test.txt")
+
+	if err := fs.UploadFileDedup(file, "a/test.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	if encrypted, _ := fs.IsEncrypted("a/test.txt"); !encrypted {
+		t.Fatal("Expected the blob to be encrypted")
+	}
+
+	r, err := fs.NewContentReader("a/test.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	content, _ := io.ReadAll(r)
+	if string(content) != "test" {
+		t.Fatalf("Expected content %q, got %q", "test", content)
+	}
+}
+
+func assertBlobs(t *testing.T, fs *filesystem.System, expected int) {
+	t.Helper()
+
+	objects, err := fs.List(filesystem.DedupBlobsPrefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(objects) != expected {
+		t.Fatalf("Expected %d blobs, got %d", expected, len(objects))
+	}
+}
+
+// testDedupIndex is an in-memory filesystem.DedupIndex implementation.
+type testDedupIndex struct {
+	mux          sync.Mutex
+	refs         map[string]string
+	blobs        map[string]int
+	releaseCalls int
+}
+
+func newTestDedupIndex() *testDedupIndex {
+	return &testDedupIndex{
+		refs:  map[string]string{},
+		blobs: map[string]int{},
+	}
+}
+
+func (idx *testDedupIndex) AddFileBlobRef(fileKey string, blobKey string, onLastRef func(blobKey string) error) error {
+	idx.mux.Lock()
+	defer idx.mux.Unlock()
+
+	if old, ok := idx.refs[fileKey]; ok {
+		if old == blobKey {
+			return nil
+		}
+
+		if err := idx.release(fileKey, onLastRef); err != nil {
+			return err
+		}
+	}
+
+	idx.refs[fileKey] = blobKey
+	idx.blobs[blobKey]++
+
+	return nil
+}
+
+func (idx *testDedupIndex) ReleaseFileBlobRefs(fileKeys []string, onLastRef func(blobKey string) error) (int, error) {
+	idx.mux.Lock()
+	defer idx.mux.Unlock()
+
+	idx.releaseCalls++
+
+	var released int
+
+	for _, fileKey := range fileKeys {
+		if _, ok := idx.refs[fileKey]; !ok {
+			continue
+		}
+
+		if err := idx.release(fileKey, onLastRef); err != nil {
+			return released, err
+		}
+
+		released++
+	}
+
+	return released, nil
+}
+
+func (idx *testDedupIndex) release(fileKey string, onLastRef func(blobKey string) error) error {
+	blobKey := idx.refs[fileKey]
+	delete(idx.refs, fileKey)
+
+	idx.blobs[blobKey]--
+	if idx.blobs[blobKey] > 0 {
+		return nil
+	}
+
+	delete(idx.blobs, blobKey)
+
+	return onLastRef(blobKey)
+}
@@ -0,0 +1,406 @@
+package filesystem
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	crand "crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/security"
+	"gocloud.dev/blob"
+)
+
+// The encrypted files metadata keys.
+const (
+	encryptionKeyMetaKey   = "pb-enc-key"
+	encryptionNonceMetaKey = "pb-enc-nonce"
+)
+
+const (
+	// encryptionChunkSize is the size of a single plain content chunk.
+	encryptionChunkSize = 64 * 1024
+
+	// encryptionTagSize is the size of the AES-GCM authentication tag appended to each chunk.
+	encryptionTagSize = 16
+
+	// encryptionNoncePrefixSize is the size of the random per-file nonce
+	// prefix (the remaining 4 bytes of the nonce are the chunk index).
+	encryptionNoncePrefixSize = 8
+)
+
+// ContentReader defines a file content reader.
+//
+// For the encrypted files the content is transparently decrypted
+// (including on Seek, aka. range requests).
+type ContentReader interface {
+	io.ReadSeekCloser
+
+	// ContentType returns the file MIME type.
+	ContentType() string
+
+	// ModTime returns the file last modified time.
+	ModTime() time.Time
+
+	// Size returns the (decrypted) file content size.
+	Size() int64
+}
+
+// SetEncryptionKey enables the envelope encryption of the files stored in the filesystem.
+//
+// key must be a valid 32 chars AES key and it is used only to wrap the
+// random per-file data keys (the file content is encrypted with AES-256-GCM
+// in fixed size chunks to allow random access).
+//
+// If encryptUploads is false, only the existing encrypted files are decrypted
+// and the new files are stored as they are.
+func (s *System) SetEncryptionKey(key string, encryptUploads bool) error {
+	if _, err := aes.NewCipher([]byte(key)); err != nil || len(key) != 32 {
+		return errors.New("the encryption key must be a valid 32 chars AES key")
+	}
+
+	s.encryptionKey = key
+	s.encryptUploads = encryptUploads
+
+	return nil
+}
+
+// IsEncrypted checks whether the fileKey file content is stored encrypted.
+func (s *System) IsEncrypted(fileKey string) (bool, error) {
+	_, attrs, err := s.resolveAttributes(fileKey)
+	if err != nil {
+		return false, err
+	}
+
+	return attrs.Metadata[encryptionKeyMetaKey] != "", nil
+}
+
+// NewContentReader returns a file content reader for the given fileKey
+// that transparently decrypts the encrypted files and resolves the
+// deduplicated files blob content.
+//
+// NB! Make sure to call `Close()` after you are done working with it.
+func (s *System) NewContentReader(fileKey string) (ContentReader, error) {
+	fileKey, attrs, err := s.resolveAttributes(fileKey)
+	if err != nil {
+		return nil, err
+	}
+
+	br, err := s.bucket.NewReader(s.ctx, fileKey, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	wrappedKey := attrs.Metadata[encryptionKeyMetaKey]
+	if wrappedKey == "" {
+		return br, nil // not encrypted
+	}
+
+	if s.encryptionKey == "" {
+		br.Close()
+		return nil, errors.New("missing encryption key to decrypt " + fileKey)
+	}
+
+	gcm, noncePrefix, err := s.unwrapDataKey(wrappedKey, attrs.Metadata[encryptionNonceMetaKey])
+	if err != nil {
+		br.Close()
+		return nil, err
+	}
+
+	return newDecryptReader(br, gcm, noncePrefix), nil
+}
+
+// newWriter opens a new storage writer for the fileKey location
+// that encrypts the written content (if encryption is enabled).
+func (s *System) newWriter(fileKey string, opts *blob.WriterOptions) (io.WriteCloser, error) {
+	return s.newContextWriter(s.ctx, fileKey, opts)
+}
+
+// newContextWriter is similar to [System.newWriter] but opens the
+// writer with the provided ctx (the write is aborted if ctx is canceled).
+func (s *System) newContextWriter(ctx context.Context, fileKey string, opts *blob.WriterOptions) (io.WriteCloser, error) {
+	if !s.encryptUploads {
+		return s.bucket.NewWriter(ctx, fileKey, opts)
+	}
+
+	if opts == nil {
+		opts = &blob.WriterOptions{}
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := io.ReadFull(crand.Reader, dataKey); err != nil {
+		return nil, err
+	}
+
+	noncePrefix := make([]byte, encryptionNoncePrefixSize)
+	if _, err := io.ReadFull(crand.Reader, noncePrefix); err != nil {
+		return nil, err
+	}
+
+	wrappedKey, err := security.Encrypt(dataKey, s.encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := make(map[string]string, len(opts.Metadata)+2)
+	for k, v := range opts.Metadata {
+		metadata[k] = v
+	}
+	metadata[encryptionKeyMetaKey] = wrappedKey
+	metadata[encryptionNonceMetaKey] = base64.StdEncoding.EncodeToString(noncePrefix)
+
+	encryptedOpts := *opts
+	encryptedOpts.Metadata = metadata
+
+	w, err := s.bucket.NewWriter(ctx, fileKey, &encryptedOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &encryptWriter{
+		w:           w,
+		gcm:         gcm,
+		noncePrefix: noncePrefix,
+		buf:         make([]byte, 0, encryptionChunkSize),
+	}, nil
+}
+
+func (s *System) unwrapDataKey(wrappedKey string, rawNoncePrefix string) (cipher.AEAD, []byte, error) {
+	dataKey, err := security.Decrypt(wrappedKey, s.encryptionKey)
+	if err != nil {
+		return nil, nil, errors.New("failed to unwrap the file data key")
+	}
+
+	noncePrefix, err := base64.StdEncoding.DecodeString(rawNoncePrefix)
+	if err != nil || len(noncePrefix) != encryptionNoncePrefixSize {
+		return nil, nil, errors.New("invalid file nonce")
+	}
+
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return gcm, noncePrefix, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// chunkNonce returns the nonce of the chunk with the specified index.
+func chunkNonce(noncePrefix []byte, index int64) []byte {
+	nonce := make([]byte, encryptionNoncePrefixSize+4)
+	copy(nonce, noncePrefix)
+	binary.BigEndian.PutUint32(nonce[encryptionNoncePrefixSize:], uint32(index))
+	return nonce
+}
+
+// chunkAdditionalData returns the chunk authenticated data that
+// marks the last chunk to prevent undetected content truncation.
+func chunkAdditionalData(isLast bool) []byte {
+	if isLast {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+// -------------------------------------------------------------------
+
+type encryptWriter struct {
+	w           *blob.Writer
+	gcm         cipher.AEAD
+	noncePrefix []byte
+	buf         []byte
+	index       int64
+}
+
+// Write implements the [io.Writer] interface.
+func (w *encryptWriter) Write(p []byte) (int, error) {
+	total := len(p)
+
+	for len(p) > 0 {
+		n := min(encryptionChunkSize-len(w.buf), len(p))
+		w.buf = append(w.buf, p[:n]...)
+		p = p[n:]
+
+		// always keep the last full chunk in the buffer
+		// so that it could be marked as such on close
+		if len(w.buf) == encryptionChunkSize && len(p) > 0 {
+			if err := w.flush(false); err != nil {
+				return total - len(p), err
+			}
+		}
+	}
+
+	return total, nil
+}
+
+// Close implements the [io.Closer] interface.
+func (w *encryptWriter) Close() error {
+	if err := w.flush(true); err != nil {
+		w.w.Close()
+		return err
+	}
+
+	return w.w.Close()
+}
+
+func (w *encryptWriter) flush(isLast bool) error {
+	sealed := w.gcm.Seal(nil, chunkNonce(w.noncePrefix, w.index), w.buf, chunkAdditionalData(isLast))
+
+	if _, err := w.w.Write(sealed); err != nil {
+		return err
+	}
+
+	w.index++
+	w.buf = w.buf[:0]
+
+	return nil
+}
+
+// -------------------------------------------------------------------
+
+var _ ContentReader = (*decryptReader)(nil)
+
+type decryptReader struct {
+	br          *blob.Reader
+	gcm         cipher.AEAD
+	noncePrefix []byte
+
+	size       int64 // the plain content size
+	lastChunk  int64
+	offset     int64 // the current plain content offset
+	rawOffset  int64 // the current encrypted content offset
+	chunk      []byte
+	chunkIndex int64
+}
+
+func newDecryptReader(br *blob.Reader, gcm cipher.AEAD, noncePrefix []byte) *decryptReader {
+	// each chunk has a fixed overhead so the plain content size
+	// could be calculated from the encrypted one
+	// (note: there is always at least 1 chunk even for empty content)
+	encryptedChunkSize := int64(encryptionChunkSize + encryptionTagSize)
+	totalChunks := max((br.Size()+encryptedChunkSize-1)/encryptedChunkSize, 1)
+
+	return &decryptReader{
+		br:          br,
+		gcm:         gcm,
+		noncePrefix: noncePrefix,
+		size:        max(br.Size()-totalChunks*encryptionTagSize, 0),
+		lastChunk:   totalChunks - 1,
+		chunkIndex:  -1,
+	}
+}
+
+// ContentType implements the [ContentReader] interface.
+func (r *decryptReader) ContentType() string {
+	return r.br.ContentType()
+}
+
+// ModTime implements the [ContentReader] interface.
+func (r *decryptReader) ModTime() time.Time {
+	return r.br.ModTime()
+}
+
+// Size implements the [ContentReader] interface.
+func (r *decryptReader) Size() int64 {
+	return r.size
+}
+
+// Read implements the [io.Reader] interface.
+func (r *decryptReader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+
+	index := r.offset / encryptionChunkSize
+
+	if index != r.chunkIndex {
+		if err := r.loadChunk(index); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.chunk[r.offset-index*encryptionChunkSize:])
+	r.offset += int64(n)
+
+	return n, nil
+}
+
+// Seek implements the [io.Seeker] interface.
+func (r *decryptReader) Seek(offset int64, whence int) (int64, error) {
+	var newOffset int64
+
+	switch whence {
+	case io.SeekStart:
+		newOffset = offset
+	case io.SeekCurrent:
+		newOffset = r.offset + offset
+	case io.SeekEnd:
+		newOffset = r.size + offset
+	default:
+		return 0, errors.New("invalid seek whence")
+	}
+
+	if newOffset < 0 {
+		return 0, errors.New("negative seek offset")
+	}
+
+	r.offset = newOffset
+
+	return newOffset, nil
+}
+
+// Close implements the [io.Closer] interface.
+func (r *decryptReader) Close() error {
+	return r.br.Close()
+}
+
+func (r *decryptReader) loadChunk(index int64) error {
+	start := index * (encryptionChunkSize + encryptionTagSize)
+
+	if start != r.rawOffset {
+		if _, err := r.br.Seek(start, io.SeekStart); err != nil {
+			return err
+		}
+		r.rawOffset = start
+	}
+
+	sealed := make([]byte, min(encryptionChunkSize+encryptionTagSize, r.br.Size()-start))
+
+	n, err := io.ReadFull(r.br, sealed)
+	r.rawOffset += int64(n)
+	if err != nil {
+		return err
+	}
+
+	chunk, err := r.gcm.Open(
+		r.chunk[:0],
+		chunkNonce(r.noncePrefix, index),
+		sealed,
+		chunkAdditionalData(index == r.lastChunk),
+	)
+	if err != nil {
+		return errors.New("failed to decrypt file chunk")
+	}
+
+	r.chunk = chunk
+	r.chunkIndex = index
+
+	return nil
+}
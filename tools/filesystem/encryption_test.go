@@ -0,0 +1,311 @@
+package filesystem_test
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/filesystem"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/security"
+)
+
+func TestFileSystemSetEncryptionKey(t *testing.T) {
+	fs, err := filesystem.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	scenarios := []struct {
+		key         string
+		expectError bool
+	}{
+		{"", true},
+		{"short", true},
+		{security.RandomString(33), true},
+		{security.RandomString(32), false},
+	}
+
+	for i, s := range scenarios {
+		err := fs.SetEncryptionKey(s.key, true)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+		}
+	}
+}
+
+func TestFileSystemEncryptionRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	key := security.RandomString(32)
+
+	fs, err := filesystem.NewLocal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	if err := fs.SetEncryptionKey(key, true); err != nil {
+		t.Fatal(err)
+	}
+
+	// empty, single partial chunk, exactly 1 chunk and multiple chunks
+	sizes := []int{0, 100, 64 * 1024, 200*1024 + 7}
+
+	for _, size := range sizes {
+		content := bytes.Repeat([]byte("abcdefghij"), size/10+1)[:size]
+		fileKey := "test_" + strings.Repeat("x", size%7)
+
+		if err := fs.Upload(content, fileKey); err != nil {
+			t.Fatalf("[%d] Failed to upload: %v", size, err)
+		}
+
+		encrypted, err := fs.IsEncrypted(fileKey)
+		if err != nil || !encrypted {
+			t.Fatalf("[%d] Expected the file to be encrypted, got %v (%v)", size, encrypted, err)
+		}
+
+		// the raw stored content must differ
+		raw, err := os.ReadFile(dir + "/" + fileKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if size > 0 && bytes.Contains(raw, content[:min(size, 32)]) {
+			t.Fatalf("[%d] Expected the raw content to be encrypted", size)
+		}
+
+		r, err := fs.NewContentReader(fileKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if r.Size() != int64(size) {
+			t.Fatalf("[%d] Expected size %d, got %d", size, size, r.Size())
+		}
+
+		decrypted, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("[%d] Failed to read: %v", size, err)
+		}
+		if !bytes.Equal(decrypted, content) {
+			t.Fatalf("[%d] The decrypted content doesn't match", size)
+		}
+
+		// random access across the chunks boundary
+		if size > 64*1024 {
+			offset := int64(64*1024 - 5)
+			if _, err := r.Seek(offset, io.SeekStart); err != nil {
+				t.Fatal(err)
+			}
+
+			part := make([]byte, 10)
+			if _, err := io.ReadFull(r, part); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(part, content[offset:offset+10]) {
+				t.Fatalf("[%d] Expected %q, got %q", size, content[offset:offset+10], part)
+			}
+		}
+
+		r.Close()
+	}
+}
+
+func TestFileSystemEncryptionCompatibility(t *testing.T) {
+	dir := createTestDir(t)
+	defer os.RemoveAll(dir)
+
+	key := security.RandomString(32)
+
+	fs, err := filesystem.NewLocal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	if err := fs.SetEncryptionKey(key, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fs.Upload([]byte("test"), "encrypted.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	// existing plain files
+	r, err := fs.NewContentReader("image.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Close()
+
+	// copy of plain file should be encrypted
+	if err := fs.Copy("image.png", "image_copy.png"); err != nil {
+		t.Fatal(err)
+	}
+	if encrypted, _ := fs.IsEncrypted("image_copy.png"); !encrypted {
+		t.Fatal("Expected the copied file to be encrypted")
+	}
+
+	// decrypt only
+	fs2, err := filesystem.NewLocal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs2.Close()
+
+	if err := fs2.SetEncryptionKey(key, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fs2.Upload([]byte("test"), "plain.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if encrypted, _ := fs2.IsEncrypted("plain.txt"); encrypted {
+		t.Fatal("Expected the file to be stored as plain")
+	}
+
+	r2, err := fs2.NewContentReader("encrypted.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, _ := io.ReadAll(r2)
+	r2.Close()
+	if string(content) != "test" {
+		t.Fatalf("Expected the decrypted content %q, got %q", "test", content)
+	}
+
+	// missing key
+	fs3, err := filesystem.NewLocal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs3.Close()
+
+	if _, err := fs3.NewContentReader("encrypted.txt"); err == nil {
+		t.Fatal("Expected error for missing encryption key")
+	}
+
+	// wrong key
+	if err := fs3.SetEncryptionKey(security.RandomString(32), false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fs3.NewContentReader("encrypted.txt"); err == nil {
+		t.Fatal("Expected error for wrong encryption key")
+	}
+}
+
+func TestFileSystemEncryptionTampering(t *testing.T) {
+	dir := t.TempDir()
+
+	fs, err := filesystem.NewLocal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	if err := fs.SetEncryptionKey(security.RandomString(32), true); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fs.Upload(bytes.Repeat([]byte("a"), 100*1024), "test.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := os.ReadFile(dir + "/test.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// truncate to the first chunk
+	if err := os.WriteFile(dir+"/test.txt", raw[:64*1024+16], 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := fs.NewContentReader("test.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	if _, err := io.ReadAll(r); err == nil {
+		t.Fatal("Expected error for truncated content")
+	}
+}
+
+func TestFileSystemEncryptionServeAndThumb(t *testing.T) {
+	fs, err := filesystem.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	if err := fs.SetEncryptionKey(security.RandomString(32), true); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 50, 50))); err != nil {
+		t.Fatal(err)
+	}
+	content := buf.Bytes()
+
+	if err := fs.Upload(content, "image.png"); err != nil {
+		t.Fatal(err)
+	}
+
+	// full
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/", nil)
+	if err := fs.Serve(res, req, "image.png", "image.png"); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(res.Body.Bytes(), content) {
+		t.Fatal("Expected the served content to be decrypted")
+	}
+	if ct := res.Header().Get("Content-Type"); ct != "image/png" {
+		t.Fatalf("Expected Content-Type %q, got %q", "image/png", ct)
+	}
+
+	// range
+	res = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/", nil)
+	req.Header.Add("Range", "bytes=10-20")
+	if err := fs.Serve(res, req, "image.png", "image.png"); err != nil {
+		t.Fatal(err)
+	}
+	if res.Code != http.StatusPartialContent {
+		t.Fatalf("Expected StatusCode %d, got %d", http.StatusPartialContent, res.Code)
+	}
+	if !bytes.Equal(res.Body.Bytes(), content[10:21]) {
+		t.Fatalf("Expected range %v, got %v", content[10:21], res.Body.Bytes())
+	}
+
+	// thumb
+	if err := fs.CreateThumb("image.png", "thumb.png", "10x10"); err != nil {
+		t.Fatal(err)
+	}
+	if encrypted, _ := fs.IsEncrypted("thumb.png"); !encrypted {
+		t.Fatal("Expected the thumb to be encrypted")
+	}
+
+	r, err := fs.NewContentReader("thumb.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	thumb, err := png.Decode(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := thumb.Bounds(); b.Dx() != 10 || b.Dy() != 10 {
+		t.Fatalf("Expected 10x10 thumb, got %v", b)
+	}
+}
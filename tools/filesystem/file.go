@@ -79,6 +79,33 @@ func NewFileFromMultipart(mh *multipart.FileHeader) (*File, error) {
 	return f, nil
 }
 
+// NewFileFromObject creates a new File from an already stored object
+// (eg. a file uploaded directly to the storage with a presigned url).
+//
+// newFilesystem is used to initialize the filesystem where the object is
+// located every time the file is read (eg. [core.App.NewFilesystem]).
+func NewFileFromObject(newFilesystem func() (*System, error), fileKey string) (*File, error) {
+	fs, err := newFilesystem()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.Close()
+
+	attrs, err := fs.Attributes(fileKey)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &File{}
+
+	f.Reader = &ObjectReader{NewFilesystem: newFilesystem, Key: fileKey}
+	f.Size = attrs.Size
+	f.OriginalName = path.Base(fileKey)
+	f.Name = normalizeName(f.Reader, f.OriginalName)
+
+	return f, nil
+}
+
 // NewFileFromUrl creates a new File from the provided url by
 // downloading the resource and load it as BytesReader.
 //
@@ -166,6 +193,44 @@ func (r *bytesReadSeekCloser) Close() error {
 
 // -------------------------------------------------------------------
 
+var _ FileReader = (*ObjectReader)(nil)
+
+// ObjectReader defines a FileReader from an already stored filesystem object.
+type ObjectReader struct {
+	// NewFilesystem initializes the filesystem where the object is located.
+	NewFilesystem func() (*System, error)
+
+	Key string
+}
+
+// Open implements the [filesystem.FileReader] interface.
+func (r *ObjectReader) Open() (io.ReadSeekCloser, error) {
+	fs, err := r.NewFilesystem()
+	if err != nil {
+		return nil, err
+	}
+
+	br, err := fs.NewContentReader(r.Key)
+	if err != nil {
+		fs.Close()
+		return nil, err
+	}
+
+	return &objectReadSeekCloser{ContentReader: br, fs: fs}, nil
+}
+
+type objectReadSeekCloser struct {
+	ContentReader
+	fs *System
+}
+
+// Close implements the [io.ReadSeekCloser] interface.
+func (r *objectReadSeekCloser) Close() error {
+	return errors.Join(r.ContentReader.Close(), r.fs.Close())
+}
+
+// -------------------------------------------------------------------
+
 var extInvalidCharsRegex = regexp.MustCompile(`[^\w\.\*\-\+\=\#]+`)
 
 func normalizeName(fr FileReader, name string) string {
@@ -3,6 +3,7 @@ package filesystem_test
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -115,6 +116,63 @@ func TestNewFileFromMultipart(t *testing.T) {
 	}
 }
 
+func TestNewFileFromObject(t *testing.T) {
+	testDir := createTestDir(t)
+	defer os.RemoveAll(testDir)
+
+	newFilesystem := func() (*filesystem.System, error) {
+		return filesystem.NewLocal(testDir)
+	}
+
+	// missing object
+	_, err := filesystem.NewFileFromObject(newFilesystem, "missing")
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	// existing object
+	originalName := "image.png"
+	info, err := os.Stat(filepath.Join(testDir, originalName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	normalizedNamePattern := regexp.QuoteMeta("image_") + `\w{10}` + regexp.QuoteMeta(".png")
+	f, err := filesystem.NewFileFromObject(newFilesystem, originalName)
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+	if f.OriginalName != originalName {
+		t.Fatalf("Expected OriginalName %q, got %q", originalName, f.OriginalName)
+	}
+	if match, err := regexp.Match(normalizedNamePattern, []byte(f.Name)); !match {
+		t.Fatalf("Expected Name to match %v, got %q (%v)", normalizedNamePattern, f.Name, err)
+	}
+	if f.Size != info.Size() {
+		t.Fatalf("Expected Size %v, got %v", info.Size(), f.Size)
+	}
+	reader, ok := f.Reader.(*filesystem.ObjectReader)
+	if !ok {
+		t.Fatalf("Expected Reader to be ObjectReader, got %v", f.Reader)
+	}
+	if reader.Key != originalName {
+		t.Fatalf("Expected Reader key %q, got %q", originalName, reader.Key)
+	}
+
+	r, err := f.Reader.Open()
+	if err != nil {
+		t.Fatalf("Failed to open the object reader: %v", err)
+	}
+	defer r.Close()
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int64(len(content)) != info.Size() {
+		t.Fatalf("Expected %d read bytes, got %d", info.Size(), len(content))
+	}
+}
+
 func TestNewFileFromUrlTimeout(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/error" {
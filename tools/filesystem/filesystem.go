@@ -1,6 +1,7 @@
 package filesystem
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"image"
@@ -14,23 +15,40 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/list"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/tracing"
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/disintegration/imaging"
 	"github.com/gabriel-vasile/mimetype"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"gocloud.dev/blob"
 	"gocloud.dev/blob/fileblob"
+	"gocloud.dev/gcerrors"
 )
 
 var gcpIgnoreHeaders = []string{"Accept-Encoding"}
 
+// ErrSignedURLNotSupported is returned when the filesystem driver
+// doesn't support presigned urls (eg. the local filesystem).
+var ErrSignedURLNotSupported = errors.New("the filesystem doesn't support presigned urls")
+
 type System struct {
 	ctx    context.Context
 	bucket *blob.Bucket
+
+	encryptionKey  string
+	encryptUploads bool
+
+	dedupIndex DedupIndex
+
+	// sharedBucket indicates whether the bucket shouldn't be closed on Close()
+	sharedBucket bool
 }
 
 // NewS3 initializes an S3 filesystem instance.
@@ -106,13 +124,54 @@ func NewLocal(dirPath string) (*System, error) {
 	return &System{ctx: ctx, bucket: bucket}, nil
 }
 
+// NewFromBucket initializes a filesystem instance from an already
+// opened gocloud bucket, allowing to use any of the gocloud blob drivers
+// (eg. azureblob, gcsblob, memblob, etc.).
+//
+// If shared is true, the bucket is not closed on [System.Close] so that
+// it could be reused by multiple System instances (eg. a single memblob bucket).
+//
+// NB! Make sure to call `Close()` after you are done working with it.
+func NewFromBucket(bucket *blob.Bucket, shared bool) *System {
+	return &System{ctx: context.Background(), bucket: bucket, sharedBucket: shared}
+}
+
 // SetContext assigns the specified context to the current filesystem.
 func (s *System) SetContext(ctx context.Context) {
 	s.ctx = ctx
 }
 
+// traceOperation starts a new filesystem operation span as part of the
+// filesystem context trace (if any) and returns a function to end it.
+//
+// Example:
+//
+//	defer s.traceOperation("Upload", fileKey)(&err)
+func (s *System) traceOperation(operation string, fileKey string) func(errPtr *error) {
+	_, span := tracing.Start(
+		s.ctx,
+		"filesystem."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("file.key", fileKey)),
+	)
+	if !span.IsRecording() {
+		return func(errPtr *error) {}
+	}
+
+	return func(errPtr *error) {
+		if errPtr != nil {
+			tracing.RecordError(span, *errPtr)
+		}
+		span.End()
+	}
+}
+
 // Close releases any resources used for the related filesystem.
 func (s *System) Close() error {
+	if s.sharedBucket {
+		return nil
+	}
+
 	return s.bucket.Close()
 }
 
@@ -128,9 +187,14 @@ func (s *System) Attributes(fileKey string) (*blob.Attributes, error) {
 
 // GetFile returns a file content reader for the given fileKey.
 //
+// Note that the returned reader is for the raw stored content, aka.
+// encrypted files are not decrypted (see [System.NewContentReader]).
+//
 // NB! Make sure to call `Close()` after you are done working with it.
-func (s *System) GetFile(fileKey string) (*blob.Reader, error) {
-	br, err := s.bucket.NewReader(s.ctx, fileKey, nil)
+func (s *System) GetFile(fileKey string) (br *blob.Reader, err error) {
+	defer s.traceOperation("GetFile", fileKey)(&err)
+
+	br, err = s.bucket.NewReader(s.ctx, fileKey, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -141,10 +205,83 @@ func (s *System) GetFile(fileKey string) (*blob.Reader, error) {
 // Copy copies the file stored at srcKey to dstKey.
 //
 // If dstKey file already exists, it is overwritten.
-func (s *System) Copy(srcKey, dstKey string) error {
+//
+// If srcKey is a deduplicated file, only a new reference to its blob is created.
+//
+// If the uploads encryption is enabled and the srcKey file is not
+// encrypted, its content is encrypted while copying.
+func (s *System) Copy(srcKey, dstKey string) (err error) {
+	defer s.traceOperation("Copy", srcKey)(&err)
+
+	attrs, err := s.bucket.Attributes(s.ctx, srcKey)
+	if err != nil {
+		return err
+	}
+
+	// deduplicated file reference
+	if hash := attrs.Metadata[dedupBlobMetaKey]; hash != "" {
+		return s.copyBlobRef(hash, srcKey, dstKey, attrs)
+	}
+
+	if s.encryptUploads && attrs.Metadata[encryptionKeyMetaKey] == "" {
+		return s.copyEncrypted(srcKey, dstKey, attrs)
+	}
+
 	return s.bucket.Copy(s.ctx, dstKey, srcKey, nil)
 }
 
+func (s *System) copyEncrypted(srcKey, dstKey string, attrs *blob.Attributes) error {
+	r, err := s.bucket.NewReader(s.ctx, srcKey, nil)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	w, err := s.newWriter(dstKey, &blob.WriterOptions{
+		ContentType: attrs.ContentType,
+		Metadata:    attrs.Metadata,
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(w, r); err != nil {
+		w.Close()
+		return err
+	}
+
+	return w.Close()
+}
+
+// SignedUploadURL returns a presigned PUT url that allows uploading
+// a single object with the specified size and content type directly
+// to the fileKey location (without proxying it through the app).
+//
+// Returns [ErrSignedURLNotSupported] if the filesystem driver
+// doesn't support presigned urls.
+func (s *System) SignedUploadURL(fileKey string, size int64, contentType string, expiry time.Duration) (string, error) {
+	url, err := s.bucket.SignedURL(s.ctx, fileKey, &blob.SignedURLOptions{
+		Expiry: expiry,
+		Method: http.MethodPut,
+		BeforeSign: func(asFunc func(any) bool) error {
+			var input *s3.PutObjectInput
+			if asFunc(&input) {
+				input.ContentLength = aws.Int64(size)
+				input.ContentType = aws.String(contentType)
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		if gcerrors.Code(err) == gcerrors.Unimplemented {
+			return "", ErrSignedURLNotSupported
+		}
+		return "", err
+	}
+
+	return url, nil
+}
+
 // List returns a flat list with info for all files under the specified prefix.
 func (s *System) List(prefix string) ([]*blob.ListObject, error) {
 	files := []*blob.ListObject{}
@@ -168,12 +305,14 @@ func (s *System) List(prefix string) ([]*blob.ListObject, error) {
 }
 
 // Upload writes content into the fileKey location.
-func (s *System) Upload(content []byte, fileKey string) error {
+func (s *System) Upload(content []byte, fileKey string) (err error) {
+	defer s.traceOperation("Upload", fileKey)(&err)
+
 	opts := &blob.WriterOptions{
 		ContentType: mimetype.Detect(content).String(),
 	}
 
-	w, writerErr := s.bucket.NewWriter(s.ctx, fileKey, opts)
+	w, writerErr := s.newWriter(fileKey, opts)
 	if writerErr != nil {
 		return writerErr
 	}
@@ -186,8 +325,51 @@ func (s *System) Upload(content []byte, fileKey string) error {
 	return w.Close()
 }
 
+// mimetypeSniffLen is the number of the content leading bytes
+// used for the content type detection (same as the mimetype default).
+const mimetypeSniffLen = 3072
+
+// UploadStream uploads the content of the provided reader to the fileKey location
+// without loading it in memory.
+//
+// The upload is aborted (aka. the fileKey object is not created or
+// replaced) if reading from r fails.
+func (s *System) UploadStream(r io.Reader, fileKey string) (err error) {
+	defer s.traceOperation("UploadStream", fileKey)(&err)
+
+	br := bufio.NewReaderSize(r, mimetypeSniffLen)
+
+	// detect the content type from the first content bytes
+	head, err := br.Peek(mimetypeSniffLen)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return err
+	}
+
+	opts := &blob.WriterOptions{
+		ContentType: mimetype.Detect(head).String(),
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+
+	w, err := s.newContextWriter(ctx, fileKey, opts)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(w, br); err != nil {
+		cancel() // abort the write
+		w.Close()
+		return err
+	}
+
+	return w.Close()
+}
+
 // UploadFile uploads the provided multipart file to the fileKey location.
-func (s *System) UploadFile(file *File, fileKey string) error {
+func (s *System) UploadFile(file *File, fileKey string) (err error) {
+	defer s.traceOperation("UploadFile", fileKey)(&err)
+
 	f, err := file.Reader.Open()
 	if err != nil {
 		return err
@@ -215,12 +397,12 @@ func (s *System) UploadFile(file *File, fileKey string) error {
 		},
 	}
 
-	w, err := s.bucket.NewWriter(s.ctx, fileKey, opts)
+	w, err := s.newWriter(fileKey, opts)
 	if err != nil {
 		return err
 	}
 
-	if _, err := w.ReadFrom(f); err != nil {
+	if _, err := io.Copy(w, f); err != nil {
 		w.Close()
 		return err
 	}
@@ -229,7 +411,9 @@ func (s *System) UploadFile(file *File, fileKey string) error {
 }
 
 // UploadMultipart uploads the provided multipart file to the fileKey location.
-func (s *System) UploadMultipart(fh *multipart.FileHeader, fileKey string) error {
+func (s *System) UploadMultipart(fh *multipart.FileHeader, fileKey string) (err error) {
+	defer s.traceOperation("UploadMultipart", fileKey)(&err)
+
 	f, err := fh.Open()
 	if err != nil {
 		return err
@@ -257,12 +441,12 @@ func (s *System) UploadMultipart(fh *multipart.FileHeader, fileKey string) error
 		},
 	}
 
-	w, err := s.bucket.NewWriter(s.ctx, fileKey, opts)
+	w, err := s.newWriter(fileKey, opts)
 	if err != nil {
 		return err
 	}
 
-	if _, err := w.ReadFrom(f); err != nil {
+	if _, err := io.Copy(w, f); err != nil {
 		w.Close()
 		return err
 	}
@@ -271,7 +455,32 @@ func (s *System) UploadMultipart(fh *multipart.FileHeader, fileKey string) error
 }
 
 // Delete deletes stored file at fileKey location.
-func (s *System) Delete(fileKey string) error {
+//
+// For deduplicated files the referenced blob is deleted
+// only if fileKey was its last reference (see [System.SetDedupIndex]).
+func (s *System) Delete(fileKey string) (err error) {
+	defer s.traceOperation("Delete", fileKey)(&err)
+
+	deleteErr := s.bucket.Delete(s.ctx, fileKey)
+	if deleteErr != nil && gcerrors.Code(deleteErr) != gcerrors.NotFound {
+		return deleteErr
+	}
+
+	// release the blob reference even if the pointer object
+	// is missing to avoid keeping unreferenced blobs
+	released, err := s.releaseBlobRefs(fileKey)
+	if err != nil || released > 0 {
+		return err
+	}
+
+	return deleteErr
+}
+
+// deleteObject deletes the fileKey storage object
+// without releasing its blob reference (if any).
+func (s *System) deleteObject(fileKey string) (err error) {
+	defer s.traceOperation("Delete", fileKey)(&err)
+
 	return s.bucket.Delete(s.ctx, fileKey)
 }
 
@@ -287,6 +496,8 @@ func (s *System) DeletePrefix(prefix string) []error {
 	dirsMap := map[string]struct{}{}
 	dirsMap[prefix] = struct{}{}
 
+	deleted := []string{}
+
 	// delete all files with the prefix
 	// ---
 	iter := s.bucket.List(&blob.ListOptions{
@@ -301,12 +512,19 @@ func (s *System) DeletePrefix(prefix string) []error {
 			break
 		}
 
-		if err := s.Delete(obj.Key); err != nil {
+		if err := s.deleteObject(obj.Key); err != nil {
 			failed = append(failed, err)
 		} else {
+			deleted = append(deleted, obj.Key)
 			dirsMap[path.Dir(obj.Key)] = struct{}{}
 		}
 	}
+
+	// release the blob references of the deleted files in a batch
+	// (instead of checking each deleted file separately)
+	if _, err := s.releaseBlobRefs(deleted...); err != nil {
+		failed = append(failed, err)
+	}
 	// ---
 
 	// try to delete the empty remaining dir objects
@@ -326,7 +544,7 @@ func (s *System) DeletePrefix(prefix string) []error {
 	// delete dirs
 	for _, d := range dirs {
 		if d != "" {
-			s.Delete(d)
+			s.bucket.Delete(s.ctx, d)
 		}
 	}
 	// ---
@@ -360,8 +578,10 @@ const forceAttachmentParam = "download"
 //
 // If the `download` query parameter is used the file will be always served for
 // download no matter of its type (aka. with "Content-Disposition: attachment").
-func (s *System) Serve(res http.ResponseWriter, req *http.Request, fileKey string, name string) error {
-	br, readErr := s.bucket.NewReader(s.ctx, fileKey, nil)
+func (s *System) Serve(res http.ResponseWriter, req *http.Request, fileKey string, name string) (err error) {
+	defer s.traceOperation("Serve", fileKey)(&err)
+
+	br, readErr := s.NewContentReader(fileKey)
 	if readErr != nil {
 		return readErr
 	}
@@ -418,22 +638,16 @@ var ThumbSizeRegex = regexp.MustCompile(`^(\d+)x(\d+)(t|b|f)?$`)
 // - WxHt (eg. 300x100t) - resize and crop to WxH viewbox (from top)
 // - WxHb (eg. 300x100b) - resize and crop to WxH viewbox (from bottom)
 // - WxHf (eg. 300x100f) - fit inside a WxH viewbox (without cropping)
-func (s *System) CreateThumb(originalKey string, thumbKey, thumbSize string) error {
-	sizeParts := ThumbSizeRegex.FindStringSubmatch(thumbSize)
-	if len(sizeParts) != 4 {
-		return errors.New("thumb size must be in WxH, WxHt, WxHb or WxHf format")
-	}
+func (s *System) CreateThumb(originalKey string, thumbKey, thumbSize string) (err error) {
+	defer s.traceOperation("CreateThumb", thumbKey)(&err)
 
-	width, _ := strconv.Atoi(sizeParts[1])
-	height, _ := strconv.Atoi(sizeParts[2])
-	resizeType := sizeParts[3]
-
-	if width == 0 && height == 0 {
-		return errors.New("thumb width and height cannot be zero at the same time")
+	width, height, resizeType, err := parseThumbSize(thumbSize)
+	if err != nil {
+		return err
 	}
 
 	// fetch the original
-	r, readErr := s.bucket.NewReader(s.ctx, originalKey, nil)
+	r, readErr := s.NewContentReader(originalKey)
 	if readErr != nil {
 		return readErr
 	}
@@ -446,34 +660,14 @@ func (s *System) CreateThumb(originalKey string, thumbKey, thumbSize string) err
 		return decodeErr
 	}
 
-	var thumbImg *image.NRGBA
-
-	if width == 0 || height == 0 {
-		// force resize preserving aspect ratio
-		thumbImg = imaging.Resize(img, width, height, imaging.Linear)
-	} else {
-		switch resizeType {
-		case "f":
-			// fit
-			thumbImg = imaging.Fit(img, width, height, imaging.Linear)
-		case "t":
-			// fill and crop from top
-			thumbImg = imaging.Fill(img, width, height, imaging.Top, imaging.Linear)
-		case "b":
-			// fill and crop from bottom
-			thumbImg = imaging.Fill(img, width, height, imaging.Bottom, imaging.Linear)
-		default:
-			// fill and crop from center
-			thumbImg = imaging.Fill(img, width, height, imaging.Center, imaging.Linear)
-		}
-	}
+	thumbImg := resizeImage(img, width, height, resizeType, imaging.Linear)
 
 	opts := &blob.WriterOptions{
 		ContentType: r.ContentType(),
 	}
 
 	// open a thumb storage writer (aka. prepare for upload)
-	w, writerErr := s.bucket.NewWriter(s.ctx, thumbKey, opts)
+	w, writerErr := s.newWriter(thumbKey, opts)
 	if writerErr != nil {
 		return writerErr
 	}
@@ -494,3 +688,45 @@ func (s *System) CreateThumb(originalKey string, thumbKey, thumbSize string) err
 	// check for close errors to ensure that the thumb was really saved
 	return w.Close()
 }
+
+// parseThumbSize parses and validates the provided thumb size string
+// (see [System.CreateThumb] for the supported formats).
+func parseThumbSize(thumbSize string) (width int, height int, resizeType string, err error) {
+	sizeParts := ThumbSizeRegex.FindStringSubmatch(thumbSize)
+	if len(sizeParts) != 4 {
+		return 0, 0, "", errors.New("thumb size must be in WxH, WxHt, WxHb or WxHf format")
+	}
+
+	width, _ = strconv.Atoi(sizeParts[1])
+	height, _ = strconv.Atoi(sizeParts[2])
+	resizeType = sizeParts[3]
+
+	if width == 0 && height == 0 {
+		return 0, 0, "", errors.New("thumb width and height cannot be zero at the same time")
+	}
+
+	return width, height, resizeType, nil
+}
+
+// resizeImage resizes img to the specified thumb dimensions and resize type.
+func resizeImage(img image.Image, width int, height int, resizeType string, filter imaging.ResampleFilter) *image.NRGBA {
+	if width == 0 || height == 0 {
+		// force resize preserving aspect ratio
+		return imaging.Resize(img, width, height, filter)
+	}
+
+	switch resizeType {
+	case "f":
+		// fit
+		return imaging.Fit(img, width, height, filter)
+	case "t":
+		// fill and crop from top
+		return imaging.Fill(img, width, height, imaging.Top, filter)
+	case "b":
+		// fill and crop from bottom
+		return imaging.Fill(img, width, height, imaging.Bottom, filter)
+	default:
+		// fill and crop from center
+		return imaging.Fill(img, width, height, imaging.Center, filter)
+	}
+}
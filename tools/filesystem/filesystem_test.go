@@ -2,8 +2,11 @@ package filesystem_test
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"image"
 	"image/png"
+	"io"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
@@ -11,8 +14,13 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"testing/iotest"
+	"time"
 
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/filesystem"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/tracing"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func TestFileSystemExists(t *testing.T) {
@@ -241,6 +249,89 @@ func TestFileSystemUpload(t *testing.T) {
 	}
 }
 
+func TestFileSystemUploadStream(t *testing.T) {
+	dir := createTestDir(t)
+	defer os.RemoveAll(dir)
+
+	fs, err := filesystem.NewLocal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	// successful upload
+	fileKey := "newdir/stream.txt"
+	if err := fs.UploadStream(strings.NewReader("demo"), fileKey); err != nil {
+		t.Fatal(err)
+	}
+
+	attrs, err := fs.Attributes(fileKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attrs.Size != 4 || !strings.HasPrefix(attrs.ContentType, "text/plain") {
+		t.Fatalf("Unexpected uploaded file attributes (size %d, content type %q)", attrs.Size, attrs.ContentType)
+	}
+
+	// aborted upload
+	failedKey := "newdir/failed.txt"
+	readErr := errors.New("read error")
+	if err := fs.UploadStream(io.MultiReader(strings.NewReader("demo"), iotest.ErrReader(readErr)), failedKey); !errors.Is(err, readErr) {
+		t.Fatalf("Expected the read error, got %v", err)
+	}
+
+	if exists, _ := fs.Exists(failedKey); exists {
+		t.Fatalf("Expected %s to not exist", failedKey)
+	}
+}
+
+func TestFileSystemTracing(t *testing.T) {
+	dir := createTestDir(t)
+	defer os.RemoveAll(dir)
+
+	fs, err := filesystem.NewLocal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tracer := tracing.NewTracer("test", exporter, 1)
+	defer tracer.Shutdown(context.Background())
+
+	ctx, root := tracer.Start(context.Background(), "root")
+	fs.SetContext(ctx)
+
+	if err := fs.Upload([]byte("demo"), "test/new.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := fs.GetFile("test/missing.txt"); err == nil {
+		t.Fatal("Expected missing file error")
+	}
+
+	root.End()
+
+	if err := tracer.ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("Expected 3 spans, got %d", len(spans))
+	}
+
+	upload, getFile := spans[0], spans[1]
+
+	if upload.Name != "filesystem.Upload" || upload.Status.Code == codes.Error || upload.Parent.SpanID() != root.SpanContext().SpanID() {
+		t.Fatalf("Unexpected upload span %+v", upload)
+	}
+
+	if getFile.Name != "filesystem.GetFile" || getFile.Status.Code != codes.Error {
+		t.Fatalf("Unexpected get file span %+v", getFile)
+	}
+}
+
 func TestFileSystemServe(t *testing.T) {
 	dir := createTestDir(t)
 	defer os.RemoveAll(dir)
@@ -461,6 +552,22 @@ func TestFileSystemCopy(t *testing.T) {
 	}
 }
 
+func TestFileSystemSignedUploadURL(t *testing.T) {
+	dir := createTestDir(t)
+	defer os.RemoveAll(dir)
+
+	fs, err := filesystem.NewLocal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	_, err = fs.SignedUploadURL("new.png", 10, "image/png", time.Minute)
+	if !errors.Is(err, filesystem.ErrSignedURLNotSupported) {
+		t.Fatalf("Expected ErrSignedURLNotSupported, got %v", err)
+	}
+}
+
 func TestFileSystemList(t *testing.T) {
 	dir := createTestDir(t)
 	defer os.RemoveAll(dir)
@@ -632,6 +739,149 @@ func TestFileSystemCreateThumb(t *testing.T) {
 	}
 }
 
+func TestImageTransformValidate(t *testing.T) {
+	scenarios := []struct {
+		transform   filesystem.ImageTransform
+		expectError bool
+	}{
+		{filesystem.ImageTransform{}, false},
+		{filesystem.ImageTransform{Size: "invalid"}, true},
+		{filesystem.ImageTransform{Format: "missing"}, true},
+		{filesystem.ImageTransform{Quality: 101}, true},
+		{filesystem.ImageTransform{Rotate: 45}, true},
+		{filesystem.ImageTransform{Blur: 51}, true},
+		{filesystem.ImageTransform{Blur: 1.3}, true},
+		{filesystem.ImageTransform{Blur: 1.5}, false},
+		{filesystem.ImageTransform{Dpr: 5, Size: "100x100"}, true},
+		{filesystem.ImageTransform{Dpr: 2}, true},
+		{filesystem.ImageTransform{Size: "100x100", Format: "jpeg", Quality: 80, Rotate: 90, Blur: 2, Dpr: 2}, false},
+	}
+
+	for i, s := range scenarios {
+		err := s.transform.Validate()
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+		}
+	}
+}
+
+func TestQuantizeBlur(t *testing.T) {
+	scenarios := []struct {
+		blur     float64
+		expected float64
+	}{
+		{0, 0},
+		{0.2, 0},
+		{0.3, 0.5},
+		{1.3, 1.5},
+		{1.7, 1.5},
+		{1.8, 2},
+		{50, 50},
+	}
+
+	for _, s := range scenarios {
+		if v := filesystem.QuantizeBlur(s.blur); v != s.expected {
+			t.Errorf("Expected %v for blur %v, got %v", s.expected, s.blur, v)
+		}
+	}
+}
+
+func TestImageTransformKey(t *testing.T) {
+	scenarios := []struct {
+		transform filesystem.ImageTransform
+		expected  string
+	}{
+		{filesystem.ImageTransform{}, ""},
+		{filesystem.ImageTransform{Size: "100x50t"}, "100x50t"},
+		{filesystem.ImageTransform{Format: "jpeg"}, ""},
+		{filesystem.ImageTransform{Size: "100x100", Format: "jpeg", Quality: 80, Rotate: 90, Blur: 2.5, Dpr: 2}, "100x100_q80_r90_b2.5_2x"},
+		{filesystem.ImageTransform{Dpr: 1, Blur: 1}, "b1"},
+	}
+
+	for i, s := range scenarios {
+		if key := s.transform.Key(); key != s.expected {
+			t.Errorf("(%d) Expected key %q, got %q", i, s.expected, key)
+		}
+	}
+}
+
+func TestRegisterImageFormat(t *testing.T) {
+	if filesystem.HasImageFormat("test_format") {
+		t.Fatal("Expected test_format to be missing")
+	}
+
+	filesystem.RegisterImageFormat("Test_Format", "image/x-test", func(w io.Writer, img image.Image, quality int) error {
+		_, err := w.Write([]byte("test"))
+		return err
+	})
+
+	if !filesystem.HasImageFormat("test_format") {
+		t.Fatal("Expected test_format to be registered")
+	}
+}
+
+func TestFileSystemCreateImageTransform(t *testing.T) {
+	dir := createTestDir(t)
+	defer os.RemoveAll(dir)
+
+	fs, err := filesystem.NewLocal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	scenarios := []struct {
+		file                string
+		transformed         string
+		transform           filesystem.ImageTransform
+		expectError         bool
+		expectedContentType string
+	}{
+		// missing
+		{"missing.txt", "transform_missing", filesystem.ImageTransform{Size: "10x10"}, true, ""},
+		// non-image existing file
+		{"test/sub1.txt", "transform_sub1", filesystem.ImageTransform{Size: "10x10"}, true, ""},
+		// invalid transform
+		{"image.png", "transform_invalid", filesystem.ImageTransform{Rotate: 1}, true, ""},
+		// preserved original format
+		{"image.png", "transform_png", filesystem.ImageTransform{Size: "10x10", Rotate: 90, Blur: 1, Dpr: 2}, false, "image/png"},
+		// different format
+		{"image.png", "transform_jpeg", filesystem.ImageTransform{Format: "jpeg", Quality: 50}, false, "image/jpeg"},
+		// builtin lossless webp
+		{"image.png", "transform_webp", filesystem.ImageTransform{Size: "10x10", Format: "webp", Quality: 50}, false, "image/webp"},
+		// webp original with preserved format
+		{"transform_webp", "transform_webp_rotated", filesystem.ImageTransform{Rotate: 180}, false, "image/webp"},
+		// format without registered encoder
+		{"image.png", "transform_avif", filesystem.ImageTransform{Format: "avif"}, true, ""},
+	}
+
+	for i, s := range scenarios {
+		err := fs.CreateImageTransform(s.file, s.transformed, s.transform)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr to be %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if s.expectError {
+			continue
+		}
+
+		attrs, err := fs.Attributes(s.transformed)
+		if err != nil {
+			t.Errorf("(%d) Couldn't find %q transformed image: %v", i, s.transformed, err)
+			continue
+		}
+
+		if attrs.ContentType != s.expectedContentType {
+			t.Errorf("(%d) Expected content type %q, got %q", i, s.expectedContentType, attrs.ContentType)
+		}
+	}
+}
+
 // ---
 
 func createTestDir(t *testing.T) string {
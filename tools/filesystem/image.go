@@ -0,0 +1,287 @@
+package filesystem
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/HugoSmits86/nativewebp"
+	"github.com/disintegration/imaging"
+	"gocloud.dev/blob"
+
+	// register the webp decoder so that webp originals could be transformed
+	_ "golang.org/x/image/webp"
+)
+
+// BlurStep is the precision of the [ImageTransform] blur sigma.
+//
+// The blur is part of the transformed image cache key and
+// therefore its value is limited to a multiple of the step.
+const BlurStep = 0.5
+
+// QuantizeBlur rounds the provided blur sigma to the nearest [BlurStep] multiple.
+func QuantizeBlur(blur float64) float64 {
+	return math.Round(blur/BlurStep) * BlurStep
+}
+
+// ImageEncoder defines a function that encodes img into w.
+//
+// quality is in the 1-100 range (0 means the encoder default).
+type ImageEncoder func(w io.Writer, img image.Image, quality int) error
+
+type imageFormat struct {
+	contentType string
+	encoder     ImageEncoder
+}
+
+var imageFormatsMux sync.RWMutex
+
+var imageFormats = map[string]imageFormat{
+	"jpeg": {
+		contentType: "image/jpeg",
+		encoder: func(w io.Writer, img image.Image, quality int) error {
+			if quality <= 0 {
+				quality = jpeg.DefaultQuality
+			}
+			return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+		},
+	},
+	"png": {
+		contentType: "image/png",
+		encoder: func(w io.Writer, img image.Image, quality int) error {
+			return png.Encode(w, img)
+		},
+	},
+	"gif": {
+		contentType: "image/gif",
+		encoder: func(w io.Writer, img image.Image, quality int) error {
+			return gif.Encode(w, img, nil)
+		},
+	},
+	"webp": {
+		contentType: "image/webp",
+		// note: the builtin webp encoder is lossless and ignores the quality
+		encoder: func(w io.Writer, img image.Image, quality int) error {
+			return nativewebp.Encode(w, img, nil)
+		},
+	},
+}
+
+// RegisterImageFormat registers a new (or replaces an existing)
+// image transformation output format encoder.
+//
+// The builtin output formats are "jpeg", "png", "gif" and "webp"
+// (the builtin webp encoder is lossless and ignores the quality option).
+//
+// There is no builtin "avif" encoder and the format is rejected as
+// unsupported unless an encoder from a third party package is registered
+// (the same could be used to replace the builtin lossless webp encoder), for example:
+//
+//	filesystem.RegisterImageFormat("avif", "image/avif", func(w io.Writer, img image.Image, quality int) error {
+//		return avif.Encode(w, img, avif.Options{Quality: quality})
+//	})
+func RegisterImageFormat(format string, contentType string, encoder ImageEncoder) {
+	imageFormatsMux.Lock()
+	defer imageFormatsMux.Unlock()
+
+	imageFormats[strings.ToLower(format)] = imageFormat{
+		contentType: contentType,
+		encoder:     encoder,
+	}
+}
+
+// HasImageFormat checks whether the specified image output format has a registered encoder.
+func HasImageFormat(format string) bool {
+	imageFormatsMux.RLock()
+	defer imageFormatsMux.RUnlock()
+
+	_, ok := imageFormats[strings.ToLower(format)]
+
+	return ok
+}
+
+func findImageFormat(format string) (imageFormat, bool) {
+	imageFormatsMux.RLock()
+	defer imageFormatsMux.RUnlock()
+
+	f, ok := imageFormats[strings.ToLower(format)]
+
+	return f, ok
+}
+
+// ImageTransform defines the on-the-fly image transformation options.
+type ImageTransform struct {
+	// Size is an optional thumb size (see [System.CreateThumb] for the supported formats).
+	Size string
+
+	// Format is the output format (eg. "jpeg", "png", "webp").
+	// If not set, the original image format is preserved.
+	Format string
+
+	// Quality is the optional output encoding quality in the 1-100 range.
+	Quality int
+
+	// Rotate is the optional clockwise rotation angle (0, 90, 180 or 270).
+	Rotate int
+
+	// Blur is the optional gaussian blur sigma (a multiple of [BlurStep]).
+	Blur float64
+
+	// Dpr is the optional device pixel ratio multiplier of the thumb size.
+	Dpr int
+}
+
+// IsZero checks whether the transformation has no options set.
+func (t ImageTransform) IsZero() bool {
+	return t == ImageTransform{}
+}
+
+// Key returns a short unique name representation of the transformation options
+// (eg. "100x100_q80_r90_b2_2x") that could be used as a cache key.
+//
+// The output format is not part of the key since it is expected to be
+// part of the transformed file extension.
+func (t ImageTransform) Key() string {
+	parts := []string{}
+
+	if t.Size != "" {
+		parts = append(parts, t.Size)
+	}
+	if t.Quality > 0 {
+		parts = append(parts, "q"+strconv.Itoa(t.Quality))
+	}
+	if t.Rotate > 0 {
+		parts = append(parts, "r"+strconv.Itoa(t.Rotate))
+	}
+	if t.Blur > 0 {
+		parts = append(parts, "b"+strconv.FormatFloat(t.Blur, 'f', -1, 64))
+	}
+	if t.Dpr > 1 {
+		parts = append(parts, strconv.Itoa(t.Dpr)+"x")
+	}
+
+	return strings.Join(parts, "_")
+}
+
+// Validate checks whether the transformation options are valid.
+func (t ImageTransform) Validate() error {
+	if t.Size != "" {
+		if _, _, _, err := parseThumbSize(t.Size); err != nil {
+			return err
+		}
+	}
+
+	if t.Format != "" && !HasImageFormat(t.Format) {
+		return fmt.Errorf("unsupported image format %q", t.Format)
+	}
+
+	if t.Quality < 0 || t.Quality > 100 {
+		return errors.New("quality must be in the 1-100 range")
+	}
+
+	if t.Rotate != 0 && t.Rotate != 90 && t.Rotate != 180 && t.Rotate != 270 {
+		return errors.New("rotate must be 0, 90, 180 or 270")
+	}
+
+	if t.Blur < 0 || t.Blur > 50 {
+		return errors.New("blur must be in the 0-50 range")
+	}
+
+	if QuantizeBlur(t.Blur) != t.Blur {
+		return fmt.Errorf("blur must be a multiple of %v", BlurStep)
+	}
+
+	if t.Dpr < 0 || t.Dpr > 4 {
+		return errors.New("dpr must be in the 1-4 range")
+	}
+
+	if t.Dpr > 1 && t.Size == "" {
+		return errors.New("dpr requires a thumb size")
+	}
+
+	return nil
+}
+
+// CreateImageTransform creates a new transformed image for the file
+// at originalKey location and stores it at transformedKey location.
+//
+// The original image is always auto oriented based on its EXIF data.
+func (s *System) CreateImageTransform(originalKey string, transformedKey string, transform ImageTransform) error {
+	if err := transform.Validate(); err != nil {
+		return err
+	}
+
+	// fetch the original
+	r, readErr := s.NewContentReader(originalKey)
+	if readErr != nil {
+		return readErr
+	}
+	defer r.Close()
+
+	// (note: only the first frame for animated image formats)
+	img, decodeErr := imaging.Decode(r, imaging.AutoOrientation(true))
+	if decodeErr != nil {
+		return decodeErr
+	}
+
+	var result image.Image = img
+
+	if transform.Size != "" {
+		width, height, resizeType, _ := parseThumbSize(transform.Size)
+		if transform.Dpr > 1 {
+			width *= transform.Dpr
+			height *= transform.Dpr
+		}
+		result = resizeImage(result, width, height, resizeType, imaging.Lanczos)
+	}
+
+	switch transform.Rotate {
+	case 90:
+		result = imaging.Rotate270(result) // imaging rotates counter-clockwise
+	case 180:
+		result = imaging.Rotate180(result)
+	case 270:
+		result = imaging.Rotate90(result)
+	}
+
+	if transform.Blur > 0 {
+		result = imaging.Blur(result, transform.Blur)
+	}
+
+	// resolve the output format
+	// (fallbacks to the original format and then to png)
+	formatName := transform.Format
+	if formatName == "" {
+		formatName = strings.TrimPrefix(r.ContentType(), "image/")
+		if formatName == "jpg" {
+			formatName = "jpeg"
+		}
+	}
+	format, ok := findImageFormat(formatName)
+	if !ok {
+		format, _ = findImageFormat("png")
+	}
+
+	w, writerErr := s.newWriter(transformedKey, &blob.WriterOptions{
+		ContentType: format.contentType,
+	})
+	if writerErr != nil {
+		return writerErr
+	}
+
+	if err := format.encoder(w, result, transform.Quality); err != nil {
+		w.Close()
+		return err
+	}
+
+	// check for close errors to ensure that the image was really saved
+	return w.Close()
+}
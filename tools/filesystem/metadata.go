@@ -0,0 +1,230 @@
+package filesystem
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"image"
+	"io"
+	"strings"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+// Metadata defines the common file metadata extracted at upload time.
+type Metadata struct {
+	// Size is the file content size in bytes.
+	Size int64 `json:"size"`
+
+	// MimeType is the detected file content MIME type.
+	MimeType string `json:"mimeType"`
+
+	// Width and Height are the image dimensions in pixels
+	// (zero for non-image or unsupported image files).
+	Width  int `json:"width"`
+	Height int `json:"height"`
+
+	// Duration is the audio/video duration in seconds
+	// (zero for non-media or unsupported media files).
+	Duration float64 `json:"duration"`
+
+	// Checksum is the hex encoded sha256 checksum of the file content.
+	Checksum string `json:"checksum"`
+}
+
+// ExtractMetadata reads the provided file content and extracts its metadata.
+//
+// The image dimensions are resolved for all registered image decoders
+// (jpeg, png, gif, bmp, tiff) and the media duration is resolved
+// for the mp4/mov/m4a and wav files.
+func ExtractMetadata(file *File) (*Metadata, error) {
+	if file == nil || file.Reader == nil {
+		return nil, errors.New("missing file reader")
+	}
+
+	f, err := file.Reader.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &Metadata{
+		Size:     size,
+		Checksum: hex.EncodeToString(h.Sum(nil)),
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	mt, err := mimetype.DetectReader(f)
+	if err != nil {
+		return nil, err
+	}
+	meta.MimeType = mt.String()
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case strings.HasPrefix(meta.MimeType, "image/"):
+		// ignore the decode error since not all image formats are supported
+		if config, _, err := image.DecodeConfig(f); err == nil {
+			meta.Width = config.Width
+			meta.Height = config.Height
+		}
+	case mt.Is("audio/wav"):
+		meta.Duration = wavDuration(f)
+	case strings.HasPrefix(meta.MimeType, "video/") || strings.HasPrefix(meta.MimeType, "audio/"):
+		meta.Duration = isoMediaDuration(f, size)
+	}
+
+	return meta, nil
+}
+
+// isoMediaDuration returns the duration (in seconds) of an ISO base media
+// file (mp4, mov, m4a, etc.) from its "moov/mvhd" box.
+//
+// Returns 0 if the box is missing or malformed.
+func isoMediaDuration(r io.ReadSeeker, size int64) float64 {
+	var offset int64
+
+	end := size
+
+	for offset+8 <= end {
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return 0
+		}
+
+		header := make([]byte, 8)
+		if _, err := io.ReadFull(r, header); err != nil {
+			return 0
+		}
+
+		boxSize := int64(binary.BigEndian.Uint32(header[:4]))
+		boxType := string(header[4:])
+		headerSize := int64(8)
+
+		switch boxSize {
+		case 0: // extends to the end of the file
+			boxSize = end - offset
+		case 1: // 64-bit large size
+			largeSize := make([]byte, 8)
+			if _, err := io.ReadFull(r, largeSize); err != nil {
+				return 0
+			}
+			boxSize = int64(binary.BigEndian.Uint64(largeSize))
+			headerSize = 16
+		}
+
+		if boxSize < headerSize {
+			return 0
+		}
+
+		switch boxType {
+		case "moov":
+			// descend into the container box
+			end = offset + boxSize
+			offset += headerSize
+			continue
+		case "mvhd":
+			return mvhdDuration(r)
+		}
+
+		offset += boxSize
+	}
+
+	return 0
+}
+
+// mvhdDuration parses the content of a "mvhd" box
+// (the reader is expected to be right after the box header).
+func mvhdDuration(r io.Reader) float64 {
+	version := make([]byte, 4) // version + flags
+	if _, err := io.ReadFull(r, version); err != nil {
+		return 0
+	}
+
+	var timescale uint32
+	var duration uint64
+
+	if version[0] == 1 {
+		data := make([]byte, 28) // creation(8) + modification(8) + timescale(4) + duration(8)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return 0
+		}
+		timescale = binary.BigEndian.Uint32(data[16:20])
+		duration = binary.BigEndian.Uint64(data[20:28])
+	} else {
+		data := make([]byte, 16) // creation(4) + modification(4) + timescale(4) + duration(4)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return 0
+		}
+		timescale = binary.BigEndian.Uint32(data[8:12])
+		duration = uint64(binary.BigEndian.Uint32(data[12:16]))
+	}
+
+	if timescale == 0 {
+		return 0
+	}
+
+	return float64(duration) / float64(timescale)
+}
+
+// wavDuration returns the duration (in seconds) of a RIFF WAVE file
+// calculated from its "fmt " byte rate and "data" chunk size.
+//
+// Returns 0 if the chunks are missing or malformed.
+func wavDuration(r io.Reader) float64 {
+	header := make([]byte, 12)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0
+	}
+
+	if !bytes.Equal(header[:4], []byte("RIFF")) || !bytes.Equal(header[8:], []byte("WAVE")) {
+		return 0
+	}
+
+	var byteRate uint32
+
+	for {
+		chunk := make([]byte, 8)
+		if _, err := io.ReadFull(r, chunk); err != nil {
+			return 0
+		}
+
+		chunkSize := binary.LittleEndian.Uint32(chunk[4:])
+
+		switch string(chunk[:4]) {
+		case "fmt ":
+			if chunkSize < 16 || chunkSize > 1024 {
+				return 0
+			}
+			data := make([]byte, chunkSize)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return 0
+			}
+			byteRate = binary.LittleEndian.Uint32(data[8:12])
+		case "data":
+			if byteRate == 0 {
+				return 0
+			}
+			return float64(chunkSize) / float64(byteRate)
+		default:
+			// the chunks are word aligned
+			if _, err := io.CopyN(io.Discard, r, int64(chunkSize+chunkSize%2)); err != nil {
+				return 0
+			}
+		}
+	}
+}
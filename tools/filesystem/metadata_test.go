@@ -0,0 +1,135 @@
+package filesystem_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/filesystem"
+)
+
+func TestExtractMetadata(t *testing.T) {
+	var pngBuf bytes.Buffer
+	if err := png.Encode(&pngBuf, image.NewRGBA(image.Rect(0, 0, 30, 20))); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name           string
+		content        []byte
+		expectMimeType string
+		expectWidth    int
+		expectHeight   int
+		expectDuration float64
+	}{
+		{"text", []byte("test"), "text/plain; charset=utf-8", 0, 0, 0},
+		{"png", pngBuf.Bytes(), "image/png", 30, 20, 0},
+		{"wav", testWav(2, 8000), "audio/wav", 0, 0, 2},
+		{"mp4 v0", testMp4(0, 1000, 3500), "video/mp4", 0, 0, 3.5},
+		{"mp4 v1", testMp4(1, 600, 6000), "video/mp4", 0, 0, 10},
+		{"mp4 zero timescale", testMp4(0, 0, 3500), "video/mp4", 0, 0, 0},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			file, err := filesystem.NewFileFromBytes(s.content, "test")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			meta, err := filesystem.ExtractMetadata(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checksum := sha256.Sum256(s.content)
+
+			if meta.Size != int64(len(s.content)) {
+				t.Fatalf("Expected size %d, got %d", len(s.content), meta.Size)
+			}
+
+			if meta.Checksum != hex.EncodeToString(checksum[:]) {
+				t.Fatalf("Expected checksum %q, got %q", hex.EncodeToString(checksum[:]), meta.Checksum)
+			}
+
+			if meta.MimeType != s.expectMimeType {
+				t.Fatalf("Expected mime type %q, got %q", s.expectMimeType, meta.MimeType)
+			}
+
+			if meta.Width != s.expectWidth || meta.Height != s.expectHeight {
+				t.Fatalf("Expected %dx%d dimensions, got %dx%d", s.expectWidth, s.expectHeight, meta.Width, meta.Height)
+			}
+
+			if meta.Duration != s.expectDuration {
+				t.Fatalf("Expected duration %v, got %v", s.expectDuration, meta.Duration)
+			}
+		})
+	}
+
+	if _, err := filesystem.ExtractMetadata(nil); err == nil {
+		t.Fatal("Expected error for nil file")
+	}
+}
+
+// testWav generates a minimal 8-bit mono RIFF WAVE file content.
+func testWav(seconds int, sampleRate uint32) []byte {
+	data := make([]byte, int(sampleRate)*seconds)
+
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(36+len(data)))
+	buf.WriteString("WAVE")
+	buf.WriteString("fmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))
+	binary.Write(&buf, binary.LittleEndian, uint16(1))  // PCM
+	binary.Write(&buf, binary.LittleEndian, uint16(1))  // channels
+	binary.Write(&buf, binary.LittleEndian, sampleRate) // sample rate
+	binary.Write(&buf, binary.LittleEndian, sampleRate) // byte rate
+	binary.Write(&buf, binary.LittleEndian, uint16(1))  // block align
+	binary.Write(&buf, binary.LittleEndian, uint16(8))  // bits per sample
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(len(data)))
+	buf.Write(data)
+
+	return buf.Bytes()
+}
+
+// testMp4 generates a minimal ISO base media file content with a "moov/mvhd" box.
+func testMp4(version byte, timescale uint32, duration uint32) []byte {
+	box := func(boxType string, content []byte) []byte {
+		var buf bytes.Buffer
+		binary.Write(&buf, binary.BigEndian, uint32(8+len(content)))
+		buf.WriteString(boxType)
+		buf.Write(content)
+		return buf.Bytes()
+	}
+
+	var mvhd bytes.Buffer
+	mvhd.Write([]byte{version, 0, 0, 0})
+	if version == 1 {
+		mvhd.Write(make([]byte, 16)) // creation + modification
+		binary.Write(&mvhd, binary.BigEndian, timescale)
+		binary.Write(&mvhd, binary.BigEndian, uint64(duration))
+	} else {
+		mvhd.Write(make([]byte, 8)) // creation + modification
+		binary.Write(&mvhd, binary.BigEndian, timescale)
+		binary.Write(&mvhd, binary.BigEndian, duration)
+	}
+	mvhd.Write(make([]byte, 80)) // the rest of the box fields
+
+	var ftyp bytes.Buffer
+	ftyp.WriteString("isom")
+	binary.Write(&ftyp, binary.BigEndian, uint32(512))
+	ftyp.WriteString("isomiso2mp41")
+
+	var result []byte
+	result = append(result, box("ftyp", ftyp.Bytes())...)
+	result = append(result, box("free", make([]byte, 10))...)
+	result = append(result, box("moov", box("mvhd", mvhd.Bytes()))...)
+
+	return result
+}
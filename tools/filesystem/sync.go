@@ -0,0 +1,197 @@
+package filesystem
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sync"
+
+	"gocloud.dev/blob"
+)
+
+// SyncOptions defines the [Sync] options.
+type SyncOptions struct {
+	// Prefix limits the synced files only to the ones starting with the specified key prefix.
+	Prefix string
+
+	// Workers is the max number of the concurrently copied files (default to 1).
+	Workers int
+
+	// Verify enables the sha256 checksum verification of the copied
+	// files and of the already existing destination files.
+	Verify bool
+
+	// OnFile is an optional callback that is invoked after each processed file.
+	OnFile func(key string, skipped bool, err error)
+}
+
+// SyncResult defines the [Sync] processed files summary.
+type SyncResult struct {
+	Total   int
+	Copied  int
+	Skipped int
+	Failed  []string
+}
+
+// Sync copies all src files (including their content type and metadata)
+// to dst preserving their keys.
+//
+// The destination files with the same size (and checksum if options.Verify is set)
+// are skipped, aka. an interrupted sync could be resumed by just rerunning it.
+//
+// The sync stops on ctx cancellation and returns an error if at least
+// one of the files failed to be copied.
+func Sync(ctx context.Context, src *System, dst *System, options SyncOptions) (*SyncResult, error) {
+	files, err := src.List(options.Prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	workers := options.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	result := &SyncResult{}
+
+	var mux sync.Mutex
+	var wg sync.WaitGroup
+
+	keys := make(chan *blob.ListObject)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for obj := range keys {
+				skipped, err := syncFile(ctx, src, dst, obj, options.Verify)
+
+				mux.Lock()
+				result.Total++
+				switch {
+				case err != nil:
+					result.Failed = append(result.Failed, obj.Key)
+				case skipped:
+					result.Skipped++
+				default:
+					result.Copied++
+				}
+				mux.Unlock()
+
+				if options.OnFile != nil {
+					options.OnFile(obj.Key, skipped, err)
+				}
+			}
+		}()
+	}
+
+	for _, obj := range files {
+		if obj.IsDir {
+			continue
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+
+		keys <- obj
+	}
+	close(keys)
+
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+
+	if len(result.Failed) > 0 {
+		return result, fmt.Errorf("failed to sync %d file(s)", len(result.Failed))
+	}
+
+	return result, nil
+}
+
+// Checksum returns the hex encoded sha256 checksum of the fileKey file content.
+func (s *System) Checksum(fileKey string) (string, error) {
+	r, err := s.bucket.NewReader(s.ctx, fileKey, nil)
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	h := sha256.New()
+
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func syncFile(ctx context.Context, src *System, dst *System, obj *blob.ListObject, verify bool) (bool, error) {
+	// resume
+	if dstAttrs, err := dst.Attributes(obj.Key); err == nil && dstAttrs.Size == obj.Size {
+		if !verify {
+			return true, nil
+		}
+
+		srcChecksum, err := src.Checksum(obj.Key)
+		if err != nil {
+			return false, err
+		}
+
+		if dstChecksum, _ := dst.Checksum(obj.Key); dstChecksum == srcChecksum {
+			return true, nil
+		}
+	}
+
+	attrs, err := src.Attributes(obj.Key)
+	if err != nil {
+		return false, err
+	}
+
+	r, err := src.bucket.NewReader(ctx, obj.Key, nil)
+	if err != nil {
+		return false, err
+	}
+	defer r.Close()
+
+	w, err := dst.bucket.NewWriter(ctx, obj.Key, &blob.WriterOptions{
+		ContentType:        attrs.ContentType,
+		CacheControl:       attrs.CacheControl,
+		ContentDisposition: attrs.ContentDisposition,
+		ContentEncoding:    attrs.ContentEncoding,
+		ContentLanguage:    attrs.ContentLanguage,
+		Metadata:           attrs.Metadata,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	h := sha256.New()
+
+	if _, err := io.Copy(w, io.TeeReader(r, h)); err != nil {
+		w.Close()
+		return false, err
+	}
+
+	if err := w.Close(); err != nil {
+		return false, err
+	}
+
+	if verify {
+		dstChecksum, err := dst.Checksum(obj.Key)
+		if err != nil {
+			return false, err
+		}
+
+		if srcChecksum := hex.EncodeToString(h.Sum(nil)); srcChecksum != dstChecksum {
+			return false, fmt.Errorf("checksum mismatch for %q (expected %s, got %s)", obj.Key, srcChecksum, dstChecksum)
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,102 @@
+package filesystem_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/filesystem"
+)
+
+func TestSync(t *testing.T) {
+	srcDir := createTestDir(t)
+	defer os.RemoveAll(srcDir)
+
+	dstDir := t.TempDir()
+
+	src, err := filesystem.NewLocal(srcDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+
+	dst, err := filesystem.NewLocal(dstDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dst.Close()
+
+	srcFiles, err := src.List("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// initial sync
+	result, err := filesystem.Sync(context.Background(), src, dst, filesystem.SyncOptions{
+		Workers: 3,
+		Verify:  true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != len(srcFiles) || result.Copied != len(srcFiles) || result.Skipped != 0 || len(result.Failed) != 0 {
+		t.Fatalf("Unexpected initial sync result %+v", result)
+	}
+
+	for _, f := range srcFiles {
+		srcChecksum, _ := src.Checksum(f.Key)
+		dstChecksum, _ := dst.Checksum(f.Key)
+		if srcChecksum == "" || srcChecksum != dstChecksum {
+			t.Fatalf("Expected %q checksum %q, got %q", f.Key, srcChecksum, dstChecksum)
+		}
+	}
+
+	attrs, err := dst.Attributes("image.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attrs.ContentType != "image/png" {
+		t.Fatalf("Expected the content type to be preserved, got %q", attrs.ContentType)
+	}
+
+	// resume
+	result, err = filesystem.Sync(context.Background(), src, dst, filesystem.SyncOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Copied != 0 || result.Skipped != len(srcFiles) {
+		t.Fatalf("Expected all files to be skipped, got %+v", result)
+	}
+
+	// same size but different content
+	if err := src.Upload([]byte("a"), "test/sub1.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := dst.Upload([]byte("b"), "test/sub1.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	processed := []string{}
+	result, err = filesystem.Sync(context.Background(), src, dst, filesystem.SyncOptions{
+		Prefix: "test/",
+		Verify: true,
+		OnFile: func(key string, skipped bool, err error) {
+			if !skipped {
+				processed = append(processed, key)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 2 || result.Copied != 1 || len(processed) != 1 || processed[0] != "test/sub1.txt" {
+		t.Fatalf("Expected only test/sub1.txt to be copied, got %+v (%v)", result, processed)
+	}
+
+	// canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := filesystem.Sync(ctx, src, dst, filesystem.SyncOptions{}); err == nil {
+		t.Fatal("Expected canceled context error")
+	}
+}
@@ -0,0 +1,146 @@
+package graphql
+
+// Document represents a parsed GraphQL executable document.
+type Document struct {
+	Operations []*OperationDefinition
+	Fragments  map[string]*FragmentDefinition
+}
+
+// Operation returns the document operation with the specified name.
+//
+// If name is empty and the document has only one operation, it is returned.
+func (d *Document) Operation(name string) *OperationDefinition {
+	if name == "" {
+		if len(d.Operations) == 1 {
+			return d.Operations[0]
+		}
+		return nil
+	}
+
+	for _, op := range d.Operations {
+		if op.Name == name {
+			return op
+		}
+	}
+
+	return nil
+}
+
+// Supported operation types.
+const (
+	OperationQuery    = "query"
+	OperationMutation = "mutation"
+)
+
+// OperationDefinition represents a single query or mutation operation.
+type OperationDefinition struct {
+	Operation    string
+	Name         string
+	Variables    []*VariableDefinition
+	SelectionSet SelectionSet
+}
+
+// VariableDefinition represents a single operation variable definition.
+type VariableDefinition struct {
+	Name         string
+	Type         string
+	DefaultValue any
+}
+
+// FragmentDefinition represents a single named fragment definition.
+type FragmentDefinition struct {
+	Name          string
+	TypeCondition string
+	SelectionSet  SelectionSet
+}
+
+// Selection is implemented by the different selection set items
+// ([*Field], [*FragmentSpread] and [*InlineFragment]).
+type Selection interface {
+	isSelection()
+}
+
+// SelectionSet represents a list of selections.
+type SelectionSet []Selection
+
+// Field represents a single selection set field.
+type Field struct {
+	Alias        string
+	Name         string
+	Arguments    []*Argument
+	Directives   []*Directive
+	SelectionSet SelectionSet
+}
+
+// ResponseKey returns the key under which the field value will be returned.
+func (f *Field) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+
+	return f.Name
+}
+
+// FragmentSpread represents a named fragment spread (eg. "...userFields").
+type FragmentSpread struct {
+	Name       string
+	Directives []*Directive
+}
+
+// InlineFragment represents an inline fragment (eg. "... on User { id }").
+type InlineFragment struct {
+	TypeCondition string
+	Directives    []*Directive
+	SelectionSet  SelectionSet
+}
+
+func (*Field) isSelection()          {}
+func (*FragmentSpread) isSelection() {}
+func (*InlineFragment) isSelection() {}
+
+// Argument represents a single field or directive argument.
+//
+// The argument value could be a plain Go value (nil, bool, int64, float64,
+// string, []any, map[string]any), an [EnumValue] or a [Variable].
+type Argument struct {
+	Name  string
+	Value any
+}
+
+// Directive represents a single directive (eg. "@include(if: $flag)").
+type Directive struct {
+	Name      string
+	Arguments []*Argument
+}
+
+// Variable represents a variable reference inside an argument value.
+type Variable string
+
+// EnumValue represents an unquoted enum value literal.
+type EnumValue string
+
+// ResolveValue returns a copy of the provided argument value
+// with all variable references replaced with their provided values
+// and all enum values converted to plain strings.
+func ResolveValue(value any, variables map[string]any) any {
+	switch v := value.(type) {
+	case Variable:
+		return variables[string(v)]
+	case EnumValue:
+		return string(v)
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = ResolveValue(item, variables)
+		}
+		return result
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for k, item := range v {
+			result[k] = ResolveValue(item, variables)
+		}
+		return result
+	default:
+		return v
+	}
+}
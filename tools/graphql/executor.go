@@ -0,0 +1,567 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// Request defines a single GraphQL request (eg. the body of a GraphQL over HTTP POST request).
+type Request struct {
+	Query         string         `json:"query" form:"query"`
+	OperationName string         `json:"operationName" form:"operationName"`
+	Variables     map[string]any `json:"variables" form:"variables"`
+}
+
+// Result defines a GraphQL execution result.
+type Result struct {
+	Data   any      `json:"data"`
+	Errors []*Error `json:"errors,omitempty"`
+}
+
+// Error defines a single GraphQL result error.
+type Error struct {
+	Message    string         `json:"message"`
+	Path       []any          `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+// Error implements the [error] interface.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// ExtendedError defines a resolver error with additional
+// information to be returned as part of the result error "extensions".
+type ExtendedError interface {
+	error
+	Extensions() map[string]any
+}
+
+// ResultMap is an insertion ordered map used to store
+// the resolved object values of an execution result.
+type ResultMap struct {
+	keys   []string
+	values map[string]any
+}
+
+// Get returns the value of the specified result key.
+func (m *ResultMap) Get(key string) any {
+	return m.values[key]
+}
+
+// Keys returns the ordered list of the result keys.
+func (m *ResultMap) Keys() []string {
+	return m.keys
+}
+
+func (m *ResultMap) set(key string, value any) {
+	if m.values == nil {
+		m.values = map[string]any{}
+	}
+
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+
+	m.values[key] = value
+}
+
+// MarshalJSON implements the [json.Marshaler] interface.
+func (m *ResultMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		rawKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(rawKey)
+
+		buf.WriteByte(':')
+
+		rawValue, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(rawValue)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// Execute parses and executes the provided request against the current schema.
+func (s *Schema) Execute(req Request) *Result {
+	doc, err := Parse(req.Query)
+	if err != nil {
+		return &Result{Errors: []*Error{{Message: "Syntax error: " + err.Error()}}}
+	}
+
+	return s.ExecuteDocument(doc, req.OperationName, req.Variables)
+}
+
+// ExecuteDocument executes the specified document operation against the current schema.
+func (s *Schema) ExecuteDocument(doc *Document, operationName string, variables map[string]any) *Result {
+	op := doc.Operation(operationName)
+	if op == nil {
+		if operationName == "" {
+			return &Result{Errors: []*Error{{Message: "Must provide operation name if the document contains multiple operations."}}}
+		}
+		return &Result{Errors: []*Error{{Message: fmt.Sprintf("Unknown operation named %q.", operationName)}}}
+	}
+
+	var root *Object
+	switch op.Operation {
+	case OperationQuery:
+		root = s.Query
+	case OperationMutation:
+		root = s.Mutation
+	}
+	if root == nil {
+		return &Result{Errors: []*Error{{Message: fmt.Sprintf("The schema doesn't support %s operations.", op.Operation)}}}
+	}
+
+	if err := s.checkLimits(doc, op); err != nil {
+		return &Result{Errors: []*Error{{Message: err.Error()}}}
+	}
+
+	vars, err := coerceVariables(op.Variables, variables)
+	if err != nil {
+		return &Result{Errors: []*Error{{Message: err.Error()}}}
+	}
+
+	e := &executor{
+		schema:    s,
+		doc:       doc,
+		variables: vars,
+	}
+
+	data, nullified := e.executeSelectionSet(root, nil, op.SelectionSet, nil)
+
+	result := &Result{Errors: e.errors}
+	if !nullified {
+		result.Data = data
+	}
+
+	return result
+}
+
+// -------------------------------------------------------------------
+
+// errPropagated is returned when a nested field error was already
+// registered and the null value has to be propagated to the parent.
+var errPropagated = errors.New("propagated")
+
+type executor struct {
+	schema    *Schema
+	doc       *Document
+	variables map[string]any
+	errors    []*Error
+}
+
+func (e *executor) addError(err error, path []any) {
+	if errors.Is(err, errPropagated) {
+		return
+	}
+
+	result := &Error{
+		Message: err.Error(),
+		Path:    append([]any{}, path...),
+	}
+
+	var extended ExtendedError
+	if errors.As(err, &extended) {
+		result.Extensions = extended.Extensions()
+	}
+
+	e.errors = append(e.errors, result)
+}
+
+// executeSelectionSet resolves all selection set fields of the obj type.
+//
+// It returns true as second result if the object value
+// must be nullified due to a non-null field error.
+func (e *executor) executeSelectionSet(obj *Object, source any, set SelectionSet, path []any) (*ResultMap, bool) {
+	result := &ResultMap{}
+
+	for _, field := range e.collectFields(obj, set, nil) {
+		key := field.ResponseKey()
+		fieldPath := append(append([]any{}, path...), key)
+
+		if field.Name == "__typename" {
+			result.set(key, obj.Name)
+			continue
+		}
+
+		def := obj.Field(field.Name)
+		if def == nil && obj == e.schema.Query {
+			def = introspectionMetaField(field.Name)
+			if def != nil && !e.schema.Introspection {
+				e.addError(errors.New("GraphQL introspection is not allowed."), fieldPath)
+				result.set(key, nil)
+				continue
+			}
+		}
+		if def == nil {
+			e.addError(fmt.Errorf("Cannot query field %q on type %q.", field.Name, obj.Name), fieldPath)
+			result.set(key, nil)
+			continue
+		}
+
+		value, err := e.resolveField(def, field, source, fieldPath)
+		if err != nil {
+			e.addError(err, fieldPath)
+			if def.Type.nonNull != nil {
+				return nil, true
+			}
+			result.set(key, nil)
+			continue
+		}
+
+		result.set(key, value)
+	}
+
+	return result, false
+}
+
+func (e *executor) resolveField(def *FieldDefinition, field *Field, source any, path []any) (any, error) {
+	args, err := e.coerceArguments(def, field)
+	if err != nil {
+		return nil, err
+	}
+
+	var value any
+
+	if def.Resolve != nil {
+		value, err = def.Resolve(ResolveParams{
+			Source: source,
+			Args:   args,
+			Field:  field,
+			exec:   e,
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else if m, ok := source.(map[string]any); ok {
+		value = m[def.Name]
+	}
+
+	return e.completeValue(def.Type, field, value, path)
+}
+
+func (e *executor) completeValue(t *Type, field *Field, value any, path []any) (any, error) {
+	if t.nonNull != nil {
+		result, err := e.completeValue(t.nonNull, field, value, path)
+		if err != nil {
+			return nil, err
+		}
+		if result == nil {
+			return nil, fmt.Errorf("Cannot return null for non-nullable field %q.", field.Name)
+		}
+		return result, nil
+	}
+
+	if isNil(value) {
+		return nil, nil
+	}
+
+	if t.list != nil {
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return nil, fmt.Errorf("Expected a list value for field %q.", field.Name)
+		}
+
+		result := make([]any, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			itemPath := append(append([]any{}, path...), i)
+			item, err := e.completeValue(t.list, field, rv.Index(i).Interface(), itemPath)
+			if err != nil {
+				if t.list.nonNull != nil {
+					e.addError(err, itemPath)
+					return nil, errPropagated
+				}
+				e.addError(err, itemPath)
+			}
+			result[i] = item
+		}
+
+		return result, nil
+	}
+
+	if t.object != nil {
+		if len(field.SelectionSet) == 0 {
+			return nil, fmt.Errorf("Field %q of type %q must have a selection of subfields.", field.Name, t.object.Name)
+		}
+
+		result, nullified := e.executeSelectionSet(t.object, value, field.SelectionSet, path)
+		if nullified {
+			return nil, errPropagated
+		}
+
+		return result, nil
+	}
+
+	if len(field.SelectionSet) > 0 {
+		return nil, fmt.Errorf("Field %q must not have a selection since type %q has no subfields.", field.Name, t.scalar)
+	}
+
+	return value, nil
+}
+
+// collectFields flattens the provided selection set into a list of fields
+// by merging the fragments and the fields with the same response key.
+//
+// If obj is not nil, fragments with type conditions different
+// from the object name are ignored.
+func (e *executor) collectFields(obj *Object, set SelectionSet, visitedFragments map[string]struct{}) []*Field {
+	if visitedFragments == nil {
+		visitedFragments = map[string]struct{}{}
+	}
+
+	result := []*Field{}
+	indexes := map[string]int{}
+
+	merge := func(fields ...*Field) {
+		for _, f := range fields {
+			key := f.ResponseKey()
+			if i, ok := indexes[key]; ok {
+				existing := *result[i]
+				existing.SelectionSet = append(append(SelectionSet{}, existing.SelectionSet...), f.SelectionSet...)
+				result[i] = &existing
+				continue
+			}
+			indexes[key] = len(result)
+			result = append(result, f)
+		}
+	}
+
+	for _, selection := range set {
+		switch s := selection.(type) {
+		case *Field:
+			if e.shouldInclude(s.Directives) {
+				merge(s)
+			}
+		case *InlineFragment:
+			if !e.shouldInclude(s.Directives) || !matchTypeCondition(obj, s.TypeCondition) {
+				continue
+			}
+			merge(e.collectFields(obj, s.SelectionSet, visitedFragments)...)
+		case *FragmentSpread:
+			if !e.shouldInclude(s.Directives) {
+				continue
+			}
+			if _, ok := visitedFragments[s.Name]; ok {
+				continue
+			}
+			visitedFragments[s.Name] = struct{}{}
+
+			fragment := e.doc.Fragments[s.Name]
+			if fragment == nil || !matchTypeCondition(obj, fragment.TypeCondition) {
+				continue
+			}
+			merge(e.collectFields(obj, fragment.SelectionSet, visitedFragments)...)
+		}
+	}
+
+	return result
+}
+
+// shouldInclude evaluates the @skip and @include directives.
+func (e *executor) shouldInclude(directives []*Directive) bool {
+	for _, d := range directives {
+		if d.Name != "skip" && d.Name != "include" {
+			continue
+		}
+
+		var condition bool
+		for _, arg := range d.Arguments {
+			if arg.Name == "if" {
+				condition, _ = ResolveValue(arg.Value, e.variables).(bool)
+			}
+		}
+
+		if (d.Name == "skip" && condition) || (d.Name == "include" && !condition) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (e *executor) coerceArguments(def *FieldDefinition, field *Field) (map[string]any, error) {
+	provided := make(map[string]any, len(field.Arguments))
+
+	for _, arg := range field.Arguments {
+		if def.Arg(arg.Name) == nil {
+			return nil, fmt.Errorf("Unknown argument %q on field %q.", arg.Name, field.Name)
+		}
+
+		// skip unset variables so that the default value could be used
+		if v, ok := arg.Value.(Variable); ok {
+			if _, exists := e.variables[string(v)]; !exists {
+				continue
+			}
+		}
+
+		provided[arg.Name] = ResolveValue(arg.Value, e.variables)
+	}
+
+	result := make(map[string]any, len(def.Args))
+
+	for _, argDef := range def.Args {
+		value, ok := provided[argDef.Name]
+		if !ok {
+			value = argDef.DefaultValue
+		}
+
+		coerced, err := coerceInput(argDef.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid argument %q on field %q: %w", argDef.Name, field.Name, err)
+		}
+
+		if coerced != nil || ok {
+			result[argDef.Name] = coerced
+		}
+	}
+
+	return result, nil
+}
+
+// coerceVariables returns the operation variables with their default values applied.
+func coerceVariables(defs []*VariableDefinition, provided map[string]any) (map[string]any, error) {
+	result := map[string]any{}
+
+	for _, def := range defs {
+		value, ok := provided[def.Name]
+		if !ok {
+			if def.DefaultValue != nil {
+				result[def.Name] = def.DefaultValue
+			} else if strings.HasSuffix(def.Type, "!") {
+				return nil, fmt.Errorf("Variable \"$%s\" of required type %q was not provided.", def.Name, def.Type)
+			}
+			continue
+		}
+
+		if value == nil && strings.HasSuffix(def.Type, "!") {
+			return nil, fmt.Errorf("Variable \"$%s\" of non-null type %q must not be null.", def.Name, def.Type)
+		}
+
+		result[def.Name] = value
+	}
+
+	return result, nil
+}
+
+// coerceInput validates and normalizes a single input value against the provided type.
+//
+// Custom (non built-in) scalars are returned as they are.
+func coerceInput(t *Type, value any) (any, error) {
+	if t.nonNull != nil {
+		if isNil(value) {
+			return nil, errors.New("expected a non-null value")
+		}
+		return coerceInput(t.nonNull, value)
+	}
+
+	if isNil(value) {
+		return nil, nil
+	}
+
+	if t.list != nil {
+		list, ok := value.([]any)
+		if !ok {
+			// input coercion of a single item to a list
+			list = []any{value}
+		}
+
+		result := make([]any, len(list))
+		for i, item := range list {
+			v, err := coerceInput(t.list, item)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = v
+		}
+
+		return result, nil
+	}
+
+	switch t.scalar {
+	case ScalarString:
+		if v, ok := value.(string); ok {
+			return v, nil
+		}
+		return nil, errors.New("expected a String value")
+	case ScalarID:
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case int64:
+			return fmt.Sprint(v), nil
+		case float64:
+			if v == math.Trunc(v) {
+				return fmt.Sprint(int64(v)), nil
+			}
+		}
+		return nil, errors.New("expected an ID value")
+	case ScalarBoolean:
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+		return nil, errors.New("expected a Boolean value")
+	case ScalarInt:
+		switch v := value.(type) {
+		case int64:
+			return int(v), nil
+		case int:
+			return v, nil
+		case float64:
+			if v == math.Trunc(v) {
+				return int(v), nil
+			}
+		}
+		return nil, errors.New("expected an Int value")
+	case ScalarFloat:
+		switch v := value.(type) {
+		case int64:
+			return float64(v), nil
+		case int:
+			return float64(v), nil
+		case float64:
+			return v, nil
+		}
+		return nil, errors.New("expected a Float value")
+	}
+
+	return value, nil
+}
+
+func matchTypeCondition(obj *Object, typeCondition string) bool {
+	return obj == nil || typeCondition == "" || typeCondition == obj.Name
+}
+
+func isNil(value any) bool {
+	if value == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface, reflect.Func:
+		return rv.IsNil()
+	}
+
+	return false
+}
@@ -0,0 +1,407 @@
+package graphql_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/graphql"
+)
+
+type testExtendedError struct {
+	code int
+}
+
+func (e testExtendedError) Error() string {
+	return "extended error"
+}
+
+func (e testExtendedError) Extensions() map[string]any {
+	return map[string]any{"code": e.code}
+}
+
+func testSchema() *graphql.Schema {
+	item := graphql.NewObject("Item", "")
+	item.AddField(&graphql.FieldDefinition{Name: "id", Type: graphql.NonNull(graphql.Scalar(graphql.ScalarID))})
+	item.AddField(&graphql.FieldDefinition{Name: "title", Type: graphql.Scalar(graphql.ScalarString)})
+	item.AddField(&graphql.FieldDefinition{
+		Name: "broken",
+		Type: graphql.NonNull(graphql.Scalar(graphql.ScalarString)),
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			return nil, errors.New("broken field")
+		},
+	})
+	item.AddField(&graphql.FieldDefinition{
+		Name: "children",
+		Type: graphql.ListOf(graphql.NonNull(graphql.ObjectType(item))),
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			return []map[string]any{{"id": "c1", "title": "child"}}, nil
+		},
+	})
+
+	query := graphql.NewObject("Query", "")
+	query.AddField(&graphql.FieldDefinition{
+		Name: "item",
+		Type: graphql.ObjectType(item),
+		Args: []*graphql.ArgumentDefinition{
+			{Name: "id", Type: graphql.NonNull(graphql.Scalar(graphql.ScalarID))},
+			{Name: "limit", Type: graphql.Scalar(graphql.ScalarInt), DefaultValue: int64(10)},
+		},
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			if p.Args["id"] == "missing" {
+				return nil, nil
+			}
+			return map[string]any{"id": p.Args["id"], "title": fmt.Sprintf("limit %v", p.Args["limit"])}, nil
+		},
+	})
+	query.AddField(&graphql.FieldDefinition{
+		Name: "lookahead",
+		Type: graphql.ObjectType(item),
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			names := []string{}
+			for _, f := range p.CollectFields(p.Field.SelectionSet) {
+				names = append(names, f.Name)
+			}
+			return map[string]any{"id": "x", "title": strings.Join(names, ",")}, nil
+		},
+	})
+
+	query.AddField(&graphql.FieldDefinition{
+		Name: "extended",
+		Type: graphql.Scalar(graphql.ScalarString),
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			return nil, testExtendedError{code: 400}
+		},
+	})
+
+	mutation := graphql.NewObject("Mutation", "")
+	mutation.AddField(&graphql.FieldDefinition{
+		Name: "ping",
+		Type: graphql.NonNull(graphql.Scalar(graphql.ScalarBoolean)),
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			return true, nil
+		},
+	})
+
+	return &graphql.Schema{Query: query, Mutation: mutation}
+}
+
+func toString(v any) string {
+	raw, _ := json.Marshal(v)
+	return string(raw)
+}
+
+func TestSchemaExecute(t *testing.T) {
+	schema := testSchema()
+
+	scenarios := []struct {
+		name     string
+		request  graphql.Request
+		expected string
+	}{
+		{
+			"syntax error",
+			graphql.Request{Query: "{ item("},
+			`{"data":null,"errors":[{"message":"Syntax error: unexpected end of document at position 7"}]}`,
+		},
+		{
+			"missing operation name",
+			graphql.Request{Query: "query a { ping } query b { ping }"},
+			`{"data":null,"errors":[{"message":"Must provide operation name if the document contains multiple operations."}]}`,
+		},
+		{
+			"unknown operation name",
+			graphql.Request{Query: "query a { ping }", OperationName: "b"},
+			`{"data":null,"errors":[{"message":"Unknown operation named \"b\"."}]}`,
+		},
+		{
+			"missing required variable",
+			graphql.Request{Query: "query ($id: ID!) { item(id: $id) { id } }"},
+			`{"data":null,"errors":[{"message":"Variable \"$id\" of required type \"ID!\" was not provided."}]}`,
+		},
+		{
+			"unknown field",
+			graphql.Request{Query: "{ missing }"},
+			`{"data":{"missing":null},"errors":[{"message":"Cannot query field \"missing\" on type \"Query\".","path":["missing"]}]}`,
+		},
+		{
+			"unknown argument",
+			graphql.Request{Query: `{ item(id: "1", missing: 1) { id } }`},
+			`{"data":{"item":null},"errors":[{"message":"Unknown argument \"missing\" on field \"item\".","path":["item"]}]}`,
+		},
+		{
+			"missing required argument",
+			graphql.Request{Query: `{ item { id } }`},
+			`{"data":{"item":null},"errors":[{"message":"Invalid argument \"id\" on field \"item\": expected a non-null value","path":["item"]}]}`,
+		},
+		{
+			"invalid argument type",
+			graphql.Request{Query: `{ item(id: "1", limit: "abc") { id } }`},
+			`{"data":{"item":null},"errors":[{"message":"Invalid argument \"limit\" on field \"item\": expected an Int value","path":["item"]}]}`,
+		},
+		{
+			"missing object selection",
+			graphql.Request{Query: `{ item(id: "1") }`},
+			`{"data":{"item":null},"errors":[{"message":"Field \"item\" of type \"Item\" must have a selection of subfields.","path":["item"]}]}`,
+		},
+		{
+			"scalar with selection",
+			graphql.Request{Query: `{ item(id: "1") { id { a } } }`},
+			`{"data":{"item":null},"errors":[{"message":"Field \"id\" must not have a selection since type \"ID\" has no subfields.","path":["item","id"]}]}`,
+		},
+		{
+			"null object",
+			graphql.Request{Query: `{ item(id: "missing") { id } }`},
+			`{"data":{"item":null}}`,
+		},
+		{
+			"non-null field error propagation",
+			graphql.Request{Query: `{ item(id: "1") { id broken } }`},
+			`{"data":{"item":null},"errors":[{"message":"broken field","path":["item","broken"]}]}`,
+		},
+		{
+			"variables, defaults, aliases, typename and nested lists",
+			graphql.Request{
+				Query:     `query ($id: ID!, $limit: Int) { a: item(id: $id) { __typename id title } b: item(id: 2, limit: $limit) { id title children { id title } } }`,
+				Variables: map[string]any{"id": "1", "limit": float64(5)},
+			},
+			`{"data":{"a":{"__typename":"Item","id":"1","title":"limit 10"},"b":{"id":"2","title":"limit 5","children":[{"id":"c1","title":"child"}]}}}`,
+		},
+		{
+			"fragments, directives and field merging",
+			graphql.Request{
+				Query:     `query ($skip: Boolean = true) { item(id: "1") { ...f ... on Item { title } ... on Other { broken } id @skip(if: $skip) title @include(if: false) } } fragment f on Item { id children { id } children { title } }`,
+				Variables: map[string]any{},
+			},
+			`{"data":{"item":{"id":"1","children":[{"id":"c1","title":"child"}],"title":"limit 10"}}}`,
+		},
+		{
+			"lookahead",
+			graphql.Request{Query: `{ lookahead { ...f id @skip(if: true) title } } fragment f on Item { children { id } }`},
+			`{"data":{"lookahead":{"children":[{"id":"c1"}],"title":"children,title"}}}`,
+		},
+		{
+			"extended error",
+			graphql.Request{Query: `{ extended }`},
+			`{"data":{"extended":null},"errors":[{"message":"extended error","path":["extended"],"extensions":{"code":400}}]}`,
+		},
+		{
+			"mutation",
+			graphql.Request{Query: `mutation { ping second: ping }`},
+			`{"data":{"ping":true,"second":true}}`,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := schema.Execute(s.request)
+
+			raw, err := json.Marshal(result)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if str := string(raw); str != s.expected {
+				t.Fatalf("Expected\n%s\ngot\n%s", s.expected, str)
+			}
+		})
+	}
+}
+
+func TestSchemaExecuteWithoutMutation(t *testing.T) {
+	schema := &graphql.Schema{Query: testSchema().Query}
+
+	result := schema.Execute(graphql.Request{Query: "mutation { ping }"})
+
+	if len(result.Errors) != 1 || result.Errors[0].Message != "The schema doesn't support mutation operations." {
+		t.Fatalf("Expected unsupported mutation error, got %v", result.Errors)
+	}
+}
+
+func TestResultMap(t *testing.T) {
+	schema := testSchema()
+
+	result := schema.Execute(graphql.Request{Query: `{ item(id: "1") { title id } }`})
+
+	data, ok := result.Data.(*graphql.ResultMap)
+	if !ok {
+		t.Fatalf("Expected *ResultMap data, got %T", result.Data)
+	}
+
+	item, ok := data.Get("item").(*graphql.ResultMap)
+	if !ok {
+		t.Fatalf("Expected *ResultMap item, got %T", data.Get("item"))
+	}
+
+	if keys := toString(item.Keys()); keys != `["title","id"]` {
+		t.Fatalf("Expected ordered keys, got %s", keys)
+	}
+}
+
+func TestSchemaExecuteLimits(t *testing.T) {
+	scenarios := []struct {
+		name          string
+		maxDepth      int
+		maxComplexity int
+		query         string
+		expectedError string
+	}{
+		{
+			"no limits",
+			0,
+			0,
+			`{ item(id: "1") { children { children { id } } } }`,
+			"",
+		},
+		{
+			"depth within the limit",
+			3,
+			0,
+			`{ item(id: "1") { children { id } } }`,
+			"",
+		},
+		{
+			"depth exceeded",
+			3,
+			0,
+			`{ item(id: "1") { children { children { id } } } }`,
+			"The operation depth 4 exceeds the max allowed depth 3.",
+		},
+		{
+			"depth exceeded through fragments",
+			3,
+			0,
+			`{ item(id: "1") { ...a } } fragment a on Item { children { ... on Item { children { id } } } }`,
+			"The operation depth 4 exceeds the max allowed depth 3.",
+		},
+		{
+			"complexity exceeded",
+			0,
+			3,
+			`{ item(id: "1") { id title children { id } } }`,
+			"The operation complexity 5 exceeds the max allowed complexity 3.",
+		},
+		{
+			"complexity exceeded through repeated fragments",
+			0,
+			30,
+			`{ item(id: "1") { ...c ...c2 } }
+			fragment c on Item { children { ...d a: children { ...d } } }
+			fragment c2 on Item { b: children { ...c } }
+			fragment d on Item { id title x: title y: title z: title w: title v: title u: title }`,
+			"The operation complexity 38 exceeds the max allowed complexity 30.",
+		},
+		{
+			"fragment cycle",
+			0,
+			0,
+			`{ item(id: "1") { ...a } } fragment a on Item { children { ...b } } fragment b on Item { ...a }`,
+			"Cannot spread fragment \"a\" within itself.",
+		},
+		{
+			"introspection fields are not counted",
+			1,
+			1,
+			`{ ping: __typename }`,
+			"",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			schema := testSchema()
+			schema.MaxDepth = s.maxDepth
+			schema.MaxComplexity = s.maxComplexity
+
+			result := schema.Execute(graphql.Request{Query: s.query})
+
+			var errMessage string
+			if len(result.Errors) > 0 {
+				errMessage = result.Errors[0].Message
+			}
+
+			if s.expectedError == "" {
+				if result.Data == nil {
+					t.Fatalf("Expected data, got errors %v", toString(result.Errors))
+				}
+				return
+			}
+
+			if errMessage != s.expectedError || result.Data != nil {
+				t.Fatalf("Expected error %q, got %v", s.expectedError, toString(result))
+			}
+		})
+	}
+}
+
+func TestSchemaExecuteIntrospection(t *testing.T) {
+	query := `{
+		__type(name: "Item") {
+			kind
+			name
+			fields {
+				name
+				args { name defaultValue }
+				type { kind name ofType { kind name } }
+			}
+		}
+		missing: __type(name: "Missing") { name }
+		__schema {
+			queryType { name }
+			mutationType { name }
+			subscriptionType { name }
+			directives { name locations }
+		}
+	}`
+
+	t.Run("disabled", func(t *testing.T) {
+		schema := testSchema()
+
+		result := toString(schema.Execute(graphql.Request{Query: query}))
+
+		expected := `{"data":{"__type":null,"missing":null,"__schema":null},"errors":[` +
+			`{"message":"GraphQL introspection is not allowed.","path":["__type"]},` +
+			`{"message":"GraphQL introspection is not allowed.","path":["missing"]},` +
+			`{"message":"GraphQL introspection is not allowed.","path":["__schema"]}]}`
+
+		if result != expected {
+			t.Fatalf("Expected\n%s\ngot\n%s", expected, result)
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		schema := testSchema()
+		schema.Introspection = true
+
+		result := toString(schema.Execute(graphql.Request{Query: query}))
+
+		expected := `{"data":{"__type":{"kind":"OBJECT","name":"Item","fields":[` +
+			`{"name":"id","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"ID"}}},` +
+			`{"name":"title","args":[],"type":{"kind":"SCALAR","name":"String","ofType":null}},` +
+			`{"name":"broken","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String"}}},` +
+			`{"name":"children","args":[],"type":{"kind":"LIST","name":null,"ofType":{"kind":"NON_NULL","name":null}}}]},` +
+			`"missing":null,` +
+			`"__schema":{"queryType":{"name":"Query"},"mutationType":{"name":"Mutation"},"subscriptionType":null,"directives":[` +
+			`{"name":"include","locations":["FIELD","FRAGMENT_SPREAD","INLINE_FRAGMENT"]},` +
+			`{"name":"skip","locations":["FIELD","FRAGMENT_SPREAD","INLINE_FRAGMENT"]}]}}}`
+
+		if result != expected {
+			t.Fatalf("Expected\n%s\ngot\n%s", expected, result)
+		}
+	})
+
+	t.Run("types list", func(t *testing.T) {
+		schema := testSchema()
+		schema.Introspection = true
+
+		result := toString(schema.Execute(graphql.Request{Query: `{ __schema { types { name } } }`}))
+
+		for _, name := range []string{"Query", "Mutation", "Item", "__Schema", "__Type", "ID", "String", "Boolean"} {
+			if !strings.Contains(result, `{"name":"`+name+`"}`) {
+				t.Fatalf("Missing type %q in\n%s", name, result)
+			}
+		}
+	})
+}
@@ -0,0 +1,399 @@
+package graphql
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+)
+
+// Type kinds returned by the "__Type.kind" introspection field.
+const (
+	KindScalar  = "SCALAR"
+	KindObject  = "OBJECT"
+	KindList    = "LIST"
+	KindNonNull = "NON_NULL"
+)
+
+// Note that the introspection enums (__TypeKind and __DirectiveLocation)
+// are exposed as plain String values because enum types are not supported.
+var (
+	introspectionOnce  sync.Once
+	introspectionTypes []*Object
+	schemaMetaField    *FieldDefinition
+	typeMetaField      *FieldDefinition
+	builtinDirectives  []*directiveDefinition
+)
+
+type directiveDefinition struct {
+	name        string
+	description string
+	locations   []string
+	args        []*ArgumentDefinition
+}
+
+// introspectionMetaField returns the "__schema" or "__type"
+// query meta field definition (or nil for any other name).
+func introspectionMetaField(name string) *FieldDefinition {
+	introspectionOnce.Do(initIntrospection)
+
+	switch name {
+	case "__schema":
+		return schemaMetaField
+	case "__type":
+		return typeMetaField
+	}
+
+	return nil
+}
+
+// namedTypes returns all named types of the schema
+// (including the built-in scalars and the introspection types).
+func (s *Schema) namedTypes() []*Type {
+	introspectionOnce.Do(initIntrospection)
+
+	objects, scalars := s.collectTypes()
+
+	names := map[string]struct{}{}
+	for _, name := range scalars {
+		names[name] = struct{}{}
+	}
+	for name := range builtinScalars {
+		names[name] = struct{}{}
+	}
+
+	result := make([]*Type, 0, len(objects)+len(names)+len(introspectionTypes))
+
+	for _, o := range objects {
+		result = append(result, ObjectType(o))
+	}
+
+	for _, o := range introspectionTypes {
+		result = append(result, ObjectType(o))
+	}
+
+	scalarNames := make([]string, 0, len(names))
+	for name := range names {
+		scalarNames = append(scalarNames, name)
+	}
+	sort.Strings(scalarNames)
+
+	for _, name := range scalarNames {
+		result = append(result, Scalar(name))
+	}
+
+	return result
+}
+
+func initIntrospection() {
+	str := Scalar(ScalarString)
+	boolean := Scalar(ScalarBoolean)
+
+	schemaObj := NewObject("__Schema", "A GraphQL schema defines the capabilities of a GraphQL server.")
+	typeObj := NewObject("__Type", "The fundamental unit of any GraphQL schema.")
+	fieldObj := NewObject("__Field", "Object field definition.")
+	inputValueObj := NewObject("__InputValue", "Argument definition.")
+	enumValueObj := NewObject("__EnumValue", "Enum value definition (enums are not supported and always empty).")
+	directiveObj := NewObject("__Directive", "Directive definition.")
+
+	introspectionTypes = []*Object{schemaObj, typeObj, fieldObj, inputValueObj, enumValueObj, directiveObj}
+
+	includeDeprecatedArg := &ArgumentDefinition{Name: "includeDeprecated", Type: boolean, DefaultValue: false}
+
+	null := func(p ResolveParams) (any, error) { return nil, nil }
+	falseValue := func(p ResolveParams) (any, error) { return false, nil }
+
+	// __Schema
+	// ---
+	schemaObj.AddField(&FieldDefinition{Name: "description", Type: str, Resolve: null})
+	schemaObj.AddField(&FieldDefinition{
+		Name: "types",
+		Type: NonNull(ListOf(NonNull(ObjectType(typeObj)))),
+		Resolve: func(p ResolveParams) (any, error) {
+			return p.Source.(*Schema).namedTypes(), nil
+		},
+	})
+	schemaObj.AddField(&FieldDefinition{
+		Name: "queryType",
+		Type: NonNull(ObjectType(typeObj)),
+		Resolve: func(p ResolveParams) (any, error) {
+			return ObjectType(p.Source.(*Schema).Query), nil
+		},
+	})
+	schemaObj.AddField(&FieldDefinition{
+		Name: "mutationType",
+		Type: ObjectType(typeObj),
+		Resolve: func(p ResolveParams) (any, error) {
+			if m := p.Source.(*Schema).Mutation; m != nil {
+				return ObjectType(m), nil
+			}
+			return nil, nil
+		},
+	})
+	schemaObj.AddField(&FieldDefinition{Name: "subscriptionType", Type: ObjectType(typeObj), Resolve: null})
+	schemaObj.AddField(&FieldDefinition{
+		Name: "directives",
+		Type: NonNull(ListOf(NonNull(ObjectType(directiveObj)))),
+		Resolve: func(p ResolveParams) (any, error) {
+			return builtinDirectives, nil
+		},
+	})
+
+	// __Type
+	// ---
+	typeObj.AddField(&FieldDefinition{
+		Name: "kind",
+		Type: NonNull(str),
+		Resolve: func(p ResolveParams) (any, error) {
+			t := p.Source.(*Type)
+			switch {
+			case t.nonNull != nil:
+				return KindNonNull, nil
+			case t.list != nil:
+				return KindList, nil
+			case t.object != nil:
+				return KindObject, nil
+			default:
+				return KindScalar, nil
+			}
+		},
+	})
+	typeObj.AddField(&FieldDefinition{
+		Name: "name",
+		Type: str,
+		Resolve: func(p ResolveParams) (any, error) {
+			t := p.Source.(*Type)
+			switch {
+			case t.object != nil:
+				return t.object.Name, nil
+			case t.scalar != "":
+				return t.scalar, nil
+			}
+			return nil, nil
+		},
+	})
+	typeObj.AddField(&FieldDefinition{
+		Name: "description",
+		Type: str,
+		Resolve: func(p ResolveParams) (any, error) {
+			if t := p.Source.(*Type); t.object != nil && t.object.Description != "" {
+				return t.object.Description, nil
+			}
+			return nil, nil
+		},
+	})
+	typeObj.AddField(&FieldDefinition{Name: "specifiedByURL", Type: str, Resolve: null})
+	typeObj.AddField(&FieldDefinition{
+		Name: "fields",
+		Type: ListOf(NonNull(ObjectType(fieldObj))),
+		Args: []*ArgumentDefinition{includeDeprecatedArg},
+		Resolve: func(p ResolveParams) (any, error) {
+			if t := p.Source.(*Type); t.object != nil {
+				return t.object.Fields(), nil
+			}
+			return nil, nil
+		},
+	})
+	typeObj.AddField(&FieldDefinition{
+		Name: "interfaces",
+		Type: ListOf(NonNull(ObjectType(typeObj))),
+		Resolve: func(p ResolveParams) (any, error) {
+			if t := p.Source.(*Type); t.object != nil {
+				return []*Type{}, nil
+			}
+			return nil, nil
+		},
+	})
+	typeObj.AddField(&FieldDefinition{Name: "possibleTypes", Type: ListOf(NonNull(ObjectType(typeObj))), Resolve: null})
+	typeObj.AddField(&FieldDefinition{
+		Name:    "enumValues",
+		Type:    ListOf(NonNull(ObjectType(enumValueObj))),
+		Args:    []*ArgumentDefinition{includeDeprecatedArg},
+		Resolve: null,
+	})
+	typeObj.AddField(&FieldDefinition{
+		Name:    "inputFields",
+		Type:    ListOf(NonNull(ObjectType(inputValueObj))),
+		Args:    []*ArgumentDefinition{includeDeprecatedArg},
+		Resolve: null,
+	})
+	typeObj.AddField(&FieldDefinition{
+		Name: "ofType",
+		Type: ObjectType(typeObj),
+		Resolve: func(p ResolveParams) (any, error) {
+			t := p.Source.(*Type)
+			switch {
+			case t.nonNull != nil:
+				return t.nonNull, nil
+			case t.list != nil:
+				return t.list, nil
+			}
+			return nil, nil
+		},
+	})
+
+	// __Field
+	// ---
+	fieldObj.AddField(&FieldDefinition{
+		Name: "name",
+		Type: NonNull(str),
+		Resolve: func(p ResolveParams) (any, error) {
+			return p.Source.(*FieldDefinition).Name, nil
+		},
+	})
+	fieldObj.AddField(&FieldDefinition{
+		Name: "description",
+		Type: str,
+		Resolve: func(p ResolveParams) (any, error) {
+			if d := p.Source.(*FieldDefinition).Description; d != "" {
+				return d, nil
+			}
+			return nil, nil
+		},
+	})
+	fieldObj.AddField(&FieldDefinition{
+		Name: "args",
+		Type: NonNull(ListOf(NonNull(ObjectType(inputValueObj)))),
+		Args: []*ArgumentDefinition{includeDeprecatedArg},
+		Resolve: func(p ResolveParams) (any, error) {
+			args := p.Source.(*FieldDefinition).Args
+			if args == nil {
+				args = []*ArgumentDefinition{}
+			}
+			return args, nil
+		},
+	})
+	fieldObj.AddField(&FieldDefinition{
+		Name: "type",
+		Type: NonNull(ObjectType(typeObj)),
+		Resolve: func(p ResolveParams) (any, error) {
+			return p.Source.(*FieldDefinition).Type, nil
+		},
+	})
+	fieldObj.AddField(&FieldDefinition{Name: "isDeprecated", Type: NonNull(boolean), Resolve: falseValue})
+	fieldObj.AddField(&FieldDefinition{Name: "deprecationReason", Type: str, Resolve: null})
+
+	// __InputValue
+	// ---
+	inputValueObj.AddField(&FieldDefinition{
+		Name: "name",
+		Type: NonNull(str),
+		Resolve: func(p ResolveParams) (any, error) {
+			return p.Source.(*ArgumentDefinition).Name, nil
+		},
+	})
+	inputValueObj.AddField(&FieldDefinition{
+		Name: "description",
+		Type: str,
+		Resolve: func(p ResolveParams) (any, error) {
+			if d := p.Source.(*ArgumentDefinition).Description; d != "" {
+				return d, nil
+			}
+			return nil, nil
+		},
+	})
+	inputValueObj.AddField(&FieldDefinition{
+		Name: "type",
+		Type: NonNull(ObjectType(typeObj)),
+		Resolve: func(p ResolveParams) (any, error) {
+			return p.Source.(*ArgumentDefinition).Type, nil
+		},
+	})
+	inputValueObj.AddField(&FieldDefinition{
+		Name: "defaultValue",
+		Type: str,
+		Resolve: func(p ResolveParams) (any, error) {
+			value := p.Source.(*ArgumentDefinition).DefaultValue
+			if value == nil {
+				return nil, nil
+			}
+			raw, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			return string(raw), nil
+		},
+	})
+	inputValueObj.AddField(&FieldDefinition{Name: "isDeprecated", Type: NonNull(boolean), Resolve: falseValue})
+	inputValueObj.AddField(&FieldDefinition{Name: "deprecationReason", Type: str, Resolve: null})
+
+	// __EnumValue
+	// ---
+	enumValueObj.AddField(&FieldDefinition{Name: "name", Type: NonNull(str)})
+	enumValueObj.AddField(&FieldDefinition{Name: "description", Type: str})
+	enumValueObj.AddField(&FieldDefinition{Name: "isDeprecated", Type: NonNull(boolean), Resolve: falseValue})
+	enumValueObj.AddField(&FieldDefinition{Name: "deprecationReason", Type: str})
+
+	// __Directive
+	// ---
+	directiveObj.AddField(&FieldDefinition{
+		Name: "name",
+		Type: NonNull(str),
+		Resolve: func(p ResolveParams) (any, error) {
+			return p.Source.(*directiveDefinition).name, nil
+		},
+	})
+	directiveObj.AddField(&FieldDefinition{
+		Name: "description",
+		Type: str,
+		Resolve: func(p ResolveParams) (any, error) {
+			return p.Source.(*directiveDefinition).description, nil
+		},
+	})
+	directiveObj.AddField(&FieldDefinition{
+		Name: "locations",
+		Type: NonNull(ListOf(NonNull(str))),
+		Resolve: func(p ResolveParams) (any, error) {
+			return p.Source.(*directiveDefinition).locations, nil
+		},
+	})
+	directiveObj.AddField(&FieldDefinition{
+		Name: "args",
+		Type: NonNull(ListOf(NonNull(ObjectType(inputValueObj)))),
+		Args: []*ArgumentDefinition{includeDeprecatedArg},
+		Resolve: func(p ResolveParams) (any, error) {
+			return p.Source.(*directiveDefinition).args, nil
+		},
+	})
+	directiveObj.AddField(&FieldDefinition{Name: "isRepeatable", Type: NonNull(boolean), Resolve: falseValue})
+
+	ifArg := []*ArgumentDefinition{{Name: "if", Type: NonNull(boolean)}}
+	builtinDirectives = []*directiveDefinition{
+		{
+			name:        "include",
+			description: "Directs the executor to include this field or fragment only when the `if` argument is true.",
+			locations:   []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"},
+			args:        ifArg,
+		},
+		{
+			name:        "skip",
+			description: "Directs the executor to skip this field or fragment when the `if` argument is true.",
+			locations:   []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"},
+			args:        ifArg,
+		},
+	}
+
+	// query meta fields
+	// ---
+	schemaMetaField = &FieldDefinition{
+		Name: "__schema",
+		Type: NonNull(ObjectType(schemaObj)),
+		Resolve: func(p ResolveParams) (any, error) {
+			return p.exec.schema, nil
+		},
+	}
+
+	typeMetaField = &FieldDefinition{
+		Name: "__type",
+		Type: ObjectType(typeObj),
+		Args: []*ArgumentDefinition{{Name: "name", Type: NonNull(str)}},
+		Resolve: func(p ResolveParams) (any, error) {
+			name, _ := p.Args["name"].(string)
+			for _, t := range p.exec.schema.namedTypes() {
+				if t.String() == name {
+					return t, nil
+				}
+			}
+			return nil, nil
+		},
+	}
+}
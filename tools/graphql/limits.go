@@ -0,0 +1,109 @@
+package graphql
+
+import "fmt"
+
+// checkLimits validates the operation against the schema MaxDepth and MaxComplexity limits.
+//
+// It also rejects cyclic fragment spreads since they would
+// otherwise result in an infinite execution.
+//
+// The introspection meta fields are not counted because they
+// don't access the application data.
+func (s *Schema) checkLimits(doc *Document, op *OperationDefinition) error {
+	m := &measurer{
+		doc:        doc,
+		fragments:  map[string]measure{},
+		inProgress: map[string]struct{}{},
+	}
+
+	result := m.selectionSet(op.SelectionSet)
+	if m.err != nil {
+		return m.err
+	}
+
+	if s.MaxDepth > 0 && result.depth > s.MaxDepth {
+		return fmt.Errorf("The operation depth %d exceeds the max allowed depth %d.", result.depth, s.MaxDepth)
+	}
+
+	if s.MaxComplexity > 0 && result.complexity > s.MaxComplexity {
+		return fmt.Errorf("The operation complexity %d exceeds the max allowed complexity %d.", result.complexity, s.MaxComplexity)
+	}
+
+	return nil
+}
+
+type measure struct {
+	depth      int
+	complexity int
+}
+
+// measurer calculates the max nesting depth and the total
+// number of fields of an operation selection set.
+//
+// The fragment results are cached to avoid exponential
+// evaluation time for repeatedly spread nested fragments.
+type measurer struct {
+	doc        *Document
+	fragments  map[string]measure
+	inProgress map[string]struct{}
+	err        error
+}
+
+func (m *measurer) selectionSet(set SelectionSet) measure {
+	var result measure
+
+	for _, selection := range set {
+		var item measure
+
+		switch v := selection.(type) {
+		case *Field:
+			if v.Name == "__schema" || v.Name == "__type" {
+				continue
+			}
+			item = m.selectionSet(v.SelectionSet)
+			item.depth++
+			item.complexity++
+		case *InlineFragment:
+			item = m.selectionSet(v.SelectionSet)
+		case *FragmentSpread:
+			item = m.fragment(v.Name)
+		}
+
+		if item.depth > result.depth {
+			result.depth = item.depth
+		}
+
+		// cap to avoid int overflow with repeatedly spread fragments
+		result.complexity = min(result.complexity+item.complexity, maxMeasureComplexity)
+	}
+
+	return result
+}
+
+func (m *measurer) fragment(name string) measure {
+	if cached, ok := m.fragments[name]; ok {
+		return cached
+	}
+
+	fragment := m.doc.Fragments[name]
+	if fragment == nil {
+		return measure{}
+	}
+
+	if _, ok := m.inProgress[name]; ok {
+		if m.err == nil {
+			m.err = fmt.Errorf("Cannot spread fragment %q within itself.", name)
+		}
+		return measure{}
+	}
+
+	m.inProgress[name] = struct{}{}
+	result := m.selectionSet(fragment.SelectionSet)
+	delete(m.inProgress, name)
+
+	m.fragments[name] = result
+
+	return result
+}
+
+const maxMeasureComplexity = 1 << 30
@@ -0,0 +1,713 @@
+package graphql
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Parse parses the provided GraphQL query into a new executable [Document].
+//
+// Only executable definitions (operations and fragments) are supported.
+func Parse(query string) (*Document, error) {
+	p := &parser{lexer: &lexer{src: query}}
+
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	doc := &Document{Fragments: map[string]*FragmentDefinition{}}
+
+	for p.token.kind != tokenEOF {
+		switch {
+		case p.token.is(tokenPunct, "{"):
+			// query shorthand
+			set, err := p.parseSelectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, &OperationDefinition{
+				Operation:    OperationQuery,
+				SelectionSet: set,
+			})
+		case p.token.is(tokenName, OperationQuery), p.token.is(tokenName, OperationMutation):
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, op)
+		case p.token.is(tokenName, "fragment"):
+			fragment, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := doc.Fragments[fragment.Name]; ok {
+				return nil, fmt.Errorf("duplicated fragment %q", fragment.Name)
+			}
+			doc.Fragments[fragment.Name] = fragment
+		default:
+			return nil, p.unexpected()
+		}
+	}
+
+	if len(doc.Operations) == 0 {
+		return nil, errors.New("the document must contain at least one operation")
+	}
+
+	return doc, nil
+}
+
+// -------------------------------------------------------------------
+
+type parser struct {
+	lexer *lexer
+	token token
+}
+
+func (p *parser) next() error {
+	t, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+
+	p.token = t
+
+	return nil
+}
+
+func (p *parser) unexpected() error {
+	if p.token.kind == tokenEOF {
+		return fmt.Errorf("unexpected end of document at position %d", p.token.pos)
+	}
+
+	return fmt.Errorf("unexpected %q at position %d", p.token.value, p.token.pos)
+}
+
+// expect checks whether the current token matches the specified
+// kind and value (if not empty) and advances to the next token.
+func (p *parser) expect(kind tokenKind, value string) (string, error) {
+	if p.token.kind != kind || (value != "" && p.token.value != value) {
+		return "", p.unexpected()
+	}
+
+	v := p.token.value
+
+	return v, p.next()
+}
+
+// skip advances to the next token if the current one is the specified punctuator.
+func (p *parser) skip(punct string) (bool, error) {
+	if !p.token.is(tokenPunct, punct) {
+		return false, nil
+	}
+
+	return true, p.next()
+}
+
+func (p *parser) parseOperation() (*OperationDefinition, error) {
+	op := &OperationDefinition{Operation: p.token.value}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	if p.token.kind == tokenName {
+		op.Name = p.token.value
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+
+	if ok, err := p.skip("("); err != nil {
+		return nil, err
+	} else if ok {
+		for !p.token.is(tokenPunct, ")") {
+			def, err := p.parseVariableDefinition()
+			if err != nil {
+				return nil, err
+			}
+			op.Variables = append(op.Variables, def)
+		}
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+
+	// operation directives are parsed but not used
+	if _, err := p.parseDirectives(); err != nil {
+		return nil, err
+	}
+
+	set, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.SelectionSet = set
+
+	return op, nil
+}
+
+func (p *parser) parseVariableDefinition() (*VariableDefinition, error) {
+	if _, err := p.expect(tokenPunct, "$"); err != nil {
+		return nil, err
+	}
+
+	name, err := p.expect(tokenName, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := p.expect(tokenPunct, ":"); err != nil {
+		return nil, err
+	}
+
+	typ, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+
+	def := &VariableDefinition{Name: name, Type: typ}
+
+	if ok, err := p.skip("="); err != nil {
+		return nil, err
+	} else if ok {
+		def.DefaultValue, err = p.parseValue(true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return def, nil
+}
+
+// parseType parses a type reference (eg. "[String!]!") and returns it as plain string.
+func (p *parser) parseType() (string, error) {
+	var result string
+
+	if ok, err := p.skip("["); err != nil {
+		return "", err
+	} else if ok {
+		inner, err := p.parseType()
+		if err != nil {
+			return "", err
+		}
+		if _, err := p.expect(tokenPunct, "]"); err != nil {
+			return "", err
+		}
+		result = "[" + inner + "]"
+	} else {
+		name, err := p.expect(tokenName, "")
+		if err != nil {
+			return "", err
+		}
+		result = name
+	}
+
+	if ok, err := p.skip("!"); err != nil {
+		return "", err
+	} else if ok {
+		result += "!"
+	}
+
+	return result, nil
+}
+
+func (p *parser) parseFragment() (*FragmentDefinition, error) {
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	name, err := p.expect(tokenName, "")
+	if err != nil {
+		return nil, err
+	}
+	if name == "on" {
+		return nil, errors.New(`the fragment name must not be "on"`)
+	}
+
+	if _, err := p.expect(tokenName, "on"); err != nil {
+		return nil, err
+	}
+
+	typeCondition, err := p.expect(tokenName, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := p.parseDirectives(); err != nil {
+		return nil, err
+	}
+
+	set, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+
+	return &FragmentDefinition{
+		Name:          name,
+		TypeCondition: typeCondition,
+		SelectionSet:  set,
+	}, nil
+}
+
+func (p *parser) parseSelectionSet() (SelectionSet, error) {
+	if _, err := p.expect(tokenPunct, "{"); err != nil {
+		return nil, err
+	}
+
+	set := SelectionSet{}
+
+	for !p.token.is(tokenPunct, "}") {
+		if p.token.kind == tokenEOF {
+			return nil, p.unexpected()
+		}
+
+		selection, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, selection)
+	}
+
+	if len(set) == 0 {
+		return nil, fmt.Errorf("empty selection set at position %d", p.token.pos)
+	}
+
+	return set, p.next()
+}
+
+func (p *parser) parseSelection() (Selection, error) {
+	if p.token.kind == tokenSpread {
+		return p.parseFragmentSelection()
+	}
+
+	field := &Field{}
+
+	name, err := p.expect(tokenName, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if ok, err := p.skip(":"); err != nil {
+		return nil, err
+	} else if ok {
+		field.Alias = name
+		name, err = p.expect(tokenName, "")
+		if err != nil {
+			return nil, err
+		}
+	}
+	field.Name = name
+
+	field.Arguments, err = p.parseArguments()
+	if err != nil {
+		return nil, err
+	}
+
+	field.Directives, err = p.parseDirectives()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.token.is(tokenPunct, "{") {
+		field.SelectionSet, err = p.parseSelectionSet()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return field, nil
+}
+
+func (p *parser) parseFragmentSelection() (Selection, error) {
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	// named fragment spread
+	if p.token.kind == tokenName && p.token.value != "on" {
+		spread := &FragmentSpread{Name: p.token.value}
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		var err error
+		spread.Directives, err = p.parseDirectives()
+		if err != nil {
+			return nil, err
+		}
+
+		return spread, nil
+	}
+
+	// inline fragment
+	fragment := &InlineFragment{}
+
+	if p.token.is(tokenName, "on") {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		typeCondition, err := p.expect(tokenName, "")
+		if err != nil {
+			return nil, err
+		}
+		fragment.TypeCondition = typeCondition
+	}
+
+	var err error
+
+	fragment.Directives, err = p.parseDirectives()
+	if err != nil {
+		return nil, err
+	}
+
+	fragment.SelectionSet, err = p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+
+	return fragment, nil
+}
+
+func (p *parser) parseArguments() ([]*Argument, error) {
+	if ok, err := p.skip("("); err != nil || !ok {
+		return nil, err
+	}
+
+	args := []*Argument{}
+
+	for !p.token.is(tokenPunct, ")") {
+		name, err := p.expect(tokenName, "")
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := p.expect(tokenPunct, ":"); err != nil {
+			return nil, err
+		}
+
+		value, err := p.parseValue(false)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, &Argument{Name: name, Value: value})
+	}
+
+	return args, p.next()
+}
+
+func (p *parser) parseDirectives() ([]*Directive, error) {
+	var directives []*Directive
+
+	for p.token.is(tokenPunct, "@") {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		name, err := p.expect(tokenName, "")
+		if err != nil {
+			return nil, err
+		}
+
+		args, err := p.parseArguments()
+		if err != nil {
+			return nil, err
+		}
+
+		directives = append(directives, &Directive{Name: name, Arguments: args})
+	}
+
+	return directives, nil
+}
+
+// parseValue parses a single input value literal.
+//
+// If isConst is true, variable references are not allowed.
+func (p *parser) parseValue(isConst bool) (any, error) {
+	t := p.token
+
+	switch t.kind {
+	case tokenInt:
+		v, err := strconv.ParseInt(t.value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid int value %q at position %d", t.value, t.pos)
+		}
+		return v, p.next()
+	case tokenFloat:
+		v, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid float value %q at position %d", t.value, t.pos)
+		}
+		return v, p.next()
+	case tokenString:
+		return t.value, p.next()
+	case tokenName:
+		var v any
+		switch t.value {
+		case "true":
+			v = true
+		case "false":
+			v = false
+		case "null":
+			v = nil
+		default:
+			v = EnumValue(t.value)
+		}
+		return v, p.next()
+	case tokenPunct:
+		switch t.value {
+		case "$":
+			if isConst {
+				return nil, fmt.Errorf("unexpected variable at position %d", t.pos)
+			}
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			name, err := p.expect(tokenName, "")
+			if err != nil {
+				return nil, err
+			}
+			return Variable(name), nil
+		case "[":
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			list := []any{}
+			for !p.token.is(tokenPunct, "]") {
+				item, err := p.parseValue(isConst)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, item)
+			}
+			return list, p.next()
+		case "{":
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			obj := map[string]any{}
+			for !p.token.is(tokenPunct, "}") {
+				name, err := p.expect(tokenName, "")
+				if err != nil {
+					return nil, err
+				}
+				if _, err := p.expect(tokenPunct, ":"); err != nil {
+					return nil, err
+				}
+				obj[name], err = p.parseValue(isConst)
+				if err != nil {
+					return nil, err
+				}
+			}
+			return obj, p.next()
+		}
+	}
+
+	return nil, p.unexpected()
+}
+
+// -------------------------------------------------------------------
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenSpread
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func (t token) is(kind tokenKind, value string) bool {
+	return t.kind == kind && t.value == value
+}
+
+type lexer struct {
+	src string
+	pos int
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipIgnored()
+
+	if l.pos >= len(l.src) {
+		return token{kind: tokenEOF, pos: l.pos}, nil
+	}
+
+	start := l.pos
+	ch := l.src[l.pos]
+
+	switch {
+	case strings.IndexByte("!$&()=:@[]{}|", ch) >= 0:
+		l.pos++
+		return token{kind: tokenPunct, value: string(ch), pos: start}, nil
+	case ch == '.':
+		if strings.HasPrefix(l.src[l.pos:], "...") {
+			l.pos += 3
+			return token{kind: tokenSpread, value: "...", pos: start}, nil
+		}
+	case ch == '_' || isLetter(ch):
+		for l.pos < len(l.src) && (l.src[l.pos] == '_' || isLetter(l.src[l.pos]) || isDigit(l.src[l.pos])) {
+			l.pos++
+		}
+		return token{kind: tokenName, value: l.src[start:l.pos], pos: start}, nil
+	case ch == '-' || isDigit(ch):
+		return l.readNumber()
+	case ch == '"':
+		return l.readString()
+	}
+
+	return token{}, fmt.Errorf("unexpected character %q at position %d", ch, start)
+}
+
+// skipIgnored skips whitespaces, commas, comments and unicode BOM.
+func (l *lexer) skipIgnored() {
+	for l.pos < len(l.src) {
+		switch l.src[l.pos] {
+		case ' ', '\t', '\n', '\r', ',':
+			l.pos++
+		case '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' && l.src[l.pos] != '\r' {
+				l.pos++
+			}
+		default:
+			if strings.HasPrefix(l.src[l.pos:], "\ufeff") {
+				l.pos += len("\ufeff")
+				continue
+			}
+			return
+		}
+	}
+}
+
+func (l *lexer) readNumber() (token, error) {
+	start := l.pos
+	kind := tokenInt
+
+	if l.src[l.pos] == '-' {
+		l.pos++
+	}
+
+	if !l.readDigits() {
+		return token{}, fmt.Errorf("invalid number at position %d", start)
+	}
+
+	if l.pos < len(l.src) && l.src[l.pos] == '.' {
+		kind = tokenFloat
+		l.pos++
+		if !l.readDigits() {
+			return token{}, fmt.Errorf("invalid number at position %d", start)
+		}
+	}
+
+	if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+		kind = tokenFloat
+		l.pos++
+		if l.pos < len(l.src) && (l.src[l.pos] == '+' || l.src[l.pos] == '-') {
+			l.pos++
+		}
+		if !l.readDigits() {
+			return token{}, fmt.Errorf("invalid number at position %d", start)
+		}
+	}
+
+	return token{kind: kind, value: l.src[start:l.pos], pos: start}, nil
+}
+
+func (l *lexer) readDigits() bool {
+	start := l.pos
+
+	for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+		l.pos++
+	}
+
+	return l.pos > start
+}
+
+func (l *lexer) readString() (token, error) {
+	start := l.pos
+
+	// block string
+	if strings.HasPrefix(l.src[l.pos:], `"""`) {
+		end := strings.Index(l.src[l.pos+3:], `"""`)
+		if end < 0 {
+			return token{}, fmt.Errorf("unterminated string at position %d", start)
+		}
+		value := l.src[l.pos+3 : l.pos+3+end]
+		l.pos += end + 6
+		return token{kind: tokenString, value: strings.TrimSpace(value), pos: start}, nil
+	}
+
+	l.pos++ // opening quote
+
+	var sb strings.Builder
+
+	for l.pos < len(l.src) {
+		ch := l.src[l.pos]
+
+		switch ch {
+		case '"':
+			l.pos++
+			return token{kind: tokenString, value: sb.String(), pos: start}, nil
+		case '\n', '\r':
+			return token{}, fmt.Errorf("unterminated string at position %d", start)
+		case '\\':
+			if l.pos+1 >= len(l.src) {
+				return token{}, fmt.Errorf("unterminated string at position %d", start)
+			}
+			esc := l.src[l.pos+1]
+			l.pos += 2
+			switch esc {
+			case '"', '\\', '/':
+				sb.WriteByte(esc)
+			case 'b':
+				sb.WriteByte('\b')
+			case 'f':
+				sb.WriteByte('\f')
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case 'u':
+				if l.pos+4 > len(l.src) {
+					return token{}, fmt.Errorf("invalid unicode escape sequence at position %d", l.pos-2)
+				}
+				code, err := strconv.ParseUint(l.src[l.pos:l.pos+4], 16, 32)
+				if err != nil {
+					return token{}, fmt.Errorf("invalid unicode escape sequence at position %d", l.pos-2)
+				}
+				sb.WriteRune(rune(code))
+				l.pos += 4
+			default:
+				return token{}, fmt.Errorf("invalid escape sequence at position %d", l.pos-2)
+			}
+		default:
+			r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+			sb.WriteRune(r)
+			l.pos += size
+		}
+	}
+
+	return token{}, fmt.Errorf("unterminated string at position %d", start)
+}
+
+func isLetter(ch byte) bool {
+	return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
+
+func isDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
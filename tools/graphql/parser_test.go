@@ -0,0 +1,112 @@
+package graphql_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/graphql"
+)
+
+func TestParse(t *testing.T) {
+	scenarios := []struct {
+		name        string
+		query       string
+		expectError bool
+		expected    string
+	}{
+		{"empty", "", true, ""},
+		{"only fragment", "fragment a on A { id }", true, ""},
+		{"unterminated selection set", "{ a { b }", true, ""},
+		{"empty selection set", "{ }", true, ""},
+		{"invalid character", "{ a% }", true, ""},
+		{"unterminated string", `{ a(b: "c) }`, true, ""},
+		{"variable in default value", `query ($a: Int = $b) { a }`, true, ""},
+		{"fragment named on", `{ a } fragment on on A { id }`, true, ""},
+		{
+			"query shorthand",
+			"{ a, b }",
+			false,
+			`[{"Operation":"query","Name":"","Variables":null,"SelectionSet":[{"Alias":"","Name":"a","Arguments":null,"Directives":null,"SelectionSet":null},{"Alias":"","Name":"b","Arguments":null,"Directives":null,"SelectionSet":null}]}]`,
+		},
+		{
+			"named query with variables, aliases, arguments and directives",
+			`# comment
+			query test($id: ID!, $tags: [String!] = ["x"]) {
+				item: a(id: $id, n: -1.5e2, flag: true, e: ASC, s: "q\"A", o: {x: [1, null]}) @include(if: true) {
+					b
+				}
+			}`,
+			false,
+			`[{"Operation":"query","Name":"test","Variables":[{"Name":"id","Type":"ID!","DefaultValue":null},{"Name":"tags","Type":"[String!]","DefaultValue":["x"]}],"SelectionSet":[{"Alias":"item","Name":"a","Arguments":[{"Name":"id","Value":"id"},{"Name":"n","Value":-150},{"Name":"flag","Value":true},{"Name":"e","Value":"ASC"},{"Name":"s","Value":"q\"A"},{"Name":"o","Value":{"x":[1,null]}}],"Directives":[{"Name":"include","Arguments":[{"Name":"if","Value":true}]}],"SelectionSet":[{"Alias":"","Name":"b","Arguments":null,"Directives":null,"SelectionSet":null}]}]}]`,
+		},
+		{
+			"mutation with fragments",
+			`mutation { a { ...f ... on B { c } ... @skip(if: false) { d } } } fragment f on A { e }`,
+			false,
+			`[{"Operation":"mutation","Name":"","Variables":null,"SelectionSet":[{"Alias":"","Name":"a","Arguments":null,"Directives":null,"SelectionSet":[{"Name":"f","Directives":null},{"TypeCondition":"B","Directives":null,"SelectionSet":[{"Alias":"","Name":"c","Arguments":null,"Directives":null,"SelectionSet":null}]},{"TypeCondition":"","Directives":[{"Name":"skip","Arguments":[{"Name":"if","Value":false}]}],"SelectionSet":[{"Alias":"","Name":"d","Arguments":null,"Directives":null,"SelectionSet":null}]}]}]}]`,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			doc, err := graphql.Parse(s.query)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			raw, err := json.Marshal(doc.Operations)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if str := string(raw); str != s.expected {
+				t.Fatalf("Expected\n%s\ngot\n%s", s.expected, str)
+			}
+		})
+	}
+}
+
+func TestDocumentOperation(t *testing.T) {
+	doc, err := graphql.Parse(`query a { x } query b { y }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if op := doc.Operation(""); op != nil {
+		t.Fatalf("Expected nil operation for multi-operations document, got %v", op)
+	}
+
+	if op := doc.Operation("b"); op == nil || op.Name != "b" {
+		t.Fatalf("Expected operation b, got %v", op)
+	}
+
+	if op := doc.Operation("c"); op != nil {
+		t.Fatalf("Expected nil operation, got %v", op)
+	}
+}
+
+func TestResolveValue(t *testing.T) {
+	value := map[string]any{
+		"a": graphql.Variable("x"),
+		"b": []any{graphql.EnumValue("DESC"), graphql.Variable("missing"), 1},
+	}
+
+	result := graphql.ResolveValue(value, map[string]any{"x": "test"})
+
+	raw, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"a":"test","b":["DESC",null,1]}`
+
+	if str := string(raw); str != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, str)
+	}
+}
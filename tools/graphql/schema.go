@@ -0,0 +1,302 @@
+package graphql
+
+import (
+	"sort"
+	"strings"
+)
+
+// Built-in scalar type names.
+const (
+	ScalarString  = "String"
+	ScalarInt     = "Int"
+	ScalarFloat   = "Float"
+	ScalarBoolean = "Boolean"
+	ScalarID      = "ID"
+)
+
+var builtinScalars = map[string]struct{}{
+	ScalarString:  {},
+	ScalarInt:     {},
+	ScalarFloat:   {},
+	ScalarBoolean: {},
+	ScalarID:      {},
+}
+
+// Type describes a single field or argument type reference.
+//
+// Use [Scalar], [ObjectType], [ListOf] and [NonNull] to construct new types.
+type Type struct {
+	// only one of the below is set
+	scalar  string
+	object  *Object
+	list    *Type
+	nonNull *Type
+}
+
+// Scalar returns a new named scalar type reference (eg. "String", "JSON").
+func Scalar(name string) *Type {
+	return &Type{scalar: name}
+}
+
+// ObjectType returns a new object type reference.
+func ObjectType(obj *Object) *Type {
+	return &Type{object: obj}
+}
+
+// ListOf returns a new list type reference wrapping t.
+func ListOf(t *Type) *Type {
+	return &Type{list: t}
+}
+
+// NonNull returns a new non-null type reference wrapping t.
+func NonNull(t *Type) *Type {
+	return &Type{nonNull: t}
+}
+
+// Object returns the referenced object type (unwrapping any list or non-null modifiers).
+//
+// Returns nil if t references a scalar type.
+func (t *Type) Object() *Object {
+	switch {
+	case t.nonNull != nil:
+		return t.nonNull.Object()
+	case t.list != nil:
+		return t.list.Object()
+	default:
+		return t.object
+	}
+}
+
+// String returns the SDL representation of the type (eg. "[String!]!").
+func (t *Type) String() string {
+	switch {
+	case t.nonNull != nil:
+		return t.nonNull.String() + "!"
+	case t.list != nil:
+		return "[" + t.list.String() + "]"
+	case t.object != nil:
+		return t.object.Name
+	default:
+		return t.scalar
+	}
+}
+
+// ResolveParams defines the arguments passed to the field resolvers.
+type ResolveParams struct {
+	// Source is the resolved value of the parent object.
+	Source any
+
+	// Args contains the field arguments (including their default values).
+	Args map[string]any
+
+	// Field is the currently resolved document field.
+	Field *Field
+
+	exec *executor
+}
+
+// CollectFields returns the flattened list of fields of the provided
+// selection set (merging fragments and applying the @skip and @include directives).
+func (p ResolveParams) CollectFields(set SelectionSet) []*Field {
+	if p.exec == nil {
+		return nil
+	}
+
+	return p.exec.collectFields(nil, set, nil)
+}
+
+// ResolveFunc defines a single field resolver function.
+type ResolveFunc func(p ResolveParams) (any, error)
+
+// ArgumentDefinition describes a single field argument.
+type ArgumentDefinition struct {
+	Name         string
+	Description  string
+	Type         *Type
+	DefaultValue any
+}
+
+// FieldDefinition describes a single object field.
+type FieldDefinition struct {
+	Name        string
+	Description string
+	Type        *Type
+	Args        []*ArgumentDefinition
+
+	// Resolve is the field value resolver.
+	//
+	// If not set, the value is loaded from the parent source
+	// when it is a map[string]any (using the field name as key).
+	Resolve ResolveFunc
+}
+
+// Arg returns the field argument definition with the specified name (if exists).
+func (f *FieldDefinition) Arg(name string) *ArgumentDefinition {
+	for _, arg := range f.Args {
+		if arg.Name == name {
+			return arg
+		}
+	}
+
+	return nil
+}
+
+// Object describes a single GraphQL object type.
+type Object struct {
+	Name        string
+	Description string
+
+	fields []*FieldDefinition
+}
+
+// NewObject creates a new empty object type.
+func NewObject(name string, description string) *Object {
+	return &Object{Name: name, Description: description}
+}
+
+// AddField registers a new object field (replacing any existing field with the same name).
+func (o *Object) AddField(field *FieldDefinition) {
+	for i, f := range o.fields {
+		if f.Name == field.Name {
+			o.fields[i] = field
+			return
+		}
+	}
+
+	o.fields = append(o.fields, field)
+}
+
+// Field returns the object field with the specified name (if exists).
+func (o *Object) Field(name string) *FieldDefinition {
+	for _, f := range o.fields {
+		if f.Name == name {
+			return f
+		}
+	}
+
+	return nil
+}
+
+// Fields returns the list of the registered object fields.
+func (o *Object) Fields() []*FieldDefinition {
+	return o.fields
+}
+
+// Schema defines the GraphQL schema root operation types.
+type Schema struct {
+	Query    *Object
+	Mutation *Object
+
+	// MaxDepth is the max allowed nesting level of the operation
+	// selection sets (0 means no limit).
+	MaxDepth int
+
+	// MaxComplexity is the max allowed total number of selected
+	// operation fields, including the fields of the expanded
+	// fragments (0 means no limit).
+	MaxComplexity int
+
+	// Introspection enables the "__schema" and "__type" meta fields.
+	Introspection bool
+}
+
+// SDL returns the GraphQL schema definition language representation of the schema.
+func (s *Schema) SDL() string {
+	objects, scalars := s.collectTypes()
+
+	var sb strings.Builder
+
+	sb.WriteString("schema {\n")
+	if s.Query != nil {
+		sb.WriteString("  query: " + s.Query.Name + "\n")
+	}
+	if s.Mutation != nil {
+		sb.WriteString("  mutation: " + s.Mutation.Name + "\n")
+	}
+	sb.WriteString("}\n")
+
+	for _, name := range scalars {
+		if _, ok := builtinScalars[name]; ok {
+			continue
+		}
+		sb.WriteString("\nscalar " + name + "\n")
+	}
+
+	for _, o := range objects {
+		sb.WriteString("\n")
+		writeDescription(&sb, o.Description, "")
+		sb.WriteString("type " + o.Name + " {\n")
+		for _, f := range o.fields {
+			writeDescription(&sb, f.Description, "  ")
+			sb.WriteString("  " + f.Name)
+			if len(f.Args) > 0 {
+				args := make([]string, len(f.Args))
+				for i, arg := range f.Args {
+					args[i] = arg.Name + ": " + arg.Type.String()
+				}
+				sb.WriteString("(" + strings.Join(args, ", ") + ")")
+			}
+			sb.WriteString(": " + f.Type.String() + "\n")
+		}
+		sb.WriteString("}\n")
+	}
+
+	return sb.String()
+}
+
+// collectTypes returns all object types reachable from the schema
+// root operation types and the sorted names of the referenced scalars.
+func (s *Schema) collectTypes() ([]*Object, []string) {
+	objects := []*Object{}
+	scalars := map[string]struct{}{}
+	visited := map[*Object]struct{}{}
+
+	var visit func(o *Object)
+	visit = func(o *Object) {
+		if o == nil {
+			return
+		}
+		if _, ok := visited[o]; ok {
+			return
+		}
+		visited[o] = struct{}{}
+		objects = append(objects, o)
+
+		for _, f := range o.fields {
+			for _, arg := range f.Args {
+				collectScalar(arg.Type, scalars)
+			}
+			collectScalar(f.Type, scalars)
+			visit(f.Type.Object())
+		}
+	}
+	visit(s.Query)
+	visit(s.Mutation)
+
+	scalarNames := make([]string, 0, len(scalars))
+	for name := range scalars {
+		scalarNames = append(scalarNames, name)
+	}
+	sort.Strings(scalarNames)
+
+	return objects, scalarNames
+}
+
+func collectScalar(t *Type, scalars map[string]struct{}) {
+	switch {
+	case t.nonNull != nil:
+		collectScalar(t.nonNull, scalars)
+	case t.list != nil:
+		collectScalar(t.list, scalars)
+	case t.scalar != "":
+		scalars[t.scalar] = struct{}{}
+	}
+}
+
+func writeDescription(sb *strings.Builder, description string, indent string) {
+	if description == "" {
+		return
+	}
+
+	sb.WriteString(indent + `"""` + strings.ReplaceAll(description, `"""`, `\"""`) + `"""` + "\n")
+}
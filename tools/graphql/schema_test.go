@@ -0,0 +1,105 @@
+package graphql_test
+
+import (
+	"testing"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/graphql"
+)
+
+func TestTypeString(t *testing.T) {
+	obj := graphql.NewObject("Test", "")
+
+	scenarios := []struct {
+		typ      *graphql.Type
+		expected string
+	}{
+		{graphql.Scalar(graphql.ScalarString), "String"},
+		{graphql.ObjectType(obj), "Test"},
+		{graphql.NonNull(graphql.ObjectType(obj)), "Test!"},
+		{graphql.ListOf(graphql.Scalar("JSON")), "[JSON]"},
+		{graphql.NonNull(graphql.ListOf(graphql.NonNull(graphql.Scalar(graphql.ScalarInt)))), "[Int!]!"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.expected, func(t *testing.T) {
+			if str := s.typ.String(); str != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, str)
+			}
+		})
+	}
+}
+
+func TestTypeObject(t *testing.T) {
+	obj := graphql.NewObject("Test", "")
+
+	if o := graphql.NonNull(graphql.ListOf(graphql.ObjectType(obj))).Object(); o != obj {
+		t.Fatalf("Expected %v object, got %v", obj, o)
+	}
+
+	if o := graphql.ListOf(graphql.Scalar(graphql.ScalarString)).Object(); o != nil {
+		t.Fatalf("Expected nil object, got %v", o)
+	}
+}
+
+func TestObjectFields(t *testing.T) {
+	obj := graphql.NewObject("Test", "")
+	obj.AddField(&graphql.FieldDefinition{Name: "a", Type: graphql.Scalar(graphql.ScalarString)})
+	obj.AddField(&graphql.FieldDefinition{Name: "b", Type: graphql.Scalar(graphql.ScalarString)})
+	obj.AddField(&graphql.FieldDefinition{Name: "a", Type: graphql.Scalar(graphql.ScalarInt)}) // replace
+
+	if total := len(obj.Fields()); total != 2 {
+		t.Fatalf("Expected 2 fields, got %d", total)
+	}
+
+	if str := obj.Field("a").Type.String(); str != "Int" {
+		t.Fatalf("Expected the a field to be replaced, got type %q", str)
+	}
+
+	if f := obj.Field("missing"); f != nil {
+		t.Fatalf("Expected nil field, got %v", f)
+	}
+}
+
+func TestSchemaSDL(t *testing.T) {
+	item := graphql.NewObject("Item", "Item description.")
+	item.AddField(&graphql.FieldDefinition{Name: "id", Type: graphql.NonNull(graphql.Scalar(graphql.ScalarID))})
+	item.AddField(&graphql.FieldDefinition{Name: "data", Type: graphql.Scalar("JSON"), Description: "Raw data."})
+	item.AddField(&graphql.FieldDefinition{Name: "parent", Type: graphql.ObjectType(item)})
+
+	query := graphql.NewObject("Query", "")
+	query.AddField(&graphql.FieldDefinition{
+		Name: "items",
+		Type: graphql.NonNull(graphql.ListOf(graphql.ObjectType(item))),
+		Args: []*graphql.ArgumentDefinition{
+			{Name: "filter", Type: graphql.Scalar(graphql.ScalarString)},
+			{Name: "cursor", Type: graphql.Scalar("Cursor")},
+		},
+	})
+
+	schema := &graphql.Schema{Query: query}
+
+	expected := `schema {
+  query: Query
+}
+
+scalar Cursor
+
+scalar JSON
+
+type Query {
+  items(filter: String, cursor: Cursor): [Item]!
+}
+
+"""Item description."""
+type Item {
+  id: ID!
+  """Raw data."""
+  data: JSON
+  parent: Item
+}
+`
+
+	if sdl := schema.SDL(); sdl != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, sdl)
+	}
+}
@@ -22,8 +22,15 @@ type handlerPair[T any] struct {
 // Hook defines a concurrent safe structure for handling event hooks
 // (aka. callbacks propagation).
 type Hook[T any] struct {
-	mux      sync.RWMutex
-	handlers []*handlerPair[T]
+	mux       sync.RWMutex
+	handlers  []*handlerPair[T]
+	observers *Observers
+}
+
+// NewObservedHook creates a new Hook whose triggers and handler
+// errors are reported to the provided observers group.
+func NewObservedHook[T any](observers *Observers) *Hook[T] {
+	return &Hook[T]{observers: observers}
 }
 
 // PreAdd registers a new handler to the hook by prepending it to the existing queue.
@@ -87,7 +94,7 @@ func (h *Hook[T]) RemoveAll() {
 // The execution stops when:
 // - hook.StopPropagation is returned in one of the handlers
 // - any non-nil error is returned in one of the handlers
-func (h *Hook[T]) Trigger(data T, oneOffHandlers ...Handler[T]) error {
+func (h *Hook[T]) Trigger(data T, oneOffHandlers ...Handler[T]) (err error) {
 	h.mux.RLock()
 
 	handlers := make([]*handlerPair[T], 0, len(h.handlers)+len(oneOffHandlers))
@@ -105,17 +112,25 @@ func (h *Hook[T]) Trigger(data T, oneOffHandlers ...Handler[T]) error {
 	// is called recursively by the handlers
 	h.mux.RUnlock()
 
+	startSeq := h.observers.lastReportedSeq()
+
+	if finish := h.observers.notifyTrigger(data); finish != nil {
+		defer func() { finish(err) }()
+	}
+
 	for _, item := range handlers {
-		err := item.handler(data)
-		if err == nil {
+		handlerErr := item.handler(data)
+		if handlerErr == nil {
 			continue
 		}
 
-		if errors.Is(err, StopPropagation) {
+		if errors.Is(handlerErr, StopPropagation) {
 			return nil
 		}
 
-		return err
+		h.observers.notifyError(data, handlerErr, startSeq)
+
+		return handlerErr
 	}
 
 	return nil
@@ -2,6 +2,7 @@ package hook
 
 import (
 	"errors"
+	"sync"
 	"testing"
 )
 
@@ -177,3 +178,214 @@ func TestHookTriggerStopPropagation(t *testing.T) {
 		}
 	}
 }
+
+func TestHookErrorObservers(t *testing.T) {
+	var observed []error
+
+	observers := &Observers{}
+
+	id := observers.AddErrorObserver(func(data any, err error) {
+		if _, ok := data.(int); !ok {
+			t.Fatalf("Expected int data, got %T", data)
+		}
+		observed = append(observed, err)
+	})
+
+	demoErr := errors.New("demo")
+
+	h := NewObservedHook[int](observers)
+	h.Add(func(data int) error { return nil })
+
+	h.Trigger(1)
+	h.Trigger(1, func(data int) error { return StopPropagation })
+	h.Trigger(1, func(data int) error { return demoErr })
+
+	if len(observed) != 1 || observed[0] != demoErr {
+		t.Fatalf("Expected only the demo error to be observed, got %v", observed)
+	}
+
+	// hooks without the observers group
+	unobserved := Hook[int]{}
+	unobserved.Trigger(1, func(data int) error { return demoErr })
+
+	if len(observed) != 1 {
+		t.Fatalf("Expected the unobserved hook errors to be skipped, got %v", observed)
+	}
+
+	observers.RemoveErrorObserver(id)
+
+	h.Trigger(1, func(data int) error { return demoErr })
+
+	if len(observed) != 1 {
+		t.Fatalf("Expected no more observed errors after the observer removal, got %v", observed)
+	}
+}
+
+func TestHookErrorObserversNested(t *testing.T) {
+	var observed []any
+
+	observers := &Observers{}
+	observers.AddErrorObserver(func(data any, err error) {
+		observed = append(observed, data)
+	})
+
+	demoErr := errors.New("demo")
+
+	inner := NewObservedHook[string](observers)
+	inner.Add(func(data string) error { return demoErr })
+
+	middle := NewObservedHook[float64](observers)
+	middle.Add(func(data float64) error { return inner.Trigger("inner") })
+
+	outer := NewObservedHook[int](observers)
+	outer.Add(func(data int) error { return middle.Trigger(1.5) })
+
+	for i := 0; i < 2; i++ {
+		if err := outer.Trigger(1); err != demoErr {
+			t.Fatalf("[%d] Expected the demo error, got %v", i, err)
+		}
+	}
+
+	if len(observed) != 2 || observed[0] != "inner" || observed[1] != "inner" {
+		t.Fatalf("Expected the errors to be observed only at their origin, got %v", observed)
+	}
+
+	// the same error returned by a non-nested trigger
+	standalone := NewObservedHook[int](observers)
+	standalone.Trigger(2, func(data int) error { return demoErr })
+
+	if len(observed) != 3 || observed[2] != 2 {
+		t.Fatalf("Expected the outer error to be observed, got %v", observed)
+	}
+}
+
+func TestHookErrorObserversNestedConcurrent(t *testing.T) {
+	var mux sync.Mutex
+	var observed []any
+
+	observers := &Observers{}
+	observers.AddErrorObserver(func(data any, err error) {
+		mux.Lock()
+		defer mux.Unlock()
+
+		observed = append(observed, data)
+	})
+
+	sentinelErr := errors.New("sentinel")
+
+	inner := NewObservedHook[string](observers)
+	inner.Add(func(data string) error { return sentinelErr })
+
+	reported := make(chan struct{})
+	resume := make(chan struct{})
+
+	outer := NewObservedHook[int](observers)
+	outer.Add(func(data int) error {
+		err := inner.Trigger("inner")
+
+		// pause the propagation so that the same error could be
+		// returned meanwhile by a trigger in another goroutine
+		close(reported)
+		<-resume
+
+		return err
+	})
+
+	done := make(chan error)
+	go func() {
+		done <- outer.Trigger(1)
+	}()
+
+	<-reported
+
+	standalone := NewObservedHook[float64](observers)
+	standalone.Trigger(1.5, func(data float64) error { return sentinelErr })
+
+	close(resume)
+
+	if err := <-done; err != sentinelErr {
+		t.Fatalf("Expected the sentinel error, got %v", err)
+	}
+
+	if len(observed) != 2 || observed[0] != "inner" || observed[1] != 1.5 {
+		t.Fatalf("Expected the errors to be observed only at their origin, got %v", observed)
+	}
+}
+
+func TestHookErrorObserversNestedDeepStack(t *testing.T) {
+	var observed []any
+
+	observers := &Observers{}
+	observers.AddErrorObserver(func(data any, err error) {
+		observed = append(observed, data)
+	})
+
+	demoErr := errors.New("demo")
+
+	inner := NewObservedHook[string](observers)
+	inner.Add(func(data string) error { return demoErr })
+
+	var deep func(n int) error
+	deep = func(n int) error {
+		if n == 0 {
+			return inner.Trigger("inner")
+		}
+		return deep(n - 1)
+	}
+
+	outer := NewObservedHook[int](observers)
+	outer.Add(func(data int) error { return deep(1000) })
+
+	if err := outer.Trigger(1); err != demoErr {
+		t.Fatalf("Expected the demo error, got %v", err)
+	}
+
+	if len(observed) != 1 || observed[0] != "inner" {
+		t.Fatalf("Expected the error to be observed only at its origin, got %v", observed)
+	}
+}
+
+func TestHookTriggerObservers(t *testing.T) {
+	var started []any
+	var finished []error
+
+	observers := &Observers{}
+
+	id := observers.AddTriggerObserver(func(data any) func(err error) {
+		started = append(started, data)
+
+		return func(err error) {
+			finished = append(finished, err)
+		}
+	})
+
+	// observer without finish callback
+	noFinishId := observers.AddTriggerObserver(func(data any) func(err error) {
+		return nil
+	})
+	defer observers.RemoveTriggerObserver(noFinishId)
+
+	demoErr := errors.New("demo")
+
+	h := NewObservedHook[int](observers)
+
+	h.Trigger(1)
+	h.Trigger(2, func(data int) error { return StopPropagation })
+	h.Trigger(3, func(data int) error { return demoErr })
+
+	if len(started) != 3 || started[0] != 1 || started[1] != 2 || started[2] != 3 {
+		t.Fatalf("Unexpected started triggers %v", started)
+	}
+
+	if len(finished) != 3 || finished[0] != nil || finished[1] != nil || finished[2] != demoErr {
+		t.Fatalf("Unexpected finished triggers %v", finished)
+	}
+
+	observers.RemoveTriggerObserver(id)
+
+	h.Trigger(4)
+
+	if len(started) != 3 {
+		t.Fatalf("Expected no more observed triggers after the observer removal, got %v", started)
+	}
+}
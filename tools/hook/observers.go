@@ -0,0 +1,237 @@
+package hook
+
+import (
+	"bytes"
+	"reflect"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrorObserver defines a function that is notified for each
+// hook handler error (excluding StopPropagation).
+type ErrorObserver func(data any, err error)
+
+// TriggerObserver defines a function that is notified on each hook
+// trigger start and that could optionally return a callback
+// to be notified when the trigger completes (eg. for tracing).
+type TriggerObserver func(data any) (finish func(err error))
+
+// maxReportedErrors is the max number of the last reported errors
+// that are remembered to skip their propagation through the outer triggers.
+const maxReportedErrors = 128
+
+// Observers defines a concurrent safe group of hook trigger and error
+// observers that could be shared between multiple hooks (eg. all app hooks).
+//
+// The zero value is ready to use.
+type Observers struct {
+	mux      sync.RWMutex
+	errors   []*errorObserverPair
+	triggers []*triggerObserverPair
+
+	// the last reported errors and the sequence number of the last one
+	// (used to skip the errors propagated from the nested triggers)
+	reportedMux sync.Mutex
+	reported    []reportedError
+	reportedSeq atomic.Uint64
+}
+
+type reportedError struct {
+	err         error
+	goroutineId uint64
+	seq         uint64
+}
+
+type errorObserverPair struct {
+	id       string
+	observer ErrorObserver
+}
+
+type triggerObserverPair struct {
+	id       string
+	observer TriggerObserver
+}
+
+// AddErrorObserver registers an observer that is notified for the
+// handler errors of the hooks attached to the current group.
+//
+// An error returned by a nested hook trigger is reported only once,
+// at the trigger where the error originated.
+//
+// Returns an autogenerated id that could be used later to remove the observer with RemoveErrorObserver(id).
+func (o *Observers) AddErrorObserver(fn ErrorObserver) string {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+
+	id := generateHookId()
+
+	o.errors = append(o.errors, &errorObserverPair{id, fn})
+
+	return id
+}
+
+// RemoveErrorObserver removes a single error observer by its id.
+func (o *Observers) RemoveErrorObserver(id string) {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+
+	for i := len(o.errors) - 1; i >= 0; i-- {
+		if o.errors[i].id == id {
+			o.errors = append(o.errors[:i], o.errors[i+1:]...)
+			return
+		}
+	}
+}
+
+// AddTriggerObserver registers an observer that is notified
+// for the triggers of the hooks attached to the current group.
+//
+// Returns an autogenerated id that could be used later to remove the observer with RemoveTriggerObserver(id).
+func (o *Observers) AddTriggerObserver(fn TriggerObserver) string {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+
+	id := generateHookId()
+
+	o.triggers = append(o.triggers, &triggerObserverPair{id, fn})
+
+	return id
+}
+
+// RemoveTriggerObserver removes a single trigger observer by its id.
+func (o *Observers) RemoveTriggerObserver(id string) {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+
+	for i := len(o.triggers) - 1; i >= 0; i-- {
+		if o.triggers[i].id == id {
+			o.triggers = append(o.triggers[:i], o.triggers[i+1:]...)
+			return
+		}
+	}
+}
+
+// lastReportedSeq returns the sequence number of the last reported error.
+//
+// It is expected to be called on trigger start to mark the
+// errors reported afterwards by the nested triggers (see notifyError).
+func (o *Observers) lastReportedSeq() uint64 {
+	if o == nil {
+		return 0
+	}
+
+	return o.reportedSeq.Load()
+}
+
+// notifyError notifies the registered error observers unless err
+// was already reported by a nested trigger, aka. by a trigger in the
+// same goroutine after the current trigger start (see lastReportedSeq).
+func (o *Observers) notifyError(data any, err error, triggerStartSeq uint64) {
+	if o == nil {
+		return
+	}
+
+	o.mux.RLock()
+	items := o.errors
+	o.mux.RUnlock()
+
+	if len(items) == 0 || !o.markReported(err, triggerStartSeq) {
+		return
+	}
+
+	for _, item := range items {
+		item.observer(data, err)
+	}
+}
+
+// markReported checks whether err should be reported and remembers it.
+//
+// An error is not reported if the same error was already reported in the
+// current goroutine after triggerStartSeq, aka. it was returned by a trigger
+// nested in the current one and is propagated through its handler.
+//
+// Errors with non-comparable dynamic types (eg. maps) cannot be
+// matched and are always reported.
+func (o *Observers) markReported(err error, triggerStartSeq uint64) bool {
+	if !reflect.TypeOf(err).Comparable() {
+		return true
+	}
+
+	goroutineId := currentGoroutineId()
+
+	o.reportedMux.Lock()
+	defer o.reportedMux.Unlock()
+
+	for _, r := range o.reported {
+		if r.seq > triggerStartSeq && r.goroutineId == goroutineId && r.err == err {
+			return false
+		}
+	}
+
+	if len(o.reported) >= maxReportedErrors {
+		o.reported = o.reported[1:]
+	}
+
+	o.reported = append(o.reported, reportedError{
+		err:         err,
+		goroutineId: goroutineId,
+		seq:         o.reportedSeq.Add(1),
+	})
+
+	return true
+}
+
+// currentGoroutineId returns the id of the current goroutine
+// (parsed from the "goroutine 123 [running]:" stack header).
+//
+// It is relatively slow and should be used only on the error path.
+func currentGoroutineId() uint64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+
+	buf = bytes.TrimPrefix(buf, []byte("goroutine "))
+	if i := bytes.IndexByte(buf, ' '); i >= 0 {
+		buf = buf[:i]
+	}
+
+	id, _ := strconv.ParseUint(string(buf), 10, 64)
+
+	return id
+}
+
+// notifyTrigger notifies the registered trigger observers
+// and returns a single callback for all their finish funcs (if any).
+func (o *Observers) notifyTrigger(data any) func(err error) {
+	if o == nil {
+		return nil
+	}
+
+	o.mux.RLock()
+	items := o.triggers
+	o.mux.RUnlock()
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	var finishers []func(err error)
+
+	for _, item := range items {
+		if finish := item.observer(data); finish != nil {
+			finishers = append(finishers, finish)
+		}
+	}
+
+	if len(finishers) == 0 {
+		return nil
+	}
+
+	return func(err error) {
+		// call in reverse order
+		for i := len(finishers) - 1; i >= 0; i-- {
+			finishers[i](err)
+		}
+	}
+}
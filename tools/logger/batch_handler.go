@@ -2,6 +2,7 @@ package logger
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"sync"
 
@@ -29,6 +30,11 @@ type BatchOptions struct {
 	// BatchSize specifies how many logs to accumulate before calling WriteFunc.
 	// If not set or 0, fallback to 100 by default.
 	BatchSize int
+
+	// Sinks is an optional list of extra destinations that receive
+	// the batched logs in parallel with WriteFunc (use [BatchHandler.SetSinks]
+	// to replace them after the handler initialization).
+	Sinks []Sink
 }
 
 // NewBatchHandler creates a slog compatible handler that writes JSON
@@ -51,6 +57,7 @@ func NewBatchHandler(options BatchOptions) *BatchHandler {
 	h := &BatchHandler{
 		mux:     &sync.Mutex{},
 		options: &options,
+		sinks:   &sinksGroup{sinks: options.Sinks},
 	}
 
 	if h.options.WriteFunc == nil {
@@ -79,11 +86,19 @@ type BatchHandler struct {
 	mux     *sync.Mutex
 	parent  *BatchHandler
 	options *BatchOptions
+	sinks   *sinksGroup // only for the top level handler
 	group   string
 	attrs   []slog.Attr
 	logs    []*Log
 }
 
+// sinksGroup holds the current handler sinks
+// and tracks their in-flight writes.
+type sinksGroup struct {
+	sinks  []Sink
+	writes sync.WaitGroup
+}
+
 // Enabled reports whether the handler handles records at the given level.
 //
 // The handler ignores records whose level is lower.
@@ -181,7 +196,9 @@ func (h *BatchHandler) Handle(ctx context.Context, r slog.Record) error {
 	h.mux.Unlock()
 
 	if totalLogs >= h.options.BatchSize {
-		if err := h.WriteAll(ctx); err != nil {
+		// the batch could contain logs from other contexts
+		// so don't abort the write if ctx is canceled (eg. ended request)
+		if err := h.WriteAll(context.WithoutCancel(ctx)); err != nil {
 			return err
 		}
 	}
@@ -196,7 +213,45 @@ func (h *BatchHandler) SetLevel(level slog.Level) {
 	h.mux.Unlock()
 }
 
+// SetSinks replaces the handler sinks with the specified ones.
+//
+// The replaced sinks are closed (see [CloseSink]) after their
+// in-flight writes complete, so it shouldn't be called from a sink write.
+func (h *BatchHandler) SetSinks(sinks ...Sink) {
+	if h.parent != nil {
+		// the most top level handler is holding the sinks
+		h.parent.SetSinks(sinks...)
+		return
+	}
+
+	h.mux.Lock()
+	old := h.sinks
+	h.sinks = &sinksGroup{sinks: sinks}
+	h.mux.Unlock()
+
+	old.writes.Wait()
+
+	for _, sink := range old.sinks {
+		CloseSink(sink)
+	}
+}
+
+// Sinks returns the current handler sinks.
+func (h *BatchHandler) Sinks() []Sink {
+	if h.parent != nil {
+		return h.parent.Sinks()
+	}
+
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	return h.sinks.sinks
+}
+
 // WriteAll writes all accumulated Log entries and resets the batch queue.
+//
+// The logs are written concurrently to the WriteFunc and to all sinks
+// (a failure of one of them doesn't prevent the others from receiving the logs).
 func (h *BatchHandler) WriteAll(ctx context.Context) error {
 	if h.parent != nil {
 		// invoke recursively the parent level handler since the most
@@ -219,9 +274,41 @@ func (h *BatchHandler) WriteAll(ctx context.Context) error {
 	copy(logs, h.logs)
 	h.logs = h.logs[:0] // reset
 
+	group := h.sinks
+	sinks := group.sinks
+
+	// register the sinks write while holding the lock so that
+	// a concurrent SetSinks wouldn't close the sinks before it completes
+	if len(sinks) > 0 {
+		group.writes.Add(1)
+		defer group.writes.Done()
+	}
+
 	h.mux.Unlock()
 
-	return h.options.WriteFunc(ctx, logs)
+	if len(sinks) == 0 {
+		return h.options.WriteFunc(ctx, logs)
+	}
+
+	errs := make([]error, len(sinks)+1)
+
+	var wg sync.WaitGroup
+
+	for i, sink := range sinks {
+		wg.Add(1)
+
+		go func(i int, sink Sink) {
+			defer wg.Done()
+
+			errs[i+1] = sink.WriteLogs(ctx, logs)
+		}(i, sink)
+	}
+
+	errs[0] = h.options.WriteFunc(ctx, logs)
+
+	wg.Wait()
+
+	return errors.Join(errs...)
 }
 
 // resolveAttr writes attr into data.
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -253,6 +255,152 @@ func TestBatchHandlerWriteAll(t *testing.T) {
 	checkLogMessages([]string{"test1", "test2"}, writeLogs, t)
 }
 
+type testSink struct {
+	logs   []*Log
+	err    error
+	closed bool
+}
+
+func (s *testSink) WriteLogs(ctx context.Context, logs []*Log) error {
+	s.logs = append(s.logs, logs...)
+	return s.err
+}
+
+func (s *testSink) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestBatchHandlerSinks(t *testing.T) {
+	ctx := context.Background()
+
+	writeLogs := []*Log{}
+
+	sinkA := &testSink{err: errors.New("sinkA error")}
+
+	h := NewBatchHandler(BatchOptions{
+		BatchSize: 10,
+		Sinks:     []Sink{sinkA},
+		WriteFunc: func(_ context.Context, logs []*Log) error {
+			writeLogs = logs
+			return errors.New("write error")
+		},
+	})
+
+	h.Handle(ctx, slog.NewRecord(time.Now(), slog.LevelInfo, "test1", 0))
+
+	err := h.WriteAll(ctx)
+	if err == nil || !strings.Contains(err.Error(), "write error") || !strings.Contains(err.Error(), "sinkA error") {
+		t.Fatalf("Expected both write and sink errors, got %v", err)
+	}
+
+	// the write errors shouldn't prevent the other sinks from receiving the logs
+	checkLogMessages([]string{"test1"}, writeLogs, t)
+	checkLogMessages([]string{"test1"}, sinkA.logs, t)
+
+	// replace the sinks
+	sinkB := &testSink{}
+	h.SetSinks(sinkB)
+
+	if !sinkA.closed {
+		t.Fatal("Expected the replaced sinkA to be closed")
+	}
+
+	if total := len(h.Sinks()); total != 1 {
+		t.Fatalf("Expected 1 sink, got %d", total)
+	}
+
+	h.Handle(ctx, slog.NewRecord(time.Now(), slog.LevelInfo, "test2", 0))
+	h.WriteAll(ctx)
+
+	checkLogMessages([]string{"test2"}, writeLogs, t)
+	checkLogMessages([]string{"test1"}, sinkA.logs, t)
+	checkLogMessages([]string{"test2"}, sinkB.logs, t)
+}
+
+type blockingSink struct {
+	started chan struct{}
+	release chan struct{}
+
+	mux              sync.Mutex
+	closed           bool
+	closedOnWriteEnd bool
+}
+
+func (s *blockingSink) WriteLogs(ctx context.Context, logs []*Log) error {
+	close(s.started)
+	<-s.release
+
+	s.mux.Lock()
+	s.closedOnWriteEnd = s.closed
+	s.mux.Unlock()
+
+	return nil
+}
+
+func (s *blockingSink) Close() error {
+	s.mux.Lock()
+	s.closed = true
+	s.mux.Unlock()
+
+	return nil
+}
+
+func TestBatchHandlerSetSinksWithInFlightWrite(t *testing.T) {
+	ctx := context.Background()
+
+	sink := &blockingSink{started: make(chan struct{}), release: make(chan struct{})}
+
+	h := NewBatchHandler(BatchOptions{
+		BatchSize: 10,
+		Sinks:     []Sink{sink},
+		WriteFunc: func(_ context.Context, logs []*Log) error {
+			return nil
+		},
+	})
+
+	// child handlers should replace the top level handler sinks
+	child := h.WithGroup("sub").(*BatchHandler)
+
+	h.Handle(ctx, slog.NewRecord(time.Now(), slog.LevelInfo, "test", 0))
+
+	writeDone := make(chan struct{})
+	go func() {
+		h.WriteAll(ctx)
+		close(writeDone)
+	}()
+
+	<-sink.started
+
+	setDone := make(chan struct{})
+	go func() {
+		child.SetSinks()
+		close(setDone)
+	}()
+
+	select {
+	case <-setDone:
+		t.Fatal("Expected SetSinks to wait for the in-flight write")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if total := len(h.Sinks()); total != 0 {
+		t.Fatalf("Expected the sinks to be already replaced, got %d", total)
+	}
+
+	close(sink.release)
+	<-writeDone
+	<-setDone
+
+	if sink.closedOnWriteEnd {
+		t.Fatal("Expected the sink to be closed after the write completion")
+	}
+
+	if !sink.closed {
+		t.Fatal("Expected the replaced sink to be closed")
+	}
+}
+
 func TestBatchHandlerAttrsFormat(t *testing.T) {
 	ctx := context.Background()
 
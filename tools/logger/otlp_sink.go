@@ -0,0 +1,139 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var _ Sink = (*OTLPSink)(nil)
+
+// OTLPSink exports the logs to an OpenTelemetry collector
+// using the OTLP/HTTP protocol with JSON encoding.
+type OTLPSink struct {
+	// Endpoint is the full OTLP logs url (eg. "http://localhost:4318/v1/logs").
+	Endpoint string
+
+	// ServiceName is the exported "service.name" resource attribute.
+	ServiceName string
+
+	// Client is the http client used for the export requests.
+	Client *http.Client
+}
+
+// NewOTLPSink creates a new OTLPSink with the provided endpoint and service name.
+func NewOTLPSink(endpoint string, serviceName string) *OTLPSink {
+	return &OTLPSink{
+		Endpoint:    endpoint,
+		ServiceName: serviceName,
+		Client:      &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// WriteLogs implements the [Sink] interface.
+func (s *OTLPSink) WriteLogs(ctx context.Context, logs []*Log) error {
+	if len(logs) == 0 {
+		return nil
+	}
+
+	records := make([]any, 0, len(logs))
+	for _, l := range logs {
+		records = append(records, otlpLogRecord(l))
+	}
+
+	payload := map[string]any{
+		"resourceLogs": []any{
+			map[string]any{
+				"resource": map[string]any{
+					"attributes": []any{otlpAttribute("service.name", s.ServiceName)},
+				},
+				"scopeLogs": []any{
+					map[string]any{
+						"scope":      map[string]any{"name": "postgresbase"},
+						"logRecords": records,
+					},
+				},
+			},
+		},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		content, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return fmt.Errorf("failed to export logs (%d): %s", res.StatusCode, content)
+	}
+
+	return nil
+}
+
+func otlpLogRecord(l *Log) map[string]any {
+	attrs := make([]any, 0, len(l.Data))
+	for k, v := range l.Data {
+		attrs = append(attrs, otlpAttribute(k, v))
+	}
+
+	return map[string]any{
+		"timeUnixNano":   strconv.FormatInt(l.Time.UnixNano(), 10),
+		"severityNumber": otlpSeverityNumber(l.Level),
+		"severityText":   l.Level.String(),
+		"body":           map[string]any{"stringValue": l.Message},
+		"attributes":     attrs,
+	}
+}
+
+// otlpSeverityNumber maps the slog level to the OTLP SeverityNumber
+// (DEBUG=5, INFO=9, WARN=13, ERROR=17 and the levels in between).
+func otlpSeverityNumber(level slog.Level) int {
+	n := 9 + int(level)
+
+	return max(1, min(n, 24))
+}
+
+func otlpAttribute(key string, value any) map[string]any {
+	var v map[string]any
+
+	switch val := value.(type) {
+	case string:
+		v = map[string]any{"stringValue": val}
+	case bool:
+		v = map[string]any{"boolValue": val}
+	case int:
+		v = map[string]any{"intValue": strconv.Itoa(val)}
+	case int64:
+		v = map[string]any{"intValue": strconv.FormatInt(val, 10)}
+	case float64:
+		v = map[string]any{"doubleValue": val}
+	default:
+		raw, _ := json.Marshal(val)
+		v = map[string]any{"stringValue": string(raw)}
+	}
+
+	return map[string]any{"key": key, "value": v}
+}
@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/types"
+)
+
+func TestOTLPSink(t *testing.T) {
+	var body []byte
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+
+		if strings.Contains(string(body), "fail") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sink := NewOTLPSink(server.URL, "test")
+
+	logs := []*Log{
+		{Time: time.Unix(1, 0), Level: slog.LevelWarn, Message: "demo", Data: types.JsonMap{"a": 1, "b": "test", "c": map[string]any{"d": true}}},
+	}
+
+	if err := sink.WriteLogs(context.Background(), logs); err != nil {
+		t.Fatal(err)
+	}
+
+	expectations := []string{
+		`"resourceLogs":[{`,
+		`"key":"service.name","value":{"stringValue":"test"}`,
+		`"timeUnixNano":"1000000000"`,
+		`"severityNumber":13`,
+		`"severityText":"WARN"`,
+		`"body":{"stringValue":"demo"}`,
+		`{"key":"a","value":{"intValue":"1"}}`,
+		`{"key":"b","value":{"stringValue":"test"}}`,
+		`{"key":"c","value":{"stringValue":"{\"d\":true}"}}`,
+	}
+	for _, expected := range expectations {
+		if !strings.Contains(string(body), expected) {
+			t.Errorf("Missing %s in\n%s", expected, body)
+		}
+	}
+
+	logs[0].Message = "fail"
+	if err := sink.WriteLogs(context.Background(), logs); err == nil {
+		t.Fatal("Expected export error")
+	}
+}
@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRotatingFile(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "sub", "logs.txt")
+
+	f, err := NewRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writes := []string{"aaaaa", "bbbbb", "ccccc", "ddddd", "eeeee", "fffff", "ggggggggggggggg"}
+	for _, w := range writes {
+		if _, err := f.Write([]byte(w)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.Write([]byte("closed")); err == nil {
+		t.Fatal("Expected write error after close")
+	}
+
+	expectations := map[string]string{
+		path:        "ggggggggggggggg",
+		path + ".1": "eeeeefffff",
+		path + ".2": "cccccddddd",
+	}
+
+	for file, expected := range expectations {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(content) != expected {
+			t.Errorf("Expected %s content %q, got %q", file, expected, content)
+		}
+	}
+
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Fatalf("Expected %s.3 to be deleted, got %v", path, err)
+	}
+
+	// reopen and append to the existing file
+	f, err = NewRotatingFile(path, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write([]byte("h")); err != nil {
+		t.Fatal(err)
+	}
+
+	content, _ := os.ReadFile(path)
+	if string(content) != "ggggggggggggggg"+"h" {
+		t.Fatalf("Expected the new content to be appended, got %q", content)
+	}
+}
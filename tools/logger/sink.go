@@ -0,0 +1,210 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RedactedValue is the value that replaces the redacted log data attributes.
+const RedactedValue = "******"
+
+// Sink defines a destination of the batched logs (stdout, file, syslog, etc.).
+//
+// Sinks that need to release resources could also implement [io.Closer].
+//
+// Note that the logs are shared between the BatchHandler sinks
+// and they must not be modified (see [FilteredSink]).
+type Sink interface {
+	WriteLogs(ctx context.Context, logs []*Log) error
+}
+
+// CloseSink closes the provided sink if it implements [io.Closer].
+func CloseSink(sink Sink) error {
+	if closer, ok := sink.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// -------------------------------------------------------------------
+
+var _ Sink = (*FilteredSink)(nil)
+
+// FilteredSink is a Sink wrapper that writes to the wrapped Sink
+// only the logs with the specified min level, optionally with
+// redacted data attributes.
+type FilteredSink struct {
+	sink     Sink
+	minLevel slog.Level
+	redact   [][]string
+}
+
+// NewFilteredSink creates a new FilteredSink for the provided sink.
+//
+// The redact rules are dot separated log data attribute paths
+// (eg. "auth", "details.password") where "*" matches any key
+// at the specific path segment (eg. "*.token").
+// The attribute keys are compared case-insensitively.
+func NewFilteredSink(sink Sink, minLevel slog.Level, redact ...string) *FilteredSink {
+	s := &FilteredSink{
+		sink:     sink,
+		minLevel: minLevel,
+		redact:   make([][]string, 0, len(redact)),
+	}
+
+	for _, rule := range redact {
+		if rule == "" {
+			continue
+		}
+
+		s.redact = append(s.redact, strings.Split(strings.ToLower(rule), "."))
+	}
+
+	return s
+}
+
+// WriteLogs implements the [Sink] interface.
+func (s *FilteredSink) WriteLogs(ctx context.Context, logs []*Log) error {
+	filtered := make([]*Log, 0, len(logs))
+
+	for _, l := range logs {
+		if l.Level < s.minLevel {
+			continue
+		}
+
+		if len(s.redact) > 0 && len(l.Data) > 0 {
+			clone := *l
+			clone.Data = redactData(l.Data, s.redact)
+			l = &clone
+		}
+
+		filtered = append(filtered, l)
+	}
+
+	if len(filtered) == 0 {
+		return nil
+	}
+
+	return s.sink.WriteLogs(ctx, filtered)
+}
+
+// Close implements the [io.Closer] interface by closing the wrapped sink.
+func (s *FilteredSink) Close() error {
+	return CloseSink(s.sink)
+}
+
+// redactData returns a redacted shallow copy of the provided data
+// (only the maps along the matching rule paths are copied).
+func redactData(data map[string]any, rules [][]string) map[string]any {
+	result := make(map[string]any, len(data))
+
+	for key, value := range data {
+		lowerKey := strings.ToLower(key)
+
+		var nested [][]string
+		redacted := false
+
+		for _, rule := range rules {
+			if rule[0] != "*" && rule[0] != lowerKey {
+				continue
+			}
+
+			if len(rule) == 1 {
+				redacted = true
+				break
+			}
+
+			nested = append(nested, rule[1:])
+		}
+
+		switch {
+		case redacted:
+			result[key] = RedactedValue
+		case len(nested) > 0:
+			if m, ok := value.(map[string]any); ok {
+				result[key] = redactData(m, nested)
+			} else {
+				result[key] = value
+			}
+		default:
+			result[key] = value
+		}
+	}
+
+	return result
+}
+
+// -------------------------------------------------------------------
+
+var _ Sink = (*JSONSink)(nil)
+
+// JSONSink writes the logs as JSON lines (aka. one JSON object per line)
+// into the provided writer, eg.:
+//
+//	{"time":"2024-01-01T00:00:00Z","level":"INFO","message":"test","data":{"a":1}}
+type JSONSink struct {
+	mux sync.Mutex
+	w   io.Writer
+}
+
+// NewJSONSink creates a new JSONSink that writes into w.
+//
+// If w implements [io.Closer] (and it is not [os.Stdout] or [os.Stderr]),
+// it is closed on JSONSink.Close.
+func NewJSONSink(w io.Writer) *JSONSink {
+	return &JSONSink{w: w}
+}
+
+// WriteLogs implements the [Sink] interface.
+func (s *JSONSink) WriteLogs(ctx context.Context, logs []*Log) error {
+	var buf strings.Builder
+
+	encoder := json.NewEncoder(&buf)
+
+	var errs []error
+
+	for _, l := range logs {
+		err := encoder.Encode(map[string]any{
+			"time":    l.Time.UTC().Format(time.RFC3339Nano),
+			"level":   l.Level.String(),
+			"message": l.Message,
+			"data":    l.Data,
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if _, err := io.WriteString(s.w, buf.String()); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Close implements the [io.Closer] interface.
+func (s *JSONSink) Close() error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.w == os.Stdout || s.w == os.Stderr {
+		return nil
+	}
+
+	if closer, ok := s.w.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/types"
+)
+
+func TestFilteredSink(t *testing.T) {
+	logs := []*Log{
+		{Level: slog.LevelDebug, Message: "debug"},
+		{
+			Level:   slog.LevelInfo,
+			Message: "info",
+			Data: types.JsonMap{
+				"Auth":   "secret",
+				"userIp": "127.0.0.1",
+				"details": map[string]any{
+					"password": "123456",
+					"name":     "test",
+				},
+				"other": map[string]any{
+					"token": "abc",
+				},
+			},
+		},
+		{Level: slog.LevelError, Message: "error"},
+	}
+
+	target := &testSink{}
+
+	sink := NewFilteredSink(target, slog.LevelInfo, "auth", "details.password", "*.token", "")
+
+	if err := sink.WriteLogs(context.Background(), logs); err != nil {
+		t.Fatal(err)
+	}
+
+	checkLogMessages([]string{"info", "error"}, target.logs, t)
+
+	raw, err := json.Marshal(target.logs[0].Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"Auth":"******","details":{"name":"test","password":"******"},"other":{"token":"******"},"userIp":"127.0.0.1"}`
+	if string(raw) != expected {
+		t.Fatalf("Expected redacted data\n%s\ngot\n%s", expected, raw)
+	}
+
+	// the original log data must remain unchanged
+	if logs[1].Data["Auth"] != "secret" || logs[1].Data["details"].(map[string]any)["password"] != "123456" {
+		t.Fatalf("The original log data was modified: %v", logs[1].Data)
+	}
+
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !target.closed {
+		t.Fatal("Expected the wrapped sink to be closed")
+	}
+}
+
+func TestJSONSink(t *testing.T) {
+	buf := new(bytes.Buffer)
+
+	sink := NewJSONSink(buf)
+
+	logs := []*Log{
+		{Time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), Level: slog.LevelInfo, Message: "test1", Data: types.JsonMap{"a": 1}},
+		{Time: time.Date(2024, 1, 2, 3, 4, 6, 0, time.UTC), Level: slog.LevelWarn, Message: "test2"},
+	}
+
+	if err := sink.WriteLogs(context.Background(), logs); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		`{"data":{"a":1},"level":"INFO","message":"test1","time":"2024-01-02T03:04:05Z"}`,
+		`{"data":{},"level":"WARN","message":"test2","time":"2024-01-02T03:04:06Z"}`,
+		``,
+	}, "\n")
+
+	if buf.String() != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, buf.String())
+	}
+
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
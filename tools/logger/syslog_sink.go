@@ -0,0 +1,166 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+var _ Sink = (*SyslogSink)(nil)
+
+// syslogFacilityUser is the "user-level messages" syslog facility.
+const syslogFacilityUser = 1
+
+// SyslogSink writes the logs to a remote (or local unix socket)
+// syslog server using the RFC 5424 message format.
+//
+// The log data attributes are appended to the message as JSON.
+type SyslogSink struct {
+	mux      sync.Mutex
+	network  string
+	address  string
+	tag      string
+	hostname string
+	conn     net.Conn
+}
+
+// NewSyslogSink creates a new SyslogSink.
+//
+// network could be "udp" (default), "tcp" or "unix".
+// The connection is established lazily on the first write.
+func NewSyslogSink(network, address, tag string) *SyslogSink {
+	if network == "" {
+		network = "udp"
+	}
+
+	if tag == "" {
+		tag = "postgresbase"
+	}
+
+	hostname, _ := os.Hostname()
+	if hostname == "" {
+		hostname = "-"
+	}
+
+	return &SyslogSink{
+		network:  network,
+		address:  address,
+		tag:      tag,
+		hostname: hostname,
+	}
+}
+
+// WriteLogs implements the [Sink] interface.
+//
+// On write failure the connection is reestablished once
+// before returning an error.
+func (s *SyslogSink) WriteLogs(ctx context.Context, logs []*Log) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	var errs []error
+
+	for _, l := range logs {
+		msg := s.format(l)
+
+		err := s.write(ctx, msg)
+		if err != nil {
+			// retry with a new connection
+			s.closeConn()
+			err = s.write(ctx, msg)
+		}
+
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Close implements the [io.Closer] interface.
+func (s *SyslogSink) Close() error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	return s.closeConn()
+}
+
+func (s *SyslogSink) write(ctx context.Context, msg string) error {
+	if s.conn == nil {
+		dialer := net.Dialer{Timeout: 5 * time.Second}
+
+		conn, err := dialer.DialContext(ctx, s.network, s.address)
+		if err != nil {
+			return err
+		}
+
+		s.conn = conn
+	}
+
+	// stream based transports require a messages delimiter
+	if s.network != "udp" && !strings.HasSuffix(msg, "\n") {
+		msg += "\n"
+	}
+
+	s.conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+
+	_, err := s.conn.Write([]byte(msg))
+
+	return err
+}
+
+func (s *SyslogSink) closeConn() error {
+	if s.conn == nil {
+		return nil
+	}
+
+	err := s.conn.Close()
+	s.conn = nil
+
+	return err
+}
+
+// format returns the RFC 5424 representation of the provided log, eg.:
+//
+//	<14>1 2024-01-01T00:00:00Z myhost postgresbase 123 - - test message {"a":1}
+func (s *SyslogSink) format(l *Log) string {
+	msg := strings.ReplaceAll(l.Message, "\n", " ")
+
+	if len(l.Data) > 0 {
+		if raw, err := json.Marshal(l.Data); err == nil {
+			msg += " " + string(raw)
+		}
+	}
+
+	return fmt.Sprintf(
+		"<%d>1 %s %s %s %d - - %s",
+		syslogFacilityUser*8+syslogSeverity(l.Level),
+		l.Time.UTC().Format(time.RFC3339Nano),
+		s.hostname,
+		s.tag,
+		os.Getpid(),
+		msg,
+	)
+}
+
+// syslogSeverity maps the slog level to a syslog severity.
+func syslogSeverity(level slog.Level) int {
+	switch {
+	case level >= slog.LevelError:
+		return 3 // error
+	case level >= slog.LevelWarn:
+		return 4 // warning
+	case level >= slog.LevelInfo:
+		return 6 // informational
+	default:
+		return 7 // debug
+	}
+}
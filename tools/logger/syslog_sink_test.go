@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/types"
+)
+
+func TestSyslogSink(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	sink := NewSyslogSink("", conn.LocalAddr().String(), "test")
+	defer sink.Close()
+
+	logs := []*Log{
+		{Time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), Level: slog.LevelError, Message: "test\nerror", Data: types.JsonMap{"a": 1}},
+		{Time: time.Date(2024, 1, 2, 3, 4, 6, 0, time.UTC), Level: slog.LevelDebug, Message: "test debug"},
+	}
+
+	if err := sink.WriteLogs(context.Background(), logs); err != nil {
+		t.Fatal(err)
+	}
+
+	expectations := []string{
+		fmt.Sprintf(`^<11>1 2024-01-02T03:04:05Z \S+ test %d - - test error \{"a":1\}$`, os.Getpid()),
+		fmt.Sprintf(`^<15>1 2024-01-02T03:04:06Z \S+ test %d - - test debug$`, os.Getpid()),
+	}
+
+	buf := make([]byte, 1024)
+
+	for _, expected := range expectations {
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !regexp.MustCompile(expected).Match(buf[:n]) {
+			t.Fatalf("Expected message to match %s, got %q", expected, buf[:n])
+		}
+	}
+}
@@ -0,0 +1,429 @@
+// Package metrics implements a minimal concurrent safe metrics registry
+// that could be exported in the Prometheus text exposition format.
+//
+// Example:
+//
+//	r := metrics.NewRegistry()
+//
+//	requests := r.CounterVec("http_requests_total", "Total HTTP requests.", "method")
+//	requests.Inc("GET")
+//
+//	r.Write(w)
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	typeCounter   = "counter"
+	typeGauge     = "gauge"
+	typeHistogram = "histogram"
+)
+
+// DefaultBuckets defines the default histogram buckets (in seconds)
+// suitable for measuring request and query durations.
+var DefaultBuckets = []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Registry defines a concurrent safe collection of metric families.
+type Registry struct {
+	mux        sync.RWMutex
+	families   map[string]*family
+	collectors []func()
+}
+
+// NewRegistry creates a new empty metrics Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		families: map[string]*family{},
+	}
+}
+
+// OnCollect registers a function that will be called right before
+// each Write (eg. to refresh gauges with point-in-time values).
+func (r *Registry) OnCollect(fn func()) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.collectors = append(r.collectors, fn)
+}
+
+// CounterVec registers (or returns the already registered) counter
+// family with the specified name and label names.
+//
+// It panics if a family with the same name but different type was already registered.
+func (r *Registry) CounterVec(name string, help string, labels ...string) *CounterVec {
+	return &CounterVec{r.family(name, help, typeCounter, nil, labels)}
+}
+
+// GaugeVec registers (or returns the already registered) gauge
+// family with the specified name and label names.
+//
+// It panics if a family with the same name but different type was already registered.
+func (r *Registry) GaugeVec(name string, help string, labels ...string) *GaugeVec {
+	return &GaugeVec{r.family(name, help, typeGauge, nil, labels)}
+}
+
+// HistogramVec registers (or returns the already registered) histogram
+// family with the specified name, upper bucket bounds and label names.
+//
+// If buckets is empty, DefaultBuckets will be used.
+//
+// It panics if a family with the same name but different type was already registered.
+func (r *Registry) HistogramVec(name string, help string, buckets []float64, labels ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+
+	sorted := make([]float64, len(buckets))
+	copy(sorted, buckets)
+	sort.Float64s(sorted)
+
+	return &HistogramVec{r.family(name, help, typeHistogram, sorted, labels)}
+}
+
+func (r *Registry) family(name, help, metricType string, buckets []float64, labels []string) *family {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if f, ok := r.families[name]; ok {
+		if f.metricType != metricType {
+			panic(fmt.Sprintf("metric %q is already registered as %s", name, f.metricType))
+		}
+		return f
+	}
+
+	f := &family{
+		name:       name,
+		help:       help,
+		metricType: metricType,
+		buckets:    buckets,
+		labels:     labels,
+		series:     map[string]*series{},
+	}
+
+	r.families[name] = f
+
+	return f
+}
+
+// Write runs the registered collectors and writes all metric families
+// in the Prometheus text exposition format (version 0.0.4).
+func (r *Registry) Write(w io.Writer) error {
+	r.mux.RLock()
+	collectors := make([]func(), len(r.collectors))
+	copy(collectors, r.collectors)
+	r.mux.RUnlock()
+
+	for _, fn := range collectors {
+		fn()
+	}
+
+	r.mux.RLock()
+	families := make([]*family, 0, len(r.families))
+	for _, f := range r.families {
+		families = append(families, f)
+	}
+	r.mux.RUnlock()
+
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].name < families[j].name
+	})
+
+	var sb strings.Builder
+
+	for _, f := range families {
+		f.write(&sb)
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// -------------------------------------------------------------------
+
+// CounterVec defines a family of monotonically increasing counters partitioned by labels.
+type CounterVec struct {
+	f *family
+}
+
+// Inc increments by 1 the counter with the specified label values.
+func (v *CounterVec) Inc(labelValues ...string) {
+	v.Add(1, labelValues...)
+}
+
+// Add increments by delta the counter with the specified label values.
+//
+// Negative deltas are ignored.
+func (v *CounterVec) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		return
+	}
+
+	s := v.f.get(labelValues)
+
+	s.mux.Lock()
+	s.value += delta
+	s.mux.Unlock()
+}
+
+// Value returns the current value of the counter with the specified label values.
+func (v *CounterVec) Value(labelValues ...string) float64 {
+	return v.f.value(labelValues)
+}
+
+// GaugeVec defines a family of arbitrary numeric values partitioned by labels.
+type GaugeVec struct {
+	f *family
+}
+
+// Set sets the value of the gauge with the specified label values.
+func (v *GaugeVec) Set(value float64, labelValues ...string) {
+	s := v.f.get(labelValues)
+
+	s.mux.Lock()
+	s.value = value
+	s.mux.Unlock()
+}
+
+// Add adds delta (could be negative) to the gauge with the specified label values.
+func (v *GaugeVec) Add(delta float64, labelValues ...string) {
+	s := v.f.get(labelValues)
+
+	s.mux.Lock()
+	s.value += delta
+	s.mux.Unlock()
+}
+
+// Value returns the current value of the gauge with the specified label values.
+func (v *GaugeVec) Value(labelValues ...string) float64 {
+	return v.f.value(labelValues)
+}
+
+// Reset removes all gauge series.
+func (v *GaugeVec) Reset() {
+	v.f.mux.Lock()
+	defer v.f.mux.Unlock()
+
+	v.f.series = map[string]*series{}
+}
+
+// HistogramVec defines a family of bucketed observations partitioned by labels.
+type HistogramVec struct {
+	f *family
+}
+
+// Observe adds a single observation to the histogram with the specified label values.
+func (v *HistogramVec) Observe(value float64, labelValues ...string) {
+	s := v.f.get(labelValues)
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.counts == nil {
+		s.counts = make([]uint64, len(v.f.buckets))
+	}
+
+	for i, bound := range v.f.buckets {
+		if value <= bound {
+			s.counts[i]++
+		}
+	}
+
+	s.count++
+	s.value += value
+}
+
+// Count returns the total number of observations of the histogram with the specified label values.
+func (v *HistogramVec) Count(labelValues ...string) uint64 {
+	s := v.f.find(labelValues)
+	if s == nil {
+		return 0
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	return s.count
+}
+
+// -------------------------------------------------------------------
+
+type family struct {
+	mux        sync.RWMutex
+	name       string
+	help       string
+	metricType string
+	buckets    []float64
+	labels     []string
+	series     map[string]*series
+}
+
+type series struct {
+	mux         sync.Mutex
+	labelValues []string
+	value       float64  // the counter/gauge value or the histogram sum
+	count       uint64   // histogram only
+	counts      []uint64 // histogram only (cumulative bucket counts)
+}
+
+var errLabelsMismatch = errors.New("metrics: label values count mismatch")
+
+func (f *family) key(labelValues []string) string {
+	if len(labelValues) != len(f.labels) {
+		panic(fmt.Errorf("%w for %q (expected %d, got %d)", errLabelsMismatch, f.name, len(f.labels), len(labelValues)))
+	}
+
+	return strings.Join(labelValues, "\xff")
+}
+
+func (f *family) find(labelValues []string) *series {
+	key := f.key(labelValues)
+
+	f.mux.RLock()
+	defer f.mux.RUnlock()
+
+	return f.series[key]
+}
+
+func (f *family) get(labelValues []string) *series {
+	key := f.key(labelValues)
+
+	f.mux.RLock()
+	s, ok := f.series[key]
+	f.mux.RUnlock()
+
+	if ok {
+		return s
+	}
+
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	// recheck in case it was created in the meantime
+	if s, ok := f.series[key]; ok {
+		return s
+	}
+
+	values := make([]string, len(labelValues))
+	copy(values, labelValues)
+
+	s = &series{labelValues: values}
+	f.series[key] = s
+
+	return s
+}
+
+func (f *family) value(labelValues []string) float64 {
+	s := f.find(labelValues)
+	if s == nil {
+		return 0
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	return s.value
+}
+
+func (f *family) write(sb *strings.Builder) {
+	f.mux.RLock()
+	keys := make([]string, 0, len(f.series))
+	for k := range f.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	list := make([]*series, len(keys))
+	for i, k := range keys {
+		list[i] = f.series[k]
+	}
+	f.mux.RUnlock()
+
+	if f.help != "" {
+		sb.WriteString("# HELP " + f.name + " " + escapeHelp(f.help) + "\n")
+	}
+	sb.WriteString("# TYPE " + f.name + " " + f.metricType + "\n")
+
+	for _, s := range list {
+		s.mux.Lock()
+
+		if f.metricType != typeHistogram {
+			writeSample(sb, f.name, f.labels, s.labelValues, "", "", s.value)
+			s.mux.Unlock()
+			continue
+		}
+
+		for i, bound := range f.buckets {
+			var count uint64
+			if s.counts != nil {
+				count = s.counts[i]
+			}
+			writeSample(sb, f.name+"_bucket", f.labels, s.labelValues, "le", formatFloat(bound), float64(count))
+		}
+		writeSample(sb, f.name+"_bucket", f.labels, s.labelValues, "le", "+Inf", float64(s.count))
+		writeSample(sb, f.name+"_sum", f.labels, s.labelValues, "", "", s.value)
+		writeSample(sb, f.name+"_count", f.labels, s.labelValues, "", "", float64(s.count))
+
+		s.mux.Unlock()
+	}
+}
+
+func writeSample(sb *strings.Builder, name string, labels, values []string, extraLabel, extraValue string, value float64) {
+	sb.WriteString(name)
+
+	if len(labels) > 0 || extraLabel != "" {
+		sb.WriteString("{")
+
+		for i, label := range labels {
+			if i > 0 {
+				sb.WriteString(",")
+			}
+			sb.WriteString(label + `="` + escapeLabelValue(values[i]) + `"`)
+		}
+
+		if extraLabel != "" {
+			if len(labels) > 0 {
+				sb.WriteString(",")
+			}
+			sb.WriteString(extraLabel + `="` + extraValue + `"`)
+		}
+
+		sb.WriteString("}")
+	}
+
+	sb.WriteString(" " + formatFloat(value) + "\n")
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var helpReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+func escapeHelp(str string) string {
+	return helpReplacer.Replace(str)
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func escapeLabelValue(str string) string {
+	return labelValueReplacer.Replace(str)
+}
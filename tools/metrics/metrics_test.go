@@ -0,0 +1,105 @@
+package metrics_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/metrics"
+)
+
+func TestRegistryWrite(t *testing.T) {
+	r := metrics.NewRegistry()
+
+	counter := r.CounterVec("test_requests_total", "Total test requests.", "method", "status")
+	counter.Inc("GET", "200")
+	counter.Inc("GET", "200")
+	counter.Add(3, "POST", "400")
+	counter.Add(-10, "POST", "400") // should be ignored
+
+	gauge := r.GaugeVec("test_clients", "Active test\nclients.")
+	gauge.Set(5)
+	gauge.Add(-2)
+
+	histogram := r.HistogramVec("test_duration_seconds", "", []float64{1, 0.1}, "route")
+	histogram.Observe(0.05, `/a"b`)
+	histogram.Observe(0.5, `/a"b`)
+	histogram.Observe(2, `/a"b`)
+
+	collected := 0
+	r.OnCollect(func() {
+		collected++
+		r.GaugeVec("test_collected", "").Set(float64(collected))
+	})
+
+	var sb strings.Builder
+	if err := r.Write(&sb); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"# HELP test_clients Active test\\nclients.",
+		"# TYPE test_clients gauge",
+		"test_clients 3",
+		"# TYPE test_collected gauge",
+		"test_collected 1",
+		"# TYPE test_duration_seconds histogram",
+		`test_duration_seconds_bucket{route="/a\"b",le="0.1"} 1`,
+		`test_duration_seconds_bucket{route="/a\"b",le="1"} 2`,
+		`test_duration_seconds_bucket{route="/a\"b",le="+Inf"} 3`,
+		`test_duration_seconds_sum{route="/a\"b"} 2.55`,
+		`test_duration_seconds_count{route="/a\"b"} 3`,
+		"# HELP test_requests_total Total test requests.",
+		"# TYPE test_requests_total counter",
+		`test_requests_total{method="GET",status="200"} 2`,
+		`test_requests_total{method="POST",status="400"} 3`,
+		"",
+	}, "\n")
+
+	if sb.String() != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, sb.String())
+	}
+
+	if v := counter.Value("GET", "200"); v != 2 {
+		t.Fatalf("Expected counter value 2, got %v", v)
+	}
+
+	if v := histogram.Count(`/a"b`); v != 3 {
+		t.Fatalf("Expected histogram count 3, got %v", v)
+	}
+
+	gauge.Reset()
+	if v := gauge.Value(); v != 0 {
+		t.Fatalf("Expected reset gauge value 0, got %v", v)
+	}
+}
+
+func TestRegistryExistingFamily(t *testing.T) {
+	r := metrics.NewRegistry()
+
+	r.CounterVec("test", "", "a").Inc("1")
+
+	// should return the already registered family
+	if v := r.CounterVec("test", "", "a").Value("1"); v != 1 {
+		t.Fatalf("Expected the existing counter value 1, got %v", v)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expected panic for family type mismatch")
+		}
+	}()
+
+	r.GaugeVec("test", "", "a")
+}
+
+func TestLabelValuesMismatch(t *testing.T) {
+	r := metrics.NewRegistry()
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expected panic for label values mismatch")
+		}
+	}()
+
+	r.CounterVec("test", "", "a", "b").Inc("1")
+}
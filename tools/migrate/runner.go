@@ -1,6 +1,7 @@
 package migrate
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -18,6 +19,7 @@ type Runner struct {
 	db             *dbx.DB
 	migrationsList MigrationsList
 	tableName      string
+	txInit         func(tx *dbx.Tx) error
 }
 
 // NewRunner creates and initializes a new db migrations Runner instance.
@@ -35,6 +37,13 @@ func NewRunner(db *dbx.DB, migrationsList MigrationsList) (*Runner, error) {
 	return runner, nil
 }
 
+// SetTxInit registers a function that is called at the start of
+// every Up and Down migrations transaction (eg. to set transaction
+// scoped run-time parameters).
+func (r *Runner) SetTxInit(fn func(tx *dbx.Tx) error) {
+	r.txInit = fn
+}
+
 // Run interactively executes the current runner with the provided args.
 //
 // The following commands are supported:
@@ -124,6 +133,10 @@ func (r *Runner) Up() ([]string, error) {
 	applied := []string{}
 
 	err := r.db.Transactional(func(tx *dbx.Tx) error {
+		if err := r.initTx(tx); err != nil {
+			return err
+		}
+
 		for _, m := range r.migrationsList.Items() {
 			// skip applied
 			if r.isMigrationApplied(tx, m.File) {
@@ -166,6 +179,10 @@ func (r *Runner) Down(toRevertCount int) ([]string, error) {
 	}
 
 	err := r.db.Transactional(func(tx *dbx.Tx) error {
+		if err := r.initTx(tx); err != nil {
+			return err
+		}
+
 		for _, name := range names {
 			for _, m := range r.migrationsList.Items() {
 				if m.File != name {
@@ -202,6 +219,45 @@ func (r *Runner) Down(toRevertCount int) ([]string, error) {
 	return reverted, nil
 }
 
+// Pending returns the file names of the registered migrations
+// that are not applied yet (in their execution order).
+func (r *Runner) Pending() ([]string, error) {
+	return pendingMigrations(context.Background(), r.db, r.tableName, r.migrationsList)
+}
+
+// PendingMigrations returns the file names of the migrationsList
+// migrations that are not applied to db yet (in their execution order).
+//
+// Unlike NewRunner, it only reads from db and doesn't create the
+// migrations table (aka. it returns an error if the table is missing).
+func PendingMigrations(ctx context.Context, db dbx.Builder, migrationsList MigrationsList) ([]string, error) {
+	return pendingMigrations(ctx, db, DefaultMigrationsTable, migrationsList)
+}
+
+func pendingMigrations(ctx context.Context, db dbx.Builder, tableName string, migrationsList MigrationsList) ([]string, error) {
+	applied := []string{}
+
+	err := db.Select("file").From(tableName).WithContext(ctx).Column(&applied)
+	if err != nil {
+		return nil, err
+	}
+
+	appliedMap := make(map[string]struct{}, len(applied))
+	for _, file := range applied {
+		appliedMap[file] = struct{}{}
+	}
+
+	pending := []string{}
+
+	for _, m := range migrationsList.Items() {
+		if _, ok := appliedMap[m.File]; !ok {
+			pending = append(pending, m.File)
+		}
+	}
+
+	return pending, nil
+}
+
 func (r *Runner) createMigrationsTable() error {
 	rawQuery := fmt.Sprintf(
 		"CREATE TABLE IF NOT EXISTS %v (file VARCHAR(255) PRIMARY KEY NOT NULL, applied TIMESTAMP NOT NULL)",
@@ -213,6 +269,14 @@ func (r *Runner) createMigrationsTable() error {
 	return err
 }
 
+func (r *Runner) initTx(tx *dbx.Tx) error {
+	if r.txInit == nil {
+		return nil
+	}
+
+	return r.txInit(tx)
+}
+
 func (r *Runner) isMigrationApplied(tx dbx.Builder, file string) bool {
 	var exists bool
 
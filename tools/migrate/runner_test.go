@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
@@ -138,6 +139,134 @@ func TestRunnerUpAndDown(t *testing.T) {
 	}
 }
 
+func TestRunnerPending(t *testing.T) {
+	testDB, err := createTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testDB.Close()
+
+	l := MigrationsList{}
+	l.Register(nil, nil, "2_test")
+	l.Register(nil, nil, "1_test")
+	l.Register(nil, nil, "3_test")
+
+	r, err := NewRunner(testDB.DB, l)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.saveAppliedMigration(testDB, "2_test"); err != nil {
+		t.Fatal(err)
+	}
+
+	pending, err := r.Pending()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := strings.Join(pending, ","); v != "1_test,3_test" {
+		t.Fatalf("Expected pending 1_test,3_test, got %s", v)
+	}
+
+	if _, err := r.Up(); err != nil {
+		t.Fatal(err)
+	}
+
+	pending, err = r.Pending()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pending) != 0 {
+		t.Fatalf("Expected no pending migrations, got %v", pending)
+	}
+}
+
+func TestPendingMigrations(t *testing.T) {
+	testDB, err := createTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testDB.Close()
+
+	l := MigrationsList{}
+	l.Register(nil, nil, "2_test")
+	l.Register(nil, nil, "1_test")
+
+	// missing migrations table
+	if _, err := PendingMigrations(context.Background(), testDB, l); err == nil {
+		t.Fatal("Expected error for missing migrations table")
+	}
+
+	var total int
+	testDB.NewQuery("SELECT count(*) FROM sqlite_master WHERE name = {:name}").
+		Bind(dbx.Params{"name": DefaultMigrationsTable}).
+		Row(&total)
+	if total != 0 {
+		t.Fatal("Expected the migrations table to not be created")
+	}
+
+	r, err := NewRunner(testDB.DB, l)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.saveAppliedMigration(testDB, "1_test"); err != nil {
+		t.Fatal(err)
+	}
+
+	pending, err := PendingMigrations(context.Background(), testDB, l)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := strings.Join(pending, ","); v != "2_test" {
+		t.Fatalf("Expected pending 2_test, got %s", v)
+	}
+}
+
+func TestRunnerTxInit(t *testing.T) {
+	testDB, err := createTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testDB.Close()
+
+	callsOrder := []string{}
+
+	l := MigrationsList{}
+	l.Register(func(db dbx.Builder) error {
+		callsOrder = append(callsOrder, "up1")
+		return nil
+	}, func(db dbx.Builder) error {
+		callsOrder = append(callsOrder, "down1")
+		return nil
+	}, "1_test")
+
+	r, err := NewRunner(testDB.DB, l)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.SetTxInit(func(tx *dbx.Tx) error {
+		callsOrder = append(callsOrder, "init")
+		return nil
+	})
+
+	if _, err := r.Up(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.Down(1); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := strings.Join(callsOrder, ","); v != "init,up1,init,down1" {
+		t.Fatalf("Expected calls order init,up1,init,down1, got %s", v)
+	}
+}
+
 func TestHistorySync(t *testing.T) {
 	testDB, err := createTestDB()
 	if err != nil {
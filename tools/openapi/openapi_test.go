@@ -0,0 +1,100 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/openapi"
+)
+
+func TestNewDocument(t *testing.T) {
+	doc := openapi.NewDocument("test", "1.2.3")
+
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"openapi":"3.0.3","info":{"title":"test","version":"1.2.3"},"paths":{},"components":{}}`
+
+	if str := string(raw); str != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, str)
+	}
+}
+
+func TestDocumentAddTag(t *testing.T) {
+	doc := openapi.NewDocument("test", "1.0.0")
+
+	doc.AddTag("a", "a1")
+	doc.AddTag("b", "")
+	doc.AddTag("a", "a2") // duplicated
+
+	if total := len(doc.Tags); total != 2 {
+		t.Fatalf("Expected 2 tags, got %d", total)
+	}
+
+	if doc.Tags[0].Name != "a" || doc.Tags[0].Description != "a1" {
+		t.Fatalf("Expected the first tag to be unchanged, got %v", doc.Tags[0])
+	}
+}
+
+func TestDocumentOperations(t *testing.T) {
+	doc := openapi.NewDocument("test", "1.0.0")
+
+	doc.AddOperation("/a", http.MethodGet, &openapi.Operation{Summary: "get a"})
+	doc.AddOperation("/a", "post", &openapi.Operation{Summary: "post a"})
+	doc.AddOperation("/b", "invalid", &openapi.Operation{Summary: "invalid"})
+
+	scenarios := []struct {
+		path     string
+		method   string
+		expected bool
+	}{
+		{"/a", http.MethodGet, true},
+		{"/a", "get", true},
+		{"/a", http.MethodPost, true},
+		{"/a", http.MethodPatch, false},
+		{"/b", "invalid", false},
+		{"/c", http.MethodGet, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.method+" "+s.path, func(t *testing.T) {
+			result := doc.HasOperation(s.path, s.method)
+			if result != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, result)
+			}
+		})
+	}
+
+	if summary := doc.Paths["/a"].Operation("POST").Summary; summary != "post a" {
+		t.Fatalf("Expected the post operation summary to be %q, got %q", "post a", summary)
+	}
+}
+
+func TestRefs(t *testing.T) {
+	scenarios := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{"schema", openapi.SchemaRef("test"), `{"$ref":"#/components/schemas/test"}`},
+		{"parameter", openapi.ParameterRef("test"), `{"$ref":"#/components/parameters/test"}`},
+		{"response", openapi.ResponseRef("test"), `{"$ref":"#/components/responses/test"}`},
+		{"json content", openapi.JsonContent(openapi.SchemaRef("test")), `{"application/json":{"schema":{"$ref":"#/components/schemas/test"}}}`},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			raw, err := json.Marshal(s.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if str := string(raw); str != s.expected {
+				t.Fatalf("Expected\n%s\ngot\n%s", s.expected, str)
+			}
+		})
+	}
+}
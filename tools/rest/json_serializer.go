@@ -21,12 +21,19 @@ type FieldModifier interface {
 	Modify(value any) (any, error)
 }
 
+// FieldModifierFactory defines a custom request aware field modifier constructor.
+type FieldModifierFactory func(c echo.Context, args ...string) (FieldModifier, error)
+
 // Serializer represents custom REST JSON serializer based on echo.DefaultJSONSerializer,
 // with support for additional generic response data transformation (eg. fields picker).
 type Serializer struct {
 	echo.DefaultJSONSerializer
 
 	FieldsParam string
+
+	// Modifiers is an optional map with custom fields modifiers
+	// in addition to the builtin ones (eg. "excerpt").
+	Modifiers map[string]FieldModifierFactory
 }
 
 // Serialize converts an interface into a json and writes it to the response.
@@ -48,7 +55,13 @@ func (s *Serializer) Serialize(c echo.Context, i any, indent string) error {
 		return s.DefaultJSONSerializer.Serialize(c, i, indent)
 	}
 
-	decoded, err := PickFields(i, rawFields)
+	decoded, err := pickFields(i, rawFields, func(name string, args ...string) (FieldModifier, error) {
+		factory, ok := s.Modifiers[name]
+		if !ok {
+			return nil, nil
+		}
+		return factory(c, args...)
+	})
 	if err != nil {
 		return err
 	}
@@ -71,7 +84,15 @@ func (s *Serializer) Serialize(c echo.Context, i any, indent string) error {
 //	data := map[string]any{"a": 1, "b": 2, "c": map[string]any{"c1": 11, "c2": 22}}
 //	PickFields(data, "a,c.c1") // map[string]any{"a": 1, "c": map[string]any{"c1": 11}}
 func PickFields(data any, rawFields string) (any, error) {
-	parsedFields, err := parseFields(rawFields)
+	return pickFields(data, rawFields, nil)
+}
+
+// customModifierFunc resolves a custom modifier by its name
+// (returns nil modifier if there is no such modifier).
+type customModifierFunc func(name string, args ...string) (FieldModifier, error)
+
+func pickFields(data any, rawFields string, customModifier customModifierFunc) (any, error) {
+	parsedFields, err := parseFields(rawFields, customModifier)
 	if err != nil {
 		return nil, err
 	}
@@ -111,7 +132,7 @@ func PickFields(data any, rawFields string) (any, error) {
 	return decoded, nil
 }
 
-func parseFields(rawFields string) (map[string]FieldModifier, error) {
+func parseFields(rawFields string, customModifier customModifierFunc) (map[string]FieldModifier, error) {
 	t := tokenizer.NewFromString(rawFields)
 
 	fields, err := t.ScanAll()
@@ -125,7 +146,7 @@ func parseFields(rawFields string) (map[string]FieldModifier, error) {
 		parts := strings.SplitN(strings.TrimSpace(f), ":", 2)
 
 		if len(parts) > 1 {
-			m, err := initModifer(parts[1])
+			m, err := initModifer(parts[1], customModifier)
 			if err != nil {
 				return nil, err
 			}
@@ -138,7 +159,7 @@ func parseFields(rawFields string) (map[string]FieldModifier, error) {
 	return result, nil
 }
 
-func initModifer(rawModifier string) (FieldModifier, error) {
+func initModifer(rawModifier string, customModifier customModifierFunc) (FieldModifier, error) {
 	t := tokenizer.NewFromString(rawModifier)
 	t.Separators('(', ')', ',', ' ')
 	t.IgnoreParenthesis(true)
@@ -164,6 +185,16 @@ func initModifer(rawModifier string) (FieldModifier, error) {
 		return m, nil
 	}
 
+	if customModifier != nil {
+		m, err := customModifier(name, args...)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s modifier: %w", name, err)
+		}
+		if m != nil {
+			return m, nil
+		}
+	}
+
 	return nil, fmt.Errorf("missing or invalid modifier %q", name)
 }
 
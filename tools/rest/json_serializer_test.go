@@ -102,6 +102,18 @@ func TestSerialize(t *testing.T) {
 			"custom=a,  c  ,missing", // test individual fields trim
 			`{"a":1,"c":"test"}`,
 		},
+		{
+			"custom modifier",
+			rest.Serializer{Modifiers: map[string]rest.FieldModifierFactory{
+				"upper": func(c echo.Context, args ...string) (rest.FieldModifier, error) {
+					return upperModifier{}, nil
+				},
+			}},
+			200,
+			map[string]any{"a": 1, "b": 2, "c": "test"},
+			"fields=a,c:upper",
+			`{"a":1,"c":"TEST"}`,
+		},
 	}
 
 	for _, s := range scenarios {
@@ -396,3 +408,10 @@ func TestPickFields(t *testing.T) {
 		})
 	}
 }
+
+type upperModifier struct{}
+
+func (upperModifier) Modify(value any) (any, error) {
+	str, _ := value.(string)
+	return strings.ToUpper(str), nil
+}
@@ -0,0 +1,152 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+var _ Scanner = (*ClamAV)(nil)
+
+// DefaultClamAVChunkSize is the default size of a single streamed ClamAV data chunk.
+const DefaultClamAVChunkSize = 64 * 1024
+
+// ClamAV defines a ClamAV daemon (clamd) client that implements
+// the [Scanner] interface using the INSTREAM command.
+type ClamAV struct {
+	// Network is the clamd socket network ("tcp" or "unix").
+	Network string
+
+	// Address is the clamd socket address (eg. "127.0.0.1:3310" or "/var/run/clamav/clamd.ctl").
+	Address string
+
+	// Timeout is the optional max duration of a single scan.
+	Timeout time.Duration
+
+	// ChunkSize is the size of a single streamed data chunk
+	// (if not set, defaults to [DefaultClamAVChunkSize]).
+	//
+	// Note that the total streamed data is limited by the clamd StreamMaxLength option.
+	ChunkSize int
+}
+
+// NewClamAV creates a new ClamAV client from the provided
+// "tcp://host:port" or "unix:///path/to/clamd.sock" address.
+func NewClamAV(address string, timeout time.Duration) (*ClamAV, error) {
+	network, addr, ok := strings.Cut(address, "://")
+	if !ok || addr == "" || (network != "tcp" && network != "unix") {
+		return nil, fmt.Errorf("invalid clamd address %q (expected tcp://host:port or unix:///path)", address)
+	}
+
+	return &ClamAV{
+		Network: network,
+		Address: addr,
+		Timeout: timeout,
+	}, nil
+}
+
+// Scan implements [Scanner.Scan] interface method.
+func (c *ClamAV) Scan(ctx context.Context, r io.Reader) (*Result, error) {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, c.Network, c.Address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return nil, err
+	}
+
+	chunkSize := c.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultClamAVChunkSize
+	}
+
+	buf := make([]byte, 4+chunkSize)
+
+	for {
+		n, readErr := io.ReadFull(r, buf[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buf[:4], uint32(n))
+			if _, err := conn.Write(buf[:4+n]); err != nil {
+				// clamd closes the connection on stream limit exceed
+				// so try to read the reply with the actual error
+				if reply, replyErr := readClamAVReply(conn); replyErr == nil {
+					return parseClamAVReply(reply)
+				}
+				return nil, err
+			}
+		}
+
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+
+		if readErr != nil {
+			return nil, readErr
+		}
+	}
+
+	// zero-length chunk to mark the end of the stream
+	if _, err := conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return nil, err
+	}
+
+	reply, err := readClamAVReply(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseClamAVReply(reply)
+}
+
+func readClamAVReply(conn net.Conn) (string, error) {
+	reply, err := bufio.NewReader(conn).ReadBytes(0)
+	if err != nil && (err != io.EOF || len(reply) == 0) {
+		return "", err
+	}
+
+	return string(bytes.TrimRight(reply, "\x00\n")), nil
+}
+
+// parseClamAVReply parses a single clamd INSTREAM reply, eg.:
+//
+//	stream: OK
+//	stream: Eicar-Signature FOUND
+//	INSTREAM size limit exceeded. ERROR
+func parseClamAVReply(reply string) (*Result, error) {
+	reply = strings.TrimSpace(strings.TrimPrefix(reply, "stream:"))
+
+	switch {
+	case reply == "OK":
+		return &Result{}, nil
+	case strings.HasSuffix(reply, " FOUND"):
+		return &Result{
+			Infected:  true,
+			Signature: strings.TrimSpace(strings.TrimSuffix(reply, " FOUND")),
+		}, nil
+	case strings.HasSuffix(reply, "ERROR"):
+		return nil, errors.New("clamd error: " + strings.TrimSpace(strings.TrimSuffix(reply, "ERROR")))
+	default:
+		return nil, errors.New("unexpected clamd reply: " + reply)
+	}
+}
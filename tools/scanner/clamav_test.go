@@ -0,0 +1,92 @@
+package scanner_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/tests"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/scanner"
+)
+
+func TestNewClamAV(t *testing.T) {
+	scenarios := []struct {
+		address         string
+		expectError     bool
+		expectedNetwork string
+		expectedAddress string
+	}{
+		{"", true, "", ""},
+		{"127.0.0.1:3310", true, "", ""},
+		{"udp://127.0.0.1:3310", true, "", ""},
+		{"tcp://", true, "", ""},
+		{"tcp://127.0.0.1:3310", false, "tcp", "127.0.0.1:3310"},
+		{"unix:///var/run/clamav/clamd.ctl", false, "unix", "/var/run/clamav/clamd.ctl"},
+	}
+
+	for _, s := range scenarios {
+		c, err := scanner.NewClamAV(s.address, time.Second)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.address, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		if c.Network != s.expectedNetwork || c.Address != s.expectedAddress || c.Timeout != time.Second {
+			t.Errorf("[%s] Unexpected client %+v", s.address, c)
+		}
+	}
+}
+
+func TestClamAVScan(t *testing.T) {
+	server, err := tests.NewTestClamd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	c, err := scanner.NewClamAV(server.Address(), 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.ChunkSize = 10 // force multiple chunks
+
+	scenarios := []struct {
+		name              string
+		content           string
+		expectedInfected  bool
+		expectedSignature string
+	}{
+		{"empty", "", false, ""},
+		{"clean", strings.Repeat("test", 100), false, ""},
+		{"infected", "test" + tests.EicarTestSignature + "test", true, "Eicar-Signature"},
+	}
+
+	for _, s := range scenarios {
+		result, err := c.Scan(context.Background(), strings.NewReader(s.content))
+		if err != nil {
+			t.Errorf("[%s] Unexpected error %v", s.name, err)
+			continue
+		}
+
+		if result.Infected != s.expectedInfected || result.Signature != s.expectedSignature {
+			t.Errorf("[%s] Unexpected result %+v", s.name, result)
+		}
+	}
+
+	if total := server.TotalScans(); total != len(scenarios) {
+		t.Fatalf("Expected %d scans, got %d", len(scenarios), total)
+	}
+
+	// unreachable server
+	server.Close()
+	if _, err := c.Scan(context.Background(), strings.NewReader("test")); err == nil {
+		t.Fatal("Expected error for unreachable server")
+	}
+}
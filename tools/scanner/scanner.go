@@ -0,0 +1,26 @@
+// Package scanner defines a generic file scanning interface
+// (eg. antivirus) and its ClamAV daemon implementation.
+package scanner
+
+import (
+	"context"
+	"io"
+)
+
+// Result defines a single file scan result.
+type Result struct {
+	// Infected indicates whether a threat was found.
+	Infected bool `json:"infected"`
+
+	// Signature is the name of the found threat (if any).
+	Signature string `json:"signature,omitempty"`
+}
+
+// Scanner defines a base file scanner interface.
+type Scanner interface {
+	// Scan scans the content of the provided reader.
+	//
+	// An error is returned only if the scan couldn't be completed
+	// (the found threats are reported via the returned result).
+	Scan(ctx context.Context, r io.Reader) (*Result, error)
+}
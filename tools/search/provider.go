@@ -6,7 +6,9 @@ import (
 	"net/url"
 	"strconv"
 
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/tracing"
 	"github.com/pocketbase/dbx"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -182,7 +184,7 @@ func (s *Provider) Parse(urlQuery string) error {
 
 // Exec executes the search provider and fills/scans
 // the provided `items` slice with the found models.
-func (s *Provider) Exec(items any) (*Result, error) {
+func (s *Provider) Exec(items any) (result *Result, err error) {
 	if s.query == nil {
 		return nil, errors.New("query is not set")
 	}
@@ -190,6 +192,17 @@ func (s *Provider) Exec(items any) (*Result, error) {
 	// shallow clone the provider's query
 	modelsQuery := *s.query
 
+	// trace the search as part of the query context trace (if any)
+	ctx, span := tracing.Start(modelsQuery.Context(), "search.Exec")
+	if span.IsRecording() {
+		defer func() {
+			tracing.RecordError(span, err)
+			span.End()
+		}()
+
+		modelsQuery.WithContext(ctx)
+	}
+
 	// build filters
 	for _, f := range s.filter {
 		expr, err := f.BuildExpr(s.fieldResolver)
@@ -235,7 +248,17 @@ func (s *Provider) Exec(items any) (*Result, error) {
 
 	// prepare a count query from the base one
 	countQuery := modelsQuery // shallow clone
-	countExec := func() error {
+	countExec := func() (err error) {
+		if span.IsRecording() {
+			countCtx, countSpan := tracing.Start(ctx, "search.Count")
+			defer func() {
+				tracing.RecordError(countSpan, err)
+				countSpan.End()
+			}()
+
+			countQuery.WithContext(countCtx)
+		}
+
 		queryInfo := countQuery.Info()
 		countCol := s.countCol
 		if len(queryInfo.From) > 0 {
@@ -243,7 +266,7 @@ func (s *Provider) Exec(items any) (*Result, error) {
 		}
 
 		// note: countQuery is shallow cloned and slice/map in-place modifications should be avoided
-		err := countQuery.Distinct(false).
+		err = countQuery.Distinct(false).
 			Select("COUNT(DISTINCT [[" + countCol + "]])").
 			OrderBy( /* reset */ ).
 			Row(&totalCount)
@@ -279,7 +302,13 @@ func (s *Provider) Exec(items any) (*Result, error) {
 		}
 	}
 
-	result := &Result{
+	span.SetAttributes(
+		attribute.Int("search.page", s.page),
+		attribute.Int("search.perPage", s.perPage),
+		attribute.Int("search.totalItems", totalCount),
+	)
+
+	result = &Result{
 		Page:       s.page,
 		PerPage:    s.perPage,
 		TotalItems: totalCount,
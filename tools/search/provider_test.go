@@ -10,7 +10,9 @@ import (
 	"time"
 
 	"github.com/AlperRehaYAZGAN/postgresbase/tools/list"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/tracing"
 	"github.com/pocketbase/dbx"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	_ "modernc.org/sqlite"
 )
 
@@ -427,6 +429,51 @@ func TestProviderExecNonEmptyQuery(t *testing.T) {
 	}
 }
 
+func TestProviderExecTracing(t *testing.T) {
+	testDB, err := createTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testDB.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tracer := tracing.NewTracer("test", exporter, 1)
+	defer tracer.Shutdown(context.Background())
+
+	ctx, root := tracer.Start(context.Background(), "root")
+
+	query := testDB.Select("*").From("test").WithContext(ctx)
+
+	items := []dbx.NullStringMap{}
+
+	if _, err := NewProvider(&testFieldResolver{}).Query(query).Exec(&items); err != nil {
+		t.Fatal(err)
+	}
+
+	root.End()
+
+	if err := tracer.ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := map[string]tracetest.SpanStub{}
+	for _, span := range exporter.GetSpans() {
+		spans[span.Name] = span
+	}
+
+	if len(spans) != 3 {
+		t.Fatalf("Expected 3 spans, got %v", spans)
+	}
+
+	if spans["search.Exec"].Parent.SpanID() != spans["root"].SpanContext.SpanID() {
+		t.Fatalf("Expected search.Exec to be child of the root span")
+	}
+
+	if spans["search.Count"].Parent.SpanID() != spans["search.Exec"].SpanContext.SpanID() {
+		t.Fatalf("Expected search.Count to be child of the search.Exec span")
+	}
+}
+
 func TestProviderParseAndExec(t *testing.T) {
 	testDB, err := createTestDB()
 	if err != nil {
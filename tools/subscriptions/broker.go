@@ -33,6 +33,14 @@ func (b *Broker) Clients() map[string]Client {
 	return copy
 }
 
+// TotalClients returns the total number of registered clients.
+func (b *Broker) TotalClients() int {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+
+	return len(b.clients)
+}
+
 // ClientById finds a registered client by its id.
 //
 // Returns non-nil error when client with clientId is not registered.
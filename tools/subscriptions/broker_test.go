@@ -36,6 +36,21 @@ func TestClients(t *testing.T) {
 	}
 }
 
+func TestTotalClients(t *testing.T) {
+	b := subscriptions.NewBroker()
+
+	if total := b.TotalClients(); total != 0 {
+		t.Fatalf("Expected no clients, got %v", total)
+	}
+
+	b.Register(subscriptions.NewDefaultClient())
+	b.Register(subscriptions.NewDefaultClient())
+
+	if total := b.TotalClients(); total != 2 {
+		t.Fatalf("Expected 2 clients, got %v", total)
+	}
+}
+
 func TestClientById(t *testing.T) {
 	b := subscriptions.NewBroker()
 
@@ -0,0 +1,153 @@
+// Package tracing is a thin helper layer over the OpenTelemetry SDK
+// for creating and exporting (OTLP/HTTP) the app traces.
+//
+// Example:
+//
+//	exporter, _ := tracing.NewOTLPExporter("http://localhost:4318/v1/traces", nil)
+//
+//	tracer := tracing.NewTracer("my-app", exporter, 1)
+//	defer tracer.Shutdown(context.Background())
+//
+//	ctx, span := tracer.Start(context.Background(), "parent")
+//	defer span.End()
+//
+//	// child spans are created with the tracer of the parent context span
+//	// (it is no-op if ctx doesn't have an associated span)
+//	_, child := tracing.Start(ctx, "child")
+//	defer child.End()
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName is the name of the app spans instrumentation scope.
+const InstrumentationName = "github.com/AlperRehaYAZGAN/postgresbase"
+
+// propagator handles the W3C Trace Context ("traceparent") headers.
+var propagator = propagation.TraceContext{}
+
+// Tracer creates the app root spans and exports
+// in batches the ended (and sampled) spans.
+type Tracer struct {
+	provider *sdktrace.TracerProvider
+	tracer   trace.Tracer
+}
+
+// NewTracer creates a new Tracer with a batching span processor for
+// the provided exporter (call Tracer.Shutdown to stop it).
+//
+// sampleRatio (0-1) is the ratio of the sampled new traces.
+// The traces continued from a remote parent (see Extract) respect the
+// sampling decision of the caller.
+func NewTracer(serviceName string, exporter sdktrace.SpanExporter, sampleRatio float64) *Tracer {
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+	)
+
+	return &Tracer{
+		provider: provider,
+		tracer:   provider.Tracer(InstrumentationName),
+	}
+}
+
+// NewOTLPExporter creates a new OTLP/HTTP spans exporter for the
+// provided collector traces url (eg. "http://localhost:4318/v1/traces").
+func NewOTLPExporter(endpoint string, headers map[string]string) (sdktrace.SpanExporter, error) {
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpointURL(endpoint)}
+
+	if len(headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(headers))
+	}
+
+	// note: the exporter doesn't connect until the first export
+	return otlptracehttp.New(context.Background(), opts...)
+}
+
+// Start creates a new span that is a child of the ctx span
+// or of the ctx remote span context (see Extract).
+//
+// If ctx has neither, a new root span (aka. a new trace) is created.
+//
+// The returned context has the new span associated to it.
+func (t *Tracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if t == nil {
+		return ctx, trace.SpanFromContext(context.Background())
+	}
+
+	return t.tracer.Start(ctx, name, opts...)
+}
+
+// ForceFlush exports all ended spans that have not yet been exported.
+func (t *Tracer) ForceFlush(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+
+	return t.provider.ForceFlush(ctx)
+}
+
+// Shutdown flushes the remaining spans and stops the tracer.
+//
+// The spans created after the shutdown are not exported.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+
+	return t.provider.Shutdown(ctx)
+}
+
+// Start creates a new child span of the ctx span with the
+// tracer of the parent span.
+//
+// It is no-op (aka. returns a non-recording span) if
+// ctx doesn't have an associated local span.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	parent := trace.SpanFromContext(ctx)
+	if !parent.IsRecording() {
+		return ctx, parent
+	}
+
+	return parent.TracerProvider().Tracer(InstrumentationName).Start(ctx, name, opts...)
+}
+
+// HasSpan reports whether ctx has an associated (recording) local span.
+func HasSpan(ctx context.Context) bool {
+	return trace.SpanFromContext(ctx).IsRecording()
+}
+
+// RecordError records the provided error as span event
+// and marks the span status as failed.
+//
+// It is no-op if err is nil.
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// Extract returns a copy of ctx with the remote span context
+// from the "traceparent" header (if any and valid).
+func Extract(ctx context.Context, header http.Header) context.Context {
+	return propagator.Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// Inject sets the "traceparent" header of the ctx span (if any).
+func Inject(ctx context.Context, header http.Header) {
+	propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
@@ -0,0 +1,176 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/tracing"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracerSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+
+	tracer := tracing.NewTracer("test", exporter, 1)
+	defer tracer.Shutdown(context.Background())
+
+	// no-op package level start without parent span
+	if _, span := tracing.Start(context.Background(), "noop"); span.IsRecording() {
+		t.Fatal("Expected non-recording span")
+	}
+
+	header := http.Header{}
+	header.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+
+	ctx, root := tracer.Start(tracing.Extract(context.Background(), header), "root", trace.WithSpanKind(trace.SpanKindServer))
+
+	if !tracing.HasSpan(ctx) {
+		t.Fatal("Expected ctx with span")
+	}
+
+	_, child := tracing.Start(ctx, "child")
+	tracing.RecordError(child, errors.New("test error"))
+	tracing.RecordError(child, nil) // should be no-op
+	child.End()
+
+	root.End()
+
+	outgoing := http.Header{}
+	tracing.Inject(ctx, outgoing)
+	expectedTraceparent := "00-0af7651916cd43dd8448eb211c80319c-" + root.SpanContext().SpanID().String() + "-01"
+	if v := outgoing.Get("traceparent"); v != expectedTraceparent {
+		t.Fatalf("Expected injected traceparent %q, got %q", expectedTraceparent, v)
+	}
+
+	if err := tracer.ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 exported spans, got %d", len(spans))
+	}
+
+	childData, rootData := spans[0], spans[1]
+
+	if rootData.Name != "root" || rootData.SpanKind != trace.SpanKindServer {
+		t.Fatalf("Unexpected root span %+v", rootData)
+	}
+
+	if v, _ := rootData.Resource.Set().Value("service.name"); v.AsString() != "test" {
+		t.Fatalf("Expected service.name test, got %q", v.AsString())
+	}
+
+	if rootData.Parent.SpanID().String() != "b7ad6b7169203331" || !rootData.Parent.IsRemote() {
+		t.Fatalf("Expected the remote span to be the root parent, got %v", rootData.Parent.SpanID())
+	}
+
+	if childData.Parent.SpanID() != rootData.SpanContext.SpanID() || childData.SpanContext.TraceID() != rootData.SpanContext.TraceID() {
+		t.Fatalf("Expected child of the root span, got %+v", childData)
+	}
+
+	if childData.Status.Code != codes.Error || childData.Status.Description != "test error" || len(childData.Events) != 1 {
+		t.Fatalf("Expected failed child span, got %+v", childData.Status)
+	}
+}
+
+func TestTracerSampling(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+
+	tracer := tracing.NewTracer("test", exporter, 0)
+	defer tracer.Shutdown(context.Background())
+
+	ctx, root := tracer.Start(context.Background(), "root")
+	_, child := tracing.Start(ctx, "child")
+	child.End()
+	root.End()
+
+	// unsampled remote parent
+	header := http.Header{}
+	header.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00")
+	_, remoteChild := tracer.Start(tracing.Extract(context.Background(), header), "remote")
+	remoteChild.End()
+
+	// sampled remote parent
+	header.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	_, sampledRemoteChild := tracer.Start(tracing.Extract(context.Background(), header), "sampledRemote")
+	sampledRemoteChild.End()
+
+	if err := tracer.ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Name != "sampledRemote" {
+		t.Fatalf("Expected only the sampledRemote span to be exported, got %v", spans.Snapshots())
+	}
+}
+
+func TestNilTracer(t *testing.T) {
+	var tracer *tracing.Tracer
+
+	ctx, span := tracer.Start(context.Background(), "test")
+	if span.IsRecording() || ctx == nil {
+		t.Fatalf("Expected non-recording span and non-nil context")
+	}
+
+	// should be no-op
+	span.End()
+
+	if err := tracer.ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOTLPExporter(t *testing.T) {
+	var mux sync.Mutex
+	requests := []*http.Request{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mux.Lock()
+		requests = append(requests, r)
+		mux.Unlock()
+	}))
+	defer server.Close()
+
+	exporter, err := tracing.NewOTLPExporter(server.URL+"/custom/traces", map[string]string{"x-test": "123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tracer := tracing.NewTracer("test", exporter, 1)
+
+	_, span := tracer.Start(context.Background(), "test")
+	span.End()
+
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	mux.Lock()
+	defer mux.Unlock()
+
+	if len(requests) != 1 {
+		t.Fatalf("Expected 1 export request, got %d", len(requests))
+	}
+
+	r := requests[0]
+
+	if r.Method != http.MethodPost || r.URL.Path != "/custom/traces" {
+		t.Fatalf("Unexpected export request %s %s", r.Method, r.URL.Path)
+	}
+
+	if r.Header.Get("x-test") != "123" || r.Header.Get("Content-Type") != "application/x-protobuf" {
+		t.Fatalf("Unexpected export request headers %v", r.Header)
+	}
+}
@@ -0,0 +1,308 @@
+// Package tus implements a minimal local filesystem store for
+// resumable uploads following the tus protocol (https://tus.io).
+//
+// Note that the pending uploads and their locks are node-local, aka.
+// when running multiple app instances all requests of an upload must be
+// routed to the same instance (eg. with sticky sessions).
+// Storing the pending chunks as S3 multipart parts is not supported.
+package tus
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/security"
+)
+
+// Version is the supported tus protocol version.
+const Version = "1.0.0"
+
+// Extensions is the list of the supported tus protocol extensions.
+const Extensions = "creation,expiration,termination"
+
+const (
+	infoFileName = "info.json"
+	dataFileName = "data"
+)
+
+var idRegex = regexp.MustCompile(`^\w+$`)
+
+var (
+	// ErrNotFound is returned when the upload is missing or has expired.
+	ErrNotFound = errors.New("missing or expired upload")
+
+	// ErrLocked is returned when the upload is already being modified by another request.
+	ErrLocked = errors.New("the upload is locked by another request")
+
+	// ErrOffsetMismatch is returned when the chunk offset doesn't match with the upload offset.
+	ErrOffsetMismatch = errors.New("the chunk offset doesn't match with the upload offset")
+
+	// ErrSizeExceeded is returned when the chunk exceeds the declared upload size.
+	ErrSizeExceeded = errors.New("the chunk exceeds the upload size")
+)
+
+// Info defines a single resumable upload state.
+type Info struct {
+	Id       string            `json:"id"`
+	Size     int64             `json:"size"`
+	Offset   int64             `json:"offset"`
+	Metadata map[string]string `json:"metadata"`
+	Expires  time.Time         `json:"expires"`
+}
+
+// IsComplete checks whether all of the upload chunks were received.
+func (info *Info) IsComplete() bool {
+	return info.Offset >= info.Size
+}
+
+// Store manages the resumable uploads stored in a single local directory
+// (each upload has its own subdirectory with the upload info and data files).
+type Store struct {
+	dir   string
+	mux   sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// NewStore creates a new Store instance for the provided local directory.
+//
+// The directory is created on the first upload.
+func NewStore(dir string) *Store {
+	return &Store{
+		dir:   dir,
+		locks: map[string]*sync.Mutex{},
+	}
+}
+
+// Create registers a new empty upload with the specified size and metadata.
+//
+// The upload and its data are deleted on the next [Store.DeleteExpired]
+// call after the specified expiry duration.
+func (s *Store) Create(size int64, metadata map[string]string, expiry time.Duration) (*Info, error) {
+	if size < 0 {
+		return nil, errors.New("the upload size must be a positive number")
+	}
+
+	info := &Info{
+		Id:       security.RandomString(32),
+		Size:     size,
+		Metadata: metadata,
+		Expires:  time.Now().Add(expiry).UTC(),
+	}
+
+	if err := os.MkdirAll(s.uploadDir(info.Id), os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	if err := s.saveInfo(info); err != nil {
+		return nil, err
+	}
+
+	// create an empty data file
+	f, err := os.Create(filepath.Join(s.uploadDir(info.Id), dataFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	return info, f.Close()
+}
+
+// Get returns the upload info with the specified id.
+//
+// Returns [ErrNotFound] if the upload is missing or has expired.
+func (s *Store) Get(id string) (*Info, error) {
+	if !idRegex.MatchString(id) {
+		return nil, ErrNotFound
+	}
+
+	raw, err := os.ReadFile(filepath.Join(s.uploadDir(id), infoFileName))
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	info := &Info{}
+	if err := json.Unmarshal(raw, info); err != nil {
+		return nil, err
+	}
+
+	if info.Expires.Before(time.Now()) {
+		return nil, ErrNotFound
+	}
+
+	// the data file size is the source of truth for the current offset
+	// since a chunk write could have been interrupted
+	stat, err := os.Stat(s.DataPath(id))
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	info.Offset = stat.Size()
+
+	return info, nil
+}
+
+// DataPath returns the local path of the upload data file.
+func (s *Store) DataPath(id string) string {
+	return filepath.Join(s.uploadDir(id), dataFileName)
+}
+
+// Lock acquires the upload modification lock.
+//
+// Returns [ErrLocked] if the upload is already locked.
+// Call the returned function to release the lock.
+func (s *Store) Lock(id string) (func(), error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	lock, ok := s.locks[id]
+	if !ok {
+		lock = &sync.Mutex{}
+		s.locks[id] = lock
+	}
+
+	if !lock.TryLock() {
+		return nil, ErrLocked
+	}
+
+	return func() {
+		s.mux.Lock()
+		defer s.mux.Unlock()
+
+		lock.Unlock()
+		delete(s.locks, id)
+	}, nil
+}
+
+// WriteChunk appends the content of r to the upload data
+// starting from the provided offset and returns the updated upload info.
+//
+// The upload should be locked with [Store.Lock] before writing to it.
+//
+// Note that when the reader fails in the middle of the chunk
+// (eg. due to network error), the already read part is preserved
+// so that the client could resume from the new offset.
+func (s *Store) WriteChunk(id string, offset int64, r io.Reader) (*Info, error) {
+	info, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if offset != info.Offset {
+		return nil, ErrOffsetMismatch
+	}
+
+	f, err := os.OpenFile(s.DataPath(id), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	remaining := info.Size - info.Offset
+
+	n, copyErr := io.Copy(f, io.LimitReader(r, remaining))
+	info.Offset += n
+
+	if copyErr != nil {
+		return info, copyErr
+	}
+
+	// check for extra data
+	if n == remaining {
+		if extra, _ := r.Read(make([]byte, 1)); extra > 0 {
+			// revert the chunk
+			if err := f.Truncate(offset); err != nil {
+				return nil, err
+			}
+			info.Offset = offset
+
+			return info, ErrSizeExceeded
+		}
+	}
+
+	return info, nil
+}
+
+// Delete deletes the upload with the specified id and its data.
+func (s *Store) Delete(id string) error {
+	if !idRegex.MatchString(id) {
+		return ErrNotFound
+	}
+
+	return os.RemoveAll(s.uploadDir(id))
+}
+
+// DeleteExpired deletes all expired uploads.
+func (s *Store) DeleteExpired() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil // nothing to delete
+		}
+		return err
+	}
+
+	var errs []error
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		if _, err := s.Get(entry.Name()); errors.Is(err, ErrNotFound) {
+			if err := os.RemoveAll(s.uploadDir(entry.Name())); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *Store) uploadDir(id string) string {
+	return filepath.Join(s.dir, id)
+}
+
+func (s *Store) saveInfo(info *Info) error {
+	raw, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(s.uploadDir(info.Id), infoFileName), raw, 0644)
+}
+
+// ParseMetadata parses the tus Upload-Metadata header value
+// (comma separated "key base64(value)" pairs).
+func ParseMetadata(header string) (map[string]string, error) {
+	result := map[string]string{}
+
+	for _, pair := range strings.Split(header, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, " ", 2)
+
+		key := parts[0]
+
+		var value string
+		if len(parts) == 2 {
+			decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(parts[1]))
+			if err != nil {
+				return nil, errors.New("invalid metadata value for key " + key)
+			}
+			value = string(decoded)
+		}
+
+		result[key] = value
+	}
+
+	return result, nil
+}
@@ -0,0 +1,223 @@
+package tus_test
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/tus"
+)
+
+func TestStoreCreateAndGet(t *testing.T) {
+	dir := t.TempDir()
+
+	s := tus.NewStore(dir)
+
+	if _, err := s.Create(-1, nil, time.Minute); err == nil {
+		t.Fatal("Expected error for negative upload size")
+	}
+
+	info, err := s.Create(10, map[string]string{"filename": "test.txt"}, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Id == "" {
+		t.Fatal("Expected non-empty upload id")
+	}
+
+	loaded, err := s.Get(info.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded.Size != 10 || loaded.Offset != 0 || loaded.Metadata["filename"] != "test.txt" {
+		t.Fatalf("Unexpected upload info %v", loaded)
+	}
+
+	if _, err := s.Get("missing"); !errors.Is(err, tus.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got %v", err)
+	}
+
+	if _, err := s.Get("../" + info.Id); !errors.Is(err, tus.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound for invalid id, got %v", err)
+	}
+
+	// expired
+	expired, err := s.Create(10, nil, -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get(expired.Id); !errors.Is(err, tus.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound for expired upload, got %v", err)
+	}
+}
+
+func TestStoreWriteChunk(t *testing.T) {
+	dir := t.TempDir()
+
+	s := tus.NewStore(dir)
+
+	info, err := s.Create(10, nil, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// offset mismatch
+	if _, err := s.WriteChunk(info.Id, 1, strings.NewReader("abc")); !errors.Is(err, tus.ErrOffsetMismatch) {
+		t.Fatalf("Expected ErrOffsetMismatch, got %v", err)
+	}
+
+	// first chunk
+	info, err = s.WriteChunk(info.Id, 0, strings.NewReader("abcd"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Offset != 4 || info.IsComplete() {
+		t.Fatalf("Expected offset 4 and incomplete upload, got %v", info)
+	}
+
+	// chunk exceeding the upload size
+	info, err = s.WriteChunk(info.Id, 4, strings.NewReader("efghijklmn"))
+	if !errors.Is(err, tus.ErrSizeExceeded) {
+		t.Fatalf("Expected ErrSizeExceeded, got %v", err)
+	}
+	if info.Offset != 4 {
+		t.Fatalf("Expected the exceeding chunk to be reverted, got offset %d", info.Offset)
+	}
+
+	// last chunk
+	info, err = s.WriteChunk(info.Id, 4, strings.NewReader("efghij"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Offset != 10 || !info.IsComplete() {
+		t.Fatalf("Expected offset 10 and complete upload, got %v", info)
+	}
+
+	content, err := os.ReadFile(s.DataPath(info.Id))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if str := string(content); str != "abcdefghij" {
+		t.Fatalf("Expected data %q, got %q", "abcdefghij", str)
+	}
+}
+
+func TestStoreLock(t *testing.T) {
+	s := tus.NewStore(t.TempDir())
+
+	unlock, err := s.Lock("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Lock("test"); !errors.Is(err, tus.ErrLocked) {
+		t.Fatalf("Expected ErrLocked, got %v", err)
+	}
+
+	// different upload
+	unlock2, err := s.Lock("test2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlock2()
+
+	unlock()
+
+	unlock3, err := s.Lock("test")
+	if err != nil {
+		t.Fatalf("Expected to acquire the released lock, got %v", err)
+	}
+	unlock3()
+}
+
+func TestStoreDelete(t *testing.T) {
+	s := tus.NewStore(t.TempDir())
+
+	info, err := s.Create(10, nil, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Delete(info.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Get(info.Id); !errors.Is(err, tus.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestStoreDeleteExpired(t *testing.T) {
+	dir := t.TempDir()
+
+	s := tus.NewStore(dir)
+
+	// missing store dir
+	if err := tus.NewStore(dir + "/missing").DeleteExpired(); err != nil {
+		t.Fatalf("Expected nil error for missing dir, got %v", err)
+	}
+
+	active, err := s.Create(10, nil, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expired, err := s.Create(10, nil, -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.DeleteExpired(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Get(active.Id); err != nil {
+		t.Fatalf("Expected the active upload to remain, got %v", err)
+	}
+
+	if _, err := os.Stat(s.DataPath(expired.Id)); !os.IsNotExist(err) {
+		t.Fatalf("Expected the expired upload to be deleted, got %v", err)
+	}
+}
+
+func TestParseMetadata(t *testing.T) {
+	scenarios := []struct {
+		header      string
+		expectError bool
+		expected    map[string]string
+	}{
+		{"", false, map[string]string{}},
+		{"filename dGVzdC50eHQ=", false, map[string]string{"filename": "test.txt"}},
+		{"filename dGVzdC50eHQ=, filetype dGV4dC9wbGFpbg==,is_confidential", false, map[string]string{
+			"filename":        "test.txt",
+			"filetype":        "text/plain",
+			"is_confidential": "",
+		}},
+		{"filename !invalid", true, nil},
+	}
+
+	for i, s := range scenarios {
+		result, err := tus.ParseMetadata(s.header)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if len(result) != len(s.expected) {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expected, result)
+			continue
+		}
+
+		for k, v := range s.expected {
+			if result[k] != v {
+				t.Errorf("(%d) Expected %q value %q, got %q", i, k, v, result[k])
+			}
+		}
+	}
+}
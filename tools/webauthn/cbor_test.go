@@ -0,0 +1,53 @@
+package webauthn
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeCBOR(t *testing.T) {
+	scenarios := []struct {
+		name         string
+		data         []byte
+		expected     any
+		expectedRest []byte
+		expectError  bool
+	}{
+		{"empty", nil, nil, nil, true},
+		{"small uint", []byte{0x0a}, int64(10), []byte{}, false},
+		{"uint16", []byte{0x19, 0x01, 0x00}, int64(256), []byte{}, false},
+		{"negative int", []byte{0x26}, int64(-7), []byte{}, false},
+		{"negative int16", []byte{0x39, 0x01, 0x00}, int64(-257), []byte{}, false},
+		{"byte string", []byte{0x42, 0x01, 0x02, 0xff}, []byte{0x01, 0x02}, []byte{0xff}, false},
+		{"truncated byte string", []byte{0x45, 0x01}, nil, nil, true},
+		{"text string", []byte{0x63, 'a', 'b', 'c'}, "abc", []byte{}, false},
+		{"array", []byte{0x82, 0x01, 0xf5}, []any{int64(1), true}, []byte{}, false},
+		{"map", []byte{0xa2, 0x01, 0x02, 0x61, 'a', 0xf6}, map[any]any{int64(1): int64(2), "a": nil}, []byte{}, false},
+		{"map with unsupported key", []byte{0xa1, 0x41, 0x01, 0x02}, nil, nil, true},
+		{"indefinite length", []byte{0x5f, 0x41, 0x01, 0xff}, nil, nil, true},
+		{"float", []byte{0xf9, 0x3c, 0x00}, nil, nil, true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result, rest, err := decodeCBOR(s.data)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			if !reflect.DeepEqual(result, s.expected) {
+				t.Fatalf("Expected %#v, got %#v", s.expected, result)
+			}
+
+			if !reflect.DeepEqual(rest, s.expectedRest) {
+				t.Fatalf("Expected rest %v, got %v", s.expectedRest, rest)
+			}
+		})
+	}
+}
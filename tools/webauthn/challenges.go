@@ -0,0 +1,83 @@
+package webauthn
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// DefaultChallengeDuration is the default ceremony challenge validity duration.
+const DefaultChallengeDuration = 5 * time.Minute
+
+const (
+	signedChallengeNonceLength   = 16
+	signedChallengeExpiresLength = 8
+	signedChallengeLength        = signedChallengeNonceLength + signedChallengeExpiresLength + sha256.Size
+)
+
+// NewSignedChallenge generates a new base64url encoded ceremony challenge
+// that is bound to the specified owner (eg. an auth record or collection
+// identifier) and valid for the specified duration.
+//
+// The challenge is composed of a random nonce, its expiration time and
+// their HMAC-SHA256 signature, aka. it doesn't need to be stored
+// server-side while the ceremony is pending.
+//
+// Note that the signed challenges are not single-use on their own and
+// the caller is responsible for tracking the already used ones
+// until their expiration.
+func NewSignedChallenge(secret string, owner string, duration time.Duration) string {
+	b := make([]byte, signedChallengeNonceLength+signedChallengeExpiresLength, signedChallengeLength)
+
+	if _, err := rand.Read(b[:signedChallengeNonceLength]); err != nil {
+		panic(err)
+	}
+
+	expires := time.Now().Add(duration).Unix()
+	binary.BigEndian.PutUint64(b[signedChallengeNonceLength:], uint64(expires))
+
+	b = append(b, signChallenge(secret, owner, b)...)
+
+	return EncodeBase64(b)
+}
+
+// ParseSignedChallenge verifies the signature of the provided
+// challenge for the specified owner and returns its expiration time.
+//
+// Returns an error if the challenge is malformed, was signed
+// with a different secret or owner, or has already expired.
+func ParseSignedChallenge(challenge string, secret string, owner string) (time.Time, error) {
+	b, err := DecodeBase64(challenge)
+	if err != nil || len(b) != signedChallengeLength {
+		return time.Time{}, errors.New("malformed challenge")
+	}
+
+	payload := b[:signedChallengeNonceLength+signedChallengeExpiresLength]
+
+	if !hmac.Equal(b[len(payload):], signChallenge(secret, owner, payload)) {
+		return time.Time{}, errors.New("invalid challenge signature")
+	}
+
+	expires := time.Unix(int64(binary.BigEndian.Uint64(payload[signedChallengeNonceLength:])), 0)
+	if !expires.After(time.Now()) {
+		return time.Time{}, errors.New("the challenge has expired")
+	}
+
+	return expires, nil
+}
+
+func signChallenge(secret string, owner string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+
+	// the owner length prefix prevents ambiguous owner-payload boundaries
+	ownerLength := make([]byte, 8)
+	binary.BigEndian.PutUint64(ownerLength, uint64(len(owner)))
+	mac.Write(ownerLength)
+	mac.Write([]byte(owner))
+	mac.Write(payload)
+
+	return mac.Sum(nil)
+}
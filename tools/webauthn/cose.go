@@ -0,0 +1,136 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+// Supported COSE algorithm identifiers.
+const (
+	AlgES256 int64 = -7
+	AlgEdDSA int64 = -8
+	AlgRS256 int64 = -257
+)
+
+// SupportedAlgorithms lists the supported COSE algorithms
+// in the order of preference.
+var SupportedAlgorithms = []int64{AlgES256, AlgEdDSA, AlgRS256}
+
+// COSE key parameters and values.
+const (
+	coseKeyKty int64 = 1
+	coseKeyAlg int64 = 3
+	coseKeyCrv int64 = -1 // or the RSA modulus "n"
+	coseKeyX   int64 = -2 // or the RSA exponent "e"
+	coseKeyY   int64 = -3
+
+	coseKtyOKP int64 = 1
+	coseKtyEC2 int64 = 2
+	coseKtyRSA int64 = 3
+
+	coseCrvP256    int64 = 1
+	coseCrvEd25519 int64 = 6
+)
+
+// PublicKey defines a parsed COSE credential public key.
+type PublicKey struct {
+	Algorithm int64
+	key       crypto.PublicKey
+}
+
+// ParsePublicKey parses the provided COSE_Key encoded credential public key.
+func ParsePublicKey(coseKey []byte) (*PublicKey, error) {
+	raw, rest, err := decodeCBOR(coseKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("unexpected trailing public key data")
+	}
+
+	params, ok := raw.(map[any]any)
+	if !ok {
+		return nil, errors.New("invalid COSE key")
+	}
+
+	kty, _ := params[coseKeyKty].(int64)
+	alg, _ := params[coseKeyAlg].(int64)
+
+	switch {
+	case kty == coseKtyEC2 && alg == AlgES256:
+		crv, _ := params[coseKeyCrv].(int64)
+		x, _ := params[coseKeyX].([]byte)
+		y, _ := params[coseKeyY].([]byte)
+		if crv != coseCrvP256 || len(x) != 32 || len(y) != 32 {
+			return nil, errors.New("invalid EC2 COSE key")
+		}
+
+		// validate that the point is on the curve
+		point := append(append([]byte{0x04}, x...), y...)
+		if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+			return nil, err
+		}
+
+		return &PublicKey{
+			Algorithm: alg,
+			key: &ecdsa.PublicKey{
+				Curve: elliptic.P256(),
+				X:     new(big.Int).SetBytes(x),
+				Y:     new(big.Int).SetBytes(y),
+			},
+		}, nil
+	case kty == coseKtyOKP && alg == AlgEdDSA:
+		crv, _ := params[coseKeyCrv].(int64)
+		x, _ := params[coseKeyX].([]byte)
+		if crv != coseCrvEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid OKP COSE key")
+		}
+
+		return &PublicKey{Algorithm: alg, key: ed25519.PublicKey(x)}, nil
+	case kty == coseKtyRSA && alg == AlgRS256:
+		n, _ := params[coseKeyCrv].([]byte)
+		e, _ := params[coseKeyX].([]byte)
+		if len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return nil, errors.New("invalid RSA COSE key")
+		}
+
+		return &PublicKey{
+			Algorithm: alg,
+			key: &rsa.PublicKey{
+				N: new(big.Int).SetBytes(n),
+				E: int(new(big.Int).SetBytes(e).Int64()),
+			},
+		}, nil
+	}
+
+	return nil, errors.New("unsupported COSE key type or algorithm")
+}
+
+// Verify checks whether sig is a valid signature of the provided data.
+func (k *PublicKey) Verify(data []byte, sig []byte) error {
+	switch key := k.key.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(data)
+		if ecdsa.VerifyASN1(key, digest[:], sig) {
+			return nil
+		}
+	case ed25519.PublicKey:
+		if ed25519.Verify(key, data, sig) {
+			return nil
+		}
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(data)
+		if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig) == nil {
+			return nil
+		}
+	}
+
+	return errors.New("invalid signature")
+}
@@ -0,0 +1,126 @@
+package webauthn
+
+import "time"
+
+// Common public key credential option values.
+const (
+	CredentialTypePublicKey = "public-key"
+
+	UserVerificationRequired  = "required"
+	UserVerificationPreferred = "preferred"
+)
+
+// RelyingParty defines the relying party ceremony options entity.
+type RelyingParty struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// User defines the user account ceremony options entity.
+type User struct {
+	// Id is the base64url encoded user handle.
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+// CredentialParameter defines a single supported credential type and algorithm.
+type CredentialParameter struct {
+	Type string `json:"type"`
+	Alg  int64  `json:"alg"`
+}
+
+// CredentialDescriptor identifies a single public key credential.
+type CredentialDescriptor struct {
+	Type string `json:"type"`
+
+	// Id is the base64url encoded credential id.
+	Id string `json:"id"`
+}
+
+// AuthenticatorSelection defines the authenticator requirements.
+type AuthenticatorSelection struct {
+	ResidentKey        string `json:"residentKey"`
+	RequireResidentKey bool   `json:"requireResidentKey"`
+	UserVerification   string `json:"userVerification"`
+}
+
+// CreationOptions defines the client PublicKeyCredentialCreationOptions
+// (with base64url encoded binary fields) of a registration ceremony.
+type CreationOptions struct {
+	Challenge              string                 `json:"challenge"`
+	RP                     RelyingParty           `json:"rp"`
+	User                   User                   `json:"user"`
+	PubKeyCredParams       []CredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                  `json:"timeout"`
+	ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection AuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                 `json:"attestation"`
+}
+
+// RequestOptions defines the client PublicKeyCredentialRequestOptions
+// (with base64url encoded binary fields) of an authentication ceremony.
+type RequestOptions struct {
+	Challenge        string                 `json:"challenge"`
+	RPId             string                 `json:"rpId"`
+	Timeout          int64                  `json:"timeout"`
+	AllowCredentials []CredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                 `json:"userVerification"`
+}
+
+// NewCreationOptions creates new resident key (aka. passkey)
+// registration ceremony options for the specified user.
+//
+// excludeCredentialIds is a list with the base64url encoded ids
+// of the already registered user credentials.
+func (c Config) NewCreationOptions(
+	challenge string,
+	timeout time.Duration,
+	user User,
+	excludeCredentialIds []string,
+) *CreationOptions {
+	params := make([]CredentialParameter, 0, len(SupportedAlgorithms))
+	for _, alg := range SupportedAlgorithms {
+		params = append(params, CredentialParameter{Type: CredentialTypePublicKey, Alg: alg})
+	}
+
+	exclude := make([]CredentialDescriptor, 0, len(excludeCredentialIds))
+	for _, id := range excludeCredentialIds {
+		exclude = append(exclude, CredentialDescriptor{Type: CredentialTypePublicKey, Id: id})
+	}
+
+	return &CreationOptions{
+		Challenge:          challenge,
+		RP:                 RelyingParty{Id: c.RPId, Name: c.RPName},
+		User:               user,
+		PubKeyCredParams:   params,
+		Timeout:            timeout.Milliseconds(),
+		ExcludeCredentials: exclude,
+		AuthenticatorSelection: AuthenticatorSelection{
+			ResidentKey:        "required",
+			RequireResidentKey: true,
+			UserVerification:   c.userVerification(),
+		},
+		Attestation: "none",
+	}
+}
+
+// NewRequestOptions creates new resident key (aka. passkey)
+// authentication ceremony options.
+func (c Config) NewRequestOptions(challenge string, timeout time.Duration) *RequestOptions {
+	return &RequestOptions{
+		Challenge:        challenge,
+		RPId:             c.RPId,
+		Timeout:          timeout.Milliseconds(),
+		AllowCredentials: []CredentialDescriptor{}, // discoverable credentials
+		UserVerification: c.userVerification(),
+	}
+}
+
+func (c Config) userVerification() string {
+	if c.RequireUserVerification {
+		return UserVerificationRequired
+	}
+
+	return UserVerificationPreferred
+}
@@ -0,0 +1,288 @@
+// Package webauthn implements a minimal WebAuthn relying party
+// for verifying passkey registration and assertion ceremonies.
+//
+// Only the ES256, EdDSA and RS256 credential algorithms are supported.
+// Attestation statements are not verified (aka. the relying party
+// always requests "none" attestation conveyance).
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// Ceremony client data types.
+const (
+	ClientDataTypeCreate = "webauthn.create"
+	ClientDataTypeGet    = "webauthn.get"
+)
+
+// Authenticator data flags.
+const (
+	FlagUserPresent            byte = 0x01
+	FlagUserVerified           byte = 0x04
+	FlagBackupEligible         byte = 0x08
+	FlagBackupState            byte = 0x10
+	FlagAttestedCredentialData byte = 0x40
+	FlagExtensionData          byte = 0x80
+)
+
+// Config defines the relying party settings.
+type Config struct {
+	// RPId is the relying party id (usually the app domain, eg. "example.com").
+	RPId string
+
+	// RPName is the human-palatable relying party name.
+	RPName string
+
+	// Origins is a list with the allowed ceremony origins (eg. "https://example.com").
+	Origins []string
+
+	// RequireUserVerification requires the authenticator to verify
+	// the user (eg. with biometrics or PIN) and not only its presence.
+	RequireUserVerification bool
+}
+
+// ClientData defines the collected client data of a WebAuthn ceremony.
+type ClientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+// ParseClientData parses the raw clientDataJSON of a WebAuthn ceremony.
+func ParseClientData(clientDataJSON []byte) (*ClientData, error) {
+	result := &ClientData{}
+
+	if err := json.Unmarshal(clientDataJSON, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// AuthenticatorData defines the parsed authenticator data of a WebAuthn ceremony.
+type AuthenticatorData struct {
+	RPIdHash  []byte
+	Flags     byte
+	SignCount uint32
+
+	// attested credential data (available only on registration)
+	AAGUID       []byte
+	CredentialId []byte
+	PublicKey    []byte // COSE_Key encoded
+}
+
+// HasFlag checks whether the provided authenticator flag is set.
+func (d *AuthenticatorData) HasFlag(flag byte) bool {
+	return d.Flags&flag == flag
+}
+
+// ParseAuthenticatorData parses the provided raw authenticator data.
+func ParseAuthenticatorData(raw []byte) (*AuthenticatorData, error) {
+	if len(raw) < 37 {
+		return nil, errors.New("authenticator data is too short")
+	}
+
+	result := &AuthenticatorData{
+		RPIdHash:  raw[:32],
+		Flags:     raw[32],
+		SignCount: binary.BigEndian.Uint32(raw[33:37]),
+	}
+
+	rest := raw[37:]
+
+	if result.HasFlag(FlagAttestedCredentialData) {
+		if len(rest) < 18 {
+			return nil, errors.New("invalid attested credential data")
+		}
+
+		result.AAGUID = rest[:16]
+
+		idLength := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if idLength == 0 || idLength > 1023 || len(rest) < idLength {
+			return nil, errors.New("invalid credential id")
+		}
+		result.CredentialId = rest[:idLength]
+		rest = rest[idLength:]
+
+		// the COSE key is followed by the optional extensions
+		// so we decode it to find its length
+		_, afterKey, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, errors.New("invalid credential public key")
+		}
+		result.PublicKey = rest[:len(rest)-len(afterKey)]
+		rest = afterKey
+	}
+
+	if result.HasFlag(FlagExtensionData) {
+		_, afterExtensions, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, errors.New("invalid extensions data")
+		}
+		rest = afterExtensions
+	}
+
+	if len(rest) > 0 {
+		return nil, errors.New("unexpected trailing authenticator data")
+	}
+
+	return result, nil
+}
+
+// NewChallenge generates a new random base64url encoded ceremony challenge.
+func NewChallenge() string {
+	b := make([]byte, 32)
+
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeBase64 decodes the provided base64url (with or without padding) string.
+func DecodeBase64(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+}
+
+// EncodeBase64 encodes the provided bytes as unpadded base64url string.
+func EncodeBase64(value []byte) string {
+	return base64.RawURLEncoding.EncodeToString(value)
+}
+
+// VerifyRegistration verifies a registration (aka. attestation) ceremony
+// response and returns the authenticator data with the new credential.
+//
+// The challenge is the expected base64url encoded ceremony challenge.
+func (c Config) VerifyRegistration(challenge string, clientDataJSON []byte, attestationObject []byte) (*AuthenticatorData, error) {
+	if err := c.verifyClientData(clientDataJSON, ClientDataTypeCreate, challenge); err != nil {
+		return nil, err
+	}
+
+	raw, rest, err := decodeCBOR(attestationObject)
+	if err != nil || len(rest) > 0 {
+		return nil, errors.New("invalid attestation object")
+	}
+
+	attestation, _ := raw.(map[any]any)
+	rawAuthData, _ := attestation["authData"].([]byte)
+	if rawAuthData == nil {
+		return nil, errors.New("missing attestation authenticator data")
+	}
+
+	authData, err := ParseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.verifyAuthenticatorData(authData); err != nil {
+		return nil, err
+	}
+
+	if !authData.HasFlag(FlagAttestedCredentialData) {
+		return nil, errors.New("missing attested credential data")
+	}
+
+	// ensure that the credential public key is supported
+	if _, err := ParsePublicKey(authData.PublicKey); err != nil {
+		return nil, err
+	}
+
+	return authData, nil
+}
+
+// VerifyAssertion verifies an authentication (aka. assertion) ceremony
+// response against the stored COSE credential public key and returns
+// the parsed authenticator data.
+//
+// The challenge is the expected base64url encoded ceremony challenge.
+//
+// Note that the caller is responsible for checking the returned
+// authenticator data SignCount against the stored one.
+func (c Config) VerifyAssertion(
+	challenge string,
+	publicKey []byte,
+	clientDataJSON []byte,
+	authenticatorData []byte,
+	signature []byte,
+) (*AuthenticatorData, error) {
+	if err := c.verifyClientData(clientDataJSON, ClientDataTypeGet, challenge); err != nil {
+		return nil, err
+	}
+
+	authData, err := ParseAuthenticatorData(authenticatorData)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.verifyAuthenticatorData(authData); err != nil {
+		return nil, err
+	}
+
+	key, err := ParsePublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+
+	signed := make([]byte, 0, len(authenticatorData)+len(clientDataHash))
+	signed = append(signed, authenticatorData...)
+	signed = append(signed, clientDataHash[:]...)
+
+	if err := key.Verify(signed, signature); err != nil {
+		return nil, err
+	}
+
+	return authData, nil
+}
+
+func (c Config) verifyClientData(clientDataJSON []byte, expectedType string, expectedChallenge string) error {
+	clientData, err := ParseClientData(clientDataJSON)
+	if err != nil {
+		return errors.New("invalid client data")
+	}
+
+	if clientData.Type != expectedType {
+		return errors.New("invalid client data type")
+	}
+
+	if expectedChallenge == "" || clientData.Challenge != expectedChallenge {
+		return errors.New("invalid client data challenge")
+	}
+
+	for _, origin := range c.Origins {
+		if clientData.Origin == origin {
+			return nil
+		}
+	}
+
+	return errors.New("invalid client data origin")
+}
+
+func (c Config) verifyAuthenticatorData(authData *AuthenticatorData) error {
+	rpIdHash := sha256.Sum256([]byte(c.RPId))
+	if !bytes.Equal(authData.RPIdHash, rpIdHash[:]) {
+		return errors.New("invalid relying party id hash")
+	}
+
+	if !authData.HasFlag(FlagUserPresent) {
+		return errors.New("the user is not present")
+	}
+
+	if c.RequireUserVerification && !authData.HasFlag(FlagUserVerified) {
+		return errors.New("the user is not verified")
+	}
+
+	return nil
+}
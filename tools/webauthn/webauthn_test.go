@@ -0,0 +1,288 @@
+package webauthn_test
+
+import (
+	"crypto/sha256"
+	"testing"
+	"time"
+
+	"github.com/AlperRehaYAZGAN/postgresbase/tests"
+	"github.com/AlperRehaYAZGAN/postgresbase/tools/webauthn"
+)
+
+func decodeCredentialResponse(t *testing.T, credential map[string]any, field string) []byte {
+	response, _ := credential["response"].(map[string]any)
+	value, _ := response[field].(string)
+
+	raw, err := webauthn.DecodeBase64(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return raw
+}
+
+func TestNewChallenge(t *testing.T) {
+	c1 := webauthn.NewChallenge()
+	c2 := webauthn.NewChallenge()
+
+	if c1 == c2 {
+		t.Fatalf("Expected different challenges, got %q", c1)
+	}
+
+	raw, err := webauthn.DecodeBase64(c1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(raw) != 32 {
+		t.Fatalf("Expected 32 bytes challenge, got %d", len(raw))
+	}
+}
+
+func TestParseAuthenticatorData(t *testing.T) {
+	rpIdHash := sha256.Sum256([]byte("example.com"))
+
+	scenarios := []struct {
+		name          string
+		raw           []byte
+		expectError   bool
+		expectedCount uint32
+	}{
+		{"empty", nil, true, 0},
+		{"too short", rpIdHash[:], true, 0},
+		{
+			"without attested credential data",
+			append(append([]byte{}, rpIdHash[:]...), webauthn.FlagUserPresent, 0, 0, 0, 5),
+			false,
+			5,
+		},
+		{
+			"with trailing data",
+			append(append([]byte{}, rpIdHash[:]...), webauthn.FlagUserPresent, 0, 0, 0, 5, 1),
+			true,
+			0,
+		},
+		{
+			"with invalid attested credential data",
+			append(append([]byte{}, rpIdHash[:]...), webauthn.FlagAttestedCredentialData, 0, 0, 0, 5, 1, 2, 3),
+			true,
+			0,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result, err := webauthn.ParseAuthenticatorData(s.raw)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			if result.SignCount != s.expectedCount {
+				t.Fatalf("Expected sign count %d, got %d", s.expectedCount, result.SignCount)
+			}
+		})
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	authenticator, err := tests.NewTestAuthenticator("example.com", "https://example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := webauthn.ParsePublicKey(nil); err == nil {
+		t.Fatal("Expected error for empty key")
+	}
+
+	if _, err := webauthn.ParsePublicKey([]byte{0xa0}); err == nil {
+		t.Fatal("Expected error for empty COSE map")
+	}
+
+	key, err := webauthn.ParsePublicKey(authenticator.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key.Algorithm != webauthn.AlgES256 {
+		t.Fatalf("Expected ES256 key, got %d", key.Algorithm)
+	}
+}
+
+func TestVerifyRegistrationAndAssertion(t *testing.T) {
+	config := webauthn.Config{
+		RPId:    "example.com",
+		RPName:  "Example",
+		Origins: []string{"https://example.com"},
+	}
+
+	authenticator, err := tests.NewTestAuthenticator("example.com", "https://example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// registration
+	// ---
+	challenge := webauthn.NewChallenge()
+
+	credential := authenticator.Register(challenge)
+	clientData := decodeCredentialResponse(t, credential, "clientDataJSON")
+	attestationObject := decodeCredentialResponse(t, credential, "attestationObject")
+
+	if _, err := config.VerifyRegistration(webauthn.NewChallenge(), clientData, attestationObject); err == nil {
+		t.Fatal("Expected registration error for different challenge")
+	}
+
+	otherConfig := config
+	otherConfig.Origins = []string{"https://other.example.com"}
+	if _, err := otherConfig.VerifyRegistration(challenge, clientData, attestationObject); err == nil {
+		t.Fatal("Expected registration error for different origin")
+	}
+
+	otherConfig = config
+	otherConfig.RPId = "other.example.com"
+	otherConfig.Origins = []string{"https://example.com"}
+	if _, err := otherConfig.VerifyRegistration(challenge, clientData, attestationObject); err == nil {
+		t.Fatal("Expected registration error for different rp id")
+	}
+
+	authData, err := config.VerifyRegistration(challenge, clientData, attestationObject)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(authData.CredentialId) != string(authenticator.CredentialId) {
+		t.Fatalf("Expected credential id %x, got %x", authenticator.CredentialId, authData.CredentialId)
+	}
+
+	if string(authData.PublicKey) != string(authenticator.PublicKey()) {
+		t.Fatalf("Expected public key %x, got %x", authenticator.PublicKey(), authData.PublicKey)
+	}
+
+	// assertion
+	// ---
+	challenge = webauthn.NewChallenge()
+
+	credential, err = authenticator.Login(challenge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientData = decodeCredentialResponse(t, credential, "clientDataJSON")
+	rawAuthData := decodeCredentialResponse(t, credential, "authenticatorData")
+	signature := decodeCredentialResponse(t, credential, "signature")
+
+	// registration response client data
+	if _, err := config.VerifyAssertion(challenge, authData.PublicKey, decodeCredentialResponse(t, authenticator.Register(challenge), "clientDataJSON"), rawAuthData, signature); err == nil {
+		t.Fatal("Expected assertion error for invalid client data type")
+	}
+
+	invalidSignature := append([]byte{}, signature...)
+	invalidSignature[len(invalidSignature)-1]++
+	if _, err := config.VerifyAssertion(challenge, authData.PublicKey, clientData, rawAuthData, invalidSignature); err == nil {
+		t.Fatal("Expected assertion error for invalid signature")
+	}
+
+	otherAuthenticator, _ := tests.NewTestAuthenticator("example.com", "https://example.com")
+	if _, err := config.VerifyAssertion(challenge, otherAuthenticator.PublicKey(), clientData, rawAuthData, signature); err == nil {
+		t.Fatal("Expected assertion error for different public key")
+	}
+
+	assertionData, err := config.VerifyAssertion(challenge, authData.PublicKey, clientData, rawAuthData, signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assertionData.SignCount != 1 {
+		t.Fatalf("Expected sign count 1, got %d", assertionData.SignCount)
+	}
+}
+
+func TestSignedChallenge(t *testing.T) {
+	challenge := webauthn.NewSignedChallenge("secret", "a", time.Minute)
+
+	if raw, err := webauthn.DecodeBase64(challenge); err != nil || len(raw) < 16 {
+		t.Fatalf("Expected at least 16 bytes base64url challenge, got %q (%v)", challenge, err)
+	}
+
+	if webauthn.NewSignedChallenge("secret", "a", time.Minute) == challenge {
+		t.Fatal("Expected the challenges to be unique")
+	}
+
+	expires, err := webauthn.ParseSignedChallenge(challenge, "secret", "a")
+	if err != nil {
+		t.Fatalf("Expected the challenge to be valid, got %v", err)
+	}
+	if d := time.Until(expires); d <= 0 || d > time.Minute {
+		t.Fatalf("Expected the challenge to expire within 1 minute, got %v", d)
+	}
+
+	scenarios := []struct {
+		name      string
+		challenge string
+		secret    string
+		owner     string
+	}{
+		{"different owner", challenge, "secret", "b"},
+		{"different secret", challenge, "secret2", "a"},
+		{"malformed", webauthn.NewChallenge(), "secret", "a"},
+		{"invalid base64", "!@#", "secret", "a"},
+		{"expired", webauthn.NewSignedChallenge("secret", "a", -time.Second), "secret", "a"},
+	}
+
+	for _, s := range scenarios {
+		if _, err := webauthn.ParseSignedChallenge(s.challenge, s.secret, s.owner); err == nil {
+			t.Errorf("[%s] Expected the challenge to be invalid", s.name)
+		}
+	}
+}
+
+func TestConfigOptions(t *testing.T) {
+	config := webauthn.Config{
+		RPId:    "example.com",
+		RPName:  "Example",
+		Origins: []string{"https://example.com"},
+	}
+
+	creation := config.NewCreationOptions("test_challenge", 2*time.Minute, webauthn.User{Id: "test_user"}, []string{"a", "b"})
+
+	if creation.Challenge != "test_challenge" || creation.RP.Id != "example.com" || creation.RP.Name != "Example" {
+		t.Fatalf("Unexpected creation options %v", creation)
+	}
+
+	if creation.Timeout != 120000 {
+		t.Fatalf("Expected timeout 120000, got %d", creation.Timeout)
+	}
+
+	if len(creation.PubKeyCredParams) != len(webauthn.SupportedAlgorithms) {
+		t.Fatalf("Expected %d credential params, got %v", len(webauthn.SupportedAlgorithms), creation.PubKeyCredParams)
+	}
+
+	if len(creation.ExcludeCredentials) != 2 {
+		t.Fatalf("Expected 2 excluded credentials, got %v", creation.ExcludeCredentials)
+	}
+
+	if !creation.AuthenticatorSelection.RequireResidentKey || creation.AuthenticatorSelection.UserVerification != webauthn.UserVerificationPreferred {
+		t.Fatalf("Unexpected authenticator selection %v", creation.AuthenticatorSelection)
+	}
+
+	config.RequireUserVerification = true
+
+	request := config.NewRequestOptions("test_challenge", time.Minute)
+
+	if request.Challenge != "test_challenge" || request.RPId != "example.com" || request.Timeout != 60000 {
+		t.Fatalf("Unexpected request options %v", request)
+	}
+
+	if request.AllowCredentials == nil || len(request.AllowCredentials) != 0 {
+		t.Fatalf("Expected empty non-nil allowed credentials, got %v", request.AllowCredentials)
+	}
+
+	if request.UserVerification != webauthn.UserVerificationRequired {
+		t.Fatalf("Expected required user verification, got %q", request.UserVerification)
+	}
+}